@@ -1,6 +1,6 @@
 // @title          Edutalks API
 // @version        1.0
-// @description    Документация API Edutalks (регистрация, логин, токены, статьи, логи и т.д.).
+// @description    Документация API Edutalks (регистрация, логин, токены, статьи, логи и т.д.). Сообщения ошибок (error.message) — на языке из Accept-Language (ru, en), для авторизованных — на языке профиля (locale); error.code от языка не зависит.
 // @contact.name   EduTalks Support
 // @contact.url    https://edutalks.ru
 // @contact.email  support@edutalks.ru
@@ -19,6 +19,8 @@ import (
 	"edutalks/internal/app"
 	"edutalks/internal/config"
 	"edutalks/internal/logger"
+	"edutalks/internal/metrics"
+	"edutalks/internal/middleware"
 
 	"context"
 	"net/http"
@@ -26,6 +28,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/getsentry/sentry-go"
 	"github.com/go-chi/cors"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"go.uber.org/zap"
@@ -49,21 +52,48 @@ func main() {
 	}
 	defer func() { _ = logger.Log.Sync() }()
 
+	// Sentry (SENTRY_DSN): паники и ответы 5xx; при выходе досылаем накопленные события
+	sentryEnv := cfg.SentryEnvironment
+	if sentryEnv == "" {
+		sentryEnv = cfg.Env
+	}
+	host, _ := os.Hostname()
+	if err := sentry.Init(sentry.ClientOptions{
+		Dsn:         cfg.SentryDSN,
+		Environment: sentryEnv,
+		Release:     cfg.SentryRelease,
+		ServerName:  host,
+		BeforeSend:  middleware.SentryBeforeSend,
+	}); err != nil {
+		logger.Log.Warn("Sentry выключен", zap.Error(err))
+	}
+	defer sentry.Flush(5 * time.Second)
+
+	// Подкоманда миграций: main migrate up|down|status|version
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := app.RunMigrate(cfg, os.Args[2:], os.Stdout); err != nil {
+			logger.Log.Fatal("Ошибка миграций", zap.Error(err))
+		}
+		return
+	}
+
 	// 3) Инициализируем приложение (роутер, зависимости) и получаем cleanup
-	router, cleanup, err := app.InitApp(cfg)
+	live := config.NewHolder(cfg)
+	router, cleanup, err := app.InitApp(live)
 	if err != nil {
 		logger.Log.Fatal("Ошибка инициализации приложения", zap.Error(err))
 	}
 
-	// 4) Swagger
+	// 4) Swagger и метрики Prometheus
 	router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
+	router.Handle("/metrics", metrics.Handler(cfg.MetricsToken)).Methods(http.MethodGet)
 
 	// 5) CORS. Для AllowCredentials=true нельзя звездочку в AllowedOrigins.
 	corsMiddleware := cors.Handler(cors.Options{
 		AllowOriginFunc:  func(r *http.Request, origin string) bool { return true }, // вернёт конкретный Origin
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Authorization", "Content-Type", "Accept", "X-Requested-With"},
-		ExposedHeaders:   []string{"Authorization", "Content-Length", "Content-Type"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type", "Accept", "X-Requested-With", "X-Request-ID"},
+		ExposedHeaders:   []string{"Authorization", "Content-Length", "Content-Type", "X-Request-ID", "Link"},
 		AllowCredentials: true,
 		MaxAge:           86400,
 	})
@@ -86,6 +116,23 @@ func main() {
 		}
 	}()
 
+	// SIGHUP — перечитать конфигурацию (окружение и .env) без рестарта
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	go func() {
+		for range hupCh {
+			warnings, err := live.Reload()
+			if err != nil {
+				logger.Log.Error("Конфигурация не перечитана, остаётся прежняя", zap.Error(err))
+				continue
+			}
+			for _, w := range warnings {
+				logger.Log.Warn("Конфигурация: " + w)
+			}
+			logger.Log.Info("Конфигурация перечитана по SIGHUP")
+		}
+	}()
+
 	// ловим SIGINT/SIGTERM
 	stopCh := make(chan os.Signal, 1)
 	signal.Notify(stopCh, syscall.SIGINT, syscall.SIGTERM)
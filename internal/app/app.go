@@ -4,11 +4,19 @@ import (
 	"context"
 	"edutalks/internal/config"
 	"edutalks/internal/db"
+	"edutalks/internal/events"
 	"edutalks/internal/handlers"
 	"edutalks/internal/logger"
+	"edutalks/internal/metrics"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
 	"edutalks/internal/repository"
 	"edutalks/internal/routes"
 	"edutalks/internal/services"
+	"edutalks/internal/utils/helpers"
+	"edutalks/migrations"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -16,7 +24,16 @@ import (
 )
 
 // InitApp возвращает router, cleanup-функцию и ошибку.
-func InitApp(cfg *config.Config) (*mux.Router, func(), error) {
+func InitApp(live *config.Holder) (*mux.Router, func(), error) {
+	cfg := live.Get()
+
+	// Оповещения об ошибках в логах — до создания сервисов, чтобы их логгеры шли через hook
+	alertSvc := services.NewAlertService(cfg)
+	if alertSvc.Enabled() {
+		logger.AddHook(alertSvc.MinLevel(), alertSvc.Hook)
+	}
+	stopAlerts := alertSvc.Start()
+
 	// DB
 	conn, err := db.NewPostgresConnection(cfg)
 	if err != nil {
@@ -25,102 +42,470 @@ func InitApp(cfg *config.Config) (*mux.Router, func(), error) {
 	}
 	logger.Log.Info("Подключение к Postgres успешно")
 
+	// Миграции схемы (встроены в бинарник)
+	migrator, err := db.NewMigrator(conn, migrations.FS)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if cfg.MigrateOnStart == "true" {
+		if err := migrateOnStart(context.Background(), migrator); err != nil {
+			logger.Log.Error("Не удалось применить миграции", zap.Error(err))
+			_ = migrator.Close()
+			conn.Close()
+			return nil, nil, err
+		}
+	}
+
 	// Репозитории
+	outboxRepo := repository.NewOutboxRepository(conn) // побочные эффекты пишутся в транзакциях других репозиториев
 	userRepo := repository.NewUserRepository(conn)
 	docRepo := repository.NewDocumentRepository(conn)
-	newsRepo := repository.NewNewsRepository(conn)
+	newsRepo := repository.NewNewsRepository(conn, outboxRepo)
 	emailTokenRepo := repository.NewEmailTokenRepository(conn)
-	articleRepo := repository.NewArticleRepo(conn)
+	articleRepo := repository.NewArticleRepo(conn, outboxRepo)
+	articleRevisionRepo := repository.NewArticleRevisionRepository(conn)
 	taxonomyRepo := repository.NewTaxonomyRepo(conn)
 	subsRepo := repository.NewSubscriptionRepository(conn)
 	pwdResetRepo := repository.NewPasswordResetRepository(conn)
+	emailOutboxRepo := repository.NewEmailOutboxRepository(conn)
+	paymentWebhookRepo := repository.NewPaymentWebhookRepository(conn)
+	paymentRepo := repository.NewPaymentRepository(conn)
+	subReminderRepo := repository.NewSubscriptionReminderRepository(conn)
+	digestRepo := repository.NewAdminDigestRepository(conn)
+	userDigestRepo := repository.NewUserDigestRepository(conn)
+	domainEventRepo := repository.NewDomainEventRepository(conn)
+	downloadRepo := repository.NewDownloadRepository(conn)
+	downloadQuotaRepo := repository.NewDownloadQuotaRepository(conn)
+	changelogRepo := repository.NewChangelogRepository(conn)
+	docRelationRepo := repository.NewDocumentRelationRepository(conn)
+	invoiceRepo := repository.NewInvoiceRepository(conn)
+	userExportRepo := repository.NewUserExportRepository(conn)
+	maintenanceRepo := repository.NewMaintenanceRepository(conn)
+	emailChangeRepo := repository.NewEmailChangeRepository(conn)
+	phoneCodeRepo := repository.NewPhoneCodeRepository(conn)
+	sessionRepo := repository.NewSessionRepository(conn)
+	oauthRepo := repository.NewOAuthRepository(conn)
+	jobLocks := repository.NewJobLockRepository(conn) // фоновые задачи — на одном инстансе
+	tokenStore, closeRedis := buildTokenStore(cfg, userRepo)
 
 	// Сервисы
-	emailService := services.NewEmailService(cfg) // <-- единственный экземпляр
-	authService := services.NewAuthService(userRepo)
-	docService := services.NewDocumentService(docRepo)
+	emailService := services.NewEmailService(live) // <-- единственный экземпляр
+	authService := services.NewAuthService(userRepo, outboxRepo, tokenStore, sessionRepo, cfg)
+	docService := services.NewDocumentService(docRepo, docRelationRepo, repository.NewDocumentGrantRepository(conn))
 	newsService := services.NewNewsService(newsRepo, userRepo, emailService, cfg)
-	emailTokenService := services.NewEmailTokenService(emailTokenRepo, userRepo)
-	articleSvc := services.NewArticleService(articleRepo)
+	emailTokenService := services.NewEmailTokenService(emailTokenRepo, userRepo, cfg)
+	htmlSanitizer := services.NewHTMLSanitizer(cfg)
+	articleSvc := services.NewArticleService(articleRepo, articleRevisionRepo, htmlSanitizer)
 	taxonomySvc := services.NewTaxonomyService(taxonomyRepo)
-	notifier := services.NewNotifier(subsRepo, taxonomyRepo, cfg.SiteURLNews, "Edutalks")
+	sectionFollowRepo := repository.NewSectionFollowRepository(conn)
+	notifier := services.NewNotifier(subsRepo, taxonomyRepo, sectionFollowRepo, cfg.SiteURLNews, "Edutalks", cfg.NotifyBatchInterval)
 	passwordSvc := services.NewPasswordService(pwdResetRepo, emailService, cfg.FrontendURL)
+	emailOutboxSvc := services.NewEmailOutboxService(emailOutboxRepo)
+	downloadStatsSvc := services.NewDownloadStatsService(downloadRepo)
+	downloadQuotaSvc := services.NewDownloadQuotaService(downloadQuotaRepo, cfg.DownloadQuotas)
+	subScheduler := services.NewSubscriptionScheduler(userRepo, subReminderRepo, jobLocks, cfg)
 	yookassaService := services.NewYooKassaService(
 		cfg.YooKassaShopID,
 		cfg.YooKassaSecret,
 		cfg.YooKassaReturnURL,
+		cfg.PaymentSandboxEnabled(),
+		paymentRepo,
 	)
+	invoiceSvc := services.NewInvoiceService(invoiceRepo, paymentRepo, userRepo, emailService, cfg)
+	userExportSvc := services.NewUserExportService(userExportRepo, userRepo, paymentRepo, invoiceRepo, downloadRepo, live)
+	deletionSvc := services.NewAccountDeletionService(userRepo, outboxRepo, tokenStore, authService, jobLocks, live)
+	emailChangeSvc := services.NewEmailChangeService(emailChangeRepo, userRepo, authService, cfg)
+	phoneSvc := services.NewPhoneVerificationService(phoneCodeRepo, userRepo, authService, services.NewSMSSender(cfg), cfg)
+	oauthSvc := services.NewOAuthService(oauthRepo, userRepo, live)
+	selfCheckSvc := services.NewSelfCheckService(maintenanceRepo, migrator, cfg)
+	fileScanSvc := services.NewFileScanService(docRepo, cfg)
+	notificationRepo := repository.NewNotificationRepository(conn)
+	notificationHub := services.NewNotificationHub(notificationRepo)
+	notificationSvc := services.NewNotificationService(notificationRepo, sectionFollowRepo, taxonomyRepo, notificationHub, cfg)
+
+	// Самопроверка окружения — до запуска фоновых задач; в strict-режиме с ошибками не стартуем
+	if cfg.SelfCheckMode != "off" {
+		report := selfCheckSvc.Run(context.Background())
+		if report.Status == models.SelfCheckFail && cfg.SelfCheckMode == "strict" {
+			closeRedis()
+			_ = migrator.Close()
+			conn.Close()
+			return nil, nil, services.ErrSelfCheckFailed
+		}
+	}
+
+	// Шина доменных событий: публикуют сервисы/хендлеры, побочные эффекты — в подписчиках
+	bus := events.NewBus(1000, buildEventSinks(cfg, domainEventRepo)...)
+	// правки опубликованного — в блок «Обновлено» групповой рассылки
+	bus.Subscribe(events.ArticleUpdated, func(ctx context.Context, ev models.DomainEvent) {
+		id, _ := ev.Payload["article_id"].(int64)
+		title, _ := ev.Payload["title"].(string)
+		notifier.AddArticleUpdateForBatch(ctx, int(id), title)
+	})
+	bus.Subscribe(events.DocumentUpdated, func(ctx context.Context, ev models.DomainEvent) {
+		id, _ := ev.Payload["document_id"].(int)
+		title, _ := ev.Payload["title"].(string)
+		sectionID, _ := ev.Payload["section_id"].(*int)
+		notifier.AddDocumentUpdateForBatch(ctx, id, title, sectionID)
+	})
+	// уведомления в личном кабинете: новые документы — тем, кто отслеживает раздел
+	bus.Subscribe(events.DocumentsAdded, func(ctx context.Context, ev models.DomainEvent) {
+		sectionID, _ := ev.Payload["section_id"].(*int)
+		if sectionID == nil {
+			return
+		}
+		ids, _ := ev.Payload["document_ids"].([]int)
+		titles, _ := ev.Payload["titles"].([]string)
+		if err := notificationSvc.DocumentsAdded(ctx, *sectionID, ids, titles); err != nil {
+			logger.WithCtx(ctx).Error("Не удалось создать уведомления о новых документах", zap.Error(err))
+		}
+	})
+	bus.Subscribe(events.SubscriptionExpiring, func(ctx context.Context, ev models.DomainEvent) {
+		expiresAt, _ := ev.Payload["expires_at"].(time.Time)
+		if ev.UserID == nil {
+			return
+		}
+		if err := notificationSvc.SubscriptionExpiring(ctx, *ev.UserID, expiresAt); err != nil {
+			logger.WithCtx(ctx).Error("Не удалось создать уведомление об окончании подписки", zap.Error(err))
+		}
+	})
+	bus.Start(2)
+	events.SetDefault(bus)
+
+	router := mux.NewRouter()
 
 	// Хендлеры
-	authHandler := handlers.NewAuthHandler(authService, emailService, emailTokenService)
-	docHandler := handlers.NewDocumentHandler(docService, authService, notifier, taxonomyRepo)
-	newsHandler := handlers.NewNewsHandler(newsService, notifier)
-	emailHandler := handlers.NewEmailHandler(emailTokenService)
+	authHandler := handlers.NewAuthHandler(authService, emailService, emailTokenService, live)
+	docDedupSvc := services.NewDocumentDedupService(docRepo, jobLocks)
+	docHandler := handlers.NewDocumentHandler(docService, authService, notifier, downloadStatsSvc, fileScanSvc, services.NewUploadPolicy(cfg.UploadDocumentTypes), downloadQuotaSvc, docDedupSvc, live)
+	contentViewSvc := services.NewContentViewService(repository.NewContentViewRepository(conn), cfg)
+	newsHandler := handlers.NewNewsHandler(newsService, fileScanSvc, contentViewSvc, live)
+	emailHandler := handlers.NewEmailHandler(emailTokenService, live)
 	searchHandler := handlers.NewSearchHandler(newsService, docService)
-	articleH := handlers.NewArticleHandler(articleSvc, notifier)
+	articleH := handlers.NewArticleHandler(articleSvc, contentViewSvc, live)
 	taxonomyH := handlers.NewTaxonomyHandler(taxonomySvc)
-	paymentHandler := handlers.NewPaymentHandler(yookassaService)
-	webhookHandler := handlers.NewWebhookHandler(authService)
+	paymentHandler := handlers.NewPaymentHandler(yookassaService, invoiceSvc)
+	webhookHandler := handlers.NewWebhookHandler(authService, services.NewYooKassaWebhookGuard(cfg), paymentWebhookRepo, paymentRepo, invoiceSvc, cfg.PaymentSandboxEnabled())
 	passwordHandler := handlers.NewPasswordHandler(passwordSvc, userRepo)
-	logsAdminH := handlers.NewAdminLogsHandler()
+	logRetentionSvc := services.NewLogRetentionService("logs", cfg)
+	logsAdminH := handlers.NewAdminLogsHandler(logRetentionSvc)
+	emailOutboxH := handlers.NewEmailOutboxHandler(emailOutboxSvc)
+	digestSvc := services.NewAdminDigestService(digestRepo, downloadStatsSvc, logsAdminH, jobLocks, cfg)
+	digestH := handlers.NewAdminDigestHandler(digestSvc)
+	alertsH := handlers.NewAdminAlertsHandler(alertSvc)
+	trashSvc := services.NewTrashService(repository.NewTrashRepository(conn), jobLocks, cfg)
+	trashH := handlers.NewTrashHandler(trashSvc)
+	reviewH := handlers.NewContentReviewHandler(services.NewContentReviewService(repository.NewContentReviewRepository(conn), articleSvc, docService))
+	userDigestSvc := services.NewUserDigestService(userDigestRepo, jobLocks, cfg)
+	downloadStatsH := handlers.NewDownloadStatsHandler(downloadStatsSvc)
+	downloadQuotaH := handlers.NewDownloadQuotaHandler(downloadQuotaSvc, authService)
+	contentViewH := handlers.NewContentViewHandler(contentViewSvc)
+	dashboardStatsH := handlers.NewDashboardStatsHandler(services.NewDashboardStatsService(repository.NewDashboardStatsRepository(conn)))
+	userActivityH := handlers.NewUserActivityHandler(services.NewUserActivityService(repository.NewUserActivityRepository(conn)), authService)
+	htmlProfileH := handlers.NewHTMLProfileHandler(htmlSanitizer)
+	notificationH := handlers.NewNotificationHandler(notificationSvc)
+	articleMediaH := handlers.NewArticleMediaHandler(services.NewArticleMediaService(repository.NewArticleMediaRepository(conn)), fileScanSvc)
+	featureFlagH := handlers.NewFeatureFlagHandler(services.NewFeatureFlagService(repository.NewFeatureFlagRepository(conn)))
+	announcementH := handlers.NewAnnouncementHandler(services.NewAnnouncementService(repository.NewAnnouncementRepository(conn)))
+	impersonationH := handlers.NewImpersonationHandler(services.NewImpersonationService(repository.NewImpersonationRepository(conn), userRepo, tokenStore, live), live)
+	changelogH := handlers.NewChangelogHandler(services.NewChangelogService(changelogRepo))
+	emailTemplatesH := handlers.NewEmailTemplateHandler()
+	campaignSvc := services.NewNotifyCampaignService(repository.NewNotifyCampaignRepository(conn), authService, jobLocks)
+	notifyH := handlers.NewNotifyHandler(notifier, campaignSvc)
+	exportH := handlers.NewUserExportHandler(userExportSvc)
+	deletionH := handlers.NewAccountDeletionHandler(deletionSvc, live)
+	emailChangeH := handlers.NewEmailChangeHandler(emailChangeSvc, live)
+	oauthH := handlers.NewOAuthHandler(oauthSvc, authService, live)
+	avatarH := handlers.NewAvatarHandler(authService)
+	phoneH := handlers.NewPhoneVerificationHandler(phoneSvc)
+	systemH := handlers.NewSystemHandler(func() *models.AuthzReport { return routes.BuildAuthzReport(router) }, services.NewReindexService(maintenanceRepo), selfCheckSvc)
+
+	// Gauge-и /metrics: считаются запросом к БД при каждом опросе
+	metrics.NewGaugeFunc("email_outbox_pending", "Писем в очереди на отправку", func(ctx context.Context) (float64, error) {
+		n, err := emailOutboxRepo.CountPending(ctx)
+		return float64(n), err
+	})
+	metrics.NewGaugeFunc("subscriptions_active", "Пользователей с действующей подпиской", func(ctx context.Context) (float64, error) {
+		n, err := userRepo.CountActiveSubscriptions(ctx)
+		return float64(n), err
+	})
+	db.RegisterPoolMetrics(conn)
 
 	// Применяем параметры воркера из .env (интервалы, ретраи, размер батча)
 	services.ConfigureEmailWorkerFromEnv(cfg)
+	services.ConfigureShutdown(cfg)
+	services.ConfigureFreshness(cfg)
+	helpers.ConfigureEmailTemplates(cfg.EmailTemplatesDir)
 
-	// Запуск почтовых воркеров — начни с одного (дозированная отправка)
-	services.StartEmailWorker(1, emailService)
+	// Запуск почтовых воркеров — начни с одного (дозированная отправка из email_outbox)
+	services.StartEmailWorker(1, emailService, emailOutboxRepo)
+	services.ReplayEmailSpool() // письма, отложенные на диск при прошлой остановке
 
-	// Чистка подписок при старте
-	if err := userRepo.ExpireSubscriptions(context.Background()); err != nil {
-		logger.Log.Warn("Не удалось выполнить ExpireSubscriptions при старте", zap.Error(err))
-	} else {
-		logger.Log.Info("ExpireSubscriptions при старте выполнен")
-	}
-	stopCleaner := startSubscriptionCleaner(userRepo)
+	// Истечение подписок и напоминания: сразу при старте и далее по расписанию
+	stopSubScheduler := subScheduler.Start()
+	stopDigest := digestSvc.Start()
+	stopUserDigest := userDigestSvc.Start()
+	stopCampaigns := campaignSvc.Start()
+	stopOutboxRelay := services.NewOutboxRelay(outboxRepo, emailOutboxRepo, notifier).Start()
+	stopTokenCleaner := startEmailTokenCleaner(emailTokenService, jobLocks, cfg.EmailTokenCleanupInterval)
+	stopArticlePublisher := startArticlePublisher(articleSvc, jobLocks, cfg.ArticlePublishInterval)
+	stopNewsPublisher := startNewsPublisher(newsService, jobLocks, cfg.NewsPublishInterval)
+	stopExportCleanup := userExportSvc.Start()
+	stopAccountDeletion := deletionSvc.Start()
+	stopTrashPurge := trashSvc.Start()
+	stopDocumentHashes := docDedupSvc.Start()
+	stopNotificationHub := notificationHub.Start()
+	poolStatsInterval, _ := time.ParseDuration(cfg.DbPoolStatsInterval) // некорректное — не пишем
+	stopPoolStats := db.StartPoolStats(conn, poolStatsInterval)
+	logCleanupInterval, _ := time.ParseDuration(cfg.LogCleanupInterval) // "0" или некорректное — только вручную
+	stopLogRetention := logRetentionSvc.Start(logCleanupInterval)
 
 	// Маршруты
-	router := mux.NewRouter()
 	routes.InitRoutes(
-		router, userRepo,
+		router, tokenStore, live,
+		func(ctx context.Context, userID int) string {
+			if u, err := authService.GetUserByID(ctx, userID); err == nil {
+				return u.Locale
+			}
+			return ""
+		},
 		authHandler, docHandler, newsHandler, emailHandler,
 		searchHandler, paymentHandler, webhookHandler,
 		articleH, taxonomyH,
 		passwordHandler,
 		logsAdminH,
+		emailOutboxH,
+		digestH,
+		alertsH,
+		trashH,
+		reviewH,
+		downloadStatsH,
+		downloadQuotaH,
+		contentViewH,
+		dashboardStatsH,
+		userActivityH,
+		impersonationH,
+		announcementH,
+		featureFlagH,
+		htmlProfileH,
+		articleMediaH,
+		notificationH,
+		changelogH,
+		systemH,
+		emailTemplatesH,
+		notifyH,
+		exportH,
+		deletionH,
+		emailChangeH,
+		oauthH,
+		avatarH,
+		phoneH,
+		cfg.TrustedProxyNets(),
+		buildRateLimits(cfg),
+		buildCompression(cfg),
 	)
 
 	logger.Log.Info("Приложение инициализировано")
 
-	// cleanup: закрываем email-очередь и останавливаем планировщик
+	// cleanup: останавливаем всё, что ставит письма в очередь (каждая задача доделывает текущий
+	// запуск), затем закрываем email-очередь: она сохраняет задания в outbox и дожидается отправки
 	cleanup := func() {
-		services.StopEmailWorkers() // закрывает канал и завершает горутины-воркеры
-		stopCleaner()
+		logger.Log.Info("Остановка фоновых задач", zap.Duration("timeout_each", services.ShutdownTimeout()))
+		stopOutboxRelay()
+		stopArticlePublisher() // публикует события в шину — до её закрытия
+		stopNewsPublisher()
+		bus.Close()
+		notifier.Stop() // батч-рассылка (в неё пишут подписчики шины) — до закрытия email-очереди
+		stopSubScheduler()
+		stopDigest()
+		stopUserDigest()
+		stopCampaigns()
+		stopTokenCleaner()
+		stopExportCleanup()
+		stopAccountDeletion()
+		stopTrashPurge()
+		stopDocumentHashes()
+		stopNotificationHub()
+		stopPoolStats()
+		stopLogRetention()
+		authService.StopLoginAlerts() // письма о входе собираются в фоне — до закрытия email-очереди
+		services.StopEmailWorkers()   // закрывает канал и завершает горутины-воркеры
+		closeRedis()
+		_ = migrator.Close()
+		stopAlerts() // последним: досылает ошибки, случившиеся при остановке
+	}
+
+	// Сверка middleware маршрутов с security в Swagger
+	if err := routes.CheckAuthz(router, cfg.AuthzCheck); err != nil {
+		cleanup()
+		return nil, nil, err
 	}
 
 	return router, cleanup, nil
 }
 
-func startSubscriptionCleaner(repo *repository.UserRepository) func() {
-	ticker := time.NewTicker(1 * time.Hour)
+func startEmailTokenCleaner(svc *services.EmailTokenService, locks services.JobLocker, intervalStr string) func() {
+	interval, err := time.ParseDuration(intervalStr)
+	if err != nil || interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		logger.Log.Info("EmailTokenCleaner запущен", zap.Duration("interval", interval))
+		for {
+			select {
+			case <-ticker.C:
+				if err := services.RunExclusive(context.Background(), locks, services.JobEmailTokenCleanup, svc.Cleanup); err != nil {
+					logger.Log.Error("Ошибка очистки токенов подтверждения email", zap.Error(err))
+				}
+			case <-done:
+				ticker.Stop()
+				logger.Log.Info("EmailTokenCleaner остановлен")
+				return
+			}
+		}
+	}()
+
+	return services.StopAndWait(services.JobEmailTokenCleanup, done, stopped)
+}
+
+// startArticlePublisher — публикация статей с наступившим publish_at (рассылка — через article.published).
+func startArticlePublisher(svc services.ArticleService, locks services.JobLocker, intervalStr string) func() {
+	interval, err := time.ParseDuration(intervalStr)
+	if err != nil || interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		logger.Log.Info("ArticlePublisher запущен", zap.Duration("interval", interval))
+		for {
+			select {
+			case <-ticker.C:
+				err := services.RunExclusive(context.Background(), locks, services.JobArticlePublisher, func(ctx context.Context) error {
+					_, err := svc.PublishScheduled(ctx)
+					return err
+				})
+				if err != nil {
+					logger.Log.Error("Ошибка отложенной публикации статей", zap.Error(err))
+				}
+			case <-done:
+				ticker.Stop()
+				logger.Log.Info("ArticlePublisher остановлен")
+				return
+			}
+		}
+	}()
+
+	return services.StopAndWait(services.JobArticlePublisher, done, stopped)
+}
+
+// startNewsPublisher — публикация новостей с наступившим publish_at (рассылка — через outbox).
+func startNewsPublisher(svc *services.NewsService, locks services.JobLocker, intervalStr string) func() {
+	interval, err := time.ParseDuration(intervalStr)
+	if err != nil || interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
 	done := make(chan struct{})
+	stopped := make(chan struct{})
 
 	go func() {
-		logger.Log.Info("SubscriptionCleaner запущен")
+		defer close(stopped)
+		logger.Log.Info("NewsPublisher запущен", zap.Duration("interval", interval))
 		for {
 			select {
 			case <-ticker.C:
-				if err := repo.ExpireSubscriptions(context.Background()); err != nil {
-					logger.Log.Error("Ошибка в ExpireSubscriptions", zap.Error(err))
-				} else {
-					logger.Log.Debug("ExpireSubscriptions выполнен по расписанию")
+				err := services.RunExclusive(context.Background(), locks, services.JobNewsPublisher, func(ctx context.Context) error {
+					_, err := svc.PublishScheduled(ctx)
+					return err
+				})
+				if err != nil {
+					logger.Log.Error("Ошибка отложенной публикации новостей", zap.Error(err))
 				}
 			case <-done:
 				ticker.Stop()
-				logger.Log.Info("SubscriptionCleaner остановлен")
+				logger.Log.Info("NewsPublisher остановлен")
 				return
 			}
 		}
 	}()
 
-	return func() { close(done) }
+	return services.StopAndWait(services.JobNewsPublisher, done, stopped)
+}
+
+// buildTokenStore — хранилище токенов: Redis, если задан REDIS_URL и он отвечает, иначе Postgres.
+// Возвращает и функцию закрытия соединений.
+func buildTokenStore(cfg *config.Config, users *repository.UserRepository) (repository.TokenStore, func()) {
+	rdb, err := db.NewRedisClient(cfg)
+	if err != nil {
+		logger.Log.Warn("Redis недоступен — токены хранятся в Postgres", zap.Error(err))
+		return users, func() {}
+	}
+	if rdb == nil {
+		return users, func() {}
+	}
+	refreshTTL, err := time.ParseDuration(cfg.RefreshTokenTTL)
+	if err != nil || refreshTTL <= 0 {
+		refreshTTL = 720 * time.Hour
+	}
+	logger.Log.Info("Токены хранятся в Redis")
+	return repository.NewRedisTokenStore(rdb, refreshTTL), func() { _ = rdb.Close() }
+}
+
+// buildEventSinks — sink-и шины событий из EVENTS_SINKS (db, webhook, log).
+// Брокеры (Kafka/NATS) подключаются так же — реализацией events.Sink.
+func buildEventSinks(cfg *config.Config, repo *repository.DomainEventRepository) []events.Sink {
+	var sinks []events.Sink
+	for _, name := range strings.Split(cfg.EventsSinks, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "":
+		case "db":
+			sinks = append(sinks, events.NewDBSink(repo))
+		case "webhook":
+			if cfg.EventsWebhookURL == "" {
+				logger.Log.Warn("EVENTS_SINKS содержит webhook, но EVENTS_WEBHOOK_URL не задан — sink пропущен")
+				continue
+			}
+			sinks = append(sinks, events.NewWebhookSink(cfg.EventsWebhookURL, cfg.EventsWebhookSecret))
+		case "log":
+			sinks = append(sinks, events.LogSink{})
+		default:
+			logger.Log.Warn("Неизвестный sink событий", zap.String("sink", name))
+		}
+	}
+	return sinks
+}
+
+// buildRateLimits — ограничители частоты запросов из RATE_LIMIT_* (0 — выключено).
+func buildRateLimits(cfg *config.Config) middleware.RateLimits {
+	atoi := func(v string) int {
+		n, _ := strconv.Atoi(strings.TrimSpace(v))
+		return n
+	}
+	return middleware.RateLimits{
+		Auth:   middleware.NewRateLimiter("auth", atoi(cfg.RateLimitAuthPerMin), atoi(cfg.RateLimitAuthBurst)),
+		Global: middleware.NewRateLimiter("global", atoi(cfg.RateLimitGlobalPerMin), atoi(cfg.RateLimitGlobalBurst)),
+		User:   middleware.NewRateLimiter("user", atoi(cfg.RateLimitUserPerMin), atoi(cfg.RateLimitUserBurst)),
+	}
+}
+
+// buildCompression — сжатие ответов из COMPRESS_* (по умолчанию включено, от 1 КБ).
+func buildCompression(cfg *config.Config) middleware.CompressOptions {
+	minSize, err := strconv.Atoi(strings.TrimSpace(cfg.CompressMinBytes))
+	if err != nil || minSize < 0 {
+		minSize = 1024
+	}
+	return middleware.CompressOptions{
+		Enabled: !strings.EqualFold(strings.TrimSpace(cfg.CompressEnabled), "false"),
+		MinSize: minSize,
+	}
 }
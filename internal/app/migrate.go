@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"edutalks/internal/config"
+	"edutalks/internal/db"
+	"edutalks/internal/logger"
+	"edutalks/migrations"
+
+	"go.uber.org/zap"
+)
+
+const migrateUsage = "usage: main migrate up|down|status|version"
+
+// RunMigrate — подкоманда `migrate`: up — применить все новые миграции, down — откатить
+// последнюю, status — список с отметкой о применении, version — текущая версия схемы.
+func RunMigrate(cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+
+	conn, err := db.NewPostgresConnection(cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	m, err := db.NewMigrator(conn, migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		n, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		v, _ := m.Version(ctx)
+		fmt.Fprintf(out, "applied %d migration(s), version %d\n", n, v)
+	case "down":
+		mig, err := m.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if mig == nil {
+			fmt.Fprintln(out, "nothing to roll back")
+			return nil
+		}
+		fmt.Fprintf(out, "rolled back %d_%s\n", mig.Version, mig.Name)
+	case "status":
+		list, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%-24s %-16s %s\n", "Applied At", "Version", "Migration")
+		for _, st := range list {
+			applied := "Pending"
+			if st.AppliedAt != nil {
+				applied = st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(out, "%-24s %-16d %s\n", applied, st.Version, st.Name)
+		}
+	case "version":
+		v, err := m.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "version %d (latest %d)\n", v, m.Latest())
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
+
+// migrateOnStart — применяет встроенные миграции при старте (MIGRATE_ON_START).
+func migrateOnStart(ctx context.Context, m *db.Migrator) error {
+	n, err := m.Up(ctx)
+	if err != nil {
+		return err
+	}
+	v, _ := m.Version(ctx)
+	logger.Log.Info("Миграции применены при старте", zap.Int("applied", n), zap.Int64("version", v))
+	return nil
+}
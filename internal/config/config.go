@@ -3,9 +3,11 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/joho/godotenv"
+	"edutalks/internal/utils/clientip"
 )
 
 type Config struct {
@@ -17,6 +19,17 @@ type Config struct {
 	DbName    string
 	DbSSLMode string
 
+	DbStatementTimeout string // пример: "10s" — statement_timeout сессий пула; "0" — без ограничения
+	DbSlowQueryMs      string // пример: "500" — запросы дольше пишутся в лог предупреждением; "0" — выключено
+	RequestTimeout     string // пример: "15s" — дедлайн контекста запроса (его получают все запросы к БД); "0" — без дедлайна
+
+	// Пул соединений pgx; пусто — значения pgx по умолчанию
+	DbMaxConns          string // пример: "20" (по умолчанию pgx: max(4, число CPU))
+	DbMinConns          string // пример: "2"
+	DbMaxConnLifetime   string // пример: "1h"
+	DbMaxConnIdleTime   string // пример: "30m"
+	DbPoolStatsInterval string // пример: "1m" — как часто пишем статистику пула в лог; "0" — не пишем
+
 	JWTSecret       string
 	AccessTokenTTL  string
 	RefreshTokenTTL string
@@ -25,6 +38,10 @@ type Config struct {
 	LogLevel string
 	Env      string // dev|prod
 
+	LogRetentionDays     string // пример: "14" — сколько дней хранить файлы логов
+	LogCompressAfterDays string // пример: "2" — файлы старше стольких дней сжимаются в .gz; "0" — не сжимать
+	LogCleanupInterval   string // пример: "6h" — как часто чистить и сжимать логи; "0" — только вручную
+
 	SMTPHost     string
 	SMTPPort     string
 	SMTPUser     string
@@ -36,6 +53,13 @@ type Config struct {
 	YooKassaSecret    string
 	YooKassaReturnURL string
 
+	// Проверка входящих уведомлений ЮKassa
+	YooKassaWebhookIPs        string // CSV из IP/CIDR; пусто — официальные диапазоны ЮKassa, "*" — без проверки
+	YooKassaWebhookTrustProxy string // устарело, см. TrustedProxies
+	YooKassaWebhookUser       string // basic-auth в URL уведомления (опционально)
+	YooKassaWebhookPassword   string
+	YooKassaWebhookSecret     string // HMAC-SHA256 тела в заголовке X-Webhook-Signature (опционально, для прокси)
+
 	FrontendURL         string
 	PasswordResetTTLMin string
 
@@ -45,11 +69,189 @@ type Config struct {
 	EmailMaxRetries        string // пример: "6"
 	EmailBaseBackoff       string // пример: "30s"
 	EmailBatchSize         string // пример: "25"
+	EmailSpoolFile         string // пример: "email_spool.jsonl" — письма, которые не удалось ни сохранить в outbox, ни отправить
+	ShutdownTimeout        string // пример: "30s" — сколько ждать фоновые задачи и отправку писем при остановке
+
+	// Токены подтверждения email
+	EmailVerifyTokenTTL       string // пример: "24h"
+	EmailVerifyResendCooldown string // пример: "5m" — пауза между повторными письмами
+	EmailVerifyWebURL         string // страница подтверждения; по умолчанию SITEURL + "/verify-email"
+	EmailVerifyAppURL         string // deep link мобильного приложения, например "edutalks://verify-email"; пусто — не добавляем
+	EmailTokenMaxOutstanding  string // пример: "3" — сколько неподтверждённых ссылок живо одновременно
+	EmailTokenCleanupInterval string // пример: "1h"
+
+	// Планировщик подписок
+	SubscriptionCheckInterval string // пример: "1h" — как часто истекать подписки и слать напоминания
+	SubscriptionRemindBefore  string // пример: "72h" — за сколько до окончания предупреждать
+	SubscriptionRenewURL      string // куда ведёт кнопка «Продлить»; по умолчанию FRONTEND_URL
+
+	// Еженедельная сводка для администраторов
+	AdminDigestEmails  string // CSV адресов; пусто — сводка не отправляется
+	AdminDigestWeekday string // пример: "monday"
+	AdminDigestHour    string // пример: "9" — час по локальному времени сервера
+
+	// Шина доменных событий
+	EventsSinks         string // CSV: db,webhook,log (пример: "db")
+	EventsWebhookURL    string // куда слать события для sink-а webhook
+	EventsWebhookSecret string // HMAC-подпись тела (X-Event-Signature); пусто — без подписи
+
+	// Оповещения об ошибках в логах (Telegram/Slack)
+	AlertTelegramBotToken string // токен бота; вместе с ALERT_TELEGRAM_CHAT_ID включает Telegram
+	AlertTelegramChatID   string
+	AlertSlackWebhookURL  string // incoming webhook Slack; пусто — Slack выключен
+	AlertMinLevel         string // "error" | "dpanic" | "panic" | "fatal"
+	AlertDedupWindow      string // пример: "10m" — одинаковая ошибка за окно оповещает один раз
+	AlertMaxPerMinute     string // пример: "10" — не больше оповещений в минуту на инстанс
+
+	// Sentry: паники и ответы 5xx; пустой DSN — выключено
+	SentryDSN         string
+	SentryEnvironment string // по умолчанию — ENV
+	SentryRelease     string // пусто — ревизия git из сборки
+
+	// Ограничение частоты запросов (token bucket на IP и на пользователя)
+	RateLimitAuthPerMin   string // пример: "10" — /login, /register, /password/forgot; 0 — выключено
+	RateLimitAuthBurst    string // пример: "5"
+	RateLimitGlobalPerMin string // пример: "600" — все запросы /api; 0 — выключено
+	RateLimitGlobalBurst  string // пример: "100"
+	RateLimitUserPerMin   string // пример: "300" — запросы авторизованного пользователя; 0 — выключено
+	RateLimitUserBurst    string // пример: "60"
+	RateLimitTrustProxy   string // устарело, см. TrustedProxies
+
+	// CSV из IP/CIDR прокси (nginx), от которых принимаются X-Forwarded-For / X-Real-IP;
+	// одно правило для логов, ограничения частоты, сессий и вебхуков. Пусто — заголовки игнорируются
+	TrustedProxies string
+
+	// Отложенная публикация статей
+	ArticlePublishInterval string // пример: "1m" — как часто проверять publish_at
+
+	// Свежесть контента: сколько после содержательной правки материал считается «обновлённым»
+	ContentFreshWindow string // пример: "168h"
+
+	// Подмены внешних провайдеров для dev/staging
+	EmailMode      string // "smtp" (по умолчанию) | "file" — письма сохраняются в EmailDevDir как .eml
+	EmailDevDir    string // пример: "dev_mail"
+	PaymentSandbox string // "true" — платежи без ЮKassa, уведомления эмулирует админ
+
+	// Сверка авторизации маршрутов со Swagger при старте
+	AuthzCheck string // "warn" (по умолчанию) | "strict" — не стартовать при расхождениях | "off"
+
+	// Отписка в один клик (List-Unsubscribe)
+	UnsubscribeURL    string // пример: "https://edutalks.ru/api/unsubscribe"
+	UnsubscribeSecret string // ключ подписи токенов отписки; пусто — ключ, выведенный из JWT_SECRET (HKDF)
+
+	// Шаблоны писем: каталог переопределений встроенных шаблонов (правятся и через админку)
+	EmailTemplatesDir string // пример: "templates/email"; пусто — только встроенные
+
+	// Квитанции об оплате (PDF): реквизиты продавца, префикс номера и шрифт с кириллицей
+	InvoiceSellerName    string // пример: "ИП Иванов Иван Иванович"
+	InvoiceSellerINN     string
+	InvoiceSellerOGRN    string // ОГРН/ОГРНИП
+	InvoiceSellerAddress string
+	InvoiceNumberPrefix  string // пример: "ET" → ET2026-000001
+	InvoiceFont          string // путь к .ttf (TrueType)
+
+	// Prometheus: токен для /metrics (Authorization: Bearer ...); пусто — без авторизации
+	MetricsToken string
+
+	// Самопроверка при старте (БД, миграции, загрузки, SMTP, переменные, JWT)
+	SelfCheckMode string // "warn" (по умолчанию) | "strict" — не стартовать при ошибках | "off"
+
+	// Встроенные миграции схемы: применять при старте (иначе — командой `main migrate up`)
+	MigrateOnStart string // "true" (по умолчанию) | "false"
+
+	// Групповая рассылка о новых/обновлённых материалах: как часто отправлять накопленное
+	NotifyBatchInterval string // пример: "10m"
+
+	// Redis для блоклиста access-токенов и refresh-токенов; пусто — хранятся в Postgres
+	RedisURL string // пример: "redis://:password@localhost:6379/0", с TLS — rediss://
+
+	// Кэш пользователей по ID в AuthService (скачивание документов, профиль)
+	UserCacheTTL  string // пример: "30s"; "0" — без кэша
+	UserCacheSize string // пример: "1000" — сколько пользователей держать
+
+	// Выгрузка данных пользователя (ZIP): каталог архивов, срок действия ссылки и её базовый URL
+	ExportDir     string // пример: "exports"
+	ExportLinkTTL string // пример: "72h"
+	ExportURL     string // пример: "https://edutalks.ru/api/exports"
+
+	// Удаление аккаунта по запросу пользователя: срок на отмену и URL ссылки отмены из письма
+	AccountDeletionGrace     string // пример: "720h"
+	AccountDeletionCancelURL string // пример: "https://edutalks.ru/api/account/deletion/cancel"
+
+	// Корзина: через сколько дней удалённые документы, новости и статьи удаляются окончательно
+	TrashRetentionDays string // пример: "30"
+
+	// Смена email: ссылка подтверждения, которая уходит на новый адрес
+	EmailChangeConfirmURL string // пример: "https://edutalks.ru/api/profile/email/confirm"
+
+	// Вход через соцсети (провайдер включён, если заданы client id и secret)
+	OAuthGoogleClientID     string
+	OAuthGoogleClientSecret string
+	OAuthYandexClientID     string
+	OAuthYandexClientSecret string
+	OAuthRedirectBaseURL    string // пример: "https://edutalks.ru/api/auth/oauth" (+ /{provider}/callback)
+
+	// Сжатие ответов
+	CompressEnabled  string // "true" | "false"
+	CompressMinBytes string // пример: "1024"
+
+	// Антивирус (clamd); пустой адрес — проверка выключена
+	ClamAVAddress string // пример: "unix:/run/clamav/clamd.ctl" или "127.0.0.1:3310"
+	ClamAVTimeout string // пример: "60s"
+
+	// Допустимые типы документов и лимиты размера в МБ
+	UploadDocumentTypes string // пример: "pdf:100,docx:50,zip:200"
+
+	// Загрузка файла, который уже есть (по SHA-256): warn — сохранить копию и предупредить,
+	// link — не хранить копию, а сослаться на файл существующего документа
+	DuplicateUploadMode string // "warn" | "link"
+
+	// Водяной знак с данными подписчика при просмотре PDF в браузере
+	ViewWatermark string // "true" | "false"
+
+	// Дневной лимит скачиваний по тарифам; тариф без записи — без ограничения
+	DownloadQuotas string // пример: "free:5,subscriber:20,monthly:20,halfyear:30,yearly:50"
+
+	// Отложенная публикация новостей
+	NewsPublishInterval string // пример: "1m" — как часто проверять publish_at
+
+	// Популярное: период по умолчанию для GET /api/content/trending
+	TrendingWindow string // пример: "168h"
+
+	// Вход поддержки под пользователем: срок действия выдаваемого токена
+	ImpersonationTTL string // пример: "15m"
+
+	// Очистка HTML статей: что разрешено сверх базового профиля
+	HTMLExtraElements string // пример: "mark,abbr"
+	HTMLExtraAttrs    string // пример: "img:width|height|loading,span:class"
+	HTMLIframeHosts   string // пример: "www.youtube.com,rutube.ru"; "none" — iframe запрещены
+	HTMLAllowVideo    string // "true" | "false"
+
+	// Сводки новых материалов для пользователей с email_frequency daily / weekly (время — МСК)
+	UserDigestHour    string // пример: "8"
+	UserDigestWeekday string // пример: "monday" — день еженедельной сводки
+
+	// SMS: подтверждение телефона и сброс пароля по номеру
+	SMSProvider       string // "smsc" | "smsru" | "log" — только в лог (dev); пусто — SMS выключены
+	SMSCLogin         string
+	SMSCPassword      string
+	SMSRuAPIID        string
+	SMSSenderName     string // имя отправителя, согласованное у провайдера; пусто — по умолчанию
+	SMSCodeTTL        string // пример: "10m"
+	SMSResendCooldown string // пример: "60s" — пауза между кодами одному пользователю
+
+	PhoneResetRequireVerified string // "true" (по умолчанию) — код сброса пароля приходит только на подтверждённый номер
+
+	// Письма о входе с нового устройства
+	GeoIPProvider      string // "ipwhois" — примерное место по IP через ipwho.is (HTTPS); пусто — без места
+	GeoIPURL           string // свой адрес API в формате ipwho.is, только https; пусто — https://ipwho.is/
+	GeoIPTimeout       string // пример: "2s" — дольше место не ищем, письмо уходит без него
+	LoginAlertResetURL string // ссылка «сбросить пароль» в письме; пусто — FRONTEND_URL + /forgot-password
 }
 
 // LoadConfig загружает .env, читает переменные окружения и выставляет дефолты.
 func LoadConfig() (*Config, error) {
-	_ = godotenv.Load(".env")
+	loadDotEnv()
 
 	def := func(v, d string) string {
 		v = strings.TrimSpace(v)
@@ -68,6 +270,16 @@ func LoadConfig() (*Config, error) {
 		DbName:    os.Getenv("DB_NAME"),
 		DbSSLMode: def(os.Getenv("DB_SSLMODE"), "disable"),
 
+		DbStatementTimeout: def(os.Getenv("DB_STATEMENT_TIMEOUT"), "10s"),
+		DbSlowQueryMs:      def(os.Getenv("DB_SLOW_QUERY_MS"), "500"),
+		RequestTimeout:     def(os.Getenv("REQUEST_TIMEOUT"), "15s"),
+
+		DbMaxConns:          os.Getenv("DB_MAX_CONNS"),
+		DbMinConns:          os.Getenv("DB_MIN_CONNS"),
+		DbMaxConnLifetime:   os.Getenv("DB_MAX_CONN_LIFETIME"),
+		DbMaxConnIdleTime:   os.Getenv("DB_MAX_CONN_IDLE_TIME"),
+		DbPoolStatsInterval: def(os.Getenv("DB_POOL_STATS_INTERVAL"), "1m"),
+
 		JWTSecret:       os.Getenv("JWT_SECRET"),
 		AccessTokenTTL:  def(os.Getenv("ACCESS_TOKEN_EXPIRY"), "15m"),
 		RefreshTokenTTL: def(os.Getenv("REFRESH_TOKEN_EXPIRY"), "720h"),
@@ -76,6 +288,10 @@ func LoadConfig() (*Config, error) {
 		LogLevel: strings.ToLower(def(os.Getenv("LOGLEVEL"), "info")),
 		Env:      strings.ToLower(def(os.Getenv("ENV"), "prod")),
 
+		LogRetentionDays:     def(os.Getenv("LOG_RETENTION_DAYS"), "14"),
+		LogCompressAfterDays: def(os.Getenv("LOG_COMPRESS_AFTER_DAYS"), "2"),
+		LogCleanupInterval:   def(os.Getenv("LOG_CLEANUP_INTERVAL"), "6h"),
+
 		SMTPHost:     os.Getenv("SMTP_HOST"),
 		SMTPPort:     def(os.Getenv("SMTP_PORT"), "587"),
 		SMTPUser:     os.Getenv("SMTP_USER"),
@@ -89,12 +305,153 @@ func LoadConfig() (*Config, error) {
 		FrontendURL:         os.Getenv("FRONTEND_URL"),
 		PasswordResetTTLMin: def(os.Getenv("PASSWORD_RESET_TTL_MIN"), "30"),
 
+		YooKassaWebhookIPs:        os.Getenv("YOOKASSA_WEBHOOK_IPS"),
+		YooKassaWebhookTrustProxy: def(os.Getenv("YOOKASSA_WEBHOOK_TRUST_PROXY"), "false"),
+		YooKassaWebhookUser:       os.Getenv("YOOKASSA_WEBHOOK_USER"),
+		YooKassaWebhookPassword:   os.Getenv("YOOKASSA_WEBHOOK_PASSWORD"),
+		YooKassaWebhookSecret:     os.Getenv("YOOKASSA_WEBHOOK_SECRET"),
+
 		// Новые поля: читаем как строки, парсим в сервисах
 		EmailSendInterval:      def(os.Getenv("EMAIL_SEND_INTERVAL"), "10s"),
 		EmailPerRecipientDelay: def(os.Getenv("EMAIL_PER_RECIPIENT_DELAY"), "2s"),
 		EmailMaxRetries:        def(os.Getenv("EMAIL_MAX_RETRIES"), "6"),
 		EmailBaseBackoff:       def(os.Getenv("EMAIL_BASE_BACKOFF"), "30s"),
 		EmailBatchSize:         def(os.Getenv("EMAIL_BATCH_SIZE"), "25"),
+		EmailSpoolFile:         def(os.Getenv("EMAIL_SPOOL_FILE"), "email_spool.jsonl"),
+		ShutdownTimeout:        def(os.Getenv("SHUTDOWN_TIMEOUT"), "30s"),
+
+		EmailVerifyTokenTTL:       def(os.Getenv("EMAIL_VERIFY_TOKEN_TTL"), "24h"),
+		EmailVerifyResendCooldown: def(os.Getenv("EMAIL_VERIFY_RESEND_COOLDOWN"), "5m"),
+		EmailVerifyWebURL:         os.Getenv("EMAIL_VERIFY_WEB_URL"),
+		EmailVerifyAppURL:         os.Getenv("EMAIL_VERIFY_APP_URL"),
+		EmailTokenMaxOutstanding:  def(os.Getenv("EMAIL_TOKEN_MAX_OUTSTANDING"), "3"),
+		EmailTokenCleanupInterval: def(os.Getenv("EMAIL_TOKEN_CLEANUP_INTERVAL"), "1h"),
+
+		SubscriptionCheckInterval: def(os.Getenv("SUBSCRIPTION_CHECK_INTERVAL"), "1h"),
+		SubscriptionRemindBefore:  def(os.Getenv("SUBSCRIPTION_REMIND_BEFORE"), "72h"),
+		SubscriptionRenewURL:      os.Getenv("SUBSCRIPTION_RENEW_URL"),
+
+		AdminDigestEmails:  os.Getenv("ADMIN_DIGEST_EMAILS"),
+		AdminDigestWeekday: def(os.Getenv("ADMIN_DIGEST_WEEKDAY"), "monday"),
+		AdminDigestHour:    def(os.Getenv("ADMIN_DIGEST_HOUR"), "9"),
+
+		EventsSinks:         def(os.Getenv("EVENTS_SINKS"), "db"),
+		EventsWebhookURL:    os.Getenv("EVENTS_WEBHOOK_URL"),
+		EventsWebhookSecret: os.Getenv("EVENTS_WEBHOOK_SECRET"),
+
+		AlertTelegramBotToken: os.Getenv("ALERT_TELEGRAM_BOT_TOKEN"),
+		AlertTelegramChatID:   os.Getenv("ALERT_TELEGRAM_CHAT_ID"),
+		AlertSlackWebhookURL:  os.Getenv("ALERT_SLACK_WEBHOOK_URL"),
+		AlertMinLevel:         def(os.Getenv("ALERT_MIN_LEVEL"), "error"),
+		AlertDedupWindow:      def(os.Getenv("ALERT_DEDUP_WINDOW"), "10m"),
+		AlertMaxPerMinute:     def(os.Getenv("ALERT_MAX_PER_MINUTE"), "10"),
+
+		SentryDSN:         os.Getenv("SENTRY_DSN"),
+		SentryEnvironment: os.Getenv("SENTRY_ENVIRONMENT"),
+		SentryRelease:     os.Getenv("SENTRY_RELEASE"),
+
+		RateLimitAuthPerMin:   def(os.Getenv("RATE_LIMIT_AUTH_PER_MIN"), "10"),
+		RateLimitAuthBurst:    def(os.Getenv("RATE_LIMIT_AUTH_BURST"), "5"),
+		RateLimitGlobalPerMin: def(os.Getenv("RATE_LIMIT_GLOBAL_PER_MIN"), "0"),
+		RateLimitGlobalBurst:  def(os.Getenv("RATE_LIMIT_GLOBAL_BURST"), "100"),
+		RateLimitUserPerMin:   def(os.Getenv("RATE_LIMIT_USER_PER_MIN"), "0"),
+		RateLimitUserBurst:    def(os.Getenv("RATE_LIMIT_USER_BURST"), "60"),
+		RateLimitTrustProxy:   def(os.Getenv("RATE_LIMIT_TRUST_PROXY"), "false"),
+
+		TrustedProxies: os.Getenv("TRUSTED_PROXIES"),
+
+		ArticlePublishInterval: def(os.Getenv("ARTICLE_PUBLISH_INTERVAL"), "1m"),
+
+		ContentFreshWindow: def(os.Getenv("CONTENT_FRESH_WINDOW"), "168h"),
+
+		EmailMode:      strings.ToLower(def(os.Getenv("EMAIL_MODE"), "smtp")),
+		EmailDevDir:    def(os.Getenv("EMAIL_DEV_DIR"), "dev_mail"),
+		PaymentSandbox: def(os.Getenv("PAYMENT_SANDBOX"), "false"),
+
+		AuthzCheck: strings.ToLower(def(os.Getenv("AUTHZ_CHECK"), "warn")),
+
+		UnsubscribeURL:    def(os.Getenv("UNSUBSCRIBE_URL"), "https://edutalks.ru/api/unsubscribe"),
+		UnsubscribeSecret: os.Getenv("UNSUBSCRIBE_SECRET"),
+
+		EmailTemplatesDir: def(os.Getenv("EMAIL_TEMPLATES_DIR"), "templates/email"),
+
+		InvoiceSellerName:    def(os.Getenv("INVOICE_SELLER_NAME"), "Edutalks"),
+		InvoiceSellerINN:     os.Getenv("INVOICE_SELLER_INN"),
+		InvoiceSellerOGRN:    os.Getenv("INVOICE_SELLER_OGRN"),
+		InvoiceSellerAddress: os.Getenv("INVOICE_SELLER_ADDRESS"),
+		InvoiceNumberPrefix:  def(os.Getenv("INVOICE_NUMBER_PREFIX"), "ET"),
+		InvoiceFont:          def(os.Getenv("INVOICE_FONT"), "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"),
+
+		MetricsToken:  os.Getenv("METRICS_TOKEN"),
+		SelfCheckMode: strings.ToLower(def(os.Getenv("SELFCHECK_MODE"), "warn")),
+
+		MigrateOnStart: strings.ToLower(def(os.Getenv("MIGRATE_ON_START"), "true")),
+
+		NotifyBatchInterval: def(os.Getenv("NOTIFY_BATCH_INTERVAL"), "10m"),
+
+		RedisURL: os.Getenv("REDIS_URL"),
+
+		UserCacheTTL:  def(os.Getenv("USER_CACHE_TTL"), "30s"),
+		UserCacheSize: def(os.Getenv("USER_CACHE_SIZE"), "1000"),
+
+		ExportDir:     def(os.Getenv("EXPORT_DIR"), "exports"),
+		ExportLinkTTL: def(os.Getenv("EXPORT_LINK_TTL"), "72h"),
+		ExportURL:     def(os.Getenv("EXPORT_URL"), "https://edutalks.ru/api/exports"),
+
+		AccountDeletionGrace:     def(os.Getenv("ACCOUNT_DELETION_GRACE"), "720h"),
+		AccountDeletionCancelURL: def(os.Getenv("ACCOUNT_DELETION_CANCEL_URL"), "https://edutalks.ru/api/account/deletion/cancel"),
+
+		TrashRetentionDays: def(os.Getenv("TRASH_RETENTION_DAYS"), "30"),
+
+		EmailChangeConfirmURL: def(os.Getenv("EMAIL_CHANGE_CONFIRM_URL"), "https://edutalks.ru/api/profile/email/confirm"),
+
+		OAuthGoogleClientID:     os.Getenv("OAUTH_GOOGLE_CLIENT_ID"),
+		OAuthGoogleClientSecret: os.Getenv("OAUTH_GOOGLE_CLIENT_SECRET"),
+		OAuthYandexClientID:     os.Getenv("OAUTH_YANDEX_CLIENT_ID"),
+		OAuthYandexClientSecret: os.Getenv("OAUTH_YANDEX_CLIENT_SECRET"),
+		OAuthRedirectBaseURL:    def(os.Getenv("OAUTH_REDIRECT_BASE_URL"), "https://edutalks.ru/api/auth/oauth"),
+
+		CompressEnabled:  def(os.Getenv("COMPRESS_ENABLED"), "true"),
+		CompressMinBytes: def(os.Getenv("COMPRESS_MIN_BYTES"), "1024"),
+
+		ClamAVAddress: os.Getenv("CLAMAV_ADDRESS"),
+		ClamAVTimeout: def(os.Getenv("CLAMAV_TIMEOUT"), "60s"),
+
+		UploadDocumentTypes: os.Getenv("UPLOAD_DOCUMENT_TYPES"), // пусто — services.DefaultUploadDocumentTypes
+		DuplicateUploadMode: def(os.Getenv("DUPLICATE_UPLOAD_MODE"), "warn"),
+
+		ViewWatermark: def(os.Getenv("VIEW_WATERMARK"), "true"),
+
+		DownloadQuotas: os.Getenv("DOWNLOAD_QUOTAS"), // пусто — services.DefaultDownloadQuotas
+
+		NewsPublishInterval: def(os.Getenv("NEWS_PUBLISH_INTERVAL"), "1m"),
+
+		TrendingWindow: def(os.Getenv("TRENDING_WINDOW"), "168h"),
+
+		ImpersonationTTL: def(os.Getenv("IMPERSONATION_TTL"), "15m"),
+
+		HTMLExtraElements: os.Getenv("HTML_EXTRA_ELEMENTS"),
+		HTMLExtraAttrs:    os.Getenv("HTML_EXTRA_ATTRS"),
+		HTMLIframeHosts:   os.Getenv("HTML_IFRAME_HOSTS"), // пусто — services.DefaultHTMLIframeHosts
+		HTMLAllowVideo:    def(os.Getenv("HTML_ALLOW_VIDEO"), "true"),
+
+		UserDigestHour:    def(os.Getenv("USER_DIGEST_HOUR"), "8"),
+		UserDigestWeekday: def(os.Getenv("USER_DIGEST_WEEKDAY"), "monday"),
+
+		SMSProvider:       strings.ToLower(strings.TrimSpace(os.Getenv("SMS_PROVIDER"))),
+		SMSCLogin:         os.Getenv("SMSC_LOGIN"),
+		SMSCPassword:      os.Getenv("SMSC_PASSWORD"),
+		SMSRuAPIID:        os.Getenv("SMSRU_API_ID"),
+		SMSSenderName:     os.Getenv("SMS_SENDER_NAME"),
+		SMSCodeTTL:        def(os.Getenv("SMS_CODE_TTL"), "10m"),
+		SMSResendCooldown: def(os.Getenv("SMS_RESEND_COOLDOWN"), "60s"),
+
+		PhoneResetRequireVerified: def(os.Getenv("PHONE_RESET_REQUIRE_VERIFIED"), "true"),
+
+		GeoIPProvider:      strings.ToLower(strings.TrimSpace(os.Getenv("GEOIP_PROVIDER"))),
+		GeoIPURL:           strings.TrimSpace(os.Getenv("GEOIP_URL")),
+		GeoIPTimeout:       def(os.Getenv("GEOIP_TIMEOUT"), "2s"),
+		LoginAlertResetURL: strings.TrimSpace(os.Getenv("LOGIN_ALERT_RESET_URL")),
 	}
 
 	return cfg, nil
@@ -107,21 +464,124 @@ func (c *Config) Validate() (warnings []string, err error) {
 		return nil, fmt.Errorf("incomplete DB config (DB_HOST/DB_USER/DB_NAME)")
 	}
 
+	// Таймауты БД — предупреждение
+	if d, err := time.ParseDuration(strings.TrimSpace(c.DbStatementTimeout)); err != nil || d < 0 {
+		warnings = append(warnings, "DB_STATEMENT_TIMEOUT is invalid, using default 10s")
+	}
+	if _, err := time.ParseDuration(strings.TrimSpace(c.RequestTimeout)); err != nil {
+		warnings = append(warnings, "REQUEST_TIMEOUT is invalid, using default 15s")
+	}
+
+	// Хранение логов — предупреждение
+	if n, err := strconv.Atoi(strings.TrimSpace(c.LogRetentionDays)); err != nil || n < 1 {
+		warnings = append(warnings, "LOG_RETENTION_DAYS is invalid, using default 14")
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(c.LogCompressAfterDays)); err != nil || n < 0 {
+		warnings = append(warnings, "LOG_COMPRESS_AFTER_DAYS is invalid, using default 2")
+	}
+	if _, err := time.ParseDuration(strings.TrimSpace(c.LogCleanupInterval)); err != nil {
+		warnings = append(warnings, "LOG_CLEANUP_INTERVAL is invalid, cleanup runs only on demand")
+	}
+
+	// Корзина — предупреждение
+	if n, err := strconv.Atoi(strings.TrimSpace(c.TrashRetentionDays)); err != nil || n < 1 {
+		warnings = append(warnings, "TRASH_RETENTION_DAYS is invalid, using default 30")
+	}
+
+	// Оповещения об ошибках — предупреждение
+	if (c.AlertTelegramBotToken == "") != (c.AlertTelegramChatID == "") {
+		warnings = append(warnings, "ALERT_TELEGRAM_BOT_TOKEN and ALERT_TELEGRAM_CHAT_ID must be set together, Telegram alerts are off")
+	}
+	switch strings.ToLower(strings.TrimSpace(c.AlertMinLevel)) {
+	case "error", "dpanic", "panic", "fatal":
+	default:
+		warnings = append(warnings, "ALERT_MIN_LEVEL is invalid, using error")
+	}
+	if d, err := time.ParseDuration(strings.TrimSpace(c.AlertDedupWindow)); err != nil || d < 0 {
+		warnings = append(warnings, "ALERT_DEDUP_WINDOW is invalid, using default 10m")
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(c.AlertMaxPerMinute)); err != nil || n < 1 {
+		warnings = append(warnings, "ALERT_MAX_PER_MINUTE is invalid, using default 10")
+	}
+
 	// JWT — предупреждение
 	if strings.TrimSpace(c.JWTSecret) == "" {
 		warnings = append(warnings, "JWT_SECRET is empty")
 	}
 
 	// YooKassa — предупреждение
-	if c.YooKassaShopID == "" || c.YooKassaSecret == "" {
+	if c.PaymentSandboxEnabled() {
+		warnings = append(warnings, "PAYMENT_SANDBOX is on: payments are simulated")
+	} else if c.YooKassaShopID == "" || c.YooKassaSecret == "" {
 		warnings = append(warnings, "YooKassa credentials are not set")
 	}
 
 	// SMTP — предупреждение
-	if c.SMTPHost == "" || c.SMTPUser == "" {
+	if c.EmailMode == "file" {
+		warnings = append(warnings, "EMAIL_MODE=file: emails are written to "+c.EmailDevDir+" instead of SMTP")
+	} else if c.SMTPHost == "" || c.SMTPUser == "" {
 		warnings = append(warnings, "SMTP is not fully configured")
 	}
 
+	// Квитанции — предупреждение
+	if _, err := os.Stat(c.InvoiceFont); err != nil {
+		warnings = append(warnings, "INVOICE_FONT not found: PDF invoices are unavailable")
+	}
+	if c.InvoiceSellerINN == "" {
+		warnings = append(warnings, "INVOICE_SELLER_INN is empty: invoices are issued without seller requisites")
+	}
+
+	if c.ClamAVAddress == "" {
+		warnings = append(warnings, "CLAMAV_ADDRESS is empty: uploaded files are not scanned for viruses")
+	}
+	switch strings.ToLower(strings.TrimSpace(c.DuplicateUploadMode)) {
+	case "warn", "link":
+	default:
+		warnings = append(warnings, "DUPLICATE_UPLOAD_MODE must be warn or link, using warn")
+	}
+
+	// SMS — предупреждение
+	switch c.SMSProvider {
+	case "":
+		warnings = append(warnings, "SMS_PROVIDER is empty: phone verification and password reset by phone are off")
+	case "smsc":
+		if c.SMSCLogin == "" || c.SMSCPassword == "" {
+			warnings = append(warnings, "SMSC_LOGIN and SMSC_PASSWORD must be set for SMS_PROVIDER=smsc, SMS are off")
+		}
+	case "smsru":
+		if c.SMSRuAPIID == "" {
+			warnings = append(warnings, "SMSRU_API_ID must be set for SMS_PROVIDER=smsru, SMS are off")
+		}
+	case "log":
+		warnings = append(warnings, "SMS_PROVIDER=log: SMS are written to the log instead of being sent")
+	default:
+		warnings = append(warnings, "SMS_PROVIDER must be smsc, smsru or log, SMS are off")
+	}
+
+	// Письма о входе — предупреждение
+	switch c.GeoIPProvider {
+	case "":
+	case "ipwhois":
+		if c.GeoIPURL != "" && !strings.HasPrefix(c.GeoIPURL, "https://") {
+			warnings = append(warnings, "GEOIP_URL must use https, login alert emails go without location")
+		}
+	default:
+		warnings = append(warnings, "GEOIP_PROVIDER must be ipwhois or empty, login alert emails go without location")
+	}
+
+	// Доверенные прокси — предупреждение
+	if _, bad := clientip.ParseProxies(c.TrustedProxies); len(bad) > 0 {
+		warnings = append(warnings, "TRUSTED_PROXIES has invalid entries, skipped: "+strings.Join(bad, ", "))
+	}
+	if strings.TrimSpace(c.TrustedProxies) == "" && c.legacyTrustProxy() {
+		warnings = append(warnings, "RATE_LIMIT_TRUST_PROXY / YOOKASSA_WEBHOOK_TRUST_PROXY are deprecated: set TRUSTED_PROXIES, trusting loopback and private networks for now")
+	}
+
+	// Метрики — предупреждение
+	if c.MetricsToken == "" {
+		warnings = append(warnings, "METRICS_TOKEN is empty: /metrics is served without authorization")
+	}
+
 	// PORT
 	if c.Port == "" {
 		warnings = append(warnings, "PORT is empty, using default 8080")
@@ -130,6 +590,65 @@ func (c *Config) Validate() (warnings []string, err error) {
 	return warnings, nil
 }
 
+// FrontendBaseURL — адрес фронта без завершающего «/» (по умолчанию https://edutalks.ru).
+func (c *Config) FrontendBaseURL() string {
+	base := strings.TrimRight(strings.TrimSpace(c.FrontendURL), "/")
+	if base == "" {
+		return "https://edutalks.ru"
+	}
+	return base
+}
+
+// PasswordResetPageURL — страница запроса сброса пароля для ссылки в письме о входе (LOGIN_ALERT_RESET_URL).
+func (c *Config) PasswordResetPageURL() string {
+	if c.LoginAlertResetURL != "" {
+		return c.LoginAlertResetURL
+	}
+	return c.FrontendBaseURL() + "/forgot-password"
+}
+
+// TrustedProxyNets — доверенные прокси из TRUSTED_PROXIES. Без него прежние
+// RATE_LIMIT_TRUST_PROXY / YOOKASSA_WEBHOOK_TRUST_PROXY = true означают loopback и частные сети.
+func (c *Config) TrustedProxyNets() clientip.Proxies {
+	if strings.TrimSpace(c.TrustedProxies) != "" {
+		p, _ := clientip.ParseProxies(c.TrustedProxies)
+		return p
+	}
+	if c.legacyTrustProxy() {
+		return clientip.Private
+	}
+	return nil
+}
+
+func (c *Config) legacyTrustProxy() bool {
+	return strings.EqualFold(strings.TrimSpace(c.RateLimitTrustProxy), "true") ||
+		strings.EqualFold(strings.TrimSpace(c.YooKassaWebhookTrustProxy), "true")
+}
+
+// RequestDeadline — дедлайн контекста HTTP-запроса (REQUEST_TIMEOUT); 0 — без дедлайна.
+func (c *Config) RequestDeadline() time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(c.RequestTimeout))
+	if err != nil || d < 0 {
+		return 15 * time.Second
+	}
+	return d
+}
+
+// LinkDuplicateUploads — загрузка дубликата ссылается на уже сохранённый файл (DUPLICATE_UPLOAD_MODE=link).
+func (c *Config) LinkDuplicateUploads() bool {
+	return strings.EqualFold(strings.TrimSpace(c.DuplicateUploadMode), "link")
+}
+
+// PhoneResetVerifiedOnly — код сброса пароля только на подтверждённый номер (PHONE_RESET_REQUIRE_VERIFIED, по умолчанию true).
+func (c *Config) PhoneResetVerifiedOnly() bool {
+	return !strings.EqualFold(strings.TrimSpace(c.PhoneResetRequireVerified), "false")
+}
+
+// PaymentSandboxEnabled — включена ли эмуляция платежей (PAYMENT_SANDBOX=true).
+func (c *Config) PaymentSandboxEnabled() bool {
+	return strings.EqualFold(strings.TrimSpace(c.PaymentSandbox), "true")
+}
+
 // GetDSN — полная DSN (с паролем)
 func (c *Config) GetDSN() string {
 	return fmt.Sprintf(
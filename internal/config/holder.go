@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/joho/godotenv"
+)
+
+// Holder — загруженная конфигурация процесса. Обработчики получают Holder при создании и
+// берут настройки через Get() (атомарное чтение, без обращения к окружению и .env).
+// Reload (по SIGHUP) перечитывает окружение и .env без рестарта: значения, которые читаются
+// на запрос (JWT, адрес фронта, водяной знак, доверие прокси), применяются сразу; то, что
+// сервисы разбирают при старте (БД, интервалы фоновых задач, лимиты), — после рестарта.
+type Holder struct {
+	cur   atomic.Pointer[Config]
+	mu    sync.Mutex
+	hooks []func(*Config)
+}
+
+func NewHolder(cfg *Config) *Holder {
+	h := &Holder{}
+	h.cur.Store(cfg)
+	return h
+}
+
+// Get — текущая конфигурация; возвращаемое значение не меняется, Reload подменяет его целиком.
+func (h *Holder) Get() *Config {
+	return h.cur.Load()
+}
+
+// OnReload — fn вызывается с новой конфигурацией после каждого успешного Reload.
+func (h *Holder) OnReload(fn func(*Config)) {
+	h.mu.Lock()
+	h.hooks = append(h.hooks, fn)
+	h.mu.Unlock()
+}
+
+// Reload — перечитывает конфигурацию; при критичной ошибке (Validate) остаётся прежняя.
+// Возвращает предупреждения Validate новой конфигурации.
+func (h *Holder) Reload() ([]string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	h.cur.Store(cfg)
+	for _, fn := range h.hooks {
+		fn(cfg)
+	}
+	return warnings, nil
+}
+
+var (
+	processEnvOnce sync.Once
+	processEnv     map[string]bool // переменные, заданные окружением процесса до чтения .env
+)
+
+// loadDotEnv — значения из .env для переменных, которых нет в окружении процесса. Окружение
+// процесса важнее .env (как у godotenv.Load), но при повторном чтении изменения в .env
+// применяются. Переменная, удалённая из .env, сохраняет прежнее значение до рестарта.
+func loadDotEnv() {
+	processEnvOnce.Do(func() {
+		processEnv = map[string]bool{}
+		for _, kv := range os.Environ() {
+			k, _, _ := strings.Cut(kv, "=")
+			processEnv[k] = true
+		}
+	})
+
+	vals, err := godotenv.Read(".env")
+	if err != nil {
+		return
+	}
+	for k, v := range vals {
+		if !processEnv[k] {
+			_ = os.Setenv(k, v)
+		}
+	}
+}
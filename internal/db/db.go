@@ -4,6 +4,8 @@ import (
 	"context"
 	"edutalks/internal/config"
 	"edutalks/internal/logger"
+	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
@@ -14,7 +16,31 @@ func NewPostgresConnection(cfg *config.Config) (*pgxpool.Pool, error) {
 
 	logger.Log.Info("Подключение к Postgres...", zap.String("dsn", cfg.GetDSNSafe()))
 
-	pool, err := pgxpool.New(context.Background(), dsn)
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		logger.Log.Error("Некорректные параметры подключения к Postgres",
+			zap.String("dsn", cfg.GetDSNSafe()), zap.Error(err))
+		return nil, err
+	}
+
+	// statement_timeout — на каждый оператор сессии: медленный запрос отменит сам Postgres,
+	// даже если у контекста нет дедлайна (фоновые задачи)
+	statementTimeout, err := time.ParseDuration(cfg.DbStatementTimeout)
+	if err != nil || statementTimeout < 0 {
+		statementTimeout = 10 * time.Second
+	}
+	if statementTimeout > 0 {
+		poolCfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(statementTimeout.Milliseconds(), 10)
+	}
+
+	slowMs, err := strconv.Atoi(cfg.DbSlowQueryMs)
+	if err != nil || slowMs < 0 {
+		slowMs = 500
+	}
+	poolCfg.ConnConfig.Tracer = &queryTracer{slow: time.Duration(slowMs) * time.Millisecond}
+	applyPoolConfig(poolCfg, cfg)
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
 	if err != nil {
 		logger.Log.Error("Не удалось создать пул подключений к Postgres",
 			zap.String("dsn", cfg.GetDSNSafe()), zap.Error(err))
@@ -27,6 +53,30 @@ func NewPostgresConnection(cfg *config.Config) (*pgxpool.Pool, error) {
 		return nil, err
 	}
 
-	logger.Log.Info("Соединение с Postgres успешно установлено", zap.String("dsn", cfg.GetDSNSafe()))
+	logger.Log.Info("Соединение с Postgres успешно установлено", zap.String("dsn", cfg.GetDSNSafe()),
+		zap.Duration("statement_timeout", statementTimeout), zap.Int("slow_query_ms", slowMs),
+		zap.Int32("max_conns", poolCfg.MaxConns), zap.Int32("min_conns", poolCfg.MinConns),
+		zap.Duration("max_conn_lifetime", poolCfg.MaxConnLifetime), zap.Duration("max_conn_idle_time", poolCfg.MaxConnIdleTime))
 	return pool, nil
 }
+
+// WithoutStatementTimeout — fn на отдельном соединении без statement_timeout: для операций,
+// которые законно идут долго (миграции, REINDEX). После fn таймаут сессии восстанавливается.
+func WithoutStatementTimeout(ctx context.Context, pool *pgxpool.Pool, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SET statement_timeout = 0`); err != nil {
+		return err
+	}
+	defer func() {
+		// RESET возвращает значение из параметров подключения; не вышло — соединение не возвращаем в пул
+		if _, err := conn.Exec(context.WithoutCancel(ctx), `RESET statement_timeout`); err != nil {
+			_ = conn.Conn().Close(context.WithoutCancel(ctx))
+		}
+	}()
+	return fn(conn)
+}
@@ -0,0 +1,179 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+	"go.uber.org/zap"
+)
+
+// Миграции применяет goose (Provider API): файлы в его формате и его таблица версий
+// goose_db_version — базы, размеченные goose CLI, подхватываются как есть.
+
+// migrationLockKey — advisory lock на время миграций: при старте нескольких инстансов
+// схему обновляет один, остальные ждут и видят уже применённые версии.
+const migrationLockKey int64 = 0x65647574616c6b // "edutalk" в ASCII
+
+// Migration — одна миграция.
+type Migration struct {
+	Version int64
+	Name    string
+}
+
+// MigrationStatus — миграция и время её применения (nil — не применена).
+type MigrationStatus struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+type Migrator struct {
+	provider *goose.Provider
+}
+
+// NewMigrator — миграции из fsys (*.sql в корне). goose работает через database/sql,
+// поэтому у мигратора своё подключение с параметрами пула, но без statement_timeout:
+// ожидание блокировки и тяжёлые миграции не укладываются в таймаут запросов.
+// Пропущенные миграции старше текущей версии применяются (как у goose -allow-missing).
+func NewMigrator(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	connCfg := pool.Config().ConnConfig.Copy()
+	connCfg.RuntimeParams["statement_timeout"] = "0"
+	db := stdlib.OpenDB(*connCfg)
+	db.SetMaxOpenConns(2)
+
+	locker, err := lock.NewPostgresSessionLocker(lock.WithLockID(migrationLockKey))
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, fsys,
+		goose.WithSessionLocker(locker),
+		goose.WithAllowOutofOrder(true),
+		goose.WithDisableGlobalRegistry(true),
+	)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &Migrator{provider: provider}, nil
+}
+
+// Close — закрывает подключение мигратора (*sql.DB принадлежит goose.Provider).
+func (m *Migrator) Close() error {
+	return m.provider.Close()
+}
+
+// Migrations — все известные миграции по возрастанию версий.
+func (m *Migrator) Migrations() []Migration {
+	sources := m.provider.ListSources()
+	out := make([]Migration, 0, len(sources))
+	for _, s := range sources {
+		out = append(out, sourceMigration(s))
+	}
+	return out
+}
+
+// Latest — версия последней встроенной миграции (0 — миграций нет).
+func (m *Migrator) Latest() int64 {
+	sources := m.provider.ListSources()
+	if len(sources) == 0 {
+		return 0
+	}
+	return sources[len(sources)-1].Version
+}
+
+// Version — последняя применённая версия (0 — ничего не применено).
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	return m.provider.GetDBVersion(ctx)
+}
+
+// Status — все миграции с отметкой о применении.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	list, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]MigrationStatus, 0, len(list))
+	for _, st := range list {
+		mig := sourceMigration(st.Source)
+		item := MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if st.State == goose.StateApplied {
+			at := st.AppliedAt
+			item.AppliedAt = &at
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// Up — применяет все неприменённые миграции по возрастанию версий; возвращает их число.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	results, err := m.provider.Up(ctx)
+	var partial *goose.PartialError
+	if errors.As(err, &partial) {
+		results = append(partial.Applied, partial.Failed)
+	}
+	applied := 0
+	for _, res := range results {
+		logResult(res)
+		if res.Error == nil {
+			applied++
+		}
+	}
+	return applied, err
+}
+
+// Down — откатывает последнюю применённую миграцию; возвращает её (nil — откатывать нечего).
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	res, err := m.provider.Down(ctx)
+	if errors.Is(err, goose.ErrNoNextVersion) {
+		return nil, nil
+	}
+	var partial *goose.PartialError
+	if errors.As(err, &partial) {
+		logResult(partial.Failed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	logResult(res)
+	mig := sourceMigration(res.Source)
+	return &mig, nil
+}
+
+// sourceMigration — версия и имя из файла вида 20250708095309_create_users_table.sql.
+func sourceMigration(s *goose.Source) Migration {
+	name := strings.TrimSuffix(path.Base(s.Path), ".sql")
+	if _, rest, ok := strings.Cut(name, "_"); ok {
+		name = rest
+	}
+	return Migration{Version: s.Version, Name: name}
+}
+
+func logResult(res *goose.MigrationResult) {
+	if res == nil || res.Source == nil {
+		return
+	}
+	mig := sourceMigration(res.Source)
+	fields := []zap.Field{
+		zap.Int64("version", mig.Version),
+		zap.String("name", mig.Name),
+		zap.String("direction", res.Direction),
+		zap.Duration("duration", res.Duration),
+	}
+	if res.Error != nil {
+		logger.Log.Error("Миграции: ошибка", append(fields, zap.Error(res.Error))...)
+		return
+	}
+	logger.Log.Info("Миграции: применена", fields...)
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/metrics"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// applyPoolConfig — размеры и время жизни соединений пула из DB_*; пустые и некорректные
+// значения оставляют настройки pgx по умолчанию.
+func applyPoolConfig(poolCfg *pgxpool.Config, cfg *config.Config) {
+	if n, err := strconv.Atoi(strings.TrimSpace(cfg.DbMaxConns)); err == nil && n > 0 {
+		poolCfg.MaxConns = int32(n)
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(cfg.DbMinConns)); err == nil && n >= 0 {
+		poolCfg.MinConns = min(int32(n), poolCfg.MaxConns)
+	}
+	if d, err := time.ParseDuration(strings.TrimSpace(cfg.DbMaxConnLifetime)); err == nil && d > 0 {
+		poolCfg.MaxConnLifetime = d
+	}
+	if d, err := time.ParseDuration(strings.TrimSpace(cfg.DbMaxConnIdleTime)); err == nil && d > 0 {
+		poolCfg.MaxConnIdleTime = d
+	}
+}
+
+// RegisterPoolMetrics — gauge-и /metrics по статистике пула (pgxpool.Stat, без запросов к БД).
+// Накопительные значения (число и время ожиданий) растут с момента старта.
+func RegisterPoolMetrics(pool *pgxpool.Pool) {
+	gauge := func(name, help string, fn func(s *pgxpool.Stat) float64) {
+		metrics.NewGaugeFunc(name, help, func(context.Context) (float64, error) {
+			return fn(pool.Stat()), nil
+		})
+	}
+	gauge("db_pool_max_conns", "Максимум соединений пула", func(s *pgxpool.Stat) float64 { return float64(s.MaxConns()) })
+	gauge("db_pool_total_conns", "Соединений в пуле", func(s *pgxpool.Stat) float64 { return float64(s.TotalConns()) })
+	gauge("db_pool_acquired_conns", "Соединений занято запросами", func(s *pgxpool.Stat) float64 { return float64(s.AcquiredConns()) })
+	gauge("db_pool_idle_conns", "Свободных соединений", func(s *pgxpool.Stat) float64 { return float64(s.IdleConns()) })
+	gauge("db_pool_acquires", "Получений соединения из пула", func(s *pgxpool.Stat) float64 { return float64(s.AcquireCount()) })
+	gauge("db_pool_empty_acquires", "Получений, которым пришлось ждать: свободных соединений не было",
+		func(s *pgxpool.Stat) float64 { return float64(s.EmptyAcquireCount()) })
+	gauge("db_pool_acquire_wait_seconds", "Суммарное время получения соединений",
+		func(s *pgxpool.Stat) float64 { return s.AcquireDuration().Seconds() })
+	gauge("db_pool_canceled_acquires", "Получений, прерванных контекстом (таймаут при исчерпанном пуле)",
+		func(s *pgxpool.Stat) float64 { return float64(s.CanceledAcquireCount()) })
+}
+
+// StartPoolStats — раз в interval пишет в лог статистику пула за прошедший период: среднее
+// ожидание соединения и число ожиданий из-за исчерпания (тогда — предупреждение).
+// interval <= 0 — не запускается. Возвращает функцию остановки.
+func StartPoolStats(pool *pgxpool.Pool, interval time.Duration) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		prev := pool.Stat()
+		for {
+			select {
+			case <-ticker.C:
+				cur := pool.Stat()
+				reportPoolStats(prev, cur, interval)
+				prev = cur
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+func reportPoolStats(prev, cur *pgxpool.Stat, interval time.Duration) {
+	acquires := cur.AcquireCount() - prev.AcquireCount()
+	empty := cur.EmptyAcquireCount() - prev.EmptyAcquireCount()
+	canceled := cur.CanceledAcquireCount() - prev.CanceledAcquireCount()
+	wait := cur.AcquireDuration() - prev.AcquireDuration()
+
+	var avgWait time.Duration
+	if acquires > 0 {
+		avgWait = wait / time.Duration(acquires)
+	}
+	fields := []zap.Field{
+		zap.Duration("period", interval),
+		zap.Int64("acquires", acquires),
+		zap.Int64("empty_acquires", empty),
+		zap.Int64("canceled_acquires", canceled),
+		zap.Duration("acquire_wait_total", wait),
+		zap.Duration("acquire_wait_avg", avgWait),
+		zap.Int32("acquired_conns", cur.AcquiredConns()),
+		zap.Int32("idle_conns", cur.IdleConns()),
+		zap.Int32("total_conns", cur.TotalConns()),
+		zap.Int32("max_conns", cur.MaxConns()),
+	}
+	if empty > 0 || canceled > 0 {
+		logger.Log.Warn("Пул соединений БД исчерпывался: запросы ждали свободного соединения", fields...)
+		return
+	}
+	logger.Log.Debug("Статистика пула соединений БД", fields...)
+}
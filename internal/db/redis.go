@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	redisDialTimeout = 3 * time.Second
+	redisIOTimeout   = 2 * time.Second
+	redisMaxIdle     = 10
+)
+
+// NewRedisClient — клиент go-redis по REDIS_URL (redis://[user:password@]host:port[/db],
+// rediss:// — с TLS). Пустой URL — (nil, nil): Redis не используется.
+func NewRedisClient(cfg *config.Config) (*redis.Client, error) {
+	if strings.TrimSpace(cfg.RedisURL) == "" {
+		return nil, nil
+	}
+	opts, err := redis.ParseURL(strings.TrimSpace(cfg.RedisURL))
+	if err != nil {
+		return nil, fmt.Errorf("REDIS_URL: %w", err)
+	}
+	opts.DialTimeout = redisDialTimeout
+	opts.ReadTimeout = redisIOTimeout
+	opts.WriteTimeout = redisIOTimeout
+	opts.MaxIdleConns = redisMaxIdle
+	// таймауты запросов задаёт контекст (дедлайн HTTP-запроса)
+	opts.ContextTimeoutEnabled = true
+	rdb := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisDialTimeout)
+	defer cancel()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		logger.Log.Error("Не удалось подключиться к Redis (ping failed)", zap.String("addr", opts.Addr), zap.Error(err))
+		_ = rdb.Close()
+		return nil, err
+	}
+	logger.Log.Info("Соединение с Redis успешно установлено", zap.String("addr", opts.Addr), zap.Int("db", opts.DB))
+	return rdb, nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// slowQuerySQLMax — сколько символов запроса попадает в лог.
+const slowQuerySQLMax = 500
+
+// queryTracer — pgx.QueryTracer: пишет в лог запросы дольше slow и запросы, прерванные
+// по таймауту (дедлайн контекста или statement_timeout). Лог — с request_id из контекста.
+type queryTracer struct {
+	slow time.Duration // 0 — медленные запросы не логируются
+}
+
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	sql   string
+	start time.Time
+}
+
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, queryTrace{sql: data.SQL, start: time.Now()})
+}
+
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	tr, ok := ctx.Value(queryTraceKey{}).(queryTrace)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(tr.start)
+
+	switch {
+	case isQueryTimeout(data.Err):
+		logger.WithCtx(ctx).Warn("Запрос к БД прерван по таймауту",
+			zap.Duration("elapsed", elapsed), zap.String("sql", compactSQL(tr.sql)), zap.Error(data.Err))
+	case t.slow > 0 && elapsed >= t.slow:
+		logger.WithCtx(ctx).Warn("Медленный запрос к БД",
+			zap.Duration("elapsed", elapsed), zap.String("sql", compactSQL(tr.sql)), zap.Int64("rows", data.CommandTag.RowsAffected()))
+	}
+}
+
+// isQueryTimeout — дедлайн контекста или отмена запроса сервером (57014: statement_timeout).
+func isQueryTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "57014"
+}
+
+// compactSQL — запрос в одну строку, обрезанный до slowQuerySQLMax.
+func compactSQL(sql string) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+	if r := []rune(sql); len(r) > slowQuerySQLMax {
+		sql = string(r[:slowQuerySQLMax]) + "…"
+	}
+	return sql
+}
@@ -0,0 +1,200 @@
+// Package events — внутренняя шина продуктовых событий.
+// Код, где что-то происходит (регистрация, оплата, скачивание), только публикует событие;
+// уведомления, аналитика и аудит подписываются на шину или подключаются как sink.
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/metrics"
+	"edutalks/internal/models"
+	"edutalks/internal/reqctx"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	UserRegistered     = "user.registered"
+	SubscriptionGrant  = "subscription.granted"
+	DocumentDownloaded = "document.downloaded"
+	ArticlePublished   = "article.published"
+	ArticleUpdated     = "article.updated"  // правка текста опубликованной статьи
+	DocumentUpdated    = "document.updated" // новая версия публичного документа
+	DocumentsAdded     = "document.added"   // загружены новые документы (один или пакет в одном разделе)
+
+	SubscriptionExpiring = "subscription.expiring" // скоро закончится подписка (одно на дату окончания)
+)
+
+// Sink — внешний приёмник событий (таблица, вебхук, брокер сообщений).
+type Sink interface {
+	Name() string
+	Handle(ctx context.Context, ev models.DomainEvent) error
+}
+
+// Handler — внутрипроцессный подписчик.
+type Handler func(ctx context.Context, ev models.DomainEvent)
+
+var (
+	eventsPublished = metrics.NewCounter("domain_events_published_total", "Опубликовано доменных событий")
+	eventsDropped   = metrics.NewCounter("domain_events_dropped_total", "Событий отброшено из-за переполнения очереди")
+	sinkFailures    = metrics.NewCounter("domain_events_sink_failures_total", "Ошибок доставки событий в sink")
+)
+
+type envelope struct {
+	ctx context.Context
+	ev  models.DomainEvent
+}
+
+// Bus — асинхронная шина: Publish не блокирует вызывающий код,
+// доставка в подписчиков и sink-и идёт в фоновых воркерах.
+type Bus struct {
+	queue chan envelope
+	sinks []Sink
+
+	mu   sync.RWMutex
+	subs map[string][]Handler
+
+	qmu    sync.RWMutex // защищает queue от отправки после Close
+	closed bool
+
+	wg sync.WaitGroup
+}
+
+func NewBus(buffer int, sinks ...Sink) *Bus {
+	if buffer <= 0 {
+		buffer = 1000
+	}
+	return &Bus{
+		queue: make(chan envelope, buffer),
+		sinks: sinks,
+		subs:  map[string][]Handler{},
+	}
+}
+
+// Subscribe — подписка на тип события; "*" — на все события.
+func (b *Bus) Subscribe(eventType string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[eventType] = append(b.subs[eventType], h)
+}
+
+// Publish — ставит событие в очередь. Если очередь переполнена, событие отбрасывается с предупреждением.
+func (b *Bus) Publish(ctx context.Context, eventType string, userID *int, payload map[string]any) {
+	ev := models.DomainEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		UserID:     userID,
+		Payload:    payload,
+		OccurredAt: time.Now().UTC(),
+	}
+	if rid, ok := reqctx.GetRequestID(ctx); ok {
+		ev.RequestID = rid
+	}
+
+	b.qmu.RLock()
+	defer b.qmu.RUnlock()
+	if b.closed {
+		logger.WithCtx(ctx).Warn("Шина событий остановлена — событие отброшено", zap.String("type", eventType))
+		return
+	}
+
+	select {
+	case b.queue <- envelope{ctx: context.WithoutCancel(ctx), ev: ev}:
+		eventsPublished.Inc()
+	default:
+		eventsDropped.Inc()
+		logger.WithCtx(ctx).Warn("Очередь событий переполнена — событие отброшено",
+			zap.String("type", eventType), zap.String("event_id", ev.ID))
+	}
+}
+
+// Start запускает воркеры доставки.
+func (b *Bus) Start(workers int) {
+	if workers <= 0 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			for env := range b.queue {
+				b.dispatch(env.ctx, env.ev)
+			}
+		}()
+	}
+	logger.Log.Info("Шина событий запущена", zap.Int("workers", workers), zap.Int("sinks", len(b.sinks)))
+}
+
+// Close — перестаёт принимать события и дожидается доставки уже поставленных.
+func (b *Bus) Close() {
+	b.qmu.Lock()
+	if b.closed {
+		b.qmu.Unlock()
+		return
+	}
+	b.closed = true
+	close(b.queue)
+	b.qmu.Unlock()
+
+	b.wg.Wait()
+	logger.Log.Info("Шина событий остановлена")
+}
+
+func (b *Bus) dispatch(ctx context.Context, ev models.DomainEvent) {
+	log := logger.WithCtx(ctx)
+
+	for _, s := range b.sinks {
+		if err := s.Handle(ctx, ev); err != nil {
+			sinkFailures.Inc()
+			log.Error("Ошибка доставки события в sink",
+				zap.String("sink", s.Name()),
+				zap.String("type", ev.Type),
+				zap.String("event_id", ev.ID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	b.mu.RLock()
+	handlers := append(append([]Handler{}, b.subs[ev.Type]...), b.subs["*"]...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					log.Error("Паника в подписчике события", zap.String("type", ev.Type), zap.Any("panic", rec))
+				}
+			}()
+			h(ctx, ev)
+		}()
+	}
+}
+
+// ===== шина по умолчанию =====
+
+var (
+	defaultMu  sync.RWMutex
+	defaultBus *Bus
+)
+
+// SetDefault — шина, в которую публикуют events.Publish (выставляется в app.InitApp).
+func SetDefault(b *Bus) {
+	defaultMu.Lock()
+	defaultBus = b
+	defaultMu.Unlock()
+}
+
+// Publish — публикует событие в шину по умолчанию; без шины — no-op.
+func Publish(ctx context.Context, eventType string, userID *int, payload map[string]any) {
+	defaultMu.RLock()
+	b := defaultBus
+	defaultMu.RUnlock()
+	if b != nil {
+		b.Publish(ctx, eventType, userID, payload)
+	}
+}
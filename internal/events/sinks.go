@@ -0,0 +1,112 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// DBSink — журнал событий в таблице domain_events (аудит и аналитика).
+type DBSink struct {
+	repo *repository.DomainEventRepository
+}
+
+func NewDBSink(repo *repository.DomainEventRepository) *DBSink {
+	return &DBSink{repo: repo}
+}
+
+func (s *DBSink) Name() string { return "db" }
+
+func (s *DBSink) Handle(ctx context.Context, ev models.DomainEvent) error {
+	return s.repo.Insert(ctx, ev)
+}
+
+// WebhookSink — отправляет событие POST-запросом в JSON.
+// Если задан secret, тело подписывается HMAC-SHA256 (hex) в заголовке X-Event-Signature.
+type WebhookSink struct {
+	url      string
+	secret   []byte
+	client   *http.Client
+	attempts int
+}
+
+func NewWebhookSink(url, secret string) *WebhookSink {
+	return &WebhookSink{
+		url:      url,
+		secret:   []byte(secret),
+		client:   &http.Client{Timeout: 5 * time.Second},
+		attempts: 3,
+	}
+}
+
+func (s *WebhookSink) Name() string { return "webhook" }
+
+func (s *WebhookSink) Handle(ctx context.Context, ev models.DomainEvent) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for i := 0; i < s.attempts; i++ {
+		if i > 0 {
+			time.Sleep(time.Duration(i) * time.Second)
+		}
+		if lastErr = s.post(ctx, ev, body); lastErr == nil {
+			return nil
+		}
+	}
+	return lastErr
+}
+
+func (s *WebhookSink) post(ctx context.Context, ev models.DomainEvent, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Type", ev.Type)
+	req.Header.Set("X-Event-ID", ev.ID)
+	if len(s.secret) > 0 {
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write(body)
+		req.Header.Set("X-Event-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook ответил %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// LogSink — пишет события в лог приложения (удобно в разработке).
+type LogSink struct{}
+
+func (LogSink) Name() string { return "log" }
+
+func (LogSink) Handle(ctx context.Context, ev models.DomainEvent) error {
+	logger.WithCtx(ctx).Info("Событие",
+		zap.String("type", ev.Type),
+		zap.String("event_id", ev.ID),
+		zap.Any("user_id", ev.UserID),
+		zap.Any("payload", ev.Payload),
+	)
+	return nil
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+type AccountDeletionHandler struct {
+	svc *services.AccountDeletionService
+	cfg *config.Holder
+}
+
+func NewAccountDeletionHandler(svc *services.AccountDeletionService, cfg *config.Holder) *AccountDeletionHandler {
+	return &AccountDeletionHandler{svc: svc, cfg: cfg}
+}
+
+type deleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+type deleteAccountResponse struct {
+	Message  string    `json:"message"`
+	DeleteAt time.Time `json:"delete_at"`
+}
+
+// DeleteMyAccount godoc
+// @Summary Удалить мой аккаунт
+// @Tags profile
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Description Требует текущий пароль. Аккаунт удаляется по истечении срока на отмену (ACCOUNT_DELETION_GRACE); сразу же отзываются все токены и отключается рассылка. На почту приходит письмо со ссылкой отмены; до отмены вход недоступен.
+// @Param input body deleteAccountRequest true "Текущий пароль"
+// @Success 202 {object} helpers.Response{data=deleteAccountResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 401 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/profile [delete]
+func (h *AccountDeletionHandler) DeleteMyAccount(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+
+	var req deleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Password == "" {
+		helpers.Error(w, http.StatusBadRequest, "Укажите текущий пароль")
+		return
+	}
+
+	deleteAt, err := h.svc.Request(r.Context(), userID, req.Password)
+	switch {
+	case errors.Is(err, services.ErrDeletionPasswordInvalid):
+		helpers.Fail(w, http.StatusForbidden, helpers.CodeAuthPasswordInvalid, "Неверный пароль")
+		return
+	case err != nil:
+		log.Error("Ошибка запроса на удаление аккаунта", zap.Int("user_id", userID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось удалить аккаунт")
+		return
+	}
+
+	helpers.JSON(w, http.StatusAccepted, deleteAccountResponse{
+		Message:  "Аккаунт будет удалён. Отменить удаление можно по ссылке из письма",
+		DeleteAt: deleteAt,
+	})
+}
+
+// CancelDeletion godoc
+// @Summary Отменить удаление аккаунта по ссылке из письма
+// @Tags profile
+// @Param token query string true "Токен отмены"
+// @Success 302 {string} string "Редирект на страницу фронта"
+// @Failure 400 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/account/deletion/cancel [get]
+func (h *AccountDeletionHandler) CancelDeletion(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	token := strings.TrimSpace(r.URL.Query().Get("token"))
+	if token == "" {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeAuthLinkInvalid, "Токен отсутствует")
+		return
+	}
+	if _, err := h.svc.Cancel(r.Context(), token); err != nil {
+		if errors.Is(err, services.ErrDeletionCancelInvalid) {
+			log.Warn("Отмена удаления: неверная или устаревшая ссылка")
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeAuthLinkInvalid, err.Error())
+			return
+		}
+		log.Error("Ошибка отмены удаления аккаунта", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось отменить удаление")
+		return
+	}
+
+	base := h.cfg.Get().FrontendBaseURL()
+	http.Redirect(w, r, base+"/account-deletion?status=cancelled", http.StatusFound)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+type AdminDigestHandler struct {
+	svc *services.AdminDigestService
+}
+
+func NewAdminDigestHandler(svc *services.AdminDigestService) *AdminDigestHandler {
+	return &AdminDigestHandler{svc: svc}
+}
+
+// Send godoc
+// @Summary Отправить еженедельную сводку сейчас
+// @Description Собирает сводку за последние 7 дней (новые пользователи, платежи и выручка, топ скачиваний, ошибки в логах, истекающие подписки) и отправляет её на адреса из ADMIN_DIGEST_EMAILS. С dry_run=1 только возвращает сводку.
+// @Tags admin-digest
+// @Security ApiKeyAuth
+// @Produce json
+// @Param dry_run query int false "1 — не отправлять, только показать"
+// @Success 200 {object} helpers.Response{data=AdminDigestResponse}
+// @Failure 409 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/digest/send [post]
+func (h *AdminDigestHandler) Send(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	if r.URL.Query().Get("dry_run") == "1" {
+		d, err := h.svc.Build(r.Context(), time.Now())
+		if err != nil {
+			log.Error("Ошибка сборки сводки", zap.Error(err))
+			helpers.Error(w, http.StatusInternalServerError, "Не удалось собрать сводку")
+			return
+		}
+		helpers.JSON(w, http.StatusOK, AdminDigestResponse{Sent: false, Digest: d})
+		return
+	}
+
+	d, recipients, err := h.svc.SendNow(r.Context())
+	if err != nil {
+		if errors.Is(err, services.ErrDigestNoRecipients) {
+			helpers.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		log.Error("Ошибка отправки сводки", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось отправить сводку")
+		return
+	}
+
+	log.Info("Сводка отправлена вручную", zap.Int("recipients", len(recipients)))
+	helpers.JSON(w, http.StatusOK, AdminDigestResponse{
+		Sent:       true,
+		Recipients: len(recipients),
+		Digest:     d,
+	})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+type AdminAlertsHandler struct {
+	svc *services.AlertService
+}
+
+func NewAdminAlertsHandler(svc *services.AlertService) *AdminAlertsHandler {
+	return &AdminAlertsHandler{svc: svc}
+}
+
+// Test godoc
+// @Summary Проверить канал оповещений
+// @Description Отправляет тестовое сообщение в Telegram и/или Slack (ALERT_*) без дедупликации и лимита. Без channel — во все настроенные каналы; неудачная отправка видна в results.
+// @Tags admin-alerts
+// @Security ApiKeyAuth
+// @Produce json
+// @Param channel query string false "telegram или slack"
+// @Success 200 {object} helpers.Response{data=AlertTestResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 409 {object} helpers.Response
+// @Router /api/admin/alerts/test [post]
+func (h *AdminAlertsHandler) Test(w http.ResponseWriter, r *http.Request) {
+	channel := r.URL.Query().Get("channel")
+	switch channel {
+	case "", models.AlertChannelTelegram, models.AlertChannelSlack:
+	default:
+		helpers.Error(w, http.StatusBadRequest, "channel должен быть telegram или slack")
+		return
+	}
+
+	results, err := h.svc.SendTest(r.Context(), channel)
+	if err != nil {
+		if errors.Is(err, services.ErrAlertsDisabled) || errors.Is(err, services.ErrAlertChannelUnset) {
+			helpers.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		logger.WithCtx(r.Context()).Error("Ошибка тестового оповещения", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось отправить оповещение")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, AlertTestResponse{Results: results})
+}
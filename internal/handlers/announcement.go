@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type AnnouncementHandler struct {
+	svc *services.AnnouncementService
+}
+
+func NewAnnouncementHandler(svc *services.AnnouncementService) *AnnouncementHandler {
+	return &AnnouncementHandler{svc: svc}
+}
+
+// Active godoc
+// @Summary Активные баннеры сайта
+// @Description Включённые баннеры, у которых сейчас идёт окно показа; сначала critical, затем warning, info и promo. dismissible — пользователь может скрыть баннер (скрытие хранит фронт).
+// @Tags announcements
+// @Produce json
+// @Success 200 {object} helpers.Response{data=[]models.Announcement}
+// @Failure 500 {object} helpers.Response
+// @Router /api/announcements [get]
+func (h *AnnouncementHandler) Active(w http.ResponseWriter, r *http.Request) {
+	items, err := h.svc.Active(r.Context())
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка получения баннеров", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить баннеры")
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	helpers.JSON(w, http.StatusOK, items)
+}
+
+// AdminList godoc
+// @Summary Все баннеры, включая выключенные и завершённые
+// @Tags admin-announcements
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=AnnouncementListResponse}
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/announcements [get]
+func (h *AnnouncementHandler) AdminList(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := pageParams(r)
+
+	items, total, err := h.svc.List(r.Context(), pageSize, (page-1)*pageSize)
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка получения списка баннеров", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить список баннеров")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, AnnouncementListResponse{
+		Data:       items,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+	})
+}
+
+// Create godoc
+// @Summary Создать баннер
+// @Description severity: info|warning|critical|promo (по умолчанию info). starts_at/ends_at (RFC3339) не заданы — показывать сразу и бессрочно. dismissible и enabled по умолчанию true.
+// @Tags admin-announcements
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body models.AnnouncementRequest true "Баннер"
+// @Success 201 {object} helpers.Response{data=models.Announcement}
+// @Failure 400 {object} helpers.Response
+// @Router /api/admin/announcements [post]
+func (h *AnnouncementHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.AnnouncementRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	adminID, _ := middleware.UserIDFromContext(r.Context())
+	a, err := h.svc.Create(r.Context(), req, adminID)
+	if err != nil {
+		h.writeErr(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, a)
+}
+
+// Update godoc
+// @Summary Заменить баннер
+// @Description Заменяет все поля баннера; чтобы снять баннер, не удаляя, передайте enabled=false.
+// @Tags admin-announcements
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID баннера"
+// @Param input body models.AnnouncementRequest true "Баннер"
+// @Success 200 {object} helpers.Response{data=models.Announcement}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/announcements/{id} [put]
+func (h *AnnouncementHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := announcementID(w, r)
+	if !ok {
+		return
+	}
+	var req models.AnnouncementRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	a, err := h.svc.Update(r.Context(), id, req)
+	if err != nil {
+		h.writeErr(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, a)
+}
+
+// Delete godoc
+// @Summary Удалить баннер
+// @Tags admin-announcements
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID баннера"
+// @Success 200 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/announcements/{id} [delete]
+func (h *AnnouncementHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := announcementID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		h.writeErr(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Баннер удалён"})
+}
+
+func (h *AnnouncementHandler) writeErr(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrAnnouncementWindow):
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrAnnouncementNotFound):
+		helpers.Error(w, http.StatusNotFound, "Баннер не найден")
+	default:
+		logger.WithCtx(r.Context()).Error("Ошибка сохранения баннера", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+	}
+}
+
+func announcementID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id")
+		return 0, false
+	}
+	return id, true
+}
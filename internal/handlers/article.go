@@ -5,25 +5,31 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
+	"edutalks/internal/config"
 	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
 	"edutalks/internal/models"
 	"edutalks/internal/services"
 	"edutalks/internal/utils/helpers"
 )
 
+// ArticleHandler — рассылка о публикации статьи идёт через событие article.published (см. app.InitApp).
 type ArticleHandler struct {
-	svc      services.ArticleService
-	notifier *services.Notifier
+	svc   services.ArticleService
+	views *services.ContentViewService
+	cfg   *config.Holder
 }
 
-func NewArticleHandler(svc services.ArticleService, notifier *services.Notifier) *ArticleHandler {
-	return &ArticleHandler{svc: svc, notifier: notifier}
+func NewArticleHandler(svc services.ArticleService, views *services.ContentViewService, cfg *config.Holder) *ArticleHandler {
+	return &ArticleHandler{svc: svc, views: views, cfg: cfg}
 }
 
 // Preview
@@ -32,8 +38,9 @@ func NewArticleHandler(svc services.ArticleService, notifier *services.Notifier)
 // @Accept      json
 // @Produce     json
 // @Param       body body map[string]string true "Сырый HTML статьи"
-// @Success     200 {object} map[string]string
-// @Failure     400 {object} map[string]string
+// @Success     200 {object} helpers.Response{data=ArticlePreviewResponse}
+// @Failure     400 {object} helpers.Response
+// @Security    BearerAuth
 // @Router      /api/admin/articles/preview [post]
 func (h *ArticleHandler) Preview(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -43,14 +50,14 @@ func (h *ArticleHandler) Preview(w http.ResponseWriter, r *http.Request) {
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Warn("Невалидный JSON при предпросмотре статьи", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "invalid json")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "invalid json")
 		return
 	}
 
 	safe := h.svc.PreviewHTML(req.BodyHTML)
 	log.Info("Предпросмотр статьи создан")
 
-	helpers.JSON(w, http.StatusOK, map[string]string{"bodyHtml": safe})
+	helpers.JSON(w, http.StatusOK, ArticlePreviewResponse{BodyHTML: safe})
 }
 
 // Create
@@ -59,8 +66,8 @@ func (h *ArticleHandler) Preview(w http.ResponseWriter, r *http.Request) {
 // @Accept      json,mpfd,x-www-form-urlencoded
 // @Produce     json
 // @Param       body body models.CreateArticleRequest true "Данные статьи"
-// @Success     201 {object} models.Article
-// @Failure     400 {object} map[string]string
+// @Success     201 {object} helpers.Response{data=models.Article}
+// @Failure     400 {object} helpers.Response
 // @Security    BearerAuth
 // @Router      /api/admin/articles [post]
 func (h *ArticleHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -72,6 +79,9 @@ func (h *ArticleHandler) Create(w http.ResponseWriter, r *http.Request) {
 		helpers.Error(w, http.StatusBadRequest, "invalid payload")
 		return
 	}
+	if !validRequest(w, r, &req) {
+		return
+	}
 
 	authorID := authorIDFromCtx(r.Context())
 	log.Info("Запрос на создание статьи",
@@ -91,9 +101,6 @@ func (h *ArticleHandler) Create(w http.ResponseWriter, r *http.Request) {
 		zap.Bool("published", article.IsPublished),
 	)
 
-	ctx := context.WithoutCancel(r.Context())
-	go h.notifier.NotifyArticlePublished(ctx, int(article.ID), article.Title)
-
 	helpers.JSON(w, http.StatusCreated, article)
 }
 
@@ -103,8 +110,9 @@ func (h *ArticleHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Produce     json
 // @Param       page query int false "Номер страницы"
 // @Param       page_size query int false "Размер страницы"
-// @Success     200 {array} models.Article
-// @Failure     500 {object} map[string]string
+// @Success     200 {object} helpers.Response{data=[]models.Article}
+// @Success     304 {string} string "Не изменилось (If-None-Match)"
+// @Failure     500 {object} helpers.Response
 // @Router      /api/articles [get]
 func (h *ArticleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -112,16 +120,19 @@ func (h *ArticleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	limit := parseIntQuery(r, "limit", 20)
 	offset := parseIntQuery(r, "offset", 0)
 	tag := r.URL.Query().Get("tag")
-	onlyPublished := r.URL.Query().Get("published") == "true"
+	status := ""
+	if r.URL.Query().Get("published") == "true" {
+		status = models.ArticleStatusPublished
+	}
 
 	log.Info("Запрос списка статей",
 		zap.Int("limit", limit),
 		zap.Int("offset", offset),
 		zap.String("tag", tag),
-		zap.Bool("only_published", onlyPublished),
+		zap.String("status", status),
 	)
 
-	list, err := h.svc.GetAll(r.Context(), limit, offset, tag, onlyPublished)
+	list, err := h.svc.GetAll(r.Context(), limit, offset, tag, status)
 	if err != nil {
 		log.Error("Ошибка получения статей", zap.Error(err))
 		helpers.Error(w, http.StatusInternalServerError, "internal error")
@@ -129,6 +140,83 @@ func (h *ArticleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Info("Список статей получен", zap.Int("count", len(list)))
+	helpers.JSONConditional(w, r, list)
+}
+
+// Feed
+// @Summary     RSS-лента статей
+// @Tags        articles
+// @Produce     xml
+// @Success     200 {string} string "RSS 2.0"
+// @Failure     500 {object} helpers.Response
+// @Router      /api/articles/feed.rss [get]
+func (h *ArticleHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	list, err := h.svc.GetAll(r.Context(), feedLimit, 0, "", models.ArticleStatusPublished)
+	if err != nil {
+		log.Error("Ошибка получения статей для RSS", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	entries := make([]feedEntry, 0, len(list))
+	for _, a := range list {
+		date := a.CreatedAt
+		if a.PublishedAt != nil {
+			date = *a.PublishedAt
+		}
+		summary := a.BodyHTML
+		if a.Summary != nil && strings.TrimSpace(*a.Summary) != "" {
+			summary = *a.Summary
+		}
+		entries = append(entries, feedEntry{
+			Title:   a.Title,
+			Path:    fmt.Sprintf("/zavuch/%d", a.ID),
+			Date:    date,
+			Summary: summary,
+		})
+	}
+	// список упорядочен по created_at, в ленте — по дате публикации
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Date.After(entries[j].Date) })
+	writeFeed(w, r, h.cfg.Get().FrontendBaseURL(), "EduTalks — статьи", "Статьи портала EduTalks", "/zavuch", entries)
+}
+
+// AdminList
+// @Summary     Список статей для админки
+// @Description status: published|draft|scheduled (пусто — все); scheduled сортируются по времени публикации
+// @Tags        articles
+// @Produce     json
+// @Param       limit query int false "Лимит (по умолчанию 20)"
+// @Param       offset query int false "Смещение"
+// @Param       tag query string false "Тег"
+// @Param       status query string false "published|draft|scheduled"
+// @Success     200 {object} helpers.Response{data=[]models.Article}
+// @Failure     400 {object} helpers.Response
+// @Security    BearerAuth
+// @Router      /api/admin/articles [get]
+func (h *ArticleHandler) AdminList(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	limit := parseIntQuery(r, "limit", 20)
+	offset := parseIntQuery(r, "offset", 0)
+	tag := r.URL.Query().Get("tag")
+	status := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status")))
+	switch status {
+	case "", models.ArticleStatusPublished, models.ArticleStatusDraft, models.ArticleStatusScheduled:
+	default:
+		helpers.Error(w, http.StatusBadRequest, "status должен быть published|draft|scheduled")
+		return
+	}
+
+	list, err := h.svc.GetAll(r.Context(), limit, offset, tag, status)
+	if err != nil {
+		log.Error("Ошибка получения статей (admin)", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	log.Info("Список статей (admin) получен", zap.Int("count", len(list)), zap.String("status", status))
 	helpers.JSON(w, http.StatusOK, list)
 }
 
@@ -137,8 +225,8 @@ func (h *ArticleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Tags        articles
 // @Produce     json
 // @Param       id path int true "ID статьи"
-// @Success     200 {object} models.Article
-// @Failure     404 {object} map[string]string
+// @Success     200 {object} helpers.Response{data=models.Article}
+// @Failure     404 {object} helpers.Response
 // @Router      /api/articles/{id} [get]
 func (h *ArticleHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -154,6 +242,9 @@ func (h *ArticleHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		helpers.Error(w, http.StatusNotFound, "not found")
 		return
 	}
+	if a.IsPublished && h.views.Record(r, models.ContentKindArticle, aid) {
+		a.ViewCount++
+	}
 
 	log.Info("Статья получена", zap.Int64("id", aid))
 	helpers.JSON(w, http.StatusOK, a)
@@ -166,8 +257,9 @@ func (h *ArticleHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 // @Produce     json
 // @Param       id path int true "ID статьи"
 // @Param       body body models.CreateArticleRequest true "Данные статьи"
-// @Success     200 {object} models.Article
-// @Failure     400 {object} map[string]string
+// @Success     200 {object} helpers.Response{data=models.Article}
+// @Failure     400 {object} helpers.Response
+// @Security    BearerAuth
 // @Router      /api/admin/articles/{id} [patch]
 func (h *ArticleHandler) Update(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -181,6 +273,9 @@ func (h *ArticleHandler) Update(w http.ResponseWriter, r *http.Request) {
 		helpers.Error(w, http.StatusBadRequest, "invalid payload")
 		return
 	}
+	if !validRequest(w, r, &req) {
+		return
+	}
 
 	log.Info("Запрос на обновление статьи", zap.Int64("id", aid), zap.String("title", req.Title))
 
@@ -197,11 +292,13 @@ func (h *ArticleHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete
 // @Summary     Удалить статью
+// @Description Статья уходит в корзину (/api/admin/trash): её можно восстановить, пока она не удалена окончательно.
 // @Tags        articles
 // @Produce     json
 // @Param       id path int true "ID статьи"
 // @Success     204 {string} string "no content"
-// @Failure     404 {object} map[string]string
+// @Failure     404 {object} helpers.Response
+// @Security    BearerAuth
 // @Router      /api/admin/articles/{id} [delete]
 func (h *ArticleHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -228,9 +325,10 @@ func (h *ArticleHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Produce     json
 // @Param       id path int true "ID статьи"
 // @Param       body body SetPublishBody true "Флаг публикации"
-// @Success     200 {object} models.Article
-// @Failure     400 {object} map[string]string
-// @Failure     404 {object} map[string]string
+// @Success     200 {object} helpers.Response{data=models.Article}
+// @Failure     400 {object} helpers.Response
+// @Failure     404 {object} helpers.Response
+// @Security    BearerAuth
 // @Router      /api/admin/articles/{id}/publish [patch]
 func (h *ArticleHandler) SetPublish(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -238,7 +336,7 @@ func (h *ArticleHandler) SetPublish(w http.ResponseWriter, r *http.Request) {
 	aid, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
 	if err != nil || aid <= 0 {
 		log.Warn("Невалидный ID при SetPublish", zap.String("raw", mux.Vars(r)["id"]))
-		helpers.Error(w, http.StatusBadRequest, "invalid id")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "invalid id")
 		return
 	}
 
@@ -268,19 +366,10 @@ type SetPublishBody struct {
 
 // --- helpers ---
 
-type ctxKey string
-
-const userIDKey ctxKey = "user_id"
-
-func authorIDFromCtx(ctx interface{ Value(any) any }) *int64 {
-	if v := ctx.Value(userIDKey); v != nil {
-		switch x := v.(type) {
-		case int64:
-			return &x
-		case int:
-			y := int64(x)
-			return &y
-		}
+func authorIDFromCtx(ctx context.Context) *int64 {
+	if id, ok := middleware.UserIDFromContext(ctx); ok && id > 0 {
+		aid := int64(id)
+		return &aid
 	}
 	return nil
 }
@@ -331,6 +420,7 @@ func fillFromForm(req *models.CreateArticleRequest, r *http.Request) {
 	req.Title = r.FormValue("title")
 	req.Summary = r.FormValue("summary")
 	req.BodyHTML = r.FormValue("bodyHtml")
+	req.CoverImageURL = r.FormValue("coverImageUrl")
 
 	tags := r.Form["tags[]"]
 	if len(tags) == 0 {
@@ -348,6 +438,12 @@ func fillFromForm(req *models.CreateArticleRequest, r *http.Request) {
 	pub := firstNonEmpty(r.FormValue("publish"), r.FormValue("isPublished"))
 	pub = strings.ToLower(strings.TrimSpace(pub))
 	req.Publish = pub == "true" || pub == "1" || pub == "on"
+
+	if raw := strings.TrimSpace(r.FormValue("publishAt")); raw != "" {
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			req.PublishAt = &t
+		}
+	}
 }
 
 func firstNonEmpty(vals ...string) string {
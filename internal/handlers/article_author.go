@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	"edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// authorContentError — ответ на ошибку правки материала из кабинета автора; false — ошибки нет.
+func authorContentError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrNotContentOwner):
+		helpers.Fail(w, http.StatusForbidden, helpers.CodeContentNotOwner, err.Error())
+	case errors.Is(err, services.ErrPublishNotAllowed), errors.Is(err, services.ErrContentPublished),
+		errors.Is(err, services.ErrContentLocked):
+		helpers.Fail(w, http.StatusForbidden, helpers.CodeContentPublishDenied, err.Error())
+	case errors.Is(err, pgx.ErrNoRows):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeNotFound, "Статья не найдена")
+	case errors.Is(err, services.ErrDocumentNotFound):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeDocNotFound, "Документ не найден")
+	default:
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+	}
+	return true
+}
+
+// MyArticles
+// @Summary     Мои статьи (автор)
+// @Description Статьи текущего пользователя, включая черновики и запланированные. Доступно ролям author и admin.
+// @Tags        author
+// @Produce     json
+// @Param       limit query int false "Лимит"
+// @Param       offset query int false "Смещение"
+// @Param       status query string false "published|draft|scheduled"
+// @Success     200 {object} helpers.Response{data=[]models.Article}
+// @Failure     400 {object} helpers.Response
+// @Failure     403 {object} helpers.Response
+// @Security    BearerAuth
+// @Router      /api/author/articles [get]
+func (h *ArticleHandler) MyArticles(w http.ResponseWriter, r *http.Request) {
+	authorID := authorIDFromCtx(r.Context())
+	if authorID == nil {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	status := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status")))
+	switch status {
+	case "", models.ArticleStatusPublished, models.ArticleStatusDraft, models.ArticleStatusScheduled:
+	default:
+		helpers.Error(w, http.StatusBadRequest, "status должен быть published|draft|scheduled")
+		return
+	}
+
+	list, err := h.svc.ListOwn(r.Context(), *authorID, parseIntQuery(r, "limit", 20), parseIntQuery(r, "offset", 0), status)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// CreateMyArticle
+// @Summary     Создать статью (автор)
+// @Description Статья создаётся черновиком: publish, isPublished и publishAt запрещены — публикует администратор.
+// @Tags        author
+// @Accept      json,mpfd,x-www-form-urlencoded
+// @Produce     json
+// @Param       body body models.CreateArticleRequest true "Данные статьи"
+// @Success     201 {object} helpers.Response{data=models.Article}
+// @Failure     400 {object} helpers.Response
+// @Failure     403 {object} helpers.Response "Попытка опубликовать (CONTENT_PUBLISH_DENIED)"
+// @Security    BearerAuth
+// @Router      /api/author/articles [post]
+func (h *ArticleHandler) CreateMyArticle(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	authorID := authorIDFromCtx(r.Context())
+	if authorID == nil {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	req, err := readCreateArticleRequest(r)
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	if !validRequest(w, r, &req) {
+		return
+	}
+
+	article, err := h.svc.CreateOwn(r.Context(), *authorID, req)
+	if authorContentError(w, err) {
+		log.Warn("Статья автора не создана", zap.Int64("author_id", *authorID), zap.Error(err))
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, article)
+}
+
+// UpdateMyArticle
+// @Summary     Обновить свою статью (автор)
+// @Description Статус публикации и расписание не меняются; чужая статья — 403 CONTENT_NOT_OWNER.
+// @Tags        author
+// @Accept      json,mpfd,x-www-form-urlencoded
+// @Produce     json
+// @Param       id path int true "ID статьи"
+// @Param       body body models.CreateArticleRequest true "Данные статьи"
+// @Success     200 {object} helpers.Response{data=models.Article}
+// @Failure     400 {object} helpers.Response
+// @Failure     403 {object} helpers.Response
+// @Failure     404 {object} helpers.Response
+// @Security    BearerAuth
+// @Router      /api/author/articles/{id} [patch]
+func (h *ArticleHandler) UpdateMyArticle(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	authorID := authorIDFromCtx(r.Context())
+	if authorID == nil {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	aid, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || aid <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "invalid id")
+		return
+	}
+	req, err := readCreateArticleRequest(r)
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	if !validRequest(w, r, &req) {
+		return
+	}
+
+	article, err := h.svc.UpdateOwn(r.Context(), *authorID, aid, req)
+	if authorContentError(w, err) {
+		log.Warn("Статья автора не обновлена", zap.Int64("id", aid), zap.Int64("author_id", *authorID), zap.Error(err))
+		return
+	}
+	helpers.JSON(w, http.StatusOK, article)
+}
+
+// DeleteMyArticle
+// @Summary     Удалить свою статью (автор)
+// @Description Статья уходит в корзину. Опубликованную статью снимает только администратор.
+// @Tags        author
+// @Produce     json
+// @Param       id path int true "ID статьи"
+// @Success     204 {string} string "no content"
+// @Failure     403 {object} helpers.Response
+// @Failure     404 {object} helpers.Response
+// @Security    BearerAuth
+// @Router      /api/author/articles/{id} [delete]
+func (h *ArticleHandler) DeleteMyArticle(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	authorID := authorIDFromCtx(r.Context())
+	if authorID == nil {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	aid, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || aid <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "invalid id")
+		return
+	}
+
+	err = h.svc.DeleteOwn(r.Context(), *authorID, aid)
+	if authorContentError(w, err) {
+		log.Warn("Статья автора не удалена", zap.Int64("id", aid), zap.Int64("author_id", *authorID), zap.Error(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
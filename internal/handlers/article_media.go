@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type ArticleMediaHandler struct {
+	svc     *services.ArticleMediaService
+	scanner *services.FileScanService
+}
+
+func NewArticleMediaHandler(svc *services.ArticleMediaService, scanner *services.FileScanService) *ArticleMediaHandler {
+	return &ArticleMediaHandler{svc: svc, scanner: scanner}
+}
+
+// Upload godoc
+// @Summary Загрузить изображение в медиатеку статей
+// @Description Изображение (jpg, png, webp, gif, до 10 МБ) для обложки (coverImageUrl) или вставки в текст статьи. Файл проверяется антивирусом.
+// @Tags articles
+// @Security ApiKeyAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Изображение"
+// @Success 201 {object} helpers.Response{data=models.ArticleMedia}
+// @Failure 400 {object} helpers.Response
+// @Failure 413 {object} helpers.Response
+// @Router /api/admin/articles/media [post]
+func (h *ArticleMediaHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	img, ok := saveImageUpload(w, r, h.scanner, "articles")
+	if !ok {
+		return
+	}
+
+	m := &models.ArticleMedia{
+		URL:          img.URL,
+		Path:         img.RelPath,
+		OriginalName: img.OriginalName,
+		ContentType:  img.ContentType,
+		SizeBytes:    img.Size,
+	}
+	if adminID, ok := middleware.UserIDFromContext(r.Context()); ok && adminID != 0 {
+		m.UploadedBy = &adminID
+	}
+
+	out, err := h.svc.Add(r.Context(), m)
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка сохранения изображения в медиатеке", zap.Error(err))
+		removeUpload(r, img.RelPath)
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, out)
+}
+
+// List godoc
+// @Summary Медиатека статей
+// @Description Загруженные изображения, новые сверху; used_in — статьи, где изображение стоит обложкой или встречается в тексте либо черновике. unused=true — только неиспользуемые.
+// @Tags articles
+// @Security ApiKeyAuth
+// @Produce json
+// @Param unused query bool false "Только неиспользуемые"
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=ArticleMediaListResponse}
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/articles/media [get]
+func (h *ArticleMediaHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := pageParams(r)
+	unused, _ := strconv.ParseBool(r.URL.Query().Get("unused"))
+
+	items, total, err := h.svc.List(r.Context(), unused, pageSize, (page-1)*pageSize)
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка получения медиатеки", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить медиатеку")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, ArticleMediaListResponse{
+		Data:       items,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+	})
+}
+
+// Delete godoc
+// @Summary Удалить изображение из медиатеки
+// @Description Используемое изображение не удаляется (409 MEDIA_IN_USE, в details — запись с used_in), пока не передан force=true; ссылки в статьях при этом остаются битыми.
+// @Tags articles
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID изображения"
+// @Param force query bool false "Удалить, даже если используется"
+// @Success 200 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 409 {object} helpers.Response
+// @Router /api/admin/articles/media/{id} [delete]
+func (h *ArticleMediaHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id")
+		return
+	}
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+
+	m, err := h.svc.Delete(r.Context(), id, force)
+	switch {
+	case errors.Is(err, services.ErrMediaNotFound):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeNotFound, "Изображение не найдено")
+		return
+	case errors.Is(err, services.ErrMediaInUse):
+		helpers.FailWithDetails(w, http.StatusConflict, helpers.CodeMediaInUse, "Изображение используется в статьях", m)
+		return
+	case err != nil:
+		logger.WithCtx(r.Context()).Error("Ошибка удаления изображения из медиатеки", zap.Int64("id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+
+	removeUpload(r, m.Path)
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Изображение удалено"})
+}
+
+// removeUpload — удаляет файл из каталога uploads; отсутствующий файл не ошибка.
+func removeUpload(r *http.Request, relPath string) {
+	full := filepath.Join(uploadsRoot(), filepath.FromSlash(relPath))
+	if err := os.Remove(full); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.WithCtx(r.Context()).Warn("Не удалось удалить файл", zap.String("path", full), zap.Error(err))
+	}
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	"edutalks/internal/utils/helpers"
+)
+
+// ListRevisions
+// @Summary     История версий статьи
+// @Tags        articles
+// @Produce     json
+// @Param       id path int true "ID статьи"
+// @Success     200 {object} helpers.Response{data=[]models.ArticleRevision}
+// @Failure     404 {object} helpers.Response
+// @Security    BearerAuth
+// @Router      /api/admin/articles/{id}/revisions [get]
+func (h *ArticleHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	aid, ok := pathInt64(w, r, "id")
+	if !ok {
+		return
+	}
+
+	list, err := h.svc.ListRevisions(r.Context(), aid)
+	if err != nil {
+		h.revisionError(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// GetRevision
+// @Summary     Версия статьи
+// @Tags        articles
+// @Produce     json
+// @Param       id path int true "ID статьи"
+// @Param       rev path int true "ID ревизии"
+// @Success     200 {object} helpers.Response{data=models.ArticleRevision}
+// @Failure     404 {object} helpers.Response
+// @Security    BearerAuth
+// @Router      /api/admin/articles/{id}/revisions/{rev} [get]
+func (h *ArticleHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	aid, ok := pathInt64(w, r, "id")
+	if !ok {
+		return
+	}
+	rid, ok := pathInt64(w, r, "rev")
+	if !ok {
+		return
+	}
+
+	rev, err := h.svc.GetRevision(r.Context(), aid, rid)
+	if err != nil {
+		h.revisionError(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, rev)
+}
+
+// DiffRevision
+// @Summary     Отличия версии от текущей статьи
+// @Description changedFields — изменённые поля; body — построчный diff HTML (op: "=", "-" было в ревизии, "+" есть сейчас)
+// @Tags        articles
+// @Produce     json
+// @Param       id path int true "ID статьи"
+// @Param       rev path int true "ID ревизии"
+// @Success     200 {object} helpers.Response{data=models.ArticleRevisionDiff}
+// @Failure     404 {object} helpers.Response
+// @Security    BearerAuth
+// @Router      /api/admin/articles/{id}/revisions/{rev}/diff [get]
+func (h *ArticleHandler) DiffRevision(w http.ResponseWriter, r *http.Request) {
+	aid, ok := pathInt64(w, r, "id")
+	if !ok {
+		return
+	}
+	rid, ok := pathInt64(w, r, "rev")
+	if !ok {
+		return
+	}
+
+	d, err := h.svc.DiffRevision(r.Context(), aid, rid)
+	if err != nil {
+		h.revisionError(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, d)
+}
+
+// CompareRevisions
+// @Summary     Сравнение двух версий статьи
+// @Description Для согласования правок: changedFields — изменённые поля; nodes — узлы тела по порядку
+// @Description (op: "=" без изменений, "-" удалён из {a}, "+" вставлен в {b}); html — разметка с <del>/<ins>
+// @Tags        articles
+// @Produce     json
+// @Param       id path int true "ID статьи"
+// @Param       a path int true "ID исходной ревизии"
+// @Param       b path int true "ID ревизии для сравнения"
+// @Success     200 {object} helpers.Response{data=models.ArticleRevisionCompare}
+// @Failure     404 {object} helpers.Response
+// @Security    BearerAuth
+// @Router      /api/admin/articles/{id}/revisions/{a}/diff/{b} [get]
+func (h *ArticleHandler) CompareRevisions(w http.ResponseWriter, r *http.Request) {
+	aid, ok := pathInt64(w, r, "id")
+	if !ok {
+		return
+	}
+	from, ok := pathInt64(w, r, "a")
+	if !ok {
+		return
+	}
+	to, ok := pathInt64(w, r, "b")
+	if !ok {
+		return
+	}
+
+	d, err := h.svc.CompareRevisions(r.Context(), aid, from, to)
+	if err != nil {
+		h.revisionError(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, d)
+}
+
+// RestoreRevision
+// @Summary     Восстановить статью из версии
+// @Description Текущая версия сохраняется в историю; статус публикации не меняется
+// @Tags        articles
+// @Produce     json
+// @Param       id path int true "ID статьи"
+// @Param       rev path int true "ID ревизии"
+// @Success     200 {object} helpers.Response{data=models.Article}
+// @Failure     404 {object} helpers.Response
+// @Security    BearerAuth
+// @Router      /api/admin/articles/{id}/revisions/{rev}/restore [post]
+func (h *ArticleHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	aid, ok := pathInt64(w, r, "id")
+	if !ok {
+		return
+	}
+	rid, ok := pathInt64(w, r, "rev")
+	if !ok {
+		return
+	}
+
+	a, err := h.svc.RestoreRevision(r.Context(), aid, rid)
+	if err != nil {
+		h.revisionError(w, r, err)
+		return
+	}
+	logger.WithCtx(r.Context()).Info("Статья восстановлена из ревизии", zap.Int64("id", aid), zap.Int64("revision_id", rid))
+	helpers.JSON(w, http.StatusOK, a)
+}
+
+// SaveDraft
+// @Summary     Автосохранение черновика статьи
+// @Description Не меняет опубликованную версию; черновик удаляется при обычном сохранении статьи
+// @Tags        articles
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "ID статьи"
+// @Param       body body models.ArticleDraftRequest true "Черновик"
+// @Success     200 {object} helpers.Response{data=models.ArticleDraft}
+// @Failure     400 {object} helpers.Response
+// @Failure     404 {object} helpers.Response
+// @Security    BearerAuth
+// @Router      /api/admin/articles/{id}/draft [patch]
+func (h *ArticleHandler) SaveDraft(w http.ResponseWriter, r *http.Request) {
+	aid, ok := pathInt64(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req models.ArticleDraftRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	d, err := h.svc.SaveDraft(r.Context(), aid, req)
+	if err != nil {
+		h.revisionError(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, d)
+}
+
+// GetDraft
+// @Summary     Черновик статьи
+// @Tags        articles
+// @Produce     json
+// @Param       id path int true "ID статьи"
+// @Success     200 {object} helpers.Response{data=models.ArticleDraft}
+// @Failure     404 {object} helpers.Response
+// @Security    BearerAuth
+// @Router      /api/admin/articles/{id}/draft [get]
+func (h *ArticleHandler) GetDraft(w http.ResponseWriter, r *http.Request) {
+	aid, ok := pathInt64(w, r, "id")
+	if !ok {
+		return
+	}
+
+	d, err := h.svc.GetDraft(r.Context(), aid)
+	if err != nil {
+		h.revisionError(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, d)
+}
+
+func (h *ArticleHandler) revisionError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrArticleNotFound),
+		errors.Is(err, services.ErrRevisionNotFound),
+		errors.Is(err, services.ErrDraftNotFound):
+		helpers.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrDraftTitleTooLong):
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+	default:
+		logger.WithCtx(r.Context()).Error("Ошибка работы с версиями статьи", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+func pathInt64(w http.ResponseWriter, r *http.Request, key string) (int64, bool) {
+	v, err := strconv.ParseInt(mux.Vars(r)[key], 10, 64)
+	if err != nil || v <= 0 {
+		helpers.Error(w, http.StatusBadRequest, "invalid "+key)
+		return 0, false
+	}
+	return v, true
+}
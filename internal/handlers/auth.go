@@ -7,8 +7,10 @@ import (
 	"edutalks/internal/middleware"
 	"edutalks/internal/models"
 	"edutalks/internal/services"
+	"edutalks/internal/utils/clientip"
 	helpers "edutalks/internal/utils/helpers"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -24,23 +26,27 @@ type AuthHandler struct {
 	authService       *services.AuthService
 	emailService      *services.EmailService
 	emailTokenService *services.EmailTokenService
+	cfg               *config.Holder
 }
 
-func NewAuthHandler(authService *services.AuthService, emailService *services.EmailService, emailTokenService *services.EmailTokenService) *AuthHandler {
+func NewAuthHandler(authService *services.AuthService, emailService *services.EmailService, emailTokenService *services.EmailTokenService, cfg *config.Holder) *AuthHandler {
 	return &AuthHandler{
 		authService:       authService,
 		emailService:      emailService,
 		emailTokenService: emailTokenService,
+		cfg:               cfg,
 	}
 }
 
 type registerRequest struct {
-	Username string `json:"username"`
-	FullName string `json:"full_name"`
-	Phone    string `json:"phone"`
-	Email    string `json:"email"`
-	Address  string `json:"address"`
-	Password string `json:"password"`
+	Username string `json:"username" validate:"required,min=3,max=50,username"`
+	FullName string `json:"full_name" validate:"required,max=200"`
+	Phone    string `json:"phone" validate:"phone"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Address  string `json:"address" validate:"max=500"`
+	Password string `json:"password" validate:"required,password"`
+	// Locale — язык писем (ru | en); если не задан — из Accept-Language
+	Locale string `json:"locale,omitempty" example:"ru" validate:"oneof=ru en"`
 }
 
 type loginRequest struct {
@@ -62,12 +68,22 @@ type subscriptionRequest struct {
 }
 
 type notifyRequest struct {
-	Subject string `json:"subject"`
-	Message string `json:"message"`
+	Subject string `json:"subject" validate:"required,max=200"`
+	Message string `json:"message" validate:"required"` // HTML; подстановки {{full_name}}, {{username}}, {{email}}
+	// Template — simple (по умолчанию) или news: письмо с кнопкой на url
+	Template string                 `json:"template,omitempty" validate:"oneof=simple news"`
+	URL      string                 `json:"url,omitempty" validate:"url"`
+	Audience *models.NotifyAudience `json:"audience,omitempty"`
+	// DryRun — не отправлять, только вернуть число адресатов
+	DryRun bool `json:"dry_run,omitempty"`
 }
 
 type emailSubscriptionRequest struct {
-	Subscribe bool `json:"subscribe"`
+	Subscribe *bool `json:"subscribe,omitempty"`
+	// Frequency — immediate: письма о новых материалах сразу; daily / weekly — сводкой
+	Frequency *string `json:"frequency,omitempty" example:"weekly" validate:"oneof=immediate daily weekly"`
+	// LoginAlerts — письмо о входе с нового устройства (IP + браузер)
+	LoginAlerts *bool `json:"login_alerts,omitempty"`
 }
 
 // Register godoc
@@ -83,11 +99,11 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
 
 	var req registerRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Warn("Невалидный JSON в Register", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "Невалидный JSON")
+	if !decodeValid(w, r, &req) {
 		return
 	}
+	req.Username = strings.TrimSpace(req.Username)
+	req.Email = strings.TrimSpace(req.Email)
 
 	log.Info("Регистрация пользователя",
 		zap.String("username", strings.TrimSpace(req.Username)),
@@ -101,21 +117,34 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		Phone:    req.Phone,
 		Email:    req.Email,
 		Address:  req.Address,
+		Locale:   req.Locale,
+	}
+	if user.Locale == "" {
+		user.Locale = helpers.LocaleFromAcceptLanguage(r.Header.Get("Accept-Language"))
 	}
 
 	if err := h.authService.RegisterUser(r.Context(), user, req.Password); err != nil {
 		log.Error("Ошибка регистрации пользователя", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, err.Error())
+		switch {
+		case errors.Is(err, services.ErrUsernameTaken):
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeAuthUsernameTaken, err.Error())
+		case errors.Is(err, services.ErrEmailTaken):
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeAuthEmailTaken, err.Error())
+		default:
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+		}
 		return
 	}
 
 	// Антиспам по письмам с подтверждением
-	if lastToken, err := h.emailTokenService.GetLastTokenByUserID(r.Context(), user.ID); err == nil && time.Since(lastToken.CreatedAt) < 5*time.Minute {
+	cooldown := h.emailTokenService.ResendCooldown()
+	if lastToken, err := h.emailTokenService.GetLastTokenByUserID(r.Context(), user.ID); err == nil && time.Since(lastToken.CreatedAt) < cooldown {
 		log.Warn("Слишком частая отправка письма подтверждения",
 			zap.Int("user_id", user.ID),
-			zap.Duration("remaining", 5*time.Minute-time.Since(lastToken.CreatedAt)),
+			zap.Duration("remaining", cooldown-time.Since(lastToken.CreatedAt)),
 		)
-		helpers.Error(w, http.StatusTooManyRequests, "Повторная отправка письма возможна через 5 минут")
+		helpers.Error(w, http.StatusTooManyRequests,
+			fmt.Sprintf("Повторная отправка письма возможна через %d секунд", int((cooldown-time.Since(lastToken.CreatedAt)).Seconds())))
 		return
 	}
 
@@ -144,7 +173,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param input body loginRequest true "Данные для входа"
-// @Success 200 {object} loginResponse
+// @Success 200 {object} helpers.Response{data=loginResponse}
 // @Failure 401 {string} string "Неверный логин или пароль"
 // @Router /api/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
@@ -152,7 +181,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	var req loginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		helpers.Error(w, http.StatusBadRequest, "Невалидный JSON")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "Невалидный JSON")
 		return
 	}
 
@@ -165,14 +194,25 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cfg, _ := config.LoadConfig()
+	cfg := h.cfg.Get()
 	accessTTL, _ := time.ParseDuration(cfg.AccessTokenTTL)
 
+	client := models.SessionClient{
+		UserAgent: sessionUserAgent(r),
+		IP:        clientip.FromRequest(r),
+	}
 	access, user, err := h.authService.LoginUserByIdentifier(
-		r.Context(), identifier, req.Password, cfg.JWTSecret, accessTTL,
+		r.Context(), identifier, req.Password, cfg.JWTSecret, accessTTL, client,
 	)
 	if err != nil {
-		helpers.Error(w, http.StatusUnauthorized, err.Error())
+		switch {
+		case errors.Is(err, services.ErrLoginUserNotFound), errors.Is(err, services.ErrLoginPassword):
+			helpers.Fail(w, http.StatusUnauthorized, helpers.CodeAuthInvalidCredentials, err.Error())
+		case errors.Is(err, services.ErrAccountDeletionPending):
+			helpers.Fail(w, http.StatusUnauthorized, helpers.CodeAuthDeletionPending, err.Error())
+		default:
+			helpers.Error(w, http.StatusUnauthorized, err.Error())
+		}
 		return
 	}
 
@@ -189,7 +229,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 // @Summary Получить данные профиля
 // @Tags profile
 // @Security ApiKeyAuth
-// @Success 200 {object} models.UserProfileResponse "Профиль пользователя"
+// @Success 200 {object} helpers.Response{data=models.UserProfileResponse} "Профиль пользователя"
 // @Failure 401 {string} string "Нет доступа"
 // @Failure 404 {string} string "Пользователь не найден"
 // @Router /api/profile [get]
@@ -206,7 +246,7 @@ func (h *AuthHandler) Protected(w http.ResponseWriter, r *http.Request) {
 	user, err := h.authService.GetUserByID(r.Context(), userID)
 	if err != nil {
 		log.Warn("Пользователь не найден (profile)", zap.Int("user_id", userID))
-		helpers.Error(w, http.StatusNotFound, "Пользователь не найден")
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeUserNotFound, "Пользователь не найден")
 		return
 	}
 
@@ -228,6 +268,11 @@ func (h *AuthHandler) Protected(w http.ResponseWriter, r *http.Request) {
 		IsSubscriptionActive:  isActive,
 		EmailSubscription:     user.EmailSubscription,
 		EmailVerified:         user.EmailVerified,
+		PhoneVerified:         user.PhoneVerified,
+		LoginAlerts:           user.LoginAlerts,
+		Locale:                user.Locale,
+		EmailFrequency:        user.EmailFrequency,
+		AvatarURL:             models.AvatarURL(user.ID, user.AvatarKey, models.AvatarSizes[0]),
 	}
 
 	log.Info("Профиль отдан", zap.Int("user_id", userID))
@@ -249,12 +294,12 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cfg, _ := config.LoadConfig()
+	secret := h.cfg.Get().JWTSecret
 	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
 	claims := jwt.MapClaims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(cfg.JWTSecret), nil
+		return []byte(secret), nil
 	})
 	if err != nil || !token.Valid {
 		helpers.Error(w, http.StatusUnauthorized, "Невалидный токен")
@@ -263,8 +308,10 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 
 	expUnix, _ := claims["exp"].(float64)
 	exp := time.Unix(int64(expUnix), 0)
+	userID, _ := claims["user_id"].(float64)
+	sid, _ := claims["sid"].(float64)
 
-	if err := h.authService.Logout(r.Context(), tokenString, exp); err != nil {
+	if err := h.authService.Logout(r.Context(), int(userID), int64(sid), tokenString, exp); err != nil {
 		log.Error("Ошибка при logout", zap.Error(err))
 		helpers.Error(w, http.StatusInternalServerError, "Ошибка при выходе")
 		return
@@ -296,7 +343,7 @@ func (h *AuthHandler) AdminOnly(w http.ResponseWriter, r *http.Request) {
 // @Param q query string false "Поиск по ФИО или email"
 // @Param role query string false "Фильтр по роли (admin/user/...)"
 // @Param has_subscription query string false "true|false — фильтр по подписке"
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} helpers.Response{data=PaginatedUsersResponse}
 // @Router /api/admin/users [get]
 func (h *AuthHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -348,14 +395,12 @@ func (h *AuthHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Info("Список пользователей получен", zap.Int("count", len(users)), zap.Int("total", total))
-	helpers.JSON(w, http.StatusOK, map[string]interface{}{
-		"data":             users,
-		"total":            total,
-		"page":             page,
-		"page_size":        pageSize,
-		"q":                q,
-		"role":             rolePtr,
-		"has_subscription": func() *bool { return hasSubPtr }(),
+	helpers.JSON(w, http.StatusOK, PaginatedUsersResponse{
+		Data:            users,
+		Pagination:      Pagination{Total: total, Page: page, PageSize: pageSize},
+		Q:               q,
+		Role:            rolePtr,
+		HasSubscription: hasSubPtr,
 	})
 }
 
@@ -365,7 +410,7 @@ func (h *AuthHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 // @Security ApiKeyAuth
 // @Produce json
 // @Param id path int true "ID пользователя"
-// @Success 200 {object} models.User
+// @Success 200 {object} helpers.Response{data=models.User}
 // @Failure 400 {string} string "Невалидный ID"
 // @Failure 404 {string} string "Пользователь не найден"
 // @Router /api/admin/users/{id} [get]
@@ -377,14 +422,14 @@ func (h *AuthHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		log.Warn("Невалидный ID при получении пользователя", zap.String("id", idStr))
-		helpers.Error(w, http.StatusBadRequest, "Невалидный ID")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Невалидный ID")
 		return
 	}
 
 	user, err := h.authService.GetUserByID(r.Context(), id)
 	if err != nil {
 		log.Warn("Пользователь не найден", zap.Int("user_id", id))
-		helpers.Error(w, http.StatusNotFound, "Пользователь не найден")
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeUserNotFound, "Пользователь не найден")
 		return
 	}
 
@@ -411,18 +456,25 @@ func (h *AuthHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
 		log.Warn("Невалидный ID при обновлении пользователя", zap.Any("vars", vars))
-		helpers.Error(w, http.StatusBadRequest, "Невалидный ID")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Невалидный ID")
 		return
 	}
 
 	var input models.UpdateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		log.Warn("Невалидный JSON при обновлении пользователя", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "Невалидный JSON")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "Невалидный JSON")
+		return
+	}
+	if !validRequest(w, r, &input) {
 		return
 	}
 
 	if err := h.authService.UpdateUser(r.Context(), id, &input); err != nil {
+		if errors.Is(err, services.ErrUnsupportedLocale) {
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeLocaleUnsupported, "Неподдерживаемый язык: допустимы ru, en")
+			return
+		}
 		log.Error("Ошибка при обновлении пользователя", zap.Error(err), zap.Int("user_id", id))
 		helpers.Error(w, http.StatusInternalServerError, "Ошибка при обновлении")
 		return
@@ -440,7 +492,7 @@ func (h *AuthHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param input body setSubscriptionRequest true "Действие над подпиской"
-// @Success 200 {object} map[string]interface{} "Текущее состояние подписки"
+// @Success 200 {object} helpers.Response{data=SubscriptionStateResponse} "Текущее состояние подписки"
 // @Failure 400 {string} string "Ошибка запроса"
 // @Router /api/admin/users/{id}/subscription [patch]
 func (h *AuthHandler) SetSubscription(w http.ResponseWriter, r *http.Request) {
@@ -450,14 +502,12 @@ func (h *AuthHandler) SetSubscription(w http.ResponseWriter, r *http.Request) {
 	userID, err := strconv.Atoi(idStr)
 	if err != nil {
 		log.Warn("Неверный ID при обновлении подписки", zap.String("id", idStr))
-		helpers.Error(w, http.StatusBadRequest, "Неверный ID")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Неверный ID")
 		return
 	}
 
 	var req setSubscriptionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Warn("Невалидный JSON при обновлении подписки", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "Невалидный JSON")
+	if !decodeValid(w, r, &req) {
 		return
 	}
 
@@ -512,67 +562,120 @@ func (h *AuthHandler) SetSubscription(w http.ResponseWriter, r *http.Request) {
 	isActive := u.HasSubscription && u.SubscriptionExpiresAt != nil && u.SubscriptionExpiresAt.After(now)
 
 	log.Info("Подписка обновлена", zap.Int("user_id", userID), zap.Bool("has_subscription", u.HasSubscription))
-	helpers.JSON(w, http.StatusOK, map[string]interface{}{
-		"user_id":                 u.ID,
-		"has_subscription":        u.HasSubscription,
-		"subscription_expires_at": u.SubscriptionExpiresAt,
-		"is_active":               isActive,
+	helpers.JSON(w, http.StatusOK, SubscriptionStateResponse{
+		UserID:                u.ID,
+		HasSubscription:       u.HasSubscription,
+		SubscriptionExpiresAt: u.SubscriptionExpiresAt,
+		IsActive:              isActive,
 	})
 }
 
+// BulkUsers godoc
+// @Summary Массовая операция над пользователями
+// @Description Выдать/продлить/отключить подписку, сменить роль или удалить пользователей по списку ID или фильтру.
+// @Description Выполняется в одной транзакции; all_or_nothing=true откатывает всё при первой ошибке.
+// @Tags admin-users
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body models.UserBulkRequest true "Действие и пользователи"
+// @Success 200 {object} helpers.Response{data=models.UserBulkReport} "Результат по каждому пользователю"
+// @Failure 400 {object} helpers.Response "Ошибка запроса"
+// @Router /api/admin/users/bulk [post]
+func (h *AuthHandler) BulkUsers(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	var req models.UserBulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Невалидный JSON массовой операции", zap.Error(err))
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "Невалидный JSON")
+		return
+	}
+
+	var dur time.Duration
+	action := strings.ToLower(strings.TrimSpace(req.Action))
+	if action == models.UserBulkGrant || action == models.UserBulkExtend {
+		var err error
+		if dur, err = parseHumanDuration(req.Duration); err != nil || dur <= 0 {
+			log.Warn("Невалидный duration массовой операции", zap.String("duration", req.Duration))
+			helpers.Error(w, http.StatusBadRequest, "Неверный формат duration")
+			return
+		}
+	}
+
+	actorID, _ := middleware.UserIDFromContext(r.Context())
+	report, err := h.authService.BulkUsers(r.Context(), actorID, &req, dur)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrBulkBadAction), errors.Is(err, services.ErrBulkNoTargets),
+			errors.Is(err, services.ErrBulkTooMany), errors.Is(err, services.ErrBulkBadRole):
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			log.Error("Ошибка массовой операции над пользователями", zap.Error(err))
+			helpers.Error(w, http.StatusInternalServerError, "Ошибка массовой операции")
+		}
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, report)
+}
+
 type setSubscriptionRequest struct {
-	Action   string `json:"action"`             // grant | extend | revoke
-	Duration string `json:"duration,omitempty"` // monthly | halfyear | yearly | "30d" | "72h" | ...
+	Action   string `json:"action" validate:"oneof=grant extend revoke"` // grant | extend | revoke
+	Duration string `json:"duration,omitempty"`                          // monthly | halfyear | yearly | "30d" | "72h" | ...
 }
 
 // NotifySubscribers godoc
-// @Summary Отправить письмо всем подписанным
+// @Summary Отправить письмо подписчикам
+// @Description Письмо подписанным на рассылку, при необходимости только части из них (audience: роль, подписка, отслеживаемые вкладка или раздел, даты регистрации). В subject и message подставляются {{full_name}}, {{username}} и {{email}} получателя. dry_run=true — ничего не отправляется, возвращается число адресатов.
 // @Tags admin-notify
 // @Security ApiKeyAuth
 // @Accept json
-// @Param input body notifyRequest true "Сообщение"
-// @Success 200 {string} string "Письма отправлены"
-// @Failure 400 {string} string "Ошибка запроса"
-// @Failure 500 {string} string "Ошибка отправки"
+// @Produce json
+// @Param input body notifyRequest true "Сообщение и аудитория"
+// @Success 200 {object} helpers.Response{data=NotifyResultResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
 // @Router /api/admin/notify [post]
 func (h *AuthHandler) NotifySubscribers(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
 
 	var req notifyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Warn("Невалидный JSON в NotifySubscribers", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "Невалидный JSON")
+	if !decodeValid(w, r, &req) {
 		return
 	}
+	var audience models.NotifyAudience
+	if req.Audience != nil {
+		audience = *req.Audience
+	}
 
-	emails, err := h.authService.GetSubscribedEmails(r.Context())
-	if err != nil {
-		log.Error("Не удалось получить список подписчиков", zap.Error(err))
+	msg := models.NotifyMessage{Subject: req.Subject, Message: req.Message, Template: req.Template, URL: req.URL}
+	count, err := h.authService.NotifySubscribers(r.Context(), msg, audience, req.DryRun)
+	switch {
+	case errors.Is(err, services.ErrNotifyNewsURL), errors.Is(err, services.ErrNotifyBadTemplate), errors.Is(err, services.ErrNotifyBadDate):
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeBadRequest, err.Error())
+		return
+	case err != nil:
+		log.Error("Не удалось выполнить рассылку", zap.Error(err))
 		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить список подписчиков")
 		return
 	}
 
-	if len(emails) == 0 {
-		log.Info("Нет подписчиков для рассылки")
-		helpers.JSON(w, http.StatusOK, map[string]string{"message": "Нет подписчиков"})
+	if req.DryRun {
+		helpers.JSON(w, http.StatusOK, NotifyResultResponse{DryRun: true, Recipients: count})
 		return
 	}
-
-	for _, email := range emails {
-		html := helpers.BuildSimpleHTML(req.Subject, req.Message)
-		services.EmailQueue <- services.EmailJob{
-			To:      []string{email},
-			Subject: req.Subject,
-			Body:    html,
-			IsHTML:  true,
-		}
+	if count == 0 {
+		helpers.JSON(w, http.StatusOK, NotifyResultResponse{Message: "Нет подписчиков", Recipients: 0})
+		return
 	}
-	log.Info("Письма поставлены в очередь", zap.Int("count", len(emails)))
-	helpers.JSON(w, http.StatusOK, "Письма отправлены")
+	log.Info("Письма поставлены в очередь", zap.Int("count", count))
+	helpers.JSON(w, http.StatusOK, NotifyResultResponse{Message: "Письма отправлены", Recipients: count})
 }
 
 // EmailSubscribe godoc
 // @Summary Подписка или отписка от email-уведомлений
+// @Description subscribe — получать ли письма о новых материалах; frequency — как: immediate (сразу), daily или weekly (сводкой раз в день / в неделю); login_alerts — письмо о входе с нового устройства. Поле, которого нет в запросе, не меняется.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -585,9 +688,11 @@ func (h *AuthHandler) EmailSubscribe(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
 
 	var req emailSubscriptionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Warn("Невалидный JSON в EmailSubscribe", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "Невалидный JSON")
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	if req.Subscribe == nil && req.Frequency == nil && req.LoginAlerts == nil {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeBadRequest, "Укажите subscribe, frequency или login_alerts")
 		return
 	}
 
@@ -598,24 +703,39 @@ func (h *AuthHandler) EmailSubscribe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.authService.UpdateEmailSubscription(r.Context(), userID, req.Subscribe); err != nil {
-		log.Error("Не удалось обновить статус email-подписки", zap.Error(err), zap.Int("user_id", userID))
-		helpers.Error(w, http.StatusInternalServerError, "Не удалось обновить статус подписки")
-		return
+	if req.Subscribe != nil {
+		if err := h.authService.UpdateEmailSubscription(r.Context(), userID, *req.Subscribe); err != nil {
+			log.Error("Не удалось обновить статус email-подписки", zap.Error(err), zap.Int("user_id", userID))
+			helpers.Error(w, http.StatusInternalServerError, "Не удалось обновить статус подписки")
+			return
+		}
+	}
+	if req.Frequency != nil {
+		if err := h.authService.UpdateEmailFrequency(r.Context(), userID, *req.Frequency); err != nil {
+			log.Error("Не удалось обновить частоту писем", zap.Error(err), zap.Int("user_id", userID))
+			helpers.Error(w, http.StatusInternalServerError, "Не удалось обновить статус подписки")
+			return
+		}
+	}
+	if req.LoginAlerts != nil {
+		if err := h.authService.UpdateLoginAlerts(r.Context(), userID, *req.LoginAlerts); err != nil {
+			log.Error("Не удалось обновить уведомления о входе", zap.Error(err), zap.Int("user_id", userID))
+			helpers.Error(w, http.StatusInternalServerError, "Не удалось обновить статус подписки")
+			return
+		}
 	}
 
-	log.Info("Статус email-подписки обновлён", zap.Int("user_id", userID), zap.Bool("subscribe", req.Subscribe))
-	helpers.JSON(w, http.StatusOK, map[string]string{"message": "Статус подписки обновлён"})
+	log.Info("Статус email-подписки обновлён", zap.Int("user_id", userID), zap.Boolp("subscribe", req.Subscribe), zap.Stringp("frequency", req.Frequency), zap.Boolp("login_alerts", req.LoginAlerts))
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Статус подписки обновлён"})
 }
 
 func (h *AuthHandler) SendVerificationEmail(ctx context.Context, user *models.User, token string) error {
-	cfg, _ := config.LoadConfig()
-	verifyLink := fmt.Sprintf("%s/verify-email?token=%s", cfg.SiteURL, token)
-	htmlBody := helpers.BuildVerificationHTML(user.FullName, verifyLink)
+	verifyLink, appLink := h.emailTokenService.VerificationLinks(token)
+	htmlBody := helpers.BuildVerificationHTML(user.Locale, user.FullName, verifyLink, appLink, h.emailTokenService.TTLLabel(user.Locale))
 
 	services.EmailQueue <- services.EmailJob{
 		To:      []string{user.Email},
-		Subject: "Подтверждение регистрации",
+		Subject: helpers.EmailSubject(user.Locale, helpers.MailVerification),
 		Body:    htmlBody,
 		IsHTML:  true,
 	}
@@ -629,7 +749,7 @@ func (h *AuthHandler) SendVerificationEmail(ctx context.Context, user *models.Us
 // @Description Удаляет пользователя по его ID
 // @Tags Users
 // @Param id path int true "ID пользователя"
-// @Success 200 {object} map[string]string "Пользователь успешно удалён"
+// @Success 200 {object} helpers.Response{data=MessageResponse} "Пользователь успешно удалён"
 // @Failure 400 {object} string "Некорректный id пользователя"
 // @Failure 404 {object} string "Пользователь не найден"
 // @Failure 500 {object} string "Ошибка при удалении пользователя"
@@ -643,7 +763,7 @@ func (h *AuthHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		log.Warn("Некорректный id пользователя в DeleteUser", zap.String("id", idStr))
-		helpers.Error(w, http.StatusBadRequest, "Некорректный id пользователя")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id пользователя")
 		return
 	}
 
@@ -651,7 +771,7 @@ func (h *AuthHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 
 	if _, err := h.authService.GetUserByID(r.Context(), id); err != nil {
 		log.Warn("Пользователь не найден для удаления", zap.Int("user_id", id))
-		helpers.Error(w, http.StatusNotFound, "Пользователь не найден")
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeUserNotFound, "Пользователь не найден")
 		return
 	}
 
@@ -662,7 +782,7 @@ func (h *AuthHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Info("Пользователь успешно удалён", zap.Int("user_id", id))
-	helpers.JSON(w, http.StatusOK, map[string]string{"message": "Пользователь удалён"})
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Пользователь удалён"})
 }
 
 // GetSystemStats godoc
@@ -670,7 +790,7 @@ func (h *AuthHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 // @Tags admin-users
 // @Security ApiKeyAuth
 // @Produce json
-// @Success 200 {object} models.SystemStats
+// @Success 200 {object} helpers.Response{data=models.SystemStats}
 // @Router /api/admin/stats [get]
 func (h *AuthHandler) GetSystemStats(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
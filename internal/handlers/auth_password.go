@@ -40,8 +40,8 @@ type forgotReq struct {
 // @Accept json
 // @Produce json
 // @Param input body forgotReq true "Email пользователя"
-// @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 400 {object} helpers.Response
 // @Router /api/password/forgot [post]
 func (h *PasswordHandler) Forgot(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -61,7 +61,7 @@ func (h *PasswordHandler) Forgot(w http.ResponseWriter, r *http.Request) {
 		log.Info("Запрошено восстановление пароля", zap.String("email_masked", maskEmail(req.Email)))
 	}
 
-	helpers.JSON(w, http.StatusOK, map[string]any{"message": "If the email exists, a reset link has been sent."})
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "If the email exists, a reset link has been sent."})
 }
 
 type resetReq struct {
@@ -76,8 +76,8 @@ type resetReq struct {
 // @Accept json
 // @Produce json
 // @Param input body resetReq true "Токен и новый пароль"
-// @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 400 {object} helpers.Response
 // @Router /api/password/reset [post]
 func (h *PasswordHandler) Reset(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -92,12 +92,12 @@ func (h *PasswordHandler) Reset(w http.ResponseWriter, r *http.Request) {
 	if err := h.svc.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
 		// Ошибки токена/валидации — это 400
 		log.Warn("Не удалось сбросить пароль по токену", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "invalid token or password")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeAuthLinkInvalid, "invalid token or password")
 		return
 	}
 
 	log.Info("Пароль успешно сброшен")
-	helpers.JSON(w, http.StatusOK, map[string]string{"message": "Password has been reset."})
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Password has been reset."})
 }
 
 type changeReq struct {
@@ -113,9 +113,9 @@ type changeReq struct {
 // @Accept json
 // @Produce json
 // @Param input body changeReq true "Старый и новый пароль"
-// @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 401 {object} helpers.Response
 // @Router /api/password/change [post]
 func (h *PasswordHandler) Change(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -137,7 +137,7 @@ func (h *PasswordHandler) Change(w http.ResponseWriter, r *http.Request) {
 	u, err := h.userRepo.GetUserByID(r.Context(), userID)
 	if err != nil {
 		log.Warn("Пользователь не найден при смене пароля", zap.Int("user_id", userID))
-		helpers.Error(w, http.StatusUnauthorized, "user not found")
+		helpers.Fail(w, http.StatusUnauthorized, helpers.CodeUserNotFound, "user not found")
 		return
 	}
 
@@ -149,5 +149,5 @@ func (h *PasswordHandler) Change(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Info("Пароль изменён", zap.Int("user_id", userID))
-	helpers.JSON(w, http.StatusOK, map[string]string{"message": "Password changed."})
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Password changed."})
 }
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+	"edutalks/internal/utils/imaging"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type AvatarHandler struct {
+	auth *services.AuthService
+}
+
+func NewAvatarHandler(auth *services.AuthService) *AvatarHandler {
+	return &AvatarHandler{auth: auth}
+}
+
+// UploadAvatar godoc
+// @Summary Загрузить аватар
+// @Tags profile
+// @Security ApiKeyAuth
+// @Accept mpfd
+// @Produce json
+// @Description Изображение (jpg/png/gif, до 10 МБ и 25 Мп) обрезается по центру до квадрата и сохраняется в размерах 256, 128 и 64 px (JPEG). Прежний аватар удаляется.
+// @Param file formData file true "Изображение"
+// @Success 200 {object} helpers.Response{data=AvatarResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 401 {object} helpers.Response
+// @Failure 413 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/profile/avatar [post]
+func (h *AvatarHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImageUpload)
+	if err := r.ParseMultipartForm(maxImageUpload); err != nil {
+		log.Warn("Аватар: ошибка разбора multipart", zap.Error(err))
+		helpers.Error(w, http.StatusRequestEntityTooLarge, "файл слишком большой (макс 10 МБ)")
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "поле file обязательно")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		log.Error("Аватар: чтение файла не удалось", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "ошибка чтения файла")
+		return
+	}
+	src, err := imaging.Decode(data)
+	if err != nil {
+		log.Warn("Аватар: изображение отклонено", zap.Int("user_id", userID), zap.Error(err))
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	key := fmt.Sprintf("avatars/%d_%d_%s", userID, time.Now().Unix(), randHex(4))
+	if err := writeAvatarFiles(src, key); err != nil {
+		log.Error("Аватар: сохранение не удалось", zap.Int("user_id", userID), zap.Error(err))
+		removeAvatarFiles(key)
+		helpers.Error(w, http.StatusInternalServerError, "ошибка сохранения файла")
+		return
+	}
+
+	prev, err := h.auth.SetAvatar(r.Context(), userID, &key)
+	if err != nil {
+		log.Error("Аватар: не удалось сохранить в профиле", zap.Int("user_id", userID), zap.Error(err))
+		removeAvatarFiles(key)
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка обновления профиля")
+		return
+	}
+	if prev != nil {
+		removeAvatarFiles(*prev)
+	}
+
+	log.Info("Аватар загружен", zap.Int("user_id", userID), zap.String("key", key))
+	helpers.JSON(w, http.StatusOK, AvatarResponse{AvatarURL: models.AvatarURL(userID, &key, models.AvatarSizes[0])})
+}
+
+// DeleteAvatar godoc
+// @Summary Удалить аватар
+// @Tags profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Description Вместо удалённого аватара показывается identicon.
+// @Success 200 {object} helpers.Response{data=AvatarResponse}
+// @Failure 401 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/profile/avatar [delete]
+func (h *AvatarHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+
+	prev, err := h.auth.SetAvatar(r.Context(), userID, nil)
+	if err != nil {
+		log.Error("Аватар: удаление не удалось", zap.Int("user_id", userID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка обновления профиля")
+		return
+	}
+	if prev != nil {
+		removeAvatarFiles(*prev)
+	}
+
+	helpers.JSON(w, http.StatusOK, AvatarResponse{AvatarURL: models.AvatarURL(userID, nil, models.AvatarSizes[0])})
+}
+
+// Avatar godoc
+// @Summary Аватар пользователя
+// @Tags profile
+// @Produce png
+// @Description Загруженный аватар — редирект на файл ближайшего размера не меньше size; иначе — identicon в PNG, постоянный для пользователя.
+// @Param id path int true "ID пользователя"
+// @Param size query int false "Сторона в пикселях (64, 128, 256)" default(256)
+// @Success 200 {file} file "identicon"
+// @Success 302 {string} string "Редирект на загруженный аватар"
+// @Failure 404 {object} helpers.Response
+// @Router /api/avatars/{id} [get]
+func (h *AvatarHandler) Avatar(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "Некорректный ID")
+		return
+	}
+	requested, _ := strconv.Atoi(r.URL.Query().Get("size"))
+	size := models.AvatarSize(requested)
+
+	user, err := h.auth.GetUserByID(r.Context(), id)
+	if err != nil {
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeUserNotFound, "Пользователь не найден")
+		return
+	}
+	if user.AvatarKey != nil {
+		http.Redirect(w, r, models.AvatarURL(id, user.AvatarKey, size), http.StatusFound)
+		return
+	}
+
+	png, err := imaging.EncodePNG(imaging.Identicon("user:"+strconv.Itoa(id), size))
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Аватар: identicon не построен", zap.Int("user_id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка формирования изображения")
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	_, _ = w.Write(png)
+}
+
+// writeAvatarFiles — версии всех размеров из AvatarSizes в uploads.
+func writeAvatarFiles(src image.Image, key string) error {
+	root := uploadsRoot()
+	if err := os.MkdirAll(filepath.Join(root, filepath.Dir(key)), 0o755); err != nil {
+		return err
+	}
+	for _, size := range models.AvatarSizes {
+		data, err := imaging.EncodeJPEG(imaging.SquareThumbnail(src, size))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(root, models.AvatarFile(key, size)), data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeAvatarFiles — удаляет версии аватара; отсутствующие файлы — не ошибка.
+func removeAvatarFiles(key string) {
+	root := uploadsRoot()
+	for _, size := range models.AvatarSizes {
+		if err := os.Remove(filepath.Join(root, models.AvatarFile(key, size))); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Log.Warn("Аватар: файл не удалён", zap.String("key", key), zap.Int("size", size), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type ChangelogHandler struct {
+	svc *services.ChangelogService
+}
+
+func NewChangelogHandler(svc *services.ChangelogService) *ChangelogHandler {
+	return &ChangelogHandler{svc: svc}
+}
+
+// List godoc
+// @Summary Список обновлений платформы (changelog)
+// @Description Только опубликованные записи, новые сверху
+// @Tags changelog
+// @Produce json
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=ChangelogListResponse}
+// @Failure 500 {object} helpers.Response
+// @Router /api/changelog [get]
+func (h *ChangelogHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, true)
+}
+
+// AdminList godoc
+// @Summary Все записи changelog, включая черновики
+// @Tags admin-changelog
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=ChangelogListResponse}
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/changelog [get]
+func (h *ChangelogHandler) AdminList(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, false)
+}
+
+func (h *ChangelogHandler) list(w http.ResponseWriter, r *http.Request, onlyPublished bool) {
+	log := logger.WithCtx(r.Context())
+	page, pageSize := pageParams(r)
+
+	list := h.svc.ListAll
+	if onlyPublished {
+		list = h.svc.ListPublished
+	}
+	items, total, err := list(r.Context(), pageSize, (page-1)*pageSize)
+	if err != nil {
+		log.Error("Ошибка получения changelog", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить список обновлений")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, ChangelogListResponse{
+		Data:       items,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+	})
+}
+
+// Unread godoc
+// @Summary Количество непросмотренных обновлений (бейдж «Что нового»)
+// @Tags changelog
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=models.ChangelogUnread}
+// @Failure 401 {object} helpers.Response
+// @Router /api/changelog/unread [get]
+func (h *ChangelogHandler) Unread(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+
+	res, err := h.svc.Unread(r.Context(), userID)
+	if err != nil {
+		log.Error("Ошибка подсчёта непросмотренных обновлений", zap.Int("user_id", userID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить данные")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// MarkSeen godoc
+// @Summary Отметить обновления просмотренными
+// @Tags changelog
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 401 {object} helpers.Response
+// @Router /api/changelog/seen [post]
+func (h *ChangelogHandler) MarkSeen(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+
+	if err := h.svc.MarkSeen(r.Context(), userID); err != nil {
+		log.Error("Ошибка отметки changelog просмотренным", zap.Int("user_id", userID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось сохранить отметку")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "ok"})
+}
+
+// Create godoc
+// @Summary Создать запись changelog
+// @Description kind: feature|improvement|fix (по умолчанию feature); publish=true — сразу опубликовать
+// @Tags admin-changelog
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body models.ChangelogEntryRequest true "Запись"
+// @Success 201 {object} helpers.Response{data=models.ChangelogEntry}
+// @Failure 400 {object} helpers.Response
+// @Router /api/admin/changelog [post]
+func (h *ChangelogHandler) Create(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	var req models.ChangelogEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "Невалидный JSON")
+		return
+	}
+
+	e, err := h.svc.Create(r.Context(), req)
+	if err != nil {
+		h.writeErr(w, err)
+		if !errors.Is(err, services.ErrChangelogInvalid) {
+			log.Error("Ошибка создания записи changelog", zap.Error(err))
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, e)
+}
+
+// Update godoc
+// @Summary Обновить запись changelog
+// @Tags admin-changelog
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID записи"
+// @Param input body models.ChangelogEntryRequest true "Запись"
+// @Success 200 {object} helpers.Response{data=models.ChangelogEntry}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/changelog/{id} [patch]
+func (h *ChangelogHandler) Update(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id")
+		return
+	}
+
+	var req models.ChangelogEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "Невалидный JSON")
+		return
+	}
+
+	e, err := h.svc.Update(r.Context(), id, req)
+	if err != nil {
+		h.writeErr(w, err)
+		if !errors.Is(err, services.ErrChangelogInvalid) && !errors.Is(err, services.ErrChangelogNotFound) {
+			log.Error("Ошибка обновления записи changelog", zap.Int("id", id), zap.Error(err))
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusOK, e)
+}
+
+// Delete godoc
+// @Summary Удалить запись changelog
+// @Tags admin-changelog
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID записи"
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/changelog/{id} [delete]
+func (h *ChangelogHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id")
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		h.writeErr(w, err)
+		if !errors.Is(err, services.ErrChangelogNotFound) {
+			log.Error("Ошибка удаления записи changelog", zap.Int("id", id), zap.Error(err))
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Запись удалена"})
+}
+
+func (h *ChangelogHandler) writeErr(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrChangelogInvalid):
+		helpers.Error(w, http.StatusBadRequest, "Нужен title; kind — feature|improvement|fix")
+	case errors.Is(err, services.ErrChangelogNotFound):
+		helpers.Error(w, http.StatusNotFound, "Запись не найдена")
+	default:
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+	}
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// ContentReviewHandler — согласование статей и документов авторов.
+type ContentReviewHandler struct {
+	svc *services.ContentReviewService
+}
+
+func NewContentReviewHandler(svc *services.ContentReviewService) *ContentReviewHandler {
+	return &ContentReviewHandler{svc: svc}
+}
+
+func reviewError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrReviewBadType), errors.Is(err, services.ErrReviewBadState),
+		errors.Is(err, services.ErrReviewComment):
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeBadRequest, err.Error())
+	case errors.Is(err, services.ErrReviewNotFound):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeNotFound, err.Error())
+	case errors.Is(err, services.ErrReviewTransition):
+		helpers.Fail(w, http.StatusConflict, helpers.CodeConflict, err.Error())
+	case errors.Is(err, services.ErrNotContentOwner):
+		helpers.Fail(w, http.StatusForbidden, helpers.CodeContentNotOwner, err.Error())
+	default:
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+	}
+	return true
+}
+
+// reviewTarget — тип и ID материала из пути, комментарий из необязательного тела.
+func reviewTarget(w http.ResponseWriter, r *http.Request, withBody bool) (string, int64, models.ReviewDecisionRequest, bool) {
+	var req models.ReviewDecisionRequest
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id")
+		return "", 0, req, false
+	}
+	if withBody && r.ContentLength != 0 && !decodeValid(w, r, &req) {
+		return "", 0, req, false
+	}
+	return vars["type"], id, req, true
+}
+
+// Queue godoc
+// @Summary Очередь согласования
+// @Description Статьи и документы в состоянии state (по умолчанию review — ждут проверки, давно ждущие сверху). draft → review (автор отправил) → published (одобрено) или rejected (отклонено, автор правит и отправляет снова). Опубликованное админом напрямую — published, снятое с публикации — draft.
+// @Tags admin-reviews
+// @Security ApiKeyAuth
+// @Produce json
+// @Param type query string false "article | document"
+// @Param state query string false "draft | review | rejected | published"
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=ReviewQueueResponse}
+// @Failure 400 {object} helpers.Response
+// @Router /api/admin/reviews [get]
+func (h *ContentReviewHandler) Queue(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := pageParams(r)
+	q := r.URL.Query()
+
+	items, total, err := h.svc.Queue(r.Context(), q.Get("type"), q.Get("state"), pageSize, (page-1)*pageSize)
+	if reviewError(w, err) {
+		if !errors.Is(err, services.ErrReviewBadType) && !errors.Is(err, services.ErrReviewBadState) {
+			logger.WithCtx(r.Context()).Error("Ошибка получения очереди согласования", zap.Error(err))
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusOK, ReviewQueueResponse{
+		Data:       items,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+	})
+}
+
+// Approve godoc
+// @Summary Одобрить материал
+// @Description Материал на проверке публикуется: статья — как PATCH /api/admin/articles/{id}/publish, документ становится публичным.
+// @Tags admin-reviews
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param type path string true "article | document"
+// @Param id path int true "ID материала"
+// @Param input body models.ReviewDecisionRequest false "Комментарий"
+// @Success 200 {object} helpers.Response{data=ReviewEventResponse}
+// @Failure 404 {object} helpers.Response
+// @Failure 409 {object} helpers.Response "Материал не на проверке"
+// @Router /api/admin/reviews/{type}/{id}/approve [post]
+func (h *ContentReviewHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	typ, id, req, ok := reviewTarget(w, r, true)
+	if !ok {
+		return
+	}
+	adminID, _ := middleware.UserIDFromContext(r.Context())
+
+	ev, err := h.svc.Approve(r.Context(), adminID, typ, id, req.Comment)
+	if reviewError(w, err) {
+		logger.WithCtx(r.Context()).Warn("Материал не одобрен", zap.String("type", typ), zap.Int64("id", id), zap.Error(err))
+		return
+	}
+	helpers.JSON(w, http.StatusOK, ReviewEventResponse{Data: ev})
+}
+
+// Reject godoc
+// @Summary Отклонить материал
+// @Description Материал на проверке возвращается автору; comment обязателен — автор видит его в истории.
+// @Tags admin-reviews
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param type path string true "article | document"
+// @Param id path int true "ID материала"
+// @Param input body models.ReviewDecisionRequest true "Причина отклонения"
+// @Success 200 {object} helpers.Response{data=ReviewEventResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 409 {object} helpers.Response "Материал не на проверке"
+// @Router /api/admin/reviews/{type}/{id}/reject [post]
+func (h *ContentReviewHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	typ, id, req, ok := reviewTarget(w, r, true)
+	if !ok {
+		return
+	}
+	adminID, _ := middleware.UserIDFromContext(r.Context())
+
+	ev, err := h.svc.Reject(r.Context(), adminID, typ, id, req.Comment)
+	if reviewError(w, err) {
+		logger.WithCtx(r.Context()).Warn("Материал не отклонён", zap.String("type", typ), zap.Int64("id", id), zap.Error(err))
+		return
+	}
+	helpers.JSON(w, http.StatusOK, ReviewEventResponse{Data: ev})
+}
+
+// History godoc
+// @Summary История согласования материала
+// @Tags admin-reviews
+// @Security ApiKeyAuth
+// @Produce json
+// @Param type path string true "article | document"
+// @Param id path int true "ID материала"
+// @Success 200 {object} helpers.Response{data=ReviewHistoryResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/reviews/{type}/{id}/history [get]
+func (h *ContentReviewHandler) History(w http.ResponseWriter, r *http.Request) {
+	typ, id, _, ok := reviewTarget(w, r, false)
+	if !ok {
+		return
+	}
+	events, err := h.svc.History(r.Context(), typ, id)
+	if reviewError(w, err) {
+		return
+	}
+	helpers.JSON(w, http.StatusOK, ReviewHistoryResponse{Data: events})
+}
+
+// Submit godoc
+// @Summary Отправить свой материал на проверку (автор)
+// @Description Черновик или отклонённый материал переходит в состояние review и попадает в очередь /api/admin/reviews.
+// @Tags author
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param type path string true "article | document"
+// @Param id path int true "ID материала"
+// @Param input body models.ReviewDecisionRequest false "Комментарий для проверяющего"
+// @Success 200 {object} helpers.Response{data=ReviewEventResponse}
+// @Failure 403 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 409 {object} helpers.Response "Материал уже на проверке или опубликован"
+// @Router /api/author/reviews/{type}/{id}/submit [post]
+func (h *ContentReviewHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	typ, id, req, ok := reviewTarget(w, r, true)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	ev, err := h.svc.Submit(r.Context(), userID, typ, id, req.Comment)
+	if reviewError(w, err) {
+		logger.WithCtx(r.Context()).Warn("Материал не отправлен на проверку", zap.String("type", typ), zap.Int64("id", id), zap.Error(err))
+		return
+	}
+	helpers.JSON(w, http.StatusOK, ReviewEventResponse{Data: ev})
+}
+
+// MyHistory godoc
+// @Summary История согласования своего материала (автор)
+// @Description Переходы состояния с комментариями проверяющих.
+// @Tags author
+// @Security ApiKeyAuth
+// @Produce json
+// @Param type path string true "article | document"
+// @Param id path int true "ID материала"
+// @Success 200 {object} helpers.Response{data=ReviewHistoryResponse}
+// @Failure 403 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/author/reviews/{type}/{id}/history [get]
+func (h *ContentReviewHandler) MyHistory(w http.ResponseWriter, r *http.Request) {
+	typ, id, _, ok := reviewTarget(w, r, false)
+	if !ok {
+		return
+	}
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	events, err := h.svc.OwnHistory(r.Context(), userID, typ, id)
+	if reviewError(w, err) {
+		return
+	}
+	helpers.JSON(w, http.StatusOK, ReviewHistoryResponse{Data: events})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+type ContentViewHandler struct {
+	views *services.ContentViewService
+}
+
+func NewContentViewHandler(views *services.ContentViewService) *ContentViewHandler {
+	return &ContentViewHandler{views: views}
+}
+
+// Trending godoc
+// @Summary Популярные новости и статьи
+// @Description Опубликованные материалы с наибольшим числом просмотров за период (один просмотр на зрителя в сутки, роботы не считаются).
+// @Tags content
+// @Produce json
+// @Param type query string false "news|article (пусто — все)"
+// @Param window query string false "Период: 24h, 7d, 30d… (по умолчанию TRENDING_WINDOW, до 90d)"
+// @Param limit query int false "Сколько материалов (до 50, по умолчанию 10)"
+// @Success 200 {object} helpers.Response{data=TrendingResponse}
+// @Failure 400 {object} helpers.Response
+// @Router /api/content/trending [get]
+func (h *ContentViewHandler) Trending(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+	q := r.URL.Query()
+
+	kind := strings.ToLower(strings.TrimSpace(q.Get("type")))
+	switch kind {
+	case "", models.ContentKindNews, models.ContentKindArticle:
+	default:
+		helpers.Error(w, http.StatusBadRequest, "type должен быть news|article")
+		return
+	}
+	window, ok := parseWindow(q.Get("window"))
+	if !ok {
+		helpers.Error(w, http.StatusBadRequest, "Некорректный параметр window")
+		return
+	}
+
+	items, window, err := h.views.Trending(r.Context(), kind, window, parseIntQuery(r, "limit", 10))
+	if err != nil {
+		log.Error("Ошибка получения популярных материалов", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить популярные материалы")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, TrendingResponse{
+		Items:     items,
+		Type:      kind,
+		WindowSec: int64(window.Seconds()),
+	})
+}
+
+// parseWindow — длительность вида "24h" или число дней "7d"; пусто — 0 (по умолчанию).
+func parseWindow(v string) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, true
+	}
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		n, err := time.ParseDuration(days + "h")
+		if err != nil || n <= 0 {
+			return 0, false
+		}
+		return n * 24, true
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+type DashboardStatsHandler struct {
+	svc *services.DashboardStatsService
+}
+
+func NewDashboardStatsHandler(svc *services.DashboardStatsService) *DashboardStatsHandler {
+	return &DashboardStatsHandler{svc: svc}
+}
+
+// statsPeriodParams — from/to (YYYY-MM-DD или RFC3339), обе даты включительно.
+func statsPeriodParams(w http.ResponseWriter, r *http.Request) (from, to *time.Time, ok bool) {
+	q := r.URL.Query()
+	var err error
+	if from, err = parseDateParam(q.Get("from"), false); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "Некорректный параметр from")
+		return nil, nil, false
+	}
+	if to, err = parseDateParam(q.Get("to"), false); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "Некорректный параметр to")
+		return nil, nil, false
+	}
+	return from, to, true
+}
+
+// writeSeries — ряд с границами периода; ошибка периода — 400.
+func writeSeries(w http.ResponseWriter, r *http.Request, what string, items any, p models.StatsPeriod, err error) {
+	if errors.Is(err, services.ErrStatsPeriod) {
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка построения графика дашборда", zap.String("series", what), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить статистику")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, StatsSeriesResponse{
+		From:  p.From.Format("2006-01-02"),
+		To:    p.To.Format("2006-01-02"),
+		Items: items,
+	})
+}
+
+// Registrations godoc
+// @Summary Регистрации по дням
+// @Description Новые пользователи за каждый день периода (по умолчанию последние 30 дней, не длиннее года); дни без регистраций — с нулём.
+// @Tags admin-stats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param from query string false "Начало периода (YYYY-MM-DD)"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Success 200 {object} helpers.Response{data=StatsSeriesResponse{items=[]models.DayCount}}
+// @Failure 400 {object} helpers.Response
+// @Router /api/admin/stats/registrations [get]
+func (h *DashboardStatsHandler) Registrations(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := statsPeriodParams(w, r)
+	if !ok {
+		return
+	}
+	items, p, err := h.svc.Registrations(r.Context(), from, to)
+	writeSeries(w, r, "registrations", items, p, err)
+}
+
+// Subscriptions godoc
+// @Summary Выдачи и отключения подписок по дням
+// @Description granted/extended — выдано и продлено (админом или оплатой), revoked — отключено вручную, expired — истекло по сроку.
+// @Tags admin-stats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param from query string false "Начало периода (YYYY-MM-DD)"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Success 200 {object} helpers.Response{data=StatsSeriesResponse{items=[]models.SubscriptionDayPoint}}
+// @Failure 400 {object} helpers.Response
+// @Router /api/admin/stats/subscriptions [get]
+func (h *DashboardStatsHandler) Subscriptions(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := statsPeriodParams(w, r)
+	if !ok {
+		return
+	}
+	items, p, err := h.svc.SubscriptionChanges(r.Context(), from, to)
+	writeSeries(w, r, "subscriptions", items, p, err)
+}
+
+// Uploads godoc
+// @Summary Загрузки документов по неделям
+// @Description Неделя начинается с понедельника; по умолчанию последние 12 недель.
+// @Tags admin-stats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param from query string false "Начало периода (YYYY-MM-DD)"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Success 200 {object} helpers.Response{data=StatsSeriesResponse{items=[]models.WeekCount}}
+// @Failure 400 {object} helpers.Response
+// @Router /api/admin/stats/uploads [get]
+func (h *DashboardStatsHandler) Uploads(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := statsPeriodParams(w, r)
+	if !ok {
+		return
+	}
+	items, p, err := h.svc.UploadsWeekly(r.Context(), from, to)
+	writeSeries(w, r, "uploads", items, p, err)
+}
+
+// TopAdmins godoc
+// @Summary Самые активные администраторы
+// @Description Загрузки документов, новые статьи, правки статей и изменения подписок за период (по умолчанию 30 дней).
+// @Tags admin-stats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param from query string false "Начало периода (YYYY-MM-DD)"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Param limit query int false "Сколько админов (до 50, по умолчанию 10)"
+// @Success 200 {object} helpers.Response{data=StatsSeriesResponse{items=[]models.AdminActivity}}
+// @Failure 400 {object} helpers.Response
+// @Router /api/admin/stats/admins [get]
+func (h *DashboardStatsHandler) TopAdmins(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := statsPeriodParams(w, r)
+	if !ok {
+		return
+	}
+	items, p, err := h.svc.TopAdmins(r.Context(), from, to, parseIntQuery(r, "limit", 10))
+	writeSeries(w, r, "admins", items, p, err)
+}
@@ -3,9 +3,11 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
@@ -14,10 +16,11 @@ import (
 	"strings"
 	"time"
 
+	"edutalks/internal/config"
+	"edutalks/internal/events"
 	"edutalks/internal/logger"
 	"edutalks/internal/middleware"
 	"edutalks/internal/models"
-	"edutalks/internal/repository"
 	"edutalks/internal/services"
 	helpers "edutalks/internal/utils/helpers"
 
@@ -26,176 +29,184 @@ import (
 )
 
 type DocumentHandler struct {
-	service      *services.DocumentService
-	userService  *services.AuthService
-	notifier     *services.Notifier
-	taxonomyRepo *repository.TaxonomyRepo
+	service     *services.DocumentService
+	userService *services.AuthService
+	notifier    *services.Notifier
+	downloads   *services.DownloadStatsService
+	scanner     *services.FileScanService
+	uploads     *services.UploadPolicy
+	quotas      *services.DownloadQuotaService
+	dedup       *services.DocumentDedupService
+	cfg         *config.Holder
 }
 
-func NewDocumentHandler(docService *services.DocumentService, userService *services.AuthService, notifier *services.Notifier, taxonomyRepo *repository.TaxonomyRepo) *DocumentHandler {
+func NewDocumentHandler(docService *services.DocumentService, userService *services.AuthService, notifier *services.Notifier, downloads *services.DownloadStatsService, scanner *services.FileScanService, uploads *services.UploadPolicy, quotas *services.DownloadQuotaService, dedup *services.DocumentDedupService, cfg *config.Holder) *DocumentHandler {
 	return &DocumentHandler{
-		service:      docService,
-		userService:  userService,
-		notifier:     notifier,
-		taxonomyRepo: taxonomyRepo,
+		service:     docService,
+		userService: userService,
+		notifier:    notifier,
+		downloads:   downloads,
+		scanner:     scanner,
+		uploads:     uploads,
+		quotas:      quotas,
+		dedup:       dedup,
+		cfg:         cfg,
 	}
 }
 
 // UploadDocument
 // @Summary      Загрузить документ
-// @Description  Админ может загрузить документ и привязать его к разделу
+// @Description  Админ может загрузить документ и привязать его к разделу. Несколько файлов — полем files[] (до 20): метаданные формы общие для всех, название — из titles[] по порядку или из имени файла; ответ — результат по каждому файлу, а подписчикам уходит одна запись о пакете. Общий размер запроса — не больше лимита самого крупного типа. Если такой файл уже загружен (по SHA-256), в ответе есть duplicate: документы с тем же файлом; при DUPLICATE_UPLOAD_MODE=link копия не сохраняется, а документ ссылается на существующий файл.
 // @Tags         documents
 // @Accept       multipart/form-data
 // @Produce      json
 // @Param        title       formData  string  false  "Название документа"
-// @Param        file        formData  file    true   "Файл"
+// @Param        file        formData  file    false  "Файл (один)"
+// @Param        files[]     formData  file    false  "Файлы (несколько)"
+// @Param        titles[]    formData  string  false  "Названия файлов из files[] по порядку"
 // @Param        description formData  string  false  "Описание"
 // @Param        is_public   formData  bool    true   "Публичный документ?"
 // @Param        category    formData  string  false  "Категория"
 // @Param        section_id  formData  int     false  "ID раздела"
 // @Param        allow_free_download formData bool false "Можно скачивать без подписки?"
-// @Success      201 {object} map[string]int
-// @Failure      400 {object} map[string]string
-// @Failure      500 {object} map[string]string
+// @Param        has_text_layer  formData bool   false "Есть текстовый слой (доступен скринридерам)"
+// @Param        large_print_url formData string false "Ссылка на версию крупным шрифтом"
+// @Param        audio_url       formData string false "Ссылка на аудиоверсию"
+// @Param        doc_number        formData string false "Номер нормативного документа"
+// @Param        issuing_authority formData string false "Орган, принявший документ"
+// @Param        adopted_at        formData string false "Дата принятия (YYYY-MM-DD)"
+// @Param        effective_at      formData string false "Дата вступления в силу (YYYY-MM-DD)"
+// @Success      201 {object} helpers.Response{data=UploadDocumentResponse}
+// @Success      200 {object} helpers.Response{data=uploadBatchReport} "Несколько файлов"
+// @Failure      400 {object} helpers.Response
+// @Failure      413 {object} helpers.Response "Больше лимита типа (FILE_TOO_LARGE)"
+// @Failure      415 {object} helpers.Response "Тип не разрешён или содержимое не совпадает с расширением"
+// @Failure      422 {object} helpers.Response "Файл заражён (FILE_INFECTED)"
+// @Failure      500 {object} helpers.Response
+// @Failure      503 {object} helpers.Response "Антивирус недоступен"
+// @Security     ApiKeyAuth
 // @Router       /api/admin/files/upload [post]
 func (h *DocumentHandler) UploadDocument(w http.ResponseWriter, r *http.Request) {
+	h.uploadDocuments(w, r, false)
+}
+
+// uploadDocuments — загрузка одного или нескольких файлов; own — из кабинета автора,
+// где документ можно загрузить только закрытым.
+func (h *DocumentHandler) uploadDocuments(w http.ResponseWriter, r *http.Request, own bool) {
 	log := logger.WithCtx(r.Context())
-	log.Info("Запрос на загрузку документа")
+	log.Info("Запрос на загрузку документа", zap.Bool("own", own))
 
-	if err := r.ParseMultipartForm(2000 << 20); err != nil {
-		log.Warn("Ошибка разбора формы при загрузке документа", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "Ошибка разбора формы")
+	if !h.parseUploadForm(w, r) {
 		return
 	}
 
-	file, handler, err := r.FormFile("file")
-	if err != nil {
-		log.Warn("Файл не найден в форме", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "Файл не найден")
+	files := r.MultipartForm.File["files[]"]
+	if len(files) == 0 {
+		files = r.MultipartForm.File["files"]
+	}
+	if len(files) > maxUploadFiles {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeValidationFailed, fmt.Sprintf("Не больше %d файлов за один запрос", maxUploadFiles))
 		return
 	}
-	defer file.Close()
-
-	description := r.FormValue("description")
-	isPublic := strings.ToLower(r.FormValue("is_public")) == "true"
-	category := r.FormValue("category")
-	title := r.FormValue("title")
-	allowFreeDownload := strings.ToLower(r.FormValue("allow_free_download")) == "true"
-
-	var sectionIDPtr *int
-	if s := r.FormValue("section_id"); s != "" {
-		if sid, convErr := strconv.Atoi(s); convErr == nil {
-			sectionIDPtr = &sid
-		} else {
-			log.Warn("Невалидный section_id", zap.String("raw", s))
+	var handler *multipart.FileHeader
+	if len(files) == 0 {
+		fhs := r.MultipartForm.File["file"]
+		if len(fhs) == 0 {
+			log.Warn("Файл не найден в форме")
+			helpers.Error(w, http.StatusBadRequest, "Файл не найден")
+			return
 		}
+		handler = fhs[0]
 	}
 
-	userID, ok := middleware.UserIDFromContext(r.Context())
-	if !ok || userID == 0 {
-		log.Warn("Нет user_id в контексте при загрузке документа")
-		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
-		return
-	}
-
-	log.Info("Параметры загрузки документа",
-		zap.String("original_filename", handler.Filename),
-		zap.Int64("upload_size_hint", handler.Size),
-		zap.String("title", title),
-		zap.String("category", category),
-		zap.Bool("is_public", isPublic),
-		zap.Bool("allow_free_download", allowFreeDownload),
-		zap.Any("section_id", sectionIDPtr),
-		zap.Int("user_id", userID),
-	)
-
-	uploadDir := "uploaded"
-	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
-		log.Error("Не удалось создать директорию загрузки", zap.Error(err))
-		helpers.Error(w, http.StatusInternalServerError, "Ошибка сохранения файла")
+	tmpl, ok := h.uploadTemplate(w, r)
+	if !ok {
 		return
 	}
-
-	filename := fmt.Sprintf("%d_%s", time.Now().Unix(), handler.Filename)
-	fullPath := filepath.Join(uploadDir, filename)
-
-	dst, err := os.Create(fullPath)
-	if err != nil {
-		log.Error("Не удалось создать файл на диске", zap.String("path", fullPath), zap.Error(err))
-		helpers.Error(w, http.StatusInternalServerError, "Ошибка при сохранении файла")
+	if own && (tmpl.IsPublic || tmpl.AllowFreeDownload) {
+		helpers.Fail(w, http.StatusForbidden, helpers.CodeContentPublishDenied, services.ErrPublishNotAllowed.Error())
 		return
 	}
-	defer dst.Close()
 
-	if _, err := io.Copy(dst, file); err != nil {
-		log.Error("Ошибка записи файла на диск", zap.String("path", fullPath), zap.Error(err))
-		helpers.Error(w, http.StatusInternalServerError, "Ошибка при сохранении файла")
+	if handler == nil {
+		h.uploadBatch(w, r, files, tmpl)
 		return
 	}
 
-	doc := &models.Document{
-		UserID:            userID,
-		Title:             title,
-		Filename:          handler.Filename,
-		Filepath:          fullPath,
-		Description:       description,
-		IsPublic:          isPublic,
-		Category:          category,
-		SectionID:         sectionIDPtr,
-		UploadedAt:        time.Now(),
-		AllowFreeDownload: allowFreeDownload,
-	}
-
-	log.Info("Сохраняем метаданные документа в БД",
-		zap.String("stored_filename", filename),
+	log.Info("Параметры загрузки документа",
 		zap.String("original_filename", handler.Filename),
-		zap.Int("user_id", userID),
+		zap.Int64("upload_size_hint", handler.Size),
+		zap.String("title", tmpl.Title),
+		zap.String("category", tmpl.Category),
+		zap.Bool("is_public", tmpl.IsPublic),
+		zap.Bool("allow_free_download", tmpl.AllowFreeDownload),
+		zap.Any("section_id", tmpl.SectionID),
+		zap.Int("user_id", tmpl.UserID),
 	)
 
-	id, err := h.service.Upload(r.Context(), doc)
-	if err != nil {
-		log.Error("Ошибка сохранения документа в БД", zap.Error(err))
-		helpers.Error(w, http.StatusInternalServerError, "Ошибка при сохранении документа")
+	doc, dup, fail := h.storeUpload(r.Context(), handler, tmpl)
+	if fail != nil {
+		fail.write(w)
 		return
 	}
+	id := doc.ID
 
-	ctx := context.WithoutCancel(r.Context())
-	var tabsID *int
-	if doc.SectionID != nil {
-		if tid, e := h.taxonomyRepo.GetTabIDBySectionID(ctx, *doc.SectionID); e == nil {
-			tabsID = &tid
-		} else {
-			log.Warn("Не удалось получить tab_id по section_id", zap.Any("section_id", *doc.SectionID), zap.Error(e))
-		}
-	}
-	h.notifier.AddDocumentForBatch(ctx, doc.Title, tabsID)
-	log.Info("Документ добавлен в batched-уведомления", zap.Int("doc_id", id), zap.Any("tab_id", tabsID))
-
-	helpers.JSON(w, http.StatusCreated, map[string]any{
-		"id": id,
-		"data": map[string]any{
-			"id":                  id,
-			"title":               doc.Title,
-			"filename":            doc.Filename,
-			"description":         doc.Description,
-			"category":            doc.Category,
-			"section_id":          doc.SectionID,
-			"is_public":           doc.IsPublic,
-			"uploaded_at":         doc.UploadedAt,
-			"allow_free_download": doc.AllowFreeDownload,
+	h.notifier.AddDocumentForBatch(context.WithoutCancel(r.Context()), id, doc.Title, doc.SectionID)
+	events.Publish(r.Context(), events.DocumentsAdded, &tmpl.UserID, map[string]any{
+		"document_ids": []int{id},
+		"titles":       []string{doc.Title},
+		"section_id":   doc.SectionID,
+	})
+	log.Info("Документ добавлен в batched-уведомления", zap.Int("doc_id", id), zap.Any("section_id", doc.SectionID))
+
+	helpers.JSON(w, http.StatusCreated, UploadDocumentResponse{
+		ID: id,
+		Data: UploadedDocument{
+			ID:                id,
+			Title:             doc.Title,
+			Filename:          doc.Filename,
+			Description:       doc.Description,
+			Category:          doc.Category,
+			SectionID:         doc.SectionID,
+			IsPublic:          doc.IsPublic,
+			UploadedAt:        doc.UploadedAt,
+			AllowFreeDownload: doc.AllowFreeDownload,
+			HasTextLayer:      doc.HasTextLayer,
+			LargePrintURL:     doc.LargePrintURL,
+			AudioURL:          doc.AudioURL,
+			DocNumber:         doc.DocNumber,
+			IssuingAuthority:  doc.IssuingAuthority,
+			AdoptedAt:         doc.AdoptedAt,
+			EffectiveAt:       doc.EffectiveAt,
+			ScanStatus:        doc.ScanStatus,
+			Duplicate:         dup,
 		},
 	})
 }
 
 // ListPublicDocuments
 // @Summary      Получить список публичных документов (без пагинации)
-// @Description  Поддерживает фильтры: section_id и category. Возвращает все подходящие документы.
+// @Description  Поддерживает фильтры: section_id, category, доступность (text_layer, large_print, audio) и реквизиты (номер, орган, даты). Возвращает все подходящие документы.
 // @Tags         documents
 // @Produce      json
 // @Param        section_id  query  int     false  "ID раздела"
 // @Param        category    query  string  false  "Категория документа"
-// @Success      200 {object} map[string]interface{} "data, total, category, section_id"
-// @Failure      500 {object} map[string]string
+// @Param        text_layer  query  bool    false  "Только с текстовым слоем"
+// @Param        large_print query  bool    false  "Только с версией крупным шрифтом"
+// @Param        audio       query  bool    false  "Только с аудиоверсией"
+// @Param        number         query  string  false  "Номер документа (подстрока, без учёта регистра)"
+// @Param        authority      query  string  false  "Орган, принявший документ (подстрока)"
+// @Param        adopted_from   query  string  false  "Принят не раньше (YYYY-MM-DD)"
+// @Param        adopted_to     query  string  false  "Принят не позже (YYYY-MM-DD)"
+// @Param        effective_from query  string  false  "Вступает в силу не раньше (YYYY-MM-DD)"
+// @Param        effective_to   query  string  false  "Вступает в силу не позже (YYYY-MM-DD)"
+// @Param        in_force       query  bool    false  "Только вступившие в силу"
+// @Param        sort           query  string  false  "Сортировка: uploaded_at (по умолчанию), adopted_at, effective_at, doc_number"
+// @Param        order          query  string  false  "asc|desc (по умолчанию desc)"
+// @Success      200 {object} helpers.Response{data=DocumentListResponse}
+// @Success      304 {string} string "Не изменилось (If-None-Match)"
+// @Failure      400 {object} helpers.Response
+// @Failure      500 {object} helpers.Response
 // @Router       /api/files [get]
 func (h *DocumentHandler) ListPublicDocuments(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -211,9 +222,15 @@ func (h *DocumentHandler) ListPublicDocuments(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	log.Info("Запрос публичных документов", zap.Any("section_id", sectionIDPtr), zap.String("category", category))
+	a11y := accessibilityFilter(r)
+	normative, err := normativeFilter(r)
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	log.Info("Запрос публичных документов", zap.Any("section_id", sectionIDPtr), zap.String("category", category), zap.Any("a11y", a11y), zap.Any("normative", normative))
 
-	docs, err := h.service.GetPublicDocuments(r.Context(), sectionIDPtr, category)
+	docs, err := h.service.GetPublicDocuments(r.Context(), sectionIDPtr, category, a11y, normative)
 	if err != nil {
 		log.Error("Ошибка получения публичных документов", zap.Error(err))
 		helpers.Error(w, http.StatusInternalServerError, "Ошибка при получении документов")
@@ -221,16 +238,17 @@ func (h *DocumentHandler) ListPublicDocuments(w http.ResponseWriter, r *http.Req
 	}
 
 	log.Info("Публичные документы получены", zap.Int("count", len(docs)))
-	helpers.JSON(w, http.StatusOK, map[string]any{
-		"data":       docs,
-		"total":      len(docs),
-		"category":   category,
-		"section_id": sectionIDPtr,
+	helpers.JSONConditional(w, r, DocumentListResponse{
+		Data:      docs,
+		Total:     len(docs),
+		Category:  category,
+		SectionID: sectionIDPtr,
 	})
 }
 
 // DownloadDocument godoc
 // @Summary Скачать документ по ID
+// @Description Закрытый документ или документ без бесплатного скачивания доступен без подписки, если админ выдал пользователю персональный доступ.
 // @Tags files
 // @Security ApiKeyAuth
 // @Produce application/octet-stream
@@ -238,53 +256,16 @@ func (h *DocumentHandler) ListPublicDocuments(w http.ResponseWriter, r *http.Req
 // @Success 200 {file} file
 // @Failure 403 {string} string "Нет доступа"
 // @Failure 404 {string} string "Документ не найден"
+// @Failure 429 {object} helpers.Response "Дневной лимит скачиваний исчерпан (DOWNLOAD_QUOTA_EXCEEDED), details — состояние лимита"
 // @Router /api/files/{id} [get]
 func (h *DocumentHandler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
 
-	userID, ok := middleware.UserIDFromContext(r.Context())
-	if !ok || userID == 0 {
-		log.Warn("Нет доступа при скачивании документа: отсутствует user_id")
-		helpers.Error(w, http.StatusUnauthorized, "Пользователь не найден")
-		return
-	}
-
-	idStr := mux.Vars(r)["id"]
-	id, err := strconv.Atoi(idStr)
-	if err != nil || id <= 0 {
-		log.Warn("Невалидный идентификатор документа", zap.String("raw", idStr))
-		helpers.Error(w, http.StatusBadRequest, "Некорректный идентификатор документа")
-		return
-	}
-
-	log.Info("Запрос на скачивание документа", zap.Int("user_id", userID), zap.Int("doc_id", id))
-
-	user, err := h.userService.GetUserByID(r.Context(), userID)
-	if err != nil {
-		log.Warn("Пользователь не найден при скачивании документа", zap.Int("user_id", userID))
-		helpers.Error(w, http.StatusUnauthorized, "Пользователь не найден")
+	user, doc, ok := h.accessibleDocument(w, r)
+	if !ok {
 		return
 	}
-
-	doc, err := h.service.GetDocumentByID(r.Context(), id)
-	if err != nil {
-		log.Warn("Документ не найден", zap.Int("doc_id", id))
-		helpers.Error(w, http.StatusNotFound, "Документ не найден")
-		return
-	}
-
-	if user.Role != "admin" {
-		if !doc.IsPublic {
-			log.Warn("Попытка доступа к закрытому документу", zap.Int("user_id", userID), zap.Int("doc_id", id))
-			helpers.Error(w, http.StatusForbidden, "Этот документ закрыт")
-			return
-		}
-		if !isActiveSub(user) && !doc.AllowFreeDownload {
-			log.Warn("Нет подписки и документ не free", zap.Int("user_id", userID), zap.Int("doc_id", id))
-			helpers.Error(w, http.StatusForbidden, "Нет доступа — купите подписку")
-			return
-		}
-	}
+	userID, id := user.ID, doc.ID
 
 	f, err := os.Open(doc.Filepath)
 	if err != nil {
@@ -294,6 +275,21 @@ func (h *DocumentHandler) DownloadDocument(w http.ResponseWriter, r *http.Reques
 	}
 	defer f.Close()
 
+	// докачки (Range не с начала файла) не считаем отдельными скачиваниями
+	rng := r.Header.Get("Range")
+	fromStart := rng == "" || strings.HasPrefix(rng, "bytes=0-")
+
+	quota, err := h.quotas.Consume(r.Context(), user, id, !fromStart)
+	switch {
+	case errors.Is(err, services.ErrDownloadQuotaExceeded):
+		log.Info("Скачивание отклонено: дневной лимит", zap.Int("user_id", userID), zap.Int("doc_id", id), zap.String("tier", quota.Tier))
+		writeQuotaExceeded(w, quota)
+		return
+	case err != nil:
+		// сбой учёта не лишает пользователя доступа к файлу
+		log.Warn("Не удалось учесть лимит скачиваний", zap.Int("user_id", userID), zap.Error(err))
+	}
+
 	ctype := mime.TypeByExtension(strings.ToLower(filepath.Ext(doc.Filename)))
 	if ctype == "" {
 		buf := make([]byte, 512)
@@ -307,6 +303,10 @@ func (h *DocumentHandler) DownloadDocument(w http.ResponseWriter, r *http.Reques
 
 	encoded := url.PathEscape(doc.Filename)
 	w.Header().Set("Content-Type", ctype)
+	if doc.SupersededBy != nil {
+		// документ заменён — клиент может показать ссылку на действующую редакцию
+		w.Header().Set("Link", fmt.Sprintf(`</api/documents/%d/preview>; rel="successor-version"`, doc.SupersededBy.DocumentID))
+	}
 	// Добавляем и filename и filename*, чтобы охватить больше клиентов
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q; filename*=UTF-8''%s", doc.Filename, encoded))
 
@@ -319,6 +319,15 @@ func (h *DocumentHandler) DownloadDocument(w http.ResponseWriter, r *http.Reques
 
 	http.ServeContent(w, r, doc.Filename, doc.UploadedAt, f)
 
+	if fromStart {
+		h.downloads.Record(context.WithoutCancel(r.Context()), id, userID)
+	}
+	events.Publish(r.Context(), events.DocumentDownloaded, &userID, map[string]any{
+		"document_id": id,
+		"role":        user.Role,
+		"free":        doc.AllowFreeDownload,
+	})
+
 	log.Info("Документ успешно скачан",
 		zap.Int("user_id", userID),
 		zap.Int("doc_id", id),
@@ -329,8 +338,69 @@ func (h *DocumentHandler) DownloadDocument(w http.ResponseWriter, r *http.Reques
 	)
 }
 
+// accessibleDocument — пользователь и документ из запроса с проверкой доступа:
+// админ — к любому, автор (роль author) — к загруженным им самим, пользователь с персональным
+// доступом — к выданному ему документу, остальные — к публичному при подписке или бесплатном скачивании.
+// Заражённые файлы не отдаются никому. При отказе ответ уже записан.
+func (h *DocumentHandler) accessibleDocument(w http.ResponseWriter, r *http.Request) (*models.User, *models.Document, bool) {
+	log := logger.WithCtx(r.Context())
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		log.Warn("Нет доступа к документу: отсутствует user_id")
+		helpers.Fail(w, http.StatusUnauthorized, helpers.CodeUserNotFound, "Пользователь не найден")
+		return nil, nil, false
+	}
+
+	idStr := mux.Vars(r)["id"]
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		log.Warn("Невалидный идентификатор документа", zap.String("raw", idStr))
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный идентификатор документа")
+		return nil, nil, false
+	}
+
+	log.Info("Запрос файла документа", zap.Int("user_id", userID), zap.Int("doc_id", id), zap.String("path", r.URL.Path))
+
+	user, err := h.userService.GetUserByID(r.Context(), userID)
+	if err != nil {
+		log.Warn("Пользователь не найден при запросе документа", zap.Int("user_id", userID))
+		helpers.Fail(w, http.StatusUnauthorized, helpers.CodeUserNotFound, "Пользователь не найден")
+		return nil, nil, false
+	}
+
+	doc, err := h.service.GetDocumentByID(r.Context(), id)
+	if err != nil {
+		log.Warn("Документ не найден", zap.Int("doc_id", id))
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeDocNotFound, "Документ не найден")
+		return nil, nil, false
+	}
+
+	ownDoc := user.Role == models.RoleAuthor && doc.UserID == user.ID
+	if user.Role != "admin" && !ownDoc && !h.hasGrant(r, doc, user) {
+		if !doc.IsPublic {
+			log.Warn("Попытка доступа к закрытому документу", zap.Int("user_id", userID), zap.Int("doc_id", id))
+			helpers.Fail(w, http.StatusForbidden, helpers.CodeDocNotPublic, "Этот документ закрыт")
+			return nil, nil, false
+		}
+		if !isActiveSub(user) && !doc.AllowFreeDownload {
+			log.Warn("Нет подписки и документ не free", zap.Int("user_id", userID), zap.Int("doc_id", id))
+			helpers.Fail(w, http.StatusForbidden, helpers.CodeDocSubscriptionRequired, "Нет доступа — купите подписку")
+			return nil, nil, false
+		}
+	}
+
+	if doc.ScanStatus == models.DocumentScanInfected {
+		log.Warn("Попытка получить заражённый файл", zap.Int("user_id", userID), zap.Int("doc_id", id))
+		helpers.Fail(w, http.StatusForbidden, helpers.CodeFileInfected, "Файл заблокирован: обнаружена угроза")
+		return nil, nil, false
+	}
+	return user, doc, true
+}
+
 // DeleteDocument godoc
 // @Summary Удаление документа (только для админа)
+// @Description Документ уходит в корзину (/api/admin/trash); файл на диске удаляется вместе с окончательным удалением из корзины.
 // @Tags admin-files
 // @Security ApiKeyAuth
 // @Param id path int true "ID документа"
@@ -344,7 +414,7 @@ func (h *DocumentHandler) DeleteDocument(w http.ResponseWriter, r *http.Request)
 	id, err := strconv.Atoi(idStr)
 	if err != nil || id <= 0 {
 		log.Warn("Невалидный doc_id в DeleteDocument", zap.String("raw", idStr))
-		helpers.Error(w, http.StatusBadRequest, "Некорректный id документа")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id документа")
 		return
 	}
 
@@ -353,7 +423,7 @@ func (h *DocumentHandler) DeleteDocument(w http.ResponseWriter, r *http.Request)
 	doc, err := h.service.GetDocumentByID(r.Context(), id)
 	if err != nil {
 		log.Warn("Документ не найден для удаления", zap.Int("doc_id", id))
-		helpers.Error(w, http.StatusNotFound, "Документ не найден")
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeDocNotFound, "Документ не найден")
 		return
 	}
 
@@ -363,13 +433,7 @@ func (h *DocumentHandler) DeleteDocument(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := os.Remove(doc.Filepath); err != nil && !os.IsNotExist(err) {
-		log.Error("Ошибка при удалении файла с диска", zap.String("filepath", doc.Filepath), zap.Error(err))
-		helpers.Error(w, http.StatusInternalServerError, "Файл не удалось удалить")
-		return
-	}
-
-	log.Info("Документ успешно удалён", zap.Int("doc_id", id))
+	log.Info("Документ перемещён в корзину", zap.Int("doc_id", id), zap.String("filename", doc.Filename))
 	helpers.JSON(w, http.StatusOK, "Документ удалён")
 }
 
@@ -379,8 +443,8 @@ func (h *DocumentHandler) DeleteDocument(w http.ResponseWriter, r *http.Request)
 // @Security ApiKeyAuth
 // @Produce json
 // @Param limit query int false "Максимальное количество документов (по умолчанию 10, 0 = все)"
-// @Success 200 {array} models.Document
-// @Failure 500 {string} string "Ошибка сервера"
+// @Success 200 {object} helpers.Response{data=DocumentsResponse}
+// @Failure 500 {object} helpers.Response "Ошибка сервера"
 // @Router /api/admin/files [get]
 func (h *DocumentHandler) GetAllDocuments(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -406,7 +470,7 @@ func (h *DocumentHandler) GetAllDocuments(w http.ResponseWriter, r *http.Request
 	}
 
 	log.Info("Список документов получен", zap.Int("count", len(docs)))
-	helpers.JSON(w, http.StatusOK, map[string]any{"data": docs})
+	helpers.JSON(w, http.StatusOK, DocumentsResponse{Data: docs})
 }
 
 // PreviewDocument godoc
@@ -415,7 +479,7 @@ func (h *DocumentHandler) GetAllDocuments(w http.ResponseWriter, r *http.Request
 // @Tags public-documents
 // @Param id path int true "ID документа"
 // @Produce json
-// @Success 200 {object} models.DocumentPreviewResponse
+// @Success 200 {object} helpers.Response{data=DocumentPreviewItemResponse}
 // @Failure 404 {object} string "Документ не найден"
 // @Failure 403 {object} string "Документ не публичный"
 // @Router /api/documents/{id}/preview [get]
@@ -426,20 +490,20 @@ func (h *DocumentHandler) PreviewDocument(w http.ResponseWriter, r *http.Request
 	id, err := strconv.Atoi(idStr)
 	if err != nil || id <= 0 {
 		log.Warn("Невалидный id в PreviewDocument", zap.String("raw", idStr))
-		helpers.Error(w, http.StatusBadRequest, "Невалидный id")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Невалидный id")
 		return
 	}
 
 	doc, err := h.service.GetDocumentByID(r.Context(), id)
 	if err != nil {
 		log.Warn("Документ не найден (preview)", zap.Int("doc_id", id))
-		helpers.Error(w, http.StatusNotFound, "Документ не найден")
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeDocNotFound, "Документ не найден")
 		return
 	}
 
 	if !doc.IsPublic {
 		log.Warn("Документ не публичный (preview запрещён)", zap.Int("doc_id", id))
-		helpers.Error(w, http.StatusForbidden, "Документ недоступен для просмотра")
+		helpers.Fail(w, http.StatusForbidden, helpers.CodeDocNotPublic, "Документ недоступен для просмотра")
 		return
 	}
 
@@ -451,10 +515,24 @@ func (h *DocumentHandler) PreviewDocument(w http.ResponseWriter, r *http.Request
 		SectionID:   doc.SectionID,
 		UploadedAt:  doc.UploadedAt.Format("2006-01-02"),
 		Message:     "Документ доступен только по подписке",
+
+		AllowFreeDownload: doc.AllowFreeDownload,
+		HasTextLayer:      doc.HasTextLayer,
+		LargePrintURL:     doc.LargePrintURL,
+		AudioURL:          doc.AudioURL,
+		ContentUpdatedAt:  doc.ContentUpdatedAt,
+		IsUpdatedRecently: doc.IsUpdatedRecently,
+		Relations:         publicLinks(doc.Relations),
+		SupersededBy:      doc.SupersededBy,
+
+		DocNumber:        doc.DocNumber,
+		IssuingAuthority: doc.IssuingAuthority,
+		AdoptedAt:        formatDate(doc.AdoptedAt),
+		EffectiveAt:      formatDate(doc.EffectiveAt),
 	}
 
 	log.Info("Превью документа сформировано", zap.Int("doc_id", id))
-	helpers.JSON(w, http.StatusOK, map[string]any{"item": resp})
+	helpers.JSON(w, http.StatusOK, DocumentPreviewItemResponse{Item: resp})
 }
 
 // PreviewDocuments godoc
@@ -464,8 +542,21 @@ func (h *DocumentHandler) PreviewDocument(w http.ResponseWriter, r *http.Request
 // @Param page query int false "Номер страницы (по умолчанию 1)"
 // @Param page_size query int false "Размер страницы (по умолчанию 10)"
 // @Param category query string false "Категория"
-// @Success 200 {object} map[string]interface{} "data, page, page_size, total, category"
-// @Failure 500 {object} map[string]string
+// @Param text_layer query bool false "Только с текстовым слоем"
+// @Param large_print query bool false "Только с версией крупным шрифтом"
+// @Param audio query bool false "Только с аудиоверсией"
+// @Param number query string false "Номер документа (подстрока, без учёта регистра)"
+// @Param authority query string false "Орган, принявший документ (подстрока)"
+// @Param adopted_from query string false "Принят не раньше (YYYY-MM-DD)"
+// @Param adopted_to query string false "Принят не позже (YYYY-MM-DD)"
+// @Param effective_from query string false "Вступает в силу не раньше (YYYY-MM-DD)"
+// @Param effective_to query string false "Вступает в силу не позже (YYYY-MM-DD)"
+// @Param in_force query bool false "Только вступившие в силу"
+// @Param sort query string false "Сортировка: uploaded_at (по умолчанию), adopted_at, effective_at, doc_number"
+// @Param order query string false "asc|desc (по умолчанию desc)"
+// @Success 200 {object} helpers.Response{data=DocumentPreviewListResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
 // @Router /api/documents/preview [get]
 func (h *DocumentHandler) PreviewDocuments(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -480,13 +571,20 @@ func (h *DocumentHandler) PreviewDocuments(w http.ResponseWriter, r *http.Reques
 	}
 	offset := (page - 1) * pageSize
 	category := r.URL.Query().Get("category")
+	a11y := accessibilityFilter(r)
+	normative, err := normativeFilter(r)
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	log.Info("Запрос превью документов",
 		zap.Int("page", page), zap.Int("page_size", pageSize),
 		zap.Int("offset", offset), zap.String("category", category),
+		zap.Any("a11y", a11y), zap.Any("normative", normative),
 	)
 
-	docs, total, err := h.service.GetPublicDocumentsPaginated(r.Context(), pageSize, offset, category)
+	docs, total, err := h.service.GetPublicDocumentsPaginated(r.Context(), pageSize, offset, category, a11y, normative)
 	if err != nil {
 		log.Error("Ошибка получения превью документов", zap.Error(err))
 		helpers.Error(w, http.StatusInternalServerError, "Ошибка получения документов")
@@ -506,19 +604,123 @@ func (h *DocumentHandler) PreviewDocuments(w http.ResponseWriter, r *http.Reques
 			SectionID:   d.SectionID,
 			UploadedAt:  d.UploadedAt.Format("2006-01-02"),
 			Message:     "Документ доступен только по подписке",
+
+			AllowFreeDownload: d.AllowFreeDownload,
+			HasTextLayer:      d.HasTextLayer,
+			LargePrintURL:     d.LargePrintURL,
+			AudioURL:          d.AudioURL,
+			ContentUpdatedAt:  d.ContentUpdatedAt,
+			IsUpdatedRecently: d.IsUpdatedRecently,
+			Relations:         d.Relations,
+			SupersededBy:      d.SupersededBy,
+
+			DocNumber:        d.DocNumber,
+			IssuingAuthority: d.IssuingAuthority,
+			AdoptedAt:        formatDate(d.AdoptedAt),
+			EffectiveAt:      formatDate(d.EffectiveAt),
 		})
 	}
 
 	log.Info("Превью документов сформировано", zap.Int("count", len(previews)), zap.Int("total", total))
-	helpers.JSON(w, http.StatusOK, map[string]any{
-		"data":      previews,
-		"total":     total,
-		"page":      page,
-		"page_size": pageSize,
-		"category":  category,
+	helpers.JSON(w, http.StatusOK, DocumentPreviewListResponse{
+		Data:       previews,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+		Category:   category,
 	})
 }
 
+// UpdateAccessibility godoc
+// @Summary Изменить поля доступности документа (только для админа)
+// @Description Поля, которые не переданы, не меняются; пустая строка очищает ссылку
+// @Tags admin-files
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID документа"
+// @Param input body models.UpdateDocumentAccessibilityRequest true "Поля доступности"
+// @Success 200 {object} helpers.Response{data=DocumentResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/files/{id}/accessibility [patch]
+func (h *DocumentHandler) UpdateAccessibility(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	idStr := mux.Vars(r)["id"]
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id документа")
+		return
+	}
+
+	var req models.UpdateDocumentAccessibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "Невалидный JSON")
+		return
+	}
+
+	doc, err := h.service.UpdateAccessibility(r.Context(), id, req)
+	switch {
+	case errors.Is(err, services.ErrInvalidAccessibilityURL):
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, services.ErrDocumentNotFound):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeDocNotFound, "Документ не найден")
+		return
+	case err != nil:
+		log.Error("Ошибка обновления доступности документа", zap.Int("doc_id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, DocumentResponse{Data: doc})
+}
+
+// UpdateNormative godoc
+// @Summary Изменить реквизиты нормативного документа (только для админа)
+// @Description Номер, орган, дата принятия и вступления в силу. Не переданные поля не меняются; пустая строка очищает поле.
+// @Tags admin-files
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID документа"
+// @Param input body models.UpdateDocumentNormativeRequest true "Реквизиты"
+// @Success 200 {object} helpers.Response{data=DocumentResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/files/{id}/normative [patch]
+func (h *DocumentHandler) UpdateNormative(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	idStr := mux.Vars(r)["id"]
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id документа")
+		return
+	}
+
+	var req models.UpdateDocumentNormativeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "Невалидный JSON")
+		return
+	}
+
+	doc, err := h.service.UpdateNormative(r.Context(), id, req)
+	switch {
+	case services.IsNormativeValidationError(err):
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, services.ErrDocumentNotFound):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeDocNotFound, "Документ не найден")
+		return
+	case err != nil:
+		log.Error("Ошибка обновления реквизитов документа", zap.Int("doc_id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, DocumentResponse{Data: doc})
+}
+
 // UpdateMyProfile godoc
 // @Summary Обновить свои данные
 // @Tags profile
@@ -529,6 +731,7 @@ func (h *DocumentHandler) PreviewDocuments(w http.ResponseWriter, r *http.Reques
 // @Success 200 {string} string "Профиль обновлён"
 // @Failure 400 {string} string "Ошибка запроса"
 // @Failure 401 {string} string "Нет доступа"
+// @Failure 403 {object} helpers.Response "Смена телефона в режиме поддержки"
 // @Router /api/profile [patch]
 func (h *AuthHandler) UpdateMyProfile(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -543,20 +746,133 @@ func (h *AuthHandler) UpdateMyProfile(w http.ResponseWriter, r *http.Request) {
 	var input models.UpdateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		log.Warn("Невалидный JSON при обновлении профиля", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "Невалидный JSON")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "Невалидный JSON")
 		return
 	}
 
 	input.Role = nil // обычный пользователь не меняет роль
+	if !validRequest(w, r, &input) {
+		return
+	}
+
+	// телефон — канал восстановления пароля: поддержка его за пользователя не меняет
+	if _, impersonated := middleware.ImpersonationIDFromContext(r.Context()); impersonated && input.Phone != nil {
+		helpers.Fail(w, http.StatusForbidden, helpers.CodeAuthImpersonated, "Действие недоступно в режиме поддержки")
+		return
+	}
+
+	// email меняется только с подтверждением нового адреса (POST /api/profile/email)
+	if input.Email != nil {
+		u, err := h.authService.GetUserByID(r.Context(), userID)
+		if err != nil {
+			log.Error("Ошибка получения пользователя при обновлении профиля", zap.Error(err), zap.Int("user_id", userID))
+			helpers.Error(w, http.StatusInternalServerError, "Ошибка обновления профиля")
+			return
+		}
+		if !strings.EqualFold(strings.TrimSpace(*input.Email), u.Email) {
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeEmailChangeRequired, "Email меняется через POST /api/profile/email с подтверждением нового адреса")
+			return
+		}
+		input.Email = nil
+	}
 
 	if err := h.authService.UpdateUser(r.Context(), userID, &input); err != nil {
+		if errors.Is(err, services.ErrUnsupportedLocale) {
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeLocaleUnsupported, "Неподдерживаемый язык: допустимы ru, en")
+			return
+		}
 		log.Error("Ошибка обновления профиля", zap.Error(err), zap.Int("user_id", userID))
 		helpers.Error(w, http.StatusInternalServerError, "Ошибка обновления профиля")
 		return
 	}
 
 	log.Info("Профиль обновлён", zap.Int("user_id", userID))
-	helpers.JSON(w, http.StatusOK, map[string]string{"message": "Профиль обновлён"})
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Профиль обновлён"})
+}
+
+// accessibilityFilter — text_layer/large_print/audio из query (true|1).
+func accessibilityFilter(r *http.Request) models.DocumentAccessibilityFilter {
+	q := r.URL.Query()
+	on := func(k string) bool {
+		v := strings.ToLower(strings.TrimSpace(q.Get(k)))
+		return v == "true" || v == "1"
+	}
+	return models.DocumentAccessibilityFilter{
+		TextLayer:  on("text_layer"),
+		LargePrint: on("large_print"),
+		Audio:      on("audio"),
+	}
+}
+
+// normativeFilter — фильтры и сортировка по реквизитам из query; ошибка — некорректная дата.
+func normativeFilter(r *http.Request) (models.DocumentNormativeFilter, error) {
+	q := r.URL.Query()
+	f := models.DocumentNormativeFilter{
+		Number:    strings.TrimSpace(q.Get("number")),
+		Authority: strings.TrimSpace(q.Get("authority")),
+		Asc:       strings.ToLower(strings.TrimSpace(q.Get("order"))) == "asc",
+	}
+	switch sort := strings.TrimSpace(q.Get("sort")); sort {
+	case models.DocumentSortAdopted, models.DocumentSortEffective, models.DocumentSortNumber:
+		f.Sort = sort
+	default:
+		f.Sort = models.DocumentSortUploaded
+	}
+	if v := strings.ToLower(strings.TrimSpace(q.Get("in_force"))); v == "true" || v == "1" {
+		f.InForce = true
+	}
+
+	dates := []struct {
+		key string
+		dst **time.Time
+	}{
+		{"adopted_from", &f.AdoptedFrom},
+		{"adopted_to", &f.AdoptedTo},
+		{"effective_from", &f.EffectiveFrom},
+		{"effective_to", &f.EffectiveTo},
+	}
+	for _, d := range dates {
+		t, err := services.ParseNormativeDate(q.Get(d.key))
+		if err != nil {
+			return f, fmt.Errorf("%s: %w", d.key, err)
+		}
+		*d.dst = t
+	}
+	return f, nil
+}
+
+// formString — необязательное текстовое поле формы (пусто — nil).
+func formString(r *http.Request, key string) *string {
+	v := strings.TrimSpace(r.FormValue(key))
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(services.NormativeDateLayout)
+}
+
+// formAccessibilityURLs — ссылки на версию крупным шрифтом и аудиоверсию из формы загрузки.
+func formAccessibilityURLs(r *http.Request) (largePrint, audio *string, ok bool) {
+	get := func(key string) (*string, bool) {
+		v := strings.TrimSpace(r.FormValue(key))
+		if v == "" {
+			return nil, true
+		}
+		return &v, services.ValidAccessibilityURL(v)
+	}
+	if largePrint, ok = get("large_print_url"); !ok {
+		return nil, nil, false
+	}
+	if audio, ok = get("audio_url"); !ok {
+		return nil, nil, false
+	}
+	return largePrint, audio, true
 }
 
 func isActiveSub(u *models.User) bool {
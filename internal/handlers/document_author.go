@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// MyDocuments godoc
+// @Summary Мои документы (автор)
+// @Description Документы, загруженные текущим пользователем, включая закрытые. Доступно ролям author и admin.
+// @Tags author
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=MyDocumentsResponse}
+// @Failure 403 {object} helpers.Response
+// @Router /api/author/files [get]
+func (h *DocumentHandler) MyDocuments(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	page, pageSize := pageParams(r)
+
+	docs, total, err := h.service.ListOwn(r.Context(), userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка получения документов")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, MyDocumentsResponse{
+		Data:       docs,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+	})
+}
+
+// UploadMyDocument godoc
+// @Summary Загрузить документ (автор)
+// @Description Как /api/admin/files/upload, но документ загружается только закрытым: is_public=true или allow_free_download=true — 403 CONTENT_PUBLISH_DENIED. Открывает документ администратор.
+// @Tags author
+// @Security ApiKeyAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param title       formData string false "Название документа"
+// @Param file        formData file   false "Файл (один)"
+// @Param files[]     formData file   false "Файлы (несколько)"
+// @Param titles[]    formData string false "Названия файлов из files[] по порядку"
+// @Param description formData string false "Описание"
+// @Param category    formData string false "Категория"
+// @Param section_id  formData int    false "ID раздела"
+// @Success 201 {object} helpers.Response{data=UploadDocumentResponse}
+// @Success 200 {object} helpers.Response{data=uploadBatchReport} "Несколько файлов"
+// @Failure 400 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 413 {object} helpers.Response "Больше лимита типа (FILE_TOO_LARGE)"
+// @Failure 415 {object} helpers.Response "Тип не разрешён или содержимое не совпадает с расширением"
+// @Failure 422 {object} helpers.Response "Файл заражён (FILE_INFECTED)"
+// @Router /api/author/files/upload [post]
+func (h *DocumentHandler) UploadMyDocument(w http.ResponseWriter, r *http.Request) {
+	h.uploadDocuments(w, r, true)
+}
+
+// UpdateMyDocument godoc
+// @Summary Изменить свой документ (автор)
+// @Description Как /api/admin/files/{id}, но только для своего документа (иначе 403 CONTENT_NOT_OWNER); is_public и allow_free_download меняет только администратор.
+// @Tags author
+// @Security ApiKeyAuth
+// @Accept json
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "ID документа"
+// @Param input body models.UpdateDocumentRequest false "Метаданные (JSON)"
+// @Param file formData file false "Новый файл (multipart)"
+// @Success 200 {object} helpers.Response{data=DocumentResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/author/files/{id} [patch]
+func (h *DocumentHandler) UpdateMyDocument(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	h.updateDocument(w, r, userID)
+}
+
+// DeleteMyDocument godoc
+// @Summary Удалить свой документ (автор)
+// @Description Документ уходит в корзину. Публичный документ убирает только администратор.
+// @Tags author
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID документа"
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 403 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/author/files/{id} [delete]
+func (h *DocumentHandler) DeleteMyDocument(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id документа")
+		return
+	}
+
+	if authorContentError(w, h.service.DeleteOwn(r.Context(), userID, id)) {
+		logger.WithCtx(r.Context()).Warn("Документ автора не удалён", zap.Int("doc_id", id), zap.Int("user_id", userID))
+		return
+	}
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Документ перемещён в корзину"})
+}
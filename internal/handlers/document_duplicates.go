@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"edutalks/internal/logger"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+// DuplicateReport godoc
+// @Summary Дубликаты файлов документов
+// @Description Группы документов с одинаковым содержимым файла (SHA-256), включая документы в корзине; больше всего лишнего места сверху. files — сколько копий на диске, wasted_bytes — место под лишние копии; linked — документ ссылается на файл более раннего документа группы (DUPLICATE_UPLOAD_MODE=link). Документы, загруженные до появления проверки, попадают в отчёт после подсчёта их хэшей в фоне.
+// @Tags admin-files
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=DocumentDuplicatesResponse}
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/files/duplicates [get]
+func (h *DocumentHandler) DuplicateReport(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := pageParams(r)
+
+	groups, total, wasted, err := h.dedup.Report(r.Context(), pageSize, (page-1)*pageSize)
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка получения отчёта о дубликатах файлов", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, DocumentDuplicatesResponse{
+		Data:        groups,
+		Pagination:  Pagination{Total: total, Page: page, PageSize: pageSize},
+		WastedBytes: wasted,
+	})
+}
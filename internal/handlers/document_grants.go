@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// hasGrant — персональный доступ пользователя к документу. Проверяется, только когда без него
+// доступа нет (закрытый документ или нет подписки); ошибка проверки — доступа нет.
+func (h *DocumentHandler) hasGrant(r *http.Request, doc *models.Document, user *models.User) bool {
+	if doc.IsPublic && (isActiveSub(user) || doc.AllowFreeDownload) {
+		return false
+	}
+	ok, err := h.service.HasGrant(r.Context(), doc.ID, user.ID)
+	if err != nil {
+		logger.WithCtx(r.Context()).Warn("Не удалось проверить персональный доступ к документу",
+			zap.Int("doc_id", doc.ID), zap.Int("user_id", user.ID), zap.Error(err))
+		return false
+	}
+	if ok {
+		logger.WithCtx(r.Context()).Info("Доступ к документу по персональному доступу",
+			zap.Int("doc_id", doc.ID), zap.Int("user_id", user.ID))
+	}
+	return ok
+}
+
+func grantError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrDocumentGrantExpired):
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeBadRequest, err.Error())
+	case errors.Is(err, services.ErrDocumentNotFound):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeDocNotFound, "Документ не найден")
+	case errors.Is(err, services.ErrDocumentGrantUser):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeUserNotFound, err.Error())
+	case errors.Is(err, services.ErrDocumentGrantNotFound):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeNotFound, err.Error())
+	default:
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+	}
+	return true
+}
+
+// ListGrants godoc
+// @Summary Персональные доступы к документу (только для админа)
+// @Description Пользователи, которым документ выдан лично, включая истёкшие доступы (expires_at в прошлом).
+// @Tags admin-files
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID документа"
+// @Success 200 {object} helpers.Response{data=DocumentGrantsResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/files/{id}/grants [get]
+func (h *DocumentHandler) ListGrants(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id документа")
+		return
+	}
+
+	grants, err := h.service.Grants(r.Context(), id)
+	if grantError(w, err) {
+		if !errors.Is(err, services.ErrDocumentNotFound) {
+			logger.WithCtx(r.Context()).Error("Ошибка получения доступов к документу", zap.Int("doc_id", id), zap.Error(err))
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusOK, DocumentGrantsResponse{Data: grants})
+}
+
+// GrantAccess godoc
+// @Summary Выдать пользователю доступ к документу (только для админа)
+// @Description Пользователь скачивает и просматривает документ, даже если тот закрыт или у пользователя нет подписки (дневной лимит скачиваний действует). Без expires_at — бессрочно; повторная выдача тому же пользователю меняет срок и заметку.
+// @Tags admin-files
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID документа"
+// @Param input body models.GrantDocumentRequest true "Доступ"
+// @Success 200 {object} helpers.Response{data=DocumentGrantResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/files/{id}/grants [post]
+func (h *DocumentHandler) GrantAccess(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id документа")
+		return
+	}
+	var req models.GrantDocumentRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	adminID, _ := middleware.UserIDFromContext(r.Context())
+
+	g, err := h.service.GrantAccess(r.Context(), id, adminID, req)
+	if grantError(w, err) {
+		log.Warn("Доступ к документу не выдан", zap.Int("doc_id", id), zap.Int("user_id", req.UserID), zap.Error(err))
+		return
+	}
+	helpers.JSON(w, http.StatusOK, DocumentGrantResponse{Data: g})
+}
+
+// RevokeAccess godoc
+// @Summary Отозвать доступ пользователя к документу (только для админа)
+// @Tags admin-files
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID документа"
+// @Param userId path int true "ID пользователя"
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/files/{id}/grants/{userId} [delete]
+func (h *DocumentHandler) RevokeAccess(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id документа")
+		return
+	}
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil || userID <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id пользователя")
+		return
+	}
+
+	if grantError(w, h.service.RevokeAccess(r.Context(), id, userID)) {
+		return
+	}
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Доступ отозван"})
+}
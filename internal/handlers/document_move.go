@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+// BulkMoveDocuments godoc
+// @Summary Перенести документы в раздел
+// @Description Переносит документы document_ids и/или все документы раздела from_section_id в раздел section_id (null — убрать из раздела). Выполняется одной транзакцией; результат — по каждому документу.
+// @Tags admin-files
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body models.DocumentBulkMoveRequest true "Документы и целевой раздел"
+// @Success 200 {object} helpers.Response{data=models.DocumentBulkMoveReport}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response "Раздел не найден"
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/files/bulk-move [post]
+func (h *DocumentHandler) BulkMoveDocuments(w http.ResponseWriter, r *http.Request) {
+	var req models.DocumentBulkMoveRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	report, err := h.service.MoveDocuments(r.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMoveNoTargets), errors.Is(err, services.ErrMoveTooMany):
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeValidationFailed, err.Error())
+		case errors.Is(err, services.ErrMoveSectionNotFound):
+			helpers.Fail(w, http.StatusNotFound, helpers.CodeNotFound, err.Error())
+		default:
+			logger.WithCtx(r.Context()).Error("Ошибка переноса документов", zap.Error(err))
+			helpers.Error(w, http.StatusInternalServerError, "Не удалось перенести документы")
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusOK, report)
+}
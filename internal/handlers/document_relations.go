@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// publicLinks — связи, которые можно показать без авторизации (только с публичными документами).
+func publicLinks(links []models.DocumentLink) []models.DocumentLink {
+	var out []models.DocumentLink
+	for _, l := range links {
+		if l.IsPublic {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func relationError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrInvalidDocumentRelation):
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrDocumentNotFound):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeDocNotFound, "Документ не найден")
+	case errors.Is(err, services.ErrDocumentRelationNotFound):
+		helpers.Error(w, http.StatusNotFound, "Связь не найдена")
+	case errors.Is(err, services.ErrDocumentRelationExists):
+		helpers.Error(w, http.StatusConflict, err.Error())
+	default:
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+	}
+	return true
+}
+
+// ListRelations godoc
+// @Summary Связи документа (только для админа)
+// @Description relation развёрнут к документу: supersedes / superseded_by, amended_by / amends, related
+// @Tags admin-files
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID документа"
+// @Success 200 {object} helpers.Response{data=DocumentLinksResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/files/{id}/relations [get]
+func (h *DocumentHandler) ListRelations(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id документа")
+		return
+	}
+
+	links, err := h.service.Relations(r.Context(), id)
+	if relationError(w, err) {
+		if !errors.Is(err, services.ErrDocumentNotFound) {
+			logger.WithCtx(r.Context()).Error("Ошибка получения связей документа", zap.Int("doc_id", id), zap.Error(err))
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusOK, DocumentLinksResponse{Data: links})
+}
+
+// AddRelation godoc
+// @Summary Связать документ с другим (только для админа)
+// @Description «Документ {id} supersedes related_id» — новый приказ заменяет старый; amended_by — в документ внесены изменения; related — см. также
+// @Tags admin-files
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID документа"
+// @Param input body models.CreateDocumentRelationRequest true "Связь"
+// @Success 201 {object} helpers.Response{data=DocumentRelationResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 409 {object} helpers.Response
+// @Router /api/admin/files/{id}/relations [post]
+func (h *DocumentHandler) AddRelation(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id документа")
+		return
+	}
+
+	var req models.CreateDocumentRelationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "Невалидный JSON")
+		return
+	}
+
+	rel, err := h.service.AddRelation(r.Context(), id, req)
+	if relationError(w, err) {
+		log.Warn("Связь документов не добавлена", zap.Int("doc_id", id), zap.Int("related_id", req.RelatedID), zap.Error(err))
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, DocumentRelationResponse{Data: rel})
+}
+
+// DeleteRelation godoc
+// @Summary Удалить связь документа (только для админа)
+// @Tags admin-files
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID документа"
+// @Param relationId path int true "ID связи"
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/files/{id}/relations/{relationId} [delete]
+func (h *DocumentHandler) DeleteRelation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id документа")
+		return
+	}
+	relationID, err := strconv.ParseInt(vars["relationId"], 10, 64)
+	if err != nil || relationID <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id связи")
+		return
+	}
+
+	if relationError(w, h.service.DeleteRelation(r.Context(), id, relationID)) {
+		return
+	}
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Связь удалена"})
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+	"edutalks/internal/utils/validate"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// UpdateDocument godoc
+// @Summary Изменить документ (только для админа)
+// @Description Частичная правка: не переданные поля не меняются. JSON — только метаданные; multipart/form-data — те же поля и необязательный file для замены файла (проверки как при загрузке). Замена файла публичного документа попадает в блок «Обновлено» рассылки.
+// @Tags admin-files
+// @Security ApiKeyAuth
+// @Accept json
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "ID документа"
+// @Param input body models.UpdateDocumentRequest false "Метаданные (JSON)"
+// @Param file formData file false "Новый файл (multipart)"
+// @Success 200 {object} helpers.Response{data=DocumentResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 413 {object} helpers.Response "Больше лимита типа (FILE_TOO_LARGE)"
+// @Failure 415 {object} helpers.Response "Тип не разрешён или содержимое не совпадает с расширением"
+// @Failure 422 {object} helpers.Response "Файл заражён (FILE_INFECTED)"
+// @Failure 503 {object} helpers.Response "Антивирус недоступен"
+// @Router /api/admin/files/{id} [patch]
+func (h *DocumentHandler) UpdateDocument(w http.ResponseWriter, r *http.Request) {
+	h.updateDocument(w, r, 0)
+}
+
+// updateDocument — правка документа; ownerID > 0 — из кабинета автора: только свой документ
+// и без смены is_public/allow_free_download (проверяет DocumentService.UpdateOwn).
+func (h *DocumentHandler) updateDocument(w http.ResponseWriter, r *http.Request, ownerID int) {
+	log := logger.WithCtx(r.Context())
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id документа")
+		return
+	}
+
+	var req models.UpdateDocumentRequest
+	var replacement *multipart.FileHeader
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "multipart/form-data" {
+		if !h.parseUploadForm(w, r) {
+			return
+		}
+		var ok bool
+		if req, ok = updateRequestFromForm(w, r.MultipartForm); !ok {
+			return
+		}
+		if fhs := r.MultipartForm.File["file"]; len(fhs) > 0 {
+			replacement = fhs[0]
+		}
+		if !validRequest(w, r, &req) {
+			return
+		}
+	} else if !decodeValid(w, r, &req) {
+		return
+	}
+
+	var before *models.Document
+	if ownerID > 0 {
+		before, err = h.service.OwnDocument(r.Context(), ownerID, id)
+	} else {
+		before, err = h.service.GetDocumentByID(r.Context(), id)
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrNotContentOwner) {
+			authorContentError(w, err)
+			return
+		}
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeDocNotFound, "Документ не найден")
+		return
+	}
+
+	var file *models.DocumentFile
+	if replacement != nil {
+		var fail *uploadFailure
+		if file, fail = h.saveUpload(r.Context(), replacement); fail != nil {
+			fail.write(w)
+			return
+		}
+	}
+
+	var doc *models.Document
+	if ownerID > 0 {
+		doc, err = h.service.UpdateOwn(r.Context(), ownerID, id, req, file)
+	} else {
+		doc, err = h.service.UpdateDocument(r.Context(), id, req, file)
+	}
+	if err != nil {
+		if file != nil {
+			_ = os.Remove(file.Filepath)
+		}
+		if errors.Is(err, services.ErrNotContentOwner) || errors.Is(err, services.ErrPublishNotAllowed) {
+			authorContentError(w, err)
+			return
+		}
+		if errors.Is(err, services.ErrDocumentNotFound) {
+			helpers.Fail(w, http.StatusNotFound, helpers.CodeDocNotFound, "Документ не найден")
+			return
+		}
+		log.Error("Ошибка обновления документа", zap.Int("doc_id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+
+	// старый файл больше не нужен, если им не пользуются другие документы (DUPLICATE_UPLOAD_MODE=link);
+	// не удалился — документ уже обновлён, только пишем в лог
+	if file != nil && before.Filepath != doc.Filepath {
+		if err := h.dedup.RemoveFileIfUnused(r.Context(), before.Filepath); err != nil {
+			log.Warn("Не удалось удалить прежний файл документа", zap.String("filepath", before.Filepath), zap.Error(err))
+		}
+	}
+
+	log.Info("Документ обновлён", zap.Int("doc_id", id), zap.Bool("file_replaced", file != nil))
+	helpers.JSON(w, http.StatusOK, DocumentResponse{Data: doc})
+}
+
+// updateRequestFromForm — поля правки из multipart-формы: переданное поле (даже пустое) меняется.
+func updateRequestFromForm(w http.ResponseWriter, form *multipart.Form) (models.UpdateDocumentRequest, bool) {
+	var req models.UpdateDocumentRequest
+	str := func(key string) *string {
+		if vs, ok := form.Value[key]; ok && len(vs) > 0 {
+			return &vs[0]
+		}
+		return nil
+	}
+	req.Title = str("title")
+	req.Description = str("description")
+	req.Category = str("category")
+
+	for key, dst := range map[string]**bool{"is_public": &req.IsPublic, "allow_free_download": &req.AllowFreeDownload} {
+		raw := str(key)
+		if raw == nil {
+			continue
+		}
+		v, err := strconv.ParseBool(*raw)
+		if err != nil {
+			helpers.ValidationError(w, validate.Errors{{Field: key, Message: "ожидается true или false"}})
+			return req, false
+		}
+		*dst = &v
+	}
+	return req, true
+}
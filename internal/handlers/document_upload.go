@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/events"
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+// maxUploadFiles — сколько файлов принимается в одном запросе files[].
+const maxUploadFiles = 20
+
+const uploadDir = "uploaded"
+
+// uploadFailure — отказ по одному загружаемому файлу: статус и тело ошибки для ответа.
+type uploadFailure struct {
+	Status  int
+	Code    helpers.ErrorCode
+	Message string
+	Details any
+}
+
+func (f *uploadFailure) write(w http.ResponseWriter) {
+	helpers.FailWithDetails(w, f.Status, f.Code, f.Message, f.Details)
+}
+
+// uploadFileResult — результат по одному файлу пакетной загрузки.
+type uploadFileResult struct {
+	Filename  string             `json:"filename"`
+	Status    string             `json:"status"` // created | failed
+	ID        int                `json:"id,omitempty"`
+	Title     string             `json:"title,omitempty"`
+	Duplicate *UploadDuplicate   `json:"duplicate,omitempty"`
+	Error     *helpers.ErrorBody `json:"error,omitempty"`
+}
+
+type uploadBatchReport struct {
+	Total   int                `json:"total"`
+	Created int                `json:"created"`
+	Failed  int                `json:"failed"`
+	Results []uploadFileResult `json:"results"`
+}
+
+// uploadTemplate — общие метаданные загрузки из полей формы; false — ответ с ошибкой уже записан.
+func (h *DocumentHandler) uploadTemplate(w http.ResponseWriter, r *http.Request) (models.Document, bool) {
+	log := logger.WithCtx(r.Context())
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		log.Warn("Нет user_id в контексте при загрузке документа")
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return models.Document{}, false
+	}
+
+	largePrintURL, audioURL, ok := formAccessibilityURLs(r)
+	if !ok {
+		log.Warn("Некорректная ссылка доступности при загрузке документа")
+		helpers.Error(w, http.StatusBadRequest, services.ErrInvalidAccessibilityURL.Error())
+		return models.Document{}, false
+	}
+
+	adoptedAt, err := services.ParseNormativeDate(r.FormValue("adopted_at"))
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "adopted_at: "+err.Error())
+		return models.Document{}, false
+	}
+	effectiveAt, err := services.ParseNormativeDate(r.FormValue("effective_at"))
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "effective_at: "+err.Error())
+		return models.Document{}, false
+	}
+
+	var sectionIDPtr *int
+	if s := r.FormValue("section_id"); s != "" {
+		if sid, convErr := strconv.Atoi(s); convErr == nil {
+			sectionIDPtr = &sid
+		} else {
+			log.Warn("Невалидный section_id", zap.String("raw", s))
+		}
+	}
+
+	return models.Document{
+		UserID:            userID,
+		Title:             r.FormValue("title"),
+		Description:       r.FormValue("description"),
+		IsPublic:          strings.ToLower(r.FormValue("is_public")) == "true",
+		Category:          r.FormValue("category"),
+		SectionID:         sectionIDPtr,
+		AllowFreeDownload: strings.ToLower(r.FormValue("allow_free_download")) == "true",
+		HasTextLayer:      strings.ToLower(r.FormValue("has_text_layer")) == "true",
+		LargePrintURL:     largePrintURL,
+		AudioURL:          audioURL,
+		DocNumber:         formString(r, "doc_number"),
+		IssuingAuthority:  formString(r, "issuing_authority"),
+		AdoptedAt:         adoptedAt,
+		EffectiveAt:       effectiveAt,
+	}, true
+}
+
+// storeUpload — сохранение файла (saveUpload) и запись документа в БД. Если такой файл
+// уже есть у других документов, возвращает их; в режиме link копия удаляется, а документ
+// ссылается на файл самого раннего из них. При отказе новый файл на диске не остаётся.
+func (h *DocumentHandler) storeUpload(ctx context.Context, fh *multipart.FileHeader, tmpl models.Document) (*models.Document, *UploadDuplicate, *uploadFailure) {
+	log := logger.WithCtx(ctx)
+
+	file, fail := h.saveUpload(ctx, fh)
+	if fail != nil {
+		return nil, nil, fail
+	}
+
+	doc := tmpl
+	doc.Filename = file.Filename
+	doc.Filepath = file.Filepath
+	doc.UploadedAt = time.Now()
+	doc.ScanStatus = file.ScanStatus
+	doc.ScannedAt = file.ScannedAt
+	doc.SHA256 = file.SHA256
+	doc.SizeBytes = file.SizeBytes
+
+	dup := h.findDuplicates(ctx, file)
+	if dup != nil && h.cfg.Get().LinkDuplicateUploads() && fileExists(dup.Documents[0].Filepath) {
+		_ = os.Remove(file.Filepath)
+		doc.Filepath = dup.Documents[0].Filepath
+		dup.Linked = true
+	}
+
+	log.Info("Сохраняем метаданные документа в БД",
+		zap.String("stored_path", file.Filepath),
+		zap.String("original_filename", file.Filename),
+		zap.Int("user_id", doc.UserID),
+	)
+
+	id, err := h.service.Upload(ctx, &doc)
+	if err != nil {
+		if dup == nil || !dup.Linked {
+			_ = os.Remove(file.Filepath)
+		}
+		if services.IsNormativeValidationError(err) {
+			return nil, nil, &uploadFailure{Status: http.StatusBadRequest, Code: helpers.CodeBadRequest, Message: err.Error()}
+		}
+		log.Error("Ошибка сохранения документа в БД", zap.Error(err))
+		return nil, nil, &uploadFailure{Status: http.StatusInternalServerError, Code: helpers.CodeInternal, Message: "Ошибка при сохранении документа"}
+	}
+	doc.ID = id
+	return &doc, dup, nil
+}
+
+// findDuplicates — документы с тем же содержимым файла; nil — дубликатов нет или их не удалось
+// найти (загрузка от этого не отказывает).
+func (h *DocumentHandler) findDuplicates(ctx context.Context, file *models.DocumentFile) *UploadDuplicate {
+	docs, err := h.dedup.FindDuplicates(ctx, file.SHA256)
+	if err != nil {
+		logger.WithCtx(ctx).Warn("Не удалось проверить загружаемый файл на дубликаты", zap.Error(err))
+		return nil
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	ids := make([]int, len(docs))
+	for i, d := range docs {
+		ids[i] = d.ID
+	}
+	logger.WithCtx(ctx).Warn("Загружен файл, который уже есть у других документов",
+		zap.String("filename", file.Filename), zap.String("sha256", file.SHA256), zap.Ints("duplicate_of", ids))
+	return &UploadDuplicate{Documents: docs}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// saveUpload — проверка политикой загрузки, сохранение на диск и антивирус для одного файла.
+// При отказе файл на диске не остаётся.
+func (h *DocumentHandler) saveUpload(ctx context.Context, fh *multipart.FileHeader) (*models.DocumentFile, *uploadFailure) {
+	log := logger.WithCtx(ctx)
+
+	file, err := fh.Open()
+	if err != nil {
+		log.Warn("Не удалось открыть файл из формы", zap.String("filename", fh.Filename), zap.Error(err))
+		return nil, &uploadFailure{Status: http.StatusBadRequest, Code: helpers.CodeBadRequest, Message: "Не удалось прочитать файл"}
+	}
+	defer file.Close()
+
+	if err := h.uploads.Check(fh.Filename, fh.Size, file); err != nil {
+		log.Warn("Загрузка документа отклонена политикой", zap.String("filename", fh.Filename), zap.Int64("size", fh.Size), zap.Error(err))
+		var ue *services.UploadError
+		switch {
+		case errors.As(err, &ue) && errors.Is(err, services.ErrUploadTooLarge):
+			return nil, &uploadFailure{Status: http.StatusRequestEntityTooLarge, Code: helpers.CodeFileTooLarge, Message: err.Error(),
+				Details: map[string]any{"extension": ue.Ext, "max_bytes": ue.MaxBytes}}
+		case errors.As(err, &ue) && errors.Is(err, services.ErrUploadType):
+			return nil, &uploadFailure{Status: http.StatusUnsupportedMediaType, Code: helpers.CodeFileTypeNotAllowed, Message: err.Error(),
+				Details: map[string]any{"extension": ue.Ext, "allowed": ue.Allowed}}
+		case errors.As(err, &ue):
+			return nil, &uploadFailure{Status: http.StatusUnsupportedMediaType, Code: helpers.CodeFileContentMismatch, Message: err.Error()}
+		default:
+			return nil, &uploadFailure{Status: http.StatusBadRequest, Code: helpers.CodeBadRequest, Message: "Не удалось прочитать файл"}
+		}
+	}
+
+	saveFailed := &uploadFailure{Status: http.StatusInternalServerError, Code: helpers.CodeInternal, Message: "Ошибка при сохранении файла"}
+	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
+		log.Error("Не удалось создать директорию загрузки", zap.Error(err))
+		return nil, saveFailed
+	}
+
+	original := filepath.Base(fh.Filename)
+	// наносекунды — файлы одного пакета с одинаковыми именами не перезапишут друг друга
+	fullPath := filepath.Join(uploadDir, fmt.Sprintf("%d_%s", time.Now().UnixNano(), original))
+
+	dst, err := os.Create(fullPath)
+	if err != nil {
+		log.Error("Не удалось создать файл на диске", zap.String("path", fullPath), zap.Error(err))
+		return nil, saveFailed
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(dst, hash), file)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(fullPath)
+		log.Error("Ошибка записи файла на диск", zap.String("path", fullPath), zap.Error(err))
+		return nil, saveFailed
+	}
+
+	scan, fail := scanSaved(ctx, h.scanner, fullPath)
+	if fail != nil {
+		return nil, fail
+	}
+
+	saved := &models.DocumentFile{
+		Filename:   original,
+		Filepath:   fullPath,
+		ScanStatus: scan.Status,
+		SHA256:     hex.EncodeToString(hash.Sum(nil)),
+		SizeBytes:  size,
+	}
+	if scan.Status != models.DocumentScanSkipped {
+		now := time.Now()
+		saved.ScannedAt = &now
+	}
+	return saved, nil
+}
+
+// parseUploadForm — multipart-форма с ограничением тела по самому большому лимиту типа;
+// false — ответ с ошибкой уже записан.
+func (h *DocumentHandler) parseUploadForm(w http.ResponseWriter, r *http.Request) bool {
+	log := logger.WithCtx(r.Context())
+
+	// тело — не больше самого большого лимита типа (+1 МБ на поля формы)
+	maxBody := h.uploads.MaxBytes() + 1<<20
+	r.Body = http.MaxBytesReader(w, r.Body, maxBody)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			log.Warn("Загрузка документа: превышен размер запроса", zap.Int64("limit", maxBody))
+			helpers.FailWithDetails(w, http.StatusRequestEntityTooLarge, helpers.CodeFileTooLarge, "Файл слишком большой",
+				map[string]int64{"max_bytes": h.uploads.MaxBytes()})
+			return false
+		}
+		log.Warn("Ошибка разбора формы при загрузке документа", zap.Error(err))
+		helpers.Error(w, http.StatusBadRequest, "Ошибка разбора формы")
+		return false
+	}
+	return true
+}
+
+// uploadBatch — загрузка нескольких файлов с общими метаданными. Файлы обрабатываются
+// независимо: отказ по одному не отменяет остальные. Подписчикам — одна запись о пакете.
+func (h *DocumentHandler) uploadBatch(w http.ResponseWriter, r *http.Request, files []*multipart.FileHeader, tmpl models.Document) {
+	log := logger.WithCtx(r.Context())
+	titles := r.MultipartForm.Value["titles[]"]
+
+	log.Info("Пакетная загрузка документов",
+		zap.Int("files", len(files)),
+		zap.String("category", tmpl.Category),
+		zap.Bool("is_public", tmpl.IsPublic),
+		zap.Any("section_id", tmpl.SectionID),
+		zap.Int("user_id", tmpl.UserID),
+	)
+
+	report := uploadBatchReport{Total: len(files)}
+	var created []*models.Document
+	for i, fh := range files {
+		fileTmpl := tmpl
+		fileTmpl.Title = uploadTitle(fh.Filename, titles, i)
+
+		res := uploadFileResult{Filename: fh.Filename}
+		doc, dup, fail := h.storeUpload(r.Context(), fh, fileTmpl)
+		if fail != nil {
+			res.Status = "failed"
+			res.Error = &helpers.ErrorBody{Code: fail.Code, Message: fail.Message, Details: fail.Details}
+			report.Failed++
+		} else {
+			res.Status, res.ID, res.Title, res.Duplicate = "created", doc.ID, doc.Title, dup
+			report.Created++
+			created = append(created, doc)
+		}
+		report.Results = append(report.Results, res)
+	}
+
+	if len(created) > 0 {
+		ids := make([]int, len(created))
+		docTitles := make([]string, len(created))
+		for i, d := range created {
+			ids[i], docTitles[i] = d.ID, d.Title
+		}
+		h.notifier.AddDocumentsForBatch(context.WithoutCancel(r.Context()), ids, docTitles, tmpl.SectionID)
+		events.Publish(r.Context(), events.DocumentsAdded, &tmpl.UserID, map[string]any{
+			"document_ids": ids,
+			"titles":       docTitles,
+			"section_id":   tmpl.SectionID,
+		})
+	}
+
+	log.Info("Пакетная загрузка завершена", zap.Int("created", report.Created), zap.Int("failed", report.Failed))
+	status := http.StatusOK
+	if report.Created == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	helpers.JSON(w, status, report)
+}
+
+// uploadTitle — название i-го файла пакета: из titles[] или имя файла без расширения.
+func uploadTitle(filename string, titles []string, i int) string {
+	if i < len(titles) {
+		if t := strings.TrimSpace(titles[i]); t != "" {
+			return t
+		}
+	}
+	base := filepath.Base(filename)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
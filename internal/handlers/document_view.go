@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	helpers "edutalks/internal/utils/helpers"
+	"edutalks/internal/utils/pdf"
+
+	"go.uber.org/zap"
+)
+
+// ViewDocument godoc
+// @Summary Просмотр PDF в браузере
+// @Tags files
+// @Security ApiKeyAuth
+// @Produce application/pdf
+// @Description Отдаёт PDF с Content-Disposition: inline (для встроенного просмотрщика). Доступ — как у скачивания. Подписчикам на страницы наносится водяной знак с почтой и ID (отключается VIEW_WATERMARK=false). Поддерживает Range.
+// @Param id path int true "ID документа"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Failure 401 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 415 {object} helpers.Response "Документ не PDF"
+// @Failure 500 {object} helpers.Response "Водяной знак не нанесён (зашифрованный или повреждённый PDF)"
+// @Router /api/files/{id}/view [get]
+func (h *DocumentHandler) ViewDocument(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	user, doc, ok := h.accessibleDocument(w, r)
+	if !ok {
+		return
+	}
+
+	f, err := os.Open(doc.Filepath)
+	if err != nil {
+		log.Error("Файл не найден на диске", zap.String("filepath", doc.Filepath), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Файл не найден")
+		return
+	}
+	defer f.Close()
+
+	head := make([]byte, 1024)
+	n, _ := io.ReadFull(f, head)
+	if !bytes.Contains(head[:n], []byte("%PDF-")) {
+		helpers.Fail(w, http.StatusUnsupportedMediaType, helpers.CodeFileTypeNotAllowed, "Просмотр в браузере доступен только для PDF")
+		return
+	}
+	_, _ = f.Seek(0, io.SeekStart)
+
+	encoded := url.PathEscape(doc.Filename)
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q; filename*=UTF-8''%s", doc.Filename, encoded))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	// подписчику отдаётся персональная копия — не кэшируем нигде
+	w.Header().Set("Cache-Control", "private, no-store")
+
+	cfg := h.cfg.Get()
+	watermark := user.Role != "admin" && isActiveSub(user) && !strings.EqualFold(strings.TrimSpace(cfg.ViewWatermark), "false")
+	if watermark {
+		// лишний байт сверх лимита — чтобы Watermark отверг файл, а не обрезанную копию
+		src, err := io.ReadAll(io.LimitReader(f, pdf.MaxInputSize+1))
+		if err != nil {
+			log.Error("Ошибка чтения файла", zap.String("filepath", doc.Filepath), zap.Error(err))
+			helpers.Error(w, http.StatusInternalServerError, "Не удалось прочитать файл")
+			return
+		}
+		marked, err := pdf.Watermark(src, viewWatermarkText(user, cfg))
+		if err != nil {
+			// без знака не отдаём: иначе копию без знака получит любой подписчик
+			log.Error("Не удалось нанести водяной знак", zap.Int("doc_id", doc.ID), zap.Error(err))
+			helpers.Error(w, http.StatusInternalServerError, "Не удалось подготовить документ к просмотру")
+			return
+		}
+		http.ServeContent(w, r, doc.Filename, time.Time{}, bytes.NewReader(marked))
+		log.Info("Документ открыт для просмотра", zap.Int("user_id", user.ID), zap.Int("doc_id", doc.ID), zap.Bool("watermark", true))
+		return
+	}
+
+	http.ServeContent(w, r, doc.Filename, doc.UploadedAt, f)
+	log.Info("Документ открыт для просмотра", zap.Int("user_id", user.ID), zap.Int("doc_id", doc.ID), zap.Bool("watermark", false))
+}
+
+// viewWatermarkText — почта и ID крупно, имя и дата — строкой внизу страницы.
+func viewWatermarkText(u *models.User, cfg *config.Config) pdf.WatermarkText {
+	contact := u.Email
+	if contact == "" {
+		contact = u.Username
+	}
+	site := strings.TrimRight(strings.TrimSpace(cfg.FrontendURL), "/")
+	if site == "" {
+		site = "https://edutalks.ru"
+	}
+	site = strings.TrimPrefix(strings.TrimPrefix(site, "https://"), "http://")
+
+	name := strings.TrimSpace(u.FullName)
+	if name == "" {
+		name = u.Username
+	}
+	return pdf.WatermarkText{
+		Diagonal: contact + " / ID " + strconv.Itoa(u.ID),
+		Footer: fmt.Sprintf("%s: %s <%s>, ID %d, %s. Распространение запрещено.",
+			site, name, contact, u.ID, time.Now().Format("02.01.2006 15:04")),
+	}
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+type DownloadQuotaHandler struct {
+	quotas *services.DownloadQuotaService
+	users  *services.AuthService
+}
+
+func NewDownloadQuotaHandler(quotas *services.DownloadQuotaService, users *services.AuthService) *DownloadQuotaHandler {
+	return &DownloadQuotaHandler{quotas: quotas, users: users}
+}
+
+// writeQuotaExceeded — 429 с состоянием лимита и Retry-After до сброса счётчика.
+func writeQuotaExceeded(w http.ResponseWriter, q *models.DownloadQuota) {
+	sec := int(math.Ceil(time.Until(q.ResetsAt).Seconds()))
+	if sec < 1 {
+		sec = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(sec))
+	helpers.FailWithDetails(w, http.StatusTooManyRequests, helpers.CodeDownloadQuotaExceeded,
+		"Дневной лимит скачиваний исчерпан", q)
+}
+
+// MyQuota godoc
+// @Summary Мой дневной лимит скачиваний
+// @Description Тариф (free, subscriber или план оплаты), лимит и остаток на сегодня; limit и remaining null — без ограничения. Счётчик сбрасывается в полночь по Москве.
+// @Tags profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=models.DownloadQuota}
+// @Failure 401 {object} helpers.Response
+// @Router /api/profile/download-quota [get]
+func (h *DownloadQuotaHandler) MyQuota(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	h.writeStatus(w, r, userID)
+}
+
+// AdminQuota godoc
+// @Summary Лимит скачиваний пользователя
+// @Tags admin-users
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} helpers.Response{data=models.DownloadQuota}
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/users/{id}/download-quota [get]
+func (h *DownloadQuotaHandler) AdminQuota(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+	h.writeStatus(w, r, id)
+}
+
+// SetOverride godoc
+// @Summary Индивидуальный лимит скачиваний
+// @Description Заменяет тарифный дневной лимит пользователя. daily_limit: null — без ограничения, 0 — скачивание запрещено.
+// @Tags admin-users
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param input body models.DownloadQuotaOverrideRequest true "Лимит"
+// @Success 200 {object} helpers.Response{data=models.DownloadQuota}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/users/{id}/download-quota [put]
+func (h *DownloadQuotaHandler) SetOverride(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	id, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+	var req models.DownloadQuotaOverrideRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	if _, err := h.users.GetUserByID(r.Context(), id); err != nil {
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeUserNotFound, "Пользователь не найден")
+		return
+	}
+
+	adminID, _ := middleware.UserIDFromContext(r.Context())
+	if err := h.quotas.SetOverride(r.Context(), id, req.DailyLimit, adminID); err != nil {
+		log.Error("Ошибка установки лимита скачиваний", zap.Int("user_id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+
+	log.Info("Установлен индивидуальный лимит скачиваний", zap.Int("user_id", id), zap.Intp("daily_limit", req.DailyLimit), zap.Int("admin_id", adminID))
+	h.writeStatus(w, r, id)
+}
+
+// ClearOverride godoc
+// @Summary Вернуть тарифный лимит скачиваний
+// @Tags admin-users
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} helpers.Response{data=models.DownloadQuota}
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/users/{id}/download-quota [delete]
+func (h *DownloadQuotaHandler) ClearOverride(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	id, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+	removed, err := h.quotas.ClearOverride(r.Context(), id)
+	if err != nil {
+		log.Error("Ошибка сброса лимита скачиваний", zap.Int("user_id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+
+	log.Info("Индивидуальный лимит скачиваний снят", zap.Int("user_id", id), zap.Bool("removed", removed))
+	h.writeStatus(w, r, id)
+}
+
+func (h *DownloadQuotaHandler) writeStatus(w http.ResponseWriter, r *http.Request, userID int) {
+	u, err := h.users.GetUserByID(r.Context(), userID)
+	if err != nil {
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeUserNotFound, "Пользователь не найден")
+		return
+	}
+	q, err := h.quotas.Status(r.Context(), u)
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка получения лимита скачиваний", zap.Int("user_id", userID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, q)
+}
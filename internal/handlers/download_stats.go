@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+type DownloadStatsHandler struct {
+	svc *services.DownloadStatsService
+}
+
+func NewDownloadStatsHandler(svc *services.DownloadStatsService) *DownloadStatsHandler {
+	return &DownloadStatsHandler{svc: svc}
+}
+
+// parseDownloadFilter — общие фильтры: document_id, from/to (YYYY-MM-DD или RFC3339).
+func parseDownloadFilter(w http.ResponseWriter, r *http.Request) (models.DownloadStatsFilter, bool) {
+	q := r.URL.Query()
+	var f models.DownloadStatsFilter
+
+	if v := q.Get("document_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный параметр document_id")
+			return f, false
+		}
+		f.DocumentID = &id
+	}
+
+	var err error
+	if f.From, err = parseDateParam(q.Get("from"), false); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "Некорректный параметр from")
+		return f, false
+	}
+	if f.To, err = parseDateParam(q.Get("to"), true); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "Некорректный параметр to")
+		return f, false
+	}
+	return f, true
+}
+
+// Stats godoc
+// @Summary Статистика скачиваний документов
+// @Description Количество скачиваний по документам (популярные сверху) и временной ряд по дням
+// @Tags admin-stats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param document_id query int false "Только этот документ"
+// @Param from query string false "Начало периода (YYYY-MM-DD или RFC3339)"
+// @Param to query string false "Конец периода (дата включительно)"
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=DownloadStatsResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/stats/downloads [get]
+func (h *DownloadStatsHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	f, ok := parseDownloadFilter(w, r)
+	if !ok {
+		return
+	}
+	page, pageSize := pageParams(r)
+
+	items, total, series, err := h.svc.Stats(r.Context(), f, pageSize, (page-1)*pageSize)
+	if err != nil {
+		log.Error("Ошибка получения статистики скачиваний", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить статистику скачиваний")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, DownloadStatsResponse{
+		Data:       items,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+		Series:     series,
+	})
+}
+
+// Top godoc
+// @Summary Топ скачиваемых документов
+// @Tags admin-stats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param from query string false "Начало периода (YYYY-MM-DD или RFC3339)"
+// @Param to query string false "Конец периода (дата включительно)"
+// @Param limit query int false "Сколько документов вернуть (по умолчанию 10)"
+// @Success 200 {object} helpers.Response{data=[]models.DocumentDownloadStat}
+// @Failure 400 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/stats/downloads/top [get]
+func (h *DownloadStatsHandler) Top(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	f, ok := parseDownloadFilter(w, r)
+	if !ok {
+		return
+	}
+	limit := parseIntQuery(r, "limit", 10)
+
+	items, err := h.svc.Top(r.Context(), f, limit)
+	if err != nil {
+		log.Error("Ошибка получения топа скачиваний", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить топ документов")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, items)
+}
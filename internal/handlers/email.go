@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,10 +17,11 @@ import (
 
 type EmailHandler struct {
 	emailTokenService *services.EmailTokenService
+	cfg               *config.Holder
 }
 
-func NewEmailHandler(emailTokenService *services.EmailTokenService) *EmailHandler {
-	return &EmailHandler{emailTokenService: emailTokenService}
+func NewEmailHandler(emailTokenService *services.EmailTokenService, cfg *config.Holder) *EmailHandler {
+	return &EmailHandler{emailTokenService: emailTokenService, cfg: cfg}
 }
 
 // VerifyEmail godoc
@@ -29,8 +31,8 @@ func NewEmailHandler(emailTokenService *services.EmailTokenService) *EmailHandle
 // @Accept json
 // @Produce json
 // @Param token query string true "Токен подтверждения"
-// @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Success 302 {string} string "Редирект на страницу фронта"
+// @Failure 400 {object} helpers.Response
 // @Router /api/verify-email [get]
 func (h *EmailHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -38,7 +40,7 @@ func (h *EmailHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 	token := r.URL.Query().Get("token")
 	if strings.TrimSpace(token) == "" {
 		log.Warn("VerifyEmail: отсутствует токен")
-		helpers.Error(w, http.StatusBadRequest, "Токен отсутствует")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeAuthLinkInvalid, "Токен отсутствует")
 		return
 	}
 
@@ -54,15 +56,11 @@ func (h *EmailHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 		default:
 			msg = "Внутренняя ошибка сервиса."
 		}
-		helpers.Error(w, http.StatusBadRequest, msg)
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeAuthLinkInvalid, msg)
 		return
 	}
 
-	cfg, _ := config.LoadConfig()
-	base := strings.TrimRight(strings.TrimSpace(cfg.FrontendURL), "/")
-	if base == "" {
-		base = "https://edutalks.ru"
-	}
+	base := h.cfg.Get().FrontendBaseURL()
 	redirectURL := base + "/verify-email?status=success"
 
 	log.Info("VerifyEmail: email подтверждён, редирект на фронт", zap.String("redirect_to", redirectURL))
@@ -75,11 +73,11 @@ func (h *EmailHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param input body map[string]string true "Email пользователя"
-// @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 429 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 429 {object} helpers.Response "data: retry_after_sec, next_allowed_at, token_valid_for_sec, token_expires_at"
+// @Failure 500 {object} helpers.Response
 // @Router /api/resend-verification [post]
 func (h *AuthHandler) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -97,23 +95,35 @@ func (h *AuthHandler) ResendVerificationEmail(w http.ResponseWriter, r *http.Req
 	user, err := h.authService.GetUserByEmail(r.Context(), req.Email)
 	if err != nil {
 		log.Warn("ResendVerificationEmail: пользователь не найден", zap.String("email_masked", maskEmail(req.Email)))
-		helpers.Error(w, http.StatusNotFound, "Пользователь не найден")
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeUserNotFound, "Пользователь не найден")
 		return
 	}
 
 	// Лимит повторной отправки
 	if lastToken, err := h.emailTokenService.GetLastTokenByUserID(r.Context(), user.ID); err == nil {
-		nextAllowed := lastToken.CreatedAt.Add(5 * time.Minute)
+		nextAllowed := lastToken.CreatedAt.Add(h.emailTokenService.ResendCooldown())
 		if nextAllowed.After(time.Now()) {
 			remaining := int(time.Until(nextAllowed).Seconds())
+			validFor := int(time.Until(lastToken.ExpiresAt).Seconds())
+			if validFor < 0 {
+				validFor = 0
+			}
 			log.Info("ResendVerificationEmail: превышен лимит, слишком рано",
 				zap.Time("created_at", lastToken.CreatedAt),
 				zap.Time("next_allowed", nextAllowed),
 				zap.Int("remaining_sec", remaining),
+				zap.Int("token_valid_for_sec", validFor),
 				zap.Int("user_id", user.ID),
 			)
-			helpers.Error(w, http.StatusTooManyRequests,
-				fmt.Sprintf("Вы можете повторно запросить письмо через %d секунд", remaining))
+			w.Header().Set("Retry-After", strconv.Itoa(remaining))
+			helpers.ErrorWithData(w, http.StatusTooManyRequests,
+				fmt.Sprintf("Вы можете повторно запросить письмо через %d секунд", remaining),
+				map[string]interface{}{
+					"retry_after_sec":     remaining,
+					"next_allowed_at":     nextAllowed.UTC(),
+					"token_valid_for_sec": validFor, // ссылка из предыдущего письма ещё действует столько
+					"token_expires_at":    lastToken.ExpiresAt.UTC(),
+				})
 			return
 		}
 	}
@@ -134,7 +144,7 @@ func (h *AuthHandler) ResendVerificationEmail(w http.ResponseWriter, r *http.Req
 	}
 
 	log.Info("ResendVerificationEmail: письмо отправлено повторно", zap.Int("user_id", user.ID))
-	helpers.JSON(w, http.StatusOK, map[string]string{
-		"message": "Письмо с подтверждением отправлено повторно",
+	helpers.JSON(w, http.StatusOK, MessageResponse{
+		Message: "Письмо с подтверждением отправлено повторно",
 	})
 }
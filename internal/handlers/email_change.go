@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+type EmailChangeHandler struct {
+	svc *services.EmailChangeService
+	cfg *config.Holder
+}
+
+func NewEmailChangeHandler(svc *services.EmailChangeService, cfg *config.Holder) *EmailChangeHandler {
+	return &EmailChangeHandler{svc: svc, cfg: cfg}
+}
+
+type emailChangeRequest struct {
+	NewEmail string `json:"new_email"`
+	Password string `json:"password"`
+}
+
+type emailChangeResponse struct {
+	Message   string    `json:"message"`
+	NewEmail  string    `json:"new_email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RequestEmailChange godoc
+// @Summary Сменить email
+// @Tags profile
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Description Требует текущий пароль. На новый адрес уходит письмо со ссылкой подтверждения (действует 24 часа); до перехода по ней остаётся в силе текущий адрес. Повторный запрос заменяет предыдущий.
+// @Param input body emailChangeRequest true "Новый адрес и текущий пароль"
+// @Success 202 {object} helpers.Response{data=emailChangeResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 401 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 409 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/profile/email [post]
+func (h *EmailChangeHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+
+	var req emailChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.NewEmail == "" || req.Password == "" {
+		helpers.Error(w, http.StatusBadRequest, "Укажите новый адрес и текущий пароль")
+		return
+	}
+
+	change, err := h.svc.Request(r.Context(), userID, req.NewEmail, req.Password)
+	switch {
+	case errors.Is(err, services.ErrEmailInvalid):
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeValidationFailed, err.Error())
+		return
+	case errors.Is(err, services.ErrEmailUnchanged):
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeEmailUnchanged, err.Error())
+		return
+	case errors.Is(err, services.ErrEmailChangePassword):
+		helpers.Fail(w, http.StatusForbidden, helpers.CodeAuthPasswordInvalid, "Неверный пароль")
+		return
+	case errors.Is(err, services.ErrEmailTaken):
+		helpers.Fail(w, http.StatusConflict, helpers.CodeAuthEmailTaken, err.Error())
+		return
+	case err != nil:
+		log.Error("Ошибка запроса на смену email", zap.Int("user_id", userID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось запросить смену email")
+		return
+	}
+
+	helpers.JSON(w, http.StatusAccepted, emailChangeResponse{
+		Message:   "Ссылка подтверждения отправлена на новый адрес",
+		NewEmail:  change.NewEmail,
+		ExpiresAt: change.ExpiresAt,
+	})
+}
+
+// ConfirmEmailChange godoc
+// @Summary Подтвердить смену email по ссылке из письма
+// @Tags profile
+// @Param token query string true "Токен подтверждения"
+// @Success 302 {string} string "Редирект на страницу фронта"
+// @Failure 400 {object} helpers.Response
+// @Failure 409 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/profile/email/confirm [get]
+func (h *EmailChangeHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	token := strings.TrimSpace(r.URL.Query().Get("token"))
+	if token == "" {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeAuthLinkInvalid, "Токен отсутствует")
+		return
+	}
+	if err := h.svc.Confirm(r.Context(), token); err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmailChangeTokenInvalid):
+			log.Warn("Смена email: неверная или устаревшая ссылка")
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeAuthLinkInvalid, err.Error())
+		case errors.Is(err, services.ErrEmailTaken):
+			helpers.Fail(w, http.StatusConflict, helpers.CodeAuthEmailTaken, err.Error())
+		default:
+			log.Error("Ошибка подтверждения смены email", zap.Error(err))
+			helpers.Error(w, http.StatusInternalServerError, "Не удалось сменить email")
+		}
+		return
+	}
+
+	base := h.cfg.Get().FrontendBaseURL()
+	http.Redirect(w, r, base+"/profile?email_change=success", http.StatusFound)
+}
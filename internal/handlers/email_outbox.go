@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type EmailOutboxHandler struct {
+	svc *services.EmailOutboxService
+}
+
+func NewEmailOutboxHandler(svc *services.EmailOutboxService) *EmailOutboxHandler {
+	return &EmailOutboxHandler{svc: svc}
+}
+
+// List godoc
+// @Summary История отправки писем
+// @Description Фильтры: recipient (подстрока адреса), status (pending|sent|failed), subject (подстрока), from/to (YYYY-MM-DD или RFC3339, по created_at)
+// @Tags admin-emails
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Param recipient query string false "Адрес получателя (подстрока)"
+// @Param status query string false "pending|sent|failed"
+// @Param subject query string false "Тема (подстрока)"
+// @Param from query string false "Начало периода"
+// @Param to query string false "Конец периода (дата включительно)"
+// @Success 200 {object} helpers.Response{data=EmailOutboxListResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/emails [get]
+func (h *EmailOutboxHandler) List(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+	q := r.URL.Query()
+
+	page, pageSize := pageParams(r)
+
+	f := models.EmailOutboxFilter{
+		Recipient: q.Get("recipient"),
+		Status:    strings.ToLower(strings.TrimSpace(q.Get("status"))),
+		Subject:   q.Get("subject"),
+	}
+	switch f.Status {
+	case "", models.EmailStatusPending, models.EmailStatusSent, models.EmailStatusFailed:
+	default:
+		helpers.Error(w, http.StatusBadRequest, "status должен быть pending|sent|failed")
+		return
+	}
+
+	var err error
+	if f.From, err = parseDateParam(q.Get("from"), false); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "Некорректный параметр from")
+		return
+	}
+	if f.To, err = parseDateParam(q.Get("to"), true); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "Некорректный параметр to")
+		return
+	}
+
+	items, total, err := h.svc.List(r.Context(), f, pageSize, (page-1)*pageSize)
+	if err != nil {
+		log.Error("Ошибка получения истории писем", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить список писем")
+		return
+	}
+
+	log.Info("История писем получена", zap.Int("count", len(items)), zap.Int("total", total))
+	helpers.JSON(w, http.StatusOK, EmailOutboxListResponse{
+		Data:       items,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+	})
+}
+
+// Get godoc
+// @Summary Детали письма (тело, попытки, последняя ошибка SMTP)
+// @Tags admin-emails
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID письма в outbox"
+// @Success 200 {object} helpers.Response{data=models.EmailOutbox}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/emails/{id} [get]
+func (h *EmailOutboxHandler) Get(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id")
+		return
+	}
+
+	m, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, services.ErrEmailNotFound) {
+			helpers.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Error("Ошибка получения письма", zap.Int64("id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить письмо")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, m)
+}
+
+// ListFailed godoc
+// @Summary Список неотправленных писем (failed)
+// @Tags admin-emails
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=EmailOutboxListResponse}
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/emails/failed [get]
+func (h *EmailOutboxHandler) ListFailed(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	page, pageSize := pageParams(r)
+
+	items, total, err := h.svc.ListFailed(r.Context(), pageSize, (page-1)*pageSize)
+	if err != nil {
+		log.Error("Ошибка получения списка неотправленных писем", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить список писем")
+		return
+	}
+
+	log.Info("Список неотправленных писем получен", zap.Int("count", len(items)), zap.Int("total", total))
+	helpers.JSON(w, http.StatusOK, EmailOutboxListResponse{
+		Data:       items,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+	})
+}
+
+// Requeue godoc
+// @Summary Повторно поставить письмо в очередь
+// @Tags admin-emails
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID письма в outbox"
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/emails/{id}/requeue [post]
+func (h *EmailOutboxHandler) Requeue(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id")
+		return
+	}
+
+	if err := h.svc.Requeue(r.Context(), id); err != nil {
+		if errors.Is(err, services.ErrEmailNotRequeueable) {
+			log.Warn("Requeue: письмо не найдено или не failed", zap.Int64("id", id))
+			helpers.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось поставить письмо в очередь")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Письмо поставлено в очередь"})
+}
+
+// parseDateParam — YYYY-MM-DD или RFC3339; для даты-конца периода (endOfDay) берём начало следующего дня.
+func parseDateParam(v string, endOfDay bool) (*time.Time, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// pageParams — page (с 1) и page_size (1..100, по умолчанию 20) из query.
+func pageParams(r *http.Request) (page, pageSize int) {
+	page = parseIntQuery(r, "page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize = parseIntQuery(r, "page_size", 20)
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
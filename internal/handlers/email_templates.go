@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// EmailTemplateHandler — шаблоны писем: просмотр, правка, сброс к встроенному и предпросмотр.
+type EmailTemplateHandler struct{}
+
+func NewEmailTemplateHandler() *EmailTemplateHandler {
+	return &EmailTemplateHandler{}
+}
+
+type EmailTemplateRequest struct {
+	Source string `json:"source"`
+}
+
+type EmailTemplatePreviewRequest struct {
+	Source string `json:"source,omitempty"` // черновик; пусто — текущий шаблон
+	Locale string `json:"locale,omitempty"`
+}
+
+type EmailTemplatePreview struct {
+	HTML string `json:"html"`
+	Text string `json:"text"`
+}
+
+// List godoc
+// @Summary Шаблоны писем
+// @Tags Шаблоны писем
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=[]models.EmailTemplateInfo}
+// @Router /api/admin/email-templates [get]
+func (h *EmailTemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	helpers.JSON(w, http.StatusOK, helpers.EmailTemplates())
+}
+
+// Get godoc
+// @Summary Исходник шаблона письма
+// @Tags Шаблоны писем
+// @Security ApiKeyAuth
+// @Produce json
+// @Param name path string true "Имя шаблона"
+// @Success 200 {object} helpers.Response{data=models.EmailTemplateInfo}
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/email-templates/{name} [get]
+func (h *EmailTemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	source, overridden, err := helpers.EmailTemplateSource(name)
+	if err != nil {
+		emailTemplateError(w, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, models.EmailTemplateInfo{Name: name, Overridden: overridden, Source: source})
+}
+
+// Save godoc
+// @Summary Сохранить шаблон письма
+// @Tags Шаблоны писем
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Description Шаблон проверяется на примере данных; при ошибке разбора или выполнения — 400, ничего не сохраняется.
+// @Param name path string true "Имя шаблона"
+// @Param input body handlers.EmailTemplateRequest true "Исходник (html/template)"
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/email-templates/{name} [put]
+func (h *EmailTemplateHandler) Save(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	var req EmailTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Source == "" {
+		helpers.Error(w, http.StatusBadRequest, "source is required")
+		return
+	}
+	if err := helpers.SaveEmailTemplate(name, req.Source); err != nil {
+		emailTemplateError(w, err)
+		return
+	}
+
+	logger.WithCtx(r.Context()).Info("Шаблон письма сохранён", zap.String("template", name))
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "шаблон сохранён"})
+}
+
+// Reset godoc
+// @Summary Сбросить шаблон письма к встроенному
+// @Tags Шаблоны писем
+// @Security ApiKeyAuth
+// @Produce json
+// @Param name path string true "Имя шаблона"
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/email-templates/{name} [delete]
+func (h *EmailTemplateHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if err := helpers.ResetEmailTemplate(name); err != nil {
+		emailTemplateError(w, err)
+		return
+	}
+
+	logger.WithCtx(r.Context()).Info("Шаблон письма сброшен", zap.String("template", name))
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "используется встроенный шаблон"})
+}
+
+// Preview godoc
+// @Summary Предпросмотр письма
+// @Tags Шаблоны писем
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Description Письмо на примере данных: HTML и текстовая версия (text/plain). Можно передать черновик шаблона — он не сохраняется.
+// @Param name path string true "Имя шаблона"
+// @Param input body handlers.EmailTemplatePreviewRequest false "Черновик и язык"
+// @Success 200 {object} helpers.Response{data=EmailTemplatePreview}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/email-templates/{name}/preview [post]
+func (h *EmailTemplateHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	var req EmailTemplatePreviewRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "invalid json")
+			return
+		}
+	}
+
+	html, text, err := helpers.PreviewEmailTemplate(name, req.Source, req.Locale)
+	if err != nil {
+		emailTemplateError(w, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, EmailTemplatePreview{HTML: html, Text: text})
+}
+
+func emailTemplateError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, helpers.ErrUnknownEmailTemplate):
+		helpers.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, helpers.ErrEmailTemplatesReadOnly):
+		helpers.Error(w, http.StatusConflict, err.Error())
+	case errors.As(err, new(*fs.PathError)):
+		logger.Log.Error("Шаблоны писем: ошибка записи файла", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "failed to store template")
+	default:
+		// ошибки разбора/выполнения шаблона — ошибка во вводе админа
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type FeatureFlagHandler struct {
+	svc *services.FeatureFlagService
+}
+
+func NewFeatureFlagHandler(svc *services.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{svc: svc}
+}
+
+// Flags godoc
+// @Summary Флаги фич для текущего пользователя
+// @Description Ключ флага → включён ли он. С токеном учитывается частичное выкатывание по ID пользователя; без токена такие флаги выключены.
+// @Tags flags
+// @Produce json
+// @Success 200 {object} helpers.Response{data=map[string]bool}
+// @Failure 500 {object} helpers.Response
+// @Router /api/flags [get]
+func (h *FeatureFlagHandler) Flags(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	flags, err := h.svc.Evaluate(r.Context(), userID)
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка получения флагов фич", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить флаги")
+		return
+	}
+	w.Header().Set("Cache-Control", "private, no-cache")
+	helpers.JSON(w, http.StatusOK, flags)
+}
+
+// AdminList godoc
+// @Summary Все флаги фич с настройками
+// @Tags admin-flags
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=[]models.FeatureFlag}
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/flags [get]
+func (h *FeatureFlagHandler) AdminList(w http.ResponseWriter, r *http.Request) {
+	flags, err := h.svc.List(r.Context())
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка получения списка флагов", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить флаги")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, flags)
+}
+
+// Create godoc
+// @Summary Создать флаг фичи
+// @Description key — латиница в нижнем регистре, цифры, _ . -; rollout_percent (0–100, по умолчанию 100) — доля пользователей, для которых включённый флаг активен.
+// @Tags admin-flags
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body models.FeatureFlagRequest true "Флаг"
+// @Success 201 {object} helpers.Response{data=models.FeatureFlag}
+// @Failure 400 {object} helpers.Response
+// @Failure 409 {object} helpers.Response
+// @Router /api/admin/flags [post]
+func (h *FeatureFlagHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.FeatureFlagRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	f, err := h.svc.Create(r.Context(), req)
+	if err != nil {
+		h.writeErr(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, f)
+}
+
+// Update godoc
+// @Summary Изменить флаг фичи
+// @Description Заменяет описание, enabled и rollout_percent; key в теле игнорируется.
+// @Tags admin-flags
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param key path string true "Ключ флага"
+// @Param input body models.FeatureFlagRequest true "Флаг"
+// @Success 200 {object} helpers.Response{data=models.FeatureFlag}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/flags/{key} [put]
+func (h *FeatureFlagHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req models.FeatureFlagRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	f, err := h.svc.Update(r.Context(), mux.Vars(r)["key"], req)
+	if err != nil {
+		h.writeErr(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, f)
+}
+
+// Delete godoc
+// @Summary Удалить флаг фичи
+// @Tags admin-flags
+// @Security ApiKeyAuth
+// @Produce json
+// @Param key path string true "Ключ флага"
+// @Success 200 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/flags/{key} [delete]
+func (h *FeatureFlagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Delete(r.Context(), mux.Vars(r)["key"]); err != nil {
+		h.writeErr(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Флаг удалён"})
+}
+
+func (h *FeatureFlagHandler) writeErr(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrFeatureFlagKey), errors.Is(err, services.ErrFeatureFlagRollout):
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrFeatureFlagExists):
+		helpers.Error(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrFeatureFlagNotFound):
+		helpers.Error(w, http.StatusNotFound, "Флаг не найден")
+	default:
+		logger.WithCtx(r.Context()).Error("Ошибка сохранения флага фичи", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"edutalks/internal/logger"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/microcosm-cc/bluemonday"
+	"go.uber.org/zap"
+)
+
+// feedLimit — сколько последних записей отдаётся в ленте.
+const feedLimit = 50
+
+// RSS 2.0 с atom:link rel="self" (его требуют валидаторы).
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      rssAtom   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssAtom struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// feedEntry — запись ленты до сериализации.
+type feedEntry struct {
+	Title   string
+	Path    string // путь на сайте: /news/1
+	Date    time.Time
+	Summary string // HTML или текст — в описание пойдёт текст
+}
+
+var feedTextPolicy = bluemonday.StrictPolicy().AddSpaceWhenStrippingTag(true)
+
+// writeFeed — отдаёт RSS 2.0; base — адрес сайта для ссылок, title/description — канала,
+// channelPath — страница раздела на сайте.
+func writeFeed(w http.ResponseWriter, r *http.Request, base, title, description, channelPath string, entries []feedEntry) {
+
+	doc := rssDoc{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       title,
+			Link:        base + channelPath,
+			Description: description,
+			Language:    "ru",
+			AtomLink:    rssAtom{Href: base + r.URL.Path, Rel: "self", Type: "application/rss+xml"},
+			Items:       make([]rssItem, 0, len(entries)),
+		},
+	}
+	var latest time.Time
+	for _, e := range entries {
+		link := base + e.Path
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       e.Title,
+			Link:        link,
+			GUID:        rssGUID{IsPermaLink: true, Value: link},
+			PubDate:     e.Date.UTC().Format(time.RFC1123Z),
+			Description: feedExcerpt(e.Summary, 500),
+		})
+		if e.Date.After(latest) {
+			latest = e.Date
+		}
+	}
+	if !latest.IsZero() {
+		doc.Channel.LastBuildDate = latest.UTC().Format(time.RFC1123Z)
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка формирования RSS", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка формирования ленты")
+		return
+	}
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=600")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	w.Write(out)
+}
+
+// feedExcerpt — текст без разметки, не длиннее max символов.
+func feedExcerpt(s string, max int) string {
+	s = html.UnescapeString(feedTextPolicy.Sanitize(s))
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:max])) + "…"
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// scanUpload — антивирусная проверка только что сохранённого файла. Заражённый или
+// непроверенный (clamd недоступен) файл удаляется, клиенту уходит ошибка; false — ответ уже записан.
+func scanUpload(w http.ResponseWriter, r *http.Request, scanner *services.FileScanService, path string) (services.ScanResult, bool) {
+	res, fail := scanSaved(r.Context(), scanner, path)
+	if fail != nil {
+		fail.write(w)
+		return res, false
+	}
+	return res, true
+}
+
+// scanSaved — то же, что scanUpload, но отказ возвращается, а не пишется в ответ (для пакетной загрузки).
+func scanSaved(ctx context.Context, scanner *services.FileScanService, path string) (services.ScanResult, *uploadFailure) {
+	log := logger.WithCtx(ctx)
+
+	res, err := scanner.ScanFile(ctx, path)
+	if err != nil {
+		_ = os.Remove(path)
+		if errors.Is(err, services.ErrScanUnavailable) {
+			return res, &uploadFailure{Status: http.StatusServiceUnavailable, Code: helpers.CodeServiceUnavailable, Message: err.Error()}
+		}
+		log.Error("Ошибка проверки загруженного файла", zap.String("path", path), zap.Error(err))
+		return res, &uploadFailure{Status: http.StatusInternalServerError, Code: helpers.CodeInternal, Message: "Ошибка при проверке файла"}
+	}
+	if res.Infected() {
+		_ = os.Remove(path)
+		log.Warn("Загрузка отклонена: файл заражён", zap.String("signature", res.Signature))
+		return res, &uploadFailure{Status: http.StatusUnprocessableEntity, Code: helpers.CodeFileInfected,
+			Message: "Файл заражён и не может быть загружен", Details: map[string]string{"signature": res.Signature}}
+	}
+	return res, nil
+}
+
+type rescanResponse struct {
+	Status    string `json:"status"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// RescanDocument godoc
+// @Summary Повторно проверить файл документа антивирусом
+// @Tags files
+// @Security ApiKeyAuth
+// @Produce json
+// @Description Синхронная проверка через clamd. status: clean | infected (файл перестаёт скачиваться).
+// @Param id path int true "ID документа"
+// @Success 200 {object} helpers.Response{data=rescanResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 503 {object} helpers.Response "Антивирус выключен или недоступен"
+// @Router /api/admin/files/{id}/rescan [post]
+func (h *DocumentHandler) RescanDocument(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id документа")
+		return
+	}
+
+	res, err := h.scanner.RescanDocument(r.Context(), id)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeDocNotFound, "Документ не найден")
+		return
+	case errors.Is(err, services.ErrScanUnavailable):
+		helpers.Error(w, http.StatusServiceUnavailable, err.Error())
+		return
+	case err != nil:
+		log.Error("Ошибка повторной проверки документа", zap.Int("doc_id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось проверить файл")
+		return
+	}
+
+	log.Info("Документ повторно проверен", zap.Int("doc_id", id), zap.String("status", res.Status))
+	helpers.JSON(w, http.StatusOK, rescanResponse{Status: res.Status, Signature: res.Signature})
+}
+
+// RescanDocuments godoc
+// @Summary Повторно проверить все файлы документов антивирусом
+// @Tags files
+// @Security ApiKeyAuth
+// @Produce json
+// @Description Проверка идёт в фоне, итог — в логе («повторная проверка завершена»). only_unchecked=true — только файлы без результата (загруженные до включения проверки или с ошибкой).
+// @Param only_unchecked query bool false "Только непроверенные"
+// @Success 202 {object} helpers.Response
+// @Failure 409 {object} helpers.Response "Уже выполняется"
+// @Failure 503 {object} helpers.Response "Антивирус выключен"
+// @Router /api/admin/files/rescan [post]
+func (h *DocumentHandler) RescanDocuments(w http.ResponseWriter, r *http.Request) {
+	onlyUnchecked := r.URL.Query().Get("only_unchecked") == "true"
+
+	if err := h.scanner.StartRescan(r.Context(), onlyUnchecked); err != nil {
+		switch {
+		case errors.Is(err, services.ErrRescanRunning):
+			helpers.Error(w, http.StatusConflict, err.Error())
+		case errors.Is(err, services.ErrScanUnavailable):
+			helpers.Error(w, http.StatusServiceUnavailable, err.Error())
+		default:
+			helpers.Error(w, http.StatusInternalServerError, "Не удалось запустить проверку")
+		}
+		return
+	}
+
+	logger.WithCtx(r.Context()).Info("Запущена повторная проверка файлов", zap.Bool("only_unchecked", onlyUnchecked))
+	helpers.JSON(w, http.StatusAccepted, MessageResponse{Message: "Проверка запущена"})
+}
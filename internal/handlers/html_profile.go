@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+type HTMLProfileHandler struct {
+	sanitizer *services.HTMLSanitizer
+}
+
+func NewHTMLProfileHandler(sanitizer *services.HTMLSanitizer) *HTMLProfileHandler {
+	return &HTMLProfileHandler{sanitizer: sanitizer}
+}
+
+// Profile godoc
+// @Summary Профиль очистки HTML статей
+// @Description Что разрешено сверх базового профиля: элементы, атрибуты, хосты iframe, video. Задаётся переменными HTML_EXTRA_ELEMENTS, HTML_EXTRA_ATTRS, HTML_IFRAME_HOSTS, HTML_ALLOW_VIDEO.
+// @Tags articles
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=models.HTMLProfile}
+// @Router /api/admin/html-profile [get]
+func (h *HTMLProfileHandler) Profile(w http.ResponseWriter, r *http.Request) {
+	helpers.JSON(w, http.StatusOK, h.sanitizer.Profile())
+}
+
+// Preview godoc
+// @Summary Проверить очистку HTML
+// @Description Возвращает HTML после очистки по текущему профилю — так он сохранится в статье.
+// @Tags articles
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body models.HTMLPreviewRequest true "HTML"
+// @Success 200 {object} helpers.Response{data=models.HTMLPreviewResponse}
+// @Failure 400 {object} helpers.Response
+// @Router /api/admin/html-profile/preview [post]
+func (h *HTMLProfileHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	var req models.HTMLPreviewRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	clean := h.sanitizer.Sanitize(req.HTML)
+	logger.WithCtx(r.Context()).Debug("Проверка очистки HTML",
+		zap.Int("raw_len", len(req.HTML)), zap.Int("clean_len", len(clean)))
+
+	helpers.JSON(w, http.StatusOK, models.HTMLPreviewResponse{
+		HTML:    clean,
+		Changed: clean != req.HTML,
+		Profile: h.sanitizer.Profile(),
+	})
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+const maxImageUpload = 10 << 20 // 10 MiB
+
+// imageTypes — допустимые изображения: content-type → расширение.
+var imageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+// storedImage — изображение, сохранённое в uploads/<dir>.
+type storedImage struct {
+	Name         string // имя файла на диске
+	RelPath      string // путь относительно uploadsRoot()
+	URL          string // публичная ссылка /uploads/...
+	ContentType  string
+	OriginalName string
+	Size         int64
+}
+
+// saveImageUpload — изображение из поля file multipart-формы: тип по содержимому (или по
+// расширению), сохранение в uploads/dir и антивирус. false — ответ с ошибкой уже записан.
+func saveImageUpload(w http.ResponseWriter, r *http.Request, scanner *services.FileScanService, dir string) (*storedImage, bool) {
+	log := logger.WithCtx(r.Context()).With(zap.String("dir", dir))
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImageUpload)
+	if err := r.ParseMultipartForm(maxImageUpload); err != nil {
+		log.Warn("upload image: multipart parse error", zap.Error(err))
+		helpers.Error(w, http.StatusRequestEntityTooLarge, "файл слишком большой (макс 10 МБ)")
+		return nil, false
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		log.Warn("upload image: отсутствует поле file", zap.Error(err))
+		helpers.Error(w, http.StatusBadRequest, "поле file обязательно")
+		return nil, false
+	}
+	defer file.Close()
+
+	// определить content-type по содержимому
+	sniff := make([]byte, 512)
+	n, _ := file.Read(sniff)
+	contentType := http.DetectContentType(sniff[:n])
+
+	ext, ok := imageTypes[contentType]
+	if !ok {
+		// fallback по имени файла
+		ext = strings.ToLower(filepath.Ext(header.Filename))
+		if ext == ".jpeg" {
+			ext = ".jpg"
+		}
+		if _, ok := map[string]struct{}{".jpg": {}, ".png": {}, ".webp": {}, ".gif": {}}[ext]; !ok {
+			log.Warn("upload image: недопустимый тип", zap.String("ctype", contentType), zap.String("filename", header.Filename))
+			helpers.Error(w, http.StatusBadRequest, "допустимы только изображения: jpg, png, webp, gif")
+			return nil, false
+		}
+	}
+
+	root := uploadsRoot() // абсолютный путь на диске
+	if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+		log.Error("upload image: mkdir error", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "не удалось создать директорию")
+		return nil, false
+	}
+
+	name := fmt.Sprintf("%d_%s%s", time.Now().Unix(), randHex(6), ext)
+	img := &storedImage{
+		Name:         name,
+		RelPath:      dir + "/" + name,
+		URL:          "/uploads/" + dir + "/" + name,
+		ContentType:  contentType,
+		OriginalName: header.Filename,
+	}
+	fullPath := filepath.Join(root, dir, name)
+
+	dst, err := os.Create(fullPath)
+	if err != nil {
+		log.Error("upload image: create dst error", zap.Error(err), zap.String("path", fullPath))
+		helpers.Error(w, http.StatusInternalServerError, "ошибка сохранения файла")
+		return nil, false
+	}
+	defer dst.Close()
+
+	// дописываем уже прочитанные байты и остаток
+	if n > 0 {
+		if _, err := dst.Write(sniff[:n]); err != nil {
+			log.Error("upload image: запись первых байт не удалась", zap.Error(err))
+			helpers.Error(w, http.StatusInternalServerError, "ошибка записи файла")
+			return nil, false
+		}
+	}
+	rest, err := io.Copy(dst, file)
+	if err != nil {
+		log.Error("upload image: запись остатка не удалась", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "ошибка записи файла")
+		return nil, false
+	}
+	dst.Close()
+	img.Size = int64(n) + rest
+
+	if _, ok := scanUpload(w, r, scanner, fullPath); !ok {
+		return nil, false
+	}
+
+	log.Info("upload image: успех",
+		zap.String("filename", header.Filename),
+		zap.String("stored_name", name),
+		zap.String("ctype", contentType),
+		zap.String("path", fullPath),
+		zap.String("url", img.URL),
+	)
+	return img, true
+}
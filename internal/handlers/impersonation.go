@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	"edutalks/internal/utils/clientip"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type ImpersonationHandler struct {
+	svc *services.ImpersonationService
+	cfg *config.Holder
+}
+
+func NewImpersonationHandler(svc *services.ImpersonationService, cfg *config.Holder) *ImpersonationHandler {
+	return &ImpersonationHandler{svc: svc, cfg: cfg}
+}
+
+// Impersonate godoc
+// @Summary Войти под пользователем
+// @Description Короткоживущий access-токен пользователя для поддержки (по умолчанию 15 минут, без refresh). Выдача пишется в журнал с причиной; запросы с таким токеном помечаются в логах, ответы — заголовком X-Impersonated-By. Пароль, e-mail, телефон, сессии, оплата, выгрузка и удаление аккаунта с ним недоступны (403 AUTH_IMPERSONATED). Под администратором войти нельзя.
+// @Tags admin-users
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param input body models.ImpersonateRequest true "Причина (например, номер обращения)"
+// @Success 200 {object} helpers.Response{data=models.ImpersonateResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/users/{id}/impersonate [post]
+func (h *ImpersonationHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	id, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+	var req models.ImpersonateRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	client := models.SessionClient{
+		UserAgent: sessionUserAgent(r),
+		IP:        clientip.FromRequest(r),
+	}
+	adminID, _ := middleware.UserIDFromContext(r.Context())
+
+	res, err := h.svc.Start(r.Context(), adminID, id, req.Reason, client)
+	switch {
+	case err == nil:
+	case errors.Is(err, pgx.ErrNoRows):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeUserNotFound, "Пользователь не найден")
+		return
+	case errors.Is(err, services.ErrImpersonateSelf), errors.Is(err, services.ErrImpersonateAdmin):
+		helpers.Error(w, http.StatusForbidden, err.Error())
+		return
+	default:
+		log.Error("Ошибка входа под пользователем", zap.Int("user_id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// StopImpersonation godoc
+// @Summary Выйти из режима поддержки
+// @Description Завершает вход поддержки, которым подписан запрос: токен поддержки больше не принимается.
+// @Tags admin-users
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} helpers.Response
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/impersonation/stop [post]
+func (h *ImpersonationHandler) StopImpersonation(w http.ResponseWriter, r *http.Request) {
+	id, ok := middleware.ImpersonationIDFromContext(r.Context())
+	if !ok {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeBadRequest, "Запрос выполнен не в режиме поддержки")
+		return
+	}
+	h.stop(w, r, id)
+}
+
+// AdminStopImpersonation godoc
+// @Summary Завершить вход поддержки
+// @Description Досрочно завершает вход под пользователем (например, если токен поддержки утёк): токен больше не принимается.
+// @Tags admin-users
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID записи журнала входов"
+// @Success 200 {object} helpers.Response
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/impersonations/{id}/stop [post]
+func (h *ImpersonationHandler) AdminStopImpersonation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id")
+		return
+	}
+	h.stop(w, r, id)
+}
+
+func (h *ImpersonationHandler) stop(w http.ResponseWriter, r *http.Request, id int64) {
+	switch err := h.svc.Stop(r.Context(), id); {
+	case err == nil:
+		helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Вход поддержки завершён"})
+	case errors.Is(err, services.ErrImpersonationNotFound):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeNotFound, err.Error())
+	default:
+		logger.WithCtx(r.Context()).Error("Ошибка завершения входа поддержки", zap.Int64("impersonation_id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+	}
+}
@@ -4,6 +4,7 @@ package handlers
 import (
 	"archive/zip"
 	"bufio"
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
@@ -19,42 +20,50 @@ import (
 	"time"
 
 	"edutalks/internal/logger"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
 	"go.uber.org/zap"
 )
 
-// AdminLogsHandler — просмотр логов (14 дней), поддерживает:
+// AdminLogsHandler — просмотр логов за срок хранения (LogRetentionService), поддерживает:
 // 1) app.YYYY-MM-DD.log и app.YYYY-MM-DD.log.gz
 // 2) lumberjack: app-<timestamp>.log[.gz] (фильтрация по дате в имени)
 // 3) app.log (только для сегодняшнего дня)
 type AdminLogsHandler struct {
 	LogDir    string // папка с логами
-	Retention int    // дней хранить
+	retention *services.LogRetentionService
 }
 
-func NewAdminLogsHandler() *AdminLogsHandler {
+func NewAdminLogsHandler(retention *services.LogRetentionService) *AdminLogsHandler {
 	return &AdminLogsHandler{
-		LogDir:    "logs",
-		Retention: 14,
+		LogDir:    retention.Dir(),
+		retention: retention,
 	}
 }
 
+// retentionDays — сколько дней логов доступно для просмотра.
+func (h *AdminLogsHandler) retentionDays() int {
+	return h.retention.RetentionDays()
+}
+
 // ====== HTTP ======
 
 // ListDays
 // @Summary      Доступные дни логов
-// @Description  Возвращает список дат (YYYY-MM-DD), за которые доступны файлы логов (до {{Retention}} дней).
+// @Description  Возвращает список дат (YYYY-MM-DD), за которые доступны файлы логов (в пределах срока хранения).
 // @Tags         admin-logs
 // @Security     ApiKeyAuth
 // @Produce      json
-// @Success      200 {object} map[string][]string "days"
-// @Failure      401 {object} map[string]string "unauthorized"
+// @Success      200 {object} LogDaysResponse
+// @Failure      401 {object} helpers.Response "unauthorized"
 // @Router       /api/admin/logs/days [get]
 func (h *AdminLogsHandler) ListDays(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
 
 	today := time.Now().Local()
+	retention := h.retentionDays()
 	var days []string
-	for i := 0; i < h.Retention; i++ {
+	for i := 0; i < retention; i++ {
 		d := today.AddDate(0, 0, -i).Format("2006-01-02")
 		if _, err := h.listFilesForDay(d); err == nil {
 			days = append(days, d)
@@ -64,11 +73,11 @@ func (h *AdminLogsHandler) ListDays(w http.ResponseWriter, r *http.Request) {
 	sort.Sort(sort.Reverse(sort.StringSlice(days)))
 
 	log.Info("admin logs: список доступных дней",
-		zap.Int("retention_days", h.Retention),
+		zap.Int("retention_days", retention),
 		zap.Int("days_count", len(days)),
 	)
 
-	writeJSON(w, http.StatusOK, map[string]any{"days": days})
+	writeJSON(w, http.StatusOK, LogDaysResponse{Days: days})
 }
 
 // LogItem — структурированная запись лога для UI
@@ -79,52 +88,81 @@ type LogItem struct {
 	Fields  map[string]any `json:"fields,omitempty"`
 }
 
+// LogDaysResponse — дни, за которые есть файлы логов, свежие сверху.
+type LogDaysResponse struct {
+	Days []string `json:"days" example:"2026-01-31"`
+}
+
+// LogQueryResponse — страница записей за день (day) или за период (from, to).
+// nextCursor — номер строки дня для day и YYYY-MM-DD:N для периода.
+type LogQueryResponse struct {
+	Day        string    `json:"day,omitempty"`
+	From       string    `json:"from,omitempty"`
+	To         string    `json:"to,omitempty"`
+	Items      []LogItem `json:"items"`
+	NextCursor any       `json:"nextCursor" swaggertype:"string" example:"2026-01-31:200"`
+	HasMore    bool      `json:"hasMore"`
+}
+
+// LogStatsResponse — число записей по уровням для каждого часа (0-23) дня.
+type LogStatsResponse struct {
+	Day   string                 `json:"day"`
+	Stats map[int]map[string]int `json:"stats"`
+}
+
+// LogSummaryResponse — число записей по уровням за последние дни: всего и по каждому дню.
+type LogSummaryResponse struct {
+	Total  int                       `json:"total"`
+	Levels map[string]int            `json:"levels"`
+	ByDay  map[string]map[string]int `json:"by_day"`
+}
+
 // GetLogs
-// @Summary      Логи за день
+// @Summary      Логи за день или период
 // @Description  Возвращает массив логов за указанный день. Поддерживает фильтрацию по уровню, часу и строке поиска.
+// @Description  from/to вместо day — период не длиннее срока хранения логов: файлы идут по порядку дней, cursor вида YYYY-MM-DD:N (nextCursor из ответа).
+// @Description  format=csv|ndjson — выгрузка файлом всех подходящих записей за day или за период from..to (без limit/cursor).
 // @Tags         admin-logs
 // @Security     ApiKeyAuth
-// @Produce      json
-// @Param        day     query  string true  "Дата (YYYY-MM-DD)"
+// @Produce      json,text/csv,application/x-ndjson
+// @Param        day     query  string false "Дата (YYYY-MM-DD); обязательна без format и from/to"
 // @Param        level   query  string false "CSV уровней: debug,info,warn,error,panic,fatal"
 // @Param        hour    query  int    false "Час (0-23)"
 // @Param        q       query  string false "Поиск по подстроке"
+// @Param        request_id query string false "Только записи одного запроса (X-Request-ID)"
 // @Param        limit   query  int    false "Лимит (по умолч. 200, макс. 1000)"
-// @Param        cursor  query  int    false "Номер строки для пагинации (по умолч. 0) — счётчик по файлу"
+// @Param        cursor  query  string false "Пагинация: номер строки дня (по умолч. 0, дальше — nextCursor); для from/to — YYYY-MM-DD:N"
 // @Param        order   query  string false "Порядок в выдаче: asc|desc (по умолчанию asc)"
 // @Param        tail    query  int    false "Вернуть только последние N совпадений после сортировки (опц.)"
-// @Success      200 {object} map[string]interface{}
-// @Failure      401 {object} map[string]string "unauthorized"
-// @Failure      404 {object} map[string]string "day not found"
+// @Param        format  query  string false "Выгрузка файлом: csv|ndjson"
+// @Param        from    query  string false "Начало периода (YYYY-MM-DD), вместо day"
+// @Param        to      query  string false "Конец периода (YYYY-MM-DD), включительно"
+// @Success      200 {object} LogQueryResponse
+// @Failure      401 {object} helpers.Response "unauthorized"
+// @Failure      404 {object} helpers.Response "day not found"
 // @Router       /api/admin/logs [get]
 func (h *AdminLogsHandler) GetLogs(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
 
+	// выгрузка: весь отфильтрованный результат файлом, без лимита
+	switch format := strings.ToLower(r.URL.Query().Get("format")); format {
+	case logExportCSV, logExportNDJSON:
+		h.exportLogs(w, r, format)
+		return
+	}
+
 	day := r.URL.Query().Get("day")
+	if day == "" && (r.URL.Query().Get("from") != "" || r.URL.Query().Get("to") != "") {
+		h.getLogsRange(w, r)
+		return
+	}
 	if !reDay.MatchString(day) {
 		log.Warn("admin logs: некорректный параметр day", zap.String("day", day))
-		http.Error(w, "bad day", http.StatusBadRequest)
+		helpers.Error(w, http.StatusBadRequest, "bad day")
 		return
 	}
 
-	levels := parseCSV(r.URL.Query().Get("level")) // CSV
-	levelSet := toUpperSet(strings.Join(levels, ","))
-
-	q := strings.TrimSpace(r.URL.Query().Get("q"))
-	var qre *regexp.Regexp
-	if q != "" {
-		qre = regexp.MustCompile("(?i)" + regexp.QuoteMeta(q))
-	}
-
-	hourStr := r.URL.Query().Get("hour") // 0..23
-	var hourPtr *int
-	if hourStr != "" {
-		if hv, err := strconv.Atoi(hourStr); err == nil && hv >= 0 && hv <= 23 {
-			hourPtr = &hv
-		} else {
-			log.Warn("admin logs: некорректный час", zap.String("hour", hourStr))
-		}
-	}
+	f := parseLogFilter(r)
 
 	limit := clampAtoi(r.URL.Query().Get("limit"), 200, 50, 1000)
 	cursor := clampAtoi(r.URL.Query().Get("cursor"), 0, 0, 10_000_000)
@@ -133,54 +171,27 @@ func (h *AdminLogsHandler) GetLogs(w http.ResponseWriter, r *http.Request) {
 
 	log.Info("admin logs: запрос логов",
 		zap.String("day", day),
-		zap.Strings("levels", levels),
-		zap.Any("hour", hourPtr),
-		zap.String("q", q),
+		zap.Strings("levels", f.levelList),
+		zap.Any("hour", f.hour),
+		zap.String("q", f.q),
+		zap.String("request_id", f.requestID),
 		zap.Int("limit", limit),
 		zap.Int("cursor", cursor),
 		zap.String("order", order),
 		zap.Int("tail", tail),
 	)
 
-	lineNo := 0
+	lineNo := cursor
 	matched := 0
 	var items []LogItem
 
-	err := h.forEachDayLineCtx(r.Context(), day, func(raw []byte) bool {
-		lineNo++
-		if lineNo <= cursor {
-			return true // продолжаем читать
-		}
-		// быстрый фильтр по подстроке
-		if qre != nil && !qre.Match(raw) {
-			return true
-		}
-		// парсим JSON
-		var obj map[string]any
-		if err := json.Unmarshal(raw, &obj); err != nil {
-			// пропускаем не-JSON (например, консольный формат)
-			return true
-		}
-
-		// фильтр по уровню
-		lvl := strings.ToUpper(getString(obj, "level"))
-		if len(levelSet) > 0 && !levelSet[lvl] {
+	// по индексу дня чтение начинается сразу с cursor (и с отрезка часа, если задан hour)
+	err := h.forEachDayLineFrom(r.Context(), day, cursor, f.hour, func(n int, raw []byte) bool {
+		lineNo = n
+		obj, ok := f.match(raw)
+		if !ok {
 			return true
 		}
-		// фильтр по часу
-		if hourPtr != nil {
-			ts := getString(obj, "time")
-			if hr, ok := extractHour(ts); ok {
-				if hr != *hourPtr {
-					return true
-				}
-			} else {
-				// пробуем fallback из сырой строки (если формат нестандартный)
-				if hr2, ok2 := extractHourFromRaw(raw); ok2 && hr2 != *hourPtr {
-					return true
-				}
-			}
-		}
 
 		items = append(items, toLogItem(obj))
 		matched++
@@ -190,7 +201,7 @@ func (h *AdminLogsHandler) GetLogs(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		log.Warn("admin logs: файлы за день не найдены", zap.String("day", day), zap.Error(err))
-		http.Error(w, "day not found", http.StatusNotFound)
+		helpers.Error(w, http.StatusNotFound, "day not found")
 		return
 	}
 
@@ -209,23 +220,23 @@ func (h *AdminLogsHandler) GetLogs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// курсор и флаг наличия ещё данных
-	next := cursor + matched
+	// курсор — номер последней прочитанной строки дня; флаг наличия ещё данных
+	next := lineNo
 	hasMore := matched >= limit // простая и честная эвристика
 
 	log.Info("admin logs: логи отданы",
 		zap.String("day", day),
 		zap.Int("returned", len(items)),
 		zap.Int("next_cursor", next),
-		zap.Int("scanned_lines", lineNo),
+		zap.Int("scanned_lines", lineNo-cursor),
 		zap.Bool("has_more", hasMore),
 	)
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"day":        day,
-		"items":      items,
-		"nextCursor": next,
-		"hasMore":    hasMore,
+	writeJSON(w, http.StatusOK, LogQueryResponse{
+		Day:        day,
+		Items:      items,
+		NextCursor: next,
+		HasMore:    hasMore,
 	})
 }
 
@@ -236,9 +247,9 @@ func (h *AdminLogsHandler) GetLogs(w http.ResponseWriter, r *http.Request) {
 // @Security     ApiKeyAuth
 // @Produce      json
 // @Param        day query string true "Дата (YYYY-MM-DD)"
-// @Success      200 {object} map[string]interface{}
-// @Failure      401 {object} map[string]string "unauthorized"
-// @Failure      404 {object} map[string]string "day not found"
+// @Success      200 {object} LogStatsResponse
+// @Failure      401 {object} helpers.Response "unauthorized"
+// @Failure      404 {object} helpers.Response "day not found"
 // @Router       /api/admin/logs/stats [get]
 func (h *AdminLogsHandler) Stats(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -246,62 +257,39 @@ func (h *AdminLogsHandler) Stats(w http.ResponseWriter, r *http.Request) {
 	day := r.URL.Query().Get("day")
 	if !reDay.MatchString(day) {
 		log.Warn("admin logs: некорректный параметр day (stats)", zap.String("day", day))
-		http.Error(w, "bad day", http.StatusBadRequest)
+		helpers.Error(w, http.StatusBadRequest, "bad day")
 		return
 	}
 
 	levels := []string{"DEBUG", "INFO", "WARN", "ERROR", "PANIC", "FATAL"}
 
 	// заранее инициализируем карту по всем часам и уровням
-	type Lvl map[string]int
-	stats := make(map[int]Lvl, 24)
+	stats := make(map[int]map[string]int, 24)
 	for hr := 0; hr < 24; hr++ {
-		stats[hr] = Lvl{}
+		stats[hr] = map[string]int{}
 		for _, lv := range levels {
 			stats[hr][lv] = 0
 		}
 	}
 
-	err := h.forEachDayLineCtx(r.Context(), day, func(raw []byte) bool {
-		// парсим JSON
-		var obj map[string]any
-		if err := json.Unmarshal(raw, &obj); err != nil {
-			// не-JSON пропускаем
-			return true
-		}
-
-		lvl := strings.ToUpper(getString(obj, "level"))
-		if lvl == "" {
-			lvl = "INFO"
-		}
-
-		ts := getString(obj, "time")
-		if hr, ok := extractHour(ts); ok {
-			stats[hr][lvl]++
-			return true
-		}
-
-		// fallback: попытка распарсить через time.Parse с нормализацией
-		if t, ok := parseTimestamp(ts); ok {
-			stats[t.Hour()][lvl]++
-			return true
-		}
-
-		// ещё один fallback: вытащить по регэкспу из сырой строки
-		if hr2, ok2 := extractHourFromRaw(raw); ok2 {
-			stats[hr2][lvl]++
-		}
-		return true
-	})
+	// счётчики по часам берём из индекса файлов дня — без повторного разбора строк
+	_, indexes, err := h.dayIndexes(r.Context(), day)
 	if err != nil {
-		http.Error(w, "day not found", http.StatusNotFound)
+		helpers.Error(w, http.StatusNotFound, "day not found")
 		return
 	}
+	for _, idx := range indexes {
+		for hr := range idx.hours {
+			for lvl, n := range idx.hours[hr].levels {
+				stats[hr][lvl] += n
+			}
+		}
+	}
 
 	log.Info("admin logs: статистика по дням сформирована", zap.String("day", day))
-	writeJSON(w, http.StatusOK, map[string]any{
-		"day":   day,
-		"stats": stats,
+	writeJSON(w, http.StatusOK, LogStatsResponse{
+		Day:   day,
+		Stats: stats,
 	})
 }
 
@@ -313,7 +301,7 @@ func (h *AdminLogsHandler) Stats(w http.ResponseWriter, r *http.Request) {
 // @Param        day query string true "Дата (YYYY-MM-DD)"
 // @Param        zip query int false "Если 1 — отдать ZIP со всеми файлами за день"
 // @Success      200 {file} file "Лог-файл"
-// @Failure      404 {object} map[string]string "file not found"
+// @Failure      404 {object} helpers.Response "file not found"
 // @Router       /api/admin/logs/download [get]
 func (h *AdminLogsHandler) DownloadLog(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -322,7 +310,7 @@ func (h *AdminLogsHandler) DownloadLog(w http.ResponseWriter, r *http.Request) {
 	files, err := h.listFilesForDay(day)
 	if err != nil || len(files) == 0 {
 		log.Warn("admin logs: файл лога не найден для скачивания", zap.String("day", day))
-		http.Error(w, "file not found", http.StatusNotFound)
+		helpers.Error(w, http.StatusNotFound, "file not found")
 		return
 	}
 
@@ -365,55 +353,113 @@ func (h *AdminLogsHandler) DownloadLog(w http.ResponseWriter, r *http.Request) {
 // @Security     ApiKeyAuth
 // @Produce      json
 // @Param        days query int false "Количество дней (по умолчанию 7)"
-// @Success      200 {object} map[string]interface{}
-// @Failure      401 {object} map[string]string "unauthorized"
+// @Success      200 {object} LogSummaryResponse
+// @Failure      401 {object} helpers.Response "unauthorized"
 // @Router       /api/admin/logs/summary [get]
 func (h *AdminLogsHandler) StatsSummary(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
 
-	days := clampAtoi(r.URL.Query().Get("days"), 7, 1, h.Retention)
+	days := clampAtoi(r.URL.Query().Get("days"), 7, 1, h.retentionDays())
 
 	today := time.Now().Local()
-	summary := map[string]any{
-		"total":  0,
-		"levels": map[string]int{},
-		"by_day": map[string]map[string]int{},
+	summary := LogSummaryResponse{
+		Levels: map[string]int{},
+		ByDay:  map[string]map[string]int{},
 	}
-	levelsTotal := summary["levels"].(map[string]int)
 
 	for i := 0; i < days; i++ {
 		d := today.AddDate(0, 0, -i).Format("2006-01-02")
 		dayStats := map[string]int{}
 
-		_ = h.forEachDayLineCtx(r.Context(), d, func(raw []byte) bool {
-			var obj map[string]any
-			if err := json.Unmarshal(raw, &obj); err != nil {
-				return true
-			}
-			lvl := strings.ToUpper(getString(obj, "level"))
-			if lvl == "" {
-				lvl = "INFO"
+		_, indexes, _ := h.dayIndexes(r.Context(), d)
+		for _, idx := range indexes {
+			for lvl, n := range idx.levels {
+				dayStats[lvl] += n
+				summary.Levels[lvl] += n
+				summary.Total += n
 			}
-			dayStats[lvl]++
-			levelsTotal[lvl]++
-			summary["total"] = summary["total"].(int) + 1
-			return true
-		})
+		}
 
 		if len(dayStats) > 0 {
-			summary["by_day"].(map[string]map[string]int)[d] = dayStats
+			summary.ByDay[d] = dayStats
 		}
 	}
 
 	log.Info("admin logs: краткая статистика сформирована",
 		zap.Int("days", days),
-		zap.Int("total", summary["total"].(int)),
+		zap.Int("total", summary.Total),
 	)
 	writeJSON(w, http.StatusOK, summary)
 }
 
 // ====== CORE ======
 
+// logFilter — фильтры записей из query: level (CSV), q, request_id, hour.
+type logFilter struct {
+	levelList []string
+	levels    map[string]bool
+	q         string
+	qre       *regexp.Regexp
+	requestID string
+	hour      *int
+}
+
+func parseLogFilter(r *http.Request) logFilter {
+	q := r.URL.Query()
+	f := logFilter{
+		levelList: parseCSV(q.Get("level")),
+		q:         strings.TrimSpace(q.Get("q")),
+		requestID: strings.TrimSpace(q.Get("request_id")),
+	}
+	f.levels = toUpperSet(strings.Join(f.levelList, ","))
+	if f.q != "" {
+		f.qre = regexp.MustCompile("(?i)" + regexp.QuoteMeta(f.q))
+	}
+	if hourStr := q.Get("hour"); hourStr != "" { // 0..23
+		if hv, err := strconv.Atoi(hourStr); err == nil && hv >= 0 && hv <= 23 {
+			f.hour = &hv
+		} else {
+			logger.WithCtx(r.Context()).Warn("admin logs: некорректный час", zap.String("hour", hourStr))
+		}
+	}
+	return f
+}
+
+// match — разобранная запись, если строка проходит фильтры; не-JSON строки (консольный формат) пропускаются.
+func (f logFilter) match(raw []byte) (map[string]any, bool) {
+	// быстрый фильтр по подстроке
+	if f.qre != nil && !f.qre.Match(raw) {
+		return nil, false
+	}
+	if f.requestID != "" && !bytes.Contains(raw, []byte(f.requestID)) {
+		return nil, false
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, false
+	}
+	// фильтр по request_id (точное совпадение поля)
+	if f.requestID != "" && getString(obj, "request_id") != f.requestID {
+		return nil, false
+	}
+	// фильтр по уровню
+	if len(f.levels) > 0 && !f.levels[strings.ToUpper(getString(obj, "level"))] {
+		return nil, false
+	}
+	// фильтр по часу
+	if f.hour != nil {
+		if hr, ok := extractHour(getString(obj, "time")); ok {
+			if hr != *f.hour {
+				return nil, false
+			}
+		} else if hr2, ok2 := extractHourFromRaw(raw); ok2 && hr2 != *f.hour {
+			// fallback из сырой строки (если формат нестандартный)
+			return nil, false
+		}
+	}
+	return obj, true
+}
+
 var reDay = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
 var reDateTime = regexp.MustCompile(`(\d{4}-\d{2}-\d{2})[T ](\d{2}):(\d{2}):(\d{2})`)
 
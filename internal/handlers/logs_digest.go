@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"edutalks/internal/models"
+)
+
+// DigestLogStats — выжимка из логов за период для еженедельной сводки:
+// ошибки (ERROR/PANIC/FATAL), сгруппированные по сообщению.
+func (h *AdminLogsHandler) DigestLogStats(ctx context.Context, from, to time.Time, topN int) (models.DigestLogStats, error) {
+	var res models.DigestLogStats
+	byMsg := map[string]*models.DigestLogError{}
+
+	for day := from.Local(); !day.After(to.Local()); day = day.AddDate(0, 0, 1) {
+		_ = h.forEachDayLineCtx(ctx, day.Format("2006-01-02"), func(raw []byte) bool {
+			var obj map[string]any
+			if err := json.Unmarshal(raw, &obj); err != nil {
+				return true
+			}
+			ts := getString(obj, "time")
+			if t, ok := parseTimestamp(ts); ok && (t.Before(from) || !t.Before(to)) {
+				return true
+			}
+
+			switch strings.ToUpper(getString(obj, "level")) {
+			case "ERROR", "PANIC", "FATAL":
+			default:
+				return true
+			}
+
+			res.ErrorsTotal++
+			msg := getString(obj, "msg")
+			e, ok := byMsg[msg]
+			if !ok {
+				e = &models.DigestLogError{Message: msg}
+				byMsg[msg] = e
+			}
+			e.Count++
+			if ts > e.LastSeen {
+				e.LastSeen = ts
+			}
+			return true
+		})
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+	}
+
+	for _, e := range byMsg {
+		res.TopErrors = append(res.TopErrors, *e)
+	}
+	sort.Slice(res.TopErrors, func(i, j int) bool {
+		if res.TopErrors[i].Count != res.TopErrors[j].Count {
+			return res.TopErrors[i].Count > res.TopErrors[j].Count
+		}
+		return res.TopErrors[i].Message < res.TopErrors[j].Message
+	})
+	if topN > 0 && len(res.TopErrors) > topN {
+		res.TopErrors = res.TopErrors[:topN]
+	}
+	return res, nil
+}
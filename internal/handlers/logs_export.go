@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"edutalks/internal/logger"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+// Форматы выгрузки логов (GetLogs?format=...).
+const (
+	logExportCSV    = "csv"
+	logExportNDJSON = "ndjson"
+)
+
+// logExportColumns — колонки CSV; всё остальное уходит в fields одной JSON-строкой.
+var logExportColumns = []string{"time", "level", "msg", "request_id", "user_id", "method", "path", "status", "error"}
+
+// logExportFlushEvery — как часто (в записях) сбрасывать буфер клиенту.
+const logExportFlushEvery = 500
+
+// exportLogs — потоковая выгрузка отфильтрованных записей за день/диапазон без лимита на количество.
+func (h *AdminLogsHandler) exportLogs(w http.ResponseWriter, r *http.Request, format string) {
+	log := logger.WithCtx(r.Context())
+
+	days, err := h.rangeDays(r)
+	if err != nil {
+		log.Warn("admin logs: некорректный период выгрузки", zap.Error(err))
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(days) == 0 {
+		helpers.Error(w, http.StatusNotFound, "day not found")
+		return
+	}
+	f := parseLogFilter(r)
+
+	name := "logs-" + days[0]
+	if len(days) > 1 {
+		name += "_" + days[len(days)-1]
+	}
+	name += "." + format
+
+	rc := http.NewResponseController(w)
+	// большие выгрузки идут дольше WriteTimeout сервера; обрыв клиента отменит контекст
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	if format == logExportCSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+name+"\"")
+	w.WriteHeader(http.StatusOK)
+
+	var cw *csv.Writer
+	if format == logExportCSV {
+		_, _ = w.Write([]byte("\xEF\xBB\xBF")) // BOM — чтобы Excel открыл кириллицу
+		cw = csv.NewWriter(w)
+		_ = cw.Write(append(append([]string{}, logExportColumns...), "fields"))
+	}
+
+	exported := 0
+	var writeErr error
+	for _, day := range days {
+		_ = h.forEachDayLineCtx(r.Context(), day, func(raw []byte) bool {
+			obj, ok := f.match(raw)
+			if !ok {
+				return true
+			}
+
+			if cw != nil {
+				writeErr = cw.Write(logCSVRow(obj))
+			} else {
+				_, writeErr = w.Write(append(append([]byte{}, raw...), '\n'))
+			}
+			if writeErr != nil {
+				return false
+			}
+
+			exported++
+			if exported%logExportFlushEvery == 0 {
+				if cw != nil {
+					cw.Flush()
+				}
+				_ = rc.Flush()
+			}
+			return true
+		})
+		if writeErr != nil || r.Context().Err() != nil {
+			break
+		}
+	}
+	if cw != nil {
+		cw.Flush()
+	}
+	_ = rc.Flush()
+
+	log.Info("admin logs: выгрузка логов",
+		zap.String("format", format),
+		zap.Strings("days", days),
+		zap.Int("exported", exported),
+		zap.Bool("interrupted", writeErr != nil || r.Context().Err() != nil),
+	)
+}
+
+// logCSVRow — строка CSV: основные колонки и прочие поля JSON-ом.
+func logCSVRow(obj map[string]any) []string {
+	row := make([]string, 0, len(logExportColumns)+1)
+	rest := make(map[string]any, len(obj))
+	for k, v := range obj {
+		rest[k] = v
+	}
+	for _, col := range logExportColumns {
+		v, ok := obj[col]
+		if col == "msg" && !ok {
+			v, ok = obj["message"]
+			delete(rest, "message")
+		}
+		delete(rest, col)
+		switch {
+		case !ok || v == nil:
+			row = append(row, "")
+		case isString(v):
+			row = append(row, v.(string))
+		default:
+			b, _ := json.Marshal(v)
+			row = append(row, string(b))
+		}
+	}
+	extra := ""
+	if len(rest) > 0 {
+		b, _ := json.Marshal(rest)
+		extra = string(b)
+	}
+	return append(row, extra)
+}
+
+func isString(v any) bool {
+	_, ok := v.(string)
+	return ok
+}
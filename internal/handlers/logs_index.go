@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Индекс файла логов строится одним проходом и хранится в памяти: для каждого часа —
+// первая строка и её смещение, счётчики уровней; плюс смещения каждой logIndexStep-й строки.
+// Stats/StatsSummary считаются по индексу без чтения файла, GetLogs с cursor/hour
+// начинает чтение сразу с нужного места. Файл, в который ещё пишут, доиндексируется
+// с места, где остановились; усечённый или перезаписанный — индексируется заново.
+
+// logIndexStep — шаг контрольных точек «номер строки → смещение».
+const logIndexStep = 1000
+
+// logIndexMaxFiles — после стольких файлов в кэше выбрасываем индексы удалённых (ротация).
+const logIndexMaxFiles = 64
+
+// logIndexHeadLen — по скольким первым байтам узнаём, что файл перезаписан, а не дописан.
+const logIndexHeadLen = 256
+
+type logHourIndex struct {
+	first  int // номер первой строки часа в файле (с 0), -1 — записей нет
+	levels map[string]int
+}
+
+type logFileIndex struct {
+	size    int64 // проиндексировано байт (до конца последней полной строки)
+	modTime time.Time
+	head    uint32 // crc32 первых logIndexHeadLen байт
+	gz      bool
+
+	lines       int
+	checkpoints []int64 // смещение строки i*logIndexStep
+	hours       [24]logHourIndex
+	levels      map[string]int // по всем JSON-записям файла
+	sorted      bool           // часы не убывают — можно читать только отрезок часа
+	lastHour    int
+}
+
+type logIndexEntry struct {
+	mu  sync.Mutex
+	idx *logFileIndex
+}
+
+var (
+	logIndexMu    sync.Mutex
+	logIndexCache = map[string]*logIndexEntry{}
+)
+
+// fileIndex — актуальный индекс файла: из кэша, дополненный или построенный заново.
+func fileIndex(ctx context.Context, path string) (*logFileIndex, error) {
+	st, err := os.Stat(path)
+	if err != nil {
+		logIndexMu.Lock()
+		delete(logIndexCache, path)
+		logIndexMu.Unlock()
+		return nil, err
+	}
+
+	logIndexMu.Lock()
+	e, ok := logIndexCache[path]
+	if !ok {
+		if len(logIndexCache) >= logIndexMaxFiles {
+			pruneLogIndex()
+		}
+		e = &logIndexEntry{}
+		logIndexCache[path] = e
+	}
+	logIndexMu.Unlock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if idx := e.idx; idx != nil {
+		switch {
+		case idx.modTime.Equal(st.ModTime()) && st.Size() >= idx.size:
+			return idx, nil // не менялся (неполная последняя строка доиндексируется позже)
+		case !idx.gz && st.Size() >= idx.size && headCRC(path) == idx.head:
+			next := idx.clone()
+			if err := next.scan(ctx, path); err != nil {
+				return nil, err
+			}
+			next.modTime = st.ModTime()
+			e.idx = next
+			return next, nil
+		}
+	}
+
+	idx := newLogFileIndex(strings.HasSuffix(path, ".gz"))
+	if err := idx.scan(ctx, path); err != nil {
+		return nil, err
+	}
+	idx.modTime = st.ModTime()
+	idx.head = headCRC(path)
+	e.idx = idx
+	return idx, nil
+}
+
+// pruneLogIndex — убирает из кэша файлы, которых уже нет; вызывается под logIndexMu.
+func pruneLogIndex() {
+	for path := range logIndexCache {
+		if _, err := os.Stat(path); err != nil {
+			delete(logIndexCache, path)
+		}
+	}
+}
+
+func newLogFileIndex(gz bool) *logFileIndex {
+	idx := &logFileIndex{gz: gz, levels: map[string]int{}, sorted: true, lastHour: -1}
+	for h := range idx.hours {
+		idx.hours[h] = logHourIndex{first: -1, levels: map[string]int{}}
+	}
+	return idx
+}
+
+// clone — копия для дописывания (старый индекс могут читать параллельные запросы).
+func (idx *logFileIndex) clone() *logFileIndex {
+	cp := *idx
+	cp.checkpoints = append([]int64(nil), idx.checkpoints...)
+	cp.levels = copyCounts(idx.levels)
+	for h := range cp.hours {
+		cp.hours[h].levels = copyCounts(idx.hours[h].levels)
+	}
+	return &cp
+}
+
+func copyCounts(m map[string]int) map[string]int {
+	out := make(map[string]int, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// scan — индексирует полные строки начиная с idx.size.
+func (idx *logFileIndex) scan(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var reader io.Reader = f
+	if idx.gz {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		reader = gr
+	} else if _, err := f.Seek(idx.size, io.SeekStart); err != nil {
+		return err
+	}
+
+	br := bufio.NewReaderSize(reader, 256*1024)
+	offset := idx.size
+	for {
+		if idx.lines%logIndexStep == 0 && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line, err := br.ReadBytes('\n')
+		if err != nil {
+			if err == io.EOF {
+				if idx.gz && len(line) > 0 {
+					idx.add(line, offset) // в архиве последняя строка уже не допишется
+					offset += int64(len(line))
+				}
+				break
+			}
+			return err
+		}
+		idx.add(line, offset)
+		offset += int64(len(line))
+	}
+	idx.size = offset
+	return nil
+}
+
+// add — учитывает одну строку (с \n на конце или без), начинающуюся со смещения offset.
+func (idx *logFileIndex) add(line []byte, offset int64) {
+	if idx.lines%logIndexStep == 0 {
+		idx.checkpoints = append(idx.checkpoints, offset)
+	}
+	lineNo := idx.lines
+	idx.lines++
+
+	raw := bytes.TrimRight(line, "\r\n")
+	var rec struct {
+		Time  string `json:"time"`
+		Level string `json:"level"`
+	}
+	isJSON := json.Unmarshal(raw, &rec) == nil
+
+	hr, ok := extractHour(rec.Time)
+	if !ok {
+		if t, ok2 := parseTimestamp(rec.Time); ok2 {
+			hr, ok = t.Hour(), true
+		} else {
+			hr, ok = extractHourFromRaw(raw)
+		}
+	}
+
+	lvl := strings.ToUpper(rec.Level)
+	if lvl == "" {
+		lvl = "INFO"
+	}
+	if isJSON {
+		idx.levels[lvl]++
+	}
+	if !ok {
+		return
+	}
+
+	if hr < idx.lastHour {
+		idx.sorted = false
+	}
+	idx.lastHour = hr
+	h := &idx.hours[hr]
+	if h.first < 0 {
+		h.first = lineNo
+	}
+	if isJSON {
+		h.levels[lvl]++
+	}
+}
+
+// hourRange — строки [from, to) часа hr; ok=false — час не вычленить (порядок нарушен).
+func (idx *logFileIndex) hourRange(hr int) (from, to int, ok bool) {
+	if !idx.sorted {
+		return 0, 0, false
+	}
+	h := idx.hours[hr]
+	if h.first < 0 {
+		return 0, 0, true // часа нет — пустой отрезок
+	}
+	to = -1 // до конца файла (он мог дописаться после индексации)
+	for next := hr + 1; next < 24; next++ {
+		if idx.hours[next].first >= 0 {
+			to = idx.hours[next].first
+			break
+		}
+	}
+	return h.first, to, true
+}
+
+// seek — ближайшая известная позиция не дальше строки line: номер строки и смещение.
+func (idx *logFileIndex) seek(line int) (int, int64) {
+	if idx.gz || len(idx.checkpoints) == 0 || line <= 0 {
+		return 0, 0
+	}
+	i := line / logIndexStep
+	if i >= len(idx.checkpoints) {
+		i = len(idx.checkpoints) - 1
+	}
+	return i * logIndexStep, idx.checkpoints[i]
+}
+
+func headCRC(path string) uint32 {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	buf := make([]byte, logIndexHeadLen)
+	n, _ := io.ReadFull(f, buf)
+	return crc32.ChecksumIEEE(buf[:n])
+}
+
+// dayIndexes — индексы всех файлов дня в порядке listFilesForDay.
+func (h *AdminLogsHandler) dayIndexes(ctx context.Context, day string) ([]string, []*logFileIndex, error) {
+	files, err := h.listFilesForDay(day)
+	if err != nil || len(files) == 0 {
+		return nil, nil, os.ErrNotExist
+	}
+	var (
+		paths []string
+		out   []*logFileIndex
+	)
+	for _, path := range files {
+		idx, err := fileIndex(ctx, path)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			continue // файл мог быть удалён/перемещён
+		}
+		paths = append(paths, path)
+		out = append(out, idx)
+	}
+	return paths, out, nil
+}
+
+// forEachDayLineFrom — как forEachDayLineCtx, но начиная со строки дня start (нумерация с 1
+// в handle, как счётчик lineNo в GetLogs) и, если задан hour, только по отрезку этого часа.
+// Ненужные файлы и строки пропускаются по индексу без чтения.
+func (h *AdminLogsHandler) forEachDayLineFrom(ctx context.Context, day string, start int, hour *int, handle func(lineNo int, raw []byte) bool) error {
+	paths, indexes, err := h.dayIndexes(ctx, day)
+	if err != nil {
+		return err
+	}
+
+	base := 0 // номер последней строки предыдущих файлов
+	for i, path := range paths {
+		idx := indexes[i]
+		from, to := 0, -1
+		if hour != nil {
+			if f, t, ok := idx.hourRange(*hour); ok {
+				if f == t {
+					base += idx.lines
+					continue
+				}
+				from, to = f, t
+			}
+		}
+		if skip := start - base; skip > from {
+			from = skip
+		}
+		// файл целиком до курсора (строки, дописанные после индексации, читаются ниже)
+		if from >= idx.lines && !isLastPath(i, paths) {
+			base += idx.lines
+			continue
+		}
+
+		lines, keep, err := readLinesFrom(ctx, path, idx, from, to, func(n int, raw []byte) bool {
+			return handle(base+n, raw)
+		})
+		if err != nil {
+			return err
+		}
+		if !keep {
+			return nil
+		}
+		if lines < idx.lines {
+			lines = idx.lines
+		}
+		base += lines
+	}
+	return nil
+}
+
+func isLastPath(i int, paths []string) bool { return i == len(paths)-1 }
+
+// readLinesFrom — строки файла с номерами (с 1) от from+1 до to (to<0 — до конца).
+// Возвращает число строк в файле (если дочитан до конца) и false, если handle остановил обход.
+func readLinesFrom(ctx context.Context, path string, idx *logFileIndex, from, to int, handle func(int, []byte) bool) (int, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, true, nil // файл мог быть удалён/перемещён; пропускаем
+	}
+	defer f.Close()
+
+	var reader io.Reader = f
+	lineNo, offset := idx.seek(from)
+	if idx.gz {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return 0, true, nil
+		}
+		defer gr.Close()
+		reader = gr
+	} else if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, true, err
+	}
+
+	sc := bufio.NewScanner(reader)
+	// 4 МБ на строку — чтобы не обрезать длинные JSON-записи
+	sc.Buffer(make([]byte, 0, 256*1024), 4*1024*1024)
+	for sc.Scan() {
+		if lineNo%logIndexStep == 0 && ctx.Err() != nil {
+			return lineNo, false, ctx.Err()
+		}
+		lineNo++
+		if lineNo <= from {
+			continue
+		}
+		if to >= 0 && lineNo > to {
+			return lineNo - 1, true, nil
+		}
+		if !handle(lineNo, sc.Bytes()) {
+			return lineNo, false, nil
+		}
+	}
+	return lineNo, true, nil
+}
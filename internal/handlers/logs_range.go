@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+// rangeDays — дни запроса: day или диапазон from..to (включительно, не длиннее срока хранения;
+// без to — по сегодня, без from — один день to). В ответе только дни с файлами, по возрастанию.
+func (h *AdminLogsHandler) rangeDays(r *http.Request) ([]string, error) {
+	q := r.URL.Query()
+	from, to := q.Get("from"), q.Get("to")
+	switch {
+	case from == "" && to == "":
+		from, to = q.Get("day"), q.Get("day")
+	case to == "":
+		to = time.Now().Local().Format("2006-01-02")
+	case from == "":
+		from = to
+	}
+	if !reDay.MatchString(from) || !reDay.MatchString(to) {
+		return nil, fmt.Errorf("bad day")
+	}
+	start, err1 := time.ParseInLocation("2006-01-02", from, time.Local)
+	end, err2 := time.ParseInLocation("2006-01-02", to, time.Local)
+	if err1 != nil || err2 != nil || end.Before(start) {
+		return nil, fmt.Errorf("bad range")
+	}
+	if retention := h.retentionDays(); end.Sub(start) >= time.Duration(retention)*24*time.Hour {
+		return nil, fmt.Errorf("range is longer than %d days", retention)
+	}
+
+	var days []string
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		day := d.Format("2006-01-02")
+		if files, err := h.listFilesForDay(day); err == nil && len(files) > 0 {
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+// logCursor — позиция в периоде: день и число прочитанных строк этого дня.
+// Строки считаются по всем файлам дня в порядке listFilesForDay, поэтому позиция
+// не сдвигается, когда в текущий файл дописываются новые записи.
+type logCursor struct {
+	day  string
+	line int
+}
+
+func (c logCursor) String() string {
+	return c.day + ":" + strconv.Itoa(c.line)
+}
+
+// parseLogCursor — курсор вида YYYY-MM-DD:N; пустой — начало периода.
+func parseLogCursor(s string, days []string) (logCursor, error) {
+	if s == "" {
+		return logCursor{day: days[0]}, nil
+	}
+	day, line, ok := strings.Cut(s, ":")
+	n, err := strconv.Atoi(line)
+	if !ok || !reDay.MatchString(day) || err != nil || n < 0 {
+		return logCursor{}, fmt.Errorf("bad cursor")
+	}
+	return logCursor{day: day, line: n}, nil
+}
+
+// getLogsRange — GetLogs за период from..to: дни по порядку, курсор переходит через границы файлов и дней.
+func (h *AdminLogsHandler) getLogsRange(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	days, err := h.rangeDays(r)
+	if err != nil {
+		log.Warn("admin logs: некорректный период", zap.Error(err))
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(days) == 0 {
+		helpers.Error(w, http.StatusNotFound, "day not found")
+		return
+	}
+	cur, err := parseLogCursor(r.URL.Query().Get("cursor"), days)
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	f := parseLogFilter(r)
+	limit := clampAtoi(r.URL.Query().Get("limit"), 200, 50, 1000)
+	order := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("order")))
+
+	var items []LogItem
+	next := cur
+	scanned := 0
+	for _, day := range days {
+		if day < cur.day {
+			continue
+		}
+		skip := 0
+		if day == cur.day {
+			skip = cur.line
+		}
+
+		lineNo := skip
+		_ = h.forEachDayLineFrom(r.Context(), day, skip, f.hour, func(n int, raw []byte) bool {
+			lineNo = n
+			scanned++
+			if obj, ok := f.match(raw); ok {
+				items = append(items, toLogItem(obj))
+			}
+			return len(items) < limit
+		})
+		next = logCursor{day: day, line: lineNo}
+		if len(items) >= limit || r.Context().Err() != nil {
+			break
+		}
+	}
+
+	if order == "desc" {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	hasMore := len(items) >= limit
+
+	log.Info("admin logs: логи за период отданы",
+		zap.String("from", days[0]),
+		zap.String("to", days[len(days)-1]),
+		zap.String("cursor", cur.String()),
+		zap.String("next_cursor", next.String()),
+		zap.Int("returned", len(items)),
+		zap.Int("scanned_lines", scanned),
+		zap.Bool("has_more", hasMore),
+	)
+
+	writeJSON(w, http.StatusOK, LogQueryResponse{
+		From:       days[0],
+		To:         days[len(days)-1],
+		Items:      items,
+		NextCursor: next.String(),
+		HasMore:    hasMore,
+	})
+}
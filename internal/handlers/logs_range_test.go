@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/services"
+
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// writeLogFile — n JSON-строк msg "<prefix>-<i>"; .gz сжимается.
+func writeLogFile(t *testing.T, path, prefix string, n int) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var w io.Writer = f
+	if filepath.Ext(path) == ".gz" {
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+		w = gz
+	}
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(w, `{"time":"2026-01-01T10:00:00Z","level":"info","msg":"%s-%d"}`+"\n", prefix, i)
+	}
+}
+
+func TestGetLogsRangeCursor(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().Local()
+	day1 := now.AddDate(0, 0, -2).Format("2006-01-02")
+	day2 := now.AddDate(0, 0, -1).Format("2006-01-02")
+
+	// день 1 — ротированный файл lumberjack и дневной файл, день 2 — сжатый дневной
+	writeLogFile(t, filepath.Join(dir, "app-"+day1+"T10-00-00.000.log"), "a", 30)
+	writeLogFile(t, filepath.Join(dir, "app."+day1+".log"), "b", 40)
+	writeLogFile(t, filepath.Join(dir, "app."+day2+".log.gz"), "c", 45)
+	var want []string
+	for _, f := range []struct {
+		prefix string
+		n      int
+	}{{"a", 30}, {"b", 40}, {"c", 45}} {
+		for i := 1; i <= f.n; i++ {
+			want = append(want, fmt.Sprintf("%s-%d", f.prefix, i))
+		}
+	}
+
+	h := NewAdminLogsHandler(services.NewLogRetentionService(dir, &config.Config{}))
+	var got, cursors []string
+	cursor := ""
+	for page := 0; ; page++ {
+		if page > 5 {
+			t.Fatal("пагинация не заканчивается")
+		}
+		url := fmt.Sprintf("/api/admin/logs?from=%s&to=%s&limit=50&cursor=%s", day1, day2, cursor)
+		rec := httptest.NewRecorder()
+		h.GetLogs(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+		}
+		var resp struct {
+			Items      []LogItem `json:"items"`
+			NextCursor string    `json:"nextCursor"`
+			HasMore    bool      `json:"hasMore"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		for _, it := range resp.Items {
+			got = append(got, it.Message)
+		}
+		cursors = append(cursors, resp.NextCursor)
+		if !resp.HasMore {
+			break
+		}
+		cursor = resp.NextCursor
+	}
+
+	// страницы переходят через границу файлов (30 → 31) и дней без пропусков и повторов
+	wantCursors := []string{day1 + ":50", day2 + ":30", day2 + ":45"}
+	if fmt.Sprint(cursors) != fmt.Sprint(wantCursors) {
+		t.Errorf("cursors = %v, want %v", cursors, wantCursors)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("messages = %v\nwant %v", got, want)
+	}
+}
+
+func TestParseLogCursor(t *testing.T) {
+	days := []string{"2026-01-01", "2026-01-02"}
+	if c, err := parseLogCursor("", days); err != nil || c.String() != "2026-01-01:0" {
+		t.Errorf("empty cursor = %v, %v", c, err)
+	}
+	if c, err := parseLogCursor("2026-01-02:17", days); err != nil || c.String() != "2026-01-02:17" {
+		t.Errorf("cursor = %v, %v", c, err)
+	}
+	for _, bad := range []string{"17", "2026-01-02", "2026-01-02:-1", "day:1"} {
+		if _, err := parseLogCursor(bad, days); err == nil {
+			t.Errorf("parseLogCursor(%q) accepted", bad)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+// GetRetention
+// @Summary      Настройки хранения логов
+// @Description  Сколько дней хранятся файлы логов и через сколько дней они сжимаются в .gz (0 — не сжимать).
+// @Tags         admin-logs
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Success      200 {object} models.LogRetentionSettings
+// @Failure      401 {object} helpers.Response "unauthorized"
+// @Router       /api/admin/logs/retention [get]
+func (h *AdminLogsHandler) GetRetention(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.retention.Settings())
+}
+
+// UpdateRetention
+// @Summary      Изменить хранение логов
+// @Description  Меняет срок хранения (1..365 дней) и сжатия логов этого инстанса; compress_after_days должен быть меньше retention_days. Применяется со следующей очистки.
+// @Tags         admin-logs
+// @Security     ApiKeyAuth
+// @Accept       json
+// @Produce      json
+// @Param        input body models.LogRetentionSettings true "Настройки хранения"
+// @Success      200 {object} models.LogRetentionSettings
+// @Failure      400 {object} helpers.Response "invalid settings"
+// @Failure      401 {object} helpers.Response "unauthorized"
+// @Router       /api/admin/logs/retention [put]
+func (h *AdminLogsHandler) UpdateRetention(w http.ResponseWriter, r *http.Request) {
+	var req models.LogRetentionSettings
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	if err := h.retention.Update(r.Context(), req); err != nil {
+		if errors.Is(err, services.ErrLogRetentionInvalid) {
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		logger.WithCtx(r.Context()).Error("admin logs: не удалось сохранить настройки хранения", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "failed to save settings")
+		return
+	}
+	writeJSON(w, http.StatusOK, h.retention.Settings())
+}
+
+// Cleanup
+// @Summary      Очистить логи сейчас
+// @Description  Сразу сжимает и удаляет файлы логов этого инстанса по текущим настройкам хранения, не дожидаясь планового запуска.
+// @Tags         admin-logs
+// @Security     ApiKeyAuth
+// @Produce      json
+// @Success      200 {object} models.LogCleanupReport
+// @Failure      401 {object} helpers.Response "unauthorized"
+// @Failure      500 {object} helpers.Response "cleanup failed"
+// @Router       /api/admin/logs/cleanup [post]
+func (h *AdminLogsHandler) Cleanup(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	report, err := h.retention.Cleanup(r.Context())
+	if err != nil {
+		log.Error("admin logs: ошибка очистки логов", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "cleanup failed")
+		return
+	}
+	log.Info("admin logs: очистка логов по запросу",
+		zap.Int("compressed", len(report.Compressed)),
+		zap.Int("deleted", len(report.Deleted)),
+	)
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// Параметры live-tail: как часто проверяем файл и шлём keep-alive, чтобы прокси не рвали соединение.
+var (
+	logStreamPoll      = 500 * time.Millisecond
+	logStreamHeartbeat = 15 * time.Second
+)
+
+// logStreamMaxChunk — сколько байт нового хвоста читаем за один опрос.
+const logStreamMaxChunk = 1 << 20
+
+// currentLogFile — файл, в который логгер пишет сейчас: дневной app.YYYY-MM-DD.log,
+// иначе app.log (lumberjack / старый формат).
+func (h *AdminLogsHandler) currentLogFile() string {
+	daily := filepath.Join(h.LogDir, fmt.Sprintf("app.%s.log", time.Now().Local().Format("2006-01-02")))
+	if _, err := os.Stat(daily); err == nil {
+		return daily
+	}
+	return filepath.Join(h.LogDir, "app.log")
+}
+
+// Stream
+// @Summary      Логи в реальном времени (SSE)
+// @Description  Server-Sent Events: новые записи текущего файла логов по мере записи (event: log, data — LogItem).
+// @Description  При переподключении браузер присылает Last-Event-ID — поток продолжится с этого места того же файла.
+// @Description  Раз в 15 секунд приходит комментарий-keepalive.
+// @Description  Нужен заголовок Authorization, поэтому в браузере — fetch-клиент SSE, а не нативный EventSource.
+// @Tags         admin-logs
+// @Security     ApiKeyAuth
+// @Produce      text/event-stream
+// @Param        level      query string false "CSV уровней: debug,info,warn,error,panic,fatal"
+// @Param        q          query string false "Поиск по подстроке"
+// @Param        request_id query string false "Только записи одного запроса (X-Request-ID)"
+// @Success      200 {string} string "text/event-stream"
+// @Failure      401 {object} helpers.Response "unauthorized"
+// @Router       /api/admin/logs/stream [get]
+func (h *AdminLogsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	f := parseLogFilter(r)
+	f.hour = nil // поток — только новые записи, час не нужен
+
+	rc := http.NewResponseController(w)
+	// поток живёт дольше WriteTimeout сервера
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx: не буферизовать
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		log.Warn("admin logs: SSE не поддерживается", zap.Error(err))
+		return
+	}
+
+	path := h.currentLogFile()
+	offset := int64(-1) // -1 — начать с конца файла
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		if file, off, ok := strings.Cut(id, ":"); ok && file == filepath.Base(path) {
+			if n, err := strconv.ParseInt(off, 10, 64); err == nil {
+				offset = n
+			}
+		}
+	}
+
+	log.Info("admin logs: live-поток открыт",
+		zap.String("file", path),
+		zap.Strings("levels", f.levelList),
+		zap.String("request_id", f.requestID),
+	)
+	started := time.Now()
+	sent := 0
+
+	poll := time.NewTicker(logStreamPoll)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(logStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	var partial []byte
+	for {
+		select {
+		case <-r.Context().Done():
+			log.Info("admin logs: live-поток закрыт",
+				zap.Int("sent", sent),
+				zap.Duration("duration", time.Since(started)),
+			)
+			return
+
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			_ = rc.Flush()
+
+		case <-poll.C:
+			// смена дня — логгер открыл новый файл
+			if cur := h.currentLogFile(); cur != path {
+				path, offset, partial = cur, 0, nil
+			}
+
+			chunk, next, err := readLogTail(path, offset)
+			if err != nil {
+				continue // файла ещё нет или он ротируется
+			}
+			if next-int64(len(chunk)) != offset {
+				partial = nil // файл усечён — читаем заново с начала
+			}
+			offset = next
+			if len(chunk) == 0 {
+				continue
+			}
+
+			data := append(partial, chunk...)
+			start := offset - int64(len(data)) // смещение начала data в файле
+			end := bytes.LastIndexByte(data, '\n')
+			if end < 0 {
+				partial = data
+				continue
+			}
+			partial = append([]byte(nil), data[end+1:]...)
+
+			var out bytes.Buffer
+			pos := start
+			for _, raw := range bytes.Split(data[:end], []byte("\n")) {
+				pos += int64(len(raw)) + 1
+				obj, ok := f.match(raw)
+				if !ok {
+					continue
+				}
+				item, _ := json.Marshal(toLogItem(obj))
+				// id — место в файле после записи: с него продолжим после переподключения
+				fmt.Fprintf(&out, "id: %s:%d\nevent: log\ndata: %s\n\n", filepath.Base(path), pos, item)
+				sent++
+			}
+			if out.Len() == 0 {
+				continue
+			}
+
+			if _, err := w.Write(out.Bytes()); err != nil {
+				return
+			}
+			_ = rc.Flush()
+		}
+	}
+}
+
+// readLogTail — новые байты файла начиная с offset (-1 — с конца) и новое смещение.
+// Если файл стал короче (усечён/пересоздан), читаем с начала.
+func readLogTail(path string, offset int64) ([]byte, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, offset, err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return nil, offset, err
+	}
+	size := st.Size()
+	switch {
+	case offset < 0:
+		return nil, size, nil
+	case size < offset:
+		offset = 0
+	case size == offset:
+		return nil, offset, nil
+	}
+
+	n := size - offset
+	if n > logStreamMaxChunk {
+		n = logStreamMaxChunk
+	}
+	buf := make([]byte, n)
+	read, err := f.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return nil, offset, err
+	}
+	return buf[:read], offset + int64(read), nil
+}
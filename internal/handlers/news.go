@@ -1,19 +1,18 @@
 package handlers
 
 import (
-	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
+	"edutalks/internal/config"
 	"edutalks/internal/logger"
 	"edutalks/internal/models"
 	"edutalks/internal/services"
@@ -25,27 +24,38 @@ import (
 
 type NewsHandler struct {
 	newsService *services.NewsService
-	notifier    *services.Notifier
+	scanner     *services.FileScanService
+	views       *services.ContentViewService
+	cfg         *config.Holder
 }
 
-func NewNewsHandler(newsService *services.NewsService, notifier *services.Notifier) *NewsHandler {
-	return &NewsHandler{newsService: newsService, notifier: notifier}
+func NewNewsHandler(newsService *services.NewsService, scanner *services.FileScanService, views *services.ContentViewService, cfg *config.Holder) *NewsHandler {
+	return &NewsHandler{newsService: newsService, scanner: scanner, views: views, cfg: cfg}
 }
 
+// createNewsRequest — publish не передан — новость публикуется сразу (как до появления черновиков),
+// а с publish_at в будущем — по расписанию; false — сохраняется черновиком без рассылки.
 type createNewsRequest struct {
-	Title    string `json:"title"`
-	Content  string `json:"content"`
-	ImageURL string `json:"image_url"`
-	Color    string `json:"color"`
-	Sticker  string `json:"sticker"`
+	Title     string     `json:"title" validate:"required,max=255"`
+	Content   string     `json:"content" validate:"required"`
+	ImageURL  string     `json:"image_url" validate:"url,max=2048"`
+	Color     string     `json:"color" validate:"max=32"`
+	Sticker   string     `json:"sticker" validate:"max=64"`
+	Category  string     `json:"category" validate:"max=64"`
+	Tags      []string   `json:"tags" example:"олимпиады,фгос" validate:"max=5"`
+	Publish   *bool      `json:"publish"`
+	PublishAt *time.Time `json:"publish_at,omitempty" example:"2026-11-01T09:00:00+03:00"`
 }
 
+// updateNewsRequest — category и tags не переданы — не меняются; "tags": [] — очистить.
 type updateNewsRequest struct {
-	Title    string `json:"title"`
-	Content  string `json:"content"`
-	ImageURL string `json:"image_url"`
-	Color    string `json:"color"`
-	Sticker  string `json:"sticker"`
+	Title    string   `json:"title" validate:"required,max=255"`
+	Content  string   `json:"content" validate:"required"`
+	ImageURL string   `json:"image_url" validate:"url,max=2048"`
+	Color    string   `json:"color" validate:"max=32"`
+	Sticker  string   `json:"sticker" validate:"max=64"`
+	Category *string  `json:"category,omitempty" validate:"max=64"`
+	Tags     []string `json:"tags,omitempty" validate:"max=5"`
 }
 
 // CreateNews godoc
@@ -54,17 +64,16 @@ type updateNewsRequest struct {
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
+// @Description publish: false — черновик (без рассылки подписчикам); publish_at в будущем — публикация по расписанию; по умолчанию новость публикуется сразу.
 // @Param input body createNewsRequest true "Данные новости"
-// @Success 201 {string} string "Новость создана"
+// @Success 201 {object} helpers.Response{data=CreatedResponse} "Новость создана"
 // @Failure 400 {string} string "Ошибка запроса"
 // @Router /api/admin/news [post]
 func (h *NewsHandler) CreateNews(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
 	var req createNewsRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Warn("create news: невалидный JSON", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "Невалидный JSON")
+	if !decodeValid(w, r, &req) {
 		return
 	}
 
@@ -79,31 +88,40 @@ func (h *NewsHandler) CreateNews(w http.ResponseWriter, r *http.Request) {
 		zap.String("image_url", req.ImageURL),
 		zap.String("color", req.Color),
 		zap.String("sticker", req.Sticker),
+		zap.String("category", req.Category),
+		zap.Strings("tags", req.Tags),
+		zap.Boolp("publish", req.Publish),
+		zap.Timep("publish_at", req.PublishAt),
 	)
 
 	news := &models.News{
-		Title:     req.Title,
-		Content:   req.Content,
-		ImageURL:  req.ImageURL,
-		Color:     req.Color,
-		Sticker:   req.Sticker,
-		CreatedAt: time.Now(),
+		Title:       req.Title,
+		Content:     req.Content,
+		ImageURL:    req.ImageURL,
+		Color:       req.Color,
+		Sticker:     req.Sticker,
+		Category:    req.Category,
+		Tags:        req.Tags,
+		IsPublished: publishNow(req.Publish, req.PublishAt),
+		PublishAt:   req.PublishAt,
+		CreatedAt:   time.Now(),
 	}
 
 	id, err := h.newsService.Create(r.Context(), news)
+	if errors.Is(err, services.ErrNewsTags) {
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		log.Error("create news: ошибка сервиса", zap.Error(err))
 		helpers.Error(w, http.StatusInternalServerError, "Не удалось создать новость")
 		return
 	}
 
-	ctx := context.WithoutCancel(r.Context())
-	go h.notifier.NotifyNewsPublished(ctx, id, news.Title)
-
 	log.Info("create news: новость создана", zap.Int("news_id", id))
-	helpers.JSON(w, http.StatusCreated, map[string]any{
-		"message": "Новость создана",
-		"id":      id,
+	helpers.JSON(w, http.StatusCreated, CreatedResponse{
+		Message: "Новость создана",
+		ID:      id,
 	})
 }
 
@@ -113,9 +131,43 @@ func (h *NewsHandler) CreateNews(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param page query int false "Номер страницы (начиная с 1)"
 // @Param page_size query int false "Размер страницы"
-// @Success 200 {array} models.News
+// @Param tag query string false "Только с этим тегом"
+// @Param category query string false "Только из этой рубрики"
+// @Success 200 {object} helpers.Response{data=NewsListResponse}
+// @Success 304 {string} string "Не изменилось (If-None-Match)"
 // @Router /api/news [get]
 func (h *NewsHandler) ListNews(w http.ResponseWriter, r *http.Request) {
+	filter := newsFilter(r)
+	filter.Status = models.NewsStatusPublished
+	h.listNews(w, r, filter)
+}
+
+// AdminListNews godoc
+// @Summary Список новостей для админки (с черновиками)
+// @Tags admin-news
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы"
+// @Param tag query string false "Только с этим тегом"
+// @Param category query string false "Только из этой рубрики"
+// @Param status query string false "published|draft|scheduled (пусто — все); scheduled — ближайшие первыми"
+// @Success 200 {object} helpers.Response{data=NewsListResponse}
+// @Failure 400 {object} helpers.Response
+// @Router /api/admin/news [get]
+func (h *NewsHandler) AdminListNews(w http.ResponseWriter, r *http.Request) {
+	filter := newsFilter(r)
+	filter.Status = strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status")))
+	switch filter.Status {
+	case "", models.NewsStatusPublished, models.NewsStatusDraft, models.NewsStatusScheduled:
+	default:
+		helpers.Error(w, http.StatusBadRequest, "status должен быть published|draft|scheduled")
+		return
+	}
+	h.listNews(w, r, filter)
+}
+
+func (h *NewsHandler) listNews(w http.ResponseWriter, r *http.Request, filter models.NewsFilter) {
 	log := logger.WithCtx(r.Context())
 
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
@@ -128,9 +180,10 @@ func (h *NewsHandler) ListNews(w http.ResponseWriter, r *http.Request) {
 	}
 	offset := (page - 1) * pageSize
 
-	log.Info("list news: параметры", zap.Int("page", page), zap.Int("page_size", pageSize), zap.Int("offset", offset))
+	log.Info("list news: параметры", zap.Int("page", page), zap.Int("page_size", pageSize), zap.Int("offset", offset),
+		zap.String("tag", filter.Tag), zap.String("category", filter.Category), zap.String("status", filter.Status))
 
-	newsList, total, err := h.newsService.ListPaginated(r.Context(), pageSize, offset)
+	newsList, total, err := h.newsService.ListPaginated(r.Context(), pageSize, offset, filter)
 	if err != nil {
 		log.Error("list news: ошибка сервиса", zap.Error(err))
 		helpers.Error(w, http.StatusInternalServerError, "Ошибка получения новостей")
@@ -138,20 +191,65 @@ func (h *NewsHandler) ListNews(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Info("list news: успех", zap.Int("returned", len(newsList)), zap.Int("total", total))
-	helpers.JSON(w, http.StatusOK, map[string]interface{}{
-		"data":      newsList,
-		"total":     total,
-		"page":      page,
-		"page_size": pageSize,
+	helpers.JSONConditional(w, r, NewsListResponse{
+		Data:       newsList,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+		Tag:        filter.Tag,
+		Category:   filter.Category,
 	})
 }
 
+// newsFilter — фильтры списка новостей из query: tag, category.
+func newsFilter(r *http.Request) models.NewsFilter {
+	q := r.URL.Query()
+	return models.NewsFilter{
+		Tag:      strings.ToLower(strings.TrimSpace(q.Get("tag"))),
+		Category: strings.TrimSpace(q.Get("category")),
+	}
+}
+
+// NewsFeed godoc
+// @Summary RSS-лента новостей
+// @Tags news
+// @Produce xml
+// @Param tag query string false "Только с этим тегом"
+// @Param category query string false "Только из этой рубрики"
+// @Success 200 {string} string "RSS 2.0"
+// @Router /api/news/feed.rss [get]
+func (h *NewsHandler) NewsFeed(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	filter := newsFilter(r)
+	filter.Status = models.NewsStatusPublished
+	newsList, _, err := h.newsService.ListPaginated(r.Context(), feedLimit, 0, filter)
+	if err != nil {
+		log.Error("news feed: ошибка сервиса", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка получения новостей")
+		return
+	}
+
+	entries := make([]feedEntry, 0, len(newsList))
+	for _, n := range newsList {
+		date := n.CreatedAt
+		if n.PublishedAt != nil {
+			date = *n.PublishedAt
+		}
+		entries = append(entries, feedEntry{
+			Title:   n.Title,
+			Path:    fmt.Sprintf("/news/%d", n.ID),
+			Date:    date,
+			Summary: n.Content,
+		})
+	}
+	writeFeed(w, r, h.cfg.Get().FrontendBaseURL(), "EduTalks — новости", "Новости портала EduTalks", "/news", entries)
+}
+
 // GetNews godoc
 // @Summary Получить новость по ID
 // @Tags news
 // @Produce json
 // @Param id path int true "ID новости"
-// @Success 200 {object} models.News
+// @Success 200 {object} helpers.Response{data=models.News}
 // @Failure 404 {string} string "Не найдено"
 // @Router /api/news/{id} [get]
 func (h *NewsHandler) GetNews(w http.ResponseWriter, r *http.Request) {
@@ -161,11 +259,14 @@ func (h *NewsHandler) GetNews(w http.ResponseWriter, r *http.Request) {
 	log.Info("get news: вход", zap.Int("news_id", id))
 
 	news, err := h.newsService.GetByID(r.Context(), id)
-	if err != nil {
+	if err != nil || !news.IsPublished {
 		log.Warn("get news: новость не найдена", zap.Int("news_id", id))
-		helpers.Error(w, http.StatusNotFound, "Новость не найдена")
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeNewsNotFound, "Новость не найдена")
 		return
 	}
+	if h.views.Record(r, models.ContentKindNews, int64(id)) {
+		news.ViewCount++
+	}
 
 	log.Info("get news: успех", zap.Int("news_id", id))
 	helpers.JSON(w, http.StatusOK, news)
@@ -185,9 +286,7 @@ func (h *NewsHandler) UpdateNews(w http.ResponseWriter, r *http.Request) {
 	id, _ := strconv.Atoi(mux.Vars(r)["id"])
 	var req updateNewsRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Warn("update news: невалидный JSON", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "Невалидный JSON")
+	if !decodeValid(w, r, &req) {
 		return
 	}
 
@@ -203,9 +302,24 @@ func (h *NewsHandler) UpdateNews(w http.ResponseWriter, r *http.Request) {
 		zap.String("image_url", req.ImageURL),
 		zap.String("color", req.Color),
 		zap.String("sticker", req.Sticker),
+		zap.Stringp("category", req.Category),
+		zap.Strings("tags", req.Tags),
 	)
 
-	if err := h.newsService.Update(r.Context(), id, req.Title, req.Content, req.ImageURL, req.Color, req.Sticker); err != nil {
+	err := h.newsService.Update(r.Context(), id, models.NewsUpdate{
+		Title:    req.Title,
+		Content:  req.Content,
+		ImageURL: req.ImageURL,
+		Color:    req.Color,
+		Sticker:  req.Sticker,
+		Category: req.Category,
+		Tags:     req.Tags,
+	})
+	if errors.Is(err, services.ErrNewsTags) {
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
 		log.Error("update news: ошибка сервиса", zap.Error(err), zap.Int("news_id", id))
 		helpers.Error(w, http.StatusInternalServerError, "Ошибка обновления")
 		return
@@ -215,8 +329,75 @@ func (h *NewsHandler) UpdateNews(w http.ResponseWriter, r *http.Request) {
 	helpers.JSON(w, http.StatusOK, "Обновлено")
 }
 
+// setNewsPublishBody — publish_at без publish=true планирует публикацию черновика;
+// publish=true/false публикует или снимает с публикации сразу и отменяет расписание.
+type setNewsPublishBody struct {
+	Publish   *bool      `json:"publish"`
+	PublishAt *time.Time `json:"publish_at,omitempty" example:"2026-11-01T09:00:00+03:00"`
+}
+
+// SetPublishNews godoc
+// @Summary Опубликовать новость, снять с публикации или запланировать (только admin)
+// @Description Подписчики получают письмо только при первой публикации — сразу или в момент publish_at.
+// @Tags admin-news
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID новости"
+// @Param input body setNewsPublishBody true "Флаг публикации или время"
+// @Success 200 {object} helpers.Response{data=models.News}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 409 {object} helpers.Response "Новость уже опубликована"
+// @Router /api/admin/news/{id}/publish [patch]
+func (h *NewsHandler) SetPublishNews(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	var body setNewsPublishBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || (body.Publish == nil && body.PublishAt == nil) {
+		helpers.Error(w, http.StatusBadRequest, "Некорректный запрос: нужен publish или publish_at")
+		return
+	}
+
+	log.Info("publish news: вход", zap.Int("news_id", id), zap.Boolp("publish", body.Publish), zap.Timep("publish_at", body.PublishAt))
+
+	var news *models.News
+	var err error
+	if body.PublishAt != nil && (body.Publish == nil || !*body.Publish) {
+		news, err = h.newsService.Schedule(r.Context(), id, *body.PublishAt)
+	} else {
+		news, err = h.newsService.SetPublish(r.Context(), id, *body.Publish)
+	}
+	switch {
+	case errors.Is(err, services.ErrNewsNotFound):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeNewsNotFound, "Новость не найдена")
+		return
+	case errors.Is(err, services.ErrNewsPublished):
+		helpers.Error(w, http.StatusConflict, "Новость уже опубликована — сначала снимите её с публикации")
+		return
+	}
+	if err != nil {
+		log.Error("publish news: ошибка сервиса", zap.Error(err), zap.Int("news_id", id))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка изменения публикации")
+		return
+	}
+
+	log.Info("publish news: успех", zap.Int("news_id", id), zap.Bool("published", news.IsPublished))
+	helpers.JSON(w, http.StatusOK, news)
+}
+
+// publishNow — публиковать ли новость при создании: явный publish, иначе сразу, если нет publish_at.
+func publishNow(publish *bool, publishAt *time.Time) bool {
+	if publish != nil {
+		return *publish
+	}
+	return publishAt == nil
+}
+
 // DeleteNews godoc
 // @Summary Удалить новость (только admin)
+// @Description Новость уходит в корзину (/api/admin/trash): её можно восстановить, пока она не удалена окончательно.
 // @Tags admin-news
 // @Security ApiKeyAuth
 // @Param id path int true "ID новости"
@@ -251,102 +432,19 @@ func uploadsRoot() string {
 // @Accept mpfd
 // @Produce json
 // @Param file formData file true "Файл изображения (jpeg/png/webp/gif)"
-// @Success 201 {object} map[string]string "url: публичная ссылка"
-// @Failure 400 {object} map[string]string
-// @Failure 413 {object} map[string]string
+// @Success 201 {object} helpers.Response{data=URLResponse} "url: публичная ссылка"
+// @Failure 400 {object} helpers.Response
+// @Failure 413 {object} helpers.Response
+// @Failure 422 {object} helpers.Response "Файл заражён"
+// @Failure 503 {object} helpers.Response "Антивирус недоступен"
 // @Security ApiKeyAuth
 // @Router /api/admin/news/upload [post]
 func (h *NewsHandler) UploadNewsImage(w http.ResponseWriter, r *http.Request) {
-	log := logger.WithCtx(r.Context())
-
-	const maxUpload = 10 << 20 // 10 MiB
-	r.Body = http.MaxBytesReader(w, r.Body, maxUpload)
-
-	if err := r.ParseMultipartForm(maxUpload); err != nil {
-		log.Warn("upload news image: multipart parse error", zap.Error(err))
-		helpers.Error(w, http.StatusRequestEntityTooLarge, "файл слишком большой (макс 10 МБ)")
-		return
-	}
-
-	file, header, err := r.FormFile("file")
-	if err != nil {
-		log.Warn("upload news image: отсутствует поле file", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "поле file обязательно")
-		return
-	}
-	defer file.Close()
-
-	// определить content-type по содержимому
-	sniff := make([]byte, 512)
-	n, _ := file.Read(sniff)
-	contentType := http.DetectContentType(sniff[:n])
-
-	// допустимые типы -> расширения
-	allowedTypes := map[string]string{
-		"image/jpeg": ".jpg",
-		"image/png":  ".png",
-		"image/webp": ".webp",
-		"image/gif":  ".gif",
-	}
-
-	ext, ok := allowedTypes[contentType]
+	img, ok := saveImageUpload(w, r, h.scanner, "news")
 	if !ok {
-		// fallback по имени файла
-		ext = strings.ToLower(filepath.Ext(header.Filename))
-		if ext == ".jpeg" {
-			ext = ".jpg"
-		}
-		if _, ok := map[string]struct{}{".jpg": {}, ".png": {}, ".webp": {}, ".gif": {}}[ext]; !ok {
-			log.Warn("upload news image: недопустимый тип", zap.String("ctype", contentType), zap.String("filename", header.Filename))
-			helpers.Error(w, http.StatusBadRequest, "допустимы только изображения: jpg, png, webp, gif")
-			return
-		}
-	}
-
-	root := uploadsRoot()              // абсолютный путь на диске
-	dir := filepath.Join(root, "news") // /edutalks/uploads/news
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		log.Error("upload news image: mkdir error", zap.Error(err), zap.String("dir", dir))
-		helpers.Error(w, http.StatusInternalServerError, "не удалось создать директорию")
-		return
-	}
-
-	name := fmt.Sprintf("%d_%s%s", time.Now().Unix(), randHex(6), ext)
-	fullPath := filepath.Join(dir, name)
-
-	dst, err := os.Create(fullPath)
-	if err != nil {
-		log.Error("upload news image: create dst error", zap.Error(err), zap.String("path", fullPath))
-		helpers.Error(w, http.StatusInternalServerError, "ошибка сохранения файла")
 		return
 	}
-	defer dst.Close()
-
-	// дописываем уже прочитанные байты и остаток
-	if n > 0 {
-		if _, err := dst.Write(sniff[:n]); err != nil {
-			log.Error("upload news image: запись первых байт не удалась", zap.Error(err))
-			helpers.Error(w, http.StatusInternalServerError, "ошибка записи файла")
-			return
-		}
-	}
-	if _, err := io.Copy(dst, file); err != nil {
-		log.Error("upload news image: запись остатка не удалась", zap.Error(err))
-		helpers.Error(w, http.StatusInternalServerError, "ошибка записи файла")
-		return
-	}
-
-	publicURL := "/uploads/news/" + name
-
-	log.Info("upload news image: успех",
-		zap.String("filename", header.Filename),
-		zap.String("stored_name", name),
-		zap.String("ctype", contentType),
-		zap.String("path", fullPath),
-		zap.String("url", publicURL),
-	)
-
-	helpers.JSON(w, http.StatusCreated, map[string]string{"url": publicURL})
+	helpers.JSON(w, http.StatusCreated, URLResponse{URL: img.URL})
 }
 
 func randHex(n int) string {
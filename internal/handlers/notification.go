@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// Параметры потока уведомлений: keep-alive для прокси и сколько записей читаем за раз.
+var notificationStreamHeartbeat = 15 * time.Second
+
+const notificationStreamBatch = 50
+
+type NotificationHandler struct {
+	svc *services.NotificationService
+}
+
+func NewNotificationHandler(svc *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{svc: svc}
+}
+
+// List godoc
+// @Summary Мои уведомления
+// @Description Уведомления личного кабинета, новые сверху: document_added (новые документы в отслеживаемом разделе), subscription_expiring, broadcast (объявление администрации). unread — всего непрочитанных (для значка), total — с учётом фильтра.
+// @Tags notifications
+// @Security ApiKeyAuth
+// @Produce json
+// @Param unread query bool false "Только непрочитанные"
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=NotificationListResponse}
+// @Failure 401 {object} helpers.Response
+// @Router /api/notifications [get]
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	page, pageSize := pageParams(r)
+	unreadOnly, _ := strconv.ParseBool(r.URL.Query().Get("unread"))
+
+	items, total, unread, err := h.svc.List(r.Context(), userID, unreadOnly, pageSize, (page-1)*pageSize)
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка получения уведомлений", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить уведомления")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, NotificationListResponse{
+		Data:       items,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+		Unread:     unread,
+	})
+}
+
+// UnreadCount godoc
+// @Summary Число непрочитанных уведомлений
+// @Tags notifications
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=UnreadCountResponse}
+// @Failure 401 {object} helpers.Response
+// @Router /api/notifications/unread-count [get]
+func (h *NotificationHandler) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	n, err := h.svc.UnreadCount(r.Context(), userID)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, UnreadCountResponse{Unread: n})
+}
+
+// MarkRead godoc
+// @Summary Отметить уведомление прочитанным
+// @Tags notifications
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID уведомления"
+// @Success 200 {object} helpers.Response{data=UnreadCountResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /api/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id")
+		return
+	}
+
+	if err := h.svc.MarkRead(r.Context(), userID, id); err != nil {
+		if errors.Is(err, services.ErrNotificationNotFound) {
+			helpers.Fail(w, http.StatusNotFound, helpers.CodeNotFound, "Уведомление не найдено")
+			return
+		}
+		logger.WithCtx(r.Context()).Error("Ошибка отметки уведомления", zap.Int64("id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+	h.writeUnread(w, r, userID)
+}
+
+// MarkAllRead godoc
+// @Summary Отметить все уведомления прочитанными
+// @Tags notifications
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=MarkAllReadResponse}
+// @Failure 401 {object} helpers.Response
+// @Router /api/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	n, err := h.svc.MarkAllRead(r.Context(), userID)
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка отметки уведомлений", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, MarkAllReadResponse{Marked: n, Unread: 0})
+}
+
+func (h *NotificationHandler) writeUnread(w http.ResponseWriter, r *http.Request, userID int) {
+	n, err := h.svc.UnreadCount(r.Context(), userID)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, UnreadCountResponse{Unread: n})
+}
+
+// Stream godoc
+// @Summary Уведомления в реальном времени (SSE)
+// @Description Server-Sent Events: при подключении — event: unread с числом непрочитанных, далее каждое новое уведомление — event: notification (id — id уведомления, data — Notification), после них — обновлённый unread.
+// @Description При переподключении браузер присылает Last-Event-ID — сначала придут уведомления, пропущенные за время разрыва.
+// @Description Раз в 15 секунд приходит комментарий-keepalive. Нужен заголовок Authorization, поэтому в браузере — fetch-клиент SSE, а не нативный EventSource.
+// @Tags notifications
+// @Security ApiKeyAuth
+// @Produce text/event-stream
+// @Success 200 {string} string "text/event-stream"
+// @Failure 401 {object} helpers.Response
+// @Router /api/notifications/stream [get]
+func (h *NotificationHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+	log := logger.WithCtx(ctx)
+
+	// подписываемся до чтения последнего id: уведомление между ними не потеряется
+	wake, cancel := h.svc.Subscribe(userID)
+	defer cancel()
+
+	var lastID int64
+	resume := false
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil && id >= 0 {
+			lastID, resume = id, true
+		}
+	}
+	if !resume {
+		id, err := h.svc.LastID(ctx, userID)
+		if err != nil {
+			helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+			return
+		}
+		lastID = id
+	}
+
+	rc := http.NewResponseController(w)
+	// поток живёт дольше WriteTimeout сервера
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx: не буферизовать
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		log.Warn("Уведомления: SSE не поддерживается", zap.Error(err))
+		return
+	}
+
+	// pump — дописывает в поток уведомления после lastID и актуальное число непрочитанных
+	pump := func(force bool) error {
+		var out bytes.Buffer
+		sent := 0
+		for {
+			items, err := h.svc.ListAfter(ctx, userID, lastID, notificationStreamBatch)
+			if err != nil {
+				return err
+			}
+			for _, n := range items {
+				data, _ := json.Marshal(n)
+				fmt.Fprintf(&out, "id: %d\nevent: notification\ndata: %s\n\n", n.ID, data)
+				lastID = n.ID
+			}
+			sent += len(items)
+			if len(items) < notificationStreamBatch {
+				break
+			}
+		}
+		if sent == 0 && !force {
+			return nil
+		}
+		unread, err := h.svc.UnreadCount(ctx, userID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&out, "event: unread\ndata: {\"unread\":%d}\n\n", unread)
+		if _, err := w.Write(out.Bytes()); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	if err := pump(true); err != nil {
+		log.Warn("Уведомления: ошибка потока", zap.Error(err))
+		return
+	}
+	log.Debug("Уведомления: поток открыт", zap.Int("user_id", userID), zap.Int64("last_id", lastID), zap.Bool("resume", resume))
+
+	heartbeat := time.NewTicker(notificationStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("Уведомления: поток закрыт", zap.Int("user_id", userID))
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			_ = rc.Flush()
+		case <-wake:
+			if err := pump(false); err != nil {
+				if ctx.Err() == nil {
+					log.Warn("Уведомления: ошибка потока", zap.Error(err))
+				}
+				return
+			}
+		}
+	}
+}
+
+// ListFollows godoc
+// @Summary Отслеживаемые разделы
+// @Description Разделы, о новых документах в которых (и в их подразделах) приходят уведомления document_added и письма групповой рассылки.
+// @Tags notifications
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=[]models.SectionFollow}
+// @Failure 401 {object} helpers.Response
+// @Router /api/profile/follows/sections [get]
+func (h *NotificationHandler) ListFollows(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	items, err := h.svc.Follows(r.Context(), userID)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, items)
+}
+
+// Follow godoc
+// @Summary Отслеживать раздел
+// @Description Новые и обновлённые документы раздела и его подразделов приходят в уведомления и в групповую рассылку (если она включена в профиле); документы разделов, которые пользователь не отслеживает, в письма не попадают. Повторная подписка не ошибка.
+// @Tags notifications
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID раздела"
+// @Success 200 {object} helpers.Response{data=[]models.SectionFollow}
+// @Failure 404 {object} helpers.Response
+// @Router /api/sections/{id}/follow [post]
+func (h *NotificationHandler) Follow(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	sectionID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || sectionID <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id")
+		return
+	}
+
+	if err := h.svc.Follow(r.Context(), userID, sectionID); err != nil {
+		if errors.Is(err, services.ErrFollowSectionNotFound) {
+			helpers.Fail(w, http.StatusNotFound, helpers.CodeNotFound, "Раздел не найден")
+			return
+		}
+		logger.WithCtx(r.Context()).Error("Ошибка подписки на раздел", zap.Int("section_id", sectionID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+	h.ListFollows(w, r)
+}
+
+// Unfollow godoc
+// @Summary Перестать отслеживать раздел
+// @Tags notifications
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID раздела"
+// @Success 200 {object} helpers.Response{data=[]models.SectionFollow}
+// @Failure 400 {object} helpers.Response
+// @Router /api/sections/{id}/follow [delete]
+func (h *NotificationHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	userID, ok := notificationUser(w, r)
+	if !ok {
+		return
+	}
+	sectionID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || sectionID <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id")
+		return
+	}
+
+	if err := h.svc.Unfollow(r.Context(), userID, sectionID); err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка отписки от раздела", zap.Int("section_id", sectionID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+	h.ListFollows(w, r)
+}
+
+// Broadcast godoc
+// @Summary Объявление в уведомления пользователей
+// @Description Уведомление broadcast всем пользователям (audience=all, по умолчанию) или только с действующей подпиской (subscribers). Открытые SSE-потоки получают его сразу. recipients — сколько уведомлений создано.
+// @Tags Уведомления
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body models.BroadcastRequest true "Объявление"
+// @Success 201 {object} helpers.Response{data=RecipientsResponse}
+// @Failure 400 {object} helpers.Response
+// @Router /api/admin/notifications/broadcast [post]
+func (h *NotificationHandler) Broadcast(w http.ResponseWriter, r *http.Request) {
+	var req models.BroadcastRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	adminID, _ := middleware.UserIDFromContext(r.Context())
+	n, err := h.svc.Broadcast(r.Context(), req, adminID)
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка рассылки объявления", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, RecipientsResponse{Recipients: n})
+}
+
+func notificationUser(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return 0, false
+	}
+	return userID, true
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type NotifyHandler struct {
+	notifier  *services.Notifier
+	campaigns *services.NotifyCampaignService
+}
+
+func NewNotifyHandler(notifier *services.Notifier, campaigns *services.NotifyCampaignService) *NotifyHandler {
+	return &NotifyHandler{notifier: notifier, campaigns: campaigns}
+}
+
+// FlushBatch godoc
+// @Summary Отправить групповую рассылку сейчас
+// @Tags Уведомления
+// @Security ApiKeyAuth
+// @Produce json
+// @Description Отправляет подписчикам накопленные новые и обновлённые материалы, не дожидаясь очередного тика (NOTIFY_BATCH_INTERVAL). items — сколько материалов попало в письмо; 0 — буфер был пуст.
+// @Success 200 {object} helpers.Response{data=FlushBatchResponse}
+// @Failure 401 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 500 {object} helpers.Response "рассылка не ушла, материалы остались в буфере"
+// @Router /api/admin/notify/batch/flush [post]
+func (h *NotifyHandler) FlushBatch(w http.ResponseWriter, r *http.Request) {
+	n, err := h.notifier.Flush(r.Context())
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка ручной групповой рассылки", zap.Int("items_count", n), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось отправить рассылку, материалы остались в очереди")
+		return
+	}
+	logger.WithCtx(r.Context()).Info("Групповая рассылка отправлена вручную", zap.Int("items_count", n))
+	helpers.JSON(w, http.StatusOK, FlushBatchResponse{Items: n})
+}
+
+// CreateCampaign godoc
+// @Summary Запланировать рассылку
+// @Description Письмо и аудитория — как в POST /api/admin/notify. send_at — время первой отправки; repeat: none (по умолчанию), daily, weekly, monthly — повторять с этим шагом.
+// @Tags Уведомления
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body models.NotifyCampaignRequest true "Рассылка"
+// @Success 201 {object} helpers.Response{data=models.NotifyCampaign}
+// @Failure 400 {object} helpers.Response
+// @Router /api/admin/notify/campaigns [post]
+func (h *NotifyHandler) CreateCampaign(w http.ResponseWriter, r *http.Request) {
+	var req models.NotifyCampaignRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	adminID, _ := middleware.UserIDFromContext(r.Context())
+	c, err := h.campaigns.Create(r.Context(), adminID, req)
+	if err != nil {
+		h.campaignError(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, c)
+}
+
+// ListCampaigns godoc
+// @Summary Рассылки по расписанию
+// @Description Со статистикой доставки (по адресатам): queued — ждут отправки, sent, failed. Ожидающие рассылки сверху.
+// @Tags Уведомления
+// @Security ApiKeyAuth
+// @Produce json
+// @Param status query string false "scheduled | sent | cancelled"
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=CampaignListResponse}
+// @Router /api/admin/notify/campaigns [get]
+func (h *NotifyHandler) ListCampaigns(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := pageParams(r)
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.CampaignScheduled, models.CampaignSent, models.CampaignCancelled:
+	default:
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeBadRequest, "status: scheduled, sent или cancelled")
+		return
+	}
+
+	items, total, err := h.campaigns.List(r.Context(), status, pageSize, (page-1)*pageSize)
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка получения списка рассылок", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, CampaignListResponse{
+		Data:       items,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+	})
+}
+
+// GetCampaign godoc
+// @Summary Рассылка по расписанию
+// @Tags Уведомления
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID рассылки"
+// @Success 200 {object} helpers.Response{data=models.NotifyCampaign}
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/notify/campaigns/{id} [get]
+func (h *NotifyHandler) GetCampaign(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignID(w, r)
+	if !ok {
+		return
+	}
+	c, err := h.campaigns.Get(r.Context(), id)
+	if err != nil {
+		h.campaignError(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, c)
+}
+
+// CampaignStats godoc
+// @Summary Статистика доставки рассылки
+// @Description По адресатам за все запуски: queued — в очереди (в том числе на повторе), sent — отправлено, failed — не доставлено.
+// @Tags Уведомления
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID рассылки"
+// @Success 200 {object} helpers.Response{data=models.NotifyCampaignStats}
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/notify/campaigns/{id}/stats [get]
+func (h *NotifyHandler) CampaignStats(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignID(w, r)
+	if !ok {
+		return
+	}
+	c, err := h.campaigns.Get(r.Context(), id)
+	if err != nil {
+		h.campaignError(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, c.Stats)
+}
+
+// UpdateCampaign godoc
+// @Summary Изменить рассылку по расписанию
+// @Description Только ожидающую (status=scheduled); поля заменяются целиком.
+// @Tags Уведомления
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "ID рассылки"
+// @Param input body models.NotifyCampaignRequest true "Рассылка"
+// @Success 200 {object} helpers.Response{data=models.NotifyCampaign}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 409 {object} helpers.Response
+// @Router /api/admin/notify/campaigns/{id} [put]
+func (h *NotifyHandler) UpdateCampaign(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignID(w, r)
+	if !ok {
+		return
+	}
+	var req models.NotifyCampaignRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	c, err := h.campaigns.Update(r.Context(), id, req)
+	if err != nil {
+		h.campaignError(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, c)
+}
+
+// CancelCampaign godoc
+// @Summary Отменить рассылку по расписанию
+// @Description Следующих запусков не будет; письма, уже поставленные в очередь, уходят.
+// @Tags Уведомления
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID рассылки"
+// @Success 200 {object} helpers.Response{data=models.NotifyCampaign}
+// @Failure 404 {object} helpers.Response
+// @Failure 409 {object} helpers.Response
+// @Router /api/admin/notify/campaigns/{id}/cancel [post]
+func (h *NotifyHandler) CancelCampaign(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignID(w, r)
+	if !ok {
+		return
+	}
+	c, err := h.campaigns.Cancel(r.Context(), id)
+	if err != nil {
+		h.campaignError(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, c)
+}
+
+func (h *NotifyHandler) campaignError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrCampaignNotFound):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeNotFound, err.Error())
+	case errors.Is(err, services.ErrCampaignNotEditable):
+		helpers.Fail(w, http.StatusConflict, helpers.CodeConflict, err.Error())
+	case errors.Is(err, services.ErrNotifyNewsURL), errors.Is(err, services.ErrNotifyBadTemplate), errors.Is(err, services.ErrNotifyBadDate):
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeBadRequest, err.Error())
+	default:
+		logger.WithCtx(r.Context()).Error("Ошибка работы с рассылкой", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+	}
+}
+
+func campaignID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id")
+		return 0, false
+	}
+	return id, true
+}
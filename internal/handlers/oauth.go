@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	"edutalks/internal/utils/clientip"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	oauthStateCookie = "oauth_state"
+	oauthCookiePath  = "/api/auth/oauth"
+)
+
+type OAuthHandler struct {
+	svc  *services.OAuthService
+	auth *services.AuthService
+	cfg  *config.Holder
+}
+
+func NewOAuthHandler(svc *services.OAuthService, auth *services.AuthService, cfg *config.Holder) *OAuthHandler {
+	return &OAuthHandler{svc: svc, auth: auth, cfg: cfg}
+}
+
+type oauthStartResponse struct {
+	URL string `json:"url"`
+}
+
+// Providers godoc
+// @Summary Доступные провайдеры входа через соцсети
+// @Tags auth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=[]string}
+// @Router /api/auth/oauth/providers [get]
+func (h *OAuthHandler) Providers(w http.ResponseWriter, r *http.Request) {
+	helpers.JSON(w, http.StatusOK, h.svc.Providers())
+}
+
+// Start godoc
+// @Summary Вход через соцсеть
+// @Tags auth
+// @Description Редирект на страницу входа провайдера. После входа провайдер вернёт пользователя на /api/auth/oauth/{provider}/callback, откуда он попадёт на фронт: {FRONTEND_URL}/oauth/callback#access_token=... или {FRONTEND_URL}/login?oauth_error=...
+// @Param provider path string true "google | yandex"
+// @Success 302 {string} string "Редирект к провайдеру"
+// @Failure 404 {object} helpers.Response
+// @Router /api/auth/oauth/{provider} [get]
+func (h *OAuthHandler) Start(w http.ResponseWriter, r *http.Request) {
+	authURL, ok := h.begin(w, r, 0)
+	if !ok {
+		return
+	}
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// StartLink godoc
+// @Summary Привязать аккаунт соцсети к профилю
+// @Tags profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Description Возвращает ссылку на страницу провайдера, на которую фронт переводит браузер. После входа у провайдера — редирект на {FRONTEND_URL}/profile?oauth_linked={provider} (или oauth_error=...).
+// @Param provider path string true "google | yandex"
+// @Success 200 {object} helpers.Response{data=oauthStartResponse}
+// @Failure 401 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/profile/oauth/{provider} [post]
+func (h *OAuthHandler) StartLink(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	authURL, ok := h.begin(w, r, userID)
+	if !ok {
+		return
+	}
+	helpers.JSON(w, http.StatusOK, oauthStartResponse{URL: authURL})
+}
+
+// begin — ссылка к провайдеру и подписанная cookie, которая привязывает возврат к этому браузеру.
+func (h *OAuthHandler) begin(w http.ResponseWriter, r *http.Request, userID int) (string, bool) {
+	authURL, cookie, err := h.svc.Begin(mux.Vars(r)["provider"], userID)
+	if err != nil {
+		if errors.Is(err, services.ErrOAuthUnknownProvider) {
+			helpers.Fail(w, http.StatusNotFound, helpers.CodeOAuthProvider, err.Error())
+			return "", false
+		}
+		logger.WithCtx(r.Context()).Error("OAuth: ошибка начала входа", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось начать вход")
+		return "", false
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    cookie,
+		Path:     oauthCookiePath,
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return authURL, true
+}
+
+// Callback godoc
+// @Summary Возврат от провайдера входа
+// @Tags auth
+// @Param provider path string true "google | yandex"
+// @Param code query string false "Код авторизации"
+// @Param state query string true "Состояние, выданное при старте"
+// @Success 302 {string} string "Редирект на страницу фронта"
+// @Router /api/auth/oauth/{provider}/callback [get]
+func (h *OAuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+	provider := mux.Vars(r)["provider"]
+	q := r.URL.Query()
+
+	cfg := h.cfg.Get()
+	base := cfg.FrontendBaseURL()
+	fail := func(page, code string) {
+		http.Redirect(w, r, base+page+"?oauth_error="+url.QueryEscape(code), http.StatusFound)
+	}
+
+	var cookie string
+	if c, err := r.Cookie(oauthStateCookie); err == nil {
+		cookie = c.Value
+	}
+	http.SetCookie(w, &http.Cookie{Name: oauthStateCookie, Path: oauthCookiePath, MaxAge: -1, HttpOnly: true, Secure: true})
+
+	// пользователь отказался от входа у провайдера
+	if q.Get("error") != "" || q.Get("code") == "" {
+		fail("/login", "denied")
+		return
+	}
+
+	res, err := h.svc.Complete(r.Context(), provider, q.Get("code"), q.Get("state"), cookie)
+	if err != nil {
+		code := "failed"
+		switch {
+		case errors.Is(err, services.ErrOAuthUnknownProvider):
+			code = "unknown_provider"
+		case errors.Is(err, services.ErrOAuthState):
+			code = "invalid_state"
+		case errors.Is(err, services.ErrOAuthEmailUnverified):
+			code = "email_unverified"
+		case errors.Is(err, services.ErrOAuthEmailTaken):
+			code = "email_taken"
+		case errors.Is(err, services.ErrOAuthIdentityTaken):
+			code = "identity_taken"
+		default:
+			log.Error("OAuth: ошибка входа", zap.String("provider", provider), zap.Error(err))
+		}
+		fail("/login", code)
+		return
+	}
+
+	if res.LinkedTo != 0 {
+		http.Redirect(w, r, base+"/profile?oauth_linked="+url.QueryEscape(provider), http.StatusFound)
+		return
+	}
+
+	accessTTL, _ := time.ParseDuration(cfg.AccessTokenTTL)
+	client := models.SessionClient{
+		UserAgent: sessionUserAgent(r),
+		IP:        clientip.FromRequest(r),
+	}
+	access, err := h.auth.StartSession(r.Context(), res.User, cfg.JWTSecret, accessTTL, client)
+	if err != nil {
+		if errors.Is(err, services.ErrAccountDeletionPending) {
+			fail("/login", "deletion_pending")
+			return
+		}
+		log.Error("OAuth: ошибка создания сессии", zap.Int("user_id", res.User.ID), zap.Error(err))
+		fail("/login", "failed")
+		return
+	}
+
+	log.Info("Вход через соцсеть", zap.Int("user_id", res.User.ID), zap.String("provider", provider), zap.Bool("created", res.Created))
+	// токен — во фрагменте: он не уходит на сервер фронта и не пишется в логи
+	frag := url.Values{}
+	frag.Set("access_token", access)
+	if res.Created {
+		frag.Set("new_user", "1")
+	}
+	http.Redirect(w, r, base+"/oauth/callback#"+frag.Encode(), http.StatusFound)
+}
+
+// ListMyIdentities godoc
+// @Summary Привязанные аккаунты соцсетей
+// @Tags profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=[]models.OAuthIdentity}
+// @Failure 401 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/profile/oauth [get]
+func (h *OAuthHandler) ListMyIdentities(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	list, err := h.svc.Identities(r.Context(), userID)
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка получения привязанных аккаунтов", zap.Int("user_id", userID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить привязанные аккаунты")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// Unlink godoc
+// @Summary Отвязать аккаунт соцсети
+// @Tags profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Param provider path string true "google | yandex"
+// @Success 200 {object} helpers.Response
+// @Failure 401 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/profile/oauth/{provider} [delete]
+func (h *OAuthHandler) Unlink(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	provider := mux.Vars(r)["provider"]
+	if err := h.svc.Unlink(r.Context(), userID, provider); err != nil {
+		if errors.Is(err, services.ErrOAuthNotLinked) {
+			helpers.Fail(w, http.StatusNotFound, helpers.CodeOAuthNotLinked, err.Error())
+			return
+		}
+		logger.WithCtx(r.Context()).Error("Ошибка отвязки аккаунта", zap.Int("user_id", userID), zap.String("provider", provider), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось отвязать аккаунт")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Аккаунт отвязан"})
+}
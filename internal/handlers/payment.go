@@ -1,22 +1,28 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"edutalks/internal/logger"
 	"edutalks/internal/middleware"
+	"edutalks/internal/models"
 	"edutalks/internal/services"
 	"edutalks/internal/utils/helpers"
 
+	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
 
 type PaymentHandler struct {
 	YooKassaService *services.YooKassaService
+	Invoices        *services.InvoiceService
 }
 
-func NewPaymentHandler(yoo *services.YooKassaService) *PaymentHandler {
-	return &PaymentHandler{YooKassaService: yoo}
+func NewPaymentHandler(yoo *services.YooKassaService, invoices *services.InvoiceService) *PaymentHandler {
+	return &PaymentHandler{YooKassaService: yoo, Invoices: invoices}
 }
 
 type PaymentResult struct {
@@ -40,7 +46,7 @@ func (h *PaymentHandler) CreatePayment(w http.ResponseWriter, r *http.Request) {
 	plan := r.URL.Query().Get("plan")
 	if plan == "" {
 		log.Warn("create payment: отсутствует параметр plan")
-		helpers.Error(w, http.StatusBadRequest, "missing plan")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodePaymentInvalidPlan, "missing plan")
 		return
 	}
 
@@ -68,7 +74,7 @@ func (h *PaymentHandler) CreatePayment(w http.ResponseWriter, r *http.Request) {
 		description = "Годовая подписка"
 	default:
 		log.Warn("create payment: неверный план", zap.String("plan", plan))
-		helpers.Error(w, http.StatusBadRequest, "invalid plan")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodePaymentInvalidPlan, "invalid plan")
 		return
 	}
 
@@ -89,3 +95,149 @@ func (h *PaymentHandler) CreatePayment(w http.ResponseWriter, r *http.Request) {
 	log.Info("create payment: ссылка получена", zap.String("confirmation_url", paymentURL))
 	helpers.JSON(w, http.StatusOK, PaymentResult{ConfirmationURL: paymentURL})
 }
+
+// MyPayments godoc
+// @Summary История платежей текущего пользователя
+// @Tags Оплата
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=PaymentListResponse}
+// @Failure 401 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/payments [get]
+func (h *PaymentHandler) MyPayments(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	userID, ok := r.Context().Value(middleware.ContextUserID).(int)
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	page, pageSize := pageParams(r)
+	items, total, err := h.YooKassaService.ListPayments(r.Context(), models.PaymentFilter{UserID: &userID}, pageSize, (page-1)*pageSize)
+	if err != nil {
+		log.Error("payments: ошибка получения истории платежей", zap.Int("user_id", userID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "failed to load payments")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, PaymentListResponse{
+		Data:       items,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+	})
+}
+
+// AdminPayments godoc
+// @Summary Все платежи (для сверки с ЮKassa)
+// @Tags Оплата
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Param user_id query int false "ID пользователя"
+// @Param status query string false "pending|succeeded|canceled|failed"
+// @Param plan query string false "monthly|halfyear|yearly"
+// @Param provider_payment_id query string false "ID платежа в ЮKassa"
+// @Param from query string false "Начало периода (YYYY-MM-DD или RFC3339)"
+// @Param to query string false "Конец периода (дата включительно)"
+// @Success 200 {object} helpers.Response{data=PaymentListResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/payments [get]
+func (h *PaymentHandler) AdminPayments(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+	q := r.URL.Query()
+
+	f := models.PaymentFilter{
+		Status:            strings.TrimSpace(q.Get("status")),
+		Plan:              strings.TrimSpace(q.Get("plan")),
+		ProviderPaymentID: strings.TrimSpace(q.Get("provider_payment_id")),
+	}
+	if v := strings.TrimSpace(q.Get("user_id")); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "invalid user_id")
+			return
+		}
+		f.UserID = &id
+	}
+	var err error
+	if f.From, err = parseDateParam(q.Get("from"), false); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid from")
+		return
+	}
+	if f.To, err = parseDateParam(q.Get("to"), true); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid to")
+		return
+	}
+
+	page, pageSize := pageParams(r)
+	items, total, err := h.YooKassaService.ListPayments(r.Context(), f, pageSize, (page-1)*pageSize)
+	if err != nil {
+		log.Error("payments: ошибка получения списка платежей (admin)", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "failed to load payments")
+		return
+	}
+
+	log.Info("payments: список платежей получен", zap.Int("count", len(items)), zap.Int("total", total))
+	helpers.JSON(w, http.StatusOK, PaymentListResponse{
+		Data:       items,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+	})
+}
+
+// Invoice godoc
+// @Summary Квитанция об оплате (PDF)
+// @Description Квитанция по успешному платежу текущего пользователя: реквизиты продавца, сквозной номер. Номер присваивается при первом запросе (или при автоматической отправке на почту после оплаты) и дальше не меняется.
+// @Tags Оплата
+// @Security ApiKeyAuth
+// @Produce application/pdf
+// @Param id path int true "ID платежа (из истории платежей)"
+// @Success 200 {file} file "PDF"
+// @Failure 401 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 409 {object} helpers.Response "Платёж не завершён успешно"
+// @Failure 503 {object} helpers.Response "Квитанции не настроены"
+// @Router /api/profile/payments/{id}/invoice [get]
+func (h *PaymentHandler) Invoice(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	userID, ok := r.Context().Value(middleware.ContextUserID).(int)
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	paymentID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || paymentID <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "invalid id")
+		return
+	}
+
+	inv, data, err := h.Invoices.ForUser(r.Context(), userID, paymentID)
+	switch {
+	case errors.Is(err, services.ErrInvoicePaymentNotFound):
+		helpers.Error(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, services.ErrInvoiceNotPaid):
+		helpers.Error(w, http.StatusConflict, err.Error())
+		return
+	case errors.Is(err, services.ErrInvoiceUnavailable):
+		helpers.Error(w, http.StatusServiceUnavailable, err.Error())
+		return
+	case err != nil:
+		log.Error("payments: ошибка формирования квитанции", zap.Int("user_id", userID), zap.Int64("payment_id", paymentID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "failed to build invoice")
+		return
+	}
+
+	log.Info("payments: квитанция выдана", zap.Int("user_id", userID), zap.Int64("payment_id", paymentID), zap.String("number", inv.Number))
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+services.InvoiceFilename(inv)+"\"")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+// SandboxWebhookRequest — какое уведомление ЮKassa эмулировать по sandbox-платежу.
+type SandboxWebhookRequest struct {
+	PaymentID string `json:"payment_id"`
+	Event     string `json:"event"` // payment.succeeded (по умолчанию) | payment.canceled
+}
+
+// sandboxEventStatus — события, которые умеет эмулировать песочница, и статус платежа в них.
+var sandboxEventStatus = map[string]string{
+	"payment.succeeded": models.PaymentStatusSucceeded,
+	"payment.canceled":  models.PaymentStatusCanceled,
+}
+
+// SandboxWebhook godoc
+// @Summary Эмулировать уведомление ЮKassa (песочница)
+// @Tags Оплата
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Description Доступно только при PAYMENT_SANDBOX=true. Обрабатывает sandbox-платёж так же, как настоящее уведомление ЮKassa: история платежей, выдача/продление подписки, письмо.
+// @Param input body handlers.SandboxWebhookRequest true "ID sandbox-платежа и событие"
+// @Success 200 {object} helpers.Response{data=WebhookAckResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/payments/sandbox/webhook [post]
+func (h *WebhookHandler) SandboxWebhook(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	if !h.Sandbox {
+		helpers.Error(w, http.StatusNotFound, "payment sandbox is disabled")
+		return
+	}
+
+	var req SandboxWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "invalid json")
+		return
+	}
+	req.PaymentID = strings.TrimSpace(req.PaymentID)
+	if req.Event == "" {
+		req.Event = "payment.succeeded"
+	}
+	status, ok := sandboxEventStatus[req.Event]
+	if !ok {
+		helpers.Error(w, http.StatusBadRequest, "event must be payment.succeeded or payment.canceled")
+		return
+	}
+	// настоящие платежи через песочницу не подтверждаем
+	if !strings.HasPrefix(req.PaymentID, services.SandboxPaymentPrefix) {
+		helpers.Error(w, http.StatusBadRequest, "payment_id must be a sandbox payment")
+		return
+	}
+
+	items, _, err := h.Payments.List(r.Context(), models.PaymentFilter{ProviderPaymentID: req.PaymentID}, 1, 0)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to load payment")
+		return
+	}
+	if len(items) == 0 {
+		helpers.Fail(w, http.StatusNotFound, helpers.CodePaymentNotFound, "payment not found")
+		return
+	}
+	p := items[0]
+
+	var webhook PaymentWebhook
+	webhook.Event = req.Event
+	webhook.Object.ID = req.PaymentID
+	webhook.Object.Status = status
+	webhook.Object.Amount.Value = strconv.FormatFloat(p.Amount, 'f', 2, 64)
+	webhook.Object.Amount.Currency = p.Currency
+	webhook.Object.Description = p.Description
+	webhook.Object.Metadata.UserID = strconv.Itoa(p.UserID)
+	webhook.Object.Metadata.Plan = p.Plan
+
+	raw, _ := json.Marshal(webhook)
+
+	log.Info("webhook: эмуляция уведомления (sandbox)",
+		zap.String("event", req.Event),
+		zap.String("payment_id", req.PaymentID),
+		zap.Int("user_id", p.UserID),
+	)
+	h.processWebhook(w, r, webhook, raw, "sandbox")
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+type PhoneVerificationHandler struct {
+	svc *services.PhoneVerificationService
+}
+
+func NewPhoneVerificationHandler(svc *services.PhoneVerificationService) *PhoneVerificationHandler {
+	return &PhoneVerificationHandler{svc: svc}
+}
+
+type phoneCodeRequest struct {
+	Code string `json:"code" example:"123456"`
+}
+
+type phoneForgotRequest struct {
+	Phone string `json:"phone" example:"+7 999 123-45-67"`
+}
+
+type phoneResetRequest struct {
+	Phone       string `json:"phone" validate:"required,notblank" example:"+7 999 123-45-67"`
+	Code        string `json:"code" validate:"required,notblank" example:"123456"`
+	NewPassword string `json:"new_password" validate:"required,password"`
+}
+
+// RequestPhoneVerification godoc
+// @Summary Отправить код подтверждения телефона
+// @Tags profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Description SMS с кодом из 6 цифр уходит на номер из профиля. Новый код — не чаще раза в минуту (SMS_RESEND_COOLDOWN); после 5 неверных вводов код нужно запросить заново. Смена номера в профиле сбрасывает подтверждение.
+// @Success 202 {object} helpers.Response{data=models.PhoneCodeStatus}
+// @Failure 400 {object} helpers.Response "PHONE_MISSING или некорректный номер"
+// @Failure 401 {object} helpers.Response
+// @Failure 409 {object} helpers.Response "PHONE_ALREADY_VERIFIED"
+// @Failure 429 {object} helpers.Response "details: retry_after_sec, resend_at, expires_at"
+// @Failure 503 {object} helpers.Response "SMS не настроены или провайдер недоступен"
+// @Router /api/profile/phone/verification [post]
+func (h *PhoneVerificationHandler) RequestPhoneVerification(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+
+	status, err := h.svc.RequestVerification(r.Context(), userID)
+	switch {
+	case errors.Is(err, services.ErrPhoneCodeCooldown):
+		writeCodeCooldown(w, status, err)
+		return
+	case errors.Is(err, services.ErrPhoneMissing):
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodePhoneMissing, err.Error())
+		return
+	case errors.Is(err, services.ErrPhoneInvalid):
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeValidationFailed, err.Error())
+		return
+	case errors.Is(err, services.ErrPhoneAlreadyVerified):
+		helpers.Fail(w, http.StatusConflict, helpers.CodePhoneAlreadyVerified, err.Error())
+		return
+	case errors.Is(err, services.ErrSMSDisabled), errors.Is(err, services.ErrPhoneSendFailed):
+		helpers.Fail(w, http.StatusServiceUnavailable, helpers.CodeServiceUnavailable, err.Error())
+		return
+	case err != nil:
+		log.Error("Ошибка отправки кода подтверждения телефона", zap.Int("user_id", userID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось отправить код")
+		return
+	}
+
+	helpers.JSON(w, http.StatusAccepted, status)
+}
+
+// ConfirmPhoneVerification godoc
+// @Summary Подтвердить телефон кодом из SMS
+// @Tags profile
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body phoneCodeRequest true "Код из SMS"
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 400 {object} helpers.Response "PHONE_CODE_INVALID — код неверный, устарел или номер сменили после отправки"
+// @Failure 401 {object} helpers.Response
+// @Failure 409 {object} helpers.Response "PHONE_TAKEN — номер уже подтверждён другим пользователем"
+// @Failure 429 {object} helpers.Response "PHONE_CODE_ATTEMPTS_EXCEEDED"
+// @Router /api/profile/phone/verification/confirm [post]
+func (h *PhoneVerificationHandler) ConfirmPhoneVerification(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+
+	var req phoneCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Code) == "" {
+		helpers.Error(w, http.StatusBadRequest, "Укажите код из SMS")
+		return
+	}
+
+	if err := h.svc.ConfirmVerification(r.Context(), userID, req.Code); err != nil {
+		if errors.Is(err, services.ErrPhoneTaken) {
+			helpers.Fail(w, http.StatusConflict, helpers.CodePhoneTaken, err.Error())
+			return
+		}
+		if !writeCodeError(w, err) {
+			log.Error("Ошибка подтверждения телефона", zap.Int("user_id", userID), zap.Error(err))
+			helpers.Error(w, http.StatusInternalServerError, "Не удалось подтвердить телефон")
+		}
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Телефон подтверждён"})
+}
+
+// ForgotByPhone godoc
+// @Summary Запрос сброса пароля по телефону
+// @Description Отправляет SMS с кодом сброса пароля. Ответ одинаковый, даже если номер не найден; по умолчанию код приходит только на подтверждённый номер (PHONE_RESET_REQUIRE_VERIFIED).
+// @Tags password
+// @Accept json
+// @Produce json
+// @Param input body phoneForgotRequest true "Номер телефона"
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 503 {object} helpers.Response "SMS не настроены"
+// @Router /api/password/forgot-phone [post]
+func (h *PhoneVerificationHandler) ForgotByPhone(w http.ResponseWriter, r *http.Request) {
+	var req phoneForgotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Phone) == "" {
+		helpers.Error(w, http.StatusBadRequest, "Укажите номер телефона")
+		return
+	}
+
+	switch err := h.svc.RequestReset(r.Context(), req.Phone); {
+	case errors.Is(err, services.ErrSMSDisabled):
+		helpers.Fail(w, http.StatusServiceUnavailable, helpers.CodeServiceUnavailable, err.Error())
+		return
+	case errors.Is(err, services.ErrPhoneInvalid):
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeValidationFailed, err.Error())
+		return
+	}
+
+	logger.WithCtx(r.Context()).Info("Запрошен сброс пароля по телефону", zap.String("phone_masked", maskPhone(req.Phone)))
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "If the phone number exists, a reset code has been sent."})
+}
+
+// ResetByPhone godoc
+// @Summary Сброс пароля по коду из SMS
+// @Tags password
+// @Accept json
+// @Produce json
+// @Param input body phoneResetRequest true "Номер, код из SMS и новый пароль (от 8 символов, буквы и цифры, до 72 байт)"
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 400 {object} helpers.Response "PHONE_CODE_INVALID или VALIDATION_FAILED"
+// @Failure 429 {object} helpers.Response "PHONE_CODE_ATTEMPTS_EXCEEDED"
+// @Router /api/password/reset-phone [post]
+func (h *PhoneVerificationHandler) ResetByPhone(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	var req phoneResetRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	if err := h.svc.ResetPassword(r.Context(), req.Phone, req.Code, req.NewPassword); err != nil {
+		switch {
+		case writeCodeError(w, err):
+			log.Warn("Сброс пароля по телефону: код не принят", zap.String("phone_masked", maskPhone(req.Phone)), zap.Error(err))
+		default:
+			log.Error("Ошибка сброса пароля по телефону", zap.Error(err))
+			helpers.Error(w, http.StatusInternalServerError, "Не удалось сбросить пароль")
+		}
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Password has been reset."})
+}
+
+// writeCodeError — ответ на неверный код из SMS; false — ошибка другая, ответ не записан.
+func writeCodeError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrPhoneCodeInvalid):
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodePhoneCodeInvalid, err.Error())
+	case errors.Is(err, services.ErrPhoneCodeAttempts):
+		helpers.Fail(w, http.StatusTooManyRequests, helpers.CodePhoneCodeAttempts, err.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+// writeCodeCooldown — 429: новый код пока нельзя, предыдущий ещё действует.
+func writeCodeCooldown(w http.ResponseWriter, status *models.PhoneCodeStatus, err error) {
+	remaining := int(time.Until(status.ResendAt).Seconds()) + 1
+	w.Header().Set("Retry-After", strconv.Itoa(remaining))
+	helpers.FailWithDetails(w, http.StatusTooManyRequests, helpers.CodeRateLimited, err.Error(), map[string]any{
+		"retry_after_sec": remaining,
+		"resend_at":       status.ResendAt.UTC(),
+		"expires_at":      status.ExpiresAt.UTC(),
+	})
+}
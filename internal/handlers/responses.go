@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"time"
+
+	"edutalks/internal/models"
+)
+
+// Типы ответов для Swagger: хендлеры отдают их через helpers.JSON, поэтому в аннотациях
+// они стоят в поле data — helpers.Response{data=...}.
+
+// MessageResponse — ответ без данных, только сообщение для человека.
+type MessageResponse struct {
+	Message string `json:"message" example:"ok"`
+}
+
+// IDResponse — ID созданной записи.
+type IDResponse struct {
+	ID int `json:"id" example:"42"`
+}
+
+// URLResponse — публичная ссылка на загруженный файл.
+type URLResponse struct {
+	URL string `json:"url"`
+}
+
+// AvatarResponse — ссылка на аватар профиля (256 px).
+type AvatarResponse struct {
+	AvatarURL string `json:"avatar_url" example:"/uploads/avatars/7_1760000000_a1b2c3d4_256.jpg"`
+}
+
+// Pagination — поля постраничных списков (pageParams).
+type Pagination struct {
+	Total    int `json:"total" example:"120"`
+	Page     int `json:"page" example:"1"`
+	PageSize int `json:"page_size" example:"20"`
+}
+
+// PaginatedUsersResponse — GET /api/admin/users: страница пользователей и применённые фильтры.
+type PaginatedUsersResponse struct {
+	Data []*models.User `json:"data"`
+	Pagination
+	Q               string  `json:"q"`
+	Role            *string `json:"role"`
+	HasSubscription *bool   `json:"has_subscription"`
+}
+
+// SubscriptionStateResponse — состояние подписки пользователя после изменения админом.
+type SubscriptionStateResponse struct {
+	UserID                int        `json:"user_id"`
+	HasSubscription       bool       `json:"has_subscription"`
+	SubscriptionExpiresAt *time.Time `json:"subscription_expires_at"`
+	IsActive              bool       `json:"is_active"`
+}
+
+// NotifyResultResponse — итог рассылки подписчикам; с dry_run — только число адресатов.
+type NotifyResultResponse struct {
+	Message    string `json:"message,omitempty"`
+	DryRun     bool   `json:"dry_run,omitempty"`
+	Recipients int    `json:"recipients"`
+}
+
+// DocumentListResponse — GET /api/files: все подходящие документы и применённые фильтры.
+type DocumentListResponse struct {
+	Data      []*models.Document `json:"data"`
+	Total     int                `json:"total"`
+	Category  string             `json:"category"`
+	SectionID *int               `json:"section_id"`
+}
+
+// DocumentPreviewListResponse — GET /api/documents/preview: страница карточек документов.
+type DocumentPreviewListResponse struct {
+	Data []models.DocumentPreviewResponse `json:"data"`
+	Pagination
+	Category string `json:"category"`
+}
+
+// UploadedDocument — карточка только что загруженного документа.
+type UploadedDocument struct {
+	ID                int              `json:"id"`
+	Title             string           `json:"title"`
+	Filename          string           `json:"filename"`
+	Description       string           `json:"description"`
+	Category          string           `json:"category"`
+	SectionID         *int             `json:"section_id"`
+	IsPublic          bool             `json:"is_public"`
+	UploadedAt        time.Time        `json:"uploaded_at"`
+	AllowFreeDownload bool             `json:"allow_free_download"`
+	HasTextLayer      bool             `json:"has_text_layer"`
+	LargePrintURL     *string          `json:"large_print_url"`
+	AudioURL          *string          `json:"audio_url"`
+	DocNumber         *string          `json:"doc_number"`
+	IssuingAuthority  *string          `json:"issuing_authority"`
+	AdoptedAt         *time.Time       `json:"adopted_at"`
+	EffectiveAt       *time.Time       `json:"effective_at"`
+	ScanStatus        string           `json:"scan_status"`
+	Duplicate         *UploadDuplicate `json:"duplicate,omitempty"`
+}
+
+// UploadDuplicate — загруженный файл уже есть у других документов; linked — копия не сохранена,
+// документ ссылается на файл первого из них (DUPLICATE_UPLOAD_MODE=link).
+type UploadDuplicate struct {
+	Linked    bool                       `json:"linked"`
+	Documents []models.DocumentDuplicate `json:"documents"`
+}
+
+// UploadDocumentResponse — ответ на загрузку одного файла; id дублируется для старых клиентов.
+type UploadDocumentResponse struct {
+	ID   int              `json:"id"`
+	Data UploadedDocument `json:"data"`
+}
+
+// GlobalSearchResponse — результаты глобального поиска по новостям и документам.
+type GlobalSearchResponse struct {
+	News      []models.News     `json:"news"`
+	Documents []models.Document `json:"documents"`
+}
+
+// TaxonomyTreeResponse — дерево вкладок и разделов.
+type TaxonomyTreeResponse struct {
+	Data []models.TabTree `json:"data"`
+}
+
+// TaxonomySearchResponse — вкладки и разделы, найденные по названию.
+type TaxonomySearchResponse struct {
+	Data []models.TaxonomySearchItem `json:"data"`
+}
+
+// NotificationListResponse — страница уведомлений и общее число непрочитанных.
+type NotificationListResponse struct {
+	Data []models.Notification `json:"data"`
+	Pagination
+	Unread int `json:"unread"`
+}
+
+// UnreadCountResponse — число непрочитанных уведомлений.
+type UnreadCountResponse struct {
+	Unread int `json:"unread"`
+}
+
+// MarkAllReadResponse — сколько уведомлений отмечено; непрочитанных после этого не остаётся.
+type MarkAllReadResponse struct {
+	Marked int `json:"marked"`
+	Unread int `json:"unread"`
+}
+
+// RecipientsResponse — сколько адресатов получили уведомление.
+type RecipientsResponse struct {
+	Recipients int `json:"recipients"`
+}
+
+// FlushBatchResponse — сколько материалов попало в групповую рассылку.
+type FlushBatchResponse struct {
+	Items int `json:"items"`
+}
+
+// CampaignListResponse — страница рассылок по расписанию.
+type CampaignListResponse struct {
+	Data []*models.NotifyCampaign `json:"data"`
+	Pagination
+}
+
+// ChangelogListResponse — страница записей changelog.
+type ChangelogListResponse struct {
+	Data []models.ChangelogEntry `json:"data"`
+	Pagination
+}
+
+// UserActivityListResponse — страница ленты действий пользователя.
+type UserActivityListResponse struct {
+	Data []models.UserActivity `json:"data"`
+	Pagination
+}
+
+// AnnouncementListResponse — страница баннеров.
+type AnnouncementListResponse struct {
+	Data []models.Announcement `json:"data"`
+	Pagination
+}
+
+// ArticleMediaListResponse — страница медиатеки статей.
+type ArticleMediaListResponse struct {
+	Data []models.ArticleMedia `json:"data"`
+	Pagination
+}
+
+// PaymentListResponse — страница платежей.
+type PaymentListResponse struct {
+	Data []models.Payment `json:"data"`
+	Pagination
+}
+
+// EmailOutboxListResponse — страница писем из outbox.
+type EmailOutboxListResponse struct {
+	Data []models.EmailOutbox `json:"data"`
+	Pagination
+}
+
+// DownloadStatsResponse — скачивания по документам и временной ряд по дням.
+type DownloadStatsResponse struct {
+	Data []models.DocumentDownloadStat `json:"data"`
+	Pagination
+	Series []models.DownloadsDayPoint `json:"series"`
+}
+
+// AdminDigestResponse — сводка; recipients — только если она отправлена.
+type AdminDigestResponse struct {
+	Sent       bool                `json:"sent"`
+	Recipients int                 `json:"recipients,omitempty"`
+	Digest     *models.AdminDigest `json:"digest"`
+}
+
+// ArticlePreviewResponse — очищенный HTML статьи.
+type ArticlePreviewResponse struct {
+	BodyHTML string `json:"bodyHtml"`
+}
+
+// WebhookAckResponse — уведомление о платеже принято.
+type WebhookAckResponse struct {
+	Status string `json:"status" example:"ok"`
+}
+
+// CreatedResponse — ID созданной записи с сообщением для человека.
+type CreatedResponse struct {
+	Message string `json:"message"`
+	ID      int    `json:"id"`
+}
+
+// DocumentResponse — карточка документа после изменения.
+type DocumentResponse struct {
+	Data *models.Document `json:"data"`
+}
+
+// DocumentsResponse — список документов для админки.
+type DocumentsResponse struct {
+	Data []*models.Document `json:"data"`
+}
+
+// DocumentPreviewItemResponse — превью одного документа.
+type DocumentPreviewItemResponse struct {
+	Item models.DocumentPreviewResponse `json:"item"`
+}
+
+// DocumentLinksResponse — связи документа с другими документами.
+type DocumentLinksResponse struct {
+	Data []models.DocumentLink `json:"data"`
+}
+
+// DocumentRelationResponse — созданная связь документов.
+type DocumentRelationResponse struct {
+	Data *models.DocumentRelation `json:"data"`
+}
+
+// DocumentGrantsResponse — персональные доступы к документу.
+type DocumentGrantsResponse struct {
+	Data []models.DocumentGrant `json:"data"`
+}
+
+// DocumentGrantResponse — выданный доступ к документу.
+type DocumentGrantResponse struct {
+	Data *models.DocumentGrant `json:"data"`
+}
+
+// TrendingResponse — популярные материалы за окно window_sec.
+type TrendingResponse struct {
+	Items     []models.TrendingItem `json:"items"`
+	Type      string                `json:"type"`
+	WindowSec int64                 `json:"window_sec"`
+}
+
+// StatsSeriesResponse — ряд графика дашборда за период from..to (YYYY-MM-DD); тип items
+// зависит от графика и указывается в аннотации хендлера.
+type StatsSeriesResponse struct {
+	From  string `json:"from" example:"2026-01-01"`
+	To    string `json:"to" example:"2026-01-31"`
+	Items any    `json:"items"`
+}
+
+// NewsListResponse — страница новостей и применённые фильтры.
+type NewsListResponse struct {
+	Data []*models.News `json:"data"`
+	Pagination
+	Tag      string `json:"tag"`
+	Category string `json:"category"`
+}
+
+// IDsResponse — ID записей в новом порядке.
+type IDsResponse struct {
+	IDs []int `json:"ids"`
+}
+
+// AlertTestResponse — итог тестовой отправки по каждому каналу оповещений.
+type AlertTestResponse struct {
+	Results []models.AlertTestResult `json:"results"`
+}
+
+// TrashListResponse — страница корзины.
+type TrashListResponse struct {
+	Data []models.TrashItem `json:"data"`
+	Pagination
+}
+
+// DocumentDuplicatesResponse — страница групп дубликатов файлов и место под лишние копии по всем группам.
+type DocumentDuplicatesResponse struct {
+	Data []models.DocumentDuplicateGroup `json:"data"`
+	Pagination
+	WastedBytes int64 `json:"wasted_bytes" example:"52428800"`
+}
+
+// MyDocumentsResponse — страница документов, загруженных автором.
+type MyDocumentsResponse struct {
+	Data []*models.Document `json:"data"`
+	Pagination
+}
+
+// ReviewQueueResponse — страница очереди согласования.
+type ReviewQueueResponse struct {
+	Data []models.ReviewItem `json:"data"`
+	Pagination
+}
+
+// ReviewEventResponse — выполненный переход согласования.
+type ReviewEventResponse struct {
+	Data *models.ReviewEvent `json:"data"`
+}
+
+// ReviewHistoryResponse — история согласования материала.
+type ReviewHistoryResponse struct {
+	Data []models.ReviewEvent `json:"data"`
+}
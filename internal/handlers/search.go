@@ -32,7 +32,7 @@ func NewSearchHandler(
 // @Tags search
 // @Produce json
 // @Param query query string true "Поисковый запрос"
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} helpers.Response{data=GlobalSearchResponse}
 // @Failure 400 {string} string "Пустой запрос"
 // @Router /api/search [get]
 func (h *SearchHandler) GlobalSearch(w http.ResponseWriter, r *http.Request) {
@@ -66,10 +66,8 @@ func (h *SearchHandler) GlobalSearch(w http.ResponseWriter, r *http.Request) {
 		zap.Duration("elapsed", elapsed),
 	)
 
-	results := map[string]interface{}{
-		"news":      newsResults,
-		"documents": documentResults,
-	}
-
-	helpers.JSON(w, http.StatusOK, results)
+	helpers.JSON(w, http.StatusOK, GlobalSearchResponse{
+		News:      newsResults,
+		Documents: documentResults,
+	})
 }
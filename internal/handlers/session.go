@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const maxSessionUserAgent = 512
+
+// sessionUserAgent — User-Agent для списка сессий (обрезается до разумной длины).
+func sessionUserAgent(r *http.Request) string {
+	ua := []rune(r.UserAgent())
+	if len(ua) > maxSessionUserAgent {
+		ua = ua[:maxSessionUserAgent]
+	}
+	return string(ua)
+}
+
+// ListMySessions godoc
+// @Summary Мои активные сессии
+// @Tags profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Description Устройства (User-Agent) и IP, с которых выполнен вход; текущая сессия помечена current=true.
+// @Success 200 {object} helpers.Response{data=[]models.UserSession}
+// @Failure 401 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/profile/sessions [get]
+func (h *AuthHandler) ListMySessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	current, _ := middleware.SessionIDFromContext(r.Context())
+	h.writeSessions(w, r, userID, current)
+}
+
+// RevokeMySession godoc
+// @Summary Завершить сессию
+// @Tags profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID сессии"
+// @Success 200 {object} helpers.Response
+// @Failure 400 {object} helpers.Response
+// @Failure 401 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/profile/sessions/{id} [delete]
+func (h *AuthHandler) RevokeMySession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	h.revokeSession(w, r, userID)
+}
+
+// AdminListUserSessions godoc
+// @Summary Активные сессии пользователя
+// @Tags admin-users
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} helpers.Response{data=[]models.UserSession}
+// @Failure 400 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/users/{id}/sessions [get]
+func (h *AuthHandler) AdminListUserSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || userID <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Неверный ID пользователя")
+		return
+	}
+	h.writeSessions(w, r, userID, 0)
+}
+
+// AdminRevokeUserSession godoc
+// @Summary Завершить сессию пользователя
+// @Tags admin-users
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param sid path int true "ID сессии"
+// @Success 200 {object} helpers.Response
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/users/{id}/sessions/{sid} [delete]
+func (h *AuthHandler) AdminRevokeUserSession(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || userID <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Неверный ID пользователя")
+		return
+	}
+	h.revokeSession(w, r, userID)
+}
+
+func (h *AuthHandler) writeSessions(w http.ResponseWriter, r *http.Request, userID int, current int64) {
+	list, err := h.authService.ListSessions(r.Context(), userID, current)
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка получения сессий", zap.Int("user_id", userID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить сессии")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// revokeSession — id сессии из пути: {sid} в админском маршруте, {id} — в профиле.
+func (h *AuthHandler) revokeSession(w http.ResponseWriter, r *http.Request, userID int) {
+	vars := mux.Vars(r)
+	raw, ok := vars["sid"]
+	if !ok {
+		raw = vars["id"]
+	}
+	sessionID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || sessionID <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Неверный ID сессии")
+		return
+	}
+
+	if err := h.authService.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			helpers.Fail(w, http.StatusNotFound, helpers.CodeSessionNotFound, err.Error())
+			return
+		}
+		logger.WithCtx(r.Context()).Error("Ошибка завершения сессии", zap.Int("user_id", userID), zap.Int64("session_id", sessionID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось завершить сессию")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Сессия завершена"})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"edutalks/internal/middleware"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+)
+
+type SystemHandler struct {
+	authzReport func() *models.AuthzReport
+	reindex     *services.ReindexService
+	selfCheck   *services.SelfCheckService
+}
+
+// NewSystemHandler — report строит отчёт по роутеру (маршруты живут в пакете routes).
+func NewSystemHandler(report func() *models.AuthzReport, reindex *services.ReindexService, selfCheck *services.SelfCheckService) *SystemHandler {
+	return &SystemHandler{authzReport: report, reindex: reindex, selfCheck: selfCheck}
+}
+
+// AuthzReport godoc
+// @Summary Отчёт о доступе к маршрутам
+// @Tags Система
+// @Security ApiKeyAuth
+// @Produce json
+// @Description Какие маршруты публичные, требуют JWT или только для админа, и где это расходится с security в Swagger.
+// @Success 200 {object} helpers.Response{data=models.AuthzReport}
+// @Failure 401 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Router /api/admin/system/authz-report [get]
+func (h *SystemHandler) AuthzReport(w http.ResponseWriter, r *http.Request) {
+	helpers.JSON(w, http.StatusOK, h.authzReport())
+}
+
+// StartReindex godoc
+// @Summary Запустить переиндексацию
+// @Tags Система
+// @Security ApiKeyAuth
+// @Produce json
+// @Description Фоновая пересборка индексов и статистики таблиц поиска (документы, новости, статьи), дерева разделов и топов скачиваний — после массового импорта или изменения индексов, без перезапуска. Ход выполнения — GET /api/admin/system/reindex.
+// @Success 202 {object} helpers.Response{data=models.ReindexJob}
+// @Failure 401 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 409 {object} helpers.Response{data=models.ReindexJob} "Уже выполняется"
+// @Router /api/admin/system/reindex [post]
+func (h *SystemHandler) StartReindex(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middleware.ContextUserID).(int)
+
+	job, err := h.reindex.Start(r.Context(), userID)
+	if errors.Is(err, services.ErrReindexRunning) {
+		helpers.JSON(w, http.StatusConflict, job)
+		return
+	}
+	helpers.JSON(w, http.StatusAccepted, job)
+}
+
+// ReindexStatus godoc
+// @Summary Ход переиндексации
+// @Tags Система
+// @Security ApiKeyAuth
+// @Produce json
+// @Description Состояние последнего запуска: прогресс (done/total) и результат по каждой таблице. null — с момента старта сервиса запусков не было.
+// @Success 200 {object} helpers.Response{data=models.ReindexJob}
+// @Failure 401 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Router /api/admin/system/reindex [get]
+func (h *SystemHandler) ReindexStatus(w http.ResponseWriter, r *http.Request) {
+	helpers.JSON(w, http.StatusOK, h.reindex.Status())
+}
+
+// SelfCheck godoc
+// @Summary Отчёт самопроверки при старте
+// @Tags Система
+// @Security ApiKeyAuth
+// @Produce json
+// @Description Результат последней самопроверки: подключение к БД и версия миграций, права на каталог загрузок, доступность SMTP, обязательные переменные окружения и ключ JWT. null — проверка отключена (SELFCHECK_MODE=off).
+// @Success 200 {object} helpers.Response{data=models.SelfCheckReport}
+// @Failure 401 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Router /api/admin/system/selfcheck [get]
+func (h *SystemHandler) SelfCheck(w http.ResponseWriter, r *http.Request) {
+	helpers.JSON(w, http.StatusOK, h.selfCheck.Last())
+}
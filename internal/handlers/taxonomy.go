@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"edutalks/internal/logger"
 	"edutalks/internal/models"
@@ -11,6 +12,7 @@ import (
 	helpers "edutalks/internal/utils/helpers"
 
 	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
@@ -22,11 +24,12 @@ func NewTaxonomyHandler(s *services.TaxonomyService) *TaxonomyHandler {
 
 // PublicTree
 // @Summary      Получить дерево вкладок и разделов
-// @Description  Возвращает список вкладок с разделами и количеством документов в каждом разделе
+// @Description  Возвращает список вкладок с деревом разделов (children — подразделы). docs_count — документы раздела вместе с подразделами, own_docs_count — только самого раздела
 // @Tags         taxonomy
 // @Produce      json
-// @Success      200 {object} map[string][]models.TabTree
-// @Failure      500 {object} map[string]string
+// @Success      200 {object} helpers.Response{data=TaxonomyTreeResponse}
+// @Success      304 {string} string "Не изменилось (If-None-Match)"
+// @Failure      500 {object} helpers.Response
 // @Router       /api/taxonomy/tree [get]
 func (h *TaxonomyHandler) PublicTree(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -40,7 +43,8 @@ func (h *TaxonomyHandler) PublicTree(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Info("taxonomy: дерево получено", zap.Int("tabs_count", len(tree)))
-	helpers.JSON(w, http.StatusOK, map[string]any{"data": tree})
+	// в дереве счётчики документов — времени изменения нет, только ETag
+	helpers.JSONConditional(w, r, TaxonomyTreeResponse{Data: tree})
 }
 
 // CreateTab
@@ -50,17 +54,16 @@ func (h *TaxonomyHandler) PublicTree(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Param        body  body  models.Tab  true  "Данные вкладки"
-// @Success      201   {object} map[string]int
-// @Failure      400   {object} map[string]string
-// @Failure      500   {object} map[string]string
+// @Success      201   {object} helpers.Response{data=IDResponse}
+// @Failure      400   {object} helpers.Response
+// @Failure      500   {object} helpers.Response
+// @Security     ApiKeyAuth
 // @Router       /api/admin/tabs [post]
 func (h *TaxonomyHandler) CreateTab(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
 
 	var req models.Tab
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Warn("taxonomy: невалидный JSON при создании вкладки", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "bad json")
+	if !decodeValid(w, r, &req) {
 		return
 	}
 
@@ -74,7 +77,7 @@ func (h *TaxonomyHandler) CreateTab(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Info("taxonomy: вкладка создана", zap.Int("id", id))
-	helpers.JSON(w, http.StatusCreated, map[string]int{"id": id})
+	helpers.JSON(w, http.StatusCreated, IDResponse{ID: id})
 }
 
 // UpdateTab
@@ -86,8 +89,9 @@ func (h *TaxonomyHandler) CreateTab(w http.ResponseWriter, r *http.Request) {
 // @Param        id    path  int        true  "ID вкладки"
 // @Param        body  body  models.Tab true  "Обновлённые данные"
 // @Success      204   {string} string  "No Content"
-// @Failure      400   {object} map[string]string
-// @Failure      500   {object} map[string]string
+// @Failure      400   {object} helpers.Response
+// @Failure      500   {object} helpers.Response
+// @Security     ApiKeyAuth
 // @Router       /api/admin/tabs/{id} [patch]
 func (h *TaxonomyHandler) UpdateTab(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -96,14 +100,12 @@ func (h *TaxonomyHandler) UpdateTab(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(idStr)
 	if err != nil || id <= 0 {
 		log.Warn("taxonomy: неверный id вкладки при обновлении", zap.String("raw", idStr))
-		helpers.Error(w, http.StatusBadRequest, "bad id")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "bad id")
 		return
 	}
 
 	var req models.Tab
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Warn("taxonomy: невалидный JSON при обновлении вкладки", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "bad json")
+	if !decodeValid(w, r, &req) {
 		return
 	}
 	req.ID = id
@@ -126,7 +128,8 @@ func (h *TaxonomyHandler) UpdateTab(w http.ResponseWriter, r *http.Request) {
 // @Tags         taxonomy
 // @Param        id  path  int  true  "ID вкладки"
 // @Success      204 {string} string "No Content"
-// @Failure      500 {object} map[string]string
+// @Failure      500 {object} helpers.Response
+// @Security     ApiKeyAuth
 // @Router       /api/admin/tabs/{id} [delete]
 func (h *TaxonomyHandler) DeleteTab(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -135,7 +138,7 @@ func (h *TaxonomyHandler) DeleteTab(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(idStr)
 	if err != nil || id <= 0 {
 		log.Warn("taxonomy: неверный id вкладки при удалении", zap.String("raw", idStr))
-		helpers.Error(w, http.StatusBadRequest, "bad id")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "bad id")
 		return
 	}
 
@@ -157,23 +160,26 @@ func (h *TaxonomyHandler) DeleteTab(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Param        body  body  models.Section  true  "Данные раздела"
-// @Success      201   {object} map[string]int
-// @Failure      400   {object} map[string]string
-// @Failure      500   {object} map[string]string
+// @Success      201   {object} helpers.Response{data=IDResponse}
+// @Failure      400   {object} helpers.Response
+// @Failure      500   {object} helpers.Response
+// @Security     ApiKeyAuth
 // @Router       /api/admin/sections [post]
 func (h *TaxonomyHandler) CreateSection(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
 
 	var req models.Section
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Warn("taxonomy: невалидный JSON при создании раздела", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "bad json")
+	if !decodeValid(w, r, &req) {
 		return
 	}
 
 	log.Info("taxonomy: создание раздела", zap.String("title", req.Title), zap.Int("tab_id", req.TabID))
 
 	id, err := h.svc.CreateSection(r.Context(), &req)
+	if errors.Is(err, services.ErrSectionParent) {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeValidationFailed, err.Error())
+		return
+	}
 	if err != nil {
 		log.Error("taxonomy: ошибка создания раздела", zap.Error(err))
 		helpers.Error(w, http.StatusInternalServerError, err.Error())
@@ -181,7 +187,7 @@ func (h *TaxonomyHandler) CreateSection(w http.ResponseWriter, r *http.Request)
 	}
 
 	log.Info("taxonomy: раздел создан", zap.Int("id", id))
-	helpers.JSON(w, http.StatusCreated, map[string]int{"id": id})
+	helpers.JSON(w, http.StatusCreated, IDResponse{ID: id})
 }
 
 // UpdateSection
@@ -193,8 +199,9 @@ func (h *TaxonomyHandler) CreateSection(w http.ResponseWriter, r *http.Request)
 // @Param        id    path  int            true  "ID раздела"
 // @Param        body  body  models.Section true  "Обновлённые данные"
 // @Success      204   {string} string      "No Content"
-// @Failure      400   {object} map[string]string
-// @Failure      500   {object} map[string]string
+// @Failure      400   {object} helpers.Response
+// @Failure      500   {object} helpers.Response
+// @Security     ApiKeyAuth
 // @Router       /api/admin/sections/{id} [patch]
 func (h *TaxonomyHandler) UpdateSection(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -203,14 +210,12 @@ func (h *TaxonomyHandler) UpdateSection(w http.ResponseWriter, r *http.Request)
 	id, err := strconv.Atoi(idStr)
 	if err != nil || id <= 0 {
 		log.Warn("taxonomy: неверный id раздела при обновлении", zap.String("raw", idStr))
-		helpers.Error(w, http.StatusBadRequest, "bad id")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "bad id")
 		return
 	}
 
 	var req models.Section
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Warn("taxonomy: невалидный JSON при обновлении раздела", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "bad json")
+	if !decodeValid(w, r, &req) {
 		return
 	}
 	req.ID = id
@@ -218,6 +223,14 @@ func (h *TaxonomyHandler) UpdateSection(w http.ResponseWriter, r *http.Request)
 	log.Info("taxonomy: обновление раздела", zap.Int("id", id), zap.String("title", req.Title), zap.Int("tab_id", req.TabID))
 
 	if err := h.svc.UpdateSection(r.Context(), &req); err != nil {
+		if errors.Is(err, services.ErrSectionParent) {
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeValidationFailed, err.Error())
+			return
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			helpers.Fail(w, http.StatusNotFound, helpers.CodeNotFound, "Раздел не найден")
+			return
+		}
 		log.Error("taxonomy: ошибка обновления раздела", zap.Error(err), zap.Int("id", id))
 		helpers.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -229,11 +242,18 @@ func (h *TaxonomyHandler) UpdateSection(w http.ResponseWriter, r *http.Request)
 
 // DeleteSection
 // @Summary      Удалить раздел
-// @Description  Доступно только администратору
+// @Description  Доступно только администратору. Удаляются и подразделы. mode решает судьбу документов поддерева: block (по умолчанию) — 409, если в разделе есть документы или подразделы; detach — документы остаются без раздела; reassign — переносятся в раздел move_to. move_to без mode означает reassign.
 // @Tags         taxonomy
-// @Param        id  path  int  true  "ID раздела"
-// @Success      204 {string} string "No Content"
-// @Failure      500 {object} map[string]string
+// @Produce      json
+// @Param        id       path   int     true   "ID раздела"
+// @Param        mode     query  string  false  "block | detach | reassign"
+// @Param        move_to  query  int     false  "ID раздела, куда перенести документы (для reassign)"
+// @Success      200 {object} helpers.Response{data=models.SectionDeleteResult}
+// @Failure      400 {object} helpers.Response
+// @Failure      404 {object} helpers.Response
+// @Failure      409 {object} helpers.Response "SECTION_NOT_EMPTY; в details — documents и subsections"
+// @Failure      500 {object} helpers.Response
+// @Security     ApiKeyAuth
 // @Router       /api/admin/sections/{id} [delete]
 func (h *TaxonomyHandler) DeleteSection(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -242,19 +262,102 @@ func (h *TaxonomyHandler) DeleteSection(w http.ResponseWriter, r *http.Request)
 	id, err := strconv.Atoi(idStr)
 	if err != nil || id <= 0 {
 		log.Warn("taxonomy: неверный id раздела при удалении", zap.String("raw", idStr))
-		helpers.Error(w, http.StatusBadRequest, "bad id")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "bad id")
 		return
 	}
 
-	log.Info("taxonomy: удаление раздела", zap.Int("id", id))
-	if err := h.svc.DeleteSection(r.Context(), id); err != nil {
-		log.Error("taxonomy: ошибка удаления раздела", zap.Error(err), zap.Int("id", id))
-		helpers.Error(w, http.StatusInternalServerError, err.Error())
+	q := r.URL.Query()
+	mode := q.Get("mode")
+	var moveTo *int
+	if raw := q.Get("move_to"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный move_to")
+			return
+		}
+		moveTo = &v
+		if mode == "" {
+			mode = models.SectionDeleteReassign
+		}
+	}
+
+	log.Info("taxonomy: удаление раздела", zap.Int("id", id), zap.String("mode", mode), zap.Intp("move_to", moveTo))
+	res, err := h.svc.DeleteSection(r.Context(), id, mode, moveTo)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSectionNotEmpty):
+			helpers.FailWithDetails(w, http.StatusConflict, helpers.CodeSectionNotEmpty, err.Error(), map[string]int{
+				"documents":   res.Documents,
+				"subsections": res.Subsections,
+			})
+		case errors.Is(err, services.ErrSectionDeleteMode), errors.Is(err, services.ErrSectionMoveTarget):
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeValidationFailed, err.Error())
+		case errors.Is(err, pgx.ErrNoRows):
+			helpers.Fail(w, http.StatusNotFound, helpers.CodeNotFound, "Раздел не найден")
+		default:
+			log.Error("taxonomy: ошибка удаления раздела", zap.Error(err), zap.Int("id", id))
+			helpers.Error(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
-	log.Info("taxonomy: раздел удалён", zap.Int("id", id))
-	w.WriteHeader(http.StatusNoContent)
+	log.Info("taxonomy: раздел удалён", zap.Int("id", id), zap.String("mode", res.Mode))
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// ReorderTabs
+// @Summary      Изменить порядок вкладок
+// @Description  Доступно только администратору. ids — вкладки в новом порядке; не перечисленные встают следом в прежнем порядке. Позиции перенумеровываются 1..N в одной транзакции. Возвращает итоговый порядок.
+// @Tags         taxonomy
+// @Accept       json
+// @Produce      json
+// @Param        body  body  models.TabReorderRequest  true  "Новый порядок"
+// @Success      200   {object} helpers.Response{data=IDsResponse}
+// @Failure      400   {object} helpers.Response
+// @Failure      500   {object} helpers.Response
+// @Security     ApiKeyAuth
+// @Router       /api/admin/tabs/reorder [patch]
+func (h *TaxonomyHandler) ReorderTabs(w http.ResponseWriter, r *http.Request) {
+	var req models.TabReorderRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	order, err := h.svc.ReorderTabs(r.Context(), req.IDs)
+	h.writeReorder(w, r, order, err)
+}
+
+// ReorderSections
+// @Summary      Изменить порядок разделов вкладки
+// @Description  Доступно только администратору. ids — разделы вкладки tab_id в новом порядке; не перечисленные встают следом. Позиции перенумеровываются 1..N в одной транзакции. Возвращает итоговый порядок.
+// @Tags         taxonomy
+// @Accept       json
+// @Produce      json
+// @Param        body  body  models.SectionReorderRequest  true  "Новый порядок"
+// @Success      200   {object} helpers.Response{data=IDsResponse}
+// @Failure      400   {object} helpers.Response
+// @Failure      500   {object} helpers.Response
+// @Security     ApiKeyAuth
+// @Router       /api/admin/sections/reorder [patch]
+func (h *TaxonomyHandler) ReorderSections(w http.ResponseWriter, r *http.Request) {
+	var req models.SectionReorderRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	order, err := h.svc.ReorderSections(r.Context(), req.TabID, req.IDs)
+	h.writeReorder(w, r, order, err)
+}
+
+func (h *TaxonomyHandler) writeReorder(w http.ResponseWriter, r *http.Request, order []int, err error) {
+	if err != nil {
+		if errors.Is(err, services.ErrTaxonomyReorder) {
+			helpers.Fail(w, http.StatusBadRequest, helpers.CodeValidationFailed, err.Error())
+			return
+		}
+		logger.WithCtx(r.Context()).Error("taxonomy: ошибка сортировки", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось сохранить порядок")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, IDsResponse{IDs: order})
 }
 
 // PublicTreeByTab
@@ -265,8 +368,8 @@ func (h *TaxonomyHandler) DeleteSection(w http.ResponseWriter, r *http.Request)
 // @Param        tab   path   string  true   "Slug или ID вкладки"
 // @Param        id    query  int     false  "ID вкладки (необязателен)"
 // @Param        slug  query  string  false  "Slug вкладки (необязателен)"
-// @Success      200 {object} map[string][]models.TabTree
-// @Failure      500 {object} map[string]string
+// @Success      200 {object} helpers.Response{data=TaxonomyTreeResponse}
+// @Failure      500 {object} helpers.Response
 // @Router       /api/taxonomy/tree/{tab} [get]
 func (h *TaxonomyHandler) PublicTreeByTab(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
@@ -312,5 +415,65 @@ func (h *TaxonomyHandler) PublicTreeByTab(w http.ResponseWriter, r *http.Request
 	}
 
 	log.Info("taxonomy: дерево по вкладке получено", zap.Int("tabs_count", len(items)))
-	helpers.JSON(w, http.StatusOK, map[string]any{"data": items})
+	helpers.JSON(w, http.StatusOK, TaxonomyTreeResponse{Data: items})
+}
+
+// Resolve
+// @Summary      Разрешить slug-путь каталога в цепочку вкладка → раздел
+// @Description  Для хлебных крошек и корректных 404 на SPA: /api/taxonomy/resolve?path=/zavuch/planirovanie
+// @Tags         taxonomy
+// @Produce      json
+// @Param        path  query  string  true  "Путь вида /tab-slug[/section-slug]"
+// @Success      200 {object} helpers.Response{data=models.TaxonomyResolveResult}
+// @Failure      400 {object} helpers.Response
+// @Failure      404 {object} helpers.Response
+// @Failure      500 {object} helpers.Response
+// @Router       /api/taxonomy/resolve [get]
+func (h *TaxonomyHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	path := strings.TrimSpace(r.URL.Query().Get("path"))
+	if path == "" {
+		helpers.Error(w, http.StatusBadRequest, "path is required")
+		return
+	}
+
+	res, err := h.svc.Resolve(r.Context(), path)
+	if err != nil {
+		if errors.Is(err, services.ErrTaxonomyPathNotFound) {
+			log.Info("taxonomy: путь не найден", zap.String("path", path))
+			helpers.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Error("taxonomy: ошибка разрешения пути", zap.Error(err), zap.String("path", path))
+		helpers.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// Search
+// @Summary      Поиск вкладок и разделов для пикеров
+// @Description  Доступно только администратору. Возвращает и неактивные элементы; для разделов — родительскую вкладку.
+// @Tags         taxonomy
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Param        q      query  string  true   "Подстрока title/slug"
+// @Param        limit  query  int     false  "Лимит (по умолчанию 20, максимум 50)"
+// @Success      200 {object} helpers.Response{data=TaxonomySearchResponse}
+// @Failure      500 {object} helpers.Response
+// @Router       /api/admin/taxonomy/search [get]
+func (h *TaxonomyHandler) Search(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	q := r.URL.Query().Get("q")
+	items, err := h.svc.Search(r.Context(), q, parseIntQuery(r, "limit", 20))
+	if err != nil {
+		log.Error("taxonomy: ошибка поиска", zap.Error(err), zap.String("q", q))
+		helpers.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, TaxonomySearchResponse{Data: items})
 }
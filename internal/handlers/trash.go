@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type TrashHandler struct {
+	svc *services.TrashService
+}
+
+func NewTrashHandler(svc *services.TrashService) *TrashHandler {
+	return &TrashHandler{svc: svc}
+}
+
+// List godoc
+// @Summary Корзина
+// @Description Удалённые документы, новости и статьи, недавно удалённые сверху. purge_at — когда материал удалится окончательно (TRASH_RETENTION_DAYS).
+// @Tags admin-trash
+// @Security ApiKeyAuth
+// @Produce json
+// @Param type query string false "document | news | article"
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=TrashListResponse}
+// @Failure 400 {object} helpers.Response
+// @Router /api/admin/trash [get]
+func (h *TrashHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := pageParams(r)
+	typ := r.URL.Query().Get("type")
+	if typ != "" && !validTrashType(typ) {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeBadRequest, "type: document, news или article")
+		return
+	}
+
+	items, total, err := h.svc.List(r.Context(), typ, pageSize, (page-1)*pageSize)
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка получения корзины", zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, TrashListResponse{
+		Data:       items,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+	})
+}
+
+// Restore godoc
+// @Summary Восстановить из корзины
+// @Description Материал возвращается на место с прежней публикацией; документ, раздел которого удалён, остаётся без раздела.
+// @Tags admin-trash
+// @Security ApiKeyAuth
+// @Produce json
+// @Param type path string true "document | news | article"
+// @Param id path int true "ID материала"
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/trash/{type}/{id}/restore [post]
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	typ, id, ok := trashTarget(w, r)
+	if !ok {
+		return
+	}
+	if err := h.svc.Restore(r.Context(), typ, id); err != nil {
+		h.trashError(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Восстановлено"})
+}
+
+// Delete godoc
+// @Summary Удалить из корзины окончательно
+// @Description Без возможности восстановления; у документа удаляется и файл на диске.
+// @Tags admin-trash
+// @Security ApiKeyAuth
+// @Produce json
+// @Param type path string true "document | news | article"
+// @Param id path int true "ID материала"
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/trash/{type}/{id} [delete]
+func (h *TrashHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	typ, id, ok := trashTarget(w, r)
+	if !ok {
+		return
+	}
+	if err := h.svc.Delete(r.Context(), typ, id); err != nil {
+		h.trashError(w, r, err)
+		return
+	}
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Удалено окончательно"})
+}
+
+func (h *TrashHandler) trashError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, services.ErrTrashItemNotFound) {
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeNotFound, err.Error())
+		return
+	}
+	logger.WithCtx(r.Context()).Error("Ошибка работы с корзиной", zap.Error(err))
+	helpers.Error(w, http.StatusInternalServerError, "Ошибка сервера")
+}
+
+func validTrashType(typ string) bool {
+	switch typ {
+	case models.TrashDocument, models.TrashNews, models.TrashArticle:
+		return true
+	}
+	return false
+}
+
+func trashTarget(w http.ResponseWriter, r *http.Request) (string, int64, bool) {
+	vars := mux.Vars(r)
+	if !validTrashType(vars["type"]) {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeBadRequest, "type: document, news или article")
+		return "", 0, false
+	}
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный id")
+		return "", 0, false
+	}
+	return vars["type"], id, true
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"edutalks/internal/logger"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+// unsubscribeToken — токен из query и адрес, который он отписывает; false — ответ 400 отправлен.
+func (h *AuthHandler) unsubscribeToken(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	token := strings.TrimSpace(r.URL.Query().Get("token"))
+	if token == "" {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeAuthLinkInvalid, "Токен отсутствует")
+		return "", "", false
+	}
+	email, err := h.emailService.ParseUnsubscribeToken(token)
+	if err != nil {
+		logger.WithCtx(r.Context()).Warn("Unsubscribe: неверный токен отписки", zap.Error(err))
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeAuthLinkInvalid, "Неверная ссылка отписки")
+		return "", "", false
+	}
+	return token, email, true
+}
+
+// Unsubscribe godoc
+// @Summary Страница подтверждения отписки
+// @Description Проверяет подписанный токен из письма и перенаправляет на страницу фронта с кнопкой «Отписаться» (она отправляет POST). Сам GET ничего не меняет: ссылки из писем открывают антивирусы и превью почтовых клиентов.
+// @Tags email
+// @Param token query string true "Токен отписки"
+// @Success 302 {string} string "Редирект на страницу подтверждения"
+// @Failure 400 {object} helpers.Response
+// @Router /api/unsubscribe [get]
+func (h *AuthHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	token, _, ok := h.unsubscribeToken(w, r)
+	if !ok {
+		return
+	}
+
+	base := h.cfg.Get().FrontendBaseURL()
+	http.Redirect(w, r, base+"/unsubscribe?token="+url.QueryEscape(token), http.StatusFound)
+}
+
+// UnsubscribeOneClick godoc
+// @Summary Отписаться от рассылки
+// @Description Отключает email-рассылку по токену из письма. Вызывается почтовым клиентом (RFC 8058, тело List-Unsubscribe=One-Click) или кнопкой на странице подтверждения; токен — в query.
+// @Tags email
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token query string true "Токен отписки"
+// @Success 200 {object} helpers.Response{data=MessageResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/unsubscribe [post]
+func (h *AuthHandler) UnsubscribeOneClick(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	_, email, ok := h.unsubscribeToken(w, r)
+	if !ok {
+		return
+	}
+	if err := h.authService.UnsubscribeByEmail(r.Context(), email); err != nil {
+		log.Error("Unsubscribe: не удалось отписать", zap.String("email_masked", maskEmail(email)), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось отписаться от рассылки")
+		return
+	}
+
+	log.Info("Unsubscribe: адрес отписан от рассылки", zap.String("email_masked", maskEmail(email)))
+	helpers.JSON(w, http.StatusOK, MessageResponse{Message: "Вы отписаны от рассылки"})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+type UserActivityHandler struct {
+	svc   *services.UserActivityService
+	users *services.AuthService
+}
+
+func NewUserActivityHandler(svc *services.UserActivityService, users *services.AuthService) *UserActivityHandler {
+	return &UserActivityHandler{svc: svc, users: users}
+}
+
+// List godoc
+// @Summary Лента действий пользователя
+// @Description Входы, скачивания, платежи, изменения подписки, подтверждение и смена email, входы поддержки под пользователем — новые сверху. Входы видны, пока хранится сессия (до недели после истечения).
+// @Tags admin-users
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param type query string false "login|download|payment|subscription|email_verified|email_changed|impersonation"
+// @Param page query int false "Номер страницы (начиная с 1)"
+// @Param page_size query int false "Размер страницы (до 100)"
+// @Success 200 {object} helpers.Response{data=UserActivityListResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /api/admin/users/{id}/activity [get]
+func (h *UserActivityHandler) List(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathUserID(w, r)
+	if !ok {
+		return
+	}
+	if _, err := h.users.GetUserByID(r.Context(), id); err != nil {
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeUserNotFound, "Пользователь не найден")
+		return
+	}
+
+	page, pageSize := pageParams(r)
+	typ := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("type")))
+	items, total, err := h.svc.List(r.Context(), id, typ, pageSize, (page-1)*pageSize)
+	if errors.Is(err, services.ErrActivityType) {
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		logger.WithCtx(r.Context()).Error("Ошибка получения ленты действий", zap.Int("user_id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось получить ленту действий")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, UserActivityListResponse{
+		Data:       items,
+		Pagination: Pagination{Total: total, Page: page, PageSize: pageSize},
+	})
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/middleware"
+	"edutalks/internal/services"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type UserExportHandler struct {
+	svc *services.UserExportService
+}
+
+func NewUserExportHandler(svc *services.UserExportService) *UserExportHandler {
+	return &UserExportHandler{svc: svc}
+}
+
+// ExportMyData godoc
+// @Summary Выгрузить мои данные
+// @Tags profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Description Запускает сборку архива со всеми данными о пользователе: профиль, состояние рассылок, платежи, квитанции, скачивания. Архив собирается в фоне, ссылка для скачивания (с ограниченным сроком действия) приходит на почту. Повторный запрос в течение 10 минут возвращает уже запущенную выгрузку.
+// @Success 202 {object} helpers.Response{data=models.UserExport}
+// @Failure 401 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/profile/export [get]
+func (h *UserExportHandler) ExportMyData(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok || userID == 0 {
+		helpers.Error(w, http.StatusUnauthorized, "Нет доступа")
+		return
+	}
+	h.request(w, r, userID, userID)
+}
+
+// ExportUserData godoc
+// @Summary Выгрузить данные пользователя
+// @Tags admin-users
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Description То же, что /api/profile/export, но для любого пользователя; ссылка на архив приходит на почту администратора.
+// @Success 202 {object} helpers.Response{data=models.UserExport}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /api/admin/users/{id}/export [post]
+func (h *UserExportHandler) ExportUserData(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := middleware.UserIDFromContext(r.Context())
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || userID <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный ID пользователя")
+		return
+	}
+	h.request(w, r, userID, adminID)
+}
+
+func (h *UserExportHandler) request(w http.ResponseWriter, r *http.Request, userID, requestedBy int) {
+	e, err := h.svc.Request(r.Context(), userID, requestedBy)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		helpers.Fail(w, http.StatusNotFound, helpers.CodeUserNotFound, "Пользователь не найден")
+		return
+	case err != nil:
+		logger.WithCtx(r.Context()).Error("Ошибка запуска выгрузки данных", zap.Int("user_id", userID), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось запустить выгрузку")
+		return
+	}
+	helpers.JSON(w, http.StatusAccepted, e)
+}
+
+// Download godoc
+// @Summary Скачать выгрузку данных
+// @Tags profile
+// @Produce application/zip
+// @Param id path int true "ID выгрузки"
+// @Param token query string true "Подпись из ссылки в письме"
+// @Description Ссылка из письма; авторизация — подписью в token, срок действия ограничен (EXPORT_LINK_TTL).
+// @Success 200 {file} file "ZIP"
+// @Failure 403 {object} helpers.Response "Ссылка недействительна или устарела"
+// @Failure 409 {object} helpers.Response "Выгрузка ещё собирается"
+// @Router /api/exports/{id}/download [get]
+func (h *UserExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	log := logger.WithCtx(r.Context())
+
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Некорректный ID выгрузки")
+		return
+	}
+
+	e, err := h.svc.Open(r.Context(), id, r.URL.Query().Get("token"))
+	switch {
+	case errors.Is(err, services.ErrExportLinkInvalid):
+		helpers.Error(w, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, services.ErrExportNotReady):
+		helpers.Error(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		log.Error("Ошибка открытия выгрузки данных", zap.Int64("export_id", id), zap.Error(err))
+		helpers.Error(w, http.StatusInternalServerError, "Не удалось открыть выгрузку")
+		return
+	}
+
+	f, err := os.Open(e.FilePath)
+	if err != nil {
+		log.Error("Файл выгрузки недоступен", zap.Int64("export_id", id), zap.Error(err))
+		helpers.Error(w, http.StatusGone, "Файл выгрузки недоступен")
+		return
+	}
+	defer f.Close()
+
+	log.Info("Выгрузка данных скачана", zap.Int64("export_id", id), zap.Int("user_id", e.UserID))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+filepath.Base(e.FilePath)+"\"")
+	w.Header().Set("Cache-Control", "private, no-store")
+	modTime := e.CreatedAt
+	if e.FinishedAt != nil {
+		modTime = *e.FinishedAt
+	}
+	http.ServeContent(w, r, filepath.Base(e.FilePath), modTime, f)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"edutalks/internal/logger"
+	helpers "edutalks/internal/utils/helpers"
+	"edutalks/internal/utils/validate"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// decodeValid — JSON тела запроса в dst и проверка по тегам validate. false — ответ с ошибкой
+// (400 «Невалидный JSON» или ошибки по полям) уже отправлен.
+func decodeValid(w http.ResponseWriter, r *http.Request, dst any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		logger.WithCtx(r.Context()).Warn("Невалидный JSON", zap.String("path", r.URL.Path), zap.Error(err))
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "Невалидный JSON")
+		return false
+	}
+	return validRequest(w, r, dst)
+}
+
+// validRequest — проверка уже разобранного запроса; false — ответ с ошибками по полям отправлен.
+func validRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := validate.Struct(v); err != nil {
+		logger.WithCtx(r.Context()).Warn("Ошибка валидации запроса", zap.String("path", r.URL.Path), zap.Error(err))
+		helpers.ValidationError(w, err)
+		return false
+	}
+	return true
+}
+
+// pathUserID — ID пользователя из {id} в пути (/api/admin/users/{id}/…); false — ответ 400 уже отправлен.
+func pathUserID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "Неверный ID пользователя")
+		return 0, false
+	}
+	return id, true
+}
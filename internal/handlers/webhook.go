@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
 
 	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
 	"edutalks/internal/services"
 	helpers "edutalks/internal/utils/helpers"
 
@@ -15,20 +19,35 @@ import (
 
 type WebhookHandler struct {
 	UserService *services.AuthService
+	Guard       *services.YooKassaWebhookGuard
+	Events      *repository.PaymentWebhookRepository
+	Payments    *repository.PaymentRepository
+	Invoices    *services.InvoiceService
+	Sandbox     bool // PAYMENT_SANDBOX: доступна эмуляция уведомлений из админки
 }
 
-func NewWebhookHandler(userService *services.AuthService) *WebhookHandler {
+func NewWebhookHandler(userService *services.AuthService, guard *services.YooKassaWebhookGuard, events *repository.PaymentWebhookRepository, payments *repository.PaymentRepository, invoices *services.InvoiceService, sandbox bool) *WebhookHandler {
 	return &WebhookHandler{
 		UserService: userService,
+		Guard:       guard,
+		Events:      events,
+		Payments:    payments,
+		Invoices:    invoices,
+		Sandbox:     sandbox,
 	}
 }
 
 type PaymentWebhook struct {
 	Event  string `json:"event"`
 	Object struct {
-		ID       string `json:"id"`
-		Status   string `json:"status"`
-		Metadata struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		Amount struct {
+			Value    string `json:"value"`
+			Currency string `json:"currency"`
+		} `json:"amount"`
+		Description string `json:"description"`
+		Metadata    struct {
 			UserID string `json:"user_id"`
 			Plan   string `json:"plan"`
 		} `json:"metadata"`
@@ -40,21 +59,65 @@ type PaymentWebhook struct {
 // @Tags Оплата
 // @Accept json
 // @Produce json
+// @Description Принимается только с адресов ЮKassa (и при настроенных секретах — с basic-auth/HMAC). Повторные уведомления по тому же платежу подтверждаются без повторной обработки.
 // @Success 200 {string} string "OK"
 // @Failure 400 {string} string "Ошибка парсинга запроса"
+// @Failure 401 {string} string "Неверные учётные данные"
+// @Failure 403 {string} string "Адрес отправителя не разрешён"
 // @Failure 500 {string} string "Ошибка обновления подписки"
 // @Router /api/payments/webhook [post]
 func (h *WebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 	log := logger.WithCtx(r.Context())
-	start := time.Now()
 
 	// ограничим размер тела, чтобы не словить OOM
 	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MiB
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Warn("webhook: не удалось прочитать тело", zap.Error(err))
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "invalid body")
+		return
+	}
+	remoteIP := h.Guard.ClientIP(r)
+
+	if err := h.Guard.Verify(r, raw); err != nil {
+		log.Warn("webhook: уведомление отклонено проверкой", zap.String("remote_ip", remoteIP), zap.Error(err))
+		_ = h.Events.SaveRejected(r.Context(), "yookassa", remoteIP, string(raw), err.Error())
+		if errors.Is(err, services.ErrWebhookIPNotAllowed) {
+			helpers.Error(w, http.StatusForbidden, "forbidden")
+		} else {
+			helpers.Error(w, http.StatusUnauthorized, "unauthorized")
+		}
+		return
+	}
 
 	var webhook PaymentWebhook
-	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+	if err := json.Unmarshal(raw, &webhook); err != nil {
 		log.Warn("webhook: не удалось распарсить JSON", zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "invalid json")
+		_ = h.Events.SaveRejected(r.Context(), "yookassa", remoteIP, string(raw), "invalid json")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidJSON, "invalid json")
+		return
+	}
+
+	h.processWebhook(w, r, webhook, raw, remoteIP)
+}
+
+// processWebhook — обработка проверенного уведомления: идемпотентность, история платежей, выдача подписки.
+// Общая для настоящих уведомлений ЮKassa и эмулированных в песочнице.
+func (h *WebhookHandler) processWebhook(w http.ResponseWriter, r *http.Request, webhook PaymentWebhook, raw []byte, remoteIP string) {
+	log := logger.WithCtx(r.Context())
+	start := time.Now()
+
+	// Идемпотентность: одно и то же событие по платежу обрабатываем один раз
+	eventID, alreadyProcessed, err := h.Events.Register(r.Context(), "yookassa",
+		webhook.Event, webhook.Object.ID, webhook.Object.Status, remoteIP, string(raw))
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if alreadyProcessed {
+		log.Info("webhook: повторное уведомление — уже обработано",
+			zap.String("event", webhook.Event), zap.String("payment_id", webhook.Object.ID))
+		helpers.JSON(w, http.StatusOK, WebhookAckResponse{Status: "ok"})
 		return
 	}
 
@@ -70,7 +133,7 @@ func (h *WebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil || userID <= 0 {
 		log.Warn("webhook: некорректный user_id", zap.String("raw_user_id", userIDStr), zap.Error(err))
-		helpers.Error(w, http.StatusBadRequest, "invalid user_id")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodeInvalidID, "invalid user_id")
 		return
 	}
 
@@ -91,12 +154,34 @@ func (h *WebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 	duration, ok := planDurations[plan]
 	if !ok {
 		log.Warn("webhook: неизвестный план", zap.String("plan", plan))
-		helpers.Error(w, http.StatusBadRequest, "invalid plan")
+		helpers.Fail(w, http.StatusBadRequest, helpers.CodePaymentInvalidPlan, "invalid plan")
 		return
 	}
 
+	// История платежей: фиксируем итоговый статус (ошибка не мешает выдаче подписки)
+	if webhook.Object.ID != "" && webhook.Object.Status != "" {
+		amount, _ := strconv.ParseFloat(webhook.Object.Amount.Value, 64)
+		currency := webhook.Object.Amount.Currency
+		if currency == "" {
+			currency = "RUB"
+		}
+		_ = h.Payments.UpsertStatus(r.Context(), &models.Payment{
+			UserID:            userID,
+			Provider:          "yookassa",
+			ProviderPaymentID: &webhook.Object.ID,
+			Plan:              plan,
+			Amount:            amount,
+			Currency:          currency,
+			Description:       webhook.Object.Description,
+			Status:            webhook.Object.Status,
+		})
+	}
+
 	if webhook.Event == "payment.succeeded" && webhook.Object.Status == "succeeded" {
-		if err := h.UserService.SetSubscriptionWithExpiry(r.Context(), userID, duration); err != nil {
+		// отметка о событии и подписка — в одной транзакции: одновременные доставки
+		// одного события не продлят подписку дважды
+		granted, err := h.UserService.GrantPaidSubscription(r.Context(), h.Events, eventID, userID, duration)
+		if err != nil {
 			log.Error("webhook: не удалось активировать подписку",
 				zap.Int("user_id", userID),
 				zap.String("plan", plan),
@@ -106,18 +191,32 @@ func (h *WebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 			helpers.Error(w, http.StatusInternalServerError, "internal error")
 			return
 		}
+		if !granted {
+			log.Info("webhook: событие уже обработано параллельной доставкой",
+				zap.String("event", webhook.Event), zap.String("payment_id", webhook.Object.ID))
+			helpers.JSON(w, http.StatusOK, WebhookAckResponse{Status: "ok"})
+			return
+		}
 		log.Info("webhook: подписка активирована",
 			zap.Int("user_id", userID),
 			zap.String("plan", plan),
 			zap.Duration("duration", duration),
 		)
+		// квитанция на почту — в фоне, ответ ЮKassa не ждёт
+		if webhook.Object.ID != "" {
+			h.Invoices.IssueAndSendAsync(r.Context(), "yookassa", webhook.Object.ID)
+		}
 	} else {
-		// Идемпотентно подтверждаем другие события
+		// Идемпотентно подтверждаем другие события; без отметки ЮKassa повторит уведомление
+		if err := h.Events.MarkProcessed(r.Context(), eventID); err != nil {
+			helpers.Error(w, http.StatusInternalServerError, "internal error")
+			return
+		}
 		log.Info("webhook: событие проигнорировано (не succeeded)",
 			zap.String("event", webhook.Event),
 			zap.String("status", webhook.Object.Status))
 	}
 
 	log.Info("webhook: обработано", zap.Duration("elapsed", time.Since(start)))
-	helpers.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	helpers.JSON(w, http.StatusOK, WebhookAckResponse{Status: "ok"})
 }
@@ -135,5 +135,54 @@ func WithCtx(ctx context.Context) *zap.Logger {
 	if uid, ok := reqctx.GetUserID(ctx); ok && uid != 0 {
 		l = l.With(zap.Int("user_id", uid))
 	}
+	if aid, ok := reqctx.GetImpersonatorID(ctx); ok {
+		l = l.With(zap.Int("impersonator_id", aid))
+	}
 	return l
 }
+
+// Hook — получает записи не ниже уровня хука (с полями из With). Вызывается синхронно
+// при записи, поэтому должен быть быстрым; писать в Log на уровне хука из него нельзя.
+type Hook func(ent zapcore.Entry, fields []zapcore.Field)
+
+// AddHook — подключает hook к Log. Логгеры, полученные из Log до вызова, его не видят,
+// поэтому подключать нужно до создания сервисов.
+func AddHook(min zapcore.Level, h Hook) {
+	if Log == nil {
+		return
+	}
+	Log = Log.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(c, &hookCore{LevelEnabler: min, hook: h})
+	}))
+}
+
+// hookCore — zapcore.Core, который вместо записи вызывает hook.
+type hookCore struct {
+	zapcore.LevelEnabler
+	hook   Hook
+	fields []zapcore.Field
+}
+
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(append(all, c.fields...), fields...)
+	return &hookCore{LevelEnabler: c.LevelEnabler, hook: c.hook, fields: all}
+}
+
+func (c *hookCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *hookCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := fields
+	if len(c.fields) > 0 {
+		all = append(append(make([]zapcore.Field, 0, len(c.fields)+len(fields)), c.fields...), fields...)
+	}
+	c.hook(ent, all)
+	return nil
+}
+
+func (c *hookCore) Sync() error { return nil }
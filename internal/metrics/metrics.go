@@ -0,0 +1,102 @@
+// Package metrics — метрики процесса для Prometheus (client_golang): регистрация в общем
+// реестре и обработчик /metrics (promhttp). Кроме метрик приложения реестр отдаёт
+// стандартные go_* и process_*.
+package metrics
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// gaugeTimeout — сколько ждём один gauge (запрос к БД) при опросе.
+const gaugeTimeout = 3 * time.Second
+
+// DefBuckets — границы гистограммы длительностей по умолчанию (в секундах).
+var DefBuckets = prometheus.DefBuckets
+
+// Registry — реестр метрик приложения.
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// register — регистрирует метрику; повторный вызов с тем же именем вернёт существующую.
+func register[T prometheus.Collector](c T) T {
+	if err := Registry.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic("metrics: " + err.Error())
+	}
+	return c
+}
+
+// NewCounter — регистрирует счётчик.
+func NewCounter(name, help string) prometheus.Counter {
+	return register(prometheus.NewCounter(prometheus.CounterOpts{Name: name, Help: help}))
+}
+
+// NewCounterVec — регистрирует счётчик с метками labels (например, result="success").
+func NewCounterVec(name, help string, labels ...string) *prometheus.CounterVec {
+	return register(prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels))
+}
+
+// NewHistogramVec — регистрирует гистограмму с метками; buckets — возрастающие границы.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+	return register(prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels))
+}
+
+// NewGaugeFunc — регистрирует gauge, который вычисляется при каждом опросе (размер очереди,
+// число подписок); если fn вернула ошибку, метрика в этот раз не выводится.
+func NewGaugeFunc(name, help string, fn func(ctx context.Context) (float64, error)) {
+	register(&gaugeFunc{desc: prometheus.NewDesc(name, help, nil, nil), fn: fn})
+}
+
+// gaugeFunc — в отличие от prometheus.GaugeFunc, функция получает контекст с таймаутом
+// и может не вернуть значение.
+type gaugeFunc struct {
+	desc *prometheus.Desc
+	fn   func(ctx context.Context) (float64, error)
+}
+
+func (g *gaugeFunc) Describe(ch chan<- *prometheus.Desc) { ch <- g.desc }
+
+func (g *gaugeFunc) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), gaugeTimeout)
+	defer cancel()
+	if v, err := g.fn(ctx); err == nil {
+		ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, v)
+	}
+}
+
+// Handler — /metrics (promhttp). Если token не пуст, требуется заголовок
+// "Authorization: Bearer <token>".
+func Handler(token string) http.Handler {
+	next := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"net/http"
+
+	"edutalks/internal/utils/clientip"
+)
+
+// ClientIP — определяет IP клиента с учётом доверенных прокси (TRUSTED_PROXIES) один раз
+// на запрос; логи, Sentry, ограничение частоты, сессии и вебхуки берут его через clientip.FromRequest.
+func ClientIP(proxies clientip.Proxies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := clientip.WithIP(r.Context(), proxies.Resolve(r))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
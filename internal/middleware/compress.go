@@ -0,0 +1,256 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// CompressOptions — сжатие ответов (COMPRESS_*).
+type CompressOptions struct {
+	Enabled bool
+	MinSize int // ответы короче не сжимаются: выигрыш меньше накладных расходов
+}
+
+// brotliLevel — ответы сжимаются на лету: уровни выше 5 заметно медленнее при небольшом выигрыше.
+const brotliLevel = 5
+
+var (
+	brotliPool = sync.Pool{New: func() any { return brotli.NewWriterLevel(io.Discard, brotliLevel) }}
+	gzipPool   = sync.Pool{New: func() any { w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression); return w }}
+	flatePool  = sync.Pool{New: func() any { w, _ := flate.NewWriter(io.Discard, flate.DefaultCompression); return w }}
+)
+
+// Compress — br/gzip/deflate по Accept-Encoding для текстовых ответов (JSON, ndjson, CSV, XML, text/*).
+// Уже сжатое (файлы документов, zip/gz выгрузки логов) и SSE не трогаем. Решение принимается
+// по первым MinSize байтам ответа: до этого порога тело буферизуется.
+func Compress(opts CompressOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !opts.Enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			enc := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if enc == "" || r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, enc: enc, minSize: opts.MinSize, status: http.StatusOK}
+			defer func() {
+				// при панике ничего не дописываем: если ответ не начат, Recoverer отдаст JSON 500
+				if rec := recover(); rec != nil {
+					cw.release()
+					panic(rec)
+				}
+				cw.Close()
+			}()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// serverEncodings — поддерживаемые кодировки в порядке предпочтения при равном q.
+var serverEncodings = []string{"br", "gzip", "deflate"}
+
+// negotiateEncoding — br, gzip, deflate или "" по Accept-Encoding (RFC 9110, 12.5.3): берётся
+// кодировка с наибольшим q, при равном q — по serverEncodings; q=0 запрещает кодировку,
+// «*» задаёт q для не перечисленных явно.
+func negotiateEncoding(header string) string {
+	q := make(map[string]float64)
+	star := -1.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		weight := 1.0
+		for _, p := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+			if !strings.EqualFold(k, "q") {
+				continue
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil || f < 0 || f > 1 {
+				weight = -1
+			} else {
+				weight = f
+			}
+		}
+		if weight < 0 {
+			continue // некорректный q — элемент игнорируется
+		}
+		switch name {
+		case "x-gzip":
+			name = "gzip"
+		case "*":
+			star = weight
+			continue
+		}
+		q[name] = weight
+	}
+
+	best, bestQ := "", 0.0
+	for _, enc := range serverEncodings {
+		w, ok := q[enc]
+		if !ok {
+			w = max(star, 0)
+		}
+		if w > bestQ {
+			best, bestQ = enc, w
+		}
+	}
+	return best
+}
+
+func compressible(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case mt == "text/event-stream":
+		return false
+	case strings.HasPrefix(mt, "text/"),
+		mt == "application/json", mt == "application/x-ndjson",
+		mt == "application/xml", strings.HasSuffix(mt, "+xml"), strings.HasSuffix(mt, "+json"),
+		mt == "application/javascript":
+		return true
+	}
+	return false
+}
+
+type compressWriter struct {
+	http.ResponseWriter
+	enc     string
+	minSize int
+
+	status  int
+	buf     []byte
+	decided bool
+	zw      io.WriteCloser // nil — ответ идёт как есть
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.decided {
+		return
+	}
+	cw.status = code
+	// ответы без тела — сразу как есть
+	if code == http.StatusNoContent || code == http.StatusNotModified || code < 200 {
+		cw.passthrough()
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.decided {
+		h := cw.Header()
+		if h.Get("Content-Encoding") != "" || !compressible(h.Get("Content-Type")) {
+			cw.passthrough()
+		} else {
+			cw.buf = append(cw.buf, p...)
+			if len(cw.buf) >= cw.minSize {
+				if err := cw.start(); err != nil {
+					return 0, err
+				}
+			}
+			return len(p), nil
+		}
+	}
+	if cw.zw != nil {
+		return cw.zw.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// start — включает сжатие и отдаёт накопленный буфер.
+func (cw *compressWriter) start() error {
+	cw.decided = true
+	h := cw.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Encoding", cw.enc)
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	switch cw.enc {
+	case "br":
+		bw := brotliPool.Get().(*brotli.Writer)
+		bw.Reset(cw.ResponseWriter)
+		cw.zw = bw
+	case "gzip":
+		gw := gzipPool.Get().(*gzip.Writer)
+		gw.Reset(cw.ResponseWriter)
+		cw.zw = gw
+	default:
+		fw := flatePool.Get().(*flate.Writer)
+		fw.Reset(cw.ResponseWriter)
+		cw.zw = fw
+	}
+	buf := cw.buf
+	cw.buf = nil
+	_, err := cw.zw.Write(buf)
+	return err
+}
+
+// passthrough — ответ без сжатия (накопленный буфер уходит как есть).
+func (cw *compressWriter) passthrough() {
+	cw.decided = true
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if len(cw.buf) > 0 {
+		cw.ResponseWriter.Write(cw.buf)
+		cw.buf = nil
+	}
+}
+
+// Flush — для потоковых ответов: решение принимается по уже записанному.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if len(cw.buf) > 0 && len(cw.buf) >= cw.minSize {
+			cw.start()
+		} else {
+			cw.passthrough()
+		}
+	}
+	if f, ok := cw.zw.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+func (cw *compressWriter) Close() {
+	if !cw.decided {
+		cw.passthrough()
+	}
+	if cw.zw != nil {
+		cw.zw.Close()
+	}
+	cw.release()
+}
+
+// release — возвращает кодировщик в пул; накопленный, но не отданный буфер отбрасывается.
+func (cw *compressWriter) release() {
+	switch zw := cw.zw.(type) {
+	case *brotli.Writer:
+		brotliPool.Put(zw)
+	case *gzip.Writer:
+		gzipPool.Put(zw)
+	case *flate.Writer:
+		flatePool.Put(zw)
+	}
+	cw.zw = nil
+	cw.buf = nil
+}
+
+// Unwrap — для http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
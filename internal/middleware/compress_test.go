@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := map[string]string{
+		"":                                  "",
+		"identity":                          "",
+		"gzip":                              "gzip",
+		"x-gzip":                            "gzip",
+		"deflate":                           "deflate",
+		"gzip, deflate, br":                 "br",
+		"br;q=0.5, gzip":                    "gzip",
+		"gzip;q=0.8, deflate;q=0.9":         "deflate",
+		"gzip;q=0, deflate":                 "deflate",
+		"BR;Q=1, gzip":                      "br",
+		"*":                                 "br",
+		"*;q=0.1, gzip;q=0.5":               "gzip",
+		"*;q=0, gzip;q=0":                   "",
+		"br;q=0, *":                         "gzip",
+		"gzip;q=abc, deflate;q=0.1":         "deflate",
+		"gzip;q=1.5":                        "",
+		"gzip ; q=0.7 , br ; q=0.7":         "br",
+		"deflate;level=1;q=0.9, gzip;q=0.2": "deflate",
+	}
+	for header, want := range cases {
+		if got := negotiateEncoding(header); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestCompressBrotli(t *testing.T) {
+	body := strings.Repeat(`{"title":"Новость","text":"Текст новости"}`, 50)
+	h := Compress(CompressOptions{Enabled: true, MinSize: 256})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/news", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if enc := rec.Header().Get("Content-Encoding"); enc != "br" {
+		t.Fatalf("Content-Encoding = %q, want br", enc)
+	}
+	got, err := io.ReadAll(brotli.NewReader(rec.Body))
+	if err != nil {
+		t.Fatalf("brotli: %v", err)
+	}
+	if string(got) != body {
+		t.Error("тело после распаковки не совпадает")
+	}
+}
+
+// Паника под Compress: ответ ещё не начат — клиент получает JSON 500 от Recoverer, а не обрыв.
+func TestCompressPanicGets500(t *testing.T) {
+	h := Recoverer(Compress(CompressOptions{Enabled: true, MinSize: 256})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"partial":`)
+		panic("boom")
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/news", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	func() {
+		defer func() {
+			if p := recover(); p != nil {
+				t.Fatalf("паника дошла до сервера: %v", p)
+			}
+		}()
+		h.ServeHTTP(rec, req)
+	}()
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("code = %d, want 500", rec.Code)
+	}
+	if enc := rec.Header().Get("Content-Encoding"); enc != "" {
+		t.Errorf("Content-Encoding = %q, want none", enc)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "INTERNAL_ERROR") || strings.Contains(body, "partial") {
+		t.Errorf("body = %q", body)
+	}
+}
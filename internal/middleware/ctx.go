@@ -9,6 +9,9 @@ const (
 	ContextUserID     ctxKey = "user_id"
 	ContextRole       ctxKey = "role"
 	ContextRequestID  ctxKey = "request_id"
+	ContextSessionID  ctxKey = "session_id"
+
+	ContextImpersonationID ctxKey = "impersonation_id"
 )
 
 func WithSkipGuards(ctx context.Context) context.Context {
@@ -47,3 +50,15 @@ func RequestIDFromContext(ctx context.Context) (string, bool) {
 	rid, ok := v.(string)
 	return rid, ok
 }
+
+// SessionIDFromContext — id сессии из access-токена (нет у токенов, выпущенных до учёта сессий).
+func SessionIDFromContext(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(ContextSessionID).(int64)
+	return id, ok
+}
+
+// ImpersonationIDFromContext — запись журнала impersonations, если запрос идёт с токеном поддержки.
+func ImpersonationIDFromContext(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(ContextImpersonationID).(int64)
+	return id, ok
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestDeadline — дедлайн контекста запроса (timeout() читается на каждый запрос, так что
+// REQUEST_TIMEOUT меняется перечитыванием конфигурации). Контекст получают все запросы к БД
+// из обработчика: медленный запрос отменяется, а не держит соединение пула до WriteTimeout.
+// SSE-потоки живут долго — им дедлайн не ставим.
+func RequestDeadline(timeout func() time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d := timeout()
+			if d <= 0 || isEventStream(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// isEventStream — запрос SSE: EventSource шлёт Accept: text/event-stream, потоки — на */stream.
+func isEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream") ||
+		strings.HasSuffix(r.URL.Path, "/stream")
+}
@@ -5,7 +5,11 @@ import (
 	"edutalks/internal/config"
 	"edutalks/internal/logger"
 	"edutalks/internal/repository"
+	"edutalks/internal/reqctx"
+	"edutalks/internal/utils"
+	helpers "edutalks/internal/utils/helpers"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -14,58 +18,157 @@ import (
 
 type ContextKey string
 
-func JWTAuth(repo repository.UserRepo, next http.Handler) http.Handler {
+// JWTAuth — проверка Bearer-токена; секрет подписи берётся из cfg на каждый запрос (учитывает SIGHUP).
+func JWTAuth(tokens repository.TokenStore, cfg *config.Holder, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
 
-		cfg, _ := config.LoadConfig()
-		authHeader := r.Header.Get("Authorization")
-
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			logger.WithCtx(r.Context()).Warn("JWTAuth: отсутствует access token")
-			http.Error(w, "Отсутствует access token", http.StatusUnauthorized)
+		ctx, status, code, msg := authenticate(r, tokens, cfg)
+		if code != "" {
+			helpers.Fail(w, status, code, msg)
 			return
 		}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		userID, _ := UserIDFromContext(ctx)
+		role, _ := RoleFromContext(ctx)
+		logger.WithCtx(ctx).Info("JWTAuth: токен валиден",
+			zap.Int("user_id", userID), zap.String("role", role))
 
-		claims := jwt.MapClaims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(cfg.JWTSecret), nil
-		})
+		markImpersonation(ctx, w)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
 
-		if err != nil || !token.Valid {
-			logger.WithCtx(r.Context()).Warn("JWTAuth: неверный или просроченный токен",
-				zap.Error(err))
-			http.Error(w, "Неверный или просроченный токен", http.StatusUnauthorized)
+// OptionalJWTAuth — для публичных маршрутов: с валидным токеном пользователь попадает в контекст,
+// без токена или с невалидным запрос проходит анонимно. Если отзыв токена проверить не удалось,
+// отвечаем 503, а не подменяем пользователя анонимом.
+func OptionalJWTAuth(tokens repository.TokenStore, cfg *config.Holder, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			next.ServeHTTP(w, r)
 			return
 		}
-
-		// 🔹 Проверка блоклиста
-		if blacklisted, _ := repo.IsAccessTokenBlacklisted(r.Context(), tokenString); blacklisted {
-			logger.WithCtx(r.Context()).Warn("JWTAuth: токен найден в блоклисте")
-			http.Error(w, "Неверный или просроченный токен", http.StatusUnauthorized)
+		ctx, status, code, msg := authenticate(r, tokens, cfg)
+		switch {
+		case code == "":
+			markImpersonation(ctx, w)
+			r = r.WithContext(ctx)
+		case status != http.StatusUnauthorized:
+			helpers.Fail(w, status, code, msg)
 			return
 		}
+		next.ServeHTTP(w, r)
+	})
+}
 
-		userID, ok1 := claims["user_id"].(float64)
-		role, ok2 := claims["role"].(string)
-		if !ok1 || !ok2 {
-			logger.WithCtx(r.Context()).Warn("JWTAuth: недопустимый payload",
-				zap.Any("claims", claims))
-			http.Error(w, "Недопустимый payload", http.StatusUnauthorized)
-			return
+// authenticate — проверяет Bearer-токен; при успехе возвращает контекст с пользователем,
+// иначе — статус (401, или 503, если хранилище токенов недоступно), код и текст ошибки.
+// Отзыв проверяется fail closed: без ответа хранилища токен не принимается.
+func authenticate(r *http.Request, tokens repository.TokenStore, cfg *config.Holder) (context.Context, int, helpers.ErrorCode, string) {
+	secret := cfg.Get().JWTSecret
+	authHeader := r.Header.Get("Authorization")
+
+	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+		logger.WithCtx(r.Context()).Warn("JWTAuth: отсутствует access token")
+		return nil, http.StatusUnauthorized, helpers.CodeAuthTokenMissing, "Отсутствует access token"
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	})
+
+	if err != nil || !token.Valid {
+		logger.WithCtx(r.Context()).Warn("JWTAuth: неверный или просроченный токен",
+			zap.Error(err))
+		return nil, http.StatusUnauthorized, helpers.CodeAuthTokenInvalid, "Неверный или просроченный токен"
+	}
+
+	userID, ok1 := claims["user_id"].(float64)
+	role, ok2 := claims["role"].(string)
+	if !ok1 || !ok2 {
+		logger.WithCtx(r.Context()).Warn("JWTAuth: недопустимый payload",
+			zap.Any("claims", claims))
+		return nil, http.StatusUnauthorized, helpers.CodeAuthTokenInvalid, "Недопустимый payload"
+	}
+
+	// Блоклист, отзыв токенов пользователя и отзыв сессии — одним обращением к хранилищу
+	sid, hasSession := claims["sid"].(float64)
+	status, err := tokens.TokenStatus(r.Context(), tokenString, int(userID), int64(sid))
+	if err != nil {
+		return storeUnavailable(r, err)
+	}
+	if status.Blacklisted {
+		logger.WithCtx(r.Context()).Warn("JWTAuth: токен найден в блоклисте")
+		return nil, http.StatusUnauthorized, helpers.CodeAuthTokenInvalid, "Неверный или просроченный токен"
+	}
+
+	// Токены, выпущенные до отзыва (удаление аккаунта и т.п.), не принимаем; iat — с миллисекундами,
+	// так что токен, выданный в ту же секунду сразу после отзыва, проходит
+	if iat, ok := claims["iat"].(float64); ok {
+		if !status.RevokedAt.IsZero() && !utils.IssuedAtTime(iat).After(status.RevokedAt) {
+			logger.WithCtx(r.Context()).Warn("JWTAuth: токены пользователя отозваны", zap.Int("user_id", int(userID)))
+			return nil, http.StatusUnauthorized, helpers.CodeAuthTokenInvalid, "Неверный или просроченный токен"
 		}
+	}
 
-		ctx := context.WithValue(r.Context(), ContextUserID, int(userID))
-		ctx = context.WithValue(ctx, ContextRole, role)
+	ctx := context.WithValue(r.Context(), ContextUserID, int(userID))
+	ctx = context.WithValue(ctx, ContextRole, role)
 
-		logger.WithCtx(ctx).Info("JWTAuth: токен валиден",
-			zap.Int("user_id", int(userID)), zap.String("role", role))
+	// Сессия отозвана (из списка сессий или выходом)
+	if hasSession {
+		if status.SessionRevoked {
+			logger.WithCtx(r.Context()).Warn("JWTAuth: сессия отозвана", zap.Int64("session_id", int64(sid)))
+			return nil, http.StatusUnauthorized, helpers.CodeAuthTokenInvalid, "Неверный или просроченный токен"
+		}
+		ctx = context.WithValue(ctx, ContextSessionID, int64(sid))
+	}
+	ctx = reqctx.WithUserID(ctx, int(userID))
+	setAccessUser(ctx, int(userID), role)
 
-		next.ServeHTTP(w, r.WithContext(ctx))
+	// Токен поддержки, выданный администратору от имени пользователя: запрос помечается
+	// в логах (impersonator_id) и в заголовке ответа
+	if adminID, ok := claims["imp"].(float64); ok {
+		ctx = reqctx.WithImpersonatorID(ctx, int(adminID))
+		if impID, ok := claims["imp_id"].(float64); ok {
+			ctx = context.WithValue(ctx, ContextImpersonationID, int64(impID))
+		}
+		setAccessImpersonator(ctx, int(adminID))
+		logger.WithCtx(ctx).Info("JWTAuth: запрос от имени пользователя",
+			zap.Any("impersonation_id", claims["imp_id"]))
+	}
+	return ctx, 0, "", ""
+}
+
+// storeUnavailable — хранилище токенов не ответило: токен не принимаем, клиент может повторить запрос.
+func storeUnavailable(r *http.Request, err error) (context.Context, int, helpers.ErrorCode, string) {
+	logger.WithCtx(r.Context()).Error("JWTAuth: хранилище токенов недоступно", zap.Error(err))
+	return nil, http.StatusServiceUnavailable, helpers.CodeServiceUnavailable, "Проверка авторизации временно недоступна"
+}
+
+// markImpersonation — фронт по заголовку показывает, что сессия открыта поддержкой.
+func markImpersonation(ctx context.Context, w http.ResponseWriter) {
+	if adminID, ok := reqctx.GetImpersonatorID(ctx); ok {
+		w.Header().Set("X-Impersonated-By", strconv.Itoa(adminID))
+	}
+}
+
+// NoImpersonation — действия, которые поддержка не выполняет за пользователя: пароль, e-mail,
+// телефон, вход через соцсети, сессии, оплата, выгрузка и удаление аккаунта. С токеном
+// поддержки такие запросы отклоняются с 403.
+func NoImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if adminID, ok := reqctx.GetImpersonatorID(r.Context()); ok {
+			logger.WithCtx(r.Context()).Warn("JWTAuth: действие недоступно в режиме поддержки",
+				zap.Int("admin_id", adminID), zap.String("path", r.URL.Path))
+			helpers.Fail(w, http.StatusForbidden, helpers.CodeAuthImpersonated, "Действие недоступно в режиме поддержки")
+			return
+		}
+		next.ServeHTTP(w, r)
 	})
 }
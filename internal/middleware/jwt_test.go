@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/repository"
+	"edutalks/internal/repository/repofake"
+	"edutalks/internal/utils"
+)
+
+// brokenTokens — хранилище, которое не может ответить про отзыв токенов.
+type brokenTokens struct{ *repofake.Tokens }
+
+func (brokenTokens) TokenStatus(ctx context.Context, token string, userID int, sessionID int64) (repository.TokenStatus, error) {
+	return repository.TokenStatus{}, errors.New("redis: connection refused")
+}
+
+func TestJWTAuthRevocation(t *testing.T) {
+	cfg := config.NewHolder(&config.Config{JWTSecret: "secret"})
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	call := func(tokens repository.TokenStore, token string) int {
+		h := JWTAuth(tokens, cfg, ok)
+		req := httptest.NewRequest(http.MethodGet, "/api/profile", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	tokens := repofake.NewTokens()
+	before, _ := utils.GenerateToken("secret", 7, "user", time.Minute, "access")
+	time.Sleep(2 * time.Millisecond)
+	tokens.RevokeUserTokens(context.Background(), 7, time.Now())
+	time.Sleep(2 * time.Millisecond)
+	// выданный в ту же секунду, но после отзыва (например, вход с новым паролем)
+	after, _ := utils.GenerateToken("secret", 7, "user", time.Minute, "access")
+
+	if code := call(tokens, before); code != http.StatusUnauthorized {
+		t.Errorf("токен до отзыва: %d, want 401", code)
+	}
+	if code := call(tokens, after); code != http.StatusOK {
+		t.Errorf("токен после отзыва: %d, want 200", code)
+	}
+	// хранилище недоступно — токен не принимается
+	if code := call(brokenTokens{tokens}, after); code != http.StatusServiceUnavailable {
+		t.Errorf("хранилище недоступно: %d, want 503", code)
+	}
+}
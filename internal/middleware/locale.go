@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/gorilla/mux"
+)
+
+// LocaleLookup — язык пользователя из профиля (users.locale); "" — не удалось узнать.
+type LocaleLookup func(ctx context.Context, userID int) string
+
+type localeKey struct{}
+
+// Locale — язык ответа по Accept-Language; для авторизованных запросов его уточняет UserLocale.
+// helpers.FailWithDetails переводит сообщения ошибок на этот язык; ставить до Recoverer,
+// чтобы и 500 после паники ушла на языке клиента.
+func Locale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lw := &localeWriter{ResponseWriter: w, locale: helpers.LocaleFromAcceptLanguage(r.Header.Get("Accept-Language"))}
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(lw, r.WithContext(context.WithValue(r.Context(), localeKey{}, lw)))
+	})
+}
+
+// UserLocale — после JWT: язык из профиля пользователя важнее Accept-Language
+// (на нём же приходят письма).
+func UserLocale(lookup LocaleLookup) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if userID, ok := UserIDFromContext(r.Context()); ok {
+				if l := lookup(r.Context(), userID); helpers.SupportedLocale(l) {
+					setLocale(r.Context(), l)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func setLocale(ctx context.Context, locale string) {
+	if lw, ok := ctx.Value(localeKey{}).(*localeWriter); ok {
+		lw.locale = locale
+	}
+}
+
+// localeWriter — реализует helpers.LocaleWriter.
+type localeWriter struct {
+	http.ResponseWriter
+	locale string
+}
+
+func (lw *localeWriter) Locale() string {
+	return lw.locale
+}
+
+// Unwrap — для http.ResponseController и поиска обёрток в helpers.
+func (lw *localeWriter) Unwrap() http.ResponseWriter {
+	return lw.ResponseWriter
+}
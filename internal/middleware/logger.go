@@ -1,17 +1,46 @@
 package middleware
 
 import (
+	"context"
 	"edutalks/internal/logger"
+	"edutalks/internal/utils/clientip"
 	"net/http"
 	"time"
 
 	"go.uber.org/zap"
 )
 
+type accessInfoKey struct{}
+
+// accessInfo заполняется ниже по цепочке (JWTAuth), чтобы access-лог видел пользователя:
+// контекст, изменённый внутри next, наружу не возвращается.
+type accessInfo struct {
+	userID         int
+	role           string
+	impersonatorID int
+}
+
+func setAccessUser(ctx context.Context, userID int, role string) {
+	if ai, ok := ctx.Value(accessInfoKey{}).(*accessInfo); ok {
+		ai.userID, ai.role = userID, role
+	}
+}
+
+func setAccessImpersonator(ctx context.Context, adminID int) {
+	if ai, ok := ctx.Value(accessInfoKey{}).(*accessInfo); ok {
+		ai.impersonatorID = adminID
+	}
+}
+
+// Logging — структурированный access-лог: одна запись на запрос с request_id
+// (ставится middleware RequestID), пользователем, статусом, размером ответа и длительностью.
 func Logging(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
+		ai := &accessInfo{}
+		r = r.WithContext(context.WithValue(r.Context(), accessInfoKey{}, ai))
+
 		lrw := &loggingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 		next.ServeHTTP(lrw, r)
 
@@ -19,29 +48,40 @@ func Logging(next http.Handler) http.Handler {
 			zap.String("method", r.Method),
 			zap.String("path", r.URL.Path),
 			zap.Int("status", lrw.statusCode),
+			zap.Int64("bytes", lrw.bytes),
 			zap.Duration("duration", time.Since(start)),
+			zap.String("remote_ip", clientip.FromRequest(r)),
+			zap.String("user_agent", r.UserAgent()),
 		}
-
-		if rid, ok := r.Context().Value(ContextRequestID).(string); ok {
-			fields = append(fields, zap.String("request_id", rid))
-		}
-		if userID, ok := r.Context().Value(ContextUserID).(int); ok {
-			fields = append(fields, zap.Int("user_id", userID))
+		if ai.userID != 0 {
+			fields = append(fields, zap.Int("user_id", ai.userID), zap.String("role", ai.role))
 		}
-		if role, ok := r.Context().Value(ContextRole).(string); ok {
-			fields = append(fields, zap.String("role", role))
+		if ai.impersonatorID != 0 {
+			fields = append(fields, zap.Int("impersonator_id", ai.impersonatorID))
 		}
 
-		logger.Log.Info("HTTP-запрос", fields...)
+		logger.WithCtx(r.Context()).Info("HTTP-запрос", fields...)
 	})
 }
 
 type loggingResponseWriter struct {
 	http.ResponseWriter
 	statusCode int
+	bytes      int64
 }
 
 func (lrw *loggingResponseWriter) WriteHeader(code int) {
 	lrw.statusCode = code
 	lrw.ResponseWriter.WriteHeader(code)
 }
+
+func (lrw *loggingResponseWriter) Write(p []byte) (int, error) {
+	n, err := lrw.ResponseWriter.Write(p)
+	lrw.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap — для http.ResponseController (Flush и т.п. у исходного writer-а).
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lrw.ResponseWriter
+}
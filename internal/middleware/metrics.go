@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"edutalks/internal/metrics"
+)
+
+var httpDuration = metrics.NewHistogramVec("http_request_duration_seconds",
+	"Длительность HTTP-запросов по маршрутам", metrics.DefBuckets, "method", "route", "status")
+
+// Metrics — длительность каждого запроса в гистограмму по шаблону маршрута
+// ("/api/news/{id:[0-9]+}", а не конкретный путь — чтобы число рядов не росло).
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &loggingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		httpDuration.WithLabelValues(r.Method, routeTemplate(r), strconv.Itoa(rec.statusCode)).Observe(time.Since(start).Seconds())
+	})
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/metrics"
+	"edutalks/internal/utils/clientip"
+	helpers "edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+var rateLimited = metrics.NewCounter("http_rate_limited_total", "Запросов отклонено ограничителем частоты (429)")
+
+// RateLimiter — token bucket на ключ (IP или пользователь): perMinute токенов в минуту, не больше burst.
+type RateLimiter struct {
+	name  string
+	rate  float64 // токенов в секунду
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter — perMinute <= 0 означает «без ограничений» (вернётся nil, middleware пропускает всё).
+func NewRateLimiter(name string, perMinute, burst int) *RateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = perMinute
+	}
+	return &RateLimiter{
+		name:      name,
+		rate:      float64(perMinute) / 60,
+		burst:     float64(burst),
+		buckets:   map[string]*tokenBucket{},
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow — списывает токен для key; при отказе возвращает, через сколько появится следующий.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep — раз в минуту выкидывает полностью восстановившиеся корзины, чтобы карта не росла бесконечно.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	full := time.Duration(l.burst / l.rate * float64(time.Second))
+	for k, b := range l.buckets {
+		if now.Sub(b.last) > full {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// RateLimit — ограничивает частоту запросов по IP клиента. При превышении — 429 с заголовком Retry-After.
+func RateLimit(l *RateLimiter) func(http.Handler) http.Handler {
+	return rateLimitBy(l, func(r *http.Request) string {
+		return "ip:" + clientip.FromRequest(r)
+	})
+}
+
+// RateLimitByUser — то же по user_id; ставится после JWTAuth (запросы без пользователя не ограничивает).
+func RateLimitByUser(l *RateLimiter) func(http.Handler) http.Handler {
+	return rateLimitBy(l, func(r *http.Request) string {
+		if uid, ok := UserIDFromContext(r.Context()); ok && uid > 0 {
+			return "user:" + strconv.Itoa(uid)
+		}
+		return ""
+	})
+}
+
+func rateLimitBy(l *RateLimiter, keyFn func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if l == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := keyFn(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if ok, wait := l.Allow(key); !ok {
+				sec := int(math.Ceil(wait.Seconds()))
+				if sec < 1 {
+					sec = 1
+				}
+				rateLimited.Inc()
+				logger.WithCtx(r.Context()).Warn("Превышен лимит запросов",
+					zap.String("limiter", l.name),
+					zap.String("key", key),
+					zap.String("path", r.URL.Path),
+					zap.Int("retry_after_sec", sec),
+				)
+				w.Header().Set("Retry-After", strconv.Itoa(sec))
+				helpers.ErrorWithData(w, http.StatusTooManyRequests, "Слишком много запросов, попробуйте позже",
+					map[string]any{"retry_after_sec": sec})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimits — набор ограничителей для маршрутов (nil — выключен):
+// Auth — логин/регистрация/восстановление пароля по IP, Global — все запросы /api по IP,
+// User — авторизованные запросы по user_id.
+type RateLimits struct {
+	Auth   *RateLimiter
+	Global *RateLimiter
+	User   *RateLimiter
+}
@@ -1,35 +1,181 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"runtime/debug"
+	"strconv"
+	"strings"
 
 	"edutalks/internal/logger"
+	"edutalks/internal/reqctx"
+	"edutalks/internal/utils/clientip"
+	helpers "edutalks/internal/utils/helpers"
+
+	"github.com/getsentry/sentry-go"
+	sentryhttp "github.com/getsentry/sentry-go/http"
+	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
 
+// sentryHandler — hub Sentry на запрос; паники перехватывает Recoverer ниже по цепочке,
+// до sentryhttp доходит только штатный http.ErrAbortHandler (его отсекает SentryBeforeSend).
+var sentryHandler = sentryhttp.New(sentryhttp.Options{Repanic: true})
+
+// Sentry — sentryhttp: запрос, маршрут и request_id попадают в события, отправленные ниже по
+// цепочке (Recoverer). Ставить до Recoverer; без SENTRY_DSN события никуда не уходят.
+func Sentry(next http.Handler) http.Handler {
+	return sentryHandler.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hub := sentry.GetHubFromContext(r.Context()); hub != nil {
+			hub.Scope().SetTag("route", routeTemplate(r))
+			if rid, ok := reqctx.GetRequestID(r.Context()); ok && rid != "" {
+				hub.Scope().SetTag("request_id", rid)
+			}
+		}
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// SentryBeforeSend — фильтр событий для sentry.ClientOptions: штатный обрыв ответа не
+// отправляется, значения параметров с токенами и паролями в query заменяются на [Filtered].
+func SentryBeforeSend(ev *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+	if hint != nil {
+		if err, ok := hint.RecoveredException.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+			return nil
+		}
+	}
+	if ev.Request != nil && ev.Request.QueryString != "" {
+		if q, err := url.ParseQuery(ev.Request.QueryString); err == nil {
+			ev.Request.QueryString = scrubQuery(q)
+		} else {
+			ev.Request.QueryString = ""
+		}
+	}
+	return ev
+}
+
+// Recoverer — паника в обработчике превращается в 500 INTERNAL_ERROR в стандартном конверте
+// ошибки вместо обрыва соединения; стек пишется в лог с request_id. Если ответ уже начат,
+// дописать JSON нельзя — соединение обрывается, чтобы клиент не принял обрезанный ответ за целый.
+// С SENTRY_DSN паники и ответы 5xx через helpers уходят в Sentry вместе с запросом и пользователем
+// (Recoverer ставится после Logging и Sentry).
 func Recoverer(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &recoverWriter{ResponseWriter: w, r: r}
+
 		defer func() {
-			if rec := recover(); rec != nil {
-				fields := []zap.Field{
-					zap.Any("panic", rec),
-					zap.ByteString("stack", debug.Stack()),
-					zap.String("path", r.URL.Path),
-					zap.String("method", r.Method),
-				}
-				if rid, ok := r.Context().Value(ContextRequestID).(string); ok {
-					fields = append(fields, zap.String("request_id", rid))
-				}
-				if userID, ok := r.Context().Value(ContextUserID).(int); ok {
-					fields = append(fields, zap.Int("user_id", userID))
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler { // штатный обрыв ответа — пусть обработает net/http
+				panic(rec)
+			}
+			logger.WithCtx(r.Context()).Error("panic recovered",
+				zap.Any("panic", rec),
+				zap.ByteString("stack", debug.Stack()),
+				zap.String("path", r.URL.Path),
+				zap.String("method", r.Method),
+				zap.Bool("response_started", rw.started),
+			)
+			if hub := sentry.GetHubFromContext(r.Context()); hub != nil {
+				// не-error (panic("...")) SDK отправил бы сообщением без стека
+				err, ok := rec.(error)
+				if !ok {
+					err = fmt.Errorf("panic: %v", rec)
 				}
-				logger.Log.Error("panic recovered", fields...)
+				hub.WithScope(func(scope *sentry.Scope) {
+					scope.SetUser(requestUser(r))
+					scope.SetLevel(sentry.LevelFatal)
+					hub.RecoverWithContext(r.Context(), err)
+				})
+			}
 
-				w.WriteHeader(http.StatusInternalServerError)
-				_, _ = w.Write([]byte("internal server error"))
+			if rw.started {
+				panic(http.ErrAbortHandler)
 			}
+			// через исходный w: 500 после паники — уже в Sentry, повторно не сообщаем
+			helpers.Fail(w, http.StatusInternalServerError, helpers.CodeInternal, "Внутренняя ошибка сервера")
 		}()
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(rw, r)
 	})
 }
+
+// recoverWriter — запоминает, начат ли ответ, и реализует helpers.ServerErrorReporter:
+// ответ 5xx, отданный через helpers, уходит в Sentry.
+type recoverWriter struct {
+	http.ResponseWriter
+	r       *http.Request
+	started bool
+}
+
+func (rw *recoverWriter) WriteHeader(code int) {
+	if code >= 200 { // 1xx (103 Early Hints) ответ не начинают
+		rw.started = true
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recoverWriter) Write(p []byte) (int, error) {
+	rw.started = true
+	return rw.ResponseWriter.Write(p)
+}
+
+func (rw *recoverWriter) ReportServerError(status int, code helpers.ErrorCode, message string) {
+	hub := sentry.GetHubFromContext(rw.r.Context())
+	if hub == nil || hub.Client() == nil {
+		return
+	}
+	route := routeTemplate(rw.r)
+	ev := sentry.NewEvent()
+	ev.Level = sentry.LevelError
+	ev.Exception = []sentry.Exception{{
+		Type:       strconv.Itoa(status) + " " + string(code),
+		Value:      message,
+		Stacktrace: sentry.NewStacktrace(),
+	}}
+	ev.Fingerprint = []string{rw.r.Method, route, strconv.Itoa(status), message}
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetUser(requestUser(rw.r))
+		scope.SetTag("status", strconv.Itoa(status))
+		hub.CaptureEvent(ev)
+	})
+}
+
+// Unwrap — для http.ResponseController и поиска обёрток в helpers.
+func (rw *recoverWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// requestUser — пользователь из access-лога (JWTAuth заполняет его ниже по цепочке) и его IP.
+func requestUser(r *http.Request) sentry.User {
+	u := sentry.User{IPAddress: clientip.FromRequest(r)}
+	if ai, ok := r.Context().Value(accessInfoKey{}).(*accessInfo); ok && ai.userID != 0 {
+		u.ID = strconv.Itoa(ai.userID)
+	}
+	return u
+}
+
+func routeTemplate(r *http.Request) string {
+	if cur := mux.CurrentRoute(r); cur != nil {
+		if tpl, err := cur.GetPathTemplate(); err == nil {
+			return tpl
+		}
+	}
+	return "unmatched"
+}
+
+func scrubQuery(q url.Values) string {
+	for k := range q {
+		lk := strings.ToLower(k)
+		for _, s := range []string{"token", "secret", "password", "key", "sig", "code"} {
+			if strings.Contains(lk, s) {
+				q[k] = []string{"[Filtered]"}
+				break
+			}
+		}
+	}
+	return q.Encode()
+}
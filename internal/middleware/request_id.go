@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"edutalks/internal/reqctx"
+
+	"github.com/google/uuid"
+)
+
+// HeaderRequestID — заголовок корреляции запросов (входящий и в ответе).
+const HeaderRequestID = "X-Request-ID"
+
+const maxRequestIDLen = 128
+
+// RequestID берёт X-Request-ID от клиента/прокси (если он разумный) или генерирует новый,
+// кладёт его в контекст (его подхватывают logger.WithCtx и шина событий) и возвращает в ответе.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rid := r.Header.Get(HeaderRequestID)
+		if !validRequestID(rid) {
+			rid = uuid.NewString()
+		}
+
+		ctx := reqctx.WithRequestID(r.Context(), rid)
+		ctx = context.WithValue(ctx, ContextRequestID, rid)
+
+		w.Header().Set(HeaderRequestID, rid)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// validRequestID — не пустой, ограниченной длины и без символов, ломающих логи/заголовки.
+func validRequestID(s string) bool {
+	if s == "" || len(s) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	helpers "edutalks/internal/utils/helpers"
 	"go.uber.org/zap"
 )
 
@@ -20,7 +22,7 @@ func OnlyRole(role string) func(http.Handler) http.Handler {
 			if !ok || userRole != role {
 				logger.WithCtx(r.Context()).Warn("Доступ запрещён (OnlyRole)",
 					zap.String("required_role", role), zap.Any("got", value))
-				http.Error(w, "Доступ запрещён", http.StatusForbidden)
+				helpers.Error(w, http.StatusForbidden, "Доступ запрещён")
 				return
 			}
 
@@ -48,13 +50,13 @@ func AnyRole(allowedRoles ...string) func(http.Handler) http.Handler {
 			userRole, ok := value.(string)
 			if !ok {
 				logger.WithCtx(r.Context()).Warn("Роль не определена (AnyRole)")
-				http.Error(w, "Не удалось определить роль", http.StatusForbidden)
+				helpers.Error(w, http.StatusForbidden, "Не удалось определить роль")
 				return
 			}
 			if _, found := roleSet[userRole]; !found {
 				logger.WithCtx(r.Context()).Warn("Доступ запрещён (AnyRole)",
 					zap.String("user_role", userRole), zap.Any("allowed", allowedRoles))
-				http.Error(w, "Доступ запрещён", http.StatusForbidden)
+				helpers.Error(w, http.StatusForbidden, "Доступ запрещён")
 				return
 			}
 
@@ -64,3 +66,25 @@ func AnyRole(allowedRoles ...string) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// RequirePermission — пропускает роли с правом perm (models.HasPermission).
+func RequirePermission(perm string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if SkipGuards(r.Context()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userRole, _ := RoleFromContext(r.Context())
+			if !models.HasPermission(userRole, perm) {
+				logger.WithCtx(r.Context()).Warn("Доступ запрещён (RequirePermission)",
+					zap.String("permission", perm), zap.String("user_role", userRole))
+				helpers.Error(w, http.StatusForbidden, "Доступ запрещён")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// AdminDigest — еженедельная сводка для администраторов.
+type AdminDigest struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	NewUsers          int     `json:"new_users"`
+	PaymentsSucceeded int     `json:"payments_succeeded"`
+	PaymentsFailed    int     `json:"payments_failed"`
+	Revenue           float64 `json:"revenue"`
+
+	// Активные подписки, которые закончатся в ближайшие 7 дней
+	ExpiringSubscriptions int `json:"expiring_subscriptions"`
+
+	TopDownloads []DocumentDownloadStat `json:"top_downloads"`
+
+	ErrorsTotal int              `json:"errors_total"`
+	TopErrors   []DigestLogError `json:"top_errors"`
+}
+
+// DigestLogError — сгруппированные по сообщению ошибки из логов.
+type DigestLogError struct {
+	Message  string `json:"message"`
+	Count    int    `json:"count"`
+	LastSeen string `json:"last_seen,omitempty"`
+}
+
+// DigestLogStats — то, что удалось вытащить из файлов логов за период.
+type DigestLogStats struct {
+	ErrorsTotal int
+	TopErrors   []DigestLogError
+}
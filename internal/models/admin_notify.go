@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Шаблоны письма ручной рассылки администратора (см. helpers.BuildSimpleHTML / BuildNewsHTML).
+const (
+	NotifyTemplateSimple = "simple" // заголовок и текст
+	NotifyTemplateNews   = "news"   // текст и кнопка-ссылка «Читать новость»
+)
+
+// NotifyAudience — кому отправить ручную рассылку; пустые поля не ограничивают выборку.
+// Письма получают только подписанные на рассылку (email_subscription).
+type NotifyAudience struct {
+	Role            *string `json:"role,omitempty" validate:"oneof=user author admin"`
+	HasSubscription *bool   `json:"has_subscription,omitempty"`
+	// TabID — отслеживающие хотя бы один раздел вкладки
+	TabID *int `json:"tab_id,omitempty"`
+	// SectionID — отслеживающие раздел или его родителя (те, кому приходят его документы)
+	SectionID *int `json:"section_id,omitempty"`
+	// Дата регистрации: YYYY-MM-DD или RFC3339, границы включительно
+	RegisteredFrom string `json:"registered_from,omitempty" example:"2026-01-01"`
+	RegisteredTo   string `json:"registered_to,omitempty" example:"2026-03-31"`
+
+	// Разобранные RegisteredFrom / RegisteredTo (To — начало следующего дня)
+	CreatedFrom *time.Time `json:"-"`
+	CreatedTo   *time.Time `json:"-"`
+}
+
+// NotifyRecipient — адресат ручной рассылки и значения для подстановок.
+type NotifyRecipient struct {
+	Email    string
+	FullName string
+	Username string
+	Locale   string // язык рамки письма (кнопка, подпись)
+}
+
+// NotifyMessage — письмо ручной рассылки. В subject и message подставляются {{full_name}},
+// {{username}} и {{email}} получателя.
+type NotifyMessage struct {
+	Subject  string
+	Message  string // HTML
+	Template string // simple | news
+	URL      string // ссылка кнопки для шаблона news
+}
@@ -0,0 +1,14 @@
+package models
+
+// Каналы оповещений об ошибках.
+const (
+	AlertChannelTelegram = "telegram"
+	AlertChannelSlack    = "slack"
+)
+
+// AlertTestResult — итог тестовой отправки в канал оповещений.
+type AlertTestResult struct {
+	Channel string `json:"channel" example:"telegram"`
+	OK      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+}
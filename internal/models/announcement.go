@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+const (
+	AnnouncementInfo     = "info"
+	AnnouncementWarning  = "warning"
+	AnnouncementCritical = "critical"
+	AnnouncementPromo    = "promo"
+)
+
+// Announcement — баннер на сайте. Активен, если включён и текущий момент в окне
+// starts_at..ends_at (пустая граница — без ограничения).
+type Announcement struct {
+	ID          int        `json:"id"`
+	Message     string     `json:"message"`
+	LinkURL     *string    `json:"link_url,omitempty"`
+	LinkText    *string    `json:"link_text,omitempty"`
+	Severity    string     `json:"severity"`
+	Dismissible bool       `json:"dismissible"`
+	IsEnabled   bool       `json:"is_enabled"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	CreatedBy   *int       `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// AnnouncementRequest — создание и замена баннера. dismissible и enabled по умолчанию true.
+type AnnouncementRequest struct {
+	Message     string     `json:"message" validate:"required,max=1000"`
+	LinkURL     *string    `json:"link_url,omitempty" validate:"url,max=2048"`
+	LinkText    *string    `json:"link_text,omitempty" validate:"max=100"`
+	Severity    string     `json:"severity" validate:"oneof=info warning critical promo"`
+	Dismissible *bool      `json:"dismissible,omitempty"`
+	Enabled     *bool      `json:"enabled,omitempty"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+}
@@ -11,16 +11,38 @@ type Article struct {
 	Tags        []string   `db:"-"            json:"tags"`
 	IsPublished bool       `db:"is_published" json:"isPublished"`
 	PublishedAt *time.Time `db:"published_at" json:"publishedAt,omitempty"`
+	PublishAt   *time.Time `db:"publish_at"   json:"publishAt,omitempty"` // отложенная публикация
 	CreatedAt   time.Time  `db:"created_at"   json:"createdAt"`
 	UpdatedAt   time.Time  `db:"updated_at"   json:"updatedAt"`
+	ViewCount   int64      `db:"view_count"   json:"viewCount"`
+	// CoverImageURL — обложка статьи (обычно из медиатеки /uploads/articles/...)
+	CoverImageURL *string `db:"cover_image_url" json:"coverImageUrl,omitempty"`
+
+	// ContentUpdatedAt — последняя правка текста уже опубликованной статьи (не путать с updated_at)
+	ContentUpdatedAt  *time.Time `db:"content_updated_at" json:"contentUpdatedAt,omitempty"`
+	IsUpdatedRecently bool       `db:"-"                  json:"isUpdatedRecently"`
+
+	// ReviewState — состояние согласования (models.ReviewState*); заполняется в списках статей и в GetByID
+	ReviewState string `db:"-" json:"reviewState,omitempty"`
 }
 
 // swagger:model CreateArticleRequest
 type CreateArticleRequest struct {
-	Title       string   `json:"title"    example:"Как писать middleware в Go"`
-	Summary     string   `json:"summary"  example:"Короткое описание для превью"`
-	BodyHTML    string   `json:"bodyHtml" example:"<p>Контент</p>"`
-	Tags        []string `json:"tags"     example:"go,backend,markdown"`
+	Title       string   `json:"title"    example:"Как писать middleware в Go" validate:"required,max=300"`
+	Summary     string   `json:"summary"  example:"Короткое описание для превью" validate:"max=1000"`
+	BodyHTML    string   `json:"bodyHtml" example:"<p>Контент</p>" validate:"required"`
+	Tags        []string `json:"tags"     example:"go,backend,markdown" validate:"max=20"`
 	Publish     bool     `json:"publish"`
 	IsPublished *bool    `json:"isPublished,omitempty"`
+	// PublishAt — опубликовать по расписанию (RFC3339); игнорируется при publish=true
+	PublishAt *time.Time `json:"publishAt,omitempty" example:"2026-11-01T09:00:00+03:00"`
+	// CoverImageURL — обложка; пусто — без обложки
+	CoverImageURL string `json:"coverImageUrl" example:"/uploads/articles/1760000000_a1b2c3d4e5f6.jpg" validate:"url,max=2048"`
 }
+
+// Статусы статей для фильтра списка (пусто — все).
+const (
+	ArticleStatusPublished = "published"
+	ArticleStatusDraft     = "draft"     // не опубликована и не запланирована
+	ArticleStatusScheduled = "scheduled" // ждёт publish_at
+)
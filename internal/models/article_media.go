@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// ArticleMedia — изображение из медиатеки статей.
+type ArticleMedia struct {
+	ID           int64     `json:"id"`
+	URL          string    `json:"url"`
+	Path         string    `json:"-"`
+	OriginalName string    `json:"original_name"`
+	ContentType  string    `json:"content_type"`
+	SizeBytes    int64     `json:"size_bytes"`
+	UploadedBy   *int      `json:"uploaded_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// UsedIn — статьи, где изображение стоит обложкой или встречается в тексте либо черновике
+	UsedIn []int64 `json:"used_in"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// ArticleRevision — сохранённая предыдущая версия статьи.
+type ArticleRevision struct {
+	ID        int64     `json:"id"`
+	ArticleID int64     `json:"articleId"`
+	Title     string    `json:"title"`
+	Summary   *string   `json:"summary,omitempty"`
+	BodyHTML  string    `json:"bodyHtml,omitempty"` // в списке ревизий не отдаётся
+	Tags      []string  `json:"tags"`
+	EditorID  *int64    `json:"editorId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ArticleDraft — автосохранённый черновик правок статьи.
+type ArticleDraft struct {
+	ArticleID int64     `json:"articleId"`
+	Title     string    `json:"title"`
+	Summary   *string   `json:"summary,omitempty"`
+	BodyHTML  string    `json:"bodyHtml"`
+	Tags      []string  `json:"tags"`
+	EditorID  *int64    `json:"editorId,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// swagger:model ArticleDraftRequest
+type ArticleDraftRequest struct {
+	Title    string   `json:"title" validate:"max=300"`
+	Summary  string   `json:"summary" validate:"max=1000"`
+	BodyHTML string   `json:"bodyHtml"`
+	Tags     []string `json:"tags" validate:"max=20"`
+}
+
+// DiffLine — строка построчного сравнения: op "=" без изменений, "-" удалено, "+" добавлено.
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// ArticleRevisionDiff — отличия ревизии от текущей версии статьи.
+type ArticleRevisionDiff struct {
+	ArticleID     int64      `json:"articleId"`
+	RevisionID    int64      `json:"revisionId"`
+	ChangedFields []string   `json:"changedFields"`
+	Body          []DiffLine `json:"body"`
+}
+
+// HTMLDiffNode — узел верхнего уровня при сравнении HTML: op "=" без изменений, "-" удалён, "+" вставлен.
+type HTMLDiffNode struct {
+	Op   string `json:"op"`
+	Tag  string `json:"tag"` // имя элемента или "#text"
+	HTML string `json:"html"`
+}
+
+// ArticleRevisionCompare — отличия между двумя версиями статьи (from → to).
+type ArticleRevisionCompare struct {
+	ArticleID      int64          `json:"articleId"`
+	FromRevisionID int64          `json:"fromRevisionId"`
+	ToRevisionID   int64          `json:"toRevisionId"`
+	ChangedFields  []string       `json:"changedFields"`
+	Nodes          []HTMLDiffNode `json:"nodes"`
+	HTML           string         `json:"html"` // готовая разметка: удалённое в <del>, вставленное в <ins>
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Уровни доступа маршрута (по middleware роутера).
+const (
+	RouteAccessPublic    = "public"
+	RouteAccessProtected = "protected"
+	RouteAccessAdmin     = "admin"
+	RouteAccessAuthor    = "author"  // роли с правом content:manage (author, admin)
+	RouteAccessHandler   = "handler" // без JWT-middleware, токен проверяет сам хендлер
+)
+
+// Расхождения маршрута с описанием в Swagger.
+const (
+	AuthzMissingSecurity = "missing_security" // маршрут требует JWT, а в Swagger нет security
+	AuthzExtraSecurity   = "extra_security"   // в Swagger есть security, а маршрут публичный
+	AuthzUndocumented    = "undocumented"     // маршрута нет в Swagger
+	AuthzStaleDoc        = "stale_doc"        // в Swagger есть путь, которого нет в роутере
+)
+
+type RouteAccess struct {
+	Method          string `json:"method"`
+	Path            string `json:"path"`
+	Access          string `json:"access,omitempty"`
+	Documented      bool   `json:"documented"`
+	SwaggerSecurity bool   `json:"swagger_security"`
+	Mismatch        string `json:"mismatch,omitempty"`
+}
+
+// AuthzReport — какие маршруты публичные, под JWT, для авторов и только для админа, и где Swagger с этим расходится.
+type AuthzReport struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Summary     map[string]int `json:"summary"`
+	Routes      []RouteAccess  `json:"routes"`
+	Mismatches  []RouteAccess  `json:"mismatches"`
+}
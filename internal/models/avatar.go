@@ -0,0 +1,34 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AvatarSizes — стороны квадратных версий аватара в пикселях, по убыванию; первая — для профиля.
+var AvatarSizes = []int{256, 128, 64}
+
+// AvatarSize — ближайший размер из AvatarSizes не меньше requested (больше всех — самый крупный).
+func AvatarSize(requested int) int {
+	sizes := append([]int(nil), AvatarSizes...)
+	sort.Ints(sizes)
+	for _, s := range sizes {
+		if s >= requested {
+			return s
+		}
+	}
+	return sizes[len(sizes)-1]
+}
+
+// AvatarFile — путь файла размера size относительно uploads.
+func AvatarFile(key string, size int) string {
+	return fmt.Sprintf("%s_%d.jpg", key, size)
+}
+
+// AvatarURL — ссылка на аватар размера size; без загруженного — identicon.
+func AvatarURL(userID int, key *string, size int) string {
+	if key == nil || *key == "" {
+		return fmt.Sprintf("/api/avatars/%d?size=%d", userID, size)
+	}
+	return "/uploads/" + AvatarFile(*key, size)
+}
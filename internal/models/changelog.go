@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+const (
+	ChangelogKindFeature     = "feature"
+	ChangelogKindImprovement = "improvement"
+	ChangelogKindFix         = "fix"
+)
+
+type ChangelogEntry struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	BodyHTML    string     `json:"body_html"`
+	Version     *string    `json:"version,omitempty"`
+	Kind        string     `json:"kind"`
+	IsPublished bool       `json:"is_published"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type ChangelogEntryRequest struct {
+	Title    string  `json:"title"`
+	BodyHTML string  `json:"body_html"`
+	Version  *string `json:"version,omitempty"`
+	Kind     string  `json:"kind"`
+	Publish  bool    `json:"publish"`
+}
+
+// ChangelogUnread — данные для бейджа «Что нового».
+type ChangelogUnread struct {
+	Unread     int        `json:"unread"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+}
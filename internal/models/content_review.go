@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Типы материалов на согласовании.
+const (
+	ReviewArticle  = "article"
+	ReviewDocument = "document"
+)
+
+// Состояния согласования: draft → review → published, review → rejected → review.
+// Опубликованный админом напрямую материал тоже считается published, снятый с публикации — draft.
+const (
+	ReviewStateDraft     = "draft"
+	ReviewStateReview    = "review"
+	ReviewStateRejected  = "rejected"
+	ReviewStatePublished = "published"
+)
+
+// ReviewItem — материал в очереди согласования.
+type ReviewItem struct {
+	Type       string    `json:"type" example:"article"`
+	ID         int64     `json:"id" example:"42"`
+	Title      string    `json:"title" example:"Как писать middleware в Go"`
+	State      string    `json:"state" example:"review"`
+	AuthorID   *int      `json:"author_id,omitempty" example:"7"`
+	AuthorName string    `json:"author_name,omitempty" example:"Иван Петров"`
+	UpdatedAt  time.Time `json:"updated_at"` // последний переход состояния (или создание)
+}
+
+// ReviewEvent — переход состояния с комментарием.
+type ReviewEvent struct {
+	ID        int64     `json:"id"`
+	FromState string    `json:"from_state" example:"review"`
+	ToState   string    `json:"to_state" example:"rejected"`
+	ActorID   *int      `json:"actor_id,omitempty"`
+	ActorName string    `json:"actor_name,omitempty"`
+	Comment   string    `json:"comment,omitempty" example:"Добавьте список литературы"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReviewDecisionRequest — комментарий к отправке, одобрению или отклонению (при отклонении обязателен).
+type ReviewDecisionRequest struct {
+	Comment string `json:"comment" validate:"max=2000" example:"Добавьте список литературы"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Типы материалов со счётчиком просмотров.
+const (
+	ContentKindNews    = "news"
+	ContentKindArticle = "article"
+)
+
+// TrendingItem — материал в подборке популярного за период.
+type TrendingItem struct {
+	Type        string     `json:"type" example:"news"`
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	Views       int64      `json:"views"`      // засчитанных просмотров за период
+	ViewCount   int64      `json:"view_count"` // всего
+}
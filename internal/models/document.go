@@ -14,6 +14,51 @@ type Document struct {
 	AllowFreeDownload bool      `json:"allow_free_download"`
 	SectionID         *int      `json:"section_id"`
 	UploadedAt        time.Time `json:"uploaded_at"`
+
+	// Доступность: текстовый слой (читается скринридером), версия крупным шрифтом, аудиоверсия
+	HasTextLayer  bool    `json:"has_text_layer"`
+	LargePrintURL *string `json:"large_print_url,omitempty"`
+	AudioURL      *string `json:"audio_url,omitempty"`
+
+	// ContentUpdatedAt — когда появилась новая версия (текстовый слой, крупный шрифт, аудио)
+	ContentUpdatedAt  *time.Time `json:"content_updated_at,omitempty"`
+	IsUpdatedRecently bool       `json:"is_updated_recently"`
+
+	// Связи с другими документами; SupersededBy — действующая редакция, если этот документ заменён
+	Relations    []DocumentLink `json:"relations,omitempty"`
+	SupersededBy *DocumentLink  `json:"superseded_by,omitempty"`
+
+	// Реквизиты нормативного документа: номер, кем принят, дата принятия и вступления в силу
+	DocNumber        *string    `json:"doc_number,omitempty"`
+	IssuingAuthority *string    `json:"issuing_authority,omitempty"`
+	AdoptedAt        *time.Time `json:"adopted_at,omitempty"`
+	EffectiveAt      *time.Time `json:"effective_at,omitempty"`
+
+	// Антивирусная проверка (DocumentScan*); пусто — файл загружен до появления проверки
+	ScanStatus    string     `json:"scan_status,omitempty"`
+	ScanSignature *string    `json:"scan_signature,omitempty"`
+	ScannedAt     *time.Time `json:"scanned_at,omitempty"`
+
+	// Содержимое файла: SHA-256 (hex) и размер — для поиска дубликатов
+	SHA256    string `json:"-"`
+	SizeBytes int64  `json:"-"`
+
+	// ReviewState — состояние согласования (ReviewState*); заполняется в списке документов автора и в GetDocumentByID
+	ReviewState string `json:"review_state,omitempty"`
+}
+
+// Статусы антивирусной проверки файла документа.
+const (
+	DocumentScanClean    = "clean"
+	DocumentScanInfected = "infected"
+	DocumentScanSkipped  = "skipped" // проверка выключена (CLAMAV_ADDRESS не задан)
+	DocumentScanError    = "error"   // clamd недоступен или не смог проверить файл
+)
+
+// DocumentScanTarget — файл для повторной проверки.
+type DocumentScanTarget struct {
+	ID       int
+	Filepath string
 }
 
 type DocumentPreviewResponse struct {
@@ -25,4 +70,83 @@ type DocumentPreviewResponse struct {
 	UploadedAt        string `json:"uploaded_at"`
 	Message           string `json:"message"`
 	AllowFreeDownload bool   `json:"allow_free_download"`
+
+	HasTextLayer  bool    `json:"has_text_layer"`
+	LargePrintURL *string `json:"large_print_url,omitempty"`
+	AudioURL      *string `json:"audio_url,omitempty"`
+
+	ContentUpdatedAt  *time.Time `json:"content_updated_at,omitempty"`
+	IsUpdatedRecently bool       `json:"is_updated_recently"`
+
+	// Связи с другими документами; SupersededBy — действующая редакция, если этот документ заменён
+	Relations    []DocumentLink `json:"relations,omitempty"`
+	SupersededBy *DocumentLink  `json:"superseded_by,omitempty"`
+
+	DocNumber        *string `json:"doc_number,omitempty"`
+	IssuingAuthority *string `json:"issuing_authority,omitempty"`
+	AdoptedAt        string  `json:"adopted_at,omitempty"`   // YYYY-MM-DD
+	EffectiveAt      string  `json:"effective_at,omitempty"` // YYYY-MM-DD
+}
+
+// DocumentAccessibilityFilter — фильтр списков по доступности (false — не фильтровать).
+type DocumentAccessibilityFilter struct {
+	TextLayer  bool
+	LargePrint bool
+	Audio      bool
+}
+
+// UpdateDocumentAccessibilityRequest — изменение полей доступности документа (nil — не менять, "" — очистить ссылку).
+type UpdateDocumentAccessibilityRequest struct {
+	HasTextLayer  *bool   `json:"has_text_layer,omitempty"`
+	LargePrintURL *string `json:"large_print_url,omitempty"`
+	AudioURL      *string `json:"audio_url,omitempty"`
+}
+
+// Сортировки публичных списков документов (query sort); по умолчанию — uploaded_at.
+const (
+	DocumentSortUploaded  = "uploaded_at"
+	DocumentSortAdopted   = "adopted_at"
+	DocumentSortEffective = "effective_at"
+	DocumentSortNumber    = "doc_number"
+)
+
+// DocumentNormativeFilter — фильтры и сортировка по реквизитам нормативного документа (пустые — не фильтровать).
+type DocumentNormativeFilter struct {
+	Number        string // подстрока номера без учёта регистра
+	Authority     string // подстрока органа, принявшего документ
+	AdoptedFrom   *time.Time
+	AdoptedTo     *time.Time
+	EffectiveFrom *time.Time
+	EffectiveTo   *time.Time
+	InForce       bool // только вступившие в силу на сегодня
+
+	Sort string // uploaded_at | adopted_at | effective_at | doc_number
+	Asc  bool
+}
+
+// UpdateDocumentNormativeRequest — изменение реквизитов (nil — не менять, "" — очистить); даты в формате YYYY-MM-DD.
+type UpdateDocumentNormativeRequest struct {
+	DocNumber        *string `json:"doc_number,omitempty"`
+	IssuingAuthority *string `json:"issuing_authority,omitempty"`
+	AdoptedAt        *string `json:"adopted_at,omitempty"`
+	EffectiveAt      *string `json:"effective_at,omitempty"`
+}
+
+// UpdateDocumentRequest — правка основных метаданных документа (nil — не менять).
+type UpdateDocumentRequest struct {
+	Title             *string `json:"title,omitempty" validate:"notblank,max=255"`
+	Description       *string `json:"description,omitempty"`
+	Category          *string `json:"category,omitempty" validate:"max=64"`
+	IsPublic          *bool   `json:"is_public,omitempty"`
+	AllowFreeDownload *bool   `json:"allow_free_download,omitempty"`
+}
+
+// DocumentFile — новый файл документа: уже сохранён на диск и проверен антивирусом.
+type DocumentFile struct {
+	Filename   string
+	Filepath   string
+	ScanStatus string
+	ScannedAt  *time.Time
+	SHA256     string
+	SizeBytes  int64
 }
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// DocumentDuplicate — документ с тем же содержимым файла (SHA-256), что и загружаемый или другие в группе.
+// linked — документ не хранит свою копию, а ссылается на файл более раннего документа группы.
+type DocumentDuplicate struct {
+	ID         int       `json:"id" example:"42"`
+	Title      string    `json:"title" example:"Положение об аттестации"`
+	Filename   string    `json:"filename" example:"polozhenie.pdf"`
+	UploadedAt time.Time `json:"uploaded_at"`
+	Linked     bool      `json:"linked"`
+	InTrash    bool      `json:"in_trash"`
+	Filepath   string    `json:"-"`
+}
+
+// DocumentDuplicateGroup — документы с одинаковым файлом. files — сколько копий лежит на диске,
+// wasted_bytes — место под лишние копии (все, кроме одной).
+type DocumentDuplicateGroup struct {
+	SHA256      string              `json:"sha256"`
+	SizeBytes   int64               `json:"size_bytes" example:"1048576"`
+	Files       int                 `json:"files" example:"2"`
+	WastedBytes int64               `json:"wasted_bytes" example:"1048576"`
+	Documents   []DocumentDuplicate `json:"documents"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// DocumentGrant — персональный доступ пользователя к документу, выданный админом:
+// открывает закрытый документ и не требует подписки. expires_at = nil — бессрочно.
+type DocumentGrant struct {
+	ID         int64      `json:"id"`
+	DocumentID int        `json:"document_id"`
+	UserID     int        `json:"user_id"`
+	Email      string     `json:"email"`
+	FullName   string     `json:"full_name"`
+	GrantedBy  *int       `json:"granted_by,omitempty"`
+	Note       string     `json:"note,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// GrantDocumentRequest — выдать доступ (повторная выдача тому же пользователю меняет срок и заметку).
+type GrantDocumentRequest struct {
+	UserID    int        `json:"user_id" validate:"required,min=1" example:"17"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Note      string     `json:"note" validate:"max=500"`
+}
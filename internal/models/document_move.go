@@ -0,0 +1,30 @@
+package models
+
+// Исход переноса отдельного документа.
+const (
+	DocumentMoveOK       = "ok"
+	DocumentMoveNotFound = "not_found"
+)
+
+// DocumentBulkMoveRequest — перенос документов в раздел section_id (null — убрать из раздела).
+// Документы задаются списком document_ids и/или всеми документами раздела from_section_id.
+type DocumentBulkMoveRequest struct {
+	DocumentIDs   []int `json:"document_ids,omitempty" validate:"max=1000"`
+	FromSectionID *int  `json:"from_section_id,omitempty" validate:"min=1"`
+	SectionID     *int  `json:"section_id" validate:"min=1"`
+}
+
+type DocumentMoveResult struct {
+	DocumentID    int    `json:"document_id"`
+	Status        string `json:"status"` // ok | not_found
+	FromSectionID *int   `json:"from_section_id,omitempty"`
+}
+
+// DocumentBulkMoveReport — итог переноса с результатом по каждому документу.
+type DocumentBulkMoveReport struct {
+	SectionID *int                 `json:"section_id"`
+	Total     int                  `json:"total"`
+	Moved     int                  `json:"moved"`
+	NotFound  int                  `json:"not_found"`
+	Results   []DocumentMoveResult `json:"results"`
+}
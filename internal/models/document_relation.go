@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Типы связей документов (хранятся в document_relations.relation).
+const (
+	DocumentRelationSupersedes = "supersedes" // документ заменяет related (новая редакция приказа)
+	DocumentRelationAmendedBy  = "amended_by" // в документ внесены изменения документом related
+	DocumentRelationRelated    = "related"    // связанный норматив
+
+	// Обратные названия — как связь выглядит со стороны related.
+	DocumentRelationSupersededBy = "superseded_by"
+	DocumentRelationAmends       = "amends"
+)
+
+// InverseDocumentRelation — тип связи с точки зрения второго документа.
+func InverseDocumentRelation(relation string) string {
+	switch relation {
+	case DocumentRelationSupersedes:
+		return DocumentRelationSupersededBy
+	case DocumentRelationAmendedBy:
+		return DocumentRelationAmends
+	}
+	return relation
+}
+
+type DocumentRelation struct {
+	ID         int64     `json:"id"`
+	DocumentID int       `json:"document_id"`
+	RelatedID  int       `json:"related_id"`
+	Relation   string    `json:"relation"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// DocumentLink — связь с точки зрения текущего документа: relation уже развёрнут
+// (для заменённого приказа — superseded_by и ссылка на новый).
+type DocumentLink struct {
+	RelationID int64  `json:"relation_id"`
+	Relation   string `json:"relation"`
+	DocumentID int    `json:"document_id"`
+	Title      string `json:"title"`
+	IsPublic   bool   `json:"is_public"`
+	Note       string `json:"note,omitempty"`
+}
+
+type CreateDocumentRelationRequest struct {
+	RelatedID int    `json:"related_id"`
+	Relation  string `json:"relation"` // supersedes | amended_by | related
+	Note      string `json:"note"`
+}
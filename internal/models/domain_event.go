@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// DomainEvent — продуктовое событие (регистрация, выдача подписки, скачивание, публикация…).
+type DomainEvent struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	UserID     *int           `json:"user_id,omitempty"`
+	RequestID  string         `json:"request_id,omitempty"`
+	Payload    map[string]any `json:"payload,omitempty"`
+	OccurredAt time.Time      `json:"occurred_at"`
+}
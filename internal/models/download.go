@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// DocumentDownloadStat — скачивания документа за период.
+type DocumentDownloadStat struct {
+	DocumentID     int        `json:"document_id"`
+	Title          string     `json:"title"`
+	Downloads      int        `json:"downloads"`
+	UniqueUsers    int        `json:"unique_users"`
+	LastDownloadAt *time.Time `json:"last_download_at,omitempty"`
+}
+
+// DownloadsDayPoint — точка временного ряда скачиваний (день в формате YYYY-MM-DD).
+type DownloadsDayPoint struct {
+	Day         string `json:"day"`
+	Downloads   int    `json:"downloads"`
+	UniqueUsers int    `json:"unique_users"`
+}
+
+// DownloadStatsFilter — период и (опционально) конкретный документ.
+type DownloadStatsFilter struct {
+	DocumentID *int
+	From       *time.Time
+	To         *time.Time
+}
+
+// Тарифы дневного лимита скачиваний. У подписчика с оплатой тариф — план последнего
+// успешного платежа (monthly | halfyear | yearly); подписка без оплаты (выдана админом) — subscriber.
+const (
+	DownloadTierFree       = "free"
+	DownloadTierSubscriber = "subscriber"
+	DownloadTierAdmin      = "admin" // без ограничения
+)
+
+// DownloadQuota — дневной лимит скачиваний пользователя.
+type DownloadQuota struct {
+	Tier      string    `json:"tier"`
+	Limit     *int      `json:"limit"` // nil — без ограничения
+	Used      int       `json:"used"`
+	Remaining *int      `json:"remaining"`
+	Override  bool      `json:"override"` // лимит задан администратором
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
+// DownloadQuotaState — сохранённое состояние лимита за день.
+type DownloadQuotaState struct {
+	Used          int
+	Plan          string // план последнего успешного платежа; "" — платежей нет
+	HasOverride   bool
+	OverrideLimit *int
+}
+
+// DownloadQuotaOverrideRequest — индивидуальный дневной лимит; daily_limit null — без ограничения.
+type DownloadQuotaOverrideRequest struct {
+	DailyLimit *int `json:"daily_limit" validate:"min=0,max=100000"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// EmailChangeRequest — запрос на смену email: адрес меняется после подтверждения ссылкой из письма.
+type EmailChangeRequest struct {
+	ID          int64      `json:"id"`
+	UserID      int        `json:"user_id"`
+	NewEmail    string     `json:"new_email"`
+	TokenHash   string     `json:"-"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+const (
+	EmailStatusPending = "pending"
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed"
+)
+
+type EmailOutbox struct {
+	ID            int64      `json:"id"`
+	Recipients    []string   `json:"recipients"`
+	Subject       string     `json:"subject"`
+	Body          string     `json:"body,omitempty"`
+	IsHTML        bool       `json:"is_html"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     *string    `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	CampaignID    *int64     `json:"campaign_id,omitempty"` // письмо рассылки администратора
+}
+
+// EmailOutboxFilter — фильтры админского списка писем; пустые поля не применяются.
+type EmailOutboxFilter struct {
+	Recipient string
+	Status    string
+	Subject   string
+	From      *time.Time
+	To        *time.Time
+}
+
+// EmailTemplateInfo — шаблон письма в админке; Source — только в ответе на запрос одного шаблона.
+type EmailTemplateInfo struct {
+	Name       string `json:"name"`
+	Overridden bool   `json:"overridden"`
+	Source     string `json:"source,omitempty"`
+}
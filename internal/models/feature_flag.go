@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// FeatureFlag — флаг фичи. Включённый флаг с rollout_percent < 100 виден только части
+// пользователей: доля определяется по ID пользователя и стабильна; анонимам — только при 100.
+type FeatureFlag struct {
+	Key            string    `json:"key"`
+	Description    string    `json:"description"`
+	Enabled        bool      `json:"enabled"`
+	RolloutPercent int       `json:"rollout_percent"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// FeatureFlagRequest — создание и замена флага; rollout_percent по умолчанию 100.
+type FeatureFlagRequest struct {
+	Key            string `json:"key,omitempty" validate:"max=64"`
+	Description    string `json:"description" validate:"max=500"`
+	Enabled        bool   `json:"enabled"`
+	RolloutPercent *int   `json:"rollout_percent,omitempty" validate:"min=0,max=100"`
+}
@@ -0,0 +1,20 @@
+package models
+
+// HTMLProfile — что разрешено в HTML статей сверх базового профиля (bluemonday UGC).
+type HTMLProfile struct {
+	ExtraElements []string            `json:"extra_elements"`
+	ExtraAttrs    map[string][]string `json:"extra_attrs"` // элемент → атрибуты
+	IframeHosts   []string            `json:"iframe_hosts"`
+	AllowVideo    bool                `json:"allow_video"`
+}
+
+type HTMLPreviewRequest struct {
+	HTML string `json:"html" validate:"max=1000000"`
+}
+
+// HTMLPreviewResponse — результат очистки; changed — очистка что-то удалила или изменила.
+type HTMLPreviewResponse struct {
+	HTML    string      `json:"html"`
+	Changed bool        `json:"changed"`
+	Profile HTMLProfile `json:"profile"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Impersonation — запись журнала: администратор получил токен, действующий от имени пользователя.
+// SessionID — сессия пользователя, к которой привязан токен (0 — у записей до учёта сессий).
+type Impersonation struct {
+	ID        int64      `json:"id"`
+	AdminID   int        `json:"admin_id"`
+	UserID    int        `json:"user_id"`
+	SessionID int64      `json:"session_id"`
+	Reason    string     `json:"reason"`
+	IP        string     `json:"ip"`
+	UserAgent string     `json:"user_agent"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+}
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+type ImpersonateResponse struct {
+	AccessToken   string        `json:"access_token"`
+	ExpiresAt     time.Time     `json:"expires_at"`
+	Impersonation Impersonation `json:"impersonation"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Invoice — квитанция об оплате: номер из сквозной последовательности, одна на платёж.
+type Invoice struct {
+	ID        int64      `json:"id"`
+	PaymentID int64      `json:"payment_id"`
+	UserID    int        `json:"user_id"`
+	Number    string     `json:"number"`
+	IssuedAt  time.Time  `json:"issued_at"`
+	EmailedAt *time.Time `json:"emailed_at,omitempty"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// LogRetentionSettings — сколько хранить файлы логов; compress_after_days = 0 — не сжимать.
+// Сжатие имеет смысл, только если наступает раньше удаления.
+type LogRetentionSettings struct {
+	RetentionDays     int `json:"retention_days" validate:"min=1,max=365" example:"14"`
+	CompressAfterDays int `json:"compress_after_days" validate:"min=0,max=365" example:"2"`
+}
+
+// LogCleanupReport — итог очистки логов: какие файлы сжаты и удалены и сколько места освобождено.
+type LogCleanupReport struct {
+	Compressed []string `json:"compressed"`
+	Deleted    []string `json:"deleted"`
+	FreedBytes int64    `json:"freed_bytes"`
+}
@@ -3,11 +3,43 @@ package models
 import "time"
 
 type News struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
-	ImageURL  string    `json:"image_url"`
-	Color     string    `json:"color"`
-	Sticker   string    `json:"sticker"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`
+	ImageURL    string     `json:"image_url"`
+	Color       string     `json:"color"`
+	Sticker     string     `json:"sticker"`
+	Category    string     `json:"category"`
+	Tags        []string   `json:"tags"`
+	IsPublished bool       `json:"is_published"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"` // отложенная публикация
+	ViewCount   int64      `json:"view_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+const (
+	NewsStatusPublished = "published"
+	NewsStatusDraft     = "draft"     // не опубликована и не запланирована
+	NewsStatusScheduled = "scheduled" // ждёт publish_at
+)
+
+// NewsFilter — фильтры списка новостей; пустые поля не применяются.
+// Status — NewsStatus* или пусто (все, включая черновики).
+type NewsFilter struct {
+	Tag      string
+	Category string
+	Status   string
+}
+
+// NewsUpdate — новое содержимое новости; Category и Tags nil — оставить как есть,
+// пустой (не nil) срез тегов — очистить.
+type NewsUpdate struct {
+	Title    string
+	Content  string
+	ImageURL string
+	Color    string
+	Sticker  string
+	Category *string
+	Tags     []string
 }
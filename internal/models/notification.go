@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Типы уведомлений в личном кабинете.
+const (
+	NotificationDocumentAdded        = "document_added"        // новые документы в отслеживаемом разделе
+	NotificationSubscriptionExpiring = "subscription_expiring" // подписка скоро закончится
+	NotificationBroadcast            = "broadcast"             // объявление администрации
+)
+
+// Notification — уведомление пользователя; read_at null — не прочитано.
+type Notification struct {
+	ID        int64          `json:"id"`
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Body      string         `json:"body,omitempty"`
+	Link      *string        `json:"link,omitempty"`
+	Data      map[string]any `json:"data,omitempty"`
+	ReadAt    *time.Time     `json:"read_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// Аудитория объявления администрации.
+const (
+	BroadcastAll         = "all"
+	BroadcastSubscribers = "subscribers" // только с действующей подпиской
+)
+
+// swagger:model BroadcastRequest
+type BroadcastRequest struct {
+	Title    string  `json:"title" example:"Плановые работы 20 октября" validate:"required,notblank,max=200"`
+	Body     string  `json:"body" example:"С 02:00 до 04:00 сайт может быть недоступен" validate:"max=2000"`
+	Link     *string `json:"link,omitempty" example:"/news/42" validate:"url,max=2048"`
+	Audience string  `json:"audience" example:"all" validate:"oneof=all subscribers"`
+}
+
+// SectionFollow — раздел, на новые документы в котором подписан пользователь.
+type SectionFollow struct {
+	SectionID  int       `json:"section_id"`
+	Title      string    `json:"title"`
+	TabSlug    string    `json:"tab_slug"`
+	Slug       string    `json:"slug"`
+	FollowedAt time.Time `json:"followed_at"`
+}
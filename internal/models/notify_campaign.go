@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Состояние рассылки по расписанию.
+const (
+	CampaignScheduled = "scheduled" // ждёт send_at (повторяющаяся — следующего запуска)
+	CampaignSent      = "sent"      // разовая рассылка отправлена
+	CampaignCancelled = "cancelled"
+)
+
+// Повтор рассылки.
+const (
+	CampaignRepeatNone    = "none"
+	CampaignRepeatDaily   = "daily"
+	CampaignRepeatWeekly  = "weekly"
+	CampaignRepeatMonthly = "monthly"
+)
+
+// NotifyCampaign — рассылка администратора по расписанию (письмо как в POST /api/admin/notify).
+type NotifyCampaign struct {
+	ID        int64          `json:"id"`
+	Subject   string         `json:"subject"`
+	Message   string         `json:"message"`
+	Template  string         `json:"template"`
+	URL       string         `json:"url,omitempty"`
+	Audience  NotifyAudience `json:"audience"`
+	SendAt    time.Time      `json:"send_at"` // ближайший запуск
+	Repeat    string         `json:"repeat"`  // none | daily | weekly | monthly
+	Status    string         `json:"status"`  // scheduled | sent | cancelled
+	Runs      int            `json:"runs"`
+	LastRunAt *time.Time     `json:"last_run_at,omitempty"`
+	CreatedBy *int           `json:"created_by,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+
+	Stats NotifyCampaignStats `json:"stats"`
+}
+
+// NotifyCampaignStats — доставка писем рассылки за все запуски, по адресатам.
+type NotifyCampaignStats struct {
+	Queued int `json:"queued"` // ждут отправки (в том числе повторной)
+	Sent   int `json:"sent"`
+	Failed int `json:"failed"`
+}
+
+// NotifyCampaignRequest — создание и правка рассылки по расписанию.
+type NotifyCampaignRequest struct {
+	Subject  string          `json:"subject" validate:"required,max=200"`
+	Message  string          `json:"message" validate:"required"` // HTML; подстановки {{full_name}}, {{username}}, {{email}}
+	Template string          `json:"template,omitempty" validate:"oneof=simple news"`
+	URL      string          `json:"url,omitempty" validate:"url"`
+	Audience *NotifyAudience `json:"audience,omitempty"`
+	SendAt   time.Time       `json:"send_at" validate:"required" example:"2026-11-01T09:00:00+03:00"`
+	Repeat   string          `json:"repeat,omitempty" validate:"oneof=none daily weekly monthly"` // по умолчанию none
+}
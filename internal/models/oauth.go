@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Провайдеры входа через соцсети.
+const (
+	OAuthProviderGoogle = "google"
+	OAuthProviderYandex = "yandex"
+)
+
+// OAuthIdentity — внешний аккаунт, привязанный к пользователю.
+type OAuthIdentity struct {
+	ID        int64     `json:"id"`
+	UserID    int       `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"-"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
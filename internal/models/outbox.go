@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	OutboxKindEmail  = "email"
+	OutboxKindEvent  = "event"
+	OutboxKindNotify = "notify"
+)
+
+// Типы рассылок подписчикам о публикации (OutboxNotify.Type).
+const (
+	NotifyNewsPublished    = "news_published"
+	NotifyArticlePublished = "article_published"
+)
+
+// OutboxIntent — побочный эффект, который нужно выполнить после коммита транзакции.
+type OutboxIntent struct {
+	Kind    string
+	Payload json.RawMessage
+}
+
+// OutboxRecord — запись side_effect_outbox, взятая relay-воркером в работу.
+type OutboxRecord struct {
+	ID        int64
+	Kind      string
+	Payload   json.RawMessage
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// OutboxEmail — payload намерения kind=email.
+type OutboxEmail struct {
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Body    string   `json:"body"`
+	IsHTML  bool     `json:"is_html"`
+}
+
+// OutboxEvent — payload намерения kind=event.
+type OutboxEvent struct {
+	Type    string         `json:"type"`
+	UserID  *int           `json:"user_id,omitempty"`
+	Payload map[string]any `json:"payload,omitempty"`
+}
+
+// OutboxNotify — payload намерения kind=notify: рассылка подписчикам о публикации.
+type OutboxNotify struct {
+	Type  string `json:"type"`
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
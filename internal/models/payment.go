@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	PaymentStatusPending   = "pending"
+	PaymentStatusSucceeded = "succeeded"
+	PaymentStatusCanceled  = "canceled"
+	PaymentStatusFailed    = "failed"
+)
+
+type Payment struct {
+	ID                int64      `json:"id"`
+	UserID            int        `json:"user_id"`
+	Provider          string     `json:"provider"`
+	ProviderPaymentID *string    `json:"provider_payment_id,omitempty"`
+	Plan              string     `json:"plan"`
+	Amount            float64    `json:"amount"`
+	Currency          string     `json:"currency"`
+	Description       string     `json:"description"`
+	Status            string     `json:"status"`
+	Error             *string    `json:"error,omitempty"`
+	PaidAt            *time.Time `json:"paid_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// PaymentFilter — фильтры админского списка платежей; пустые поля не применяются.
+type PaymentFilter struct {
+	UserID            *int
+	Status            string
+	Plan              string
+	ProviderPaymentID string
+	From              *time.Time
+	To                *time.Time
+}
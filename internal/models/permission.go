@@ -0,0 +1,29 @@
+package models
+
+// Права на контент. Маршрут требует право (middleware.RequirePermission), а не конкретную роль.
+const (
+	PermContentManage  = "content:manage"  // создавать и править свои статьи и документы
+	PermContentPublish = "content:publish" // публиковать статьи, открывать документы, править чужое
+)
+
+// Роли пользователей (users.role).
+const (
+	RoleUser   = "user"
+	RoleAuthor = "author" // преподаватель/автор: свои статьи и документы без права публикации
+	RoleAdmin  = "admin"
+)
+
+var rolePermissions = map[string][]string{
+	RoleAdmin:  {PermContentManage, PermContentPublish},
+	RoleAuthor: {PermContentManage},
+}
+
+// HasPermission — есть ли у роли право perm.
+func HasPermission(role, perm string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
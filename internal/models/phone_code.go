@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Назначение кода из SMS.
+const (
+	PhoneCodeVerify = "verify" // подтверждение номера в профиле
+	PhoneCodeReset  = "reset"  // сброс пароля по номеру
+)
+
+// PhoneCode — действующий код из SMS; номер запоминается на момент отправки.
+type PhoneCode struct {
+	UserID    int
+	Purpose   string
+	Phone     string
+	CodeHash  string
+	Attempts  int
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// PhoneCodeStatus — отправленный код: до какого времени он действует и когда можно запросить новый.
+type PhoneCodeStatus struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	ResendAt  time.Time `json:"resend_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Статусы фоновой переиндексации.
+const (
+	ReindexRunning = "running"
+	ReindexDone    = "done"
+	ReindexFailed  = "failed"
+)
+
+// ReindexStep — одна таблица: пересборка её индексов и обновление статистики планировщика.
+type ReindexStep struct {
+	Group      string `json:"group"` // search | taxonomy | downloads
+	Table      string `json:"table"`
+	Status     string `json:"status"` // pending | running | done | failed
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+}
+
+// ReindexJob — состояние последнего запуска переиндексации (хранится в памяти процесса).
+type ReindexJob struct {
+	ID         string        `json:"id"`
+	Status     string        `json:"status"`
+	StartedBy  int           `json:"started_by"`
+	Done       int           `json:"done"`
+	Total      int           `json:"total"`
+	Steps      []ReindexStep `json:"steps"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Результаты проверки при старте: ok — всё в порядке, warn — работает, но настроено не до конца,
+// fail — часть функций работать не будет.
+const (
+	SelfCheckOK   = "ok"
+	SelfCheckWarn = "warn"
+	SelfCheckFail = "fail"
+)
+
+// SelfCheckItem — одна проверка (db, migrations, uploads, smtp, env, jwt).
+type SelfCheckItem struct {
+	Name       string         `json:"name"`
+	Status     string         `json:"status"`
+	Message    string         `json:"message,omitempty"`
+	Details    map[string]any `json:"details,omitempty"`
+	DurationMs int64          `json:"duration_ms"`
+}
+
+// SelfCheckReport — отчёт последней самопроверки; Status — худший из статусов проверок.
+type SelfCheckReport struct {
+	Status     string          `json:"status"`
+	Checks     []SelfCheckItem `json:"checks"`
+	StartedAt  time.Time       `json:"started_at"`
+	DurationMs int64           `json:"duration_ms"`
+}
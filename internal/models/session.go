@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// UserSession — сессия пользователя (один вход): устройство и IP, с которых выполнен вход.
+type UserSession struct {
+	ID        int64      `json:"id"`
+	UserID    int        `json:"user_id"`
+	UserAgent string     `json:"user_agent"`
+	IP        string     `json:"ip"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	Current   bool       `json:"current"` // сессия, из которой сделан запрос
+}
+
+// SessionClient — откуда выполняется вход.
+type SessionClient struct {
+	UserAgent string
+	IP        string
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type SystemStats struct {
 	TotalUsers          int `json:"total_users"`
 	Admins              int `json:"admins"`
@@ -14,3 +16,50 @@ type SystemStats struct {
 	WithSubscriptionPct    int `json:"with_subscription_pct"`
 	WithoutSubscriptionPct int `json:"without_subscription_pct"`
 }
+
+// Виды записей журнала подписок (subscription_changes).
+const (
+	SubscriptionChangeGrant  = "grant"
+	SubscriptionChangeExtend = "extend"
+	SubscriptionChangeRevoke = "revoke"
+	SubscriptionChangeExpire = "expire" // истекла по сроку
+)
+
+// StatsPeriod — период графика: даты From..To включительно.
+type StatsPeriod struct {
+	From time.Time
+	To   time.Time
+}
+
+// DayCount — точка дневного ряда (день в формате YYYY-MM-DD).
+type DayCount struct {
+	Day   string `json:"day"`
+	Count int    `json:"count"`
+}
+
+// SubscriptionDayPoint — выдачи и отключения подписок за день.
+type SubscriptionDayPoint struct {
+	Day      string `json:"day"`
+	Granted  int    `json:"granted"`
+	Extended int    `json:"extended"`
+	Revoked  int    `json:"revoked"` // вручную или массовой операцией
+	Expired  int    `json:"expired"` // по сроку
+}
+
+// WeekCount — точка недельного ряда; Week — понедельник недели (YYYY-MM-DD).
+type WeekCount struct {
+	Week  string `json:"week"`
+	Count int    `json:"count"`
+}
+
+// AdminActivity — действия администратора за период.
+type AdminActivity struct {
+	UserID              int    `json:"user_id"`
+	Username            string `json:"username"`
+	FullName            string `json:"full_name"`
+	Uploads             int    `json:"uploads"`
+	ArticlesCreated     int    `json:"articles_created"`
+	ArticleEdits        int    `json:"article_edits"`
+	SubscriptionChanges int    `json:"subscription_changes"`
+	Total               int    `json:"total"`
+}
@@ -2,34 +2,109 @@ package models
 
 import "time"
 
+// SEOMeta — мета-теги посадочной страницы вкладки/раздела (title, description, OG-картинка).
+type SEOMeta struct {
+	MetaTitle       string `json:"meta_title" validate:"max=255"`
+	MetaDescription string `json:"meta_description" validate:"max=500"`
+	OGImageURL      string `json:"og_image_url" validate:"url,max=2048"`
+}
+
 type Tab struct {
-	ID        int       `json:"id"`
-	Slug      string    `json:"slug"`
-	Title     string    `json:"title"`
-	Position  int       `json:"position"`
-	IsActive  bool      `json:"is_active"`
+	ID       int    `json:"id"`
+	Slug     string `json:"slug" validate:"max=100"`
+	Title    string `json:"title" validate:"required,max=200"`
+	Position int    `json:"position"`
+	IsActive bool   `json:"is_active"`
+	SEOMeta
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// MaxSectionDepth — сколько уровней разделов допускается внутри вкладки.
+const MaxSectionDepth = 5
+
 type Section struct {
-	ID          int       `json:"id"`
-	TabID       int       `json:"tab_id"`
-	Slug        string    `json:"slug"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Position    int       `json:"position"`
-	IsActive    bool      `json:"is_active"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              int    `json:"id"`
+	TabID           int    `json:"tab_id" validate:"required,min=1"`
+	ParentSectionID *int   `json:"parent_section_id,omitempty" validate:"min=1"` // nil — раздел верхнего уровня
+	Slug            string `json:"slug" validate:"max=100"`
+	Title           string `json:"title" validate:"required,max=200"`
+	Description     string `json:"description" validate:"max=2000"`
+	Position        int    `json:"position"`
+	IsActive        bool   `json:"is_active"`
+	SEOMeta
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// SectionWithCount — узел дерева разделов. DocsCount — документы раздела вместе с подразделами,
+// OwnDocsCount — только самого раздела.
 type SectionWithCount struct {
-	Section   Section `json:"section"`
-	DocsCount int     `json:"docs_count"`
+	Section      Section            `json:"section"`
+	DocsCount    int                `json:"docs_count"`
+	OwnDocsCount int                `json:"own_docs_count"`
+	Children     []SectionWithCount `json:"children,omitempty"`
 }
 
 type TabTree struct {
 	Tab      Tab                `json:"tab"`
 	Sections []SectionWithCount `json:"sections"`
 }
+
+// TaxonomyCrumb — звено цепочки «вкладка → раздел» для хлебных крошек.
+type TaxonomyCrumb struct {
+	Kind      string `json:"kind"` // tab | section
+	ID        int    `json:"id"`
+	Slug      string `json:"slug"`
+	Title     string `json:"title"`
+	Path      string `json:"path"`
+	DocsCount int    `json:"docs_count"`
+	SEOMeta
+}
+
+type TaxonomyResolveResult struct {
+	Path   string          `json:"path"`
+	Crumbs []TaxonomyCrumb `json:"crumbs"`
+}
+
+// TaxonomySearchItem — результат автокомплита вкладок/разделов в админке (с родительской вкладкой).
+type TaxonomySearchItem struct {
+	Kind     string  `json:"kind"` // tab | section
+	ID       int     `json:"id"`
+	Slug     string  `json:"slug"`
+	Title    string  `json:"title"`
+	IsActive bool    `json:"is_active"`
+	TabID    *int    `json:"tab_id,omitempty"`
+	TabSlug  *string `json:"tab_slug,omitempty"`
+	TabTitle *string `json:"tab_title,omitempty"`
+	Path     string  `json:"path"`
+}
+
+// TabReorderRequest — новый порядок вкладок (drag-and-drop в админке).
+// Не перечисленные вкладки встают следом в прежнем порядке; позиции — 1..N без пропусков.
+type TabReorderRequest struct {
+	IDs []int `json:"ids" validate:"required,max=1000"`
+}
+
+// SectionReorderRequest — новый порядок разделов одной вкладки.
+type SectionReorderRequest struct {
+	TabID int   `json:"tab_id" validate:"required,min=1"`
+	IDs   []int `json:"ids" validate:"required,max=1000"`
+}
+
+// Что делать с содержимым удаляемого раздела.
+const (
+	SectionDeleteBlock    = "block"    // не удалять, если есть документы или подразделы
+	SectionDeleteDetach   = "detach"   // документы остаются без раздела
+	SectionDeleteReassign = "reassign" // документы переносятся в раздел move_to
+)
+
+// SectionDeleteResult — итог удаления раздела (при отказе в режиме block — что мешает удалению).
+type SectionDeleteResult struct {
+	Mode              string `json:"mode"`
+	Documents         int    `json:"documents"`   // документов в разделе и подразделах
+	Subsections       int    `json:"subsections"` // подразделов на всех уровнях
+	DocumentsMoved    int    `json:"documents_moved"`
+	DocumentsDetached int    `json:"documents_detached"`
+	MoveTo            *int   `json:"move_to,omitempty"`
+}
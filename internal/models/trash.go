@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Типы материалов в корзине.
+const (
+	TrashDocument = "document"
+	TrashNews     = "news"
+	TrashArticle  = "article"
+)
+
+// TrashItem — удалённый материал: до purge_at его можно восстановить, потом он удаляется окончательно.
+type TrashItem struct {
+	Type      string    `json:"type" example:"document"`
+	ID        int64     `json:"id" example:"42"`
+	Title     string    `json:"title" example:"Положение об аттестации"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
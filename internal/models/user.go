@@ -17,14 +17,27 @@ type User struct {
 	HasSubscription       bool       `json:"has_subscription"`
 	EmailSubscription     bool       `json:"email_subscription"`
 	EmailVerified         bool       `json:"email_verified"`
+	PhoneVerified         bool       `json:"phone_verified"`  // номер подтверждён кодом из SMS
+	LoginAlerts           bool       `json:"login_alerts"`    // письмо о входе с нового устройства
+	Locale                string     `json:"locale"`          // язык писем и ответов API: ru | en
+	EmailFrequency        string     `json:"email_frequency"` // EmailFrequency*: сразу или сводкой
+	AvatarKey             *string    `json:"-"`               // префикс файлов аватара в uploads; nil — identicon
 }
 
+// Как пользователь получает письма о новых материалах.
+const (
+	EmailFrequencyImmediate = "immediate" // групповая рассылка и письма о публикациях
+	EmailFrequencyDaily     = "daily"     // сводка раз в день
+	EmailFrequencyWeekly    = "weekly"    // сводка раз в неделю
+)
+
 type UpdateUserRequest struct {
-	FullName *string `json:"full_name,omitempty"`
-	Email    *string `json:"email,omitempty"`
-	Phone    *string `json:"phone,omitempty"`
-	Address  *string `json:"address,omitempty"`
-	Role     *string `json:"role,omitempty"`
+	FullName *string `json:"full_name,omitempty" validate:"notblank,max=200"`
+	Email    *string `json:"email,omitempty" validate:"notblank,email,max=255"`
+	Phone    *string `json:"phone,omitempty" validate:"phone"`
+	Address  *string `json:"address,omitempty" validate:"max=500"`
+	Role     *string `json:"role,omitempty" validate:"oneof=user author admin"`
+	Locale   *string `json:"locale,omitempty" example:"en"`
 }
 
 type UserProfileResponse struct {
@@ -42,4 +55,9 @@ type UserProfileResponse struct {
 	IsSubscriptionActive  bool       `json:"is_subscription_active"`
 	EmailSubscription     bool       `json:"email_subscription"`
 	EmailVerified         bool       `json:"email_verified"`
+	PhoneVerified         bool       `json:"phone_verified"`
+	LoginAlerts           bool       `json:"login_alerts"`
+	Locale                string     `json:"locale"`
+	EmailFrequency        string     `json:"email_frequency"`
+	AvatarURL             string     `json:"avatar_url" example:"/uploads/avatars/7_1760000000_a1b2c3d4_256.jpg"` // без аватара — identicon /api/avatars/{id}
 }
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Типы событий в ленте действий пользователя.
+const (
+	ActivityLogin         = "login"
+	ActivityDownload      = "download"
+	ActivityPayment       = "payment"
+	ActivitySubscription  = "subscription"
+	ActivityEmailVerified = "email_verified"
+	ActivityEmailChanged  = "email_changed"
+	ActivityImpersonation = "impersonation"
+)
+
+// ActivityTypes — допустимые значения фильтра type.
+var ActivityTypes = []string{
+	ActivityLogin, ActivityDownload, ActivityPayment,
+	ActivitySubscription, ActivityEmailVerified, ActivityEmailChanged, ActivityImpersonation,
+}
+
+// UserActivity — событие ленты. Details зависит от типа: ip и user_agent у входа, документ
+// у скачивания, сумма и статус у платежа, вид изменения и автор у подписки, адрес у смены email, администратор и причина у входа поддержки.
+type UserActivity struct {
+	Type    string         `json:"type"`
+	At      time.Time      `json:"at"`
+	RefID   *int64         `json:"ref_id,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
@@ -0,0 +1,53 @@
+package models
+
+// Действия массовой операции над пользователями.
+const (
+	UserBulkGrant   = "grant"    // выдать подписку на duration
+	UserBulkExtend  = "extend"   // продлить подписку на duration
+	UserBulkRevoke  = "revoke"   // отключить подписку
+	UserBulkSetRole = "set_role" // сменить роль на role
+	UserBulkDelete  = "delete"   // удалить пользователя
+)
+
+// Исход операции для отдельного пользователя.
+const (
+	UserBulkOK         = "ok"
+	UserBulkFailed     = "failed"
+	UserBulkSkipped    = "skipped"     // не применялось (например, к себе)
+	UserBulkRolledBack = "rolled_back" // выполнено, но откатилось из-за ошибки в режиме all_or_nothing
+)
+
+// UserBulkFilter — выборка пользователей, как в GET /api/admin/users.
+type UserBulkFilter struct {
+	Q               string  `json:"q,omitempty"`
+	Role            *string `json:"role,omitempty"`
+	HasSubscription *bool   `json:"has_subscription,omitempty"`
+}
+
+// UserBulkRequest — массовая операция: задаётся либо user_ids, либо filter.
+type UserBulkRequest struct {
+	Action   string          `json:"action"` // grant | extend | revoke | set_role | delete
+	UserIDs  []int           `json:"user_ids,omitempty"`
+	Filter   *UserBulkFilter `json:"filter,omitempty"`
+	Duration string          `json:"duration,omitempty"` // для grant/extend: monthly | halfyear | yearly | "30d" | "72h"
+	Role     string          `json:"role,omitempty"`     // для set_role: user | author | admin
+	// AllOrNothing — при первой ошибке откатить изменения для всех пользователей.
+	AllOrNothing bool `json:"all_or_nothing,omitempty"`
+}
+
+type UserBulkResult struct {
+	UserID int    `json:"user_id"`
+	Status string `json:"status"` // ok | failed | skipped | rolled_back
+	Error  string `json:"error,omitempty"`
+}
+
+// UserBulkReport — итог массовой операции с результатом по каждому пользователю.
+type UserBulkReport struct {
+	Action     string           `json:"action"`
+	Total      int              `json:"total"`
+	Succeeded  int              `json:"succeeded"`
+	Failed     int              `json:"failed"`
+	Skipped    int              `json:"skipped"`
+	RolledBack bool             `json:"rolled_back"`
+	Results    []UserBulkResult `json:"results"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// DigestRecipient — пользователь, которому пора отправить сводку новых материалов.
+type DigestRecipient struct {
+	UserID   int
+	Email    string
+	FullName string
+	Locale   string
+	// Конец периода прошлой сводки (или момент смены частоты писем); nil — сводок ещё не было
+	SentAt *time.Time
+}
+
+// DigestItem — материал в сводке: новость, статья или документ.
+type DigestItem struct {
+	ID    int
+	Title string
+	At    time.Time
+
+	// Только у документов раздела
+	TabSlug      string
+	TabTitle     string
+	SectionSlug  string
+	SectionTitle string
+}
+
+// UserDigest — новые материалы за период для одного пользователя. В списках не больше
+// нескольких позиций, *Total — сколько всего.
+type UserDigest struct {
+	From time.Time
+	To   time.Time
+
+	News      []DigestItem
+	Articles  []DigestItem
+	Documents []DigestItem
+
+	NewsTotal      int
+	ArticlesTotal  int
+	DocumentsTotal int
+}
+
+// Empty — за период ничего не появилось.
+func (d *UserDigest) Empty() bool {
+	return d.NewsTotal == 0 && d.ArticlesTotal == 0 && d.DocumentsTotal == 0
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Статусы выгрузки персональных данных.
+const (
+	UserExportPending = "pending"
+	UserExportReady   = "ready"
+	UserExportFailed  = "failed"
+	UserExportExpired = "expired"
+)
+
+// UserExport — запрос выгрузки всех данных пользователя; готовый ZIP скачивается по подписанной ссылке из письма.
+type UserExport struct {
+	ID          int64      `json:"id"`
+	UserID      int        `json:"user_id"`
+	RequestedBy *int       `json:"requested_by,omitempty"`
+	Status      string     `json:"status"`
+	FilePath    string     `json:"-"`
+	SizeBytes   *int64     `json:"size_bytes,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// UserDownload — скачивание документа пользователем.
+type UserDownload struct {
+	DocumentID   int       `json:"document_id"`
+	Title        string    `json:"title"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+}
+
+// UserEmailSubscription — состояние почтовых рассылок пользователя.
+type UserEmailSubscription struct {
+	Email             string `json:"email"`
+	EmailSubscription bool   `json:"email_subscription"`
+	EmailVerified     bool   `json:"email_verified"`
+	Locale            string `json:"locale"`
+	EmailFrequency    string `json:"email_frequency"`
+}
+
+// UserExportBundle — всё, что хранится о пользователе; в ZIP каждый раздел — отдельный JSON-файл.
+type UserExportBundle struct {
+	GeneratedAt       time.Time             `json:"generated_at"`
+	Profile           User                  `json:"profile"`
+	EmailSubscription UserEmailSubscription `json:"email_subscription"`
+	Payments          []Payment             `json:"payments"`
+	Invoices          []Invoice             `json:"invoices"`
+	Downloads         []UserDownload        `json:"downloads"`
+}
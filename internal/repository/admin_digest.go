@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type AdminDigestRepository struct {
+	db DB
+}
+
+func NewAdminDigestRepository(db DB) *AdminDigestRepository {
+	return &AdminDigestRepository{db: db}
+}
+
+// FillCounts — заполняет в сводке счётчики из БД: пользователи, платежи, истекающие подписки.
+func (r *AdminDigestRepository) FillCounts(ctx context.Context, d *models.AdminDigest, expiringWithin time.Duration) error {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE created_at >= $1 AND created_at < $2),
+			(SELECT COUNT(*) FROM payments WHERE status = 'succeeded' AND COALESCE(paid_at, updated_at) >= $1 AND COALESCE(paid_at, updated_at) < $2),
+			(SELECT COALESCE(SUM(amount), 0)::float8 FROM payments WHERE status = 'succeeded' AND COALESCE(paid_at, updated_at) >= $1 AND COALESCE(paid_at, updated_at) < $2),
+			(SELECT COUNT(*) FROM payments WHERE status IN ('canceled', 'failed') AND updated_at >= $1 AND updated_at < $2),
+			(SELECT COUNT(*) FROM users
+			  WHERE has_subscription = true
+			    AND subscription_expires_at IS NOT NULL
+			    AND subscription_expires_at > NOW()
+			    AND subscription_expires_at <= NOW() + $3 * interval '1 second')
+	`
+	if err := r.db.QueryRow(ctx, q, d.From, d.To, int64(expiringWithin.Seconds())).Scan(
+		&d.NewUsers, &d.PaymentsSucceeded, &d.Revenue, &d.PaymentsFailed, &d.ExpiringSubscriptions,
+	); err != nil {
+		log.Error("admin digest repo: counts failed", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ReserveWeek — резервирует плановую отправку за неделю weekKey.
+// Возвращает false, если сводка за эту неделю уже была отправлена (в т.ч. другим инстансом).
+func (r *AdminDigestRepository) ReserveWeek(ctx context.Context, weekKey string, from, to time.Time) (int64, bool, error) {
+	log := logger.WithCtx(ctx)
+
+	var id int64
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO admin_digests (trigger, period_from, period_to, week_key)
+		VALUES ('schedule', $1, $2, $3)
+		ON CONFLICT (week_key) WHERE trigger = 'schedule' DO NOTHING
+		RETURNING id`, from, to, weekKey,
+	).Scan(&id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, false, nil
+		}
+		log.Error("admin digest repo: reserve week failed", zap.Error(err), zap.String("week", weekKey))
+		return 0, false, err
+	}
+	return id, true, nil
+}
+
+// Save — сохраняет ручную сводку (или дописывает плановую, если id != 0).
+func (r *AdminDigestRepository) Save(ctx context.Context, id int64, trigger string, d *models.AdminDigest, recipients []string) error {
+	log := logger.WithCtx(ctx)
+
+	payload, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	if id != 0 {
+		_, err = r.db.Exec(ctx,
+			`UPDATE admin_digests SET recipients=$2, payload=$3 WHERE id=$1`, id, recipients, payload)
+	} else {
+		_, err = r.db.Exec(ctx,
+			`INSERT INTO admin_digests (trigger, period_from, period_to, recipients, payload) VALUES ($1,$2,$3,$4,$5)`,
+			trigger, d.From, d.To, recipients, payload)
+	}
+	if err != nil {
+		log.Error("admin digest repo: save failed", zap.Error(err), zap.String("trigger", trigger))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type AnnouncementRepository struct {
+	db DB
+}
+
+func NewAnnouncementRepository(db DB) *AnnouncementRepository {
+	return &AnnouncementRepository{db: db}
+}
+
+const announcementColumns = `id, message, link_url, link_text, severity, dismissible, is_enabled,
+	starts_at, ends_at, created_by, created_at, updated_at`
+
+func scanAnnouncement(row pgx.Row) (*models.Announcement, error) {
+	var a models.Announcement
+	if err := row.Scan(&a.ID, &a.Message, &a.LinkURL, &a.LinkText, &a.Severity, &a.Dismissible, &a.IsEnabled,
+		&a.StartsAt, &a.EndsAt, &a.CreatedBy, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AnnouncementRepository) Create(ctx context.Context, a *models.Announcement) (*models.Announcement, error) {
+	log := logger.WithCtx(ctx)
+
+	out, err := scanAnnouncement(r.db.QueryRow(ctx, `
+		INSERT INTO announcements (message, link_url, link_text, severity, dismissible, is_enabled, starts_at, ends_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+announcementColumns,
+		a.Message, a.LinkURL, a.LinkText, a.Severity, a.Dismissible, a.IsEnabled, a.StartsAt, a.EndsAt, a.CreatedBy,
+	))
+	if err != nil {
+		log.Error("announcement repo: create failed", zap.Error(err))
+		return nil, err
+	}
+	log.Info("announcement repo: created", zap.Int("id", out.ID))
+	return out, nil
+}
+
+// Update — заменяет баннер целиком; автор не меняется.
+func (r *AnnouncementRepository) Update(ctx context.Context, a *models.Announcement) (*models.Announcement, error) {
+	out, err := scanAnnouncement(r.db.QueryRow(ctx, `
+		UPDATE announcements
+		SET message = $2, link_url = $3, link_text = $4, severity = $5, dismissible = $6, is_enabled = $7,
+		    starts_at = $8, ends_at = $9, updated_at = now()
+		WHERE id = $1
+		RETURNING `+announcementColumns,
+		a.ID, a.Message, a.LinkURL, a.LinkText, a.Severity, a.Dismissible, a.IsEnabled, a.StartsAt, a.EndsAt,
+	))
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			logger.WithCtx(ctx).Error("announcement repo: update failed", zap.Error(err), zap.Int("id", a.ID))
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *AnnouncementRepository) Delete(ctx context.Context, id int) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM announcements WHERE id = $1`, id)
+	if err != nil {
+		logger.WithCtx(ctx).Error("announcement repo: delete failed", zap.Error(err), zap.Int("id", id))
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// List — все баннеры для админки, новые сверху, и общее количество.
+func (r *AnnouncementRepository) List(ctx context.Context, limit, offset int) ([]models.Announcement, int, error) {
+	log := logger.WithCtx(ctx)
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM announcements`).Scan(&total); err != nil {
+		log.Error("announcement repo: count failed", zap.Error(err))
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT `+announcementColumns+` FROM announcements
+		ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		log.Error("announcement repo: list failed", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]models.Announcement, 0, limit)
+	for rows.Next() {
+		a, err := scanAnnouncement(rows)
+		if err != nil {
+			log.Error("announcement repo: scan failed", zap.Error(err))
+			return nil, 0, err
+		}
+		out = append(out, *a)
+	}
+	return out, total, rows.Err()
+}
+
+// Active — баннеры, показываемые в момент now: сначала важные, затем более свежие.
+func (r *AnnouncementRepository) Active(ctx context.Context, now time.Time) ([]models.Announcement, error) {
+	log := logger.WithCtx(ctx)
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+announcementColumns+`
+		FROM announcements
+		WHERE is_enabled
+		  AND (starts_at IS NULL OR starts_at <= $1)
+		  AND (ends_at IS NULL OR ends_at > $1)
+		ORDER BY CASE severity WHEN 'critical' THEN 0 WHEN 'warning' THEN 1 WHEN 'info' THEN 2 ELSE 3 END,
+		         COALESCE(starts_at, created_at) DESC, id DESC`, now)
+	if err != nil {
+		log.Error("announcement repo: active failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.Announcement{}
+	for rows.Next() {
+		a, err := scanAnnouncement(rows)
+		if err != nil {
+			log.Error("announcement repo: scan failed", zap.Error(err))
+			return nil, err
+		}
+		out = append(out, *a)
+	}
+	return out, rows.Err()
+}
@@ -8,63 +8,98 @@ import (
 
 	"edutalks/internal/logger"
 	"edutalks/internal/models"
+	"edutalks/internal/reqctx"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
+// ArticleIntents — побочные эффекты по сохранённой статье (id уже известен); пишутся
+// в side_effect_outbox в той же транзакции. nil или пустой список — побочных эффектов нет.
+type ArticleIntents func(a *models.Article) ([]models.OutboxIntent, error)
+
 type ArticleRepo interface {
-	Create(ctx context.Context, a *models.Article) (*models.Article, error)
-	GetAll(ctx context.Context, limit, offset int, tag string, onlyPublished bool) ([]*models.Article, error)
+	Create(ctx context.Context, a *models.Article, intents ArticleIntents) (*models.Article, error)
+	GetAll(ctx context.Context, limit, offset int, tag, status string) ([]*models.Article, error)
+	GetByAuthor(ctx context.Context, authorID int64, limit, offset int, status string) ([]*models.Article, error)
 	GetByID(ctx context.Context, id int64) (*models.Article, error)
-	Update(ctx context.Context, a *models.Article) error
+	Update(ctx context.Context, a *models.Article, intents ArticleIntents) error
 	Delete(ctx context.Context, id int64) error
 	Exists(ctx context.Context, id int64) (bool, error)
-	UpdatePublish(ctx context.Context, id int64, publish bool) error
+	UpdatePublish(ctx context.Context, id int64, publish bool, intents ArticleIntents) error
+	PublishDue(ctx context.Context, intents ArticleIntents) ([]*models.Article, error)
 }
 
-type articleRepo struct{ db *pgxpool.Pool }
+type articleRepo struct {
+	db     DB
+	outbox *OutboxRepository
+}
 
-func NewArticleRepo(db *pgxpool.Pool) ArticleRepo { return &articleRepo{db: db} }
+func NewArticleRepo(db DB, outbox *OutboxRepository) ArticleRepo {
+	return &articleRepo{db: db, outbox: outbox}
+}
 
-func (r *articleRepo) Create(ctx context.Context, a *models.Article) (*models.Article, error) {
+// addIntents — записывает побочные эффекты статьи в открытой транзакции.
+func (r *articleRepo) addIntents(ctx context.Context, tx pgx.Tx, a *models.Article, intents ArticleIntents) error {
+	if intents == nil {
+		return nil
+	}
+	list, err := intents(a)
+	if err != nil || len(list) == 0 {
+		return err
+	}
+	return r.outbox.AddTx(ctx, tx, list...)
+}
+
+func (r *articleRepo) Create(ctx context.Context, a *models.Article, intents ArticleIntents) (*models.Article, error) {
 	log := logger.WithCtx(ctx)
 
 	tagsJSON, _ := json.Marshal(a.Tags)
 	const q = `
-		INSERT INTO articles (author_id, title, summary, body_html, tags, is_published, published_at)
-		VALUES ($1,$2,$3,$4,$5::jsonb,$6, CASE WHEN $6 THEN NOW() ELSE NULL END)
-		RETURNING id, author_id, title, summary, body_html, is_published, published_at, created_at, updated_at, tags
+		INSERT INTO articles (author_id, title, summary, body_html, tags, is_published, published_at, publish_at, cover_image_url)
+		VALUES ($1,$2,$3,$4,$5::jsonb,$6, CASE WHEN $6 THEN NOW() ELSE NULL END, $7, $8)
+		RETURNING id, author_id, title, summary, body_html, is_published, published_at, publish_at, created_at, updated_at, tags, content_updated_at, view_count, cover_image_url
 	`
 
 	var out models.Article
 	var tagsRaw []byte
-	err := r.db.QueryRow(ctx, q,
-		a.AuthorID,
-		a.Title,
-		a.Summary,
-		a.BodyHTML,
-		tagsJSON,
-		a.IsPublished,
-	).Scan(
-		&out.ID,
-		&out.AuthorID,
-		&out.Title,
-		&out.Summary,
-		&out.BodyHTML,
-		&out.IsPublished,
-		&out.PublishedAt,
-		&out.CreatedAt,
-		&out.UpdatedAt,
-		&tagsRaw,
-	)
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, q,
+			a.AuthorID,
+			a.Title,
+			a.Summary,
+			a.BodyHTML,
+			tagsJSON,
+			a.IsPublished,
+			a.PublishAt,
+			a.CoverImageURL,
+		).Scan(
+			&out.ID,
+			&out.AuthorID,
+			&out.Title,
+			&out.Summary,
+			&out.BodyHTML,
+			&out.IsPublished,
+			&out.PublishedAt,
+			&out.PublishAt,
+			&out.CreatedAt,
+			&out.UpdatedAt,
+			&tagsRaw,
+			&out.ContentUpdatedAt,
+			&out.ViewCount,
+			&out.CoverImageURL,
+		); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(tagsRaw, &out.Tags); err != nil {
+			log.Warn("article repo: failed to unmarshal tags after create", zap.Error(err))
+		}
+		return r.addIntents(ctx, tx, &out, intents)
+	})
 	if err != nil {
 		log.Error("article repo: create failed", zap.Error(err))
 		return nil, err
 	}
-	if err := json.Unmarshal(tagsRaw, &out.Tags); err != nil {
-		log.Warn("article repo: failed to unmarshal tags after create", zap.Error(err))
-	}
 	log.Info("article repo: created",
 		zap.Int64("id", out.ID),
 		zap.Bool("published", out.IsPublished),
@@ -72,21 +107,35 @@ func (r *articleRepo) Create(ctx context.Context, a *models.Article) (*models.Ar
 	return &out, nil
 }
 
-func (r *articleRepo) GetAll(ctx context.Context, limit, offset int, tag string, onlyPublished bool) ([]*models.Article, error) {
+// GetAll — список статей; status — models.ArticleStatus* или пусто (все).
+func (r *articleRepo) GetAll(ctx context.Context, limit, offset int, tag, status string) ([]*models.Article, error) {
+	return r.list(ctx, limit, offset, tag, status, nil)
+}
+
+// GetByAuthor — статьи автора; status — как в GetAll.
+func (r *articleRepo) GetByAuthor(ctx context.Context, authorID int64, limit, offset int, status string) ([]*models.Article, error) {
+	return r.list(ctx, limit, offset, "", status, &authorID)
+}
+
+func (r *articleRepo) list(ctx context.Context, limit, offset int, tag, status string, authorID *int64) ([]*models.Article, error) {
 	log := logger.WithCtx(ctx)
 
-	const qBase = `
-		SELECT id, author_id, title, summary, body_html, is_published, published_at, created_at, updated_at, tags
+	qBase := `
+		SELECT id, author_id, title, summary, body_html, is_published, published_at, publish_at, created_at, updated_at, tags, content_updated_at, view_count, cover_image_url,
+		       ` + reviewStateExpr("is_published") + `
 		FROM articles
 	`
-	where := []string{}
+	where := []string{"deleted_at IS NULL"}
 	args := []any{}
 	i := 1
 
-	if onlyPublished {
-		where = append(where, fmt.Sprintf("is_published = $%d", i))
-		args = append(args, true)
-		i++
+	switch status {
+	case models.ArticleStatusPublished:
+		where = append(where, "is_published")
+	case models.ArticleStatusDraft:
+		where = append(where, "NOT is_published AND publish_at IS NULL")
+	case models.ArticleStatusScheduled:
+		where = append(where, "NOT is_published AND publish_at IS NOT NULL")
 	}
 	if tag != "" {
 		where = append(where, fmt.Sprintf(`
@@ -98,18 +147,24 @@ func (r *articleRepo) GetAll(ctx context.Context, limit, offset int, tag string,
 		args = append(args, tag)
 		i++
 	}
+	if authorID != nil {
+		where = append(where, fmt.Sprintf("author_id = $%d", i))
+		args = append(args, *authorID)
+		i++
+	}
 
-	sql := qBase
-	if len(where) > 0 {
-		sql += " WHERE " + strings.Join(where, " AND ")
+	sql := qBase + " WHERE " + strings.Join(where, " AND ")
+	if status == models.ArticleStatusScheduled {
+		sql += fmt.Sprintf(" ORDER BY publish_at ASC LIMIT $%d OFFSET $%d", i, i+1)
+	} else {
+		sql += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", i, i+1)
 	}
-	sql += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", i, i+1)
 	args = append(args, limit, offset)
 
 	rows, err := r.db.Query(ctx, sql, args...)
 	if err != nil {
 		log.Error("article repo: get all query failed", zap.Error(err),
-			zap.Int("limit", limit), zap.Int("offset", offset), zap.String("tag", tag), zap.Bool("only_published", onlyPublished))
+			zap.Int("limit", limit), zap.Int("offset", offset), zap.String("tag", tag), zap.String("status", status))
 		return nil, err
 	}
 	defer rows.Close()
@@ -120,7 +175,8 @@ func (r *articleRepo) GetAll(ctx context.Context, limit, offset int, tag string,
 		var tagsRaw []byte
 		if err := rows.Scan(
 			&a.ID, &a.AuthorID, &a.Title, &a.Summary, &a.BodyHTML,
-			&a.IsPublished, &a.PublishedAt, &a.CreatedAt, &a.UpdatedAt, &tagsRaw,
+			&a.IsPublished, &a.PublishedAt, &a.PublishAt, &a.CreatedAt, &a.UpdatedAt, &tagsRaw, &a.ContentUpdatedAt, &a.ViewCount, &a.CoverImageURL,
+			&a.ReviewState,
 		); err != nil {
 			log.Error("article repo: scan in get all failed", zap.Error(err))
 			return nil, err
@@ -140,7 +196,8 @@ func (r *articleRepo) GetAll(ctx context.Context, limit, offset int, tag string,
 		zap.Int("limit", limit),
 		zap.Int("offset", offset),
 		zap.String("tag", tag),
-		zap.Bool("only_published", onlyPublished),
+		zap.String("status", status),
+		zap.Any("author_id", authorID),
 	)
 	return list, nil
 }
@@ -148,15 +205,17 @@ func (r *articleRepo) GetAll(ctx context.Context, limit, offset int, tag string,
 func (r *articleRepo) GetByID(ctx context.Context, id int64) (*models.Article, error) {
 	log := logger.WithCtx(ctx)
 
-	const q = `
-		SELECT id, author_id, title, summary, body_html, is_published, published_at, created_at, updated_at, tags
-		FROM articles WHERE id=$1
+	q := `
+		SELECT id, author_id, title, summary, body_html, is_published, published_at, publish_at, created_at, updated_at, tags, content_updated_at, view_count, cover_image_url,
+		       ` + reviewStateExpr("is_published") + `
+		FROM articles WHERE id=$1 AND deleted_at IS NULL
 	`
 	var a models.Article
 	var tagsRaw []byte
 	if err := r.db.QueryRow(ctx, q, id).Scan(
 		&a.ID, &a.AuthorID, &a.Title, &a.Summary, &a.BodyHTML,
-		&a.IsPublished, &a.PublishedAt, &a.CreatedAt, &a.UpdatedAt, &tagsRaw,
+		&a.IsPublished, &a.PublishedAt, &a.PublishAt, &a.CreatedAt, &a.UpdatedAt, &tagsRaw, &a.ContentUpdatedAt, &a.ViewCount, &a.CoverImageURL,
+		&a.ReviewState,
 	); err != nil {
 		log.Warn("article repo: get by id failed", zap.Int64("id", id), zap.Error(err))
 		return nil, err
@@ -168,9 +227,17 @@ func (r *articleRepo) GetByID(ctx context.Context, id int64) (*models.Article, e
 	return &a, nil
 }
 
-func (r *articleRepo) Update(ctx context.Context, a *models.Article) error {
+// Update — сохраняет правки; прежняя версия уходит в article_revisions, черновик удаляется.
+// editor_id берётся из контекста запроса, content_updated_at — из a (выставляет сервис).
+func (r *articleRepo) Update(ctx context.Context, a *models.Article, intents ArticleIntents) error {
 	log := logger.WithCtx(ctx)
 
+	var editorID *int64
+	if uid, ok := reqctx.GetUserID(ctx); ok && uid != 0 {
+		id := int64(uid)
+		editorID = &id
+	}
+
 	tagsJSON, _ := json.Marshal(a.Tags)
 	const q = `
 		UPDATE articles
@@ -180,10 +247,28 @@ func (r *articleRepo) Update(ctx context.Context, a *models.Article) error {
 		    tags=$4::jsonb,
 		    is_published=$5,
 		    published_at = CASE WHEN $5 THEN COALESCE(published_at, NOW()) ELSE NULL END,
+		    publish_at=$7,
+		    content_updated_at=$8,
+		    cover_image_url=$9,
 		    updated_at=NOW()
-		WHERE id=$6
+		WHERE id=$6 AND deleted_at IS NULL
 	`
-	_, err := r.db.Exec(ctx, q, a.Title, a.Summary, a.BodyHTML, tagsJSON, a.IsPublished, a.ID)
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO article_revisions (article_id, title, summary, body_html, tags, editor_id)
+			SELECT id, title, summary, body_html, tags, $2 FROM articles WHERE id = $1 AND deleted_at IS NULL`,
+			a.ID, editorID,
+		); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, q, a.Title, a.Summary, a.BodyHTML, tagsJSON, a.IsPublished, a.ID, a.PublishAt, a.ContentUpdatedAt, a.CoverImageURL); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM article_drafts WHERE article_id = $1`, a.ID); err != nil {
+			return err
+		}
+		return r.addIntents(ctx, tx, a, intents)
+	})
 	if err != nil {
 		log.Error("article repo: update failed", zap.Error(err), zap.Int64("id", a.ID))
 		return err
@@ -192,22 +277,23 @@ func (r *articleRepo) Update(ctx context.Context, a *models.Article) error {
 	return nil
 }
 
+// Delete — переместить статью в корзину; окончательно удаляет TrashRepository.
 func (r *articleRepo) Delete(ctx context.Context, id int64) error {
 	log := logger.WithCtx(ctx)
 
-	_, err := r.db.Exec(ctx, "DELETE FROM articles WHERE id=$1", id)
+	_, err := r.db.Exec(ctx, "UPDATE articles SET deleted_at=NOW() WHERE id=$1 AND deleted_at IS NULL", id)
 	if err != nil {
 		log.Error("article repo: delete failed", zap.Error(err), zap.Int64("id", id))
 		return err
 	}
-	log.Info("article repo: deleted", zap.Int64("id", id))
+	log.Info("article repo: moved to trash", zap.Int64("id", id))
 	return nil
 }
 
 func (r *articleRepo) Exists(ctx context.Context, id int64) (bool, error) {
 	log := logger.WithCtx(ctx)
 
-	const q = `SELECT EXISTS(SELECT 1 FROM articles WHERE id = $1)`
+	const q = `SELECT EXISTS(SELECT 1 FROM articles WHERE id = $1 AND deleted_at IS NULL)`
 	var ok bool
 	if err := r.db.QueryRow(ctx, q, id).Scan(&ok); err != nil {
 		log.Error("article repo: exists query failed", zap.Error(err), zap.Int64("id", id))
@@ -217,17 +303,26 @@ func (r *articleRepo) Exists(ctx context.Context, id int64) (bool, error) {
 	return ok, nil
 }
 
-func (r *articleRepo) UpdatePublish(ctx context.Context, id int64, publish bool) error {
+// UpdatePublish — меняет статус публикации; intents получает статью уже в новом состоянии.
+func (r *articleRepo) UpdatePublish(ctx context.Context, id int64, publish bool, intents ArticleIntents) error {
 	log := logger.WithCtx(ctx)
 
 	const q = `
 		UPDATE articles
 		SET is_published = $2,
 		    published_at = CASE WHEN $2 THEN COALESCE(published_at, NOW()) ELSE NULL END,
+		    publish_at = NULL, -- ручное решение отменяет расписание
 		    updated_at = NOW()
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, author_id, title, is_published
 	`
-	_, err := r.db.Exec(ctx, q, id, publish)
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		var a models.Article
+		if err := tx.QueryRow(ctx, q, id, publish).Scan(&a.ID, &a.AuthorID, &a.Title, &a.IsPublished); err != nil {
+			return err
+		}
+		return r.addIntents(ctx, tx, &a, intents)
+	})
 	if err != nil {
 		log.Error("article repo: update publish failed", zap.Error(err), zap.Int64("id", id), zap.Bool("publish", publish))
 		return err
@@ -235,3 +330,63 @@ func (r *articleRepo) UpdatePublish(ctx context.Context, id int64, publish bool)
 	log.Info("article repo: publish updated", zap.Int64("id", id), zap.Bool("publish", publish))
 	return nil
 }
+
+// PublishDue — публикует статьи, у которых наступило publish_at, и возвращает их; побочные
+// эффекты каждой публикации пишутся в той же транзакции. Один UPDATE: при нескольких
+// инстансах каждая статья публикуется ровно одним из них.
+func (r *articleRepo) PublishDue(ctx context.Context, intents ArticleIntents) ([]*models.Article, error) {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+		UPDATE articles
+		SET is_published = true,
+		    published_at = NOW(),
+		    publish_at = NULL,
+		    updated_at = NOW()
+		WHERE NOT is_published AND publish_at IS NOT NULL AND publish_at <= NOW() AND deleted_at IS NULL
+		RETURNING id, author_id, title, summary, body_html, is_published, published_at, publish_at, created_at, updated_at, tags, content_updated_at, view_count, cover_image_url
+	`
+	var list []*models.Article
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, q)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var a models.Article
+			var tagsRaw []byte
+			if err := rows.Scan(
+				&a.ID, &a.AuthorID, &a.Title, &a.Summary, &a.BodyHTML,
+				&a.IsPublished, &a.PublishedAt, &a.PublishAt, &a.CreatedAt, &a.UpdatedAt, &tagsRaw, &a.ContentUpdatedAt, &a.ViewCount, &a.CoverImageURL,
+			); err != nil {
+				return err
+			}
+			if err := json.Unmarshal(tagsRaw, &a.Tags); err != nil {
+				log.Warn("article repo: failed to unmarshal tags in publish due", zap.Error(err), zap.Int64("id", a.ID))
+			}
+			list = append(list, &a)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		for _, a := range list {
+			if err := r.addIntents(ctx, tx, a, intents); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("article repo: publish due failed", zap.Error(err))
+		return nil, err
+	}
+
+	if len(list) > 0 {
+		log.Info("article repo: scheduled articles published", zap.Int("count", len(list)))
+	}
+	return list, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type ArticleMediaRepository struct {
+	db DB
+}
+
+func NewArticleMediaRepository(db DB) *ArticleMediaRepository {
+	return &ArticleMediaRepository{db: db}
+}
+
+// articleMediaSelect — файл медиатеки и статьи, которые на него ссылаются: обложка,
+// текст статьи или несохранённый черновик.
+const articleMediaSelect = `
+	SELECT m.id, m.url, m.path, m.original_name, m.content_type, m.size_bytes, m.uploaded_by, m.created_at,
+	       COALESCE(u.ids, '{}')
+	FROM article_media m
+	LEFT JOIN LATERAL (
+		SELECT array_agg(DISTINCT x.id ORDER BY x.id) AS ids
+		FROM (
+			SELECT a.id FROM articles a
+			WHERE a.cover_image_url = m.url OR strpos(a.body_html, m.url) > 0
+			-- статья в корзине держит медиа: после восстановления картинки должны быть на месте
+			UNION
+			SELECT d.article_id FROM article_drafts d
+			WHERE strpos(d.body_html, m.url) > 0
+		) x
+	) u ON true`
+
+func scanArticleMedia(row pgx.Row) (*models.ArticleMedia, error) {
+	var m models.ArticleMedia
+	if err := row.Scan(&m.ID, &m.URL, &m.Path, &m.OriginalName, &m.ContentType, &m.SizeBytes, &m.UploadedBy, &m.CreatedAt,
+		&m.UsedIn); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *ArticleMediaRepository) Create(ctx context.Context, m *models.ArticleMedia) (*models.ArticleMedia, error) {
+	log := logger.WithCtx(ctx)
+
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO article_media (url, path, original_name, content_type, size_bytes, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`,
+		m.URL, m.Path, m.OriginalName, m.ContentType, m.SizeBytes, m.UploadedBy,
+	).Scan(&m.ID, &m.CreatedAt)
+	if err != nil {
+		log.Error("article media repo: create failed", zap.Error(err), zap.String("url", m.URL))
+		return nil, err
+	}
+	m.UsedIn = []int64{}
+	log.Info("article media repo: created", zap.Int64("id", m.ID), zap.String("url", m.URL))
+	return m, nil
+}
+
+func (r *ArticleMediaRepository) GetByID(ctx context.Context, id int64) (*models.ArticleMedia, error) {
+	m, err := scanArticleMedia(r.db.QueryRow(ctx, articleMediaSelect+` WHERE m.id = $1`, id))
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			logger.WithCtx(ctx).Error("article media repo: get failed", zap.Error(err), zap.Int64("id", id))
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// List — файлы медиатеки, новые сверху, и общее количество; unusedOnly — только те,
+// на которые не ссылается ни одна статья.
+func (r *ArticleMediaRepository) List(ctx context.Context, unusedOnly bool, limit, offset int) ([]models.ArticleMedia, int, error) {
+	log := logger.WithCtx(ctx)
+
+	const filter = ` WHERE NOT $1 OR u.ids IS NULL`
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM (`+articleMediaSelect+filter+`) t`, unusedOnly).Scan(&total); err != nil {
+		log.Error("article media repo: count failed", zap.Error(err))
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(ctx, articleMediaSelect+filter+`
+		ORDER BY m.created_at DESC, m.id DESC LIMIT $2 OFFSET $3`, unusedOnly, limit, offset)
+	if err != nil {
+		log.Error("article media repo: list failed", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]models.ArticleMedia, 0, limit)
+	for rows.Next() {
+		m, err := scanArticleMedia(rows)
+		if err != nil {
+			log.Error("article media repo: scan failed", zap.Error(err))
+			return nil, 0, err
+		}
+		out = append(out, *m)
+	}
+	return out, total, rows.Err()
+}
+
+func (r *ArticleMediaRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM article_media WHERE id = $1`, id)
+	if err != nil {
+		logger.WithCtx(ctx).Error("article media repo: delete failed", zap.Error(err), zap.Int64("id", id))
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ArticleRevisionRepository — история версий и черновики статей.
+// Сами снимки пишет articleRepo.Update в одной транзакции с изменением.
+type ArticleRevisionRepository struct {
+	db DB
+}
+
+func NewArticleRevisionRepository(db DB) *ArticleRevisionRepository {
+	return &ArticleRevisionRepository{db: db}
+}
+
+// List — ревизии статьи, новые сверху (без body_html).
+func (r *ArticleRevisionRepository) List(ctx context.Context, articleID int64) ([]models.ArticleRevision, error) {
+	log := logger.WithCtx(ctx)
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, article_id, title, summary, tags, editor_id, created_at
+		FROM article_revisions
+		WHERE article_id = $1
+		ORDER BY id DESC`, articleID)
+	if err != nil {
+		log.Error("article revisions repo: list failed", zap.Error(err), zap.Int64("article_id", articleID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.ArticleRevision{}
+	for rows.Next() {
+		var rev models.ArticleRevision
+		var tagsRaw []byte
+		if err := rows.Scan(&rev.ID, &rev.ArticleID, &rev.Title, &rev.Summary, &tagsRaw, &rev.EditorID, &rev.CreatedAt); err != nil {
+			log.Error("article revisions repo: scan failed", zap.Error(err))
+			return nil, err
+		}
+		_ = json.Unmarshal(tagsRaw, &rev.Tags)
+		out = append(out, rev)
+	}
+	return out, rows.Err()
+}
+
+// Get — ревизия статьи целиком; pgx.ErrNoRows, если нет или принадлежит другой статье.
+func (r *ArticleRevisionRepository) Get(ctx context.Context, articleID, revisionID int64) (*models.ArticleRevision, error) {
+	var rev models.ArticleRevision
+	var tagsRaw []byte
+	err := r.db.QueryRow(ctx, `
+		SELECT id, article_id, title, summary, body_html, tags, editor_id, created_at
+		FROM article_revisions
+		WHERE article_id = $1 AND id = $2`, articleID, revisionID,
+	).Scan(&rev.ID, &rev.ArticleID, &rev.Title, &rev.Summary, &rev.BodyHTML, &tagsRaw, &rev.EditorID, &rev.CreatedAt)
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			logger.WithCtx(ctx).Error("article revisions repo: get failed", zap.Error(err),
+				zap.Int64("article_id", articleID), zap.Int64("revision_id", revisionID))
+		}
+		return nil, err
+	}
+	_ = json.Unmarshal(tagsRaw, &rev.Tags)
+	return &rev, nil
+}
+
+// SaveDraft — upsert черновика статьи.
+func (r *ArticleRevisionRepository) SaveDraft(ctx context.Context, d *models.ArticleDraft) (*models.ArticleDraft, error) {
+	tagsJSON, _ := json.Marshal(d.Tags)
+
+	out := *d
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO article_drafts (article_id, title, summary, body_html, tags, editor_id, updated_at)
+		VALUES ($1, $2, $3, $4, $5::jsonb, $6, now())
+		ON CONFLICT (article_id) DO UPDATE
+		SET title = EXCLUDED.title, summary = EXCLUDED.summary, body_html = EXCLUDED.body_html,
+		    tags = EXCLUDED.tags, editor_id = EXCLUDED.editor_id, updated_at = now()
+		RETURNING updated_at`,
+		d.ArticleID, d.Title, d.Summary, d.BodyHTML, tagsJSON, d.EditorID,
+	).Scan(&out.UpdatedAt)
+	if err != nil {
+		logger.WithCtx(ctx).Error("article revisions repo: save draft failed", zap.Error(err), zap.Int64("article_id", d.ArticleID))
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDraft — черновик статьи; pgx.ErrNoRows, если его нет.
+func (r *ArticleRevisionRepository) GetDraft(ctx context.Context, articleID int64) (*models.ArticleDraft, error) {
+	var d models.ArticleDraft
+	var tagsRaw []byte
+	err := r.db.QueryRow(ctx, `
+		SELECT article_id, title, summary, body_html, tags, editor_id, updated_at
+		FROM article_drafts WHERE article_id = $1`, articleID,
+	).Scan(&d.ArticleID, &d.Title, &d.Summary, &d.BodyHTML, &tagsRaw, &d.EditorID, &d.UpdatedAt)
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			logger.WithCtx(ctx).Error("article revisions repo: get draft failed", zap.Error(err), zap.Int64("article_id", articleID))
+		}
+		return nil, err
+	}
+	_ = json.Unmarshal(tagsRaw, &d.Tags)
+	return &d, nil
+}
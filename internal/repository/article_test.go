@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"edutalks/internal/models"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestArticleUpdate(t *testing.T) {
+	mock := newMock(t)
+	repo := NewArticleRepo(mock, NewOutboxRepository(mock))
+
+	updated := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	cover := "/uploads/covers/1.jpg"
+	summary := "Кратко"
+	a := &models.Article{
+		ID:               7,
+		Title:            "Заголовок",
+		Summary:          &summary,
+		BodyHTML:         "<p>Текст</p>",
+		Tags:             []string{"урок"},
+		IsPublished:      true,
+		ContentUpdatedAt: &updated,
+		CoverImageURL:    &cover,
+	}
+	tags, _ := json.Marshal(a.Tags)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlRe("INSERT INTO article_revisions", "WHERE id = $1")).
+		WithArgs(int64(7), (*int64)(nil)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	// число аргументов совпадает с плейсхолдерами: $8 и $9 — content_updated_at и обложка
+	mock.ExpectExec(sqlRe("UPDATE articles", "publish_at=$7", "content_updated_at=$8", "cover_image_url=$9", "WHERE id=$6")).
+		WithArgs(a.Title, a.Summary, a.BodyHTML, tags, true, int64(7), a.PublishAt, a.ContentUpdatedAt, a.CoverImageURL).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(sqlRe("DELETE FROM article_drafts WHERE article_id = $1")).
+		WithArgs(int64(7)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectCommit()
+
+	if err := repo.Update(context.Background(), a, nil); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+}
+
+func TestArticleUpdateWritesIntents(t *testing.T) {
+	mock := newMock(t)
+	repo := NewArticleRepo(mock, NewOutboxRepository(mock))
+	a := &models.Article{ID: 3, Title: "T"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlRe("INSERT INTO article_revisions")).WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec(sqlRe("UPDATE articles")).WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+		pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(sqlRe("DELETE FROM article_drafts")).WithArgs(int64(3)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec(sqlRe("INSERT INTO side_effect_outbox (kind, payload) VALUES ($1, $2)")).
+		WithArgs("event", json.RawMessage(`{"id":3}`)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	intents := func(a *models.Article) ([]models.OutboxIntent, error) {
+		return []models.OutboxIntent{{Kind: "event", Payload: []byte(`{"id":3}`)}}, nil
+	}
+	if err := repo.Update(context.Background(), a, intents); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+}
+
+func TestArticleGetByID(t *testing.T) {
+	mock := newMock(t)
+	repo := NewArticleRepo(mock, NewOutboxRepository(mock))
+
+	created := time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC)
+	author := int64(2)
+	summary := "Кратко"
+	mock.ExpectQuery(sqlRe("SELECT id, author_id, title", "content_updated_at, view_count, cover_image_url", "FROM articles WHERE id=$1 AND deleted_at IS NULL")).
+		WithArgs(int64(5)).
+		WillReturnRows(mock.NewRows([]string{
+			"id", "author_id", "title", "summary", "body_html", "is_published", "published_at", "publish_at",
+			"created_at", "updated_at", "tags", "content_updated_at", "view_count", "cover_image_url", "review_state",
+		}).AddRow(int64(5), &author, "Статья", &summary, "<p>x</p>", true, &created, (*time.Time)(nil),
+			created, created, []byte(`["a","b"]`), (*time.Time)(nil), int64(42), (*string)(nil), "published"))
+
+	a, err := repo.GetByID(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if a.ID != 5 || a.AuthorID == nil || *a.AuthorID != 2 || a.Title != "Статья" || !a.IsPublished || a.ViewCount != 42 || a.ReviewState != "published" {
+		t.Errorf("поля разобраны неверно: %+v", a)
+	}
+	if len(a.Tags) != 2 || a.Tags[1] != "b" {
+		t.Errorf("tags = %v, ожидались [a b]", a.Tags)
+	}
+	if a.PublishedAt == nil || !a.PublishedAt.Equal(created) {
+		t.Errorf("published_at = %v", a.PublishedAt)
+	}
+}
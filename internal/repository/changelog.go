@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type ChangelogRepository struct {
+	db DB
+}
+
+func NewChangelogRepository(db DB) *ChangelogRepository {
+	return &ChangelogRepository{db: db}
+}
+
+const changelogColumns = `id, title, body_html, version, kind, is_published, published_at, created_at, updated_at`
+
+func scanChangelog(row pgx.Row) (*models.ChangelogEntry, error) {
+	var e models.ChangelogEntry
+	if err := row.Scan(&e.ID, &e.Title, &e.BodyHTML, &e.Version, &e.Kind, &e.IsPublished,
+		&e.PublishedAt, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *ChangelogRepository) Create(ctx context.Context, e *models.ChangelogEntry) (*models.ChangelogEntry, error) {
+	log := logger.WithCtx(ctx)
+
+	out, err := scanChangelog(r.db.QueryRow(ctx, `
+		INSERT INTO changelog_entries (title, body_html, version, kind, is_published, published_at)
+		VALUES ($1, $2, $3, $4, $5, CASE WHEN $5 THEN now() END)
+		RETURNING `+changelogColumns,
+		e.Title, e.BodyHTML, e.Version, e.Kind, e.IsPublished,
+	))
+	if err != nil {
+		log.Error("changelog repo: create failed", zap.Error(err))
+		return nil, err
+	}
+	log.Info("changelog repo: created", zap.Int("id", out.ID))
+	return out, nil
+}
+
+// Update — при первой публикации проставляет published_at; при снятии с публикации дату сохраняет.
+func (r *ChangelogRepository) Update(ctx context.Context, e *models.ChangelogEntry) (*models.ChangelogEntry, error) {
+	log := logger.WithCtx(ctx)
+
+	out, err := scanChangelog(r.db.QueryRow(ctx, `
+		UPDATE changelog_entries
+		SET title = $2, body_html = $3, version = $4, kind = $5, is_published = $6,
+		    published_at = CASE WHEN $6 AND published_at IS NULL THEN now() ELSE published_at END,
+		    updated_at = now()
+		WHERE id = $1
+		RETURNING `+changelogColumns,
+		e.ID, e.Title, e.BodyHTML, e.Version, e.Kind, e.IsPublished,
+	))
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("changelog repo: update failed", zap.Error(err), zap.Int("id", e.ID))
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *ChangelogRepository) Delete(ctx context.Context, id int) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM changelog_entries WHERE id = $1`, id)
+	if err != nil {
+		logger.WithCtx(ctx).Error("changelog repo: delete failed", zap.Error(err), zap.Int("id", id))
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// List — записи (новые сверху) и общее количество; onlyPublished — для публичной ленты.
+func (r *ChangelogRepository) List(ctx context.Context, onlyPublished bool, limit, offset int) ([]models.ChangelogEntry, int, error) {
+	log := logger.WithCtx(ctx)
+
+	where := ""
+	order := " ORDER BY created_at DESC, id DESC"
+	if onlyPublished {
+		where = " WHERE is_published"
+		order = " ORDER BY published_at DESC, id DESC"
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM changelog_entries`+where).Scan(&total); err != nil {
+		log.Error("changelog repo: count failed", zap.Error(err))
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT `+changelogColumns+` FROM changelog_entries`+where+order+` LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		log.Error("changelog repo: list failed", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]models.ChangelogEntry, 0, limit)
+	for rows.Next() {
+		e, err := scanChangelog(rows)
+		if err != nil {
+			log.Error("changelog repo: scan failed", zap.Error(err))
+			return nil, 0, err
+		}
+		out = append(out, *e)
+	}
+	return out, total, rows.Err()
+}
+
+// Unread — сколько опубликованных записей пользователь ещё не видел.
+func (r *ChangelogRepository) Unread(ctx context.Context, userID int) (*models.ChangelogUnread, error) {
+	log := logger.WithCtx(ctx)
+
+	var res models.ChangelogUnread
+	if err := r.db.QueryRow(ctx, `
+		SELECT (SELECT seen_at FROM changelog_seen WHERE user_id = $1),
+		       (SELECT COUNT(*) FROM changelog_entries e
+		         WHERE e.is_published
+		           AND e.published_at > COALESCE((SELECT seen_at FROM changelog_seen WHERE user_id = $1), '-infinity'))`,
+		userID,
+	).Scan(&res.LastSeenAt, &res.Unread); err != nil {
+		log.Error("changelog repo: unread failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, err
+	}
+	return &res, nil
+}
+
+// MarkSeen — пользователь открыл «Что нового».
+func (r *ChangelogRepository) MarkSeen(ctx context.Context, userID int, at time.Time) error {
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO changelog_seen (user_id, seen_at) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET seen_at = GREATEST(changelog_seen.seen_at, EXCLUDED.seen_at)`,
+		userID, at,
+	); err != nil {
+		logger.WithCtx(ctx).Error("changelog repo: mark seen failed", zap.Error(err), zap.Int("user_id", userID))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ContentReviewRepository — согласование статей и документов (review_state и content_review_events).
+type ContentReviewRepository struct {
+	db DB
+}
+
+func NewContentReviewRepository(db DB) *ContentReviewRepository {
+	return &ContentReviewRepository{db: db}
+}
+
+type reviewTable struct {
+	table, owner, published string
+}
+
+var reviewTables = map[string]reviewTable{
+	models.ReviewArticle:  {table: "articles", owner: "author_id", published: "is_published"},
+	models.ReviewDocument: {table: "documents", owner: "user_id", published: "is_public"},
+}
+
+// reviewStateExpr — состояние с учётом публикации в обход согласования: опубликованный
+// материал — published, снятый с публикации после одобрения — draft.
+func reviewStateExpr(published string) string {
+	return fmt.Sprintf(`CASE WHEN %s THEN 'published' WHEN review_state = 'published' THEN 'draft' ELSE review_state END`, published)
+}
+
+// reviewSelect — материалы обоих типов с состоянием и временем последнего перехода.
+var reviewSelect = `
+	WITH items AS (
+		SELECT 'article' AS type, a.id::bigint AS id, a.title, ` + reviewStateExpr("a.is_published") + ` AS state,
+		       a.author_id::int AS author_id, a.created_at AS created_at
+		FROM articles a WHERE a.deleted_at IS NULL
+		UNION ALL
+		SELECT 'document', d.id::bigint, COALESCE(NULLIF(d.title, ''), d.filename), ` + reviewStateExpr("d.is_public") + `,
+		       d.user_id, d.uploaded_at
+		FROM documents d WHERE d.deleted_at IS NULL
+	)`
+
+// Target — владелец и состояние материала; pgx.ErrNoRows — его нет или он в корзине.
+func (r *ContentReviewRepository) Target(ctx context.Context, typ string, id int64) (*int, string, error) {
+	t := reviewTables[typ]
+	var owner *int
+	var state string
+	err := r.db.QueryRow(ctx, fmt.Sprintf(`
+		SELECT %s::int, %s FROM %s WHERE id = $1 AND deleted_at IS NULL`,
+		t.owner, reviewStateExpr(t.published), t.table), id).Scan(&owner, &state)
+	if err != nil && err != pgx.ErrNoRows {
+		logger.WithCtx(ctx).Error("content review repo: target failed", zap.Error(err),
+			zap.String("type", typ), zap.Int64("id", id))
+	}
+	return owner, state, err
+}
+
+// Transition — переводит материал в состояние ev.ToState, если review_state сейчас одно из from,
+// и записывает переход; pgx.ErrNoRows — состояние уже другое.
+func (r *ContentReviewRepository) Transition(ctx context.Context, typ string, id int64, from []string, ev *models.ReviewEvent) error {
+	log := logger.WithCtx(ctx)
+	t := reviewTables[typ]
+
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, fmt.Sprintf(`
+			UPDATE %s SET review_state = $2
+			WHERE id = $1 AND deleted_at IS NULL AND review_state = ANY($3)`, t.table),
+			id, ev.ToState, from)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+		return tx.QueryRow(ctx, `
+			INSERT INTO content_review_events (content_type, content_id, from_state, to_state, actor_id, comment)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, created_at`,
+			typ, id, ev.FromState, ev.ToState, ev.ActorID, ev.Comment,
+		).Scan(&ev.ID, &ev.CreatedAt)
+	})
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("content review repo: transition failed", zap.Error(err), zap.String("type", typ), zap.Int64("id", id))
+		}
+		return err
+	}
+
+	log.Info("content review repo: transition", zap.String("type", typ), zap.Int64("id", id),
+		zap.String("from", ev.FromState), zap.String("to", ev.ToState), zap.Any("actor_id", ev.ActorID))
+	return nil
+}
+
+// Queue — материалы в состоянии state (typ пустой — оба типа); на проверке — давно ждущие сверху,
+// в остальных состояниях — недавние сверху.
+func (r *ContentReviewRepository) Queue(ctx context.Context, typ, state string, limit, offset int) ([]models.ReviewItem, int, error) {
+	log := logger.WithCtx(ctx)
+
+	order := "DESC"
+	if state == models.ReviewStateReview {
+		order = "ASC"
+	}
+	rows, err := r.db.Query(ctx, reviewSelect+`
+		SELECT i.type, i.id, i.title, i.state, i.author_id, COALESCE(u.full_name, ''),
+		       COALESCE((SELECT MAX(e.created_at) FROM content_review_events e
+		                 WHERE e.content_type = i.type AND e.content_id = i.id), i.created_at) AS updated_at
+		FROM items i
+		LEFT JOIN users u ON u.id = i.author_id
+		WHERE i.state = $1 AND ($2 = '' OR i.type = $2)
+		ORDER BY updated_at `+order+`, i.type, i.id
+		LIMIT $3 OFFSET $4`, state, typ, limit, offset)
+	if err != nil {
+		log.Error("content review repo: queue failed", zap.Error(err), zap.String("state", state))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := []models.ReviewItem{}
+	for rows.Next() {
+		var it models.ReviewItem
+		if err := rows.Scan(&it.Type, &it.ID, &it.Title, &it.State, &it.AuthorID, &it.AuthorName, &it.UpdatedAt); err != nil {
+			log.Error("content review repo: scan queue failed", zap.Error(err))
+			return nil, 0, err
+		}
+		items = append(items, it)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, reviewSelect+`
+		SELECT COUNT(*) FROM items WHERE state = $1 AND ($2 = '' OR type = $2)`, state, typ).Scan(&total); err != nil {
+		log.Error("content review repo: count queue failed", zap.Error(err))
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// History — переходы состояния материала по порядку.
+func (r *ContentReviewRepository) History(ctx context.Context, typ string, id int64) ([]models.ReviewEvent, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT e.id, e.from_state, e.to_state, e.actor_id, COALESCE(u.full_name, ''), e.comment, e.created_at
+		FROM content_review_events e
+		LEFT JOIN users u ON u.id = e.actor_id
+		WHERE e.content_type = $1 AND e.content_id = $2
+		ORDER BY e.created_at, e.id`, typ, id)
+	if err != nil {
+		logger.WithCtx(ctx).Error("content review repo: history failed", zap.Error(err),
+			zap.String("type", typ), zap.Int64("id", id))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.ReviewEvent{}
+	for rows.Next() {
+		var ev models.ReviewEvent
+		if err := rows.Scan(&ev.ID, &ev.FromState, &ev.ToState, &ev.ActorID, &ev.ActorName, &ev.Comment, &ev.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, ev)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// contentTables — таблица материала по типу (имя таблицы подставляется в SQL только отсюда).
+var contentTables = map[string]string{
+	models.ContentKindNews:    "news",
+	models.ContentKindArticle: "articles",
+}
+
+type ContentViewRepository struct {
+	db DB
+}
+
+func NewContentViewRepository(db DB) *ContentViewRepository {
+	return &ContentViewRepository{db: db}
+}
+
+// Record — засчитывает просмотр, если зритель ещё не смотрел материал сегодня, и увеличивает
+// view_count материала. false — повторный просмотр за сутки, счётчик не изменён.
+func (r *ContentViewRepository) Record(ctx context.Context, kind string, id int64, viewer string) (bool, error) {
+	table, ok := contentTables[kind]
+	if !ok {
+		return false, fmt.Errorf("неизвестный тип материала: %s", kind)
+	}
+
+	q := fmt.Sprintf(`
+		WITH ins AS (
+			INSERT INTO content_views (kind, content_id, viewer) VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING
+			RETURNING 1
+		)
+		UPDATE %s SET view_count = view_count + 1
+		WHERE id = $2 AND EXISTS (SELECT 1 FROM ins)
+	`, table)
+	tag, err := r.db.Exec(ctx, q, kind, id, viewer)
+	if err != nil {
+		logger.WithCtx(ctx).Error("content view repo: record failed", zap.String("kind", kind), zap.Int64("id", id), zap.Error(err))
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Trending — опубликованные материалы с наибольшим числом просмотров начиная с since;
+// kind пустой — новости и статьи вместе.
+func (r *ContentViewRepository) Trending(ctx context.Context, kind string, since time.Time, limit int) ([]models.TrendingItem, error) {
+	const q = `
+		WITH top AS (
+			SELECT kind, content_id, COUNT(*) AS views
+			FROM content_views
+			WHERE viewed_at >= $1 AND ($2 = '' OR kind = $2)
+			GROUP BY kind, content_id
+		)
+		SELECT t.kind, t.content_id,
+		       COALESCE(n.title, a.title),
+		       COALESCE(n.published_at, a.published_at),
+		       t.views,
+		       COALESCE(n.view_count, a.view_count)
+		FROM top t
+		LEFT JOIN news n     ON t.kind = 'news'    AND n.id = t.content_id AND n.is_published AND n.deleted_at IS NULL
+		LEFT JOIN articles a ON t.kind = 'article' AND a.id = t.content_id AND a.is_published AND a.deleted_at IS NULL
+		WHERE n.id IS NOT NULL OR a.id IS NOT NULL
+		ORDER BY t.views DESC, t.content_id DESC
+		LIMIT $3
+	`
+	rows, err := r.db.Query(ctx, q, since, kind, limit)
+	if err != nil {
+		logger.WithCtx(ctx).Error("content view repo: trending failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.TrendingItem{}
+	for rows.Next() {
+		var it models.TrendingItem
+		if err := rows.Scan(&it.Type, &it.ID, &it.Title, &it.PublishedAt, &it.Views, &it.ViewCount); err != nil {
+			logger.WithCtx(ctx).Error("content view repo: scan trending failed", zap.Error(err))
+			return nil, err
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// DashboardStatsRepository — временные ряды для графиков админ-дашборда. Дни без событий
+// возвращаются с нулями, чтобы ряд можно было рисовать как есть.
+type DashboardStatsRepository struct {
+	db DB
+}
+
+func NewDashboardStatsRepository(db DB) *DashboardStatsRepository {
+	return &DashboardStatsRepository{db: db}
+}
+
+// Registrations — новые пользователи по дням.
+func (r *DashboardStatsRepository) Registrations(ctx context.Context, p models.StatsPeriod) ([]models.DayCount, error) {
+	const q = `
+		SELECT to_char(d, 'YYYY-MM-DD'), COUNT(u.id)
+		FROM generate_series($1::date, $2::date, interval '1 day') d
+		LEFT JOIN users u ON u.created_at >= d AND u.created_at < d + interval '1 day'
+		GROUP BY d
+		ORDER BY d`
+
+	rows, err := r.db.Query(ctx, q, p.From, p.To)
+	if err != nil {
+		logger.WithCtx(ctx).Error("dashboard stats repo: registrations failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.DayCount{}
+	for rows.Next() {
+		var pt models.DayCount
+		if err := rows.Scan(&pt.Day, &pt.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, pt)
+	}
+	return out, rows.Err()
+}
+
+// SubscriptionChanges — выдачи, продления и отключения подписок по дням.
+func (r *DashboardStatsRepository) SubscriptionChanges(ctx context.Context, p models.StatsPeriod) ([]models.SubscriptionDayPoint, error) {
+	const q = `
+		SELECT to_char(d, 'YYYY-MM-DD'),
+		       COUNT(c.id) FILTER (WHERE c.kind = 'grant'),
+		       COUNT(c.id) FILTER (WHERE c.kind = 'extend'),
+		       COUNT(c.id) FILTER (WHERE c.kind = 'revoke'),
+		       COUNT(c.id) FILTER (WHERE c.kind = 'expire')
+		FROM generate_series($1::date, $2::date, interval '1 day') d
+		LEFT JOIN subscription_changes c ON c.created_at >= d AND c.created_at < d + interval '1 day'
+		GROUP BY d
+		ORDER BY d`
+
+	rows, err := r.db.Query(ctx, q, p.From, p.To)
+	if err != nil {
+		logger.WithCtx(ctx).Error("dashboard stats repo: subscription changes failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.SubscriptionDayPoint{}
+	for rows.Next() {
+		var pt models.SubscriptionDayPoint
+		if err := rows.Scan(&pt.Day, &pt.Granted, &pt.Extended, &pt.Revoked, &pt.Expired); err != nil {
+			return nil, err
+		}
+		out = append(out, pt)
+	}
+	return out, rows.Err()
+}
+
+// UploadsWeekly — загруженные документы по неделям (с понедельника).
+func (r *DashboardStatsRepository) UploadsWeekly(ctx context.Context, p models.StatsPeriod) ([]models.WeekCount, error) {
+	const q = `
+		SELECT to_char(w, 'YYYY-MM-DD'), COUNT(d.id)
+		FROM generate_series(date_trunc('week', $1::date), $2::date, interval '1 week') w
+		LEFT JOIN documents d ON d.uploaded_at >= w AND d.uploaded_at < w + interval '1 week' AND d.deleted_at IS NULL
+		GROUP BY w
+		ORDER BY w`
+
+	rows, err := r.db.Query(ctx, q, p.From, p.To)
+	if err != nil {
+		logger.WithCtx(ctx).Error("dashboard stats repo: uploads weekly failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.WeekCount{}
+	for rows.Next() {
+		var pt models.WeekCount
+		if err := rows.Scan(&pt.Week, &pt.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, pt)
+	}
+	return out, rows.Err()
+}
+
+// TopAdmins — самые активные администраторы: загрузки документов, новые статьи, правки статей
+// и изменения подписок за период.
+func (r *DashboardStatsRepository) TopAdmins(ctx context.Context, p models.StatsPeriod, limit int) ([]models.AdminActivity, error) {
+	const q = `
+		WITH acts AS (
+			SELECT user_id AS admin_id, 'upload' AS kind FROM documents
+			WHERE uploaded_at >= $1::date AND uploaded_at < $2::date + 1
+			UNION ALL
+			SELECT author_id, 'article' FROM articles
+			WHERE created_at >= $1::date AND created_at < $2::date + 1
+			UNION ALL
+			SELECT editor_id, 'edit' FROM article_revisions
+			WHERE created_at >= $1::date AND created_at < $2::date + 1
+			UNION ALL
+			SELECT actor_id, 'subscription' FROM subscription_changes
+			WHERE created_at >= $1::date AND created_at < $2::date + 1
+		)
+		SELECT u.id, COALESCE(u.username, ''), COALESCE(u.full_name, ''),
+		       COUNT(*) FILTER (WHERE a.kind = 'upload'),
+		       COUNT(*) FILTER (WHERE a.kind = 'article'),
+		       COUNT(*) FILTER (WHERE a.kind = 'edit'),
+		       COUNT(*) FILTER (WHERE a.kind = 'subscription'),
+		       COUNT(*)
+		FROM acts a
+		JOIN users u ON u.id = a.admin_id AND u.role = 'admin'
+		GROUP BY u.id
+		ORDER BY COUNT(*) DESC, u.id
+		LIMIT $3`
+
+	rows, err := r.db.Query(ctx, q, p.From, p.To, limit)
+	if err != nil {
+		logger.WithCtx(ctx).Error("dashboard stats repo: top admins failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.AdminActivity{}
+	for rows.Next() {
+		var a models.AdminActivity
+		if err := rows.Scan(&a.UserID, &a.Username, &a.FullName,
+			&a.Uploads, &a.ArticlesCreated, &a.ArticleEdits, &a.SubscriptionChanges, &a.Total); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
@@ -5,29 +5,30 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"edutalks/internal/logger"
 	"edutalks/internal/models"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
 type DocumentRepository struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewDocumentRepository(db *pgxpool.Pool) *DocumentRepository {
+func NewDocumentRepository(db DB) *DocumentRepository {
 	return &DocumentRepository{db: db}
 }
 
 type DocumentRepo interface {
 	SaveDocument(ctx context.Context, doc *models.Document) (int, error)
-	GetPublicDocumentsPaginated(ctx context.Context, limit, offset int, category string) ([]*models.Document, int, error)
+	GetPublicDocumentsPaginated(ctx context.Context, limit, offset int, category string, a11y models.DocumentAccessibilityFilter, normative models.DocumentNormativeFilter) ([]*models.Document, int, error)
 	GetDocumentByID(ctx context.Context, id int) (*models.Document, error)
 	DeleteDocument(ctx context.Context, id int) error
 	GetAllDocuments(ctx context.Context, limit int) ([]*models.Document, error)
+	GetDocumentsByUser(ctx context.Context, userID, limit, offset int) ([]*models.Document, int, error)
 	Search(ctx context.Context, query string) ([]models.Document, error)
 	GetPublicDocumentsByFilterPaginated(
 		ctx context.Context,
@@ -40,7 +41,13 @@ type DocumentRepo interface {
 		ctx context.Context,
 		sectionID *int,
 		category string,
+		a11y models.DocumentAccessibilityFilter,
+		normative models.DocumentNormativeFilter,
 	) ([]*models.Document, error)
+	UpdateAccessibility(ctx context.Context, id int, req models.UpdateDocumentAccessibilityRequest, contentUpdated bool) (*models.Document, error)
+	UpdateNormative(ctx context.Context, id int, req models.UpdateDocumentNormativeRequest) (*models.Document, error)
+	UpdateDocument(ctx context.Context, id int, req models.UpdateDocumentRequest, file *models.DocumentFile) (*models.Document, error)
+	MoveDocuments(ctx context.Context, ids []int, fromSectionID, sectionID *int) (map[int]*int, error)
 }
 
 // SaveDocument — сохранить документ и вернуть его ID
@@ -49,8 +56,12 @@ func (r *DocumentRepository) SaveDocument(ctx context.Context, doc *models.Docum
 
 	const query = `
 		INSERT INTO documents (
-			user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+			user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download,
+			has_text_layer, large_print_url, audio_url,
+			doc_number, issuing_authority, adopted_at, effective_at,
+			scan_status, scan_signature, scanned_at,
+			sha256, size_bytes
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,NULLIF($18, ''),$19,$20,NULLIF($21, ''),$22)
 		RETURNING id
 	`
 
@@ -66,6 +77,18 @@ func (r *DocumentRepository) SaveDocument(ctx context.Context, doc *models.Docum
 		doc.SectionID,
 		doc.UploadedAt,
 		doc.AllowFreeDownload,
+		doc.HasTextLayer,
+		doc.LargePrintURL,
+		doc.AudioURL,
+		doc.DocNumber,
+		doc.IssuingAuthority,
+		doc.AdoptedAt,
+		doc.EffectiveAt,
+		doc.ScanStatus,
+		doc.ScanSignature,
+		doc.ScannedAt,
+		doc.SHA256,
+		doc.SizeBytes,
 	).Scan(&id); err != nil {
 		log.Error("document repo: save failed", zap.Error(err),
 			zap.String("filename", doc.Filename), zap.Int("user_id", doc.UserID))
@@ -76,40 +99,96 @@ func (r *DocumentRepository) SaveDocument(ctx context.Context, doc *models.Docum
 	return id, nil
 }
 
-// GetPublicDocumentsPaginated — публичные документы (опц. фильтр по категории) с пагинацией + total
-func (r *DocumentRepository) GetPublicDocumentsPaginated(ctx context.Context, limit, offset int, category string) ([]*models.Document, int, error) {
+// accessibilityCond — условия фильтра доступности (без параметров), начинается с " AND" или пустая.
+func accessibilityCond(f models.DocumentAccessibilityFilter) string {
+	var sb strings.Builder
+	if f.TextLayer {
+		sb.WriteString(" AND has_text_layer")
+	}
+	if f.LargePrint {
+		sb.WriteString(" AND large_print_url IS NOT NULL")
+	}
+	if f.Audio {
+		sb.WriteString(" AND audio_url IS NOT NULL")
+	}
+	return sb.String()
+}
+
+// normativeCond — условия фильтра по реквизитам; параметры дописываются к args, строка начинается с " AND" или пустая.
+func normativeCond(f models.DocumentNormativeFilter, args []any) (string, []any) {
+	var sb strings.Builder
+	add := func(cond string, v any) {
+		args = append(args, v)
+		sb.WriteString(fmt.Sprintf(cond, len(args)))
+	}
+	if f.Number != "" {
+		add(" AND doc_number ILIKE '%%' || $%d || '%%'", f.Number)
+	}
+	if f.Authority != "" {
+		add(" AND issuing_authority ILIKE '%%' || $%d || '%%'", f.Authority)
+	}
+	if f.AdoptedFrom != nil {
+		add(" AND adopted_at >= $%d", *f.AdoptedFrom)
+	}
+	if f.AdoptedTo != nil {
+		add(" AND adopted_at <= $%d", *f.AdoptedTo)
+	}
+	if f.EffectiveFrom != nil {
+		add(" AND effective_at >= $%d", *f.EffectiveFrom)
+	}
+	if f.EffectiveTo != nil {
+		add(" AND effective_at <= $%d", *f.EffectiveTo)
+	}
+	if f.InForce {
+		sb.WriteString(" AND effective_at <= CURRENT_DATE")
+	}
+	return sb.String(), args
+}
+
+// documentOrder — ORDER BY по сортировке фильтра; документы без реквизита — в конце.
+func documentOrder(f models.DocumentNormativeFilter) string {
+	dir := " DESC"
+	if f.Asc {
+		dir = " ASC"
+	}
+	switch f.Sort {
+	case models.DocumentSortAdopted, models.DocumentSortEffective:
+		return " ORDER BY " + f.Sort + dir + " NULLS LAST, uploaded_at DESC"
+	case models.DocumentSortNumber:
+		return " ORDER BY lower(doc_number)" + dir + " NULLS LAST, uploaded_at DESC"
+	default:
+		return " ORDER BY uploaded_at" + dir
+	}
+}
+
+// GetPublicDocumentsPaginated — публичные документы (опц. фильтр по категории, доступности и реквизитам) с пагинацией + total
+func (r *DocumentRepository) GetPublicDocumentsPaginated(ctx context.Context, limit, offset int, category string, a11y models.DocumentAccessibilityFilter, normative models.DocumentNormativeFilter) ([]*models.Document, int, error) {
 	log := logger.WithCtx(ctx)
 
 	var (
-		rows  pgx.Rows
-		err   error
 		docs  []*models.Document
-		query string
 		args  []any
 		total int
 	)
 
+	where := ` WHERE deleted_at IS NULL AND is_public = true`
 	if strings.TrimSpace(category) != "" {
-		query = `
-			SELECT id, user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download
-			FROM documents
-			WHERE is_public = true AND category = $1
-			ORDER BY uploaded_at DESC
-			LIMIT $2 OFFSET $3
-		`
-		args = []any{category, limit, offset}
-		rows, err = r.db.Query(ctx, query, args...)
-	} else {
-		query = `
-			SELECT id, user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download
-			FROM documents
-			WHERE is_public = true
-			ORDER BY uploaded_at DESC
-			LIMIT $1 OFFSET $2
-		`
-		args = []any{limit, offset}
-		rows, err = r.db.Query(ctx, query, args...)
+		args = append(args, category)
+		where += ` AND category = $1`
 	}
+	where += accessibilityCond(a11y)
+	var cond string
+	cond, args = normativeCond(normative, args)
+	where += cond
+
+	query := `
+		SELECT id, user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download,
+		       has_text_layer, large_print_url, audio_url, content_updated_at,
+		       doc_number, issuing_authority, adopted_at, effective_at
+		FROM documents` + where + documentOrder(normative) +
+		" LIMIT $" + strconv.Itoa(len(args)+1) + " OFFSET $" + strconv.Itoa(len(args)+2)
+
+	rows, err := r.db.Query(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		log.Error("document repo: get public paginated query failed", zap.Error(err),
 			zap.String("category", category), zap.Int("limit", limit), zap.Int("offset", offset))
@@ -131,6 +210,14 @@ func (r *DocumentRepository) GetPublicDocumentsPaginated(ctx context.Context, li
 			&d.SectionID,
 			&d.UploadedAt,
 			&d.AllowFreeDownload,
+			&d.HasTextLayer,
+			&d.LargePrintURL,
+			&d.AudioURL,
+			&d.ContentUpdatedAt,
+			&d.DocNumber,
+			&d.IssuingAuthority,
+			&d.AdoptedAt,
+			&d.EffectiveAt,
 		); err != nil {
 			log.Error("document repo: scan public paginated failed", zap.Error(err))
 			return nil, 0, err
@@ -143,20 +230,9 @@ func (r *DocumentRepository) GetPublicDocumentsPaginated(ctx context.Context, li
 	}
 
 	// total
-	if strings.TrimSpace(category) != "" {
-		if err := r.db.QueryRow(ctx,
-			`SELECT COUNT(*) FROM documents WHERE is_public = true AND category = $1`, category,
-		).Scan(&total); err != nil {
-			log.Error("document repo: count public paginated with category failed", zap.Error(err))
-			return nil, 0, err
-		}
-	} else {
-		if err := r.db.QueryRow(ctx,
-			`SELECT COUNT(*) FROM documents WHERE is_public = true`,
-		).Scan(&total); err != nil {
-			log.Error("document repo: count public paginated failed", zap.Error(err))
-			return nil, 0, err
-		}
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM documents`+where, args...).Scan(&total); err != nil {
+		log.Error("document repo: count public paginated failed", zap.Error(err))
+		return nil, 0, err
 	}
 
 	log.Debug("document repo: public paginated done",
@@ -169,9 +245,12 @@ func (r *DocumentRepository) GetPublicDocumentsPaginated(ctx context.Context, li
 func (r *DocumentRepository) GetDocumentByID(ctx context.Context, id int) (*models.Document, error) {
 	log := logger.WithCtx(ctx)
 
-	const query = `
-		SELECT id, user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download
-		FROM documents WHERE id = $1
+	query := `
+		SELECT id, user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download,
+		       has_text_layer, large_print_url, audio_url, content_updated_at,
+		       doc_number, issuing_authority, adopted_at, effective_at,
+		       COALESCE(scan_status, ''), scan_signature, scanned_at, ` + reviewStateExpr("is_public") + `
+		FROM documents WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var d models.Document
@@ -187,6 +266,18 @@ func (r *DocumentRepository) GetDocumentByID(ctx context.Context, id int) (*mode
 		&d.SectionID,
 		&d.UploadedAt,
 		&d.AllowFreeDownload,
+		&d.HasTextLayer,
+		&d.LargePrintURL,
+		&d.AudioURL,
+		&d.ContentUpdatedAt,
+		&d.DocNumber,
+		&d.IssuingAuthority,
+		&d.AdoptedAt,
+		&d.EffectiveAt,
+		&d.ScanStatus,
+		&d.ScanSignature,
+		&d.ScannedAt,
+		&d.ReviewState,
 	); err != nil {
 		log.Warn("document repo: get by id failed", zap.Int("doc_id", id), zap.Error(err))
 		return nil, err
@@ -196,17 +287,18 @@ func (r *DocumentRepository) GetDocumentByID(ctx context.Context, id int) (*mode
 	return &d, nil
 }
 
-// DeleteDocument — удалить документ
+// DeleteDocument — переместить документ в корзину; файл остаётся на диске до окончательного
+// удаления (TrashRepository).
 func (r *DocumentRepository) DeleteDocument(ctx context.Context, id int) error {
 	log := logger.WithCtx(ctx)
 
-	const query = `DELETE FROM documents WHERE id = $1`
+	const query = `UPDATE documents SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
 	if _, err := r.db.Exec(ctx, query, id); err != nil {
 		log.Error("document repo: delete failed", zap.Int("doc_id", id), zap.Error(err))
 		return err
 	}
 
-	log.Info("document repo: deleted", zap.Int("doc_id", id))
+	log.Info("document repo: moved to trash", zap.Int("doc_id", id))
 	return nil
 }
 
@@ -215,8 +307,12 @@ func (r *DocumentRepository) GetAllDocuments(ctx context.Context, limit int) ([]
 	log := logger.WithCtx(ctx)
 
 	query := `
-		SELECT id, user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download
+		SELECT id, user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download,
+		       has_text_layer, large_print_url, audio_url, content_updated_at,
+		       doc_number, issuing_authority, adopted_at, effective_at,
+		       COALESCE(scan_status, ''), scan_signature, scanned_at
 		FROM documents
+		WHERE deleted_at IS NULL
 		ORDER BY uploaded_at DESC
 	`
 	if limit > 0 {
@@ -245,6 +341,17 @@ func (r *DocumentRepository) GetAllDocuments(ctx context.Context, limit int) ([]
 			&d.SectionID,
 			&d.UploadedAt,
 			&d.AllowFreeDownload,
+			&d.HasTextLayer,
+			&d.LargePrintURL,
+			&d.AudioURL,
+			&d.ContentUpdatedAt,
+			&d.DocNumber,
+			&d.IssuingAuthority,
+			&d.AdoptedAt,
+			&d.EffectiveAt,
+			&d.ScanStatus,
+			&d.ScanSignature,
+			&d.ScannedAt,
 		); err != nil {
 			log.Error("document repo: scan get all failed", zap.Error(err))
 			return nil, err
@@ -260,14 +367,62 @@ func (r *DocumentRepository) GetAllDocuments(ctx context.Context, limit int) ([]
 	return docs, nil
 }
 
+// GetDocumentsByUser — документы, загруженные пользователем (и закрытые), новые сверху, и их общее число.
+func (r *DocumentRepository) GetDocumentsByUser(ctx context.Context, userID, limit, offset int) ([]*models.Document, int, error) {
+	log := logger.WithCtx(ctx)
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download,
+		       has_text_layer, large_print_url, audio_url, content_updated_at,
+		       doc_number, issuing_authority, adopted_at, effective_at,
+		       COALESCE(scan_status, ''), scan_signature, scanned_at,
+		       `+reviewStateExpr("is_public")+`, COUNT(*) OVER ()
+		FROM documents
+		WHERE deleted_at IS NULL AND user_id = $1
+		ORDER BY uploaded_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		log.Error("document repo: get by user query failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	docs := []*models.Document{}
+	total := 0
+	for rows.Next() {
+		var d models.Document
+		if err := rows.Scan(
+			&d.ID, &d.UserID, &d.Title, &d.Filename, &d.Filepath, &d.Description, &d.IsPublic, &d.Category,
+			&d.SectionID, &d.UploadedAt, &d.AllowFreeDownload,
+			&d.HasTextLayer, &d.LargePrintURL, &d.AudioURL, &d.ContentUpdatedAt,
+			&d.DocNumber, &d.IssuingAuthority, &d.AdoptedAt, &d.EffectiveAt,
+			&d.ScanStatus, &d.ScanSignature, &d.ScannedAt,
+			&d.ReviewState, &total,
+		); err != nil {
+			log.Error("document repo: scan get by user failed", zap.Error(err))
+			return nil, 0, err
+		}
+		docs = append(docs, &d)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("document repo: rows error get by user", zap.Error(err))
+		return nil, 0, err
+	}
+	return docs, total, nil
+}
+
 // Search — поиск по нескольким полям (без filepath)
 func (r *DocumentRepository) Search(ctx context.Context, query string) ([]models.Document, error) {
 	log := logger.WithCtx(ctx)
 
 	const q = `
-		SELECT id, user_id, title, filename, description, is_public, category, section_id, uploaded_at, allow_free_download
+		SELECT id, user_id, title, filename, description, is_public, category, section_id, uploaded_at, allow_free_download,
+		       has_text_layer, large_print_url, audio_url, content_updated_at,
+		       doc_number, issuing_authority, adopted_at, effective_at
 		FROM documents
-		WHERE title ILIKE $1 OR filename ILIKE $1 OR description ILIKE $1 OR category ILIKE $1
+		WHERE deleted_at IS NULL
+		  AND (title ILIKE $1 OR filename ILIKE $1 OR description ILIKE $1 OR category ILIKE $1
+		   OR doc_number ILIKE $1 OR issuing_authority ILIKE $1)
 	`
 	pattern := "%" + query + "%"
 
@@ -292,6 +447,14 @@ func (r *DocumentRepository) Search(ctx context.Context, query string) ([]models
 			&d.SectionID,
 			&d.UploadedAt,
 			&d.AllowFreeDownload,
+			&d.HasTextLayer,
+			&d.LargePrintURL,
+			&d.AudioURL,
+			&d.ContentUpdatedAt,
+			&d.DocNumber,
+			&d.IssuingAuthority,
+			&d.AdoptedAt,
+			&d.EffectiveAt,
 		); err != nil {
 			log.Error("document repo: scan search failed", zap.Error(err))
 			return nil, err
@@ -327,9 +490,11 @@ func (r *DocumentRepository) GetPublicDocumentsByFilterPaginated(
 	)
 
 	queryBase := `
-		SELECT id, user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download
+		SELECT id, user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download,
+		       has_text_layer, large_print_url, audio_url, content_updated_at,
+		       doc_number, issuing_authority, adopted_at, effective_at
 		FROM documents
-		WHERE is_public = true
+		WHERE deleted_at IS NULL AND is_public = true
 	`
 
 	if sectionID != nil {
@@ -374,6 +539,14 @@ func (r *DocumentRepository) GetPublicDocumentsByFilterPaginated(
 			&d.SectionID,
 			&d.UploadedAt,
 			&d.AllowFreeDownload,
+			&d.HasTextLayer,
+			&d.LargePrintURL,
+			&d.AudioURL,
+			&d.ContentUpdatedAt,
+			&d.DocNumber,
+			&d.IssuingAuthority,
+			&d.AdoptedAt,
+			&d.EffectiveAt,
 		); err != nil {
 			log.Error("document repo: scan public filtered paginated failed", zap.Error(err))
 			return nil, 0, err
@@ -386,7 +559,7 @@ func (r *DocumentRepository) GetPublicDocumentsByFilterPaginated(
 	}
 
 	// total
-	countQuery := `SELECT COUNT(*) FROM documents WHERE is_public = true`
+	countQuery := `SELECT COUNT(*) FROM documents WHERE deleted_at IS NULL AND is_public = true`
 	var argsCnt []any
 	if len(cond) > 0 {
 		countQuery += " AND " + strings.Join(cond, " AND ")
@@ -409,7 +582,7 @@ func (r *DocumentRepository) UpdateDocumentSection(ctx context.Context, id int,
 	log := logger.WithCtx(ctx)
 
 	if _, err := r.db.Exec(ctx,
-		`UPDATE documents SET section_id=$1, uploaded_at=uploaded_at WHERE id=$2`, sectionID, id,
+		`UPDATE documents SET section_id=$1, uploaded_at=uploaded_at WHERE id=$2 AND deleted_at IS NULL`, sectionID, id,
 	); err != nil {
 		log.Error("document repo: update section failed", zap.Error(err), zap.Int("doc_id", id), zap.Any("section_id", sectionID))
 		return err
@@ -419,19 +592,23 @@ func (r *DocumentRepository) UpdateDocumentSection(ctx context.Context, id int,
 	return nil
 }
 
-// GetPublicDocuments — публичные документы по фильтрам (без пагинации)
+// GetPublicDocuments — публичные документы по фильтрам и реквизитам (без пагинации)
 func (r *DocumentRepository) GetPublicDocuments(
 	ctx context.Context,
 	sectionID *int,
 	category string,
+	a11y models.DocumentAccessibilityFilter,
+	normative models.DocumentNormativeFilter,
 ) ([]*models.Document, error) {
 	log := logger.WithCtx(ctx)
 
 	query := `
 		SELECT id, user_id, COALESCE(title, '') AS title, filename, filepath, description, is_public,
-		       category, section_id, uploaded_at, allow_free_download
+		       category, section_id, uploaded_at, allow_free_download,
+		       has_text_layer, large_print_url, audio_url, content_updated_at,
+		       doc_number, issuing_authority, adopted_at, effective_at
 		FROM documents
-		WHERE is_public = true
+		WHERE deleted_at IS NULL AND is_public = true
 	`
 	args := []any{}
 	idx := 1
@@ -446,8 +623,12 @@ func (r *DocumentRepository) GetPublicDocuments(
 		args = append(args, category)
 		idx++
 	}
+	query += accessibilityCond(a11y)
+	var cond string
+	cond, args = normativeCond(normative, args)
+	query += cond
 
-	query += " ORDER BY uploaded_at DESC"
+	query += documentOrder(normative)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -472,6 +653,14 @@ func (r *DocumentRepository) GetPublicDocuments(
 			&d.SectionID,
 			&d.UploadedAt,
 			&d.AllowFreeDownload,
+			&d.HasTextLayer,
+			&d.LargePrintURL,
+			&d.AudioURL,
+			&d.ContentUpdatedAt,
+			&d.DocNumber,
+			&d.IssuingAuthority,
+			&d.AdoptedAt,
+			&d.EffectiveAt,
 		); err != nil {
 			log.Error("document repo: scan get public failed", zap.Error(err))
 			return nil, err
@@ -490,3 +679,212 @@ func (r *DocumentRepository) GetPublicDocuments(
 	)
 	return docs, nil
 }
+
+// UpdateAccessibility — изменить поля доступности; пустая строка в ссылке очищает её.
+// contentUpdated — появилась новая версия документа: выставить content_updated_at.
+func (r *DocumentRepository) UpdateAccessibility(ctx context.Context, id int, req models.UpdateDocumentAccessibilityRequest, contentUpdated bool) (*models.Document, error) {
+	log := logger.WithCtx(ctx)
+
+	const query = `
+		UPDATE documents SET
+			has_text_layer  = COALESCE($2, has_text_layer),
+			large_print_url = CASE WHEN $3::text IS NULL THEN large_print_url ELSE NULLIF($3, '') END,
+			audio_url       = CASE WHEN $4::text IS NULL THEN audio_url ELSE NULLIF($4, '') END,
+			content_updated_at = CASE WHEN $5 THEN NOW() ELSE content_updated_at END
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download,
+		          has_text_layer, large_print_url, audio_url, content_updated_at,
+		          doc_number, issuing_authority, adopted_at, effective_at
+	`
+
+	var d models.Document
+	if err := r.db.QueryRow(ctx, query, id, req.HasTextLayer, req.LargePrintURL, req.AudioURL, contentUpdated).Scan(
+		&d.ID,
+		&d.UserID,
+		&d.Title,
+		&d.Filename,
+		&d.Filepath,
+		&d.Description,
+		&d.IsPublic,
+		&d.Category,
+		&d.SectionID,
+		&d.UploadedAt,
+		&d.AllowFreeDownload,
+		&d.HasTextLayer,
+		&d.LargePrintURL,
+		&d.AudioURL,
+		&d.ContentUpdatedAt,
+		&d.DocNumber,
+		&d.IssuingAuthority,
+		&d.AdoptedAt,
+		&d.EffectiveAt,
+	); err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("document repo: update accessibility failed", zap.Int("doc_id", id), zap.Error(err))
+		}
+		return nil, err
+	}
+
+	log.Info("document repo: accessibility updated", zap.Int("doc_id", id), zap.Bool("content_updated", contentUpdated))
+	return &d, nil
+}
+
+// UpdateNormative — изменить реквизиты нормативного документа; пустая строка очищает поле.
+func (r *DocumentRepository) UpdateNormative(ctx context.Context, id int, req models.UpdateDocumentNormativeRequest) (*models.Document, error) {
+	log := logger.WithCtx(ctx)
+
+	const query = `
+		UPDATE documents SET
+			doc_number        = CASE WHEN $2::text IS NULL THEN doc_number ELSE NULLIF($2, '') END,
+			issuing_authority = CASE WHEN $3::text IS NULL THEN issuing_authority ELSE NULLIF($3, '') END,
+			adopted_at        = CASE WHEN $4::text IS NULL THEN adopted_at ELSE NULLIF($4, '')::date END,
+			effective_at      = CASE WHEN $5::text IS NULL THEN effective_at ELSE NULLIF($5, '')::date END
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download,
+		          has_text_layer, large_print_url, audio_url, content_updated_at,
+		          doc_number, issuing_authority, adopted_at, effective_at
+	`
+
+	var d models.Document
+	if err := r.db.QueryRow(ctx, query, id, req.DocNumber, req.IssuingAuthority, req.AdoptedAt, req.EffectiveAt).Scan(
+		&d.ID,
+		&d.UserID,
+		&d.Title,
+		&d.Filename,
+		&d.Filepath,
+		&d.Description,
+		&d.IsPublic,
+		&d.Category,
+		&d.SectionID,
+		&d.UploadedAt,
+		&d.AllowFreeDownload,
+		&d.HasTextLayer,
+		&d.LargePrintURL,
+		&d.AudioURL,
+		&d.ContentUpdatedAt,
+		&d.DocNumber,
+		&d.IssuingAuthority,
+		&d.AdoptedAt,
+		&d.EffectiveAt,
+	); err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("document repo: update normative failed", zap.Int("doc_id", id), zap.Error(err))
+		}
+		return nil, err
+	}
+
+	log.Info("document repo: normative updated", zap.Int("doc_id", id))
+	return &d, nil
+}
+
+// UpdateDocument — изменить метаданные и, если file != nil, заменить файл: результат проверки
+// берётся от нового файла, content_updated_at выставляется (новая версия документа).
+func (r *DocumentRepository) UpdateDocument(ctx context.Context, id int, req models.UpdateDocumentRequest, file *models.DocumentFile) (*models.Document, error) {
+	log := logger.WithCtx(ctx)
+
+	const query = `
+		UPDATE documents SET
+			title               = COALESCE($2, title),
+			description         = COALESCE($3, description),
+			category            = COALESCE($4, category),
+			is_public           = COALESCE($5, is_public),
+			allow_free_download = COALESCE($6, allow_free_download),
+			filename            = COALESCE($7, filename),
+			filepath            = COALESCE($8, filepath),
+			scan_status         = CASE WHEN $8::text IS NULL THEN scan_status ELSE NULLIF($9, '') END,
+			scan_signature      = CASE WHEN $8::text IS NULL THEN scan_signature ELSE NULL END,
+			scanned_at          = CASE WHEN $8::text IS NULL THEN scanned_at ELSE $10 END,
+			content_updated_at  = CASE WHEN $8::text IS NULL THEN content_updated_at ELSE NOW() END,
+			sha256              = CASE WHEN $8::text IS NULL THEN sha256 ELSE NULLIF($11, '') END,
+			size_bytes          = CASE WHEN $8::text IS NULL THEN size_bytes ELSE $12 END
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, user_id, title, filename, filepath, description, is_public, category, section_id, uploaded_at, allow_free_download,
+		          has_text_layer, large_print_url, audio_url, content_updated_at,
+		          doc_number, issuing_authority, adopted_at, effective_at,
+		          COALESCE(scan_status, ''), scan_signature, scanned_at
+	`
+
+	var filename, filepath, scanStatus, sha *string
+	var scannedAt *time.Time
+	var size *int64
+	if file != nil {
+		filename, filepath, scanStatus, scannedAt = &file.Filename, &file.Filepath, &file.ScanStatus, file.ScannedAt
+		sha, size = &file.SHA256, &file.SizeBytes
+	}
+
+	var d models.Document
+	if err := r.db.QueryRow(ctx, query, id, req.Title, req.Description, req.Category, req.IsPublic, req.AllowFreeDownload,
+		filename, filepath, scanStatus, scannedAt, sha, size,
+	).Scan(
+		&d.ID,
+		&d.UserID,
+		&d.Title,
+		&d.Filename,
+		&d.Filepath,
+		&d.Description,
+		&d.IsPublic,
+		&d.Category,
+		&d.SectionID,
+		&d.UploadedAt,
+		&d.AllowFreeDownload,
+		&d.HasTextLayer,
+		&d.LargePrintURL,
+		&d.AudioURL,
+		&d.ContentUpdatedAt,
+		&d.DocNumber,
+		&d.IssuingAuthority,
+		&d.AdoptedAt,
+		&d.EffectiveAt,
+		&d.ScanStatus,
+		&d.ScanSignature,
+		&d.ScannedAt,
+	); err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("document repo: update failed", zap.Int("doc_id", id), zap.Error(err))
+		}
+		return nil, err
+	}
+
+	log.Info("document repo: updated", zap.Int("doc_id", id), zap.Bool("file_replaced", file != nil))
+	return &d, nil
+}
+
+// ListScanTargets — файлы документов для повторной проверки; onlyUnchecked — только без результата
+// (загружены до появления проверки, при выключенной проверке или с ошибкой).
+func (r *DocumentRepository) ListScanTargets(ctx context.Context, onlyUnchecked bool) ([]models.DocumentScanTarget, error) {
+	log := logger.WithCtx(ctx)
+
+	query := `SELECT id, filepath FROM documents WHERE deleted_at IS NULL`
+	if onlyUnchecked {
+		query += ` AND (scan_status IS NULL OR scan_status IN ('skipped', 'error'))`
+	}
+	query += ` ORDER BY id`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		log.Error("document repo: list scan targets failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.DocumentScanTarget
+	for rows.Next() {
+		var t models.DocumentScanTarget
+		if err := rows.Scan(&t.ID, &t.Filepath); err != nil {
+			log.Error("document repo: scan targets row failed", zap.Error(err))
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// SetScanResult — результат антивирусной проверки файла документа.
+func (r *DocumentRepository) SetScanResult(ctx context.Context, id int, status string, signature *string) error {
+	const query = `UPDATE documents SET scan_status = $2, scan_signature = $3, scanned_at = now() WHERE id = $1`
+	if _, err := r.db.Exec(ctx, query, id, status, signature); err != nil {
+		logger.WithCtx(ctx).Error("document repo: set scan result failed", zap.Int("doc_id", id), zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// duplicateGroupsCTE — группы документов (включая корзину) с одинаковым SHA-256.
+const duplicateGroupsCTE = `
+	WITH g AS (
+		SELECT sha256, MAX(size_bytes) AS size_bytes, COUNT(DISTINCT filepath) AS files
+		FROM documents
+		WHERE sha256 IS NOT NULL
+		GROUP BY sha256
+		HAVING COUNT(*) > 1
+	)`
+
+// FindByHash — документы (не в корзине) с файлом того же содержимого, ранние сверху.
+func (r *DocumentRepository) FindByHash(ctx context.Context, sha256 string) ([]models.DocumentDuplicate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, COALESCE(NULLIF(title, ''), filename), filename, filepath, uploaded_at
+		FROM documents
+		WHERE sha256 = $1 AND deleted_at IS NULL
+		ORDER BY uploaded_at, id`, sha256)
+	if err != nil {
+		logger.WithCtx(ctx).Error("document repo: find by hash failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.DocumentDuplicate
+	for rows.Next() {
+		var d models.DocumentDuplicate
+		if err := rows.Scan(&d.ID, &d.Title, &d.Filename, &d.Filepath, &d.UploadedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// DuplicateGroups — страница групп дубликатов (больше всего лишнего места сверху),
+// число групп и место под лишние копии по всем группам.
+func (r *DocumentRepository) DuplicateGroups(ctx context.Context, limit, offset int) ([]models.DocumentDuplicateGroup, int, int64, error) {
+	log := logger.WithCtx(ctx)
+
+	var (
+		total  int
+		wasted int64
+	)
+	if err := r.db.QueryRow(ctx, duplicateGroupsCTE+`
+		SELECT COUNT(*), COALESCE(SUM(COALESCE(size_bytes, 0) * (files - 1)), 0)::bigint FROM g`,
+	).Scan(&total, &wasted); err != nil {
+		log.Error("document repo: duplicate totals failed", zap.Error(err))
+		return nil, 0, 0, err
+	}
+
+	rows, err := r.db.Query(ctx, duplicateGroupsCTE+`
+		SELECT sha256, COALESCE(size_bytes, 0), files, COALESCE(size_bytes, 0) * (files - 1) AS wasted
+		FROM g
+		ORDER BY wasted DESC, sha256
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		log.Error("document repo: duplicate groups failed", zap.Error(err))
+		return nil, 0, 0, err
+	}
+	defer rows.Close()
+
+	groups := []models.DocumentDuplicateGroup{}
+	idx := map[string]int{}
+	var hashes []string
+	for rows.Next() {
+		var g models.DocumentDuplicateGroup
+		if err := rows.Scan(&g.SHA256, &g.SizeBytes, &g.Files, &g.WastedBytes); err != nil {
+			return nil, 0, 0, err
+		}
+		g.Documents = []models.DocumentDuplicate{}
+		idx[g.SHA256] = len(groups)
+		groups = append(groups, g)
+		hashes = append(hashes, g.SHA256)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, 0, err
+	}
+	if len(hashes) == 0 {
+		return groups, total, wasted, nil
+	}
+
+	docs, err := r.db.Query(ctx, `
+		SELECT sha256, id, COALESCE(NULLIF(title, ''), filename), filename, filepath, uploaded_at, deleted_at IS NOT NULL
+		FROM documents
+		WHERE sha256 = ANY($1)
+		ORDER BY uploaded_at, id`, hashes)
+	if err != nil {
+		log.Error("document repo: duplicate documents failed", zap.Error(err))
+		return nil, 0, 0, err
+	}
+	defer docs.Close()
+
+	seen := map[string]bool{} // файл уже встречался у более раннего документа — этот ссылается на него
+	for docs.Next() {
+		var (
+			sha string
+			d   models.DocumentDuplicate
+		)
+		if err := docs.Scan(&sha, &d.ID, &d.Title, &d.Filename, &d.Filepath, &d.UploadedAt, &d.InTrash); err != nil {
+			return nil, 0, 0, err
+		}
+		d.Linked = seen[d.Filepath]
+		seen[d.Filepath] = true
+		g := &groups[idx[sha]]
+		g.Documents = append(g.Documents, d)
+	}
+	return groups, total, wasted, docs.Err()
+}
+
+// FileInUse — есть ли документ (включая корзину) с этим файлом.
+func (r *DocumentRepository) FileInUse(ctx context.Context, path string) (bool, error) {
+	var used bool
+	err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM documents WHERE filepath = $1)`, path).Scan(&used)
+	if err != nil {
+		logger.WithCtx(ctx).Error("document repo: file in use check failed", zap.Error(err))
+	}
+	return used, err
+}
+
+// WithoutHash — документы, у файла которых ещё не посчитан SHA-256, по возрастанию id после afterID.
+func (r *DocumentRepository) WithoutHash(ctx context.Context, afterID, limit int) ([]models.DocumentScanTarget, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, filepath FROM documents
+		WHERE sha256 IS NULL AND id > $1
+		ORDER BY id
+		LIMIT $2`, afterID, limit)
+	if err != nil {
+		logger.WithCtx(ctx).Error("document repo: without hash failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.DocumentScanTarget
+	for rows.Next() {
+		var t models.DocumentScanTarget
+		if err := rows.Scan(&t.ID, &t.Filepath); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// SetHash — SHA-256 и размер файла документа.
+func (r *DocumentRepository) SetHash(ctx context.Context, id int, sha256 string, size int64) error {
+	if _, err := r.db.Exec(ctx, `UPDATE documents SET sha256 = $2, size_bytes = $3 WHERE id = $1`, id, sha256, size); err != nil {
+		logger.WithCtx(ctx).Error("document repo: set hash failed", zap.Int("doc_id", id), zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// DocumentGrantRepository — персональный доступ пользователей к документам (document_grants).
+type DocumentGrantRepository struct {
+	db DB
+}
+
+func NewDocumentGrantRepository(db DB) *DocumentGrantRepository {
+	return &DocumentGrantRepository{db: db}
+}
+
+// Upsert — выдаёт доступ или меняет срок и заметку уже выданного; pgx.ErrNoRows — пользователя нет.
+func (r *DocumentGrantRepository) Upsert(ctx context.Context, g *models.DocumentGrant) error {
+	log := logger.WithCtx(ctx)
+
+	err := r.db.QueryRow(ctx, `
+		WITH g AS (
+			INSERT INTO document_grants (document_id, user_id, granted_by, note, expires_at)
+			SELECT $1, u.id, $3, $4, $5 FROM users u WHERE u.id = $2
+			ON CONFLICT (document_id, user_id) DO UPDATE
+			SET granted_by = EXCLUDED.granted_by, note = EXCLUDED.note, expires_at = EXCLUDED.expires_at
+			RETURNING id, user_id, created_at
+		)
+		SELECT g.id, g.created_at, u.email, u.full_name
+		FROM g JOIN users u ON u.id = g.user_id`,
+		g.DocumentID, g.UserID, g.GrantedBy, g.Note, g.ExpiresAt,
+	).Scan(&g.ID, &g.CreatedAt, &g.Email, &g.FullName)
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("document grants repo: upsert failed", zap.Error(err),
+				zap.Int("doc_id", g.DocumentID), zap.Int("user_id", g.UserID))
+		}
+		return err
+	}
+
+	log.Info("document grants repo: granted", zap.Int64("id", g.ID),
+		zap.Int("doc_id", g.DocumentID), zap.Int("user_id", g.UserID), zap.Any("expires_at", g.ExpiresAt))
+	return nil
+}
+
+// Delete — отзывает доступ пользователя к документу; pgx.ErrNoRows, если его не было.
+func (r *DocumentGrantRepository) Delete(ctx context.Context, documentID, userID int) error {
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM document_grants WHERE document_id = $1 AND user_id = $2`, documentID, userID)
+	if err != nil {
+		logger.WithCtx(ctx).Error("document grants repo: delete failed", zap.Error(err),
+			zap.Int("doc_id", documentID), zap.Int("user_id", userID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// List — все выданные доступы к документу (и истёкшие), новые сверху.
+func (r *DocumentGrantRepository) List(ctx context.Context, documentID int) ([]models.DocumentGrant, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT g.id, g.document_id, g.user_id, u.email, u.full_name,
+		       g.granted_by, g.note, g.expires_at, g.created_at
+		FROM document_grants g
+		JOIN users u ON u.id = g.user_id
+		WHERE g.document_id = $1
+		ORDER BY g.created_at DESC, g.id DESC`, documentID)
+	if err != nil {
+		logger.WithCtx(ctx).Error("document grants repo: list failed", zap.Error(err), zap.Int("doc_id", documentID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.DocumentGrant{}
+	for rows.Next() {
+		var g models.DocumentGrant
+		if err := rows.Scan(&g.ID, &g.DocumentID, &g.UserID, &g.Email, &g.FullName,
+			&g.GrantedBy, &g.Note, &g.ExpiresAt, &g.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
+
+// Has — есть ли у пользователя действующий (не истёкший) доступ к документу.
+func (r *DocumentGrantRepository) Has(ctx context.Context, documentID, userID int) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM document_grants
+			WHERE document_id = $1 AND user_id = $2 AND (expires_at IS NULL OR expires_at > NOW())
+		)`, documentID, userID).Scan(&ok)
+	if err != nil {
+		logger.WithCtx(ctx).Error("document grants repo: check failed", zap.Error(err),
+			zap.Int("doc_id", documentID), zap.Int("user_id", userID))
+	}
+	return ok, err
+}
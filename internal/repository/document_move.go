@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// MoveDocuments — переносит документы ids и все документы раздела fromSectionID (если задан)
+// в раздел sectionID (nil — без раздела) одной транзакцией. Возвращает прежний раздел каждого
+// перенесённого документа; ID, которых нет, в результат не попадают.
+// pgx.ErrNoRows — раздела sectionID нет.
+func (r *DocumentRepository) MoveDocuments(ctx context.Context, ids []int, fromSectionID, sectionID *int) (map[int]*int, error) {
+	log := logger.WithCtx(ctx)
+
+	moved := map[int]*int{}
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		if sectionID != nil {
+			// FOR SHARE — раздел не удалят, пока переносим в него
+			var id int
+			if err := tx.QueryRow(ctx, `SELECT id FROM sections WHERE id = $1 FOR SHARE`, *sectionID).Scan(&id); err != nil {
+				return err
+			}
+		}
+
+		rows, err := tx.Query(ctx, `
+			UPDATE documents d SET section_id = $3
+			FROM (
+				SELECT id, section_id FROM documents
+				WHERE deleted_at IS NULL AND (id = ANY($1) OR ($2::int IS NOT NULL AND section_id = $2))
+				FOR UPDATE
+			) prev
+			WHERE d.id = prev.id
+			RETURNING d.id, prev.section_id`,
+			ids, fromSectionID, sectionID,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id int
+			var prev *int
+			if err := rows.Scan(&id, &prev); err != nil {
+				return err
+			}
+			moved[id] = prev
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("document repo: move documents failed", zap.Error(err), zap.Int("count", len(ids)), zap.Any("section_id", sectionID))
+		}
+		return nil, err
+	}
+
+	log.Info("document repo: documents moved", zap.Int("moved", len(moved)), zap.Any("from_section_id", fromSectionID), zap.Any("section_id", sectionID))
+	return moved, nil
+}
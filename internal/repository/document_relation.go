@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// DocumentRelationRepository — типизированные связи между документами (document_relations).
+type DocumentRelationRepository struct {
+	db DB
+}
+
+func NewDocumentRelationRepository(db DB) *DocumentRelationRepository {
+	return &DocumentRelationRepository{db: db}
+}
+
+// Create — добавляет связь; pgx.ErrNoRows, если такая уже есть.
+func (r *DocumentRelationRepository) Create(ctx context.Context, rel *models.DocumentRelation) error {
+	log := logger.WithCtx(ctx)
+
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO document_relations (document_id, related_id, relation, note)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (document_id, related_id, relation) DO NOTHING
+		RETURNING id, created_at`,
+		rel.DocumentID, rel.RelatedID, rel.Relation, rel.Note,
+	).Scan(&rel.ID, &rel.CreatedAt)
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("document relations repo: create failed", zap.Error(err),
+				zap.Int("doc_id", rel.DocumentID), zap.Int("related_id", rel.RelatedID))
+		}
+		return err
+	}
+
+	log.Info("document relations repo: created", zap.Int64("id", rel.ID),
+		zap.Int("doc_id", rel.DocumentID), zap.Int("related_id", rel.RelatedID), zap.String("relation", rel.Relation))
+	return nil
+}
+
+// Delete — удаляет связь, если документ участвует в ней с любой стороны; pgx.ErrNoRows, если нет.
+func (r *DocumentRelationRepository) Delete(ctx context.Context, documentID int, relationID int64) error {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM document_relations
+		WHERE id = $1 AND $2 IN (document_id, related_id)`, relationID, documentID)
+	if err != nil {
+		logger.WithCtx(ctx).Error("document relations repo: delete failed", zap.Error(err), zap.Int64("id", relationID))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// LinksFor — связи документов ids с обеих сторон, развёрнутые к каждому документу.
+// publicOnly — только связи с публичными документами (для публичных ответов).
+func (r *DocumentRelationRepository) LinksFor(ctx context.Context, ids []int, publicOnly bool) (map[int][]models.DocumentLink, error) {
+	out := make(map[int][]models.DocumentLink, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	log := logger.WithCtx(ctx)
+
+	rows, err := r.db.Query(ctx, `
+		SELECT r.id, r.document_id, false, r.relation, d.id, COALESCE(d.title, d.filename), d.is_public, r.note
+		FROM document_relations r
+		JOIN documents d ON d.id = r.related_id
+		WHERE r.document_id = ANY($1) AND d.deleted_at IS NULL AND (NOT $2 OR d.is_public)
+		UNION ALL
+		SELECT r.id, r.related_id, true, r.relation, d.id, COALESCE(d.title, d.filename), d.is_public, r.note
+		FROM document_relations r
+		JOIN documents d ON d.id = r.document_id
+		WHERE r.related_id = ANY($1) AND d.deleted_at IS NULL AND (NOT $2 OR d.is_public)
+		ORDER BY 1`, ids, publicOnly)
+	if err != nil {
+		log.Error("document relations repo: links failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			owner    int
+			incoming bool
+			l        models.DocumentLink
+		)
+		if err := rows.Scan(&l.RelationID, &owner, &incoming, &l.Relation, &l.DocumentID, &l.Title, &l.IsPublic, &l.Note); err != nil {
+			log.Error("document relations repo: scan failed", zap.Error(err))
+			return nil, err
+		}
+		if incoming {
+			l.Relation = models.InverseDocumentRelation(l.Relation)
+		}
+		l.Title = strings.TrimSpace(l.Title)
+		out[owner] = append(out[owner], l)
+	}
+	return out, rows.Err()
+}
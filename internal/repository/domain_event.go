@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"go.uber.org/zap"
+)
+
+type DomainEventRepository struct {
+	db DB
+}
+
+func NewDomainEventRepository(db DB) *DomainEventRepository {
+	return &DomainEventRepository{db: db}
+}
+
+// Insert — сохраняет событие; повторная запись с тем же id игнорируется.
+func (r *DomainEventRepository) Insert(ctx context.Context, ev models.DomainEvent) error {
+	log := logger.WithCtx(ctx)
+
+	payload := ev.Payload
+	if payload == nil {
+		payload = map[string]any{}
+	}
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO domain_events (id, type, user_id, request_id, payload, occurred_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
+		ON CONFLICT (id) DO NOTHING`,
+		ev.ID, ev.Type, ev.UserID, ev.RequestID, payload, ev.OccurredAt,
+	); err != nil {
+		log.Error("domain event repo: insert failed", zap.Error(err), zap.String("type", ev.Type))
+		return err
+	}
+	return nil
+}
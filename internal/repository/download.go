@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"go.uber.org/zap"
+)
+
+type DownloadRepository struct {
+	db DB
+}
+
+func NewDownloadRepository(db DB) *DownloadRepository {
+	return &DownloadRepository{db: db}
+}
+
+// Record — фиксирует факт скачивания документа.
+func (r *DownloadRepository) Record(ctx context.Context, documentID, userID int) error {
+	log := logger.WithCtx(ctx)
+
+	if _, err := r.db.Exec(ctx,
+		`INSERT INTO document_downloads (document_id, user_id) VALUES ($1, NULLIF($2, 0))`,
+		documentID, userID,
+	); err != nil {
+		log.Error("download repo: record failed", zap.Error(err), zap.Int("doc_id", documentID), zap.Int("user_id", userID))
+		return err
+	}
+	return nil
+}
+
+func downloadsWhere(f models.DownloadStatsFilter) (string, []any) {
+	where := " WHERE 1=1"
+	args := []any{}
+	argn := 1
+
+	if f.DocumentID != nil {
+		where += fmt.Sprintf(" AND dd.document_id = $%d", argn)
+		args = append(args, *f.DocumentID)
+		argn++
+	}
+	if f.From != nil {
+		where += fmt.Sprintf(" AND dd.downloaded_at >= $%d", argn)
+		args = append(args, *f.From)
+		argn++
+	}
+	if f.To != nil {
+		where += fmt.Sprintf(" AND dd.downloaded_at < $%d", argn)
+		args = append(args, *f.To)
+	}
+	return where, args
+}
+
+// PerDocument — количество скачиваний по документам (популярные сверху) и общее число документов.
+func (r *DownloadRepository) PerDocument(ctx context.Context, f models.DownloadStatsFilter, limit, offset int) ([]models.DocumentDownloadStat, int, error) {
+	log := logger.WithCtx(ctx)
+
+	where, whereArgs := downloadsWhere(f)
+
+	var total int
+	if err := r.db.QueryRow(ctx,
+		`SELECT COUNT(DISTINCT dd.document_id) FROM document_downloads dd`+where, whereArgs...,
+	).Scan(&total); err != nil {
+		log.Error("download repo: count per document failed", zap.Error(err))
+		return nil, 0, err
+	}
+
+	argn := len(whereArgs) + 1
+	q := `
+		SELECT dd.document_id,
+		       COALESCE(NULLIF(d.title, ''), d.filename),
+		       COUNT(*),
+		       COUNT(DISTINCT dd.user_id),
+		       MAX(dd.downloaded_at)
+		FROM document_downloads dd
+		JOIN documents d ON d.id = dd.document_id` + where + fmt.Sprintf(`
+		GROUP BY dd.document_id, d.title, d.filename
+		ORDER BY COUNT(*) DESC, dd.document_id
+		LIMIT $%d OFFSET $%d`, argn, argn+1)
+	args := append(append([]any{}, whereArgs...), limit, offset)
+
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		log.Error("download repo: per document failed", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]models.DocumentDownloadStat, 0, limit)
+	for rows.Next() {
+		var s models.DocumentDownloadStat
+		if err := rows.Scan(&s.DocumentID, &s.Title, &s.Downloads, &s.UniqueUsers, &s.LastDownloadAt); err != nil {
+			log.Error("download repo: scan per document failed", zap.Error(err))
+			return nil, 0, err
+		}
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("download repo: rows error", zap.Error(err))
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+// Top — самые скачиваемые документы за период.
+func (r *DownloadRepository) Top(ctx context.Context, f models.DownloadStatsFilter, limit int) ([]models.DocumentDownloadStat, error) {
+	items, _, err := r.PerDocument(ctx, f, limit, 0)
+	return items, err
+}
+
+// DailySeries — скачивания по дням (дни без скачиваний не возвращаются).
+func (r *DownloadRepository) DailySeries(ctx context.Context, f models.DownloadStatsFilter) ([]models.DownloadsDayPoint, error) {
+	log := logger.WithCtx(ctx)
+
+	where, args := downloadsWhere(f)
+	q := `
+		SELECT to_char(date_trunc('day', dd.downloaded_at), 'YYYY-MM-DD') AS day,
+		       COUNT(*),
+		       COUNT(DISTINCT dd.user_id)
+		FROM document_downloads dd` + where + `
+		GROUP BY day
+		ORDER BY day`
+
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		log.Error("download repo: daily series failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.DownloadsDayPoint{}
+	for rows.Next() {
+		var p models.DownloadsDayPoint
+		if err := rows.Scan(&p.Day, &p.Downloads, &p.UniqueUsers); err != nil {
+			log.Error("download repo: scan daily failed", zap.Error(err))
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// ListByUser — все скачивания пользователя (новые сверху).
+func (r *DownloadRepository) ListByUser(ctx context.Context, userID int) ([]models.UserDownload, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT dd.document_id, COALESCE(NULLIF(d.title, ''), d.filename), dd.downloaded_at
+		FROM document_downloads dd
+		JOIN documents d ON d.id = dd.document_id
+		WHERE dd.user_id = $1
+		ORDER BY dd.downloaded_at DESC`, userID)
+	if err != nil {
+		logger.WithCtx(ctx).Error("download repo: list by user failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.UserDownload{}
+	for rows.Next() {
+		var d models.UserDownload
+		if err := rows.Scan(&d.DocumentID, &d.Title, &d.DownloadedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type DownloadQuotaRepository struct {
+	db DB
+}
+
+func NewDownloadQuotaRepository(db DB) *DownloadQuotaRepository {
+	return &DownloadQuotaRepository{db: db}
+}
+
+// State — использовано за день, план последнего успешного платежа и индивидуальный лимит.
+func (r *DownloadQuotaRepository) State(ctx context.Context, userID int, day time.Time) (*models.DownloadQuotaState, error) {
+	const query = `
+		SELECT COALESCE((SELECT used FROM download_quota_usage WHERE user_id = $1 AND day = $2), 0),
+		       COALESCE((SELECT plan FROM payments
+		                 WHERE user_id = $1 AND status = 'succeeded'
+		                 ORDER BY paid_at DESC NULLS LAST, id DESC LIMIT 1), ''),
+		       o.user_id IS NOT NULL,
+		       o.daily_limit
+		FROM (SELECT 1) one
+		LEFT JOIN download_quota_overrides o ON o.user_id = $1
+	`
+
+	var s models.DownloadQuotaState
+	if err := r.db.QueryRow(ctx, query, userID, day).Scan(&s.Used, &s.Plan, &s.HasOverride, &s.OverrideLimit); err != nil {
+		logger.WithCtx(ctx).Error("download quota repo: state failed", zap.Int("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Consume — учитывает скачивание, если за день использовано меньше limit (nil — без ограничения).
+// Возвращает счётчик после учёта; ok=false — лимит исчерпан, счётчик не изменён.
+func (r *DownloadQuotaRepository) Consume(ctx context.Context, userID int, day time.Time, limit *int) (used int, ok bool, err error) {
+	// проверка и увеличение — одним оператором: параллельные скачивания не превысят лимит
+	const query = `
+		INSERT INTO download_quota_usage (user_id, day, used) VALUES ($1, $2, 1)
+		ON CONFLICT (user_id, day) DO UPDATE SET used = download_quota_usage.used + 1
+		WHERE $3::int IS NULL OR download_quota_usage.used < $3
+		RETURNING used
+	`
+
+	err = r.db.QueryRow(ctx, query, userID, day, limit).Scan(&used)
+	if err == pgx.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		logger.WithCtx(ctx).Error("download quota repo: consume failed", zap.Int("user_id", userID), zap.Error(err))
+		return 0, false, err
+	}
+	return used, true, nil
+}
+
+// DownloadedSince — скачивал ли пользователь документ начиная с since (докачка не тратит лимит).
+func (r *DownloadQuotaRepository) DownloadedSince(ctx context.Context, userID, documentID int, since time.Time) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM document_downloads
+			WHERE user_id = $1 AND document_id = $2 AND downloaded_at >= $3
+		)`, userID, documentID, since).Scan(&exists)
+	if err != nil {
+		logger.WithCtx(ctx).Error("download quota repo: downloaded since failed", zap.Int("user_id", userID), zap.Error(err))
+	}
+	return exists, err
+}
+
+// SetOverride — индивидуальный лимит пользователя (nil — без ограничения).
+func (r *DownloadQuotaRepository) SetOverride(ctx context.Context, userID int, limit *int, adminID int) error {
+	log := logger.WithCtx(ctx)
+
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO download_quota_overrides (user_id, daily_limit, updated_by, updated_at)
+		VALUES ($1, $2, NULLIF($3, 0), now())
+		ON CONFLICT (user_id) DO UPDATE SET
+			daily_limit = EXCLUDED.daily_limit,
+			updated_by  = EXCLUDED.updated_by,
+			updated_at  = EXCLUDED.updated_at`,
+		userID, limit, adminID,
+	); err != nil {
+		log.Error("download quota repo: set override failed", zap.Int("user_id", userID), zap.Error(err))
+		return err
+	}
+
+	log.Info("download quota repo: override set", zap.Int("user_id", userID), zap.Any("daily_limit", limit), zap.Int("admin_id", adminID))
+	return nil
+}
+
+// DeleteOverride — вернуть пользователю тарифный лимит; false — индивидуального лимита не было.
+func (r *DownloadQuotaRepository) DeleteOverride(ctx context.Context, userID int) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM download_quota_overrides WHERE user_id = $1`, userID)
+	if err != nil {
+		logger.WithCtx(ctx).Error("download quota repo: delete override failed", zap.Int("user_id", userID), zap.Error(err))
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type EmailChangeRepository struct {
+	db DB
+}
+
+func NewEmailChangeRepository(db DB) *EmailChangeRepository {
+	return &EmailChangeRepository{db: db}
+}
+
+// Create — новый запрос на смену email; прежние неподтверждённые запросы пользователя удаляются
+// (действует только ссылка из последнего письма).
+func (r *EmailChangeRepository) Create(ctx context.Context, userID int, newEmail, tokenHash string, expiresAt time.Time) (*models.EmailChangeRequest, error) {
+	log := logger.WithCtx(ctx)
+
+	req := models.EmailChangeRequest{UserID: userID, NewEmail: newEmail, TokenHash: tokenHash, ExpiresAt: expiresAt}
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM email_change_requests WHERE user_id = $1 AND confirmed_at IS NULL`, userID); err != nil {
+			return err
+		}
+		const q = `
+			INSERT INTO email_change_requests (user_id, new_email, token_hash, expires_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at
+		`
+		return tx.QueryRow(ctx, q, userID, newEmail, tokenHash, expiresAt).Scan(&req.ID, &req.CreatedAt)
+	})
+	if err != nil {
+		log.Error("email change repo: create failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, err
+	}
+
+	log.Info("email change repo: request created", zap.Int64("id", req.ID), zap.Int("user_id", userID))
+	return &req, nil
+}
+
+// Pending — действующий неподтверждённый запрос пользователя (pgx.ErrNoRows — нет).
+func (r *EmailChangeRepository) Pending(ctx context.Context, userID int) (*models.EmailChangeRequest, error) {
+	const q = `
+		SELECT id, user_id, new_email, expires_at, created_at
+		FROM email_change_requests
+		WHERE user_id = $1 AND confirmed_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	var req models.EmailChangeRequest
+	if err := r.db.QueryRow(ctx, q, userID).Scan(&req.ID, &req.UserID, &req.NewEmail, &req.ExpiresAt, &req.CreatedAt); err != nil {
+		if err != pgx.ErrNoRows {
+			logger.WithCtx(ctx).Error("email change repo: get pending failed", zap.Error(err), zap.Int("user_id", userID))
+		}
+		return nil, err
+	}
+	return &req, nil
+}
+
+// Confirm — по хэшу действующего токена меняет email пользователя (адрес сразу считается
+// подтверждённым) и закрывает запрос; всё в одной транзакции. pgx.ErrNoRows — токен неверный,
+// использованный или истёкший; нарушение уникальности — адрес успели занять.
+func (r *EmailChangeRepository) Confirm(ctx context.Context, tokenHash string) (*models.EmailChangeRequest, error) {
+	log := logger.WithCtx(ctx)
+
+	var req models.EmailChangeRequest
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		const sel = `
+			SELECT id, user_id, new_email, expires_at, created_at
+			FROM email_change_requests
+			WHERE token_hash = $1 AND confirmed_at IS NULL AND expires_at > NOW()
+			FOR UPDATE
+		`
+		if err := tx.QueryRow(ctx, sel, tokenHash).Scan(&req.ID, &req.UserID, &req.NewEmail, &req.ExpiresAt, &req.CreatedAt); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `UPDATE users SET email = $1, email_verified = true WHERE id = $2`, req.NewEmail, req.UserID); err != nil {
+			return err
+		}
+		now := time.Now()
+		req.ConfirmedAt = &now
+		if _, err := tx.Exec(ctx, `UPDATE email_change_requests SET confirmed_at = $2 WHERE id = $1`, req.ID, now); err != nil {
+			return err
+		}
+		// ссылки подтверждения, выданные для прежнего адреса, больше не нужны
+		_, err := tx.Exec(ctx, `DELETE FROM email_verification_tokens WHERE user_id = $1 AND confirmed = false`, req.UserID)
+		return err
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			log.Warn("email change repo: valid token not found")
+		} else {
+			log.Error("email change repo: confirm failed", zap.Error(err))
+		}
+		return nil, err
+	}
+
+	log.Info("email change repo: email changed", zap.Int("user_id", req.UserID), zap.Int64("id", req.ID))
+	return &req, nil
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type EmailOutboxRepository struct {
+	db DB
+}
+
+func NewEmailOutboxRepository(db DB) *EmailOutboxRepository {
+	return &EmailOutboxRepository{db: db}
+}
+
+const emailOutboxColumns = `id, recipients, subject, body, is_html, status, attempts, last_error,
+	next_attempt_at, sent_at, created_at, updated_at, campaign_id`
+
+func scanEmailOutbox(row pgx.Row) (*models.EmailOutbox, error) {
+	var m models.EmailOutbox
+	if err := row.Scan(
+		&m.ID, &m.Recipients, &m.Subject, &m.Body, &m.IsHTML, &m.Status, &m.Attempts, &m.LastError,
+		&m.NextAttemptAt, &m.SentAt, &m.CreatedAt, &m.UpdatedAt, &m.CampaignID,
+	); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Enqueue — сохраняет письмо в outbox со статусом pending.
+func (r *EmailOutboxRepository) Enqueue(ctx context.Context, to []string, subject, body string, isHTML bool) (int64, error) {
+	return r.EnqueueCampaign(ctx, nil, to, subject, body, isHTML)
+}
+
+// EnqueueCampaign — как Enqueue, с привязкой письма к рассылке администратора (nil — без неё).
+func (r *EmailOutboxRepository) EnqueueCampaign(ctx context.Context, campaignID *int64, to []string, subject, body string, isHTML bool) (int64, error) {
+	log := logger.WithCtx(ctx)
+
+	var id int64
+	if err := r.db.QueryRow(ctx,
+		`INSERT INTO email_outbox (recipients, subject, body, is_html, campaign_id) VALUES ($1,$2,$3,$4,$5) RETURNING id`,
+		to, subject, body, isHTML, campaignID,
+	).Scan(&id); err != nil {
+		log.Error("email outbox repo: enqueue failed", zap.Error(err), zap.Int("recipients", len(to)))
+		return 0, err
+	}
+
+	log.Debug("email outbox repo: enqueued", zap.Int64("id", id), zap.Int("recipients", len(to)))
+	return id, nil
+}
+
+// ClaimNext — забирает ближайшее готовое к отправке письмо.
+// Запись остаётся pending, но next_attempt_at сдвигается на lease, чтобы другой воркер её не взял;
+// если процесс упадёт посреди отправки, письмо вернётся в работу по истечении lease.
+func (r *EmailOutboxRepository) ClaimNext(ctx context.Context, lease time.Duration) (*models.EmailOutbox, error) {
+	log := logger.WithCtx(ctx)
+
+	q := `
+		UPDATE email_outbox
+		SET attempts = attempts + 1,
+		    next_attempt_at = now() + $1::interval,
+		    updated_at = now()
+		WHERE id = (
+			SELECT id FROM email_outbox
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + emailOutboxColumns
+
+	m, err := scanEmailOutbox(r.db.QueryRow(ctx, q, lease.String()))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		log.Error("email outbox repo: claim failed", zap.Error(err))
+		return nil, err
+	}
+	return m, nil
+}
+
+// MarkSent — письмо успешно принято SMTP.
+func (r *EmailOutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	log := logger.WithCtx(ctx)
+
+	if _, err := r.db.Exec(ctx,
+		`UPDATE email_outbox SET status='sent', sent_at=now(), last_error=NULL, updated_at=now() WHERE id=$1`, id,
+	); err != nil {
+		log.Error("email outbox repo: mark sent failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return nil
+}
+
+// MarkRetry — временная ошибка, повторим после nextAttempt.
+func (r *EmailOutboxRepository) MarkRetry(ctx context.Context, id int64, errMsg string, nextAttempt time.Time) error {
+	log := logger.WithCtx(ctx)
+
+	if _, err := r.db.Exec(ctx,
+		`UPDATE email_outbox SET status='pending', last_error=$2, next_attempt_at=$3, updated_at=now() WHERE id=$1`,
+		id, errMsg, nextAttempt,
+	); err != nil {
+		log.Error("email outbox repo: mark retry failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return nil
+}
+
+// MarkFailed — окончательная ошибка, письмо ждёт ручного requeue.
+func (r *EmailOutboxRepository) MarkFailed(ctx context.Context, id int64, errMsg string) error {
+	log := logger.WithCtx(ctx)
+
+	if _, err := r.db.Exec(ctx,
+		`UPDATE email_outbox SET status='failed', last_error=$2, updated_at=now() WHERE id=$1`,
+		id, errMsg,
+	); err != nil {
+		log.Error("email outbox repo: mark failed failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return nil
+}
+
+// CountPending — глубина очереди: письма, ожидающие отправки (включая ретраи).
+func (r *EmailOutboxRepository) CountPending(ctx context.Context) (int, error) {
+	var n int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM email_outbox WHERE status = 'pending'`).Scan(&n); err != nil {
+		logger.WithCtx(ctx).Error("email outbox repo: count pending failed", zap.Error(err))
+		return 0, err
+	}
+	return n, nil
+}
+
+// ListByStatus — письма с указанным статусом (новые сверху) и общее количество.
+func (r *EmailOutboxRepository) ListByStatus(ctx context.Context, status string, limit, offset int) ([]models.EmailOutbox, int, error) {
+	return r.List(ctx, models.EmailOutboxFilter{Status: status}, limit, offset)
+}
+
+// List — история писем с фильтрами (новые сверху) и общее количество.
+// Тело письма в списке не возвращается — его отдаёт GetByID.
+func (r *EmailOutboxRepository) List(ctx context.Context, f models.EmailOutboxFilter, limit, offset int) ([]models.EmailOutbox, int, error) {
+	log := logger.WithCtx(ctx)
+
+	where := " WHERE 1=1"
+	whereArgs := []any{}
+	argn := 1
+
+	if v := strings.ToLower(strings.TrimSpace(f.Recipient)); v != "" {
+		where += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM unnest(recipients) rcpt WHERE lower(rcpt) LIKE $%d)", argn)
+		whereArgs = append(whereArgs, "%"+v+"%")
+		argn++
+	}
+	if v := strings.TrimSpace(f.Status); v != "" {
+		where += fmt.Sprintf(" AND status = $%d", argn)
+		whereArgs = append(whereArgs, v)
+		argn++
+	}
+	if v := strings.TrimSpace(f.Subject); v != "" {
+		where += fmt.Sprintf(" AND subject ILIKE $%d", argn)
+		whereArgs = append(whereArgs, "%"+v+"%")
+		argn++
+	}
+	if f.From != nil {
+		where += fmt.Sprintf(" AND created_at >= $%d", argn)
+		whereArgs = append(whereArgs, *f.From)
+		argn++
+	}
+	if f.To != nil {
+		where += fmt.Sprintf(" AND created_at < $%d", argn)
+		whereArgs = append(whereArgs, *f.To)
+		argn++
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM email_outbox`+where, whereArgs...).Scan(&total); err != nil {
+		log.Error("email outbox repo: count failed", zap.Error(err))
+		return nil, 0, err
+	}
+
+	orderPage := fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argn, argn+1)
+	args := append(append([]any{}, whereArgs...), limit, offset)
+
+	rows, err := r.db.Query(ctx, `SELECT `+emailOutboxColumns+` FROM email_outbox`+where+orderPage, args...)
+	if err != nil {
+		log.Error("email outbox repo: list failed", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]models.EmailOutbox, 0, limit)
+	for rows.Next() {
+		m, err := scanEmailOutbox(rows)
+		if err != nil {
+			log.Error("email outbox repo: scan failed", zap.Error(err))
+			return nil, 0, err
+		}
+		m.Body = ""
+		out = append(out, *m)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("email outbox repo: rows error", zap.Error(err))
+		return nil, 0, err
+	}
+
+	log.Debug("email outbox repo: list done", zap.Int("count", len(out)), zap.Int("total", total))
+	return out, total, nil
+}
+
+// GetByID — письмо целиком (с телом и последней ошибкой SMTP).
+func (r *EmailOutboxRepository) GetByID(ctx context.Context, id int64) (*models.EmailOutbox, error) {
+	log := logger.WithCtx(ctx)
+
+	m, err := scanEmailOutbox(r.db.QueryRow(ctx, `SELECT `+emailOutboxColumns+` FROM email_outbox WHERE id=$1`, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			log.Warn("email outbox repo: not found", zap.Int64("id", id))
+		} else {
+			log.Error("email outbox repo: get by id failed", zap.Error(err), zap.Int64("id", id))
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// Requeue — возвращает failed-письмо в очередь со сбросом счётчика попыток.
+// Возвращает false, если письма нет или оно не в статусе failed.
+func (r *EmailOutboxRepository) Requeue(ctx context.Context, id int64) (bool, error) {
+	log := logger.WithCtx(ctx)
+
+	tag, err := r.db.Exec(ctx,
+		`UPDATE email_outbox SET status='pending', attempts=0, next_attempt_at=now(), updated_at=now()
+		 WHERE id=$1 AND status='failed'`, id,
+	)
+	if err != nil {
+		log.Error("email outbox repo: requeue failed", zap.Error(err), zap.Int64("id", id))
+		return false, err
+	}
+
+	ok := tag.RowsAffected() > 0
+	log.Info("email outbox repo: requeue", zap.Int64("id", id), zap.Bool("requeued", ok))
+	return ok, nil
+}
@@ -2,27 +2,32 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"edutalks/internal/logger"
 	"edutalks/internal/models"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
 type EmailTokenRepository struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewEmailTokenRepository(db *pgxpool.Pool) *EmailTokenRepository {
+func NewEmailTokenRepository(db DB) *EmailTokenRepository {
 	return &EmailTokenRepository{db: db}
 }
 
-// SaveToken — сохраняет (или заменяет) токен подтверждения email для пользователя.
-func (r *EmailTokenRepository) SaveToken(ctx context.Context, token *models.EmailVerificationToken) error {
+// SaveToken — сохраняет новый токен подтверждения email.
+// У пользователя остаётся не больше maxOutstanding неподтверждённых токенов: самые старые удаляются.
+func (r *EmailTokenRepository) SaveToken(ctx context.Context, token *models.EmailVerificationToken, maxOutstanding int) error {
 	log := logger.WithCtx(ctx)
 
+	if maxOutstanding < 1 {
+		maxOutstanding = 1
+	}
+
 	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		log.Error("email token repo: begin tx failed", zap.Error(err))
@@ -30,10 +35,17 @@ func (r *EmailTokenRepository) SaveToken(ctx context.Context, token *models.Emai
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
-	if _, err := tx.Exec(ctx,
-		`DELETE FROM email_verification_tokens WHERE user_id = $1`,
-		token.UserID,
-	); err != nil {
+	// оставляем место под новый токен: сохраняем maxOutstanding-1 самых свежих
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM email_verification_tokens
+		WHERE user_id = $1
+		  AND id NOT IN (
+			SELECT id FROM email_verification_tokens
+			WHERE user_id = $1 AND confirmed = false
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2
+		  )
+	`, token.UserID, maxOutstanding-1); err != nil {
 		log.Error("email token repo: delete old tokens failed", zap.Error(err), zap.Int("user_id", token.UserID))
 		return err
 	}
@@ -103,6 +115,48 @@ func (r *EmailTokenRepository) MarkConfirmed(ctx context.Context, token string)
 	return nil
 }
 
+// DeleteOutstandingByUserID — удаляет оставшиеся неподтверждённые токены пользователя
+// (после подтверждения адреса они больше не нужны).
+func (r *EmailTokenRepository) DeleteOutstandingByUserID(ctx context.Context, userID int) error {
+	log := logger.WithCtx(ctx)
+
+	if _, err := r.db.Exec(ctx,
+		`DELETE FROM email_verification_tokens WHERE user_id = $1 AND confirmed = false`, userID,
+	); err != nil {
+		log.Error("email token repo: delete outstanding failed", zap.Error(err), zap.Int("user_id", userID))
+		return err
+	}
+	return nil
+}
+
+// Cleanup — удаляет истёкшие неподтверждённые токены и подтверждённые старше keepConfirmed.
+func (r *EmailTokenRepository) Cleanup(ctx context.Context, keepConfirmed time.Duration) (expired, confirmed int64, err error) {
+	log := logger.WithCtx(ctx)
+
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM email_verification_tokens
+		WHERE confirmed = false AND expires_at < (NOW() AT TIME ZONE 'UTC')
+	`)
+	if err != nil {
+		log.Error("email token repo: cleanup expired failed", zap.Error(err))
+		return 0, 0, err
+	}
+	expired = tag.RowsAffected()
+
+	tag, err = r.db.Exec(ctx, `
+		DELETE FROM email_verification_tokens
+		WHERE confirmed = true AND created_at < (NOW() AT TIME ZONE 'UTC') - $1::interval
+	`, keepConfirmed.String())
+	if err != nil {
+		log.Error("email token repo: cleanup confirmed failed", zap.Error(err))
+		return expired, 0, err
+	}
+	confirmed = tag.RowsAffected()
+
+	log.Info("email token repo: cleanup done", zap.Int64("expired", expired), zap.Int64("confirmed", confirmed))
+	return expired, confirmed, nil
+}
+
 // GetLastTokenByUserID — возвращает последний токен пользователя по времени создания.
 func (r *EmailTokenRepository) GetLastTokenByUserID(ctx context.Context, userID int) (*models.EmailVerificationToken, error) {
 	log := logger.WithCtx(ctx)
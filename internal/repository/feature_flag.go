@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type FeatureFlagRepository struct {
+	db DB
+}
+
+func NewFeatureFlagRepository(db DB) *FeatureFlagRepository {
+	return &FeatureFlagRepository{db: db}
+}
+
+const featureFlagColumns = `key, description, enabled, rollout_percent, created_at, updated_at`
+
+func scanFeatureFlag(row pgx.Row) (*models.FeatureFlag, error) {
+	var f models.FeatureFlag
+	if err := row.Scan(&f.Key, &f.Description, &f.Enabled, &f.RolloutPercent, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Create — новый флаг; false — флаг с таким ключом уже есть.
+func (r *FeatureFlagRepository) Create(ctx context.Context, f *models.FeatureFlag) (*models.FeatureFlag, bool, error) {
+	out, err := scanFeatureFlag(r.db.QueryRow(ctx, `
+		INSERT INTO feature_flags (key, description, enabled, rollout_percent)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO NOTHING
+		RETURNING `+featureFlagColumns,
+		f.Key, f.Description, f.Enabled, f.RolloutPercent,
+	))
+	if err == pgx.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		logger.WithCtx(ctx).Error("feature flag repo: create failed", zap.String("key", f.Key), zap.Error(err))
+		return nil, false, err
+	}
+	return out, true, nil
+}
+
+func (r *FeatureFlagRepository) Update(ctx context.Context, f *models.FeatureFlag) (*models.FeatureFlag, error) {
+	out, err := scanFeatureFlag(r.db.QueryRow(ctx, `
+		UPDATE feature_flags
+		SET description = $2, enabled = $3, rollout_percent = $4, updated_at = now()
+		WHERE key = $1
+		RETURNING `+featureFlagColumns,
+		f.Key, f.Description, f.Enabled, f.RolloutPercent,
+	))
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			logger.WithCtx(ctx).Error("feature flag repo: update failed", zap.String("key", f.Key), zap.Error(err))
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *FeatureFlagRepository) Delete(ctx context.Context, key string) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM feature_flags WHERE key = $1`, key)
+	if err != nil {
+		logger.WithCtx(ctx).Error("feature flag repo: delete failed", zap.String("key", key), zap.Error(err))
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// List — все флаги по ключу; их немного, поэтому без пагинации.
+func (r *FeatureFlagRepository) List(ctx context.Context) ([]models.FeatureFlag, error) {
+	log := logger.WithCtx(ctx)
+
+	rows, err := r.db.Query(ctx, `SELECT `+featureFlagColumns+` FROM feature_flags ORDER BY key`)
+	if err != nil {
+		log.Error("feature flag repo: list failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.FeatureFlag{}
+	for rows.Next() {
+		f, err := scanFeatureFlag(rows)
+		if err != nil {
+			log.Error("feature flag repo: scan failed", zap.Error(err))
+			return nil, err
+		}
+		out = append(out, *f)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type ImpersonationRepository struct {
+	db DB
+}
+
+func NewImpersonationRepository(db DB) *ImpersonationRepository {
+	return &ImpersonationRepository{db: db}
+}
+
+// Create — запись в журнал до выдачи токена: без неё токен не выдаётся. В той же транзакции
+// создаётся сессия пользователя, к которой привязывается токен (imp.SessionID).
+func (r *ImpersonationRepository) Create(ctx context.Context, imp *models.Impersonation) error {
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		const session = `
+			INSERT INTO user_sessions (user_id, user_agent, ip, expires_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`
+		if err := tx.QueryRow(ctx, session, imp.UserID, imp.UserAgent, imp.IP, imp.ExpiresAt).Scan(&imp.SessionID); err != nil {
+			return err
+		}
+		const q = `
+			INSERT INTO impersonations (admin_id, user_id, session_id, reason, ip, user_agent, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id, created_at
+		`
+		return tx.QueryRow(ctx, q, imp.AdminID, imp.UserID, imp.SessionID, imp.Reason, imp.IP, imp.UserAgent, imp.ExpiresAt).
+			Scan(&imp.ID, &imp.CreatedAt)
+	})
+	if err != nil {
+		logger.WithCtx(ctx).Error("impersonation repo: create failed",
+			zap.Int("admin_id", imp.AdminID), zap.Int("user_id", imp.UserID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// End — завершает действующий вход id и отзывает его сессию; pgx.ErrNoRows — такого нет
+// (уже завершён или истёк).
+func (r *ImpersonationRepository) End(ctx context.Context, id int64) (*models.Impersonation, error) {
+	log := logger.WithCtx(ctx)
+
+	var imp models.Impersonation
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		const q = `
+			UPDATE impersonations
+			SET ended_at = NOW()
+			WHERE id = $1 AND ended_at IS NULL AND expires_at > NOW()
+			RETURNING id, COALESCE(admin_id, 0), user_id, COALESCE(session_id, 0), expires_at, ended_at
+		`
+		if err := tx.QueryRow(ctx, q, id).
+			Scan(&imp.ID, &imp.AdminID, &imp.UserID, &imp.SessionID, &imp.ExpiresAt, &imp.EndedAt); err != nil {
+			return err
+		}
+		const revoke = `UPDATE user_sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+		_, err := tx.Exec(ctx, revoke, imp.SessionID)
+		return err
+	})
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("impersonation repo: end failed", zap.Int64("id", id), zap.Error(err))
+		}
+		return nil, err
+	}
+
+	log.Info("impersonation repo: impersonation ended", zap.Int64("id", id), zap.Int64("session_id", imp.SessionID))
+	return &imp, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestImpersonationCreate(t *testing.T) {
+	mock := newMock(t)
+	repo := NewImpersonationRepository(mock)
+
+	expires := time.Now().Add(15 * time.Minute)
+	created := time.Now()
+	imp := models.Impersonation{AdminID: 1, UserID: 7, Reason: "#123", IP: "203.0.113.7", UserAgent: "Firefox", ExpiresAt: expires}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(sqlRe("INSERT INTO user_sessions (user_id, user_agent, ip, expires_at)", "RETURNING id")).
+		WithArgs(7, "Firefox", "203.0.113.7", expires).
+		WillReturnRows(mock.NewRows([]string{"id"}).AddRow(int64(55)))
+	mock.ExpectQuery(sqlRe("INSERT INTO impersonations (admin_id, user_id, session_id,", "RETURNING id, created_at")).
+		WithArgs(1, 7, int64(55), "#123", "203.0.113.7", "Firefox", expires).
+		WillReturnRows(mock.NewRows([]string{"id", "created_at"}).AddRow(int64(9), created))
+	mock.ExpectCommit()
+
+	if err := repo.Create(context.Background(), &imp); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if imp.ID != 9 || imp.SessionID != 55 || !imp.CreatedAt.Equal(created) {
+		t.Errorf("запись = %+v", imp)
+	}
+}
+
+func TestImpersonationCreateRollsBack(t *testing.T) {
+	mock := newMock(t)
+	repo := NewImpersonationRepository(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(sqlRe("INSERT INTO user_sessions")).
+		WithArgs(7, "", "", pgxmock.AnyArg()).
+		WillReturnRows(mock.NewRows([]string{"id"}).AddRow(int64(55)))
+	mock.ExpectQuery(sqlRe("INSERT INTO impersonations")).
+		WithArgs(1, 7, int64(55), "", "", "", pgxmock.AnyArg()).
+		WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+
+	// без записи в журнале сессия под токен поддержки не остаётся
+	if err := repo.Create(context.Background(), &models.Impersonation{AdminID: 1, UserID: 7}); err == nil {
+		t.Fatal("ожидалась ошибка")
+	}
+}
+
+func TestImpersonationEnd(t *testing.T) {
+	mock := newMock(t)
+	repo := NewImpersonationRepository(mock)
+
+	expires := time.Now().Add(10 * time.Minute)
+	ended := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(sqlRe("UPDATE impersonations", "SET ended_at = NOW()", "WHERE id = $1 AND ended_at IS NULL AND expires_at > NOW()")).
+		WithArgs(int64(9)).
+		WillReturnRows(mock.NewRows([]string{"id", "admin_id", "user_id", "session_id", "expires_at", "ended_at"}).
+			AddRow(int64(9), 1, 7, int64(55), expires, &ended))
+	mock.ExpectExec(sqlRe("UPDATE user_sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL")).
+		WithArgs(int64(55)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	imp, err := repo.End(context.Background(), 9)
+	if err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	if imp.SessionID != 55 || imp.UserID != 7 || imp.EndedAt == nil {
+		t.Errorf("запись = %+v", imp)
+	}
+}
+
+func TestImpersonationEndNotFound(t *testing.T) {
+	mock := newMock(t)
+	repo := NewImpersonationRepository(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(sqlRe("UPDATE impersonations")).WithArgs(int64(9)).WillReturnError(pgx.ErrNoRows)
+	mock.ExpectRollback()
+
+	if _, err := repo.End(context.Background(), 9); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("err = %v, want pgx.ErrNoRows", err)
+	}
+}
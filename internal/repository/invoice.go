@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type InvoiceRepository struct {
+	db DB
+}
+
+func NewInvoiceRepository(db DB) *InvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+const invoiceColumns = `id, payment_id, user_id, number, issued_at, emailed_at`
+
+func scanInvoice(row pgx.Row) (*models.Invoice, error) {
+	var inv models.Invoice
+	if err := row.Scan(&inv.ID, &inv.PaymentID, &inv.UserID, &inv.Number, &inv.IssuedAt, &inv.EmailedAt); err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// Issue — квитанция по платежу; при первом обращении присваивает номер вида
+// <prefix><год>-<000001>. Повторные вызовы возвращают уже выданную (номер не расходуется).
+func (r *InvoiceRepository) Issue(ctx context.Context, paymentID int64, userID int, prefix string) (*models.Invoice, error) {
+	log := logger.WithCtx(ctx)
+
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO invoices (payment_id, user_id, number)
+		SELECT $1, $2, $3 || to_char(now(), 'YYYY') || '-' || lpad(nextval('invoice_number_seq')::text, 6, '0')
+		WHERE NOT EXISTS (SELECT 1 FROM invoices WHERE payment_id = $1)
+		ON CONFLICT (payment_id) DO NOTHING
+	`, paymentID, userID, prefix)
+	if err != nil {
+		log.Error("invoice repo: issue failed", zap.Error(err), zap.Int64("payment_id", paymentID))
+		return nil, err
+	}
+
+	inv, err := r.GetByPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() > 0 {
+		log.Info("invoice repo: issued", zap.Int64("payment_id", paymentID), zap.String("number", inv.Number))
+	}
+	return inv, nil
+}
+
+// GetByPayment — квитанция платежа; pgx.ErrNoRows, если ещё не выдана.
+func (r *InvoiceRepository) GetByPayment(ctx context.Context, paymentID int64) (*models.Invoice, error) {
+	inv, err := scanInvoice(r.db.QueryRow(ctx,
+		`SELECT `+invoiceColumns+` FROM invoices WHERE payment_id = $1`, paymentID))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.WithCtx(ctx).Error("invoice repo: get by payment failed", zap.Error(err), zap.Int64("payment_id", paymentID))
+	}
+	return inv, err
+}
+
+// MarkEmailed — квитанция отправлена пользователю на почту.
+func (r *InvoiceRepository) MarkEmailed(ctx context.Context, id int64) error {
+	if _, err := r.db.Exec(ctx, `UPDATE invoices SET emailed_at = now() WHERE id = $1`, id); err != nil {
+		logger.WithCtx(ctx).Error("invoice repo: mark emailed failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return nil
+}
+
+// ListByUser — квитанции пользователя (новые сверху).
+func (r *InvoiceRepository) ListByUser(ctx context.Context, userID int) ([]models.Invoice, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+invoiceColumns+` FROM invoices WHERE user_id = $1 ORDER BY issued_at DESC`, userID)
+	if err != nil {
+		logger.WithCtx(ctx).Error("invoice repo: list by user failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.Invoice{}
+	for rows.Next() {
+		inv, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *inv)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"hash/fnv"
+
+	"edutalks/internal/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// JobLockRepository — распределённые блокировки фоновых задач на advisory locks Postgres:
+// при нескольких инстансах сервиса каждый запуск задачи выполняет только один из них.
+// Блокировка сессионная: если инстанс упал или потерял соединение, Postgres снимает её сам.
+type JobLockRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewJobLockRepository(db *pgxpool.Pool) *JobLockRepository {
+	return &JobLockRepository{db: db}
+}
+
+// jobLockKey — ключ advisory lock для имени задачи.
+func jobLockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("edutalks:job:" + name))
+	return int64(h.Sum64())
+}
+
+// TryRun — выполняет fn, если блокировку задачи name удалось взять; иначе задачу сейчас
+// выполняет другой инстанс и TryRun возвращает ran=false без ожидания.
+func (r *JobLockRepository) TryRun(ctx context.Context, name string, fn func(ctx context.Context) error) (ran bool, err error) {
+	log := logger.WithCtx(ctx)
+	key := jobLockKey(name)
+
+	// блокировка живёт в сессии — держим одно соединение до unlock
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		log.Error("job lock repo: acquire conn failed", zap.String("job", name), zap.Error(err))
+		return false, err
+	}
+	defer conn.Release()
+
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&locked); err != nil {
+		log.Error("job lock repo: try lock failed", zap.String("job", name), zap.Error(err))
+		return false, err
+	}
+	if !locked {
+		log.Debug("job lock repo: held by another instance", zap.String("job", name))
+		return false, nil
+	}
+	defer func() {
+		// снимаем и при отменённом ctx задачи, иначе соединение вернётся в пул с блокировкой
+		if _, uerr := conn.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, key); uerr != nil {
+			log.Error("job lock repo: unlock failed", zap.String("job", name), zap.Error(uerr))
+			// соединение с неснятой блокировкой в пул не возвращаем
+			_ = conn.Conn().Close(context.WithoutCancel(ctx))
+		}
+	}()
+
+	return true, fn(ctx)
+}
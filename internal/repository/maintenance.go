@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/db"
+	"edutalks/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// MaintenanceRepository — обслуживание БД: пересборка индексов и статистики.
+type MaintenanceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMaintenanceRepository(db *pgxpool.Pool) *MaintenanceRepository {
+	return &MaintenanceRepository{db: db}
+}
+
+// ReindexTable — REINDEX CONCURRENTLY (таблица доступна на запись всё время) и ANALYZE.
+// table — имя из фиксированного списка сервиса, не из запроса. Без statement_timeout:
+// пересборка большой таблицы идёт дольше таймаута обычных запросов.
+func (r *MaintenanceRepository) ReindexTable(ctx context.Context, table string) error {
+	log := logger.WithCtx(ctx)
+	ident := pgx.Identifier{table}.Sanitize()
+
+	err := db.WithoutStatementTimeout(ctx, r.db, func(conn *pgxpool.Conn) error {
+		if _, err := conn.Exec(ctx, `REINDEX TABLE CONCURRENTLY `+ident); err != nil {
+			log.Error("maintenance repo: reindex failed", zap.String("table", table), zap.Error(err))
+			return err
+		}
+		if _, err := conn.Exec(ctx, `ANALYZE `+ident); err != nil {
+			log.Error("maintenance repo: analyze failed", zap.String("table", table), zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info("maintenance repo: table reindexed", zap.String("table", table))
+	return nil
+}
+
+// Ping — доступность БД.
+func (r *MaintenanceRepository) Ping(ctx context.Context) error {
+	return r.db.Ping(ctx)
+}
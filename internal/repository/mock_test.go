@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+// newMock — pgxmock-пул; ожидаемый текст запроса — регулярное выражение (см. sqlRe).
+// В конце теста проверяется, что все ожидания выполнены.
+func newMock(t *testing.T) pgxmock.PgxPoolIface {
+	t.Helper()
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("pgxmock: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("не все ожидания выполнены: %v", err)
+		}
+		mock.Close()
+	})
+	return mock
+}
+
+// sqlRe — выражение для ExpectQuery/ExpectExec из фрагментов запроса: фрагменты экранируются,
+// пробелы между ними — любые (запросы в репозиториях отформатированы по-разному).
+func sqlRe(fragments ...string) string {
+	re := ""
+	for i, f := range fragments {
+		if i > 0 {
+			re += `[\s\S]*`
+		}
+		re += regexp.QuoteMeta(f)
+	}
+	return re
+}
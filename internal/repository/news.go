@@ -2,66 +2,146 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
 	"edutalks/internal/logger"
 	"edutalks/internal/models"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
 type NewsRepository struct {
-	db *pgxpool.Pool
+	db     DB
+	outbox *OutboxRepository
 }
 
-func NewNewsRepository(db *pgxpool.Pool) *NewsRepository {
-	return &NewsRepository{db: db}
+func NewNewsRepository(db DB, outbox *OutboxRepository) *NewsRepository {
+	return &NewsRepository{db: db, outbox: outbox}
 }
 
 type NewsRepo interface {
-	Create(ctx context.Context, news *models.News) (int, error)
-	ListPaginated(ctx context.Context, limit, offset int) ([]*models.News, int, error)
+	Create(ctx context.Context, news *models.News, intents func(id int) ([]models.OutboxIntent, error)) (int, error)
+	ListPaginated(ctx context.Context, limit, offset int, f models.NewsFilter) ([]*models.News, int, error)
 	GetByID(ctx context.Context, id int) (*models.News, error)
-	Update(ctx context.Context, id int, title, content, imageURL, color, sticker string) error
+	Update(ctx context.Context, id int, u models.NewsUpdate) error
+	SetPublished(ctx context.Context, id int, publish bool, intents NewsIntents) (*models.News, error)
+	Schedule(ctx context.Context, id int, at time.Time) (*models.News, error)
+	PublishDue(ctx context.Context, intents NewsIntents) ([]*models.News, error)
 	Delete(ctx context.Context, id int) error
 	Search(ctx context.Context, query string) ([]models.News, error)
 }
 
-func (r *NewsRepository) Create(ctx context.Context, news *models.News) (int, error) {
+// NewsIntents — побочные эффекты первой публикации новости (пишутся в outbox в той же транзакции).
+type NewsIntents func(n *models.News) ([]models.OutboxIntent, error)
+
+const newsColumns = `id, title, content, created_at, image_url, color, sticker, category, tags, is_published, published_at, publish_at, view_count`
+
+// scanNews — строка с колонками newsColumns; extra — дополнительные колонки после них.
+func scanNews(row pgx.Row, n *models.News, extra ...any) error {
+	return row.Scan(append([]any{&n.ID, &n.Title, &n.Content, &n.CreatedAt, &n.ImageURL, &n.Color, &n.Sticker,
+		&n.Category, &n.Tags, &n.IsPublished, &n.PublishedAt, &n.PublishAt, &n.ViewCount}, extra...)...)
+}
+
+func (r *NewsRepository) addIntents(ctx context.Context, tx pgx.Tx, n *models.News, intents NewsIntents) error {
+	if intents == nil {
+		return nil
+	}
+	list, err := intents(n)
+	if err != nil || len(list) == 0 {
+		return err
+	}
+	return r.outbox.AddTx(ctx, tx, list...)
+}
+
+// Create — добавляет новость; intents (может быть nil) пишутся в side_effect_outbox в той же транзакции.
+func (r *NewsRepository) Create(ctx context.Context, news *models.News, intents func(id int) ([]models.OutboxIntent, error)) (int, error) {
 	log := logger.WithCtx(ctx)
 
 	const q = `
-		INSERT INTO news (title, content, image_url, color, sticker, created_at)
-		VALUES ($1, $2, $3, $4, $5, NOW())
+		INSERT INTO news (title, content, image_url, color, sticker, category, tags, is_published, published_at, publish_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, $8, CASE WHEN $8 THEN NOW() END, $9, NOW())
 		RETURNING id
 	`
 
+	tagsJSON, _ := json.Marshal(news.Tags)
 	var id int
-	if err := r.db.QueryRow(ctx, q,
-		news.Title,
-		news.Content,
-		news.ImageURL,
-		news.Color,
-		news.Sticker,
-	).Scan(&id); err != nil {
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, q,
+			news.Title,
+			news.Content,
+			news.ImageURL,
+			news.Color,
+			news.Sticker,
+			news.Category,
+			tagsJSON,
+			news.IsPublished,
+			news.PublishAt,
+		).Scan(&id); err != nil {
+			return err
+		}
+		if intents == nil {
+			return nil
+		}
+		list, err := intents(id)
+		if err != nil || len(list) == 0 {
+			return err
+		}
+		return r.outbox.AddTx(ctx, tx, list...)
+	})
+	if err != nil {
 		log.Error("news repo: create failed", zap.Error(err), zap.String("title", news.Title))
 		return 0, err
 	}
 
-	log.Info("news repo: created", zap.Int("id", id), zap.String("title", news.Title))
+	log.Info("news repo: created", zap.Int("id", id), zap.String("title", news.Title), zap.Bool("published", news.IsPublished))
 	return id, nil
 }
 
-func (r *NewsRepository) ListPaginated(ctx context.Context, limit, offset int) ([]*models.News, int, error) {
+// newsWhere — условия фильтра списка новостей (начинается с " WHERE"); удалённые в корзину не попадают.
+func newsWhere(f models.NewsFilter) (string, []any) {
+	conds := []string{"deleted_at IS NULL"}
+	var args []any
+	if f.Tag != "" {
+		args = append(args, f.Tag)
+		conds = append(conds, fmt.Sprintf("tags @> jsonb_build_array($%d::text)", len(args)))
+	}
+	if f.Category != "" {
+		args = append(args, f.Category)
+		conds = append(conds, fmt.Sprintf("category = $%d", len(args)))
+	}
+	switch f.Status {
+	case models.NewsStatusPublished:
+		conds = append(conds, "is_published")
+	case models.NewsStatusDraft:
+		conds = append(conds, "NOT is_published AND publish_at IS NULL")
+	case models.NewsStatusScheduled:
+		conds = append(conds, "NOT is_published AND publish_at IS NOT NULL")
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+func (r *NewsRepository) ListPaginated(ctx context.Context, limit, offset int, f models.NewsFilter) ([]*models.News, int, error) {
 	log := logger.WithCtx(ctx)
 
-	rows, err := r.db.Query(ctx, `
-		SELECT id, title, content, created_at, image_url, color, sticker
-		FROM news
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`, limit, offset)
+	where, args := newsWhere(f)
+	// черновик, опубликованный позже, встаёт в ленту по дате публикации;
+	// запланированные — ближайшие первыми
+	order := "COALESCE(published_at, created_at) DESC, id DESC"
+	if f.Status == models.NewsStatusScheduled {
+		order = "publish_at ASC, id ASC"
+	}
+	q := `
+		SELECT ` + newsColumns + `
+		FROM news` + where + fmt.Sprintf(`
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, order, len(args)+1, len(args)+2)
+
+	rows, err := r.db.Query(ctx, q, append(append([]any{}, args...), limit, offset)...)
 	if err != nil {
 		log.Error("news repo: list paginated query failed", zap.Error(err),
 			zap.Int("limit", limit), zap.Int("offset", offset))
@@ -72,7 +152,7 @@ func (r *NewsRepository) ListPaginated(ctx context.Context, limit, offset int) (
 	var newsList []*models.News
 	for rows.Next() {
 		var n models.News
-		if err := rows.Scan(&n.ID, &n.Title, &n.Content, &n.CreatedAt, &n.ImageURL, &n.Color, &n.Sticker); err != nil {
+		if err := scanNews(rows, &n); err != nil {
 			log.Error("news repo: scan list paginated failed", zap.Error(err))
 			return nil, 0, err
 		}
@@ -84,28 +164,24 @@ func (r *NewsRepository) ListPaginated(ctx context.Context, limit, offset int) (
 	}
 
 	var total int
-	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM news`).Scan(&total); err != nil {
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM news`+where, args...).Scan(&total); err != nil {
 		log.Error("news repo: count failed", zap.Error(err))
 		return nil, 0, err
 	}
 
 	log.Debug("news repo: list paginated done",
 		zap.Int("returned", len(newsList)), zap.Int("total", total),
-		zap.Int("limit", limit), zap.Int("offset", offset))
+		zap.Int("limit", limit), zap.Int("offset", offset),
+		zap.String("tag", f.Tag), zap.String("category", f.Category), zap.String("status", f.Status))
 	return newsList, total, nil
 }
 
 func (r *NewsRepository) GetByID(ctx context.Context, id int) (*models.News, error) {
 	log := logger.WithCtx(ctx)
 
-	const q = `
-		SELECT id, title, content, created_at, image_url, color, sticker
-		FROM news WHERE id = $1
-	`
+	const q = `SELECT ` + newsColumns + ` FROM news WHERE id = $1 AND deleted_at IS NULL`
 	var n models.News
-	if err := r.db.QueryRow(ctx, q, id).Scan(
-		&n.ID, &n.Title, &n.Content, &n.CreatedAt, &n.ImageURL, &n.Color, &n.Sticker,
-	); err != nil {
+	if err := scanNews(r.db.QueryRow(ctx, q, id), &n); err != nil {
 		if err == pgx.ErrNoRows {
 			log.Warn("news repo: not found", zap.Int("id", id))
 		} else {
@@ -118,15 +194,22 @@ func (r *NewsRepository) GetByID(ctx context.Context, id int) (*models.News, err
 	return &n, nil
 }
 
-func (r *NewsRepository) Update(ctx context.Context, id int, title, content, imageURL, color, sticker string) error {
+// Update — новое содержимое новости; рубрика и теги меняются, только если заданы (см. models.NewsUpdate).
+func (r *NewsRepository) Update(ctx context.Context, id int, u models.NewsUpdate) error {
 	log := logger.WithCtx(ctx)
 
 	const q = `
 		UPDATE news
-		SET title = $1, content = $2, image_url = $3, color = $4, sticker = $5
-		WHERE id = $6
+		SET title = $1, content = $2, image_url = $3, color = $4, sticker = $5,
+		    category = COALESCE($6, category),
+		    tags     = COALESCE($7::jsonb, tags)
+		WHERE id = $8 AND deleted_at IS NULL
 	`
-	if _, err := r.db.Exec(ctx, q, title, content, imageURL, color, sticker, id); err != nil {
+	var tagsJSON []byte
+	if u.Tags != nil {
+		tagsJSON, _ = json.Marshal(u.Tags)
+	}
+	if _, err := r.db.Exec(ctx, q, u.Title, u.Content, u.ImageURL, u.Color, u.Sticker, u.Category, tagsJSON, id); err != nil {
 		log.Error("news repo: update failed", zap.Error(err), zap.Int("id", id))
 		return err
 	}
@@ -135,15 +218,139 @@ func (r *NewsRepository) Update(ctx context.Context, id int, title, content, ima
 	return nil
 }
 
+// SetPublished — публикует новость или снимает с публикации; ручное решение отменяет расписание.
+// intents (может быть nil) вызывается только при первой публикации: повторная публикация после
+// снятия рассылку не повторяет. pgx.ErrNoRows — новости нет.
+func (r *NewsRepository) SetPublished(ctx context.Context, id int, publish bool, intents NewsIntents) (*models.News, error) {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+		WITH prev AS (SELECT published_at AS was_published_at FROM news WHERE id = $1 AND deleted_at IS NULL FOR UPDATE)
+		UPDATE news
+		SET is_published = $2,
+		    published_at = CASE WHEN $2 THEN COALESCE(published_at, NOW()) ELSE published_at END,
+		    publish_at = NULL
+		FROM prev
+		WHERE id = $1
+		RETURNING ` + newsColumns + `, was_published_at IS NULL
+	`
+	var n models.News
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		var first bool
+		if err := scanNews(tx.QueryRow(ctx, q, id, publish), &n, &first); err != nil {
+			return err
+		}
+		if !publish || !first {
+			return nil
+		}
+		return r.addIntents(ctx, tx, &n, intents)
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			log.Warn("news repo: set published: not found", zap.Int("id", id))
+		} else {
+			log.Error("news repo: set published failed", zap.Error(err), zap.Int("id", id), zap.Bool("publish", publish))
+		}
+		return nil, err
+	}
+
+	log.Info("news repo: publish updated", zap.Int("id", id), zap.Bool("publish", publish))
+	return &n, nil
+}
+
+// Schedule — назначает время публикации черновику (повторный вызов переносит его).
+// pgx.ErrNoRows — новости нет или она уже опубликована.
+func (r *NewsRepository) Schedule(ctx context.Context, id int, at time.Time) (*models.News, error) {
+	log := logger.WithCtx(ctx)
+
+	const q = `UPDATE news SET publish_at = $2 WHERE id = $1 AND NOT is_published AND deleted_at IS NULL RETURNING ` + newsColumns
+	var n models.News
+	if err := scanNews(r.db.QueryRow(ctx, q, id, at), &n); err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("news repo: schedule failed", zap.Error(err), zap.Int("id", id))
+		}
+		return nil, err
+	}
+
+	log.Info("news repo: scheduled", zap.Int("id", id), zap.Time("publish_at", at))
+	return &n, nil
+}
+
+// PublishDue — публикует новости с наступившим publish_at и возвращает их; intents первой
+// публикации пишутся в той же транзакции. SKIP LOCKED: при нескольких инстансах каждая
+// новость публикуется ровно одним из них.
+func (r *NewsRepository) PublishDue(ctx context.Context, intents NewsIntents) ([]*models.News, error) {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+		WITH due AS (
+			SELECT id AS due_id, published_at AS was_published_at
+			FROM news
+			WHERE NOT is_published AND publish_at IS NOT NULL AND publish_at <= NOW() AND deleted_at IS NULL
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE news
+		SET is_published = true,
+		    published_at = COALESCE(published_at, NOW()),
+		    publish_at = NULL
+		FROM due
+		WHERE id = due_id
+		RETURNING ` + newsColumns + `, was_published_at IS NULL
+	`
+	var list []*models.News
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, q)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var first []bool
+		for rows.Next() {
+			var n models.News
+			var f bool
+			if err := scanNews(rows, &n, &f); err != nil {
+				return err
+			}
+			list = append(list, &n)
+			first = append(first, f)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		for i, n := range list {
+			if !first[i] {
+				continue
+			}
+			if err := r.addIntents(ctx, tx, n, intents); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("news repo: publish due failed", zap.Error(err))
+		return nil, err
+	}
+
+	if len(list) > 0 {
+		log.Info("news repo: scheduled news published", zap.Int("count", len(list)))
+	}
+	return list, nil
+}
+
+// Delete — переместить новость в корзину; окончательно удаляет TrashRepository.
 func (r *NewsRepository) Delete(ctx context.Context, id int) error {
 	log := logger.WithCtx(ctx)
 
-	if _, err := r.db.Exec(ctx, `DELETE FROM news WHERE id = $1`, id); err != nil {
+	if _, err := r.db.Exec(ctx, `UPDATE news SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id); err != nil {
 		log.Error("news repo: delete failed", zap.Error(err), zap.Int("id", id))
 		return err
 	}
 
-	log.Info("news repo: deleted", zap.Int("id", id))
+	log.Info("news repo: moved to trash", zap.Int("id", id))
 	return nil
 }
 
@@ -151,9 +358,9 @@ func (r *NewsRepository) Search(ctx context.Context, query string) ([]models.New
 	log := logger.WithCtx(ctx)
 
 	const q = `
-		SELECT id, title, content, image_url, color, sticker, created_at
+		SELECT ` + newsColumns + `
 		FROM news
-		WHERE title ILIKE $1 OR content ILIKE $1
+		WHERE is_published AND deleted_at IS NULL AND (title ILIKE $1 OR content ILIKE $1)
 	`
 	pattern := "%" + query + "%"
 
@@ -167,7 +374,7 @@ func (r *NewsRepository) Search(ctx context.Context, query string) ([]models.New
 	var results []models.News
 	for rows.Next() {
 		var n models.News
-		if err := rows.Scan(&n.ID, &n.Title, &n.Content, &n.ImageURL, &n.Color, &n.Sticker, &n.CreatedAt); err != nil {
+		if err := scanNews(rows, &n); err != nil {
 			log.Error("news repo: scan search failed", zap.Error(err))
 			return nil, err
 		}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// NotificationChannel — канал LISTEN/NOTIFY о новых уведомлениях: payload — id пользователя,
+// NotificationBroadcastPayload — уведомления у многих пользователей сразу. Так о записи
+// узнают SSE-потоки на всех инстансах, а не только на том, где уведомление создано.
+const (
+	NotificationChannel          = "user_notifications"
+	NotificationBroadcastPayload = "*"
+)
+
+type NotificationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewNotificationRepository(db *pgxpool.Pool) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+const notificationColumns = `id, type, title, body, link, data, read_at, created_at`
+
+func scanNotification(row pgx.Row) (*models.Notification, error) {
+	var n models.Notification
+	if err := row.Scan(&n.ID, &n.Type, &n.Title, &n.Body, &n.Link, &n.Data, &n.ReadAt, &n.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func notificationData(n *models.Notification) map[string]any {
+	if n.Data == nil {
+		return map[string]any{}
+	}
+	return n.Data
+}
+
+// CreateForUser — уведомление одному пользователю.
+func (r *NotificationRepository) CreateForUser(ctx context.Context, userID int, n *models.Notification) (*models.Notification, error) {
+	var out *models.Notification
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		var err error
+		out, err = scanNotification(tx.QueryRow(ctx, `
+			INSERT INTO notifications (user_id, type, title, body, link, data)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING `+notificationColumns,
+			userID, n.Type, n.Title, n.Body, n.Link, notificationData(n),
+		))
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `SELECT pg_notify($1, $2)`, NotificationChannel, strconv.Itoa(userID))
+		return err
+	})
+	if err != nil {
+		logger.WithCtx(ctx).Error("notification repo: create failed", zap.Error(err), zap.Int("user_id", userID), zap.String("type", n.Type))
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateForSectionFollowers — уведомление всем, кто отслеживает раздел или его родителя;
+// возвращает число получателей.
+func (r *NotificationRepository) CreateForSectionFollowers(ctx context.Context, sectionID int, n *models.Notification) (int, error) {
+	var count int
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		// pg_notify в транзакции уходит при коммите: поток не увидит сигнал раньше записи
+		tag, err := tx.Exec(ctx, sectionFollowersCTE+`, ins AS (
+				INSERT INTO notifications (user_id, type, title, body, link, data)
+				SELECT f.user_id, $2, $3, $4, $5, $6 FROM followers f
+				RETURNING user_id
+			)
+			SELECT pg_notify($7, user_id::text) FROM ins`,
+			sectionID, n.Type, n.Title, n.Body, n.Link, notificationData(n), NotificationChannel,
+		)
+		count = int(tag.RowsAffected())
+		return err
+	})
+	if err != nil {
+		logger.WithCtx(ctx).Error("notification repo: create for followers failed", zap.Error(err), zap.Int("section_id", sectionID))
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateBroadcast — уведомление аудитории (models.Broadcast*); один сигнал NOTIFY на всех.
+func (r *NotificationRepository) CreateBroadcast(ctx context.Context, audience string, n *models.Notification) (int, error) {
+	where := "TRUE"
+	if audience == models.BroadcastSubscribers {
+		where = "has_subscription AND (subscription_expires_at IS NULL OR subscription_expires_at > NOW())"
+	}
+
+	var count int
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO notifications (user_id, type, title, body, link, data)
+			SELECT id, $1, $2, $3, $4, $5 FROM users WHERE `+where,
+			n.Type, n.Title, n.Body, n.Link, notificationData(n),
+		)
+		if err != nil {
+			return err
+		}
+		count = int(tag.RowsAffected())
+		_, err = tx.Exec(ctx, `SELECT pg_notify($1, $2)`, NotificationChannel, NotificationBroadcastPayload)
+		return err
+	})
+	if err != nil {
+		logger.WithCtx(ctx).Error("notification repo: broadcast failed", zap.Error(err), zap.String("audience", audience))
+		return 0, err
+	}
+	logger.WithCtx(ctx).Info("notification repo: broadcast created", zap.String("audience", audience), zap.Int("recipients", count))
+	return count, nil
+}
+
+// List — уведомления пользователя, новые сверху; total — с учётом фильтра, unread — всего непрочитанных.
+func (r *NotificationRepository) List(ctx context.Context, userID int, unreadOnly bool, limit, offset int) (items []models.Notification, total, unread int, err error) {
+	log := logger.WithCtx(ctx)
+
+	if err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE NOT $2 OR read_at IS NULL), COUNT(*) FILTER (WHERE read_at IS NULL)
+		FROM notifications WHERE user_id = $1`, userID, unreadOnly,
+	).Scan(&total, &unread); err != nil {
+		log.Error("notification repo: count failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, 0, 0, err
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT `+notificationColumns+` FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY id DESC LIMIT $3 OFFSET $4`, userID, unreadOnly, limit, offset)
+	if err != nil {
+		log.Error("notification repo: list failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, 0, 0, err
+	}
+	defer rows.Close()
+
+	items = make([]models.Notification, 0, limit)
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			log.Error("notification repo: scan failed", zap.Error(err))
+			return nil, 0, 0, err
+		}
+		items = append(items, *n)
+	}
+	return items, total, unread, rows.Err()
+}
+
+// ListAfter — уведомления пользователя с id больше afterID по возрастанию (для SSE-потока).
+func (r *NotificationRepository) ListAfter(ctx context.Context, userID int, afterID int64, limit int) ([]models.Notification, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+notificationColumns+` FROM notifications
+		WHERE user_id = $1 AND id > $2
+		ORDER BY id LIMIT $3`, userID, afterID, limit)
+	if err != nil {
+		logger.WithCtx(ctx).Error("notification repo: list after failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.Notification{}
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *n)
+	}
+	return out, rows.Err()
+}
+
+// LastID — id последнего уведомления пользователя; 0 — уведомлений нет.
+func (r *NotificationRepository) LastID(ctx context.Context, userID int) (int64, error) {
+	var id int64
+	err := r.db.QueryRow(ctx, `SELECT COALESCE(MAX(id), 0) FROM notifications WHERE user_id = $1`, userID).Scan(&id)
+	if err != nil {
+		logger.WithCtx(ctx).Error("notification repo: last id failed", zap.Error(err), zap.Int("user_id", userID))
+	}
+	return id, err
+}
+
+func (r *NotificationRepository) UnreadCount(ctx context.Context, userID int) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&n)
+	if err != nil {
+		logger.WithCtx(ctx).Error("notification repo: unread count failed", zap.Error(err), zap.Int("user_id", userID))
+	}
+	return n, err
+}
+
+// MarkRead — отмечает уведомление прочитанным; false — у пользователя такого уведомления нет.
+// Повторная отметка не меняет read_at.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID int, id int64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE notifications SET read_at = COALESCE(read_at, now())
+		WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		logger.WithCtx(ctx).Error("notification repo: mark read failed", zap.Error(err), zap.Int64("id", id))
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// MarkAllRead — отмечает прочитанными все уведомления пользователя; возвращает число отмеченных.
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID int) (int, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE notifications SET read_at = now() WHERE user_id = $1 AND read_at IS NULL`, userID)
+	if err != nil {
+		logger.WithCtx(ctx).Error("notification repo: mark all read failed", zap.Error(err), zap.Int("user_id", userID))
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// Listen — держит отдельное соединение с LISTEN на NotificationChannel и вызывает fn
+// с payload каждого сигнала. Возвращает ошибку при разрыве соединения или отмене ctx.
+func (r *NotificationRepository) Listen(ctx context.Context, fn func(payload string)) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// после LISTEN соединение в пул не возвращаем — закрываем
+	defer func() {
+		_ = conn.Conn().Close(context.WithoutCancel(ctx))
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, `LISTEN `+NotificationChannel); err != nil {
+		return err
+	}
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		fn(n.Payload)
+	}
+}
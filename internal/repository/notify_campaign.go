@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type NotifyCampaignRepository struct {
+	db DB
+}
+
+func NewNotifyCampaignRepository(db DB) *NotifyCampaignRepository {
+	return &NotifyCampaignRepository{db: db}
+}
+
+const notifyCampaignColumns = `id, subject, message, template, url, audience, send_at, repeat, status,
+	runs, last_run_at, created_by, created_at, updated_at`
+
+// notifyCampaignStatsSelect — колонки рассылки и статистика доставки её писем (по адресатам).
+const notifyCampaignStatsSelect = `
+	SELECT c.id, c.subject, c.message, c.template, c.url, c.audience, c.send_at, c.repeat, c.status,
+	       c.runs, c.last_run_at, c.created_by, c.created_at, c.updated_at,
+	       COALESCE(st.queued, 0), COALESCE(st.sent, 0), COALESCE(st.failed, 0)
+	FROM notify_campaigns c
+	LEFT JOIN LATERAL (
+		SELECT SUM(cardinality(recipients)) FILTER (WHERE status = 'pending') AS queued,
+		       SUM(cardinality(recipients)) FILTER (WHERE status = 'sent')    AS sent,
+		       SUM(cardinality(recipients)) FILTER (WHERE status = 'failed')  AS failed
+		FROM email_outbox WHERE campaign_id = c.id
+	) st ON true`
+
+func scanNotifyCampaign(row pgx.Row, withStats bool) (*models.NotifyCampaign, error) {
+	var c models.NotifyCampaign
+	dest := []any{
+		&c.ID, &c.Subject, &c.Message, &c.Template, &c.URL, &c.Audience, &c.SendAt, &c.Repeat, &c.Status,
+		&c.Runs, &c.LastRunAt, &c.CreatedBy, &c.CreatedAt, &c.UpdatedAt,
+	}
+	if withStats {
+		dest = append(dest, &c.Stats.Queued, &c.Stats.Sent, &c.Stats.Failed)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *NotifyCampaignRepository) Create(ctx context.Context, c *models.NotifyCampaign) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO notify_campaigns (subject, message, template, url, audience, send_at, repeat, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, status, runs, created_at, updated_at`,
+		c.Subject, c.Message, c.Template, c.URL, c.Audience, c.SendAt, c.Repeat, c.CreatedBy,
+	).Scan(&c.ID, &c.Status, &c.Runs, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		logger.WithCtx(ctx).Error("notify campaign repo: create failed", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetByID — рассылка со статистикой доставки; pgx.ErrNoRows — не найдена.
+func (r *NotifyCampaignRepository) GetByID(ctx context.Context, id int64) (*models.NotifyCampaign, error) {
+	c, err := scanNotifyCampaign(r.db.QueryRow(ctx, notifyCampaignStatsSelect+` WHERE c.id = $1`, id), true)
+	if err != nil && err != pgx.ErrNoRows {
+		logger.WithCtx(ctx).Error("notify campaign repo: get failed", zap.Int64("id", id), zap.Error(err))
+	}
+	return c, err
+}
+
+// List — рассылки со статистикой, ближайшие запуски сверху; status пустой — все.
+func (r *NotifyCampaignRepository) List(ctx context.Context, status string, limit, offset int) ([]*models.NotifyCampaign, int, error) {
+	log := logger.WithCtx(ctx)
+
+	rows, err := r.db.Query(ctx, notifyCampaignStatsSelect+`
+		WHERE $1 = '' OR c.status = $1
+		ORDER BY c.status = 'scheduled' DESC, c.send_at DESC, c.id DESC
+		LIMIT $2 OFFSET $3`, status, limit, offset)
+	if err != nil {
+		log.Error("notify campaign repo: list failed", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	list := []*models.NotifyCampaign{}
+	for rows.Next() {
+		c, err := scanNotifyCampaign(rows, true)
+		if err != nil {
+			log.Error("notify campaign repo: scan failed", zap.Error(err))
+			return nil, 0, err
+		}
+		list = append(list, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM notify_campaigns WHERE $1 = '' OR status = $1`, status).Scan(&total); err != nil {
+		log.Error("notify campaign repo: count failed", zap.Error(err))
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// Update — меняет письмо, аудиторию и расписание ожидающей рассылки;
+// pgx.ErrNoRows — рассылки нет или она уже отправлена / отменена.
+func (r *NotifyCampaignRepository) Update(ctx context.Context, c *models.NotifyCampaign) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE notify_campaigns
+		SET subject = $2, message = $3, template = $4, url = $5, audience = $6, send_at = $7, repeat = $8,
+		    updated_at = NOW()
+		WHERE id = $1 AND status = 'scheduled'`,
+		c.ID, c.Subject, c.Message, c.Template, c.URL, c.Audience, c.SendAt, c.Repeat)
+	if err != nil {
+		logger.WithCtx(ctx).Error("notify campaign repo: update failed", zap.Int64("id", c.ID), zap.Error(err))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Cancel — отменяет ожидающую рассылку; false — её нет или она уже отправлена / отменена.
+// Письма, уже поставленные в очередь, уходят.
+func (r *NotifyCampaignRepository) Cancel(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE notify_campaigns SET status = 'cancelled', updated_at = NOW()
+		WHERE id = $1 AND status = 'scheduled'`, id)
+	if err != nil {
+		logger.WithCtx(ctx).Error("notify campaign repo: cancel failed", zap.Int64("id", id), zap.Error(err))
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ClaimDue — забирает рассылки с наступившим send_at: next возвращает следующий запуск
+// повторяющейся рассылки (nil — разовая, она становится sent). Возвращает рассылки в том виде,
+// в каком их нужно отправить сейчас. SKIP LOCKED: каждый запуск достаётся одному инстансу.
+func (r *NotifyCampaignRepository) ClaimDue(ctx context.Context, now time.Time, next func(c *models.NotifyCampaign) *time.Time) ([]*models.NotifyCampaign, error) {
+	var list []*models.NotifyCampaign
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT `+notifyCampaignColumns+`
+			FROM notify_campaigns
+			WHERE status = 'scheduled' AND send_at <= $1
+			ORDER BY send_at
+			FOR UPDATE SKIP LOCKED`, now)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			c, err := scanNotifyCampaign(rows, false)
+			if err != nil {
+				return err
+			}
+			list = append(list, c)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		for _, c := range list {
+			if at := next(c); at != nil {
+				_, err = tx.Exec(ctx, `
+					UPDATE notify_campaigns SET send_at = $2, runs = runs + 1, last_run_at = $3, updated_at = NOW()
+					WHERE id = $1`, c.ID, *at, now)
+			} else {
+				_, err = tx.Exec(ctx, `
+					UPDATE notify_campaigns SET status = 'sent', runs = runs + 1, last_run_at = $2, updated_at = NOW()
+					WHERE id = $1`, c.ID, now)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithCtx(ctx).Error("notify campaign repo: claim due failed", zap.Error(err))
+		return nil, err
+	}
+	return list, nil
+}
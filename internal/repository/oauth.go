@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type OAuthRepository struct {
+	db DB
+}
+
+func NewOAuthRepository(db DB) *OAuthRepository {
+	return &OAuthRepository{db: db}
+}
+
+// FindUserID — пользователь, к которому привязан внешний аккаунт; pgx.ErrNoRows — не привязан.
+func (r *OAuthRepository) FindUserID(ctx context.Context, provider, subject string) (int, error) {
+	var userID int
+	err := r.db.QueryRow(ctx, `SELECT user_id FROM oauth_identities WHERE provider = $1 AND subject = $2`, provider, subject).Scan(&userID)
+	if err != nil && err != pgx.ErrNoRows {
+		logger.WithCtx(ctx).Error("oauth repo: find identity failed", zap.Error(err), zap.String("provider", provider))
+	}
+	return userID, err
+}
+
+// Link — привязывает внешний аккаунт к пользователю. Нарушение уникальности — аккаунт уже
+// привязан к кому-то или у пользователя уже есть аккаунт этого провайдера.
+func (r *OAuthRepository) Link(ctx context.Context, identity *models.OAuthIdentity) error {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+		INSERT INTO oauth_identities (user_id, provider, subject, email)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	if err := r.db.QueryRow(ctx, q, identity.UserID, identity.Provider, identity.Subject, identity.Email).
+		Scan(&identity.ID, &identity.CreatedAt); err != nil {
+		log.Error("oauth repo: link failed", zap.Error(err), zap.Int("user_id", identity.UserID), zap.String("provider", identity.Provider))
+		return err
+	}
+
+	log.Info("oauth repo: identity linked", zap.Int("user_id", identity.UserID), zap.String("provider", identity.Provider))
+	return nil
+}
+
+// CreateUserWithIdentity — новый пользователь (email сразу подтверждён) с привязанным внешним
+// аккаунтом; всё в одной транзакции.
+func (r *OAuthRepository) CreateUserWithIdentity(ctx context.Context, user *models.User, identity *models.OAuthIdentity) error {
+	log := logger.WithCtx(ctx)
+
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		const insUser = `
+			INSERT INTO users (username, full_name, email, password_hash, role, locale, email_verified, email_verified_at)
+			VALUES ($1, $2, $3, $4, $5, COALESCE(NULLIF($6, ''), 'ru'), true, NOW())
+			RETURNING id
+		`
+		if err := tx.QueryRow(ctx, insUser, user.Username, user.FullName, user.Email, user.PasswordHash, user.Role, user.Locale).
+			Scan(&user.ID); err != nil {
+			return err
+		}
+		user.EmailVerified = true
+		identity.UserID = user.ID
+
+		const insIdentity = `
+			INSERT INTO oauth_identities (user_id, provider, subject, email)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at
+		`
+		return tx.QueryRow(ctx, insIdentity, identity.UserID, identity.Provider, identity.Subject, identity.Email).
+			Scan(&identity.ID, &identity.CreatedAt)
+	})
+	if err != nil {
+		log.Error("oauth repo: create user with identity failed", zap.Error(err), zap.String("provider", identity.Provider))
+		return err
+	}
+
+	log.Info("oauth repo: user created via provider", zap.Int("user_id", user.ID), zap.String("provider", identity.Provider))
+	return nil
+}
+
+// Unlink — отвязывает аккаунт провайдера; false — привязки не было.
+func (r *OAuthRepository) Unlink(ctx context.Context, userID int, provider string) (bool, error) {
+	log := logger.WithCtx(ctx)
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM oauth_identities WHERE user_id = $1 AND provider = $2`, userID, provider)
+	if err != nil {
+		log.Error("oauth repo: unlink failed", zap.Error(err), zap.Int("user_id", userID), zap.String("provider", provider))
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	log.Info("oauth repo: identity unlinked", zap.Int("user_id", userID), zap.String("provider", provider))
+	return true, nil
+}
+
+// ListByUser — привязанные внешние аккаунты пользователя.
+func (r *OAuthRepository) ListByUser(ctx context.Context, userID int) ([]models.OAuthIdentity, error) {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+		SELECT id, user_id, provider, subject, email, created_at
+		FROM oauth_identities
+		WHERE user_id = $1
+		ORDER BY provider
+	`
+	rows, err := r.db.Query(ctx, q, userID)
+	if err != nil {
+		log.Error("oauth repo: list failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]models.OAuthIdentity, 0)
+	for rows.Next() {
+		var i models.OAuthIdentity
+		if err := rows.Scan(&i.ID, &i.UserID, &i.Provider, &i.Subject, &i.Email, &i.CreatedAt); err != nil {
+			log.Error("oauth repo: scan failed", zap.Error(err))
+			return nil, err
+		}
+		list = append(list, i)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("oauth repo: rows error", zap.Error(err))
+		return nil, err
+	}
+	return list, nil
+}
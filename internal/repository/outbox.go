@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// inTx — выполняет fn в транзакции: commit при nil, rollback при ошибке.
+func inTx(ctx context.Context, db DB, fn func(tx pgx.Tx) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+type OutboxRepository struct {
+	db DB
+}
+
+func NewOutboxRepository(db DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// AddTx — записывает намерения в рамках уже открытой транзакции.
+func (r *OutboxRepository) AddTx(ctx context.Context, tx pgx.Tx, intents ...models.OutboxIntent) error {
+	log := logger.WithCtx(ctx)
+
+	for _, it := range intents {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO side_effect_outbox (kind, payload) VALUES ($1, $2)`, it.Kind, it.Payload,
+		); err != nil {
+			log.Error("outbox repo: add failed", zap.Error(err), zap.String("kind", it.Kind))
+			return err
+		}
+	}
+	return nil
+}
+
+// ClaimBatch — забирает до limit готовых записей. Как и в email_outbox, запись остаётся pending,
+// а next_attempt_at сдвигается на lease: при падении relay запись вернётся в работу сама.
+func (r *OutboxRepository) ClaimBatch(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxRecord, error) {
+	log := logger.WithCtx(ctx)
+
+	rows, err := r.db.Query(ctx, `
+		UPDATE side_effect_outbox
+		SET attempts = attempts + 1,
+		    next_attempt_at = now() + $2::interval
+		WHERE id IN (
+			SELECT id FROM side_effect_outbox
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, kind, payload, attempts, created_at`, limit, lease.String(),
+	)
+	if err != nil {
+		log.Error("outbox repo: claim failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.OutboxRecord
+	for rows.Next() {
+		var m models.OutboxRecord
+		if err := rows.Scan(&m.ID, &m.Kind, &m.Payload, &m.Attempts, &m.CreatedAt); err != nil {
+			log.Error("outbox repo: scan failed", zap.Error(err))
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func (r *OutboxRepository) MarkDone(ctx context.Context, id int64) error {
+	if _, err := r.db.Exec(ctx,
+		`UPDATE side_effect_outbox SET status='done', processed_at=now(), last_error=NULL WHERE id=$1`, id,
+	); err != nil {
+		logger.WithCtx(ctx).Error("outbox repo: mark done failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return nil
+}
+
+func (r *OutboxRepository) MarkRetry(ctx context.Context, id int64, errMsg string, next time.Time) error {
+	if _, err := r.db.Exec(ctx,
+		`UPDATE side_effect_outbox SET last_error=$2, next_attempt_at=$3 WHERE id=$1`, id, errMsg, next,
+	); err != nil {
+		logger.WithCtx(ctx).Error("outbox repo: mark retry failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return nil
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, errMsg string) error {
+	if _, err := r.db.Exec(ctx,
+		`UPDATE side_effect_outbox SET status='failed', last_error=$2, processed_at=now() WHERE id=$1`, id, errMsg,
+	); err != nil {
+		logger.WithCtx(ctx).Error("outbox repo: mark failed failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return nil
+}
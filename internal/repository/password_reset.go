@@ -8,15 +8,14 @@ import (
 	"edutalks/internal/models"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
 type PasswordResetRepository struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewPasswordResetRepository(db *pgxpool.Pool) *PasswordResetRepository {
+func NewPasswordResetRepository(db DB) *PasswordResetRepository {
 	return &PasswordResetRepository{db: db}
 }
 
@@ -25,7 +24,7 @@ type PasswordResetRepo interface {
 	GetValidByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
 	MarkUsed(ctx context.Context, id int64) error
 	UpdateUserPassword(ctx context.Context, userID int64, passwordHash string) error
-	FindUserIDByEmail(ctx context.Context, email string) (int64, error)
+	FindUserIDByEmail(ctx context.Context, email string) (int64, string, error)
 }
 
 // Create — сохраняет запись для сброса пароля.
@@ -100,22 +99,25 @@ func (r *PasswordResetRepository) UpdateUserPassword(ctx context.Context, userID
 	return nil
 }
 
-// FindUserIDByEmail — получить ID пользователя по email.
-func (r *PasswordResetRepository) FindUserIDByEmail(ctx context.Context, email string) (int64, error) {
+// FindUserIDByEmail — получить ID пользователя и язык его писем по email.
+func (r *PasswordResetRepository) FindUserIDByEmail(ctx context.Context, email string) (int64, string, error) {
 	log := logger.WithCtx(ctx)
 
-	const q = `SELECT id FROM users WHERE lower(email)=lower($1) LIMIT 1`
+	const q = `SELECT id, locale FROM users WHERE lower(email)=lower($1) LIMIT 1`
 
-	var userID int64
-	if err := r.db.QueryRow(ctx, q, email).Scan(&userID); err != nil {
+	var (
+		userID int64
+		locale string
+	)
+	if err := r.db.QueryRow(ctx, q, email).Scan(&userID, &locale); err != nil {
 		if err == pgx.ErrNoRows {
 			log.Warn("password reset repo: user not found by email")
 		} else {
 			log.Error("password reset repo: select user by email failed", zap.Error(err))
 		}
-		return 0, err
+		return 0, "", err
 	}
 
 	log.Debug("password reset repo: user found by email", zap.Int64("user_id", userID))
-	return userID, nil
+	return userID, locale, nil
 }
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type PaymentRepository struct {
+	db DB
+}
+
+func NewPaymentRepository(db DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+const paymentColumns = `id, user_id, provider, provider_payment_id, plan, amount::float8, currency, description,
+	status, error, paid_at, created_at, updated_at`
+
+func scanPayment(row pgx.Row) (*models.Payment, error) {
+	var p models.Payment
+	if err := row.Scan(
+		&p.ID, &p.UserID, &p.Provider, &p.ProviderPaymentID, &p.Plan, &p.Amount, &p.Currency, &p.Description,
+		&p.Status, &p.Error, &p.PaidAt, &p.CreatedAt, &p.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Create — фиксирует попытку оплаты.
+func (r *PaymentRepository) Create(ctx context.Context, p *models.Payment) (int64, error) {
+	log := logger.WithCtx(ctx)
+
+	var id int64
+	if err := r.db.QueryRow(ctx, `
+		INSERT INTO payments (user_id, provider, provider_payment_id, plan, amount, currency, description, status, error)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id`,
+		p.UserID, p.Provider, p.ProviderPaymentID, p.Plan, p.Amount, p.Currency, p.Description, p.Status, p.Error,
+	).Scan(&id); err != nil {
+		log.Error("payment repo: create failed", zap.Error(err), zap.Int("user_id", p.UserID), zap.String("plan", p.Plan))
+		return 0, err
+	}
+
+	log.Info("payment repo: created", zap.Int64("id", id), zap.Int("user_id", p.UserID), zap.String("status", p.Status))
+	return id, nil
+}
+
+// UpsertStatus — обновляет статус по id платежа у провайдера; если записи нет
+// (платёж создан до появления таблицы), создаёт её из данных уведомления.
+func (r *PaymentRepository) UpsertStatus(ctx context.Context, p *models.Payment) error {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+		INSERT INTO payments (user_id, provider, provider_payment_id, plan, amount, currency, description, status, paid_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8, CASE WHEN $8 = 'succeeded' THEN now() END)
+		ON CONFLICT (provider, provider_payment_id)
+		DO UPDATE SET status = EXCLUDED.status,
+		              paid_at = COALESCE(payments.paid_at, EXCLUDED.paid_at),
+		              updated_at = now()
+	`
+	if _, err := r.db.Exec(ctx, q,
+		p.UserID, p.Provider, p.ProviderPaymentID, p.Plan, p.Amount, p.Currency, p.Description, p.Status,
+	); err != nil {
+		log.Error("payment repo: upsert status failed", zap.Error(err), zap.Stringp("provider_payment_id", p.ProviderPaymentID))
+		return err
+	}
+
+	log.Info("payment repo: status updated", zap.Stringp("provider_payment_id", p.ProviderPaymentID), zap.String("status", p.Status))
+	return nil
+}
+
+// List — платежи с фильтрами (новые сверху) и общее количество.
+func (r *PaymentRepository) List(ctx context.Context, f models.PaymentFilter, limit, offset int) ([]models.Payment, int, error) {
+	log := logger.WithCtx(ctx)
+
+	where := " WHERE 1=1"
+	whereArgs := []any{}
+	argn := 1
+
+	if f.UserID != nil {
+		where += fmt.Sprintf(" AND user_id = $%d", argn)
+		whereArgs = append(whereArgs, *f.UserID)
+		argn++
+	}
+	if v := strings.TrimSpace(f.Status); v != "" {
+		where += fmt.Sprintf(" AND status = $%d", argn)
+		whereArgs = append(whereArgs, v)
+		argn++
+	}
+	if v := strings.TrimSpace(f.Plan); v != "" {
+		where += fmt.Sprintf(" AND plan = $%d", argn)
+		whereArgs = append(whereArgs, v)
+		argn++
+	}
+	if v := strings.TrimSpace(f.ProviderPaymentID); v != "" {
+		where += fmt.Sprintf(" AND provider_payment_id = $%d", argn)
+		whereArgs = append(whereArgs, v)
+		argn++
+	}
+	if f.From != nil {
+		where += fmt.Sprintf(" AND created_at >= $%d", argn)
+		whereArgs = append(whereArgs, *f.From)
+		argn++
+	}
+	if f.To != nil {
+		where += fmt.Sprintf(" AND created_at < $%d", argn)
+		whereArgs = append(whereArgs, *f.To)
+		argn++
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM payments`+where, whereArgs...).Scan(&total); err != nil {
+		log.Error("payment repo: count failed", zap.Error(err))
+		return nil, 0, err
+	}
+
+	orderPage := fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argn, argn+1)
+	args := append(append([]any{}, whereArgs...), limit, offset)
+
+	rows, err := r.db.Query(ctx, `SELECT `+paymentColumns+` FROM payments`+where+orderPage, args...)
+	if err != nil {
+		log.Error("payment repo: list failed", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]models.Payment, 0, limit)
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			log.Error("payment repo: scan failed", zap.Error(err))
+			return nil, 0, err
+		}
+		out = append(out, *p)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("payment repo: rows error", zap.Error(err))
+		return nil, 0, err
+	}
+
+	log.Debug("payment repo: list done", zap.Int("count", len(out)), zap.Int("total", total))
+	return out, total, nil
+}
+
+// GetByID — платёж по id; pgx.ErrNoRows, если не найден.
+func (r *PaymentRepository) GetByID(ctx context.Context, id int64) (*models.Payment, error) {
+	p, err := scanPayment(r.db.QueryRow(ctx, `SELECT `+paymentColumns+` FROM payments WHERE id = $1`, id))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.WithCtx(ctx).Error("payment repo: get by id failed", zap.Error(err), zap.Int64("id", id))
+	}
+	return p, err
+}
+
+// GetByProviderID — платёж по id у провайдера; pgx.ErrNoRows, если не найден.
+func (r *PaymentRepository) GetByProviderID(ctx context.Context, provider, providerPaymentID string) (*models.Payment, error) {
+	p, err := scanPayment(r.db.QueryRow(ctx,
+		`SELECT `+paymentColumns+` FROM payments WHERE provider = $1 AND provider_payment_id = $2`,
+		provider, providerPaymentID))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.WithCtx(ctx).Error("payment repo: get by provider id failed", zap.Error(err), zap.String("provider_payment_id", providerPaymentID))
+	}
+	return p, err
+}
+
+// ListByUser — все платежи пользователя (новые сверху).
+func (r *PaymentRepository) ListByUser(ctx context.Context, userID int) ([]models.Payment, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+paymentColumns+` FROM payments WHERE user_id = $1 ORDER BY created_at DESC, id DESC`, userID)
+	if err != nil {
+		logger.WithCtx(ctx).Error("payment repo: list by user failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.Payment{}
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *p)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type PaymentWebhookRepository struct {
+	db DB
+}
+
+func NewPaymentWebhookRepository(db DB) *PaymentWebhookRepository {
+	return &PaymentWebhookRepository{db: db}
+}
+
+// Register — сохраняет принятое уведомление (ключ идемпотентности: provider+event+payment_id).
+// Повтор того же события увеличивает attempts и обновляет raw_payload.
+// alreadyProcessed == true — событие уже было успешно обработано ранее. Это только быстрый
+// ответ на повтор: обрабатывать событие можно лишь через ProcessOnce.
+func (r *PaymentWebhookRepository) Register(ctx context.Context, provider, event, paymentID, status, remoteIP, raw string) (id int64, alreadyProcessed bool, err error) {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+		INSERT INTO payment_webhook_events (provider, event, payment_id, status, remote_ip, raw_payload)
+		VALUES ($1,$2,$3,$4,$5,$6)
+		ON CONFLICT (provider, event, payment_id) WHERE rejected_reason IS NULL
+		DO UPDATE SET attempts = payment_webhook_events.attempts + 1,
+		              status = EXCLUDED.status,
+		              remote_ip = EXCLUDED.remote_ip,
+		              raw_payload = EXCLUDED.raw_payload,
+		              updated_at = now()
+		RETURNING id, processed_at IS NOT NULL
+	`
+	if err = r.db.QueryRow(ctx, q, provider, event, paymentID, status, remoteIP, raw).Scan(&id, &alreadyProcessed); err != nil {
+		log.Error("payment webhook repo: register failed", zap.Error(err), zap.String("event", event), zap.String("payment_id", paymentID))
+		return 0, false, err
+	}
+
+	log.Info("payment webhook repo: event registered",
+		zap.Int64("id", id), zap.String("event", event), zap.String("payment_id", paymentID), zap.Bool("already_processed", alreadyProcessed))
+	return id, alreadyProcessed, nil
+}
+
+// SaveRejected — сохраняет отклонённое уведомление для аудита (вне ключа идемпотентности).
+func (r *PaymentWebhookRepository) SaveRejected(ctx context.Context, provider, remoteIP, raw, reason string) error {
+	log := logger.WithCtx(ctx)
+
+	if _, err := r.db.Exec(ctx,
+		`INSERT INTO payment_webhook_events (provider, remote_ip, raw_payload, rejected_reason) VALUES ($1,$2,$3,$4)`,
+		provider, remoteIP, raw, reason,
+	); err != nil {
+		log.Error("payment webhook repo: save rejected failed", zap.Error(err), zap.String("remote_ip", remoteIP))
+		return err
+	}
+	return nil
+}
+
+// MarkProcessed — событие обработано, повторные уведомления будут подтверждены без действий.
+func (r *PaymentWebhookRepository) MarkProcessed(ctx context.Context, id int64) error {
+	log := logger.WithCtx(ctx)
+
+	if _, err := r.db.Exec(ctx,
+		`UPDATE payment_webhook_events SET processed_at = now(), updated_at = now() WHERE id = $1`, id,
+	); err != nil {
+		log.Error("payment webhook repo: mark processed failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return nil
+}
+
+// ProcessOnce — помечает событие обработанным и в той же транзакции выполняет op; намерения op
+// пишутся в outbox той же транзакции. Строка события блокируется UPDATE, поэтому из одновременных
+// доставок одного события op выполнит только одна: claimed == false — событие уже обработано.
+// Ошибка op откатывает и отметку — следующий повтор уведомления обработает событие заново.
+func (r *PaymentWebhookRepository) ProcessOnce(
+	ctx context.Context,
+	id int64,
+	outbox *OutboxRepository,
+	op func(ctx context.Context, tx pgx.Tx) ([]models.OutboxIntent, error),
+) (claimed bool, err error) {
+	log := logger.WithCtx(ctx)
+
+	err = inTx(ctx, r.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx,
+			`UPDATE payment_webhook_events SET processed_at = now(), updated_at = now() WHERE id = $1 AND processed_at IS NULL`, id,
+		)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil
+		}
+		claimed = true
+		intents, err := op(ctx, tx)
+		if err != nil {
+			return err
+		}
+		return outbox.AddTx(ctx, tx, intents...)
+	})
+	if err != nil {
+		log.Error("payment webhook repo: process failed", zap.Error(err), zap.Int64("id", id))
+		return false, err
+	}
+
+	log.Info("payment webhook repo: event processed", zap.Int64("id", id), zap.Bool("claimed", claimed))
+	return claimed, nil
+}
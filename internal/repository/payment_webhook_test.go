@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestPaymentWebhookRegister(t *testing.T) {
+	mock := newMock(t)
+	repo := NewPaymentWebhookRepository(mock)
+
+	mock.ExpectQuery(sqlRe("INSERT INTO payment_webhook_events (provider, event, payment_id, status, remote_ip, raw_payload)",
+		"ON CONFLICT (provider, event, payment_id) WHERE rejected_reason IS NULL",
+		"attempts = payment_webhook_events.attempts + 1", "RETURNING id, processed_at IS NOT NULL")).
+		WithArgs("yookassa", "payment.succeeded", "pay-1", "succeeded", "185.71.76.1", `{}`).
+		WillReturnRows(mock.NewRows([]string{"id", "processed"}).AddRow(int64(10), true))
+
+	id, processed, err := repo.Register(context.Background(), "yookassa", "payment.succeeded", "pay-1", "succeeded", "185.71.76.1", `{}`)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if id != 10 || !processed {
+		t.Errorf("Register = %d, %v", id, processed)
+	}
+}
+
+const claimSQL = "UPDATE payment_webhook_events SET processed_at = now(), updated_at = now() WHERE id = $1 AND processed_at IS NULL"
+
+func TestPaymentWebhookProcessOnce(t *testing.T) {
+	mock := newMock(t)
+	repo := NewPaymentWebhookRepository(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlRe(claimSQL)).WithArgs(int64(10)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(sqlRe("UPDATE users SET has_subscription")).WithArgs(5).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(sqlRe("INSERT INTO side_effect_outbox")).
+		WithArgs("email", json.RawMessage(`{}`)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	claimed, err := repo.ProcessOnce(context.Background(), 10, NewOutboxRepository(mock),
+		func(ctx context.Context, tx pgx.Tx) ([]models.OutboxIntent, error) {
+			_, err := tx.Exec(ctx, "UPDATE users SET has_subscription = TRUE WHERE id = $1", 5)
+			return []models.OutboxIntent{{Kind: "email", Payload: json.RawMessage(`{}`)}}, err
+		})
+	if err != nil || !claimed {
+		t.Fatalf("ProcessOnce = %v, %v", claimed, err)
+	}
+}
+
+func TestPaymentWebhookProcessOnceAlreadyClaimed(t *testing.T) {
+	mock := newMock(t)
+	repo := NewPaymentWebhookRepository(mock)
+
+	// другая доставка уже отметила событие: op не вызывается
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlRe(claimSQL)).WithArgs(int64(10)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectCommit()
+
+	claimed, err := repo.ProcessOnce(context.Background(), 10, NewOutboxRepository(mock),
+		func(ctx context.Context, tx pgx.Tx) ([]models.OutboxIntent, error) {
+			t.Error("op вызвана для уже обработанного события")
+			return nil, nil
+		})
+	if err != nil || claimed {
+		t.Fatalf("ProcessOnce = %v, %v", claimed, err)
+	}
+}
+
+func TestPaymentWebhookProcessOnceRollsBack(t *testing.T) {
+	mock := newMock(t)
+	repo := NewPaymentWebhookRepository(mock)
+
+	// ошибка выдачи подписки откатывает и отметку — повтор уведомления обработает событие
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlRe(claimSQL)).WithArgs(int64(10)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectRollback()
+
+	claimed, err := repo.ProcessOnce(context.Background(), 10, NewOutboxRepository(mock),
+		func(ctx context.Context, tx pgx.Tx) ([]models.OutboxIntent, error) {
+			return nil, errors.New("grant failed")
+		})
+	if err == nil || claimed {
+		t.Fatalf("ProcessOnce = %v, %v; ожидалась ошибка", claimed, err)
+	}
+}
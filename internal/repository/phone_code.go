@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// PhoneCodeRepository — коды из SMS (phone_codes) и то, что они подтверждают.
+type PhoneCodeRepository struct {
+	db DB
+}
+
+func NewPhoneCodeRepository(db DB) *PhoneCodeRepository {
+	return &PhoneCodeRepository{db: db}
+}
+
+// Save — новый код пользователя; прежний код того же назначения заменяется, счётчик попыток — с нуля.
+func (r *PhoneCodeRepository) Save(ctx context.Context, c *models.PhoneCode) error {
+	const q = `
+		INSERT INTO phone_codes (user_id, purpose, phone, code_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, purpose) DO UPDATE
+		SET phone = EXCLUDED.phone, code_hash = EXCLUDED.code_hash, attempts = 0,
+		    expires_at = EXCLUDED.expires_at, created_at = now()
+		RETURNING created_at
+	`
+	if err := r.db.QueryRow(ctx, q, c.UserID, c.Purpose, c.Phone, c.CodeHash, c.ExpiresAt).Scan(&c.CreatedAt); err != nil {
+		logger.WithCtx(ctx).Error("phone code repo: save failed", zap.Error(err),
+			zap.Int("user_id", c.UserID), zap.String("purpose", c.Purpose))
+		return err
+	}
+	return nil
+}
+
+// Get — последний код пользователя (в том числе истёкший); pgx.ErrNoRows — кода нет.
+func (r *PhoneCodeRepository) Get(ctx context.Context, userID int, purpose string) (*models.PhoneCode, error) {
+	const q = `
+		SELECT user_id, purpose, phone, code_hash, attempts, expires_at, created_at
+		FROM phone_codes
+		WHERE user_id = $1 AND purpose = $2
+	`
+	var c models.PhoneCode
+	if err := r.db.QueryRow(ctx, q, userID, purpose).Scan(
+		&c.UserID, &c.Purpose, &c.Phone, &c.CodeHash, &c.Attempts, &c.ExpiresAt, &c.CreatedAt,
+	); err != nil {
+		if err != pgx.ErrNoRows {
+			logger.WithCtx(ctx).Error("phone code repo: get failed", zap.Error(err),
+				zap.Int("user_id", userID), zap.String("purpose", purpose))
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// AddAttempt — неверный ввод кода.
+func (r *PhoneCodeRepository) AddAttempt(ctx context.Context, userID int, purpose string) error {
+	if _, err := r.db.Exec(ctx, `UPDATE phone_codes SET attempts = attempts + 1 WHERE user_id = $1 AND purpose = $2`,
+		userID, purpose); err != nil {
+		logger.WithCtx(ctx).Error("phone code repo: add attempt failed", zap.Error(err), zap.Int("user_id", userID))
+		return err
+	}
+	return nil
+}
+
+// consumePhoneCode — удаляет действующий код с хэшем codeHash и возвращает номер, на который он ушёл;
+// pgx.ErrNoRows — код неверный, истёк или уже использован.
+func consumePhoneCode(ctx context.Context, tx pgx.Tx, userID int, purpose, codeHash string, maxAttempts int) (string, error) {
+	var phone string
+	err := tx.QueryRow(ctx, `
+		DELETE FROM phone_codes
+		WHERE user_id = $1 AND purpose = $2 AND code_hash = $3 AND expires_at > now() AND attempts < $4
+		RETURNING phone`, userID, purpose, codeHash, maxAttempts).Scan(&phone)
+	return phone, err
+}
+
+// ConfirmPhone — гасит код подтверждения и отмечает номер подтверждённым, если он не менялся
+// после отправки кода; всё в одной транзакции. pgx.ErrNoRows — код не подошёл или номер уже другой;
+// 23505 — этот номер уже подтвердил другой пользователь.
+func (r *PhoneCodeRepository) ConfirmPhone(ctx context.Context, userID int, codeHash string, maxAttempts int) error {
+	log := logger.WithCtx(ctx)
+
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		phone, err := consumePhoneCode(ctx, tx, userID, models.PhoneCodeVerify, codeHash, maxAttempts)
+		if err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx, `UPDATE users SET phone_verified = TRUE WHERE id = $1 AND phone = $2`, userID, phone)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+		return nil
+	})
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("phone code repo: confirm phone failed", zap.Error(err), zap.Int("user_id", userID))
+		}
+		return err
+	}
+
+	log.Info("phone code repo: phone verified", zap.Int("user_id", userID))
+	return nil
+}
+
+// ResetPassword — гасит код сброса и ставит новый пароль; код пришёл на номер пользователя,
+// поэтому номер заодно считается подтверждённым. Сессии и refresh-токены пользователя
+// отзываются в той же транзакции. pgx.ErrNoRows — код не подошёл.
+func (r *PhoneCodeRepository) ResetPassword(ctx context.Context, userID int, codeHash, passwordHash string, maxAttempts int) error {
+	log := logger.WithCtx(ctx)
+
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		phone, err := consumePhoneCode(ctx, tx, userID, models.PhoneCodeReset, codeHash, maxAttempts)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE users SET password_hash = $2, phone_verified = phone_verified OR phone = $3
+			WHERE id = $1`, userID, passwordHash, phone)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `UPDATE user_sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`, userID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `DELETE FROM refresh_tokens WHERE user_id = $1`, userID)
+		return err
+	})
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("phone code repo: reset password failed", zap.Error(err), zap.Int("user_id", userID))
+		}
+		return err
+	}
+
+	log.Info("phone code repo: password reset by phone", zap.Int("user_id", userID))
+	return nil
+}
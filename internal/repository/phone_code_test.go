@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestPhoneCodeResetPasswordRevokesSessions(t *testing.T) {
+	mock := newMock(t)
+	repo := NewPhoneCodeRepository(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(sqlRe("DELETE FROM phone_codes", "RETURNING phone")).
+		WithArgs(7, models.PhoneCodeReset, "hash", 5).
+		WillReturnRows(mock.NewRows([]string{"phone"}).AddRow("79991234567"))
+	mock.ExpectExec(sqlRe("UPDATE users SET password_hash = $2")).
+		WithArgs(7, "pw", "79991234567").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(sqlRe("UPDATE user_sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL")).
+		WithArgs(7).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+	mock.ExpectExec(sqlRe("DELETE FROM refresh_tokens WHERE user_id = $1")).
+		WithArgs(7).
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	mock.ExpectCommit()
+
+	if err := repo.ResetPassword(context.Background(), 7, "hash", "pw", 5); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+}
+
+func TestPhoneCodeResetPasswordWrongCode(t *testing.T) {
+	mock := newMock(t)
+	repo := NewPhoneCodeRepository(mock)
+
+	// код не подошёл — пароль и сессии не трогаем
+	mock.ExpectBegin()
+	mock.ExpectQuery(sqlRe("DELETE FROM phone_codes")).
+		WithArgs(7, models.PhoneCodeReset, "hash", 5).
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectRollback()
+
+	if err := repo.ResetPassword(context.Background(), 7, "hash", "pw", 5); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("err = %v, want pgx.ErrNoRows", err)
+	}
+}
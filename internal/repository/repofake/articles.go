@@ -0,0 +1,245 @@
+package repofake
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var _ repository.ArticleRepo = (*Articles)(nil)
+
+// Articles — in-memory repository.ArticleRepo. Побочные эффекты (intents) не пишутся в outbox,
+// а копятся в Outbox; ошибка intents отменяет изменение, как откат транзакции.
+type Articles struct {
+	mu     sync.Mutex
+	nextID int64
+	byID   map[int64]*models.Article
+	outbox []models.OutboxIntent
+}
+
+func NewArticles() *Articles {
+	return &Articles{byID: map[int64]*models.Article{}}
+}
+
+// Outbox — накопленные побочные эффекты в порядке записи.
+func (r *Articles) Outbox() []models.OutboxIntent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.OutboxIntent(nil), r.outbox...)
+}
+
+func copyArticle(a *models.Article) *models.Article {
+	cp := *a
+	cp.Tags = append([]string(nil), a.Tags...)
+	return &cp
+}
+
+// commit — сохраняет a, если intents отработали без ошибки.
+func (r *Articles) commit(a *models.Article, intents repository.ArticleIntents) error {
+	if intents != nil {
+		list, err := intents(copyArticle(a))
+		if err != nil {
+			return err
+		}
+		r.outbox = append(r.outbox, list...)
+	}
+	r.byID[a.ID] = a
+	return nil
+}
+
+func (r *Articles) Create(ctx context.Context, a *models.Article, intents repository.ArticleIntents) (*models.Article, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := Now()
+	out := copyArticle(a)
+	out.ID = r.nextID + 1
+	out.CreatedAt, out.UpdatedAt = now, now
+	out.PublishedAt = nil
+	if out.IsPublished {
+		out.PublishedAt = ptr(now)
+	}
+	out.ContentUpdatedAt, out.ViewCount = nil, 0
+	if out.Tags == nil {
+		out.Tags = []string{}
+	}
+	if err := r.commit(out, intents); err != nil {
+		return nil, err
+	}
+	r.nextID = out.ID
+	return copyArticle(out), nil
+}
+
+// GetAll — status models.ArticleStatus* или пусто; запланированные — по publish_at,
+// остальные — новые первыми.
+func (r *Articles) GetAll(ctx context.Context, limit, offset int, tag, status string) ([]*models.Article, error) {
+	return r.list(limit, offset, tag, status, nil)
+}
+
+func (r *Articles) GetByAuthor(ctx context.Context, authorID int64, limit, offset int, status string) ([]*models.Article, error) {
+	return r.list(limit, offset, "", status, &authorID)
+}
+
+func (r *Articles) list(limit, offset int, tag, status string, authorID *int64) ([]*models.Article, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var list []*models.Article
+	for _, a := range r.byID {
+		switch status {
+		case models.ArticleStatusPublished:
+			if !a.IsPublished {
+				continue
+			}
+		case models.ArticleStatusDraft:
+			if a.IsPublished || a.PublishAt != nil {
+				continue
+			}
+		case models.ArticleStatusScheduled:
+			if a.IsPublished || a.PublishAt == nil {
+				continue
+			}
+		}
+		if tag != "" && !hasString(a.Tags, tag) {
+			continue
+		}
+		if authorID != nil && (a.AuthorID == nil || *a.AuthorID != *authorID) {
+			continue
+		}
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if status == models.ArticleStatusScheduled {
+			return list[i].PublishAt.Before(*list[j].PublishAt)
+		}
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.After(list[j].CreatedAt)
+		}
+		return list[i].ID > list[j].ID
+	})
+
+	var out []*models.Article
+	for _, a := range page(list, limit, offset) {
+		out = append(out, copyArticle(a))
+	}
+	return out, nil
+}
+
+func (r *Articles) GetByID(ctx context.Context, id int64) (*models.Article, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.byID[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return copyArticle(a), nil
+}
+
+// Update — правка статьи; ревизии и черновики фейк не ведёт. Статьи нет — не ошибка.
+func (r *Articles) Update(ctx context.Context, a *models.Article, intents repository.ArticleIntents) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.byID[a.ID]
+	if !ok {
+		return nil
+	}
+	next := copyArticle(cur)
+	next.Title, next.Summary, next.BodyHTML = a.Title, a.Summary, a.BodyHTML
+	next.Tags = append([]string(nil), a.Tags...)
+	next.IsPublished = a.IsPublished
+	if !a.IsPublished {
+		next.PublishedAt = nil
+	} else if next.PublishedAt == nil {
+		next.PublishedAt = ptr(Now())
+	}
+	next.PublishAt, next.ContentUpdatedAt, next.CoverImageURL = a.PublishAt, a.ContentUpdatedAt, a.CoverImageURL
+	next.UpdatedAt = Now()
+	// intents получают статью в том виде, в каком её передал сервис
+	if intents != nil {
+		list, err := intents(copyArticle(a))
+		if err != nil {
+			return err
+		}
+		r.outbox = append(r.outbox, list...)
+	}
+	r.byID[a.ID] = next
+	return nil
+}
+
+func (r *Articles) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.byID, id)
+	return nil
+}
+
+func (r *Articles) Exists(ctx context.Context, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.byID[id]
+	return ok, nil
+}
+
+// UpdatePublish — ручная публикация или снятие; расписание сбрасывается. Статьи нет — pgx.ErrNoRows.
+func (r *Articles) UpdatePublish(ctx context.Context, id int64, publish bool, intents repository.ArticleIntents) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.byID[id]
+	if !ok {
+		return pgx.ErrNoRows
+	}
+	next := copyArticle(cur)
+	next.IsPublished = publish
+	if !publish {
+		next.PublishedAt = nil
+	} else if next.PublishedAt == nil {
+		next.PublishedAt = ptr(Now())
+	}
+	next.PublishAt = nil
+	next.UpdatedAt = Now()
+	return r.commit(next, intents)
+}
+
+// PublishDue — публикует статьи с наступившим publish_at; ошибка intents откатывает все.
+func (r *Articles) PublishDue(ctx context.Context, intents repository.ArticleIntents) ([]*models.Article, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := Now()
+	var due []*models.Article
+	for _, a := range r.byID {
+		if !a.IsPublished && a.PublishAt != nil && !a.PublishAt.After(now) {
+			next := copyArticle(a)
+			next.IsPublished, next.PublishedAt, next.PublishAt, next.UpdatedAt = true, ptr(now), nil, now
+			due = append(due, next)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+
+	var added []models.OutboxIntent
+	for _, a := range due {
+		if intents == nil {
+			break
+		}
+		list, err := intents(copyArticle(a))
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, list...)
+	}
+	r.outbox = append(r.outbox, added...)
+
+	out := make([]*models.Article, 0, len(due))
+	for _, a := range due {
+		r.byID[a.ID] = a
+		out = append(out, copyArticle(a))
+	}
+	return out, nil
+}
@@ -0,0 +1,399 @@
+package repofake
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var _ repository.DocumentRepo = (*Documents)(nil)
+
+// Documents — in-memory repository.DocumentRepo. Связи между документами фейк не ведёт.
+type Documents struct {
+	mu     sync.Mutex
+	nextID int
+	byID   map[int]*models.Document
+
+	// Sections — существующие разделы для MoveDocuments; nil — любой раздел существует.
+	Sections map[int]bool
+}
+
+func NewDocuments() *Documents {
+	return &Documents{byID: map[int]*models.Document{}}
+}
+
+func copyDocument(d *models.Document) *models.Document {
+	cp := *d
+	cp.Relations = append([]models.DocumentLink(nil), d.Relations...)
+	return &cp
+}
+
+func (r *Documents) SaveDocument(ctx context.Context, doc *models.Document) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d := copyDocument(doc)
+	r.nextID++
+	d.ID = r.nextID
+	d.ContentUpdatedAt = nil
+	r.byID[d.ID] = d
+	return d.ID, nil
+}
+
+// documentMatch — те же условия, что accessibilityCond и normativeCond в SQL-реализации.
+func documentMatch(d *models.Document, a11y models.DocumentAccessibilityFilter, f models.DocumentNormativeFilter) bool {
+	if a11y.TextLayer && !d.HasTextLayer ||
+		a11y.LargePrint && d.LargePrintURL == nil ||
+		a11y.Audio && d.AudioURL == nil {
+		return false
+	}
+	if f.Number != "" && (d.DocNumber == nil || !containsFold(*d.DocNumber, f.Number)) {
+		return false
+	}
+	if f.Authority != "" && (d.IssuingAuthority == nil || !containsFold(*d.IssuingAuthority, f.Authority)) {
+		return false
+	}
+	inRange := func(v, from, to *time.Time) bool {
+		if from == nil && to == nil {
+			return true
+		}
+		return v != nil && (from == nil || !v.Before(*from)) && (to == nil || !v.After(*to))
+	}
+	if !inRange(d.AdoptedAt, f.AdoptedFrom, f.AdoptedTo) || !inRange(d.EffectiveAt, f.EffectiveFrom, f.EffectiveTo) {
+		return false
+	}
+	if f.InForce && (d.EffectiveAt == nil || d.EffectiveAt.After(Now())) {
+		return false
+	}
+	return true
+}
+
+// sortDocuments — порядок documentOrder: документы без реквизита — в конце, затем новые первыми.
+func sortDocuments(list []*models.Document, f models.DocumentNormativeFilter) {
+	uploadedDesc := func(a, b *models.Document) bool {
+		if !a.UploadedAt.Equal(b.UploadedAt) {
+			return a.UploadedAt.After(b.UploadedAt)
+		}
+		return a.ID > b.ID
+	}
+	byTime := func(a, b *time.Time, x, y *models.Document) bool {
+		switch {
+		case a == nil && b == nil:
+			return uploadedDesc(x, y)
+		case a == nil || b == nil:
+			return b == nil
+		case a.Equal(*b):
+			return uploadedDesc(x, y)
+		case f.Asc:
+			return a.Before(*b)
+		default:
+			return a.After(*b)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		switch f.Sort {
+		case models.DocumentSortAdopted:
+			return byTime(a.AdoptedAt, b.AdoptedAt, a, b)
+		case models.DocumentSortEffective:
+			return byTime(a.EffectiveAt, b.EffectiveAt, a, b)
+		case models.DocumentSortNumber:
+			switch {
+			case a.DocNumber == nil && b.DocNumber == nil:
+				return uploadedDesc(a, b)
+			case a.DocNumber == nil || b.DocNumber == nil:
+				return b.DocNumber == nil
+			}
+			na, nb := strings.ToLower(*a.DocNumber), strings.ToLower(*b.DocNumber)
+			if na == nb {
+				return uploadedDesc(a, b)
+			}
+			return (na < nb) == f.Asc
+		default:
+			if f.Asc && !a.UploadedAt.Equal(b.UploadedAt) {
+				return a.UploadedAt.Before(b.UploadedAt)
+			}
+			return uploadedDesc(a, b)
+		}
+	})
+}
+
+// public — публичные документы раздела и категории (пустые — не фильтровать).
+func (r *Documents) public(sectionID *int, category string, a11y models.DocumentAccessibilityFilter, f models.DocumentNormativeFilter) []*models.Document {
+	var list []*models.Document
+	for _, d := range r.byID {
+		if !d.IsPublic {
+			continue
+		}
+		if sectionID != nil && (d.SectionID == nil || *d.SectionID != *sectionID) {
+			continue
+		}
+		if strings.TrimSpace(category) != "" && d.Category != category {
+			continue
+		}
+		if documentMatch(d, a11y, f) {
+			list = append(list, d)
+		}
+	}
+	sortDocuments(list, f)
+	return list
+}
+
+func copyDocuments(list []*models.Document) []*models.Document {
+	var out []*models.Document
+	for _, d := range list {
+		out = append(out, copyDocument(d))
+	}
+	return out
+}
+
+func (r *Documents) GetPublicDocumentsPaginated(ctx context.Context, limit, offset int, category string, a11y models.DocumentAccessibilityFilter, normative models.DocumentNormativeFilter) ([]*models.Document, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := r.public(nil, category, a11y, normative)
+	return copyDocuments(page(list, limit, offset)), len(list), nil
+}
+
+func (r *Documents) GetDocumentByID(ctx context.Context, id int) (*models.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.byID[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return copyDocument(d), nil
+}
+
+func (r *Documents) DeleteDocument(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.byID, id)
+	return nil
+}
+
+func (r *Documents) GetAllDocuments(ctx context.Context, limit int) ([]*models.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := make([]*models.Document, 0, len(r.byID))
+	for _, d := range r.byID {
+		list = append(list, d)
+	}
+	sortDocuments(list, models.DocumentNormativeFilter{})
+	return copyDocuments(page(list, limit, 0)), nil
+}
+
+func (r *Documents) GetDocumentsByUser(ctx context.Context, userID, limit, offset int) ([]*models.Document, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var list []*models.Document
+	for _, d := range r.byID {
+		if d.UserID == userID {
+			list = append(list, d)
+		}
+	}
+	sortDocuments(list, models.DocumentNormativeFilter{})
+	return copyDocuments(page(list, limit, offset)), len(list), nil
+}
+
+// Search — подстрока в названии, имени файла, описании, категории или реквизитах; Filepath не отдаётся.
+func (r *Documents) Search(ctx context.Context, query string) ([]models.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []models.Document
+	for _, d := range r.byID {
+		fields := []string{d.Title, d.Filename, d.Description, d.Category}
+		if d.DocNumber != nil {
+			fields = append(fields, *d.DocNumber)
+		}
+		if d.IssuingAuthority != nil {
+			fields = append(fields, *d.IssuingAuthority)
+		}
+		for _, s := range fields {
+			if containsFold(s, query) {
+				cp := *copyDocument(d)
+				cp.Filepath = ""
+				out = append(out, cp)
+				break
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *Documents) GetPublicDocumentsByFilterPaginated(
+	ctx context.Context,
+	limit, offset int,
+	sectionID *int,
+	category string,
+) ([]*models.Document, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := r.public(sectionID, category, models.DocumentAccessibilityFilter{}, models.DocumentNormativeFilter{})
+	return copyDocuments(page(list, limit, offset)), len(list), nil
+}
+
+func (r *Documents) UpdateDocumentSection(ctx context.Context, id int, sectionID *int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d, ok := r.byID[id]; ok {
+		d.SectionID = copyIntPtr(sectionID)
+	}
+	return nil
+}
+
+func (r *Documents) GetPublicDocuments(
+	ctx context.Context,
+	sectionID *int,
+	category string,
+	a11y models.DocumentAccessibilityFilter,
+	normative models.DocumentNormativeFilter,
+) ([]*models.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return copyDocuments(r.public(sectionID, category, a11y, normative)), nil
+}
+
+// clearable — nil — не менять, "" — очистить (как NULLIF в SQL-реализации).
+func clearable(cur *string, v *string) *string {
+	if v == nil {
+		return cur
+	}
+	if *v == "" {
+		return nil
+	}
+	return ptr(*v)
+}
+
+func (r *Documents) UpdateAccessibility(ctx context.Context, id int, req models.UpdateDocumentAccessibilityRequest, contentUpdated bool) (*models.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.byID[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	if req.HasTextLayer != nil {
+		d.HasTextLayer = *req.HasTextLayer
+	}
+	d.LargePrintURL = clearable(d.LargePrintURL, req.LargePrintURL)
+	d.AudioURL = clearable(d.AudioURL, req.AudioURL)
+	if contentUpdated {
+		d.ContentUpdatedAt = ptr(Now())
+	}
+	return copyDocument(d), nil
+}
+
+// UpdateNormative — даты в формате YYYY-MM-DD; неверная дата — ошибка, как приведение ::date в Postgres.
+func (r *Documents) UpdateNormative(ctx context.Context, id int, req models.UpdateDocumentNormativeRequest) (*models.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.byID[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	date := func(cur *time.Time, v *string) (*time.Time, error) {
+		if v == nil {
+			return cur, nil
+		}
+		if *v == "" {
+			return nil, nil
+		}
+		t, err := time.Parse(time.DateOnly, *v)
+		if err != nil {
+			return nil, err
+		}
+		return &t, nil
+	}
+	d := copyDocument(cur)
+	var err error
+	if d.AdoptedAt, err = date(d.AdoptedAt, req.AdoptedAt); err != nil {
+		return nil, err
+	}
+	if d.EffectiveAt, err = date(d.EffectiveAt, req.EffectiveAt); err != nil {
+		return nil, err
+	}
+	d.DocNumber = clearable(d.DocNumber, req.DocNumber)
+	d.IssuingAuthority = clearable(d.IssuingAuthority, req.IssuingAuthority)
+	r.byID[id] = d
+	return copyDocument(d), nil
+}
+
+// UpdateDocument — новый файл сбрасывает сигнатуру проверки и выставляет ContentUpdatedAt.
+func (r *Documents) UpdateDocument(ctx context.Context, id int, req models.UpdateDocumentRequest, file *models.DocumentFile) (*models.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.byID[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	if req.Title != nil {
+		d.Title = *req.Title
+	}
+	if req.Description != nil {
+		d.Description = *req.Description
+	}
+	if req.Category != nil {
+		d.Category = *req.Category
+	}
+	if req.IsPublic != nil {
+		d.IsPublic = *req.IsPublic
+	}
+	if req.AllowFreeDownload != nil {
+		d.AllowFreeDownload = *req.AllowFreeDownload
+	}
+	if file != nil {
+		d.Filename, d.Filepath = file.Filename, file.Filepath
+		d.ScanStatus, d.ScanSignature, d.ScannedAt = file.ScanStatus, nil, file.ScannedAt
+		d.ContentUpdatedAt = ptr(Now())
+	}
+	return copyDocument(d), nil
+}
+
+// MoveDocuments — раздела sectionID нет в Sections — pgx.ErrNoRows; ID, которых нет, пропускаются.
+func (r *Documents) MoveDocuments(ctx context.Context, ids []int, fromSectionID, sectionID *int) (map[int]*int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if sectionID != nil && r.Sections != nil && !r.Sections[*sectionID] {
+		return nil, pgx.ErrNoRows
+	}
+	moved := map[int]*int{}
+	for _, d := range r.byID {
+		inFrom := fromSectionID != nil && d.SectionID != nil && *d.SectionID == *fromSectionID
+		if !inFrom && !containsInt(ids, d.ID) {
+			continue
+		}
+		moved[d.ID] = d.SectionID
+		d.SectionID = copyIntPtr(sectionID)
+	}
+	return moved, nil
+}
+
+func containsInt(list []int, v int) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func copyIntPtr(p *int) *int {
+	if p == nil {
+		return nil
+	}
+	return ptr(*p)
+}
@@ -0,0 +1,51 @@
+// Package repofake — in-memory реализации интерфейсов репозиториев (UserRepo, DocumentRepo,
+// ArticleRepo, NewsRepo, PasswordResetRepo, TokenStore) для сервисов и хендлеров без живой БД.
+//
+// Поведение повторяет SQL-реализации там, где от него зависят вызывающие: «не найдено» —
+// pgx.ErrNoRows, пагинация limit/offset, порядок списков. Операции, которым нужны транзакция
+// и outbox (GrantSubscriptionWithOutbox, BulkApply, *Tx), возвращают ErrUnsupported.
+// Все фейки безопасны для конкурентного использования.
+package repofake
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrUnsupported — операция требует транзакции Postgres и фейком не поддерживается.
+var ErrUnsupported = errors.New("repofake: operation requires a database transaction")
+
+// Now — часы фейков; подменяется для детерминированных сценариев.
+var Now = time.Now
+
+// page — срез [offset, offset+limit) с проверкой границ; limit <= 0 — без ограничения.
+func page[T any](items []T, limit, offset int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// containsFold — подстрока без учёта регистра (как ILIKE '%q%').
+func containsFold(s, sub string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(sub))
+}
+
+func hasString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,255 @@
+package repofake
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var _ repository.NewsRepo = (*News)(nil)
+
+// News — in-memory repository.NewsRepo; побочные эффекты копятся в Outbox (см. Articles).
+type News struct {
+	mu     sync.Mutex
+	nextID int
+	byID   map[int]*models.News
+	outbox []models.OutboxIntent
+}
+
+func NewNews() *News {
+	return &News{byID: map[int]*models.News{}}
+}
+
+// Outbox — накопленные побочные эффекты в порядке записи.
+func (r *News) Outbox() []models.OutboxIntent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.OutboxIntent(nil), r.outbox...)
+}
+
+func copyNews(n *models.News) *models.News {
+	cp := *n
+	cp.Tags = append([]string(nil), n.Tags...)
+	return &cp
+}
+
+func (r *News) Create(ctx context.Context, news *models.News, intents func(id int) ([]models.OutboxIntent, error)) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := copyNews(news)
+	n.ID = r.nextID + 1
+	n.CreatedAt = Now()
+	n.PublishedAt, n.ViewCount = nil, 0
+	if n.IsPublished {
+		n.PublishedAt = ptr(n.CreatedAt)
+	}
+	if n.Tags == nil {
+		n.Tags = []string{}
+	}
+	if intents != nil {
+		list, err := intents(n.ID)
+		if err != nil {
+			return 0, err
+		}
+		r.outbox = append(r.outbox, list...)
+	}
+	r.nextID = n.ID
+	r.byID[n.ID] = n
+	return n.ID, nil
+}
+
+// newsMatch — те же условия, что newsWhere в SQL-реализации.
+func newsMatch(n *models.News, f models.NewsFilter) bool {
+	if f.Tag != "" && !hasString(n.Tags, f.Tag) {
+		return false
+	}
+	if f.Category != "" && n.Category != f.Category {
+		return false
+	}
+	switch f.Status {
+	case models.NewsStatusPublished:
+		return n.IsPublished
+	case models.NewsStatusDraft:
+		return !n.IsPublished && n.PublishAt == nil
+	case models.NewsStatusScheduled:
+		return !n.IsPublished && n.PublishAt != nil
+	}
+	return true
+}
+
+// ListPaginated — лента по дате публикации (черновики — по дате создания), запланированные —
+// ближайшие первыми.
+func (r *News) ListPaginated(ctx context.Context, limit, offset int, f models.NewsFilter) ([]*models.News, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var list []*models.News
+	for _, n := range r.byID {
+		if newsMatch(n, f) {
+			list = append(list, n)
+		}
+	}
+	feedTime := func(n *models.News) time.Time {
+		if n.PublishedAt != nil {
+			return *n.PublishedAt
+		}
+		return n.CreatedAt
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if f.Status == models.NewsStatusScheduled {
+			if !a.PublishAt.Equal(*b.PublishAt) {
+				return a.PublishAt.Before(*b.PublishAt)
+			}
+			return a.ID < b.ID
+		}
+		if ta, tb := feedTime(a), feedTime(b); !ta.Equal(tb) {
+			return ta.After(tb)
+		}
+		return a.ID > b.ID
+	})
+
+	var out []*models.News
+	for _, n := range page(list, limit, offset) {
+		out = append(out, copyNews(n))
+	}
+	return out, len(list), nil
+}
+
+func (r *News) GetByID(ctx context.Context, id int) (*models.News, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, ok := r.byID[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return copyNews(n), nil
+}
+
+func (r *News) Update(ctx context.Context, id int, u models.NewsUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, ok := r.byID[id]
+	if !ok {
+		return nil
+	}
+	n.Title, n.Content, n.ImageURL, n.Color, n.Sticker = u.Title, u.Content, u.ImageURL, u.Color, u.Sticker
+	if u.Category != nil {
+		n.Category = *u.Category
+	}
+	if u.Tags != nil {
+		n.Tags = append([]string{}, u.Tags...)
+	}
+	return nil
+}
+
+// SetPublished — intents только при первой публикации; новости нет — pgx.ErrNoRows.
+func (r *News) SetPublished(ctx context.Context, id int, publish bool, intents repository.NewsIntents) (*models.News, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.byID[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	first := cur.PublishedAt == nil
+	n := copyNews(cur)
+	n.IsPublished = publish
+	if publish && n.PublishedAt == nil {
+		n.PublishedAt = ptr(Now())
+	}
+	n.PublishAt = nil
+
+	if publish && first && intents != nil {
+		list, err := intents(copyNews(n))
+		if err != nil {
+			return nil, err
+		}
+		r.outbox = append(r.outbox, list...)
+	}
+	r.byID[id] = n
+	return copyNews(n), nil
+}
+
+// Schedule — время публикации черновика; новости нет или она опубликована — pgx.ErrNoRows.
+func (r *News) Schedule(ctx context.Context, id int, at time.Time) (*models.News, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, ok := r.byID[id]
+	if !ok || n.IsPublished {
+		return nil, pgx.ErrNoRows
+	}
+	n.PublishAt = ptr(at)
+	return copyNews(n), nil
+}
+
+// PublishDue — публикует новости с наступившим publish_at; ошибка intents откатывает все.
+func (r *News) PublishDue(ctx context.Context, intents repository.NewsIntents) ([]*models.News, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := Now()
+	var due []*models.News
+	var first []bool
+	for _, n := range r.byID {
+		if !n.IsPublished && n.PublishAt != nil && !n.PublishAt.After(now) {
+			next := copyNews(n)
+			first = append(first, next.PublishedAt == nil)
+			if next.PublishedAt == nil {
+				next.PublishedAt = ptr(now)
+			}
+			next.IsPublished, next.PublishAt = true, nil
+			due = append(due, next)
+		}
+	}
+
+	var added []models.OutboxIntent
+	for i, n := range due {
+		if !first[i] || intents == nil {
+			continue
+		}
+		list, err := intents(copyNews(n))
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, list...)
+	}
+	r.outbox = append(r.outbox, added...)
+
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	out := make([]*models.News, 0, len(due))
+	for _, n := range due {
+		r.byID[n.ID] = n
+		out = append(out, copyNews(n))
+	}
+	return out, nil
+}
+
+func (r *News) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.byID, id)
+	return nil
+}
+
+// Search — опубликованные новости с подстрокой в заголовке или тексте.
+func (r *News) Search(ctx context.Context, query string) ([]models.News, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []models.News
+	for _, n := range r.byID {
+		if n.IsPublished && (containsFold(n.Title, query) || containsFold(n.Content, query)) {
+			out = append(out, *copyNews(n))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
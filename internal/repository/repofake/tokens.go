@@ -0,0 +1,178 @@
+package repofake
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	_ repository.TokenStore        = (*Tokens)(nil)
+	_ repository.PasswordResetRepo = (*PasswordResets)(nil)
+)
+
+// Tokens — in-memory repository.TokenStore: блоклист access-токенов, refresh-токены,
+// отзыв токенов пользователя и сессий.
+type Tokens struct {
+	mu          sync.Mutex
+	blacklist   map[string]time.Time // токен -> exp
+	refresh     map[int]map[string]bool
+	revokedAt   map[int]time.Time
+	revokedSess map[int64]time.Time // сессия -> until
+}
+
+func NewTokens() *Tokens {
+	return &Tokens{
+		blacklist:   map[string]time.Time{},
+		refresh:     map[int]map[string]bool{},
+		revokedAt:   map[int]time.Time{},
+		revokedSess: map[int64]time.Time{},
+	}
+}
+
+func (s *Tokens) AddAccessTokenToBlacklist(ctx context.Context, token string, exp time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blacklist[token] = exp
+	return nil
+}
+
+func (s *Tokens) IsAccessTokenBlacklisted(ctx context.Context, token string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.blacklist[token]
+	return ok, nil
+}
+
+func (s *Tokens) TokenStatus(ctx context.Context, token string, userID int, sessionID int64) (repository.TokenStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, blacklisted := s.blacklist[token]
+	_, sessionRevoked := s.revokedSess[sessionID]
+	return repository.TokenStatus{Blacklisted: blacklisted, RevokedAt: s.revokedAt[userID], SessionRevoked: sessionRevoked}, nil
+}
+
+func (s *Tokens) SaveRefreshToken(ctx context.Context, userID int, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.refresh[userID] == nil {
+		s.refresh[userID] = map[string]bool{}
+	}
+	s.refresh[userID][token] = true
+	return nil
+}
+
+func (s *Tokens) IsRefreshTokenValid(ctx context.Context, userID int, token string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.refresh[userID][token], nil
+}
+
+func (s *Tokens) DeleteRefreshToken(ctx context.Context, userID int, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.refresh[userID], token)
+	return nil
+}
+
+// RevokeUserTokens — запоминает самый поздний момент отзыва и удаляет refresh-токены.
+func (s *Tokens) RevokeUserTokens(ctx context.Context, userID int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if at.After(s.revokedAt[userID]) {
+		s.revokedAt[userID] = at
+	}
+	delete(s.refresh, userID)
+	return nil
+}
+
+func (s *Tokens) TokensRevokedAt(ctx context.Context, userID int) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.revokedAt[userID], nil
+}
+
+func (s *Tokens) RevokeSession(ctx context.Context, sessionID int64, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revokedSess[sessionID] = until
+	return nil
+}
+
+func (s *Tokens) IsSessionRevoked(ctx context.Context, sessionID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.revokedSess[sessionID]
+	return ok, nil
+}
+
+// PasswordResets — in-memory repository.PasswordResetRepo; пользователи и их пароли — в Users.
+type PasswordResets struct {
+	users *Users
+
+	mu     sync.Mutex
+	nextID int64
+	tokens map[int64]*models.PasswordResetToken
+}
+
+func NewPasswordResets(users *Users) *PasswordResets {
+	return &PasswordResets{users: users, tokens: map[int64]*models.PasswordResetToken{}}
+}
+
+func (r *PasswordResets) Create(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	r.tokens[r.nextID] = &models.PasswordResetToken{
+		ID:        r.nextID,
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		CreatedAt: Now(),
+	}
+	return nil
+}
+
+// GetValidByHash — неиспользованный и неистёкший токен; иначе pgx.ErrNoRows.
+func (r *PasswordResets) GetValidByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := Now()
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash && t.UsedAt == nil && t.ExpiresAt.After(now) {
+			cp := *t
+			return &cp, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (r *PasswordResets) MarkUsed(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.tokens[id]; ok {
+		t.UsedAt = ptr(Now())
+	}
+	return nil
+}
+
+func (r *PasswordResets) UpdateUserPassword(ctx context.Context, userID int64, passwordHash string) error {
+	r.users.update(int(userID), func(u *models.User) { u.PasswordHash = passwordHash })
+	return nil
+}
+
+func (r *PasswordResets) FindUserIDByEmail(ctx context.Context, email string) (int64, string, error) {
+	r.users.mu.Lock()
+	defer r.users.mu.Unlock()
+	u, err := r.users.find(func(u *models.User) bool { return strings.EqualFold(u.Email, email) })
+	if err != nil {
+		return 0, "", err
+	}
+	return int64(u.ID), u.Locale, nil
+}
@@ -0,0 +1,503 @@
+package repofake
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var _ repository.UserRepo = (*Users)(nil)
+
+// Users — in-memory repository.UserRepo.
+type Users struct {
+	mu       sync.Mutex
+	nextID   int
+	byID     map[int]*models.User
+	deletion map[int]*time.Time // deletion_scheduled_at
+
+	// Для фильтров аудитории NotifyRecipients: подписки на разделы (section_follows),
+	// дерево разделов и их вкладки. Заполняются сценарием напрямую.
+	Follows  map[int][]int // пользователь -> разделы
+	Sections map[int]*int  // раздел -> родитель (nil — корневой)
+	TabOf    map[int]int   // раздел -> вкладка
+}
+
+func NewUsers() *Users {
+	return &Users{
+		byID:     map[int]*models.User{},
+		deletion: map[int]*time.Time{},
+		Follows:  map[int][]int{},
+		Sections: map[int]*int{},
+		TabOf:    map[int]int{},
+	}
+}
+
+// Add — кладёт пользователя как есть (ID 0 — выдаётся автоматически); для подготовки сценариев.
+func (r *Users) Add(u models.User) *models.User {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.insert(u)
+}
+
+// ScheduleDeletion — отметка deletion_scheduled_at для DeletionScheduledAt.
+func (r *Users) ScheduleDeletion(userID int, at *time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deletion[userID] = at
+}
+
+func (r *Users) insert(u models.User) *models.User {
+	if u.ID == 0 {
+		r.nextID++
+		u.ID = r.nextID
+	} else if u.ID > r.nextID {
+		r.nextID = u.ID
+	}
+	now := Now()
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = now
+	}
+	if u.UpdatedAt.IsZero() {
+		u.UpdatedAt = now
+	}
+	if u.Locale == "" {
+		u.Locale = "ru"
+	}
+	if u.EmailFrequency == "" {
+		u.EmailFrequency = models.EmailFrequencyImmediate
+	}
+	cp := u
+	r.byID[u.ID] = &cp
+	return r.copyOf(&cp)
+}
+
+func (r *Users) copyOf(u *models.User) *models.User {
+	cp := *u
+	if u.SubscriptionExpiresAt != nil {
+		cp.SubscriptionExpiresAt = ptr(*u.SubscriptionExpiresAt)
+	}
+	if u.AvatarKey != nil {
+		cp.AvatarKey = ptr(*u.AvatarKey)
+	}
+	return &cp
+}
+
+// find — первый пользователь по условию в порядке ID.
+func (r *Users) find(match func(u *models.User) bool) (*models.User, error) {
+	ids := r.sortedIDs()
+	for _, id := range ids {
+		if u := r.byID[id]; match(u) {
+			return r.copyOf(u), nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (r *Users) sortedIDs() []int {
+	ids := make([]int, 0, len(r.byID))
+	for id := range r.byID {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// newestFirst — как ORDER BY created_at DESC (при равенстве — новые ID первыми).
+func (r *Users) newestFirst(match func(u *models.User) bool) []*models.User {
+	var out []*models.User
+	for _, u := range r.byID {
+		if match(u) {
+			out = append(out, u)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.After(out[j].CreatedAt)
+		}
+		return out[i].ID > out[j].ID
+	})
+	return out
+}
+
+func (r *Users) IsUsernameTaken(ctx context.Context, username string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err := r.find(func(u *models.User) bool { return u.Username == username })
+	return err == nil, nil
+}
+
+func (r *Users) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err := r.find(func(u *models.User) bool { return strings.EqualFold(u.Email, email) })
+	return err == nil, nil
+}
+
+func (r *Users) CreateUser(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u := *user
+	u.ID = 0
+	u.LoginAlerts = true // как DEFAULT в таблице
+	user.ID = r.insert(u).ID
+	return nil
+}
+
+func (r *Users) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.find(func(u *models.User) bool { return u.Username == username })
+}
+
+func (r *Users) GetAllUsersPaginated(ctx context.Context, limit, offset int) ([]*models.User, int, error) {
+	return r.GetUsersFiltered(ctx, limit, offset, "", nil, nil)
+}
+
+func (r *Users) GetUserByID(ctx context.Context, id int) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.byID[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return r.copyOf(u), nil
+}
+
+func (r *Users) UpdateUserFields(ctx context.Context, id int, input *models.UpdateUserRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.byID[id]
+	if !ok {
+		return nil // UPDATE без строк — не ошибка
+	}
+	if input.FullName != nil {
+		u.FullName = *input.FullName
+	}
+	if input.Email != nil {
+		// другой адрес ещё не подтверждён
+		u.EmailVerified = u.EmailVerified && strings.EqualFold(u.Email, *input.Email)
+		u.Email = *input.Email
+	}
+	if input.Phone != nil {
+		// другой номер ещё не подтверждён
+		u.PhoneVerified = u.PhoneVerified && u.Phone == *input.Phone
+		u.Phone = *input.Phone
+	}
+	if input.Address != nil {
+		u.Address = *input.Address
+	}
+	if input.Role != nil {
+		u.Role = *input.Role
+	}
+	if input.Locale != nil {
+		u.Locale = *input.Locale
+	}
+	u.UpdatedAt = Now()
+	return nil
+}
+
+func (r *Users) UpdateSubscriptionStatus(ctx context.Context, userID int, status bool) error {
+	r.update(userID, func(u *models.User) {
+		u.HasSubscription = status
+		if !status {
+			u.SubscriptionExpiresAt = nil
+		}
+	})
+	return nil
+}
+
+// update — fn над пользователем под блокировкой; пользователя нет — ничего не делает.
+func (r *Users) update(userID int, fn func(u *models.User)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if u, ok := r.byID[userID]; ok {
+		fn(u)
+	}
+}
+
+// NotifyRecipients — подписчики рассылки по аудитории; разделы и вкладки берутся из
+// Follows, Sections и TabOf.
+func (r *Users) NotifyRecipients(ctx context.Context, a models.NotifyAudience) ([]models.NotifyRecipient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []models.NotifyRecipient
+	for _, id := range r.sortedIDs() {
+		u := r.byID[id]
+		if !u.EmailSubscription {
+			continue
+		}
+		if a.Role != nil && strings.TrimSpace(*a.Role) != "" && u.Role != strings.TrimSpace(*a.Role) {
+			continue
+		}
+		if a.HasSubscription != nil && u.HasSubscription != *a.HasSubscription {
+			continue
+		}
+		if a.TabID != nil && !r.followsTab(u.ID, *a.TabID) {
+			continue
+		}
+		if a.SectionID != nil && !r.followsSectionOrAncestor(u.ID, *a.SectionID) {
+			continue
+		}
+		if a.CreatedFrom != nil && u.CreatedAt.Before(*a.CreatedFrom) {
+			continue
+		}
+		if a.CreatedTo != nil && !u.CreatedAt.Before(*a.CreatedTo) {
+			continue
+		}
+		out = append(out, models.NotifyRecipient{Email: u.Email, FullName: u.FullName, Username: u.Username, Locale: u.Locale})
+	}
+	return out, nil
+}
+
+func (r *Users) followsTab(userID, tabID int) bool {
+	for _, s := range r.Follows[userID] {
+		if tab, ok := r.TabOf[s]; ok && tab == tabID {
+			return true
+		}
+	}
+	return false
+}
+
+// followsSectionOrAncestor — подписан на раздел или на любого его предка.
+func (r *Users) followsSectionOrAncestor(userID, sectionID int) bool {
+	for id, seen := &sectionID, map[int]bool{}; id != nil && !seen[*id]; id = r.Sections[*id] {
+		seen[*id] = true
+		for _, s := range r.Follows[userID] {
+			if s == *id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *Users) UpdateEmailSubscription(ctx context.Context, userID int, subscribe bool) error {
+	r.update(userID, func(u *models.User) { u.EmailSubscription = subscribe })
+	return nil
+}
+
+func (r *Users) UpdateEmailFrequency(ctx context.Context, userID int, frequency string) error {
+	r.update(userID, func(u *models.User) { u.EmailFrequency = frequency })
+	return nil
+}
+
+func (r *Users) UpdateLoginAlerts(ctx context.Context, userID int, enabled bool) error {
+	r.update(userID, func(u *models.User) { u.LoginAlerts = enabled })
+	return nil
+}
+
+func (r *Users) SetEmailVerified(ctx context.Context, userID int, verified bool) error {
+	r.update(userID, func(u *models.User) { u.EmailVerified = verified })
+	return nil
+}
+
+func (r *Users) SetAvatar(ctx context.Context, userID int, key *string) (*string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.byID[userID]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	prev := u.AvatarKey
+	u.AvatarKey = key
+	return prev, nil
+}
+
+func (r *Users) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.find(func(u *models.User) bool { return strings.EqualFold(u.Email, email) })
+}
+
+func (r *Users) DeleteUserByID(ctx context.Context, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.byID, userID)
+	delete(r.deletion, userID)
+	return nil
+}
+
+func (r *Users) SetSubscriptionWithExpiry(ctx context.Context, userID int, duration time.Duration) error {
+	r.update(userID, func(u *models.User) {
+		u.HasSubscription = true
+		u.SubscriptionExpiresAt = ptr(Now().Add(duration))
+	})
+	return nil
+}
+
+func (r *Users) ExpireSubscriptions(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := Now()
+	for _, u := range r.byID {
+		if u.HasSubscription && u.SubscriptionExpiresAt != nil && !u.SubscriptionExpiresAt.After(now) {
+			u.HasSubscription = false
+		}
+	}
+	return nil
+}
+
+func (r *Users) ExtendSubscription(ctx context.Context, userID int, duration time.Duration) error {
+	r.update(userID, func(u *models.User) {
+		from := Now()
+		if u.SubscriptionExpiresAt != nil {
+			from = *u.SubscriptionExpiresAt
+		}
+		u.HasSubscription = true
+		u.SubscriptionExpiresAt = ptr(from.Add(duration))
+	})
+	return nil
+}
+
+func (r *Users) GrantSubscriptionWithOutbox(
+	ctx context.Context,
+	userID int,
+	duration time.Duration,
+	extend bool,
+	outbox *repository.OutboxRepository,
+	intents func(u *models.User) ([]models.OutboxIntent, error),
+) (*models.User, error) {
+	return nil, ErrUnsupported
+}
+
+// GetUserByPhone — сравнение по последним 10 цифрам номера; подтверждённый номер впереди,
+// затем меньший ID.
+func (r *Users) GetUserByPhone(ctx context.Context, phoneDigits string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	want := lastDigits(phoneDigits, 10)
+	if u, err := r.find(func(u *models.User) bool { return u.PhoneVerified && lastDigits(u.Phone, 10) == want }); err == nil {
+		return u, nil
+	}
+	return r.find(func(u *models.User) bool { return lastDigits(u.Phone, 10) == want })
+}
+
+func lastDigits(s string, n int) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			b.WriteRune(c)
+		}
+	}
+	d := b.String()
+	if len(d) > n {
+		d = d[len(d)-n:]
+	}
+	return d
+}
+
+// GetSystemStats — счётчики пользователей; новости, документы и статьи фейк не знает (0).
+func (r *Users) GetSystemStats(ctx context.Context) (*models.SystemStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := Now()
+	var s models.SystemStats
+	for _, u := range r.byID {
+		s.TotalUsers++
+		if u.Role == "admin" {
+			s.Admins++
+		} else {
+			s.RegularUsers++
+		}
+		expired := u.SubscriptionExpiresAt != nil && !u.SubscriptionExpiresAt.After(now)
+		if u.HasSubscription && !expired {
+			s.WithSubscription++
+		} else {
+			s.WithoutSubscription++
+		}
+	}
+	if s.TotalUsers > 0 {
+		s.WithSubscriptionPct = int(float64(s.WithSubscription)*100.0/float64(s.TotalUsers) + 0.5)
+		s.WithoutSubscriptionPct = 100 - s.WithSubscriptionPct
+	}
+	return &s, nil
+}
+
+func (r *Users) GetUsersFiltered(
+	ctx context.Context,
+	limit, offset int,
+	q string,
+	role *string,
+	hasSubscription *bool,
+) ([]*models.User, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	all := r.newestFirst(usersFilter(q, role, hasSubscription))
+	var out []*models.User
+	for _, u := range page(all, limit, offset) {
+		cp := r.copyOf(u)
+		cp.PasswordHash = "" // список не выбирает password_hash
+		out = append(out, cp)
+	}
+	return out, len(all), nil
+}
+
+// usersFilter — те же условия, что usersFilterWhere в SQL-реализации.
+func usersFilter(q string, role *string, hasSubscription *bool) func(u *models.User) bool {
+	q = strings.TrimSpace(q)
+	return func(u *models.User) bool {
+		if q != "" && !containsFold(u.FullName, q) && !containsFold(u.Email, q) {
+			return false
+		}
+		if role != nil && strings.TrimSpace(*role) != "" && u.Role != strings.TrimSpace(*role) {
+			return false
+		}
+		return hasSubscription == nil || u.HasSubscription == *hasSubscription
+	}
+}
+
+func (r *Users) FilterUserIDs(ctx context.Context, q string, role *string, hasSubscription *bool, limit int) ([]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := usersFilter(q, role, hasSubscription)
+	var ids []int
+	for _, id := range r.sortedIDs() {
+		if limit > 0 && len(ids) >= limit {
+			break
+		}
+		if match(r.byID[id]) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *Users) BulkApply(ctx context.Context, ids []int, allOrNothing bool, outbox *repository.OutboxRepository, op repository.UserBulkOp) (map[int]error, error) {
+	return nil, ErrUnsupported
+}
+
+func (r *Users) GrantSubscriptionTx(ctx context.Context, tx pgx.Tx, userID int, duration time.Duration, extend bool) (*models.User, error) {
+	return nil, ErrUnsupported
+}
+
+func (r *Users) RevokeSubscriptionTx(ctx context.Context, tx pgx.Tx, userID int) (*models.User, *time.Time, error) {
+	return nil, nil, ErrUnsupported
+}
+
+func (r *Users) SetRoleTx(ctx context.Context, tx pgx.Tx, userID int, role string) error {
+	return ErrUnsupported
+}
+
+func (r *Users) DeleteUserTx(ctx context.Context, tx pgx.Tx, userID int) error {
+	return ErrUnsupported
+}
+
+func (r *Users) DeletionScheduledAt(ctx context.Context, userID int) (*time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byID[userID]; !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return r.deletion[userID], nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// SectionFollowRepository — разделы, на новые документы в которых подписан пользователь.
+type SectionFollowRepository struct {
+	db DB
+}
+
+func NewSectionFollowRepository(db DB) *SectionFollowRepository {
+	return &SectionFollowRepository{db: db}
+}
+
+// sectionFollowersCTE — пользователи, отслеживающие раздел $1 или любой из его родителей:
+// подписка на раздел включает его подразделы.
+const sectionFollowersCTE = `
+WITH RECURSIVE up AS (
+	SELECT id, parent_section_id FROM sections WHERE id = $1
+	UNION
+	SELECT s.id, s.parent_section_id FROM sections s JOIN up ON s.id = up.parent_section_id
+), followers AS (
+	SELECT DISTINCT user_id FROM section_follows WHERE section_id IN (SELECT id FROM up)
+)`
+
+// Follow — подписка на раздел; found=false — активного раздела с таким id нет.
+// Повторная подписка не ошибка.
+func (r *SectionFollowRepository) Follow(ctx context.Context, userID, sectionID int) (found bool, err error) {
+	log := logger.WithCtx(ctx)
+
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM sections WHERE id = $1 AND is_active)`, sectionID).Scan(&found); err != nil {
+		log.Error("section follow repo: section check failed", zap.Error(err), zap.Int("section_id", sectionID))
+		return false, err
+	}
+	if !found {
+		return false, nil
+	}
+	if _, err := r.db.Exec(ctx, `
+		INSERT INTO section_follows (user_id, section_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, userID, sectionID); err != nil {
+		log.Error("section follow repo: follow failed", zap.Error(err), zap.Int("user_id", userID), zap.Int("section_id", sectionID))
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *SectionFollowRepository) Unfollow(ctx context.Context, userID, sectionID int) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM section_follows WHERE user_id = $1 AND section_id = $2`, userID, sectionID)
+	if err != nil {
+		logger.WithCtx(ctx).Error("section follow repo: unfollow failed", zap.Error(err), zap.Int("user_id", userID), zap.Int("section_id", sectionID))
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// FollowerEmails — адреса подписчиков групповой рассылки (подтверждённая почта, email_subscription,
+// письма сразу), отслеживающих раздел или его родителя.
+func (r *SectionFollowRepository) FollowerEmails(ctx context.Context, sectionID int) ([]string, error) {
+	rows, err := r.db.Query(ctx, sectionFollowersCTE+`
+		SELECT u.email FROM users u JOIN followers f ON f.user_id = u.id
+		WHERE u.email_verified AND u.email_subscription AND u.email_frequency = 'immediate'`, sectionID)
+	if err != nil {
+		logger.WithCtx(ctx).Error("section follow repo: follower emails failed", zap.Error(err), zap.Int("section_id", sectionID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []string{}
+	for rows.Next() {
+		var e string
+		if err := rows.Scan(&e); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// List — отслеживаемые разделы пользователя в порядке вкладок и разделов.
+func (r *SectionFollowRepository) List(ctx context.Context, userID int) ([]models.SectionFollow, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT s.id, s.title, t.slug, s.slug, f.created_at
+		FROM section_follows f
+		JOIN sections s ON s.id = f.section_id
+		JOIN tabs t ON t.id = s.tab_id
+		WHERE f.user_id = $1
+		ORDER BY t.position, s.position, s.id`, userID)
+	if err != nil {
+		logger.WithCtx(ctx).Error("section follow repo: list failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.SectionFollow{}
+	for rows.Next() {
+		var f models.SectionFollow
+		if err := rows.Scan(&f.SectionID, &f.Title, &f.TabSlug, &f.Slug, &f.FollowedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type SessionRepository struct {
+	db DB
+}
+
+func NewSessionRepository(db DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create — новая сессия; заодно удаляются давно истёкшие сессии пользователя.
+func (r *SessionRepository) Create(ctx context.Context, userID int, client models.SessionClient, expiresAt time.Time) (*models.UserSession, error) {
+	log := logger.WithCtx(ctx)
+
+	s := models.UserSession{UserID: userID, UserAgent: client.UserAgent, IP: client.IP, ExpiresAt: expiresAt}
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		const cleanup = `DELETE FROM user_sessions WHERE user_id = $1 AND expires_at < NOW() - INTERVAL '7 days'`
+		if _, err := tx.Exec(ctx, cleanup, userID); err != nil {
+			return err
+		}
+		const q = `
+			INSERT INTO user_sessions (user_id, user_agent, ip, expires_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at
+		`
+		return tx.QueryRow(ctx, q, userID, client.UserAgent, client.IP, expiresAt).Scan(&s.ID, &s.CreatedAt)
+	})
+	if err != nil {
+		log.Error("session repo: create failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, err
+	}
+
+	log.Debug("session repo: session created", zap.Int64("id", s.ID), zap.Int("user_id", userID))
+	return &s, nil
+}
+
+// ListActive — действующие (не отозванные и не истёкшие) сессии пользователя, новые первыми.
+func (r *SessionRepository) ListActive(ctx context.Context, userID int) ([]models.UserSession, error) {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+		SELECT id, user_id, user_agent, ip, created_at, expires_at
+		FROM user_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(ctx, q, userID)
+	if err != nil {
+		log.Error("session repo: list failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]models.UserSession, 0)
+	for rows.Next() {
+		var s models.UserSession
+		if err := rows.Scan(&s.ID, &s.UserID, &s.UserAgent, &s.IP, &s.CreatedAt, &s.ExpiresAt); err != nil {
+			log.Error("session repo: scan failed", zap.Error(err))
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("session repo: rows error", zap.Error(err))
+		return nil, err
+	}
+	return list, nil
+}
+
+// Revoke — отзывает действующую сессию id пользователя userID; pgx.ErrNoRows — такой нет
+// (чужая, уже отозвана или истекла).
+func (r *SessionRepository) Revoke(ctx context.Context, userID int, id int64) (*models.UserSession, error) {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+		UPDATE user_sessions
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL AND expires_at > NOW()
+		RETURNING id, user_id, user_agent, ip, created_at, expires_at, revoked_at
+	`
+	var s models.UserSession
+	if err := r.db.QueryRow(ctx, q, id, userID).Scan(&s.ID, &s.UserID, &s.UserAgent, &s.IP, &s.CreatedAt, &s.ExpiresAt, &s.RevokedAt); err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("session repo: revoke failed", zap.Error(err), zap.Int64("id", id))
+		}
+		return nil, err
+	}
+
+	log.Info("session repo: session revoked", zap.Int64("id", id), zap.Int("user_id", userID))
+	return &s, nil
+}
+
+// RememberDevice — отмечает вход с пары IP + User-Agent. isNew — с неё раньше не входили;
+// known — сколько устройств пользователя было известно до этого входа.
+func (r *SessionRepository) RememberDevice(ctx context.Context, userID int, client models.SessionClient) (isNew bool, known int, err error) {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+		WITH prior AS (
+			SELECT count(*) AS n FROM user_devices WHERE user_id = $1
+		), seen AS (
+			INSERT INTO user_devices (user_id, fingerprint, ip, user_agent)
+			VALUES ($1, md5($2 || '|' || $3), $2, $3)
+			ON CONFLICT (user_id, fingerprint) DO UPDATE SET last_seen_at = NOW()
+			RETURNING (xmax = 0) AS inserted
+		)
+		SELECT seen.inserted, prior.n FROM seen, prior
+	`
+	if err = r.db.QueryRow(ctx, q, userID, client.IP, client.UserAgent).Scan(&isNew, &known); err != nil {
+		log.Error("session repo: remember device failed", zap.Error(err), zap.Int("user_id", userID))
+		return false, 0, err
+	}
+
+	log.Debug("session repo: device seen", zap.Int("user_id", userID), zap.Bool("new", isNew), zap.Int("known", known))
+	return isNew, known, nil
+}
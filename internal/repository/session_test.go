@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"edutalks/internal/models"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestSessionCreate(t *testing.T) {
+	mock := newMock(t)
+	repo := NewSessionRepository(mock)
+
+	client := models.SessionClient{UserAgent: "Firefox", IP: "203.0.113.7"}
+	expires := time.Now().Add(time.Hour)
+	created := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlRe("DELETE FROM user_sessions WHERE user_id = $1 AND expires_at <")).
+		WithArgs(3).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectQuery(sqlRe("INSERT INTO user_sessions (user_id, user_agent, ip, expires_at)", "RETURNING id, created_at")).
+		WithArgs(3, "Firefox", "203.0.113.7", expires).
+		WillReturnRows(mock.NewRows([]string{"id", "created_at"}).AddRow(int64(55), created))
+	mock.ExpectCommit()
+
+	s, err := repo.Create(context.Background(), 3, client, expires)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if s.ID != 55 || s.UserID != 3 || s.IP != client.IP || !s.CreatedAt.Equal(created) {
+		t.Errorf("сессия = %+v", s)
+	}
+}
+
+func TestSessionCreateRollsBack(t *testing.T) {
+	mock := newMock(t)
+	repo := NewSessionRepository(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(sqlRe("DELETE FROM user_sessions")).WithArgs(3).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectQuery(sqlRe("INSERT INTO user_sessions")).
+		WithArgs(3, "", "", pgxmock.AnyArg()).
+		WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+
+	if _, err := repo.Create(context.Background(), 3, models.SessionClient{}, time.Now()); err == nil {
+		t.Fatal("ожидалась ошибка")
+	}
+}
+
+func TestSessionRememberDevice(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		inserted bool
+		known    int
+	}{
+		{"новое устройство", true, 2},
+		{"первое устройство", true, 0},
+		{"уже известное", false, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := newMock(t)
+			repo := NewSessionRepository(mock)
+
+			mock.ExpectQuery(sqlRe("SELECT count(*) AS n FROM user_devices WHERE user_id = $1",
+				"INSERT INTO user_devices (user_id, fingerprint, ip, user_agent)", "md5($2 || '|' || $3)",
+				"ON CONFLICT (user_id, fingerprint) DO UPDATE SET last_seen_at = NOW()", "RETURNING (xmax = 0)")).
+				WithArgs(8, "198.51.100.1", "Chrome").
+				WillReturnRows(mock.NewRows([]string{"inserted", "n"}).AddRow(tc.inserted, tc.known))
+
+			isNew, known, err := repo.RememberDevice(context.Background(), 8, models.SessionClient{UserAgent: "Chrome", IP: "198.51.100.1"})
+			if err != nil {
+				t.Fatalf("RememberDevice: %v", err)
+			}
+			if isNew != tc.inserted || known != tc.known {
+				t.Errorf("RememberDevice = %v, %d; ожидалось %v, %d", isNew, known, tc.inserted, tc.known)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"edutalks/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ReminderExpiringSoon = "expiring_soon"
+	ReminderExpired      = "expired"
+)
+
+// SubscriptionReminder — кому и о какой дате окончания подписки нужно написать.
+type SubscriptionReminder struct {
+	UserID    int
+	Email     string
+	FullName  string
+	Locale    string
+	ExpiresAt time.Time
+}
+
+type SubscriptionReminderRepository struct {
+	db DB
+}
+
+func NewSubscriptionReminderRepository(db DB) *SubscriptionReminderRepository {
+	return &SubscriptionReminderRepository{db: db}
+}
+
+// ClaimExpiringSoon — активные подписки, истекающие в ближайшие `within`, о которых ещё не напоминали.
+// Напоминание сразу фиксируется (одно на пользователя и дату окончания), поэтому повторный вызов их не вернёт.
+func (r *SubscriptionReminderRepository) ClaimExpiringSoon(ctx context.Context, within time.Duration) ([]SubscriptionReminder, error) {
+	const q = `
+		WITH due AS (
+			SELECT id, subscription_expires_at
+			FROM users
+			WHERE has_subscription = true
+			  AND subscription_expires_at IS NOT NULL
+			  AND subscription_expires_at > NOW()
+			  AND subscription_expires_at <= NOW() + $1 * interval '1 second'
+			  AND email <> ''
+		), ins AS (
+			INSERT INTO subscription_reminders (user_id, kind, expires_at)
+			SELECT id, 'expiring_soon', subscription_expires_at FROM due
+			ON CONFLICT (user_id, kind, expires_at) DO NOTHING
+			RETURNING user_id, expires_at
+		)
+		SELECT u.id, u.email, u.full_name, u.locale, ins.expires_at
+		FROM ins JOIN users u ON u.id = ins.user_id
+	`
+	return r.claim(ctx, q, ReminderExpiringSoon, int64(within.Seconds()))
+}
+
+// ClaimExpired — подписки, истёкшие за последние `lookback`, о которых ещё не сообщали.
+func (r *SubscriptionReminderRepository) ClaimExpired(ctx context.Context, lookback time.Duration) ([]SubscriptionReminder, error) {
+	const q = `
+		WITH due AS (
+			SELECT id, subscription_expires_at
+			FROM users
+			WHERE has_subscription = false
+			  AND subscription_expires_at IS NOT NULL
+			  AND subscription_expires_at <= NOW()
+			  AND subscription_expires_at > NOW() - $1 * interval '1 second'
+			  AND email <> ''
+		), ins AS (
+			INSERT INTO subscription_reminders (user_id, kind, expires_at)
+			SELECT id, 'expired', subscription_expires_at FROM due
+			ON CONFLICT (user_id, kind, expires_at) DO NOTHING
+			RETURNING user_id, expires_at
+		)
+		SELECT u.id, u.email, u.full_name, u.locale, ins.expires_at
+		FROM ins JOIN users u ON u.id = ins.user_id
+	`
+	return r.claim(ctx, q, ReminderExpired, int64(lookback.Seconds()))
+}
+
+func (r *SubscriptionReminderRepository) claim(ctx context.Context, q, kind string, seconds int64) ([]SubscriptionReminder, error) {
+	log := logger.WithCtx(ctx)
+
+	rows, err := r.db.Query(ctx, q, seconds)
+	if err != nil {
+		log.Error("subscription reminder repo: claim failed", zap.Error(err), zap.String("kind", kind))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SubscriptionReminder
+	for rows.Next() {
+		var it SubscriptionReminder
+		if err := rows.Scan(&it.UserID, &it.Email, &it.FullName, &it.Locale, &it.ExpiresAt); err != nil {
+			log.Error("subscription reminder repo: scan failed", zap.Error(err), zap.String("kind", kind))
+			return nil, err
+		}
+		out = append(out, it)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("subscription reminder repo: rows error", zap.Error(err), zap.String("kind", kind))
+		return nil, err
+	}
+
+	log.Debug("subscription reminder repo: claimed", zap.String("kind", kind), zap.Int("count", len(out)))
+	return out, nil
+}
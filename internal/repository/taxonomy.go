@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -10,15 +11,14 @@ import (
 	"edutalks/internal/models"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
 type TaxonomyRepo struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewTaxonomyRepo(db *pgxpool.Pool) *TaxonomyRepo { return &TaxonomyRepo{db: db} }
+func NewTaxonomyRepo(db DB) *TaxonomyRepo { return &TaxonomyRepo{db: db} }
 
 // ----- Tabs -----
 
@@ -27,8 +27,9 @@ func (r *TaxonomyRepo) CreateTab(ctx context.Context, t *models.Tab) (int, error
 
 	var id int
 	if err := r.db.QueryRow(ctx,
-		`INSERT INTO tabs (slug, title, position, is_active) VALUES ($1,$2,$3,$4) RETURNING id`,
-		t.Slug, t.Title, t.Position, t.IsActive,
+		`INSERT INTO tabs (slug, title, position, is_active, meta_title, meta_description, og_image_url)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id`,
+		t.Slug, t.Title, t.Position, t.IsActive, t.MetaTitle, t.MetaDescription, t.OGImageURL,
 	).Scan(&id); err != nil {
 		log.Error("taxonomy repo: create tab failed", zap.Error(err), zap.String("slug", t.Slug))
 		return 0, err
@@ -42,8 +43,9 @@ func (r *TaxonomyRepo) UpdateTab(ctx context.Context, t *models.Tab) error {
 	log := logger.WithCtx(ctx)
 
 	_, err := r.db.Exec(ctx,
-		`UPDATE tabs SET slug=$1, title=$2, position=$3, is_active=$4, updated_at=now() WHERE id=$5`,
-		t.Slug, t.Title, t.Position, t.IsActive, t.ID,
+		`UPDATE tabs SET slug=$1, title=$2, position=$3, is_active=$4,
+		 meta_title=$5, meta_description=$6, og_image_url=$7, updated_at=now() WHERE id=$8`,
+		t.Slug, t.Title, t.Position, t.IsActive, t.MetaTitle, t.MetaDescription, t.OGImageURL, t.ID,
 	)
 	if err != nil {
 		log.Error("taxonomy repo: update tab failed", zap.Error(err), zap.Int("id", t.ID))
@@ -74,9 +76,9 @@ func (r *TaxonomyRepo) CreateSection(ctx context.Context, s *models.Section) (in
 
 	var id int
 	if err := r.db.QueryRow(ctx,
-		`INSERT INTO sections (tab_id, slug, title, description, position, is_active)
-		 VALUES ($1,$2,$3,$4,$5,$6) RETURNING id`,
-		s.TabID, s.Slug, s.Title, s.Description, s.Position, s.IsActive,
+		`INSERT INTO sections (tab_id, slug, title, description, position, is_active, meta_title, meta_description, og_image_url, parent_section_id)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING id`,
+		s.TabID, s.Slug, s.Title, s.Description, s.Position, s.IsActive, s.MetaTitle, s.MetaDescription, s.OGImageURL, s.ParentSectionID,
 	).Scan(&id); err != nil {
 		log.Error("taxonomy repo: create section failed", zap.Error(err), zap.String("slug", s.Slug), zap.Int("tab_id", s.TabID))
 		return 0, err
@@ -90,8 +92,9 @@ func (r *TaxonomyRepo) UpdateSection(ctx context.Context, s *models.Section) err
 	log := logger.WithCtx(ctx)
 
 	_, err := r.db.Exec(ctx,
-		`UPDATE sections SET slug=$1, title=$2, description=$3, position=$4, is_active=$5, updated_at=now() WHERE id=$6`,
-		s.Slug, s.Title, s.Description, s.Position, s.IsActive, s.ID,
+		`UPDATE sections SET slug=$1, title=$2, description=$3, position=$4, is_active=$5,
+		 meta_title=$6, meta_description=$7, og_image_url=$8, parent_section_id=$9, updated_at=now() WHERE id=$10`,
+		s.Slug, s.Title, s.Description, s.Position, s.IsActive, s.MetaTitle, s.MetaDescription, s.OGImageURL, s.ParentSectionID, s.ID,
 	)
 	if err != nil {
 		log.Error("taxonomy repo: update section failed", zap.Error(err), zap.Int("id", s.ID))
@@ -102,17 +105,163 @@ func (r *TaxonomyRepo) UpdateSection(ctx context.Context, s *models.Section) err
 	return nil
 }
 
-func (r *TaxonomyRepo) DeleteSection(ctx context.Context, id int) error {
+var (
+	// ErrSectionMoveTarget — раздел для переноса документов не найден или входит в удаляемое поддерево.
+	ErrSectionMoveTarget = errors.New("move target section is missing or inside the deleted subtree")
+	// ErrSectionNotEmpty — в режиме block у раздела есть документы или подразделы.
+	ErrSectionNotEmpty = errors.New("section is not empty")
+)
+
+// sectionSubtreeCTE — раздел $1 и все его подразделы.
+const sectionSubtreeCTE = `
+WITH RECURSIVE sub AS (
+	SELECT id FROM sections WHERE id = $1
+	UNION
+	SELECT s.id FROM sections s JOIN sub ON s.parent_section_id = sub.id
+)`
+
+// DeleteSection — удаляет раздел вместе с подразделами в одной транзакции; с документами
+// поддерева поступает по mode (models.SectionDeleteBlock | Detach | Reassign в раздел moveTo).
+// При ErrSectionNotEmpty в результате — что мешает удалению; pgx.ErrNoRows — раздела нет.
+func (r *TaxonomyRepo) DeleteSection(ctx context.Context, id int, mode string, moveTo *int) (*models.SectionDeleteResult, error) {
 	log := logger.WithCtx(ctx)
 
-	_, err := r.db.Exec(ctx, `DELETE FROM sections WHERE id=$1`, id)
-	if err != nil {
-		log.Error("taxonomy repo: delete section failed", zap.Error(err), zap.Int("id", id))
+	res := &models.SectionDeleteResult{Mode: mode, MoveTo: moveTo}
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		// блокируем раздел: параллельная загрузка в него дождётся конца удаления
+		var locked int
+		if err := tx.QueryRow(ctx, `SELECT id FROM sections WHERE id = $1 FOR UPDATE`, id).Scan(&locked); err != nil {
+			return err
+		}
+		if err := tx.QueryRow(ctx, sectionSubtreeCTE+`
+			SELECT (SELECT COUNT(*) FROM sub) - 1,
+			       (SELECT COUNT(*) FROM documents WHERE section_id IN (SELECT id FROM sub))`, id,
+		).Scan(&res.Subsections, &res.Documents); err != nil {
+			return err
+		}
+
+		switch mode {
+		case models.SectionDeleteBlock:
+			if res.Documents > 0 || res.Subsections > 0 {
+				return ErrSectionNotEmpty
+			}
+		case models.SectionDeleteReassign:
+			var ok bool
+			if err := tx.QueryRow(ctx, sectionSubtreeCTE+`
+				SELECT EXISTS (SELECT 1 FROM sections WHERE id = $2)
+				   AND NOT EXISTS (SELECT 1 FROM sub WHERE id = $2)`,
+				id, moveTo,
+			).Scan(&ok); err != nil {
+				return err
+			}
+			if !ok {
+				return ErrSectionMoveTarget
+			}
+			tag, err := tx.Exec(ctx, sectionSubtreeCTE+`
+				UPDATE documents SET section_id = $2 WHERE section_id IN (SELECT id FROM sub)`,
+				id, moveTo,
+			)
+			if err != nil {
+				return err
+			}
+			res.DocumentsMoved = int(tag.RowsAffected())
+		case models.SectionDeleteDetach:
+			// documents.section_id — ON DELETE SET NULL
+			res.DocumentsDetached = res.Documents
+		default:
+			return fmt.Errorf("taxonomy repo: unknown delete mode %q", mode)
+		}
+
+		_, err := tx.Exec(ctx, `DELETE FROM sections WHERE id = $1`, id)
 		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSectionNotEmpty):
+			return res, err
+		case err != pgx.ErrNoRows && !errors.Is(err, ErrSectionMoveTarget):
+			log.Error("taxonomy repo: delete section failed", zap.Error(err), zap.Int("id", id), zap.String("mode", mode))
+		}
+		return nil, err
 	}
 
-	log.Info("taxonomy repo: section deleted", zap.Int("id", id))
-	return nil
+	log.Info("taxonomy repo: section deleted", zap.Int("id", id), zap.String("mode", mode),
+		zap.Int("subsections", res.Subsections), zap.Int("documents_moved", res.DocumentsMoved), zap.Int("documents_detached", res.DocumentsDetached))
+	return res, nil
+}
+
+// ----- Ordering -----
+
+// ErrReorderUnknownID — в новом порядке есть ID не из переупорядочиваемого списка.
+var ErrReorderUnknownID = errors.New("reorder: id does not belong to the list")
+
+// ReorderTabs — позиции вкладок по списку ids; возвращает итоговый порядок всех вкладок.
+func (r *TaxonomyRepo) ReorderTabs(ctx context.Context, ids []int) ([]int, error) {
+	return r.reorder(ctx, "tabs", "", nil, ids)
+}
+
+// ReorderSections — позиции разделов вкладки tabID; возвращает итоговый порядок её разделов.
+func (r *TaxonomyRepo) ReorderSections(ctx context.Context, tabID int, ids []int) ([]int, error) {
+	return r.reorder(ctx, "sections", "WHERE tab_id = $1", []any{tabID}, ids)
+}
+
+// reorder — в одной транзакции блокирует строки списка, ставит ids первыми, остальные —
+// следом в прежнем порядке, и перенумеровывает позиции 1..N (пропуски и дубли исчезают).
+func (r *TaxonomyRepo) reorder(ctx context.Context, table, scope string, scopeArgs []any, ids []int) ([]int, error) {
+	log := logger.WithCtx(ctx).With(zap.String("table", table))
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		log.Error("taxonomy repo: reorder begin tx failed", zap.Error(err))
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `SELECT id FROM `+table+` `+scope+` ORDER BY position, id FOR UPDATE`, scopeArgs...)
+	if err != nil {
+		log.Error("taxonomy repo: reorder lock failed", zap.Error(err))
+		return nil, err
+	}
+	current, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		log.Error("taxonomy repo: reorder scan failed", zap.Error(err))
+		return nil, err
+	}
+
+	known := make(map[int]bool, len(current))
+	for _, id := range current {
+		known[id] = true
+	}
+	order := make([]int, 0, len(current))
+	for _, id := range ids {
+		if !known[id] {
+			log.Warn("taxonomy repo: reorder unknown id", zap.Int("id", id))
+			return nil, fmt.Errorf("%w: %d", ErrReorderUnknownID, id)
+		}
+		known[id] = false
+		order = append(order, id)
+	}
+	for _, id := range current {
+		if known[id] {
+			order = append(order, id)
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE `+table+` t SET position = v.pos, updated_at = now()
+		FROM unnest($1::int[]) WITH ORDINALITY AS v(id, pos)
+		WHERE t.id = v.id AND t.position <> v.pos`, order)
+	if err != nil {
+		log.Error("taxonomy repo: reorder update failed", zap.Error(err))
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Error("taxonomy repo: reorder commit failed", zap.Error(err))
+		return nil, err
+	}
+
+	log.Info("taxonomy repo: reordered", zap.Int("total", len(order)), zap.Int64("moved", tag.RowsAffected()))
+	return order, nil
 }
 
 // ----- Public tree -----
@@ -125,13 +274,14 @@ WITH s AS (
   SELECT s.*, COALESCE(d.cnt,0) AS docs_count
   FROM sections s
   LEFT JOIN (
-    SELECT section_id, COUNT(*) cnt FROM documents GROUP BY section_id
+    SELECT section_id, COUNT(*) cnt FROM documents WHERE deleted_at IS NULL GROUP BY section_id
   ) d ON d.section_id = s.id
   WHERE s.is_active = true
 )
 SELECT
-  t.id, t.slug, t.title, t.position, t.is_active, t.created_at, t.updated_at,
-  s.id, s.tab_id, s.slug, s.title, s.description, s.position, s.is_active, s.created_at, s.updated_at, s.docs_count
+  t.id, t.slug, t.title, t.position, t.is_active, t.meta_title, t.meta_description, t.og_image_url, t.created_at, t.updated_at,
+  s.id, s.tab_id, s.slug, s.title, s.description, s.position, s.is_active,
+  s.meta_title, s.meta_description, s.og_image_url, s.created_at, s.updated_at, s.docs_count, s.parent_section_id
 FROM tabs t
 LEFT JOIN s ON s.tab_id = t.id
 WHERE t.is_active = true
@@ -159,14 +309,19 @@ ORDER BY t.position, t.id, s.position, s.id;
 			secDesc      sql.NullString
 			secPos       sql.NullInt32
 			secActive    sql.NullBool
+			secMetaTitle sql.NullString
+			secMetaDesc  sql.NullString
+			secOGImage   sql.NullString
 			secCreatedAt sql.NullTime
 			secUpdatedAt sql.NullTime
 			docsCount    sql.NullInt64
+			secParentID  sql.NullInt32
 		)
 
 		if err := rows.Scan(
-			&t.ID, &t.Slug, &t.Title, &t.Position, &t.IsActive, &t.CreatedAt, &t.UpdatedAt,
-			&secID, &secTabID, &secSlug, &secTitle, &secDesc, &secPos, &secActive, &secCreatedAt, &secUpdatedAt, &docsCount,
+			&t.ID, &t.Slug, &t.Title, &t.Position, &t.IsActive, &t.MetaTitle, &t.MetaDescription, &t.OGImageURL, &t.CreatedAt, &t.UpdatedAt,
+			&secID, &secTabID, &secSlug, &secTitle, &secDesc, &secPos, &secActive,
+			&secMetaTitle, &secMetaDesc, &secOGImage, &secCreatedAt, &secUpdatedAt, &docsCount, &secParentID,
 		); err != nil {
 			log.Error("taxonomy repo: scan tree row failed", zap.Error(err))
 			return nil, err
@@ -186,16 +341,26 @@ ORDER BY t.position, t.id, s.position, s.id;
 				Description: secDesc.String,
 				Position:    int(secPos.Int32),
 				IsActive:    secActive.Bool,
-				CreatedAt:   secCreatedAt.Time,
-				UpdatedAt:   secUpdatedAt.Time,
+				SEOMeta: models.SEOMeta{
+					MetaTitle:       secMetaTitle.String,
+					MetaDescription: secMetaDesc.String,
+					OGImageURL:      secOGImage.String,
+				},
+				CreatedAt: secCreatedAt.Time,
+				UpdatedAt: secUpdatedAt.Time,
+			}
+			if secParentID.Valid {
+				parent := int(secParentID.Int32)
+				s.ParentSectionID = &parent
 			}
 			cnt := 0
 			if docsCount.Valid {
 				cnt = int(docsCount.Int64)
 			}
 			cur.Sections = append(cur.Sections, models.SectionWithCount{
-				Section:   s,
-				DocsCount: cnt,
+				Section:      s,
+				DocsCount:    cnt,
+				OwnDocsCount: cnt,
 			})
 		}
 	}
@@ -204,6 +369,9 @@ ORDER BY t.position, t.id, s.position, s.id;
 		log.Error("taxonomy repo: rows error list tree", zap.Error(err))
 		return nil, err
 	}
+	for i := range out {
+		out[i].Sections = nestSections(out[i].Sections)
+	}
 
 	log.Debug("taxonomy repo: list tree done", zap.Int("tabs", len(out)))
 	return out, nil
@@ -217,13 +385,14 @@ func (r *TaxonomyRepo) ListTabTreeFilter(ctx context.Context, tabID *int, tabSlu
 WITH s AS (
   SELECT s.*, COALESCE(d.cnt,0) AS docs_count
   FROM sections s
-  LEFT JOIN (SELECT section_id, COUNT(*) cnt FROM documents GROUP BY section_id) d
+  LEFT JOIN (SELECT section_id, COUNT(*) cnt FROM documents WHERE deleted_at IS NULL GROUP BY section_id) d
     ON d.section_id = s.id
   WHERE s.is_active = true
 )
 SELECT
-  t.id, t.slug, t.title, t.position, t.is_active, t.created_at, t.updated_at,
-  s.id, s.tab_id, s.slug, s.title, s.description, s.position, s.is_active, s.created_at, s.updated_at, s.docs_count
+  t.id, t.slug, t.title, t.position, t.is_active, t.meta_title, t.meta_description, t.og_image_url, t.created_at, t.updated_at,
+  s.id, s.tab_id, s.slug, s.title, s.description, s.position, s.is_active,
+  s.meta_title, s.meta_description, s.og_image_url, s.created_at, s.updated_at, s.docs_count, s.parent_section_id
 FROM tabs t
 LEFT JOIN s ON s.tab_id = t.id
 WHERE t.is_active = true
@@ -267,14 +436,19 @@ WHERE t.is_active = true
 			secDesc      sql.NullString
 			secPos       sql.NullInt32
 			secActive    sql.NullBool
+			secMetaTitle sql.NullString
+			secMetaDesc  sql.NullString
+			secOGImage   sql.NullString
 			secCreatedAt sql.NullTime
 			secUpdatedAt sql.NullTime
 			docsCount    sql.NullInt64
+			secParentID  sql.NullInt32
 		)
 
 		if err := rows.Scan(
-			&t.ID, &t.Slug, &t.Title, &t.Position, &t.IsActive, &t.CreatedAt, &t.UpdatedAt,
-			&secID, &secTabID, &secSlug, &secTitle, &secDesc, &secPos, &secActive, &secCreatedAt, &secUpdatedAt, &docsCount,
+			&t.ID, &t.Slug, &t.Title, &t.Position, &t.IsActive, &t.MetaTitle, &t.MetaDescription, &t.OGImageURL, &t.CreatedAt, &t.UpdatedAt,
+			&secID, &secTabID, &secSlug, &secTitle, &secDesc, &secPos, &secActive,
+			&secMetaTitle, &secMetaDesc, &secOGImage, &secCreatedAt, &secUpdatedAt, &docsCount, &secParentID,
 		); err != nil {
 			log.Error("taxonomy repo: scan tree filter row failed", zap.Error(err))
 			return nil, err
@@ -293,16 +467,26 @@ WHERE t.is_active = true
 				Description: secDesc.String,
 				Position:    int(secPos.Int32),
 				IsActive:    secActive.Bool,
-				CreatedAt:   secCreatedAt.Time,
-				UpdatedAt:   secUpdatedAt.Time,
+				SEOMeta: models.SEOMeta{
+					MetaTitle:       secMetaTitle.String,
+					MetaDescription: secMetaDesc.String,
+					OGImageURL:      secOGImage.String,
+				},
+				CreatedAt: secCreatedAt.Time,
+				UpdatedAt: secUpdatedAt.Time,
+			}
+			if secParentID.Valid {
+				parent := int(secParentID.Int32)
+				s.ParentSectionID = &parent
 			}
 			cnt := 0
 			if docsCount.Valid {
 				cnt = int(docsCount.Int64)
 			}
 			cur.Sections = append(cur.Sections, models.SectionWithCount{
-				Section:   s,
-				DocsCount: cnt,
+				Section:      s,
+				DocsCount:    cnt,
+				OwnDocsCount: cnt,
 			})
 		}
 	}
@@ -310,16 +494,194 @@ WHERE t.is_active = true
 		log.Error("taxonomy repo: rows error list tree filter", zap.Error(err))
 		return nil, err
 	}
+	for i := range out {
+		out[i].Sections = nestSections(out[i].Sections)
+	}
 
 	log.Debug("taxonomy repo: list tree filter done",
 		zap.Any("tab_id", tabID), zap.Any("tab_slug", tabSlug), zap.Int("tabs", len(out)))
 	return out, nil
 }
 
+// ResolvePath — вкладка (и опционально раздел) по slug-ам одним запросом, с количеством документов.
+// Возвращает pgx.ErrNoRows, если вкладка не найдена; раздел == nil, если sectionSlug пуст или не найден.
+func (r *TaxonomyRepo) ResolvePath(ctx context.Context, tabSlug, sectionSlug string) (*models.TaxonomyCrumb, *models.TaxonomyCrumb, error) {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+SELECT
+  t.id, t.slug, t.title, t.meta_title, t.meta_description, t.og_image_url,
+  (SELECT COUNT(*) FROM documents d JOIN sections ds ON ds.id = d.section_id WHERE ds.tab_id = t.id AND ds.is_active AND d.deleted_at IS NULL),
+  s.id, s.slug, s.title, s.meta_title, s.meta_description, s.og_image_url,
+  (SELECT COUNT(*) FROM documents d WHERE d.section_id = s.id AND d.deleted_at IS NULL)
+FROM tabs t
+LEFT JOIN sections s ON s.tab_id = t.id AND s.slug = $2 AND s.is_active = true
+WHERE t.slug = $1 AND t.is_active = true
+`
+	var (
+		tab      models.TaxonomyCrumb
+		tabDocs  int64
+		secID    sql.NullInt32
+		secSlug  sql.NullString
+		secTitle sql.NullString
+		secMeta  sql.NullString
+		secDesc  sql.NullString
+		secOG    sql.NullString
+		secDocs  sql.NullInt64
+	)
+	if err := r.db.QueryRow(ctx, q, tabSlug, sectionSlug).Scan(
+		&tab.ID, &tab.Slug, &tab.Title, &tab.MetaTitle, &tab.MetaDescription, &tab.OGImageURL, &tabDocs,
+		&secID, &secSlug, &secTitle, &secMeta, &secDesc, &secOG, &secDocs,
+	); err != nil {
+		if err == pgx.ErrNoRows {
+			log.Debug("taxonomy repo: resolve path: tab not found", zap.String("tab_slug", tabSlug))
+		} else {
+			log.Error("taxonomy repo: resolve path failed", zap.Error(err), zap.String("tab_slug", tabSlug), zap.String("section_slug", sectionSlug))
+		}
+		return nil, nil, err
+	}
+	tab.Kind = "tab"
+	tab.DocsCount = int(tabDocs)
+
+	var sec *models.TaxonomyCrumb
+	if secID.Valid {
+		sec = &models.TaxonomyCrumb{
+			Kind:      "section",
+			ID:        int(secID.Int32),
+			Slug:      secSlug.String,
+			Title:     secTitle.String,
+			DocsCount: int(secDocs.Int64),
+			SEOMeta: models.SEOMeta{
+				MetaTitle:       secMeta.String,
+				MetaDescription: secDesc.String,
+				OGImageURL:      secOG.String,
+			},
+		}
+	}
+	return &tab, sec, nil
+}
+
+// Search — поиск вкладок и разделов (включая неактивные) по title/slug для пикеров в админке.
+// Сначала вкладки, затем разделы; внутри — сначала совпадения по началу строки.
+func (r *TaxonomyRepo) Search(ctx context.Context, q string, limit int) ([]models.TaxonomySearchItem, error) {
+	log := logger.WithCtx(ctx)
+
+	const query = `
+SELECT kind, id, slug, title, is_active, tab_id, tab_slug, tab_title FROM (
+  SELECT 'tab' AS kind, t.id, t.slug, t.title, t.is_active,
+         NULL::int AS tab_id, NULL::text AS tab_slug, NULL::text AS tab_title,
+         0 AS kind_order, (t.title ILIKE $2 OR t.slug ILIKE $2) AS prefix, t.position AS pos
+  FROM tabs t
+  WHERE t.title ILIKE $1 OR t.slug ILIKE $1
+  UNION ALL
+  SELECT 'section', s.id, s.slug, s.title, s.is_active,
+         t.id, t.slug, t.title,
+         1, (s.title ILIKE $2 OR s.slug ILIKE $2), s.position
+  FROM sections s
+  JOIN tabs t ON t.id = s.tab_id
+  WHERE s.title ILIKE $1 OR s.slug ILIKE $1 OR t.title ILIKE $1
+) x
+ORDER BY kind_order, prefix DESC, pos, id
+LIMIT $3
+`
+	rows, err := r.db.Query(ctx, query, "%"+q+"%", q+"%", limit)
+	if err != nil {
+		log.Error("taxonomy repo: search failed", zap.Error(err), zap.String("q", q))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.TaxonomySearchItem, 0, limit)
+	for rows.Next() {
+		var it models.TaxonomySearchItem
+		if err := rows.Scan(&it.Kind, &it.ID, &it.Slug, &it.Title, &it.IsActive, &it.TabID, &it.TabSlug, &it.TabTitle); err != nil {
+			log.Error("taxonomy repo: scan search row failed", zap.Error(err))
+			return nil, err
+		}
+		if it.TabSlug != nil {
+			it.Path = "/" + *it.TabSlug + "/" + it.Slug
+		} else {
+			it.Path = "/" + it.Slug
+		}
+		out = append(out, it)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("taxonomy repo: rows error search", zap.Error(err))
+		return nil, err
+	}
+
+	log.Debug("taxonomy repo: search done", zap.String("q", q), zap.Int("count", len(out)))
+	return out, nil
+}
+
+// nestSections — плоский список разделов вкладки (в порядке position) → дерево.
+// Счётчики документов суммируются вверх по дереву; разделы, чей родитель скрыт
+// (неактивен), в дерево не попадают — как и всё поддерево скрытого раздела.
+func nestSections(flat []models.SectionWithCount) []models.SectionWithCount {
+	children := map[int][]int{} // id родителя → индексы во flat
+	var roots []int
+	for i, s := range flat {
+		if p := s.Section.ParentSectionID; p != nil {
+			children[*p] = append(children[*p], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	var build func(i, depth int) models.SectionWithCount
+	build = func(i, depth int) models.SectionWithCount {
+		n := flat[i]
+		n.DocsCount = n.OwnDocsCount
+		if depth >= models.MaxSectionDepth {
+			return n
+		}
+		for _, c := range children[n.Section.ID] {
+			child := build(c, depth+1)
+			n.DocsCount += child.DocsCount
+			n.Children = append(n.Children, child)
+		}
+		return n
+	}
+
+	out := make([]models.SectionWithCount, 0, len(roots))
+	for _, i := range roots {
+		out = append(out, build(i, 1))
+	}
+	return out
+}
+
 // ----- Utils -----
 
 func itoa(i int) string { return fmt.Sprintf("%d", i) }
 
+// SectionParents — родители всех разделов вкладки (nil — верхний уровень); для проверки вложенности.
+func (r *TaxonomyRepo) SectionParents(ctx context.Context, tabID int) (map[int]*int, error) {
+	log := logger.WithCtx(ctx)
+
+	rows, err := r.db.Query(ctx, `SELECT id, parent_section_id FROM sections WHERE tab_id = $1`, tabID)
+	if err != nil {
+		log.Error("taxonomy repo: section parents query failed", zap.Error(err), zap.Int("tab_id", tabID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[int]*int{}
+	for rows.Next() {
+		var id int
+		var parent *int
+		if err := rows.Scan(&id, &parent); err != nil {
+			log.Error("taxonomy repo: scan section parent failed", zap.Error(err))
+			return nil, err
+		}
+		out[id] = parent
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("taxonomy repo: rows error section parents", zap.Error(err))
+		return nil, err
+	}
+	return out, nil
+}
+
 func (r *TaxonomyRepo) TabSlugExists(ctx context.Context, slug string) (bool, error) {
 	log := logger.WithCtx(ctx)
 
@@ -395,3 +757,27 @@ func (r *TaxonomyRepo) GetTabIDBySectionID(ctx context.Context, sectionID int) (
 	log.Debug("taxonomy repo: got tab id by section", zap.Int("section_id", sectionID), zap.Int("tab_id", id))
 	return id, nil
 }
+
+// GetSectionPlacement — вкладка и раздел, в которых лежит sectionID (для ссылок и группировки в письмах).
+func (r *TaxonomyRepo) GetSectionPlacement(ctx context.Context, sectionID int) (*models.TaxonomyCrumb, *models.TaxonomyCrumb, error) {
+	log := logger.WithCtx(ctx)
+
+	tab := &models.TaxonomyCrumb{Kind: "tab"}
+	sec := &models.TaxonomyCrumb{Kind: "section", ID: sectionID}
+	if err := r.db.QueryRow(ctx, `
+		SELECT t.id, t.slug, t.title, s.slug, s.title
+		FROM sections s JOIN tabs t ON t.id = s.tab_id
+		WHERE s.id = $1`, sectionID,
+	).Scan(&tab.ID, &tab.Slug, &tab.Title, &sec.Slug, &sec.Title); err != nil {
+		if err == pgx.ErrNoRows {
+			log.Warn("taxonomy repo: section placement not found", zap.Int("section_id", sectionID))
+		} else {
+			log.Error("taxonomy repo: get section placement failed", zap.Error(err), zap.Int("section_id", sectionID))
+		}
+		return nil, nil, err
+	}
+
+	tab.Path = tab.Slug
+	sec.Path = tab.Slug + "/" + sec.Slug
+	return tab, sec, nil
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"edutalks/internal/logger"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// TokenStore — блоклист access-токенов и refresh-токены. Реализации: Postgres
+// (UserRepository, по умолчанию) и Redis (REDIS_URL) — проверка токена (TokenStatus)
+// идёт на каждом авторизованном запросе и не нагружает БД.
+type TokenStore interface {
+	AddAccessTokenToBlacklist(ctx context.Context, token string, exp time.Time) error
+	// TokenStatus — блоклист, отзыв токенов пользователя и отзыв сессии одним обращением
+	// к хранилищу; sessionID 0 — токен без сессии.
+	TokenStatus(ctx context.Context, token string, userID int, sessionID int64) (TokenStatus, error)
+	SaveRefreshToken(ctx context.Context, userID int, token string) error
+	IsRefreshTokenValid(ctx context.Context, userID int, token string) (bool, error)
+	DeleteRefreshToken(ctx context.Context, userID int, token string) error
+	// RevokeUserTokens — все токены пользователя, выпущенные не позже at, перестают приниматься.
+	RevokeUserTokens(ctx context.Context, userID int, at time.Time) error
+	// RevokeSession — токены сессии sessionID (claim sid) перестают приниматься; отметка нужна до until.
+	RevokeSession(ctx context.Context, sessionID int64, until time.Time) error
+}
+
+// TokenStatus — состояние access-токена в хранилище.
+type TokenStatus struct {
+	Blacklisted    bool
+	RevokedAt      time.Time // последний отзыв токенов пользователя; нулевое — не отзывались
+	SessionRevoked bool
+}
+
+var _ TokenStore = (*UserRepository)(nil)
+
+const redisTokenPrefix = "edutalks:"
+
+// RedisTokenStore — токены в Redis с TTL: запись блоклиста живёт до истечения токена,
+// refresh-токен — REFRESH_TOKEN_EXPIRY. В ключах — sha256 токена, а не сам токен.
+type RedisTokenStore struct {
+	rdb        redis.Cmdable
+	refreshTTL time.Duration
+}
+
+func NewRedisTokenStore(rdb redis.Cmdable, refreshTTL time.Duration) *RedisTokenStore {
+	return &RedisTokenStore{rdb: rdb, refreshTTL: refreshTTL}
+}
+
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func blacklistKey(token string) string {
+	return redisTokenPrefix + "blacklist:" + tokenHash(token)
+}
+
+func revokedKey(userID int) string {
+	return redisTokenPrefix + "revoked:" + strconv.Itoa(userID)
+}
+
+func sessionRevokedKey(sessionID int64) string {
+	return redisTokenPrefix + "session-revoked:" + strconv.FormatInt(sessionID, 10)
+}
+
+func refreshKey(userID int, token string) string {
+	return redisTokenPrefix + "refresh:" + strconv.Itoa(userID) + ":" + tokenHash(token)
+}
+
+func (s *RedisTokenStore) AddAccessTokenToBlacklist(ctx context.Context, token string, exp time.Time) error {
+	log := logger.WithCtx(ctx)
+	ttl := time.Until(exp)
+	if ttl <= 0 {
+		log.Debug("redis token store: token already expired, blacklist skipped")
+		return nil
+	}
+	if err := s.rdb.Set(ctx, blacklistKey(token), "1", ttl+time.Millisecond).Err(); err != nil {
+		log.Error("redis token store: add access token to blacklist failed", zap.Error(err))
+		return err
+	}
+	log.Info("redis token store: access token added to blacklist", zap.Time("exp", exp))
+	return nil
+}
+
+// TokenStatus — три ключа одним конвейером (pipeline), один round-trip до Redis.
+func (s *RedisTokenStore) TokenStatus(ctx context.Context, token string, userID int, sessionID int64) (TokenStatus, error) {
+	var (
+		blacklisted, session *redis.IntCmd
+		revoked              *redis.StringCmd
+	)
+	_, err := s.rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+		blacklisted = p.Exists(ctx, blacklistKey(token))
+		revoked = p.Get(ctx, revokedKey(userID))
+		if sessionID != 0 {
+			session = p.Exists(ctx, sessionRevokedKey(sessionID))
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		logger.WithCtx(ctx).Error("redis token store: token status failed", zap.Error(err), zap.Int("user_id", userID))
+		return TokenStatus{}, err
+	}
+
+	st := TokenStatus{Blacklisted: blacklisted.Val() > 0}
+	if session != nil {
+		st.SessionRevoked = session.Val() > 0
+	}
+	if ms, err := revoked.Int64(); err == nil {
+		st.RevokedAt = revokedTime(ms)
+	} else if !errors.Is(err, redis.Nil) {
+		logger.WithCtx(ctx).Error("redis token store: get user revocation failed", zap.Error(err), zap.Int("user_id", userID))
+		return TokenStatus{}, err
+	}
+	return st, nil
+}
+
+func (s *RedisTokenStore) SaveRefreshToken(ctx context.Context, userID int, token string) error {
+	log := logger.WithCtx(ctx)
+	if err := s.rdb.Set(ctx, refreshKey(userID, token), "1", s.refreshTTL).Err(); err != nil {
+		log.Error("redis token store: save refresh token failed", zap.Error(err), zap.Int("user_id", userID))
+		return err
+	}
+	log.Debug("redis token store: refresh token saved", zap.Int("user_id", userID))
+	return nil
+}
+
+func (s *RedisTokenStore) IsRefreshTokenValid(ctx context.Context, userID int, token string) (bool, error) {
+	return s.exists(ctx, refreshKey(userID, token))
+}
+
+func (s *RedisTokenStore) DeleteRefreshToken(ctx context.Context, userID int, token string) error {
+	log := logger.WithCtx(ctx)
+	if err := s.rdb.Del(ctx, refreshKey(userID, token)).Err(); err != nil {
+		log.Error("redis token store: delete refresh token failed", zap.Error(err), zap.Int("user_id", userID))
+		return err
+	}
+	log.Debug("redis token store: refresh token deleted", zap.Int("user_id", userID))
+	return nil
+}
+
+// RevokeUserTokens — отметка (миллисекунды Unix) живёт REFRESH_TOKEN_EXPIRY (дольше не живёт
+// ни один токен); refresh-токены пользователя удаляются.
+func (s *RedisTokenStore) RevokeUserTokens(ctx context.Context, userID int, at time.Time) error {
+	log := logger.WithCtx(ctx)
+	if err := s.rdb.Set(ctx, revokedKey(userID), at.UnixMilli(), s.refreshTTL).Err(); err != nil {
+		log.Error("redis token store: revoke user tokens failed", zap.Error(err), zap.Int("user_id", userID))
+		return err
+	}
+	if err := s.deleteRefreshTokens(ctx, userID); err != nil {
+		log.Error("redis token store: delete user refresh tokens failed", zap.Error(err), zap.Int("user_id", userID))
+		return err
+	}
+	log.Info("redis token store: user tokens revoked", zap.Int("user_id", userID), zap.Time("at", at))
+	return nil
+}
+
+// revokedTime — отметка отзыва; записанная в секундах до перехода на миллисекунды читается как секунды.
+func revokedTime(ms int64) time.Time {
+	if ms < 1e12 {
+		return time.Unix(ms, 0)
+	}
+	return time.UnixMilli(ms)
+}
+
+func (s *RedisTokenStore) RevokeSession(ctx context.Context, sessionID int64, until time.Time) error {
+	log := logger.WithCtx(ctx)
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		log.Debug("redis token store: session already expired, revoke skipped", zap.Int64("session_id", sessionID))
+		return nil
+	}
+	if err := s.rdb.Set(ctx, sessionRevokedKey(sessionID), "1", ttl+time.Millisecond).Err(); err != nil {
+		log.Error("redis token store: revoke session failed", zap.Error(err), zap.Int64("session_id", sessionID))
+		return err
+	}
+	log.Info("redis token store: session revoked", zap.Int64("session_id", sessionID))
+	return nil
+}
+
+// deleteRefreshTokens — все refresh-токены пользователя (SCAN по префиксу ключа).
+func (s *RedisTokenStore) deleteRefreshTokens(ctx context.Context, userID int) error {
+	match := redisTokenPrefix + "refresh:" + strconv.Itoa(userID) + ":*"
+	var cursor uint64
+	for {
+		keys, next, err := s.rdb.Scan(ctx, cursor, match, 100).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := s.rdb.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+		}
+		if cursor = next; cursor == 0 {
+			return nil
+		}
+	}
+}
+
+func (s *RedisTokenStore) exists(ctx context.Context, key string) (bool, error) {
+	n, err := s.rdb.Exists(ctx, key).Result()
+	if err != nil {
+		logger.WithCtx(ctx).Error("redis token store: exists check failed", zap.Error(err))
+		return false, err
+	}
+	return n > 0, nil
+}
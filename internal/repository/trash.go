@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// TrashRepository — корзина: документы, новости и статьи с deleted_at.
+type TrashRepository struct {
+	db DB
+}
+
+func NewTrashRepository(db DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// trashTables — таблица для каждого типа материала в корзине.
+var trashTables = map[string]string{
+	models.TrashDocument: "documents",
+	models.TrashNews:     "news",
+	models.TrashArticle:  "articles",
+}
+
+// trashSelect — всё содержимое корзины одной выборкой.
+const trashSelect = `
+	WITH trash AS (
+		SELECT 'document' AS type, id::bigint AS id, COALESCE(NULLIF(title, ''), filename) AS title, deleted_at
+		FROM documents WHERE deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'news', id::bigint, title, deleted_at FROM news WHERE deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'article', id::bigint, title, deleted_at FROM articles WHERE deleted_at IS NOT NULL
+	)`
+
+// List — материалы в корзине, недавно удалённые сверху; typ пустой — все типы.
+func (r *TrashRepository) List(ctx context.Context, typ string, limit, offset int) ([]models.TrashItem, int, error) {
+	log := logger.WithCtx(ctx)
+
+	rows, err := r.db.Query(ctx, trashSelect+`
+		SELECT type, id, title, deleted_at FROM trash
+		WHERE $1 = '' OR type = $1
+		ORDER BY deleted_at DESC, type, id DESC
+		LIMIT $2 OFFSET $3`, typ, limit, offset)
+	if err != nil {
+		log.Error("trash repo: list failed", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := []models.TrashItem{}
+	for rows.Next() {
+		var it models.TrashItem
+		if err := rows.Scan(&it.Type, &it.ID, &it.Title, &it.DeletedAt); err != nil {
+			log.Error("trash repo: scan failed", zap.Error(err))
+			return nil, 0, err
+		}
+		items = append(items, it)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, trashSelect+`
+		SELECT COUNT(*) FROM trash WHERE $1 = '' OR type = $1`, typ).Scan(&total); err != nil {
+		log.Error("trash repo: count failed", zap.Error(err))
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// Restore — возвращает материал из корзины; false — в корзине такого нет.
+func (r *TrashRepository) Restore(ctx context.Context, typ string, id int64) (bool, error) {
+	table, ok := trashTables[typ]
+	if !ok {
+		return false, nil
+	}
+	tag, err := r.db.Exec(ctx,
+		fmt.Sprintf(`UPDATE %s SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, table), id)
+	if err != nil {
+		logger.WithCtx(ctx).Error("trash repo: restore failed", zap.String("type", typ), zap.Int64("id", id), zap.Error(err))
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Delete — окончательно удаляет материал из корзины. Для документа возвращает путь к файлу
+// на диске, который нужно удалить (пусто — файлом пользуется другой документ);
+// false — в корзине такого нет.
+func (r *TrashRepository) Delete(ctx context.Context, typ string, id int64) (string, bool, error) {
+	table, ok := trashTables[typ]
+	if !ok {
+		return "", false, nil
+	}
+	q := fmt.Sprintf(`DELETE FROM %s WHERE id = $1 AND deleted_at IS NOT NULL RETURNING ''`, table)
+	if typ == models.TrashDocument {
+		q = `
+			WITH d AS (DELETE FROM documents WHERE id = $1 AND deleted_at IS NOT NULL RETURNING filepath)
+			SELECT CASE WHEN EXISTS (SELECT 1 FROM documents o WHERE o.filepath = d.filepath AND o.id <> $1)
+			            THEN '' ELSE d.filepath END
+			FROM d`
+	}
+	var file string
+	err := r.db.QueryRow(ctx, q, id).Scan(&file)
+	if err == pgx.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		logger.WithCtx(ctx).Error("trash repo: delete failed", zap.String("type", typ), zap.Int64("id", id), zap.Error(err))
+		return "", false, err
+	}
+	return file, true, nil
+}
+
+// Purge — окончательно удаляет всё, что лежит в корзине с момента before или дольше.
+// Возвращает, сколько материалов удалено, и пути к файлам удалённых документов,
+// которыми больше не пользуется ни один документ.
+func (r *TrashRepository) Purge(ctx context.Context, before time.Time) (int, []string, error) {
+	log := logger.WithCtx(ctx)
+
+	var (
+		n     int
+		files []string
+	)
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		n, files = 0, nil
+		rows, err := tx.Query(ctx, `DELETE FROM documents WHERE deleted_at < $1 RETURNING filepath`, before)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var f string
+			if err := rows.Scan(&f); err != nil {
+				rows.Close()
+				return err
+			}
+			files = append(files, f)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		n = len(files)
+		if len(files) > 0 {
+			if err := tx.QueryRow(ctx, `
+				SELECT COALESCE(array_agg(DISTINCT p), '{}') FROM unnest($1::text[]) p
+				WHERE NOT EXISTS (SELECT 1 FROM documents WHERE filepath = p)`, files,
+			).Scan(&files); err != nil {
+				return err
+			}
+		}
+
+		for _, table := range []string{"news", "articles"} {
+			tag, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE deleted_at < $1`, before)
+			if err != nil {
+				return err
+			}
+			n += int(tag.RowsAffected())
+		}
+
+		// история согласования окончательно удалённых статей и документов (и удалённых через Delete)
+		_, err = tx.Exec(ctx, `
+			DELETE FROM content_review_events e
+			WHERE (e.content_type = 'article' AND NOT EXISTS (SELECT 1 FROM articles a WHERE a.id = e.content_id))
+			   OR (e.content_type = 'document' AND NOT EXISTS (SELECT 1 FROM documents d WHERE d.id = e.content_id))`)
+		return err
+	})
+	if err != nil {
+		log.Error("trash repo: purge failed", zap.Error(err))
+		return 0, nil, err
+	}
+	return n, files, nil
+}
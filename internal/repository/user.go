@@ -8,15 +8,15 @@ import (
 	"strings"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
 type UserRepository struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewUserRepository(db *pgxpool.Pool) *UserRepository {
+func NewUserRepository(db DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
@@ -29,14 +29,25 @@ type UserRepo interface {
 	GetUserByID(ctx context.Context, id int) (*models.User, error)
 	UpdateUserFields(ctx context.Context, id int, input *models.UpdateUserRequest) error
 	UpdateSubscriptionStatus(ctx context.Context, userID int, status bool) error
-	GetSubscribedEmails(ctx context.Context) ([]string, error)
+	NotifyRecipients(ctx context.Context, a models.NotifyAudience) ([]models.NotifyRecipient, error)
 	UpdateEmailSubscription(ctx context.Context, userID int, subscribe bool) error
+	UpdateEmailFrequency(ctx context.Context, userID int, frequency string) error
+	UpdateLoginAlerts(ctx context.Context, userID int, enabled bool) error
 	SetEmailVerified(ctx context.Context, userID int, verified bool) error
+	SetAvatar(ctx context.Context, userID int, key *string) (*string, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	DeleteUserByID(ctx context.Context, userID int) error
 	SetSubscriptionWithExpiry(ctx context.Context, userID int, duration time.Duration) error
 	ExpireSubscriptions(ctx context.Context) error
 	ExtendSubscription(ctx context.Context, userID int, duration time.Duration) error
+	GrantSubscriptionWithOutbox(
+		ctx context.Context,
+		userID int,
+		duration time.Duration,
+		extend bool,
+		outbox *OutboxRepository,
+		intents func(u *models.User) ([]models.OutboxIntent, error),
+	) (*models.User, error)
 	GetUserByPhone(ctx context.Context, phoneDigits string) (*models.User, error)
 	GetSystemStats(ctx context.Context) (*models.SystemStats, error)
 	GetUsersFiltered(
@@ -46,16 +57,21 @@ type UserRepo interface {
 		role *string,
 		hasSubscription *bool,
 	) ([]*models.User, int, error)
-	AddAccessTokenToBlacklist(ctx context.Context, token string, exp time.Time) error
-	IsAccessTokenBlacklisted(ctx context.Context, token string) (bool, error)
+	FilterUserIDs(ctx context.Context, q string, role *string, hasSubscription *bool, limit int) ([]int, error)
+	BulkApply(ctx context.Context, ids []int, allOrNothing bool, outbox *OutboxRepository, op UserBulkOp) (map[int]error, error)
+	GrantSubscriptionTx(ctx context.Context, tx pgx.Tx, userID int, duration time.Duration, extend bool) (*models.User, error)
+	RevokeSubscriptionTx(ctx context.Context, tx pgx.Tx, userID int) (*models.User, *time.Time, error)
+	SetRoleTx(ctx context.Context, tx pgx.Tx, userID int, role string) error
+	DeleteUserTx(ctx context.Context, tx pgx.Tx, userID int) error
+	DeletionScheduledAt(ctx context.Context, userID int) (*time.Time, error)
 }
 
 func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) error {
 	log := logger.WithCtx(ctx)
 
 	const q = `
-		INSERT INTO users (username, full_name, phone, email, address, password_hash, role)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO users (username, full_name, phone, email, address, password_hash, role, locale)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE(NULLIF($8, ''), 'ru'))
 		RETURNING id
 	`
 	if err := r.db.QueryRow(ctx, q,
@@ -66,6 +82,7 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) erro
 		user.Address,
 		user.PasswordHash,
 		user.Role,
+		user.Locale,
 	).Scan(&user.ID); err != nil {
 		log.Error("user repo: create user failed", zap.Error(err))
 		return err
@@ -107,7 +124,7 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 	const q = `
 		SELECT id, username, full_name, phone, email, address, password_hash, role,
 		       created_at, updated_at, has_subscription, subscription_expires_at,
-		       email_subscription, email_verified
+		       email_subscription, email_verified, phone_verified, login_alerts, locale, email_frequency
 		FROM users
 		WHERE username = $1
 	`
@@ -128,6 +145,10 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 		&user.SubscriptionExpiresAt,
 		&user.EmailSubscription,
 		&user.EmailVerified,
+		&user.PhoneVerified,
+		&user.LoginAlerts,
+		&user.Locale,
+		&user.EmailFrequency,
 	); err != nil {
 		log.Error("user repo: get by username failed", zap.Error(err), zap.String("username", username))
 		return nil, err
@@ -180,7 +201,7 @@ func (r *UserRepository) GetAllUsersPaginated(ctx context.Context, limit, offset
 	const q = `
 		SELECT id, username, full_name, phone, email, address, role,
 		       created_at, updated_at, has_subscription, subscription_expires_at,
-		       email_subscription, email_verified
+		       email_subscription, email_verified, phone_verified, login_alerts, locale, email_frequency
 		FROM users
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
@@ -198,7 +219,7 @@ func (r *UserRepository) GetAllUsersPaginated(ctx context.Context, limit, offset
 		if err := rows.Scan(
 			&u.ID, &u.Username, &u.FullName, &u.Phone, &u.Email, &u.Address,
 			&u.Role, &u.CreatedAt, &u.UpdatedAt, &u.HasSubscription, &u.SubscriptionExpiresAt,
-			&u.EmailSubscription, &u.EmailVerified,
+			&u.EmailSubscription, &u.EmailVerified, &u.PhoneVerified, &u.LoginAlerts, &u.Locale, &u.EmailFrequency,
 		); err != nil {
 			log.Error("user repo: scan user failed", zap.Error(err))
 			return nil, 0, err
@@ -227,7 +248,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id int) (*models.User,
 		SELECT id, username, full_name, phone, email, address,
 		       password_hash, role, created_at, updated_at,
 		       has_subscription, subscription_expires_at,
-		       email_subscription, email_verified
+		       email_subscription, email_verified, phone_verified, login_alerts, locale, email_frequency, avatar_key
 		FROM users
 		WHERE id = $1
 	`
@@ -237,7 +258,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id int) (*models.User,
 		&u.ID, &u.Username, &u.FullName, &u.Phone, &u.Email, &u.Address,
 		&u.PasswordHash, &u.Role, &u.CreatedAt, &u.UpdatedAt,
 		&u.HasSubscription, &u.SubscriptionExpiresAt,
-		&u.EmailSubscription, &u.EmailVerified,
+		&u.EmailSubscription, &u.EmailVerified, &u.PhoneVerified, &u.LoginAlerts, &u.Locale, &u.EmailFrequency, &u.AvatarKey,
 	); err != nil {
 		log.Error("user repo: get by id failed", zap.Error(err), zap.Int("user_id", id))
 		return nil, err
@@ -260,12 +281,14 @@ func (r *UserRepository) UpdateUserFields(ctx context.Context, id int, input *mo
 		argNum++
 	}
 	if input.Email != nil {
-		q += fmt.Sprintf(" email = $%d,", argNum)
+		// другой адрес ещё не подтверждён
+		q += fmt.Sprintf(" email = $%d, email_verified = (lower(email) = lower($%d) AND email_verified),", argNum, argNum)
 		args = append(args, *input.Email)
 		argNum++
 	}
 	if input.Phone != nil {
-		q += fmt.Sprintf(" phone = $%d,", argNum)
+		// другой номер ещё не подтверждён
+		q += fmt.Sprintf(" phone = $%d, phone_verified = (phone = $%d AND phone_verified),", argNum, argNum)
 		args = append(args, *input.Phone)
 		argNum++
 	}
@@ -279,6 +302,11 @@ func (r *UserRepository) UpdateUserFields(ctx context.Context, id int, input *mo
 		args = append(args, *input.Role)
 		argNum++
 	}
+	if input.Locale != nil {
+		q += fmt.Sprintf(" locale = $%d,", argNum)
+		args = append(args, *input.Locale)
+		argNum++
+	}
 
 	if len(args) == 0 {
 		log.Warn("user repo: no fields to update", zap.Int("user_id", id))
@@ -300,13 +328,22 @@ func (r *UserRepository) UpdateUserFields(ctx context.Context, id int, input *mo
 func (r *UserRepository) UpdateSubscriptionStatus(ctx context.Context, userID int, status bool) error {
 	log := logger.WithCtx(ctx)
 
+	// в журнал — только реальная смена статуса
 	const q = `
-		UPDATE users
-		SET has_subscription = $1,
-		    subscription_expires_at = CASE WHEN $1 THEN subscription_expires_at ELSE NULL END
-		WHERE id = $2
+		WITH prev AS (SELECT id, has_subscription FROM users WHERE id = $2),
+		upd AS (
+			UPDATE users
+			SET has_subscription = $1,
+			    subscription_expires_at = CASE WHEN $1 THEN subscription_expires_at ELSE NULL END
+			WHERE id = $2
+			RETURNING id
+		)
+		INSERT INTO subscription_changes (user_id, kind, actor_id)
+		SELECT upd.id, CASE WHEN $1 THEN 'grant' ELSE 'revoke' END, $3
+		FROM upd JOIN prev ON prev.id = upd.id
+		WHERE prev.has_subscription IS DISTINCT FROM $1
 	`
-	if _, err := r.db.Exec(ctx, q, status, userID); err != nil {
+	if _, err := r.db.Exec(ctx, q, status, userID, subscriptionActor(ctx)); err != nil {
 		log.Error("user repo: update subscription status failed", zap.Error(err), zap.Int("user_id", userID))
 		return err
 	}
@@ -315,50 +352,51 @@ func (r *UserRepository) UpdateSubscriptionStatus(ctx context.Context, userID in
 	return nil
 }
 
-func (r *UserRepository) GetSubscribedEmails(ctx context.Context) ([]string, error) {
+func (r *UserRepository) UpdateEmailSubscription(ctx context.Context, userID int, subscribe bool) error {
 	log := logger.WithCtx(ctx)
 
-	rows, err := r.db.Query(ctx, `SELECT email FROM users WHERE email_subscription = true`)
-	if err != nil {
-		log.Error("user repo: get subscribed emails failed", zap.Error(err))
-		return nil, err
+	const q = `UPDATE users SET email_subscription = $1 WHERE id = $2`
+	if _, err := r.db.Exec(ctx, q, subscribe, userID); err != nil {
+		log.Error("user repo: update email subscription failed", zap.Error(err), zap.Int("user_id", userID))
+		return err
 	}
-	defer rows.Close()
+	log.Info("user repo: email subscription updated", zap.Int("user_id", userID), zap.Bool("subscribe", subscribe))
+	return nil
+}
 
-	var emails []string
-	for rows.Next() {
-		var email string
-		if err := rows.Scan(&email); err != nil {
-			log.Error("user repo: scan subscribed email failed", zap.Error(err))
-			return nil, err
-		}
-		emails = append(emails, email)
-	}
-	if err := rows.Err(); err != nil {
-		log.Error("user repo: rows error subscribed emails", zap.Error(err))
-		return nil, err
-	}
+func (r *UserRepository) UpdateEmailFrequency(ctx context.Context, userID int, frequency string) error {
+	log := logger.WithCtx(ctx)
 
-	log.Debug("user repo: got subscribed emails", zap.Int("count", len(emails)))
-	return emails, nil
+	// сводка начинается с момента переключения, а не с прошлой сводки
+	const q = `UPDATE users SET email_frequency = $1, digest_sent_at = NOW() WHERE id = $2`
+	if _, err := r.db.Exec(ctx, q, frequency, userID); err != nil {
+		log.Error("user repo: update email frequency failed", zap.Error(err), zap.Int("user_id", userID))
+		return err
+	}
+	log.Info("user repo: email frequency updated", zap.Int("user_id", userID), zap.String("frequency", frequency))
+	return nil
 }
 
-func (r *UserRepository) UpdateEmailSubscription(ctx context.Context, userID int, subscribe bool) error {
+func (r *UserRepository) UpdateLoginAlerts(ctx context.Context, userID int, enabled bool) error {
 	log := logger.WithCtx(ctx)
 
-	const q = `UPDATE users SET email_subscription = $1 WHERE id = $2`
-	if _, err := r.db.Exec(ctx, q, subscribe, userID); err != nil {
-		log.Error("user repo: update email subscription failed", zap.Error(err), zap.Int("user_id", userID))
+	const q = `UPDATE users SET login_alerts = $1 WHERE id = $2`
+	if _, err := r.db.Exec(ctx, q, enabled, userID); err != nil {
+		log.Error("user repo: update login alerts failed", zap.Error(err), zap.Int("user_id", userID))
 		return err
 	}
-	log.Info("user repo: email subscription updated", zap.Int("user_id", userID), zap.Bool("subscribe", subscribe))
+	log.Info("user repo: login alerts updated", zap.Int("user_id", userID), zap.Bool("enabled", enabled))
 	return nil
 }
 
 func (r *UserRepository) SetEmailVerified(ctx context.Context, userID int, verified bool) error {
 	log := logger.WithCtx(ctx)
 
-	const q = `UPDATE users SET email_verified = $1 WHERE id = $2`
+	const q = `
+		UPDATE users
+		SET email_verified = $1,
+		    email_verified_at = CASE WHEN NOT $1 THEN NULL WHEN email_verified THEN email_verified_at ELSE NOW() END
+		WHERE id = $2`
 	if _, err := r.db.Exec(ctx, q, verified, userID); err != nil {
 		log.Error("user repo: set email verified failed", zap.Error(err), zap.Int("user_id", userID))
 		return err
@@ -367,13 +405,33 @@ func (r *UserRepository) SetEmailVerified(ctx context.Context, userID int, verif
 	return nil
 }
 
+// SetAvatar — новый префикс файлов аватара (nil — удалить); возвращает прежний, чтобы убрать его файлы.
+func (r *UserRepository) SetAvatar(ctx context.Context, userID int, key *string) (*string, error) {
+	log := logger.WithCtx(ctx)
+
+	var prev *string
+	err := r.db.QueryRow(ctx, `
+		UPDATE users u SET avatar_key = $2, updated_at = NOW()
+		FROM (SELECT id, avatar_key FROM users WHERE id = $1 FOR UPDATE) old
+		WHERE u.id = old.id
+		RETURNING old.avatar_key`, userID, key).Scan(&prev)
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			log.Error("user repo: set avatar failed", zap.Error(err), zap.Int("user_id", userID))
+		}
+		return nil, err
+	}
+	log.Info("user repo: avatar updated", zap.Int("user_id", userID), zap.Bool("removed", key == nil))
+	return prev, nil
+}
+
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	log := logger.WithCtx(ctx)
 
 	const q = `
 		SELECT id, username, full_name, phone, email, address, password_hash, role,
 		       created_at, updated_at, has_subscription, subscription_expires_at,
-		       email_subscription, email_verified
+		       email_subscription, email_verified, phone_verified, login_alerts, locale, email_frequency
 		FROM users
 		WHERE lower(email) = lower($1)
 	`
@@ -383,7 +441,7 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 		&user.ID, &user.Username, &user.FullName, &user.Phone, &user.Email, &user.Address,
 		&user.PasswordHash, &user.Role, &user.CreatedAt, &user.UpdatedAt,
 		&user.HasSubscription, &user.SubscriptionExpiresAt,
-		&user.EmailSubscription, &user.EmailVerified,
+		&user.EmailSubscription, &user.EmailVerified, &user.PhoneVerified, &user.LoginAlerts, &user.Locale, &user.EmailFrequency,
 	); err != nil {
 		log.Error("user repo: get by email failed", zap.Error(err), zap.String("email", email))
 		return nil, err
@@ -410,12 +468,17 @@ func (r *UserRepository) SetSubscriptionWithExpiry(ctx context.Context, userID i
 	log := logger.WithCtx(ctx)
 
 	const q = `
-		UPDATE users
-		SET has_subscription = true,
-		    subscription_expires_at = NOW() + $1 * interval '1 second'
-		WHERE id = $2
+		WITH upd AS (
+			UPDATE users
+			SET has_subscription = true,
+			    subscription_expires_at = NOW() + $1 * interval '1 second'
+			WHERE id = $2
+			RETURNING id
+		)
+		INSERT INTO subscription_changes (user_id, kind, actor_id)
+		SELECT id, 'grant', $3 FROM upd
 	`
-	if _, err := r.db.Exec(ctx, q, int64(duration.Seconds()), userID); err != nil {
+	if _, err := r.db.Exec(ctx, q, int64(duration.Seconds()), userID, subscriptionActor(ctx)); err != nil {
 		log.Error("user repo: set subscription with expiry failed", zap.Error(err), zap.Int("user_id", userID))
 		return err
 	}
@@ -428,11 +491,16 @@ func (r *UserRepository) ExpireSubscriptions(ctx context.Context) error {
 	log := logger.WithCtx(ctx)
 
 	const q = `
-		UPDATE users
-		SET has_subscription = false
-		WHERE has_subscription = true
-		  AND subscription_expires_at IS NOT NULL
-		  AND subscription_expires_at <= NOW()
+		WITH upd AS (
+			UPDATE users
+			SET has_subscription = false
+			WHERE has_subscription = true
+			  AND subscription_expires_at IS NOT NULL
+			  AND subscription_expires_at <= NOW()
+			RETURNING id
+		)
+		INSERT INTO subscription_changes (user_id, kind)
+		SELECT id, 'expire' FROM upd
 	`
 	if _, err := r.db.Exec(ctx, q); err != nil {
 		log.Error("user repo: expire subscriptions failed", zap.Error(err))
@@ -447,12 +515,17 @@ func (r *UserRepository) ExtendSubscription(ctx context.Context, userID int, dur
 	log := logger.WithCtx(ctx)
 
 	const q = `
-		UPDATE users
-		SET has_subscription = true,
-		    subscription_expires_at = COALESCE(subscription_expires_at, NOW()) + $1 * interval '1 second'
-		WHERE id = $2
+		WITH upd AS (
+			UPDATE users
+			SET has_subscription = true,
+			    subscription_expires_at = COALESCE(subscription_expires_at, NOW()) + $1 * interval '1 second'
+			WHERE id = $2
+			RETURNING id
+		)
+		INSERT INTO subscription_changes (user_id, kind, actor_id)
+		SELECT id, 'extend', $3 FROM upd
 	`
-	if _, err := r.db.Exec(ctx, q, int64(duration.Seconds()), userID); err != nil {
+	if _, err := r.db.Exec(ctx, q, int64(duration.Seconds()), userID, subscriptionActor(ctx)); err != nil {
 		log.Error("user repo: extend subscription failed", zap.Error(err), zap.Int("user_id", userID))
 		return err
 	}
@@ -461,15 +534,54 @@ func (r *UserRepository) ExtendSubscription(ctx context.Context, userID int, dur
 	return nil
 }
 
+// GrantSubscriptionWithOutbox — выдаёт (extend=false) или продлевает подписку и в той же транзакции
+// записывает побочные эффекты (письмо, событие), построенные по обновлённому пользователю.
+// Если запись намерений не удалась — подписка тоже не меняется.
+func (r *UserRepository) GrantSubscriptionWithOutbox(
+	ctx context.Context,
+	userID int,
+	duration time.Duration,
+	extend bool,
+	outbox *OutboxRepository,
+	intents func(u *models.User) ([]models.OutboxIntent, error),
+) (*models.User, error) {
+	log := logger.WithCtx(ctx)
+
+	var u *models.User
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		var err error
+		if u, err = r.GrantSubscriptionTx(ctx, tx, userID, duration, extend); err != nil {
+			return err
+		}
+		list, err := intents(u)
+		if err != nil {
+			return err
+		}
+		return outbox.AddTx(ctx, tx, list...)
+	})
+	if err != nil {
+		log.Error("user repo: grant subscription with outbox failed",
+			zap.Error(err), zap.Int("user_id", userID), zap.Bool("extend", extend))
+		return nil, err
+	}
+
+	log.Info("user repo: subscription granted with outbox",
+		zap.Int("user_id", userID), zap.Bool("extend", extend), zap.Int64("seconds", int64(duration.Seconds())))
+	return u, nil
+}
+
+// GetUserByPhone — пользователь по последним 10 цифрам номера. Подтверждённый номер уникален
+// (users_verified_phone_last10_uniq), поэтому его владелец выигрывает; среди неподтверждённых — самый ранний.
 func (r *UserRepository) GetUserByPhone(ctx context.Context, phoneDigits string) (*models.User, error) {
 	log := logger.WithCtx(ctx)
 
 	const q = `
 		SELECT id, username, full_name, phone, email, address, password_hash, role,
 		       created_at, updated_at, has_subscription, subscription_expires_at,
-		       email_subscription, email_verified
+		       email_subscription, email_verified, phone_verified, login_alerts, locale, email_frequency
 		FROM users
 		WHERE right(regexp_replace(phone, '\D', '', 'g'), 10) = right($1, 10)
+		ORDER BY phone_verified DESC, id
 		LIMIT 1
 	`
 
@@ -478,7 +590,7 @@ func (r *UserRepository) GetUserByPhone(ctx context.Context, phoneDigits string)
 		&user.ID, &user.Username, &user.FullName, &user.Phone, &user.Email, &user.Address,
 		&user.PasswordHash, &user.Role, &user.CreatedAt, &user.UpdatedAt,
 		&user.HasSubscription, &user.SubscriptionExpiresAt,
-		&user.EmailSubscription, &user.EmailVerified,
+		&user.EmailSubscription, &user.EmailVerified, &user.PhoneVerified, &user.LoginAlerts, &user.Locale, &user.EmailFrequency,
 	); err != nil {
 		log.Error("user repo: get by phone failed", zap.Error(err))
 		return nil, err
@@ -488,6 +600,20 @@ func (r *UserRepository) GetUserByPhone(ctx context.Context, phoneDigits string)
 	return &user, nil
 }
 
+// CountActiveSubscriptions — пользователи с действующей подпиской.
+func (r *UserRepository) CountActiveSubscriptions(ctx context.Context) (int, error) {
+	const q = `
+SELECT COUNT(*) FROM users
+ WHERE has_subscription = true
+   AND (subscription_expires_at IS NULL OR subscription_expires_at > NOW())`
+	var n int
+	if err := r.db.QueryRow(ctx, q).Scan(&n); err != nil {
+		logger.WithCtx(ctx).Error("user repo: count active subscriptions failed", zap.Error(err))
+		return 0, err
+	}
+	return n, nil
+}
+
 func (r *UserRepository) GetSystemStats(ctx context.Context) (*models.SystemStats, error) {
 	log := logger.WithCtx(ctx)
 
@@ -504,9 +630,9 @@ SELECT
      WHERE has_subscription = false
         OR (subscription_expires_at IS NOT NULL AND subscription_expires_at <= NOW())
   )                                                                              AS without_subscription,
-  (SELECT COUNT(*) FROM news)                                                    AS news_count,
-  (SELECT COUNT(*) FROM documents)                                               AS documents_count,
-  (SELECT COUNT(*) FROM articles)                                                AS articles_count
+  (SELECT COUNT(*) FROM news WHERE deleted_at IS NULL)                           AS news_count,
+  (SELECT COUNT(*) FROM documents WHERE deleted_at IS NULL)                      AS documents_count,
+  (SELECT COUNT(*) FROM articles WHERE deleted_at IS NULL)                       AS articles_count
 `
 	var s models.SystemStats
 	if err := r.db.QueryRow(ctx, q).Scan(
@@ -544,29 +670,12 @@ func (r *UserRepository) GetUsersFiltered(
 	base := `
 		SELECT id, username, full_name, phone, email, address, role,
 		       created_at, updated_at, has_subscription, subscription_expires_at,
-		       email_subscription, email_verified
+		       email_subscription, email_verified, phone_verified, login_alerts, locale, email_frequency
 		FROM users
 	`
-	where := " WHERE 1=1"
-	whereArgs := []any{}
-	argn := 1
-
 	q = strings.TrimSpace(q)
-	if q != "" {
-		where += fmt.Sprintf(" AND (full_name ILIKE $%d OR lower(email) ILIKE $%d)", argn, argn+1)
-		whereArgs = append(whereArgs, "%"+q+"%", "%"+strings.ToLower(q)+"%")
-		argn += 2
-	}
-	if role != nil && strings.TrimSpace(*role) != "" {
-		where += fmt.Sprintf(" AND role = $%d", argn)
-		whereArgs = append(whereArgs, strings.TrimSpace(*role))
-		argn++
-	}
-	if hasSubscription != nil {
-		where += fmt.Sprintf(" AND has_subscription = $%d", argn)
-		whereArgs = append(whereArgs, *hasSubscription)
-		argn++
-	}
+	where, whereArgs := usersFilterWhere(q, role, hasSubscription)
+	argn := len(whereArgs) + 1
 
 	orderPage := fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argn, argn+1)
 	args := append(append([]any{}, whereArgs...), limit, offset)
@@ -584,7 +693,7 @@ func (r *UserRepository) GetUsersFiltered(
 		if err := rows.Scan(
 			&u.ID, &u.Username, &u.FullName, &u.Phone, &u.Email, &u.Address, &u.Role,
 			&u.CreatedAt, &u.UpdatedAt, &u.HasSubscription, &u.SubscriptionExpiresAt,
-			&u.EmailSubscription, &u.EmailVerified,
+			&u.EmailSubscription, &u.EmailVerified, &u.PhoneVerified, &u.LoginAlerts, &u.Locale, &u.EmailFrequency,
 		); err != nil {
 			log.Error("user repo: scan filtered user failed", zap.Error(err))
 			return nil, 0, err
@@ -612,6 +721,29 @@ func (r *UserRepository) GetUsersFiltered(
 	return users, total, nil
 }
 
+// usersFilterWhere — WHERE для выборки пользователей по поиску (ФИО/email), роли и подписке.
+func usersFilterWhere(q string, role *string, hasSubscription *bool) (string, []any) {
+	where := " WHERE 1=1"
+	args := []any{}
+	argn := 1
+
+	if q = strings.TrimSpace(q); q != "" {
+		where += fmt.Sprintf(" AND (full_name ILIKE $%d OR lower(email) ILIKE $%d)", argn, argn+1)
+		args = append(args, "%"+q+"%", "%"+strings.ToLower(q)+"%")
+		argn += 2
+	}
+	if role != nil && strings.TrimSpace(*role) != "" {
+		where += fmt.Sprintf(" AND role = $%d", argn)
+		args = append(args, strings.TrimSpace(*role))
+		argn++
+	}
+	if hasSubscription != nil {
+		where += fmt.Sprintf(" AND has_subscription = $%d", argn)
+		args = append(args, *hasSubscription)
+	}
+	return where, args
+}
+
 func (r *UserRepository) AddAccessTokenToBlacklist(ctx context.Context, token string, exp time.Time) error {
 	log := logger.WithCtx(ctx)
 	const q = `INSERT INTO access_token_blacklist (token, expires_at) VALUES ($1, $2)`
@@ -623,13 +755,58 @@ func (r *UserRepository) AddAccessTokenToBlacklist(ctx context.Context, token st
 	return nil
 }
 
-func (r *UserRepository) IsAccessTokenBlacklisted(ctx context.Context, token string) (bool, error) {
+// TokenStatus — блоклист, отзыв токенов пользователя и отзыв сессии одним запросом.
+func (r *UserRepository) TokenStatus(ctx context.Context, token string, userID int, sessionID int64) (TokenStatus, error) {
+	const q = `
+		SELECT
+			EXISTS(SELECT 1 FROM access_token_blacklist WHERE token = $1 AND expires_at > NOW()),
+			(SELECT revoked_at FROM user_token_revocations WHERE user_id = $2),
+			EXISTS(SELECT 1 FROM user_sessions WHERE id = $3 AND revoked_at IS NOT NULL)
+	`
+	var (
+		st        TokenStatus
+		revokedAt *time.Time
+	)
+	if err := r.db.QueryRow(ctx, q, token, userID, sessionID).Scan(&st.Blacklisted, &revokedAt, &st.SessionRevoked); err != nil {
+		logger.WithCtx(ctx).Error("repo: check token status failed", zap.Error(err), zap.Int("user_id", userID))
+		return TokenStatus{}, err
+	}
+	if revokedAt != nil {
+		st.RevokedAt = *revokedAt
+	}
+	return st, nil
+}
+
+// RevokeUserTokens — отзывает все токены пользователя, выпущенные не позже at (и удаляет refresh-токены).
+func (r *UserRepository) RevokeUserTokens(ctx context.Context, userID int, at time.Time) error {
 	log := logger.WithCtx(ctx)
-	const q = `SELECT EXISTS(SELECT 1 FROM access_token_blacklist WHERE token = $1 AND expires_at > NOW())`
-	var exists bool
-	if err := r.db.QueryRow(ctx, q, token).Scan(&exists); err != nil {
-		log.Error("repo: check access token blacklist failed", zap.Error(err))
-		return false, err
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		const q = `
+			INSERT INTO user_token_revocations (user_id, revoked_at) VALUES ($1, $2)
+			ON CONFLICT (user_id) DO UPDATE SET revoked_at = GREATEST(user_token_revocations.revoked_at, EXCLUDED.revoked_at)
+		`
+		if _, err := tx.Exec(ctx, q, userID, at); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM refresh_tokens WHERE user_id = $1`, userID)
+		return err
+	})
+	if err != nil {
+		log.Error("repo: revoke user tokens failed", zap.Error(err), zap.Int("user_id", userID))
+		return err
 	}
-	return exists, nil
+	log.Info("repo: user tokens revoked", zap.Int("user_id", userID), zap.Time("at", at))
+	return nil
+}
+
+// RevokeSession — источник истины для Postgres — сама таблица user_sessions; until не нужен.
+func (r *UserRepository) RevokeSession(ctx context.Context, sessionID int64, until time.Time) error {
+	log := logger.WithCtx(ctx)
+	const q = `UPDATE user_sessions SET revoked_at = COALESCE(revoked_at, NOW()) WHERE id = $1`
+	if _, err := r.db.Exec(ctx, q, sessionID); err != nil {
+		log.Error("repo: revoke session failed", zap.Error(err), zap.Int64("session_id", sessionID))
+		return err
+	}
+	log.Info("repo: session revoked", zap.Int64("session_id", sessionID))
+	return nil
 }
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// userActivityEvents — все события пользователя одной выборкой: входы (сессии), скачивания,
+// платежи, изменения подписки, подтверждение и смена email, входы поддержки. $1 — пользователь, $2 — тип или пустая строка.
+const userActivityEvents = `
+	WITH events AS (
+		SELECT 'login' AS type, s.created_at AS at, s.id AS ref_id,
+		       jsonb_build_object('ip', s.ip, 'user_agent', s.user_agent, 'revoked', s.revoked_at IS NOT NULL) AS details
+		FROM user_sessions s WHERE s.user_id = $1
+		UNION ALL
+		SELECT 'download', dd.downloaded_at, dd.document_id::bigint,
+		       jsonb_build_object('title', COALESCE(NULLIF(d.title, ''), d.filename))
+		FROM document_downloads dd
+		JOIN documents d ON d.id = dd.document_id
+		WHERE dd.user_id = $1
+		UNION ALL
+		SELECT 'payment', p.created_at, p.id,
+		       jsonb_build_object('plan', p.plan, 'amount', p.amount, 'currency', p.currency,
+		                          'status', p.status, 'paid_at', p.paid_at, 'error', p.error)
+		FROM payments p WHERE p.user_id = $1
+		UNION ALL
+		SELECT 'subscription', c.created_at, c.id,
+		       jsonb_build_object('kind', c.kind, 'actor_id', c.actor_id)
+		FROM subscription_changes c WHERE c.user_id = $1
+		UNION ALL
+		SELECT 'email_verified', u.email_verified_at, NULL::bigint, NULL::jsonb
+		FROM users u WHERE u.id = $1 AND u.email_verified_at IS NOT NULL
+		UNION ALL
+		SELECT 'email_changed', e.confirmed_at, e.id,
+		       jsonb_build_object('new_email', e.new_email)
+		FROM email_change_requests e WHERE e.user_id = $1 AND e.confirmed_at IS NOT NULL
+		UNION ALL
+		SELECT 'impersonation', i.created_at, i.id,
+		       jsonb_build_object('admin_id', i.admin_id, 'reason', i.reason, 'expires_at', i.expires_at)
+		FROM impersonations i WHERE i.user_id = $1
+	)
+	SELECT type, at, ref_id, details FROM events
+	WHERE $2 = '' OR type = $2
+`
+
+type UserActivityRepository struct {
+	db DB
+}
+
+func NewUserActivityRepository(db DB) *UserActivityRepository {
+	return &UserActivityRepository{db: db}
+}
+
+// List — лента действий пользователя, новые события сверху; typ пустой — все типы.
+func (r *UserActivityRepository) List(ctx context.Context, userID int, typ string, limit, offset int) ([]models.UserActivity, int, error) {
+	log := logger.WithCtx(ctx)
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM (`+userActivityEvents+`) t`, userID, typ).Scan(&total); err != nil {
+		log.Error("user activity repo: count failed", zap.Int("user_id", userID), zap.Error(err))
+		return nil, 0, err
+	}
+
+	q := userActivityEvents + ` ORDER BY at DESC, type, ref_id DESC LIMIT $3 OFFSET $4`
+	rows, err := r.db.Query(ctx, q, userID, typ, limit, offset)
+	if err != nil {
+		log.Error("user activity repo: list failed", zap.Int("user_id", userID), zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := []models.UserActivity{}
+	for rows.Next() {
+		var a models.UserActivity
+		if err := rows.Scan(&a.Type, &a.At, &a.RefID, &a.Details); err != nil {
+			log.Error("user activity repo: scan failed", zap.Error(err))
+			return nil, 0, err
+		}
+		items = append(items, a)
+	}
+	return items, total, rows.Err()
+}
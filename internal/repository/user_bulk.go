@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/reqctx"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// UserBulkOp — действие над одним пользователем внутри BulkApply; возвращает намерения outbox,
+// которые запишутся в той же транзакции. pgx.ErrNoRows — пользователя нет.
+type UserBulkOp func(ctx context.Context, tx pgx.Tx, userID int) ([]models.OutboxIntent, error)
+
+var errBulkAborted = errors.New("bulk aborted")
+
+// FilterUserIDs — ID пользователей по тем же условиям, что GetUsersFiltered (не больше limit).
+func (r *UserRepository) FilterUserIDs(ctx context.Context, q string, role *string, hasSubscription *bool, limit int) ([]int, error) {
+	log := logger.WithCtx(ctx)
+
+	where, args := usersFilterWhere(q, role, hasSubscription)
+	query := fmt.Sprintf("SELECT id FROM users%s ORDER BY id LIMIT $%d", where, len(args)+1)
+
+	rows, err := r.db.Query(ctx, query, append(args, limit)...)
+	if err != nil {
+		log.Error("user repo: filter user ids failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Error("user repo: scan user id failed", zap.Error(err))
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("user repo: rows error user ids", zap.Error(err))
+		return nil, err
+	}
+
+	log.Debug("user repo: filtered user ids", zap.Int("count", len(ids)))
+	return ids, nil
+}
+
+// BulkApply — применяет op к каждому пользователю в одной транзакции; каждый пользователь —
+// в своей точке сохранения, так что ошибка на одном не портит остальных. Возвращает ошибки по ID.
+// allOrNothing: на первой ошибке вся транзакция откатывается (в карте — только эта ошибка).
+func (r *UserRepository) BulkApply(
+	ctx context.Context,
+	ids []int,
+	allOrNothing bool,
+	outbox *OutboxRepository,
+	op UserBulkOp,
+) (map[int]error, error) {
+	log := logger.WithCtx(ctx)
+
+	failed := map[int]error{}
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		for _, id := range ids {
+			if err := applyInSavepoint(ctx, tx, id, outbox, op); err != nil {
+				failed[id] = err
+				if allOrNothing {
+					return errBulkAborted
+				}
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errBulkAborted) {
+		log.Warn("user repo: bulk operation rolled back", zap.Int("count", len(ids)))
+		return failed, nil
+	}
+	if err != nil {
+		log.Error("user repo: bulk operation failed", zap.Error(err), zap.Int("count", len(ids)))
+		return nil, err
+	}
+
+	log.Info("user repo: bulk operation committed", zap.Int("count", len(ids)), zap.Int("failed", len(failed)))
+	return failed, nil
+}
+
+func applyInSavepoint(ctx context.Context, tx pgx.Tx, userID int, outbox *OutboxRepository, op UserBulkOp) error {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	intents, err := op(ctx, sp, userID)
+	if err == nil {
+		err = outbox.AddTx(ctx, sp, intents...)
+	}
+	if err != nil {
+		_ = sp.Rollback(ctx)
+		return err
+	}
+	return sp.Commit(ctx)
+}
+
+// GrantSubscriptionTx — выдаёт (extend=false) или продлевает подписку в открытой транзакции.
+func (r *UserRepository) GrantSubscriptionTx(ctx context.Context, tx pgx.Tx, userID int, duration time.Duration, extend bool) (*models.User, error) {
+	q := `
+		UPDATE users
+		SET has_subscription = true,
+		    subscription_expires_at = NOW() + $1 * interval '1 second'
+		WHERE id = $2
+		RETURNING id, email, full_name, subscription_expires_at, locale
+	`
+	if extend {
+		q = `
+		UPDATE users
+		SET has_subscription = true,
+		    subscription_expires_at = COALESCE(subscription_expires_at, NOW()) + $1 * interval '1 second'
+		WHERE id = $2
+		RETURNING id, email, full_name, subscription_expires_at, locale
+	`
+	}
+
+	var u models.User
+	if err := tx.QueryRow(ctx, q, int64(duration.Seconds()), userID).Scan(
+		&u.ID, &u.Email, &u.FullName, &u.SubscriptionExpiresAt, &u.Locale,
+	); err != nil {
+		return nil, err
+	}
+	kind := models.SubscriptionChangeGrant
+	if extend {
+		kind = models.SubscriptionChangeExtend
+	}
+	if err := logSubscriptionChange(ctx, tx, u.ID, kind); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// RevokeSubscriptionTx — отключает подписку в открытой транзакции. Возвращает пользователя
+// и прежнюю дату окончания (для письма).
+func (r *UserRepository) RevokeSubscriptionTx(ctx context.Context, tx pgx.Tx, userID int) (*models.User, *time.Time, error) {
+	const q = `
+		UPDATE users u
+		SET has_subscription = false,
+		    subscription_expires_at = NULL
+		FROM (SELECT id, has_subscription, subscription_expires_at FROM users WHERE id = $1 FOR UPDATE) prev
+		WHERE u.id = prev.id
+		RETURNING u.id, u.email, u.full_name, u.locale, prev.subscription_expires_at, prev.has_subscription
+	`
+	var (
+		u    models.User
+		prev *time.Time
+		had  bool
+	)
+	if err := tx.QueryRow(ctx, q, userID).Scan(&u.ID, &u.Email, &u.FullName, &u.Locale, &prev, &had); err != nil {
+		return nil, nil, err
+	}
+	if had {
+		if err := logSubscriptionChange(ctx, tx, u.ID, models.SubscriptionChangeRevoke); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &u, prev, nil
+}
+
+func (r *UserRepository) SetRoleTx(ctx context.Context, tx pgx.Tx, userID int, role string) error {
+	tag, err := tx.Exec(ctx, `UPDATE users SET role = $1 WHERE id = $2`, role, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *UserRepository) DeleteUserTx(ctx context.Context, tx pgx.Tx, userID int) error {
+	tag, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// logSubscriptionChange — запись в журнал подписок в открытой транзакции; автор — пользователь запроса.
+func logSubscriptionChange(ctx context.Context, tx pgx.Tx, userID int, kind string) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO subscription_changes (user_id, kind, actor_id) VALUES ($1, $2, $3)`,
+		userID, kind, subscriptionActor(ctx))
+	return err
+}
+
+// subscriptionActor — кто меняет подписку: пользователь из контекста запроса (админ или сам
+// пользователь при оплате); nil — фоновая задача или вебхук.
+func subscriptionActor(ctx context.Context) *int {
+	if uid, ok := reqctx.GetUserID(ctx); ok && uid > 0 {
+		return &uid
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ScheduleDeletion — назначает удаление аккаунта на at и отписывает от рассылки; в той же
+// транзакции пишет намерения outbox (письмо со ссылкой отмены). pgx.ErrNoRows — пользователя
+// нет или удаление уже назначено.
+func (r *UserRepository) ScheduleDeletion(
+	ctx context.Context,
+	userID int,
+	at time.Time,
+	outbox *OutboxRepository,
+	intents func(u *models.User) ([]models.OutboxIntent, error),
+) (*models.User, error) {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+		UPDATE users
+		SET deletion_scheduled_at = $2,
+		    email_subscription = false
+		WHERE id = $1 AND deletion_scheduled_at IS NULL
+		RETURNING id, email, full_name, locale
+	`
+	var u models.User
+	err := inTx(ctx, r.db, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, q, userID, at).Scan(&u.ID, &u.Email, &u.FullName, &u.Locale); err != nil {
+			return err
+		}
+		list, err := intents(&u)
+		if err != nil {
+			return err
+		}
+		return outbox.AddTx(ctx, tx, list...)
+	})
+	if err != nil {
+		log.Error("user repo: schedule deletion failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, err
+	}
+
+	log.Info("user repo: deletion scheduled", zap.Int("user_id", userID), zap.Time("at", at))
+	return &u, nil
+}
+
+// DeletionScheduledAt — когда аккаунт будет удалён (nil — удаление не назначено).
+func (r *UserRepository) DeletionScheduledAt(ctx context.Context, userID int) (*time.Time, error) {
+	var at *time.Time
+	if err := r.db.QueryRow(ctx, `SELECT deletion_scheduled_at FROM users WHERE id = $1`, userID).Scan(&at); err != nil {
+		logger.WithCtx(ctx).Error("user repo: get deletion schedule failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, err
+	}
+	return at, nil
+}
+
+// CancelDeletion — отменяет ещё не выполненное удаление, назначенное на момент scheduledUnix
+// (ссылка из письма к другому, более раннему запросу не сработает). false — отменять нечего.
+func (r *UserRepository) CancelDeletion(ctx context.Context, userID int, scheduledUnix int64) (bool, error) {
+	log := logger.WithCtx(ctx)
+
+	const q = `
+		UPDATE users
+		SET deletion_scheduled_at = NULL
+		WHERE id = $1
+		  AND deletion_scheduled_at > NOW()
+		  AND floor(extract(epoch FROM deletion_scheduled_at))::bigint = $2
+	`
+	tag, err := r.db.Exec(ctx, q, userID, scheduledUnix)
+	if err != nil {
+		log.Error("user repo: cancel deletion failed", zap.Error(err), zap.Int("user_id", userID))
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	log.Info("user repo: deletion cancelled", zap.Int("user_id", userID))
+	return true, nil
+}
+
+// DeleteDueUsers — удаляет аккаунты, у которых истёк срок на отмену; возвращает их ID.
+func (r *UserRepository) DeleteDueUsers(ctx context.Context) ([]int, error) {
+	log := logger.WithCtx(ctx)
+
+	rows, err := r.db.Query(ctx, `DELETE FROM users WHERE deletion_scheduled_at <= NOW() RETURNING id`)
+	if err != nil {
+		log.Error("user repo: delete due users failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Error("user repo: scan deleted user id failed", zap.Error(err))
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("user repo: rows error deleted users", zap.Error(err))
+		return nil, err
+	}
+
+	if len(ids) > 0 {
+		log.Info("user repo: scheduled deletions done", zap.Int("count", len(ids)))
+	}
+	return ids, nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// UserDigestRepository — выборки для ежедневных и еженедельных сводок пользователям.
+type UserDigestRepository struct {
+	db DB
+}
+
+func NewUserDigestRepository(db DB) *UserDigestRepository {
+	return &UserDigestRepository{db: db}
+}
+
+// DueUsers — подписчики с частотой frequency, которым ещё не отправлена сводка за период,
+// заканчивающийся в periodEnd.
+func (r *UserDigestRepository) DueUsers(ctx context.Context, frequency string, periodEnd time.Time, limit int) ([]models.DigestRecipient, error) {
+	const q = `
+		SELECT id, email, COALESCE(full_name, ''), locale, digest_sent_at
+		FROM users
+		WHERE email_frequency = $1
+		  AND email_subscription AND email_verified
+		  AND (digest_sent_at IS NULL OR digest_sent_at < $2)
+		ORDER BY id
+		LIMIT $3`
+
+	rows, err := r.db.Query(ctx, q, frequency, periodEnd, limit)
+	if err != nil {
+		logger.WithCtx(ctx).Error("user digest repo: due users failed", zap.String("frequency", frequency), zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.DigestRecipient
+	for rows.Next() {
+		var u models.DigestRecipient
+		if err := rows.Scan(&u.UserID, &u.Email, &u.FullName, &u.Locale, &u.SentAt); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// Claim — отмечает сводку за период как отправленную. false — её уже забрал другой инстанс
+// или пользователь за это время сменил частоту.
+func (r *UserDigestRepository) Claim(ctx context.Context, userID int, periodEnd time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE users SET digest_sent_at = $2
+		WHERE id = $1 AND (digest_sent_at IS NULL OR digest_sent_at < $2)`, userID, periodEnd)
+	if err != nil {
+		logger.WithCtx(ctx).Error("user digest repo: claim failed", zap.Int("user_id", userID), zap.Error(err))
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Content — опубликованные новости и статьи, а также публичные документы за [d.From, d.To);
+// документы раздела — только из разделов, которые пользователь отслеживает (с подразделами).
+func (r *UserDigestRepository) Content(ctx context.Context, userID int, d *models.UserDigest, limit int) error {
+	log := logger.WithCtx(ctx)
+
+	const newsQ = `
+		SELECT id, title, published_at, COUNT(*) OVER ()
+		FROM news
+		WHERE is_published AND deleted_at IS NULL AND published_at >= $1 AND published_at < $2
+		ORDER BY published_at DESC, id DESC
+		LIMIT $3`
+	if err := r.scanItems(ctx, newsQ, &d.News, &d.NewsTotal, d.From, d.To, limit); err != nil {
+		log.Error("user digest repo: news failed", zap.Int("user_id", userID), zap.Error(err))
+		return err
+	}
+
+	const articlesQ = `
+		SELECT id, title, published_at, COUNT(*) OVER ()
+		FROM articles
+		WHERE is_published AND deleted_at IS NULL AND published_at >= $1 AND published_at < $2
+		ORDER BY published_at DESC, id DESC
+		LIMIT $3`
+	if err := r.scanItems(ctx, articlesQ, &d.Articles, &d.ArticlesTotal, d.From, d.To, limit); err != nil {
+		log.Error("user digest repo: articles failed", zap.Int("user_id", userID), zap.Error(err))
+		return err
+	}
+
+	const docsQ = `
+		WITH RECURSIVE followed AS (
+			SELECT section_id AS id FROM section_follows WHERE user_id = $4
+			UNION
+			SELECT s.id FROM sections s JOIN followed f ON s.parent_section_id = f.id
+		)
+		SELECT d.id, d.title, d.uploaded_at, COUNT(*) OVER (),
+		       COALESCE(t.slug, ''), COALESCE(t.title, ''), COALESCE(s.slug, ''), COALESCE(s.title, '')
+		FROM documents d
+		LEFT JOIN sections s ON s.id = d.section_id
+		LEFT JOIN tabs t ON t.id = s.tab_id
+		WHERE d.is_public AND d.deleted_at IS NULL AND d.uploaded_at >= $1 AND d.uploaded_at < $2
+		  AND (d.section_id IS NULL OR d.section_id IN (SELECT id FROM followed))
+		ORDER BY d.uploaded_at DESC, d.id DESC
+		LIMIT $3`
+	rows, err := r.db.Query(ctx, docsQ, d.From, d.To, limit, userID)
+	if err != nil {
+		log.Error("user digest repo: documents failed", zap.Int("user_id", userID), zap.Error(err))
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var it models.DigestItem
+		if err := rows.Scan(&it.ID, &it.Title, &it.At, &d.DocumentsTotal,
+			&it.TabSlug, &it.TabTitle, &it.SectionSlug, &it.SectionTitle); err != nil {
+			return err
+		}
+		d.Documents = append(d.Documents, it)
+	}
+	return rows.Err()
+}
+
+func (r *UserDigestRepository) scanItems(ctx context.Context, q string, items *[]models.DigestItem, total *int, args ...any) error {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	list, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.DigestItem, error) {
+		var it models.DigestItem
+		err := row.Scan(&it.ID, &it.Title, &it.At, total)
+		return it, err
+	})
+	*items = list
+	return err
+}
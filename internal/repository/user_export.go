@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type UserExportRepository struct {
+	db DB
+}
+
+func NewUserExportRepository(db DB) *UserExportRepository {
+	return &UserExportRepository{db: db}
+}
+
+const userExportColumns = `id, user_id, requested_by, status, COALESCE(file_path, ''), size_bytes, error, created_at, finished_at, expires_at`
+
+func scanUserExport(row pgx.Row) (*models.UserExport, error) {
+	var e models.UserExport
+	if err := row.Scan(&e.ID, &e.UserID, &e.RequestedBy, &e.Status, &e.FilePath, &e.SizeBytes, &e.Error,
+		&e.CreatedAt, &e.FinishedAt, &e.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Create — новый запрос выгрузки в статусе pending.
+func (r *UserExportRepository) Create(ctx context.Context, userID int, requestedBy *int) (*models.UserExport, error) {
+	e, err := scanUserExport(r.db.QueryRow(ctx, `
+		INSERT INTO user_exports (user_id, requested_by) VALUES ($1, $2)
+		RETURNING `+userExportColumns, userID, requestedBy))
+	if err != nil {
+		logger.WithCtx(ctx).Error("user export repo: create failed", zap.Error(err), zap.Int("user_id", userID))
+		return nil, err
+	}
+	return e, nil
+}
+
+// GetByID — выгрузка по id; pgx.ErrNoRows, если её нет.
+func (r *UserExportRepository) GetByID(ctx context.Context, id int64) (*models.UserExport, error) {
+	e, err := scanUserExport(r.db.QueryRow(ctx, `SELECT `+userExportColumns+` FROM user_exports WHERE id = $1`, id))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.WithCtx(ctx).Error("user export repo: get failed", zap.Error(err), zap.Int64("id", id))
+	}
+	return e, err
+}
+
+// Active — незавершённая или готовая (ещё не истёкшая) выгрузка пользователя, запрошенная
+// тем же человеком после since; pgx.ErrNoRows, если такой нет.
+func (r *UserExportRepository) Active(ctx context.Context, userID int, requestedBy *int, since time.Time) (*models.UserExport, error) {
+	e, err := scanUserExport(r.db.QueryRow(ctx, `
+		SELECT `+userExportColumns+`
+		FROM user_exports
+		WHERE user_id = $1 AND requested_by IS NOT DISTINCT FROM $2 AND created_at >= $3
+		  AND (status = 'pending' OR (status = 'ready' AND expires_at > now()))
+		ORDER BY created_at DESC
+		LIMIT 1`, userID, requestedBy, since))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.WithCtx(ctx).Error("user export repo: active lookup failed", zap.Error(err), zap.Int("user_id", userID))
+	}
+	return e, err
+}
+
+// MarkReady — файл собран; ссылка действует до expiresAt.
+func (r *UserExportRepository) MarkReady(ctx context.Context, id int64, path string, size int64, expiresAt time.Time) error {
+	if _, err := r.db.Exec(ctx, `
+		UPDATE user_exports
+		SET status = 'ready', file_path = $2, size_bytes = $3, finished_at = now(), expires_at = $4
+		WHERE id = $1`, id, path, size, expiresAt); err != nil {
+		logger.WithCtx(ctx).Error("user export repo: mark ready failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return nil
+}
+
+func (r *UserExportRepository) MarkFailed(ctx context.Context, id int64, reason string) error {
+	if _, err := r.db.Exec(ctx, `
+		UPDATE user_exports SET status = 'failed', error = $2, finished_at = now() WHERE id = $1`, id, reason); err != nil {
+		logger.WithCtx(ctx).Error("user export repo: mark failed failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return nil
+}
+
+// ExpireDue — помечает истёкшие выгрузки и возвращает их (файлы удаляет сервис).
+func (r *UserExportRepository) ExpireDue(ctx context.Context) ([]models.UserExport, error) {
+	rows, err := r.db.Query(ctx, `
+		UPDATE user_exports SET status = 'expired'
+		WHERE status = 'ready' AND expires_at <= now()
+		RETURNING `+userExportColumns)
+	if err != nil {
+		logger.WithCtx(ctx).Error("user export repo: expire failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.UserExport
+	for rows.Next() {
+		e, err := scanUserExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *e)
+	}
+	return out, rows.Err()
+}
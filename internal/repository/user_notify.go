@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// NotifyRecipients — подписчики рассылки (email_subscription), попадающие под аудиторию.
+func (r *UserRepository) NotifyRecipients(ctx context.Context, a models.NotifyAudience) ([]models.NotifyRecipient, error) {
+	log := logger.WithCtx(ctx)
+
+	where := " WHERE email_subscription = true"
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if a.Role != nil && strings.TrimSpace(*a.Role) != "" {
+		where += " AND role = " + arg(strings.TrimSpace(*a.Role))
+	}
+	if a.HasSubscription != nil {
+		where += " AND has_subscription = " + arg(*a.HasSubscription)
+	}
+	if a.TabID != nil {
+		where += ` AND id IN (
+			SELECT sf.user_id FROM section_follows sf JOIN sections s ON s.id = sf.section_id
+			WHERE s.tab_id = ` + arg(*a.TabID) + `)`
+	}
+	if a.SectionID != nil {
+		where += ` AND id IN (
+			WITH RECURSIVE up AS (
+				SELECT id, parent_section_id FROM sections WHERE id = ` + arg(*a.SectionID) + `
+				UNION
+				SELECT s.id, s.parent_section_id FROM sections s JOIN up ON s.id = up.parent_section_id
+			)
+			SELECT user_id FROM section_follows WHERE section_id IN (SELECT id FROM up))`
+	}
+	if a.CreatedFrom != nil {
+		where += " AND created_at >= " + arg(*a.CreatedFrom)
+	}
+	if a.CreatedTo != nil {
+		where += " AND created_at < " + arg(*a.CreatedTo)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT email, COALESCE(full_name, ''), COALESCE(username, ''), locale
+		FROM users`+where+` ORDER BY id`, args...)
+	if err != nil {
+		log.Error("user repo: notify recipients failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.NotifyRecipient
+	for rows.Next() {
+		var rc models.NotifyRecipient
+		if err := rows.Scan(&rc.Email, &rc.FullName, &rc.Username, &rc.Locale); err != nil {
+			log.Error("user repo: scan notify recipient failed", zap.Error(err))
+			return nil, err
+		}
+		out = append(out, rc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	log.Debug("user repo: got notify recipients", zap.Int("count", len(out)))
+	return out, nil
+}
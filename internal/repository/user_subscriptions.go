@@ -4,7 +4,6 @@ import (
 	"context"
 
 	"edutalks/internal/logger"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
@@ -18,10 +17,10 @@ const (
 )
 
 type SubscriptionRepository struct {
-	db *pgxpool.Pool
+	db DB
 }
 
-func NewSubscriptionRepository(db *pgxpool.Pool) *SubscriptionRepository {
+func NewSubscriptionRepository(db DB) *SubscriptionRepository {
 	return &SubscriptionRepository{db: db}
 }
 
@@ -30,7 +29,8 @@ func NewSubscriptionRepository(db *pgxpool.Pool) *SubscriptionRepository {
 func (r *SubscriptionRepository) GetAllSubscribedEmails(ctx context.Context) ([]string, error) {
 	log := logger.WithCtx(ctx)
 
-	const q = `SELECT email FROM users WHERE email_verified = TRUE AND email_subscription = TRUE`
+	// получающие сводку (email_frequency daily/weekly) сюда не входят — им уходит UserDigest
+	const q = `SELECT email FROM users WHERE email_verified = TRUE AND email_subscription = TRUE AND email_frequency = 'immediate'`
 
 	rows, err := r.db.Query(ctx, q)
 	if err != nil {
@@ -57,6 +57,29 @@ func (r *SubscriptionRepository) GetAllSubscribedEmails(ctx context.Context) ([]
 	return emails, nil
 }
 
+// EmailLocales — язык писем (users.locale) для адресов рассылки; адреса без пользователя в ответ не попадают.
+func (r *SubscriptionRepository) EmailLocales(ctx context.Context, emails []string) (map[string]string, error) {
+	out := make(map[string]string, len(emails))
+	if len(emails) == 0 {
+		return out, nil
+	}
+	rows, err := r.db.Query(ctx, `SELECT email, locale FROM users WHERE email = ANY($1)`, emails)
+	if err != nil {
+		logger.WithCtx(ctx).Error("subscription repo: query email locales failed", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var email, locale string
+		if err := rows.Scan(&email, &locale); err != nil {
+			return nil, err
+		}
+		out[email] = locale
+	}
+	return out, rows.Err()
+}
+
 /*
 Вариант 2 (тонкие темы): отдельная таблица user_email_topics(user_id, topic)
 и метод выборки по теме. Добавишь позже при желании.
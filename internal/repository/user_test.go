@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"edutalks/internal/models"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestUserGetByID(t *testing.T) {
+	mock := newMock(t)
+	repo := NewUserRepository(mock)
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expires := created.Add(30 * 24 * time.Hour)
+	avatar := "avatars/9_1_ab"
+	mock.ExpectQuery(sqlRe("SELECT id, username", "phone_verified, login_alerts, locale, email_frequency, avatar_key", "FROM users", "WHERE id = $1")).
+		WithArgs(9).
+		WillReturnRows(mock.NewRows([]string{
+			"id", "username", "full_name", "phone", "email", "address",
+			"password_hash", "role", "created_at", "updated_at",
+			"has_subscription", "subscription_expires_at",
+			"email_subscription", "email_verified", "phone_verified", "login_alerts", "locale", "email_frequency", "avatar_key",
+		}).AddRow(9, "ivan", "Иван Петров", "+79991234567", "ivan@example.com", "Москва",
+			"hash", "author", created, created,
+			true, &expires,
+			true, true, false, true, "en", "weekly", &avatar))
+
+	u, err := repo.GetUserByID(context.Background(), 9)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if u.ID != 9 || u.Username != "ivan" || u.FullName != "Иван Петров" || u.Role != "author" {
+		t.Errorf("поля профиля разобраны неверно: %+v", u)
+	}
+	if !u.HasSubscription || u.SubscriptionExpiresAt == nil || !u.SubscriptionExpiresAt.Equal(expires) {
+		t.Errorf("подписка разобрана неверно: %v %v", u.HasSubscription, u.SubscriptionExpiresAt)
+	}
+	if !u.EmailVerified || u.PhoneVerified || !u.LoginAlerts {
+		t.Errorf("флаги перепутаны: email_verified=%v phone_verified=%v login_alerts=%v", u.EmailVerified, u.PhoneVerified, u.LoginAlerts)
+	}
+	if u.Locale != "en" || u.EmailFrequency != "weekly" || u.AvatarKey == nil || *u.AvatarKey != avatar {
+		t.Errorf("настройки разобраны неверно: %+v", u)
+	}
+}
+
+func TestUserUpdateFields(t *testing.T) {
+	mock := newMock(t)
+	repo := NewUserRepository(mock)
+
+	name, phone := "Пётр", "+79990000000"
+	// смена номера сбрасывает подтверждение; id — последний аргумент
+	mock.ExpectExec(sqlRe("UPDATE users SET full_name = $1,", "phone = $2, phone_verified = (phone = $2 AND phone_verified)", "WHERE id = $3")).
+		WithArgs(name, phone, 4).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	if err := repo.UpdateUserFields(context.Background(), 4, &models.UpdateUserRequest{FullName: &name, Phone: &phone}); err != nil {
+		t.Fatalf("UpdateUserFields: %v", err)
+	}
+}
+
+func TestUserUpdateFieldsEmpty(t *testing.T) {
+	mock := newMock(t)
+	repo := NewUserRepository(mock)
+
+	// без полей запрос не отправляется
+	if err := repo.UpdateUserFields(context.Background(), 4, &models.UpdateUserRequest{}); err != nil {
+		t.Fatalf("UpdateUserFields: %v", err)
+	}
+}
+
+func TestUserUpdateLoginAlerts(t *testing.T) {
+	mock := newMock(t)
+	repo := NewUserRepository(mock)
+
+	mock.ExpectExec(sqlRe("UPDATE users SET login_alerts = $1 WHERE id = $2")).
+		WithArgs(false, 12).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	if err := repo.UpdateLoginAlerts(context.Background(), 12, false); err != nil {
+		t.Fatalf("UpdateLoginAlerts: %v", err)
+	}
+}
+
+func TestUserGetByPhonePrefersVerified(t *testing.T) {
+	mock := newMock(t)
+	repo := NewUserRepository(mock)
+
+	// номер может стоять в нескольких профилях: берём подтвердившего, иначе самого раннего
+	mock.ExpectQuery(sqlRe("FROM users", "WHERE right(regexp_replace(phone, '\\D', '', 'g'), 10) = right($1, 10)",
+		"ORDER BY phone_verified DESC, id", "LIMIT 1")).
+		WithArgs("79991234567").
+		WillReturnRows(mock.NewRows([]string{
+			"id", "username", "full_name", "phone", "email", "address",
+			"password_hash", "role", "created_at", "updated_at",
+			"has_subscription", "subscription_expires_at",
+			"email_subscription", "email_verified", "phone_verified", "login_alerts", "locale", "email_frequency",
+		}).AddRow(4, "ivan", "", "+7 999 123-45-67", "ivan@example.com", "",
+			"hash", "user", time.Now(), time.Now(),
+			false, (*time.Time)(nil),
+			true, true, true, true, "ru", "daily"))
+
+	u, err := repo.GetUserByPhone(context.Background(), "79991234567")
+	if err != nil {
+		t.Fatalf("GetUserByPhone: %v", err)
+	}
+	if u.ID != 4 || !u.PhoneVerified {
+		t.Errorf("пользователь = %+v", u)
+	}
+}
+
+func TestUserTokenStatus(t *testing.T) {
+	mock := newMock(t)
+	repo := NewUserRepository(mock)
+
+	revoked := time.Date(2026, 10, 16, 9, 0, 0, 500_000_000, time.UTC)
+	mock.ExpectQuery(sqlRe("access_token_blacklist", "user_token_revocations", "user_sessions")).
+		WithArgs("tok", 7, int64(55)).
+		WillReturnRows(mock.NewRows([]string{"blacklisted", "revoked_at", "session_revoked"}).AddRow(false, &revoked, true))
+
+	st, err := repo.TokenStatus(context.Background(), "tok", 7, 55)
+	if err != nil {
+		t.Fatalf("TokenStatus: %v", err)
+	}
+	if st.Blacklisted || !st.RevokedAt.Equal(revoked) || !st.SessionRevoked {
+		t.Errorf("статус = %+v", st)
+	}
+}
@@ -8,6 +8,7 @@ type key int
 const (
 	keyRequestID key = iota
 	keyUserID
+	keyImpersonatorID
 )
 
 func WithRequestID(ctx context.Context, id string) context.Context {
@@ -27,3 +28,13 @@ func GetUserID(ctx context.Context) (int, bool) {
 	v, ok := ctx.Value(keyUserID).(int)
 	return v, ok
 }
+
+// WithImpersonatorID — запрос выполняется администратором от имени пользователя.
+func WithImpersonatorID(ctx context.Context, adminID int) context.Context {
+	return context.WithValue(ctx, keyImpersonatorID, adminID)
+}
+
+func GetImpersonatorID(ctx context.Context) (int, bool) {
+	v, ok := ctx.Value(keyImpersonatorID).(int)
+	return v, ok
+}
@@ -0,0 +1,216 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/gorilla/mux"
+	"github.com/swaggo/swag"
+	"go.uber.org/zap"
+)
+
+// Имена маршрутов-групп: по ним отчёт определяет, какие middleware стоят над маршрутом.
+const (
+	groupProtected = "group:protected"
+	groupAdmin     = "group:admin"
+	groupAuthor    = "group:author"
+)
+
+// handlerAuthRoutes — публичные в роутере маршруты, которые сами проверяют Bearer-токен.
+var handlerAuthRoutes = map[string]bool{
+	"POST /api/logout": true,
+}
+
+type swaggerOp struct {
+	Security []map[string][]string `json:"security"`
+}
+
+// swaggerSecurity — "METHOD {path}" → объявлена ли security в зарегистрированном Swagger.
+func swaggerSecurity() (map[string]bool, error) {
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		return nil, err
+	}
+	var spec struct {
+		Paths map[string]map[string]swaggerOp `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]bool)
+	for path, ops := range spec.Paths {
+		for method, op := range ops {
+			out[strings.ToUpper(method)+" "+normalizePath(path)] = len(op.Security) > 0
+		}
+	}
+	return out, nil
+}
+
+// normalizePath — "/users/{id:[0-9]+}" и "/users/{user_id}" сравниваются как "/users/{}".
+func normalizePath(p string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range p {
+		switch {
+		case r == '{':
+			if depth == 0 {
+				b.WriteString("{}")
+			}
+			depth++
+		case r == '}':
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "/")
+}
+
+func routeAccess(ancestors []*mux.Route) string {
+	access := models.RouteAccessPublic
+	for _, a := range ancestors {
+		switch a.GetName() {
+		case groupAdmin:
+			return models.RouteAccessAdmin
+		case groupAuthor:
+			return models.RouteAccessAuthor
+		case groupProtected:
+			access = models.RouteAccessProtected
+		}
+	}
+	return access
+}
+
+// BuildAuthzReport — обходит роутер и сверяет уровень доступа каждого маршрута с security в Swagger.
+func BuildAuthzReport(router *mux.Router) *models.AuthzReport {
+	docs, err := swaggerSecurity()
+	if err != nil {
+		logger.Log.Warn("authz: не удалось прочитать Swagger", zap.Error(err))
+		docs = map[string]bool{}
+	}
+
+	report := &models.AuthzReport{
+		GeneratedAt: time.Now(),
+		Summary:     map[string]int{},
+		Routes:      []models.RouteAccess{},
+		Mismatches:  []models.RouteAccess{},
+	}
+	seen := make(map[string]bool)
+
+	_ = router.Walk(func(route *mux.Route, _ *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil // группы и PathPrefix без методов (например, /swagger/)
+		}
+		for _, m := range methods {
+			key := m + " " + normalizePath(path)
+			access := routeAccess(ancestors)
+			if access == models.RouteAccessPublic && handlerAuthRoutes[key] {
+				access = models.RouteAccessHandler
+			}
+			seen[key] = true
+			secured, documented := docs[key]
+
+			ra := models.RouteAccess{
+				Method:          m,
+				Path:            path,
+				Access:          access,
+				Documented:      documented,
+				SwaggerSecurity: secured,
+			}
+			switch {
+			case !documented:
+				ra.Mismatch = models.AuthzUndocumented
+			case access != models.RouteAccessPublic && !secured:
+				ra.Mismatch = models.AuthzMissingSecurity
+			case access == models.RouteAccessPublic && secured:
+				ra.Mismatch = models.AuthzExtraSecurity
+			}
+
+			report.Summary[access]++
+			report.Routes = append(report.Routes, ra)
+			if ra.Mismatch != "" {
+				report.Summary[ra.Mismatch]++
+				report.Mismatches = append(report.Mismatches, ra)
+			}
+		}
+		return nil
+	})
+
+	for key, secured := range docs {
+		if seen[key] {
+			continue
+		}
+		method, path, _ := strings.Cut(key, " ")
+		report.Summary[models.AuthzStaleDoc]++
+		report.Mismatches = append(report.Mismatches, models.RouteAccess{
+			Method:          method,
+			Path:            path,
+			Documented:      true,
+			SwaggerSecurity: secured,
+			Mismatch:        models.AuthzStaleDoc,
+		})
+	}
+	sort.Slice(report.Mismatches, func(i, j int) bool {
+		a, b := report.Mismatches[i], report.Mismatches[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+
+	return report
+}
+
+// CheckAuthz — проверка при старте (AUTHZ_CHECK): "off" — пропустить, "warn" — только логировать,
+// "strict" — вернуть ошибку, если у защищённого маршрута нет security в Swagger или наоборот.
+// Незадокументированные маршруты и устаревшие пути Swagger ошибкой не считаются.
+func CheckAuthz(router *mux.Router, mode string) error {
+	if mode == "off" {
+		return nil
+	}
+	report := BuildAuthzReport(router)
+
+	var critical int
+	for _, m := range report.Mismatches {
+		fields := []zap.Field{
+			zap.String("method", m.Method),
+			zap.String("path", m.Path),
+			zap.String("access", m.Access),
+			zap.String("mismatch", m.Mismatch),
+		}
+		switch m.Mismatch {
+		case models.AuthzMissingSecurity, models.AuthzExtraSecurity:
+			critical++
+			logger.Log.Error("authz: доступ маршрута расходится со Swagger", fields...)
+		default:
+			logger.Log.Warn("authz: маршрут и Swagger не совпадают", fields...)
+		}
+	}
+
+	logger.Log.Info("authz: проверка маршрутов завершена",
+		zap.Int("routes", len(report.Routes)),
+		zap.Int("public", report.Summary[models.RouteAccessPublic]),
+		zap.Int("protected", report.Summary[models.RouteAccessProtected]),
+		zap.Int("author", report.Summary[models.RouteAccessAuthor]),
+		zap.Int("admin", report.Summary[models.RouteAccessAdmin]),
+		zap.Int("handler", report.Summary[models.RouteAccessHandler]),
+		zap.Int("security_mismatches", critical),
+	)
+
+	if critical > 0 && mode == "strict" {
+		return fmt.Errorf("authz: %d маршрутов расходятся со Swagger по авторизации", critical)
+	}
+	return nil
+}
@@ -1,23 +1,29 @@
 package routes
 
 import (
+	"edutalks/internal/config"
 	"edutalks/internal/handlers"
 	"edutalks/internal/middleware"
+	"edutalks/internal/models"
 	"edutalks/internal/repository"
+	"edutalks/internal/utils/clientip"
 	"github.com/gorilla/mux"
 	"net/http"
+	"time"
 )
 
-// helper-обёртка для передачи repo в middleware.JWTAuth
-func jwtMiddleware(repo repository.UserRepo) mux.MiddlewareFunc {
+// helper-обёртка для передачи хранилища токенов и конфигурации в middleware.JWTAuth
+func jwtMiddleware(tokens repository.TokenStore, cfg *config.Holder) mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
-		return middleware.JWTAuth(repo, next)
+		return middleware.JWTAuth(tokens, cfg, next)
 	}
 }
 
 func InitRoutes(
 	router *mux.Router,
-	userRepo repository.UserRepo, // ✅ новый аргумент
+	tokens repository.TokenStore, // блоклист токенов: Redis или Postgres
+	cfg *config.Holder,
+	userLocale middleware.LocaleLookup, // язык ответов авторизованным пользователям — из профиля
 	authHandler *handlers.AuthHandler,
 	documentHandler *handlers.DocumentHandler,
 	newsHandler *handlers.NewsHandler,
@@ -29,31 +35,86 @@ func InitRoutes(
 	taxonomyH *handlers.TaxonomyHandler,
 	passwordH *handlers.PasswordHandler,
 	logsAdminH *handlers.AdminLogsHandler,
+	emailOutboxH *handlers.EmailOutboxHandler,
+	digestH *handlers.AdminDigestHandler,
+	alertsH *handlers.AdminAlertsHandler,
+	trashH *handlers.TrashHandler,
+	reviewH *handlers.ContentReviewHandler,
+	downloadStatsH *handlers.DownloadStatsHandler,
+	downloadQuotaH *handlers.DownloadQuotaHandler,
+	contentViewH *handlers.ContentViewHandler,
+	dashboardStatsH *handlers.DashboardStatsHandler,
+	userActivityH *handlers.UserActivityHandler,
+	impersonationH *handlers.ImpersonationHandler,
+	announcementH *handlers.AnnouncementHandler,
+	featureFlagH *handlers.FeatureFlagHandler,
+	htmlProfileH *handlers.HTMLProfileHandler,
+	articleMediaH *handlers.ArticleMediaHandler,
+	notificationH *handlers.NotificationHandler,
+	changelogH *handlers.ChangelogHandler,
+	systemH *handlers.SystemHandler,
+	emailTemplatesH *handlers.EmailTemplateHandler,
+	notifyH *handlers.NotifyHandler,
+	exportH *handlers.UserExportHandler,
+	deletionH *handlers.AccountDeletionHandler,
+	emailChangeH *handlers.EmailChangeHandler,
+	oauthH *handlers.OAuthHandler,
+	avatarH *handlers.AvatarHandler,
+	phoneH *handlers.PhoneVerificationHandler,
+	proxies clientip.Proxies,
+	limits middleware.RateLimits,
+	compress middleware.CompressOptions,
 ) {
-	router.Use(middleware.Logging)
+	router.Use(middleware.ClientIP(proxies), middleware.RequestID, middleware.Locale, middleware.Logging, middleware.Metrics, middleware.Sentry, middleware.Recoverer, middleware.Compress(compress),
+		middleware.RequestDeadline(func() time.Duration { return cfg.Get().RequestDeadline() }))
 
 	// Корневой /api
 	api := router.PathPrefix("/api").Subrouter()
+	api.Use(middleware.RateLimit(limits.Global))
+
+	// ограничение попыток входа/регистрации/восстановления пароля
+	authLimited := middleware.RateLimit(limits.Auth)
 
 	// ---------- ПУБЛИЧНЫЕ ----------
-	api.HandleFunc("/register", authHandler.Register).Methods(http.MethodPost)
-	api.HandleFunc("/login", authHandler.Login).Methods(http.MethodPost)
+	api.Handle("/register", authLimited(http.HandlerFunc(authHandler.Register))).Methods(http.MethodPost)
+	api.Handle("/login", authLimited(http.HandlerFunc(authHandler.Login))).Methods(http.MethodPost)
 	api.HandleFunc("/logout", authHandler.Logout).Methods(http.MethodPost)
 
+	// вход через соцсети (Google, Яндекс)
+	api.HandleFunc("/auth/oauth/providers", oauthH.Providers).Methods(http.MethodGet)
+	api.Handle("/auth/oauth/{provider:[a-z]+}", authLimited(http.HandlerFunc(oauthH.Start))).Methods(http.MethodGet)
+	api.Handle("/auth/oauth/{provider:[a-z]+}/callback", authLimited(http.HandlerFunc(oauthH.Callback))).Methods(http.MethodGet)
+
 	// платежный вебхук (публичная точка приёмки от ЮKassa)
 	api.HandleFunc("/payments/webhook", webhookHandler.HandleWebhook).Methods(http.MethodPost)
 
 	// контент, доступный без авторизации
 	api.HandleFunc("/news", newsHandler.ListNews).Methods(http.MethodGet)
-	api.HandleFunc("/news/{id:[0-9]+}", newsHandler.GetNews).Methods(http.MethodGet)
+	api.HandleFunc("/news/feed.rss", newsHandler.NewsFeed).Methods(http.MethodGet)
+	api.Handle("/news/{id:[0-9]+}", middleware.OptionalJWTAuth(tokens, cfg, http.HandlerFunc(newsHandler.GetNews))).Methods(http.MethodGet)
 
 	// публичные статьи
 	api.HandleFunc("/articles", articleH.GetAll).Methods(http.MethodGet)
-	api.HandleFunc("/articles/{id:[0-9]+}", articleH.GetByID).Methods(http.MethodGet)
+	api.HandleFunc("/articles/feed.rss", articleH.Feed).Methods(http.MethodGet)
+	api.Handle("/articles/{id:[0-9]+}", middleware.OptionalJWTAuth(tokens, cfg, http.HandlerFunc(articleH.GetByID))).Methods(http.MethodGet)
+
+	// популярное по просмотрам
+	api.HandleFunc("/content/trending", contentViewH.Trending).Methods(http.MethodGet)
 
 	api.HandleFunc("/verify-email", emailHandler.VerifyEmail).Methods(http.MethodGet)
 	api.HandleFunc("/resend-verification", authHandler.ResendVerificationEmail).Methods(http.MethodPost)
 
+	// отписка по ссылке из письма: GET — страница подтверждения, POST — отписка (List-Unsubscribe-Post)
+	api.HandleFunc("/unsubscribe", authHandler.Unsubscribe).Methods(http.MethodGet)
+	api.HandleFunc("/unsubscribe", authHandler.UnsubscribeOneClick).Methods(http.MethodPost)
+
+	// отмена удаления аккаунта по ссылке из письма
+	api.HandleFunc("/account/deletion/cancel", deletionH.CancelDeletion).Methods(http.MethodGet)
+	api.HandleFunc("/profile/email/confirm", emailChangeH.ConfirmEmailChange).Methods(http.MethodGet)
+
+	// выгрузка данных пользователя по подписанной ссылке из письма
+	api.HandleFunc("/exports/{id:[0-9]+}/download", exportH.Download).Methods(http.MethodGet)
+
 	// превью документов
 	api.HandleFunc("/documents/{id:[0-9]+}/preview", documentHandler.PreviewDocument).Methods(http.MethodGet)
 	api.HandleFunc("/documents/preview", documentHandler.PreviewDocuments).Methods(http.MethodGet)
@@ -61,75 +122,247 @@ func InitRoutes(
 	// публичный таксономический лес
 	api.HandleFunc("/taxonomy/tree", taxonomyH.PublicTree).Methods(http.MethodGet)
 	api.HandleFunc("/taxonomy/tree/{tab}", taxonomyH.PublicTreeByTab).Methods(http.MethodGet)
+	api.HandleFunc("/taxonomy/resolve", taxonomyH.Resolve).Methods(http.MethodGet)
 
 	// публичный список файлов
 	api.HandleFunc("/files", documentHandler.ListPublicDocuments).Methods(http.MethodGet)
 
+	// обновления платформы
+	api.HandleFunc("/changelog", changelogH.List).Methods(http.MethodGet)
+	api.HandleFunc("/announcements", announcementH.Active).Methods(http.MethodGet)
+	api.Handle("/flags", middleware.OptionalJWTAuth(tokens, cfg, http.HandlerFunc(featureFlagH.Flags))).Methods(http.MethodGet)
+
 	// глобальный поиск
 	api.HandleFunc("/search", searchHandler.GlobalSearch).Methods(http.MethodGet)
 
+	// аватары: загруженный — редирект на файл, иначе identicon
+	api.HandleFunc("/avatars/{id:[0-9]+}", avatarH.Avatar).Methods(http.MethodGet)
+
 	// восстановление пароля
-	api.HandleFunc("/password/forgot", passwordH.Forgot).Methods(http.MethodPost)
+	api.Handle("/password/forgot", authLimited(http.HandlerFunc(passwordH.Forgot))).Methods(http.MethodPost)
 	api.HandleFunc("/password/reset", passwordH.Reset).Methods(http.MethodPost)
+	api.Handle("/password/forgot-phone", authLimited(http.HandlerFunc(phoneH.ForgotByPhone))).Methods(http.MethodPost)
+	api.Handle("/password/reset-phone", authLimited(http.HandlerFunc(phoneH.ResetByPhone))).Methods(http.MethodPost)
 
 	// ---------- ПРОТЕКТИРОВАННЫЕ (JWT) ----------
-	protected := api.PathPrefix("").Subrouter()
-	protected.Use(jwtMiddleware(userRepo)) // ✅ теперь проверка токена идёт с блоклистом
+	protected := api.PathPrefix("").Name(groupProtected).Subrouter()
+	protected.Use(jwtMiddleware(tokens, cfg)) // ✅ теперь проверка токена идёт с блоклистом
+	protected.Use(middleware.UserLocale(userLocale))
+	// после JWT — лимит по user_id
+	protected.Use(middleware.RateLimitByUser(limits.User))
+
+	// действия над аккаунтом и оплата — не с токеном поддержки (см. middleware.NoImpersonation)
+	sensitive := func(h http.HandlerFunc) http.Handler { return middleware.NoImpersonation(h) }
+
+	// выход из режима поддержки (токен поддержки)
+	protected.HandleFunc("/impersonation/stop", impersonationH.StopImpersonation).Methods(http.MethodPost)
 
 	// профиль, платеж и пр.
-	protected.HandleFunc("/pay", paymentHandler.CreatePayment).Methods(http.MethodGet)
+	protected.Handle("/pay", sensitive(paymentHandler.CreatePayment)).Methods(http.MethodGet)
+	protected.HandleFunc("/payments", paymentHandler.MyPayments).Methods(http.MethodGet)
+	protected.HandleFunc("/profile/payments/{id:[0-9]+}/invoice", paymentHandler.Invoice).Methods(http.MethodGet)
 	protected.HandleFunc("/profile", authHandler.Protected).Methods(http.MethodGet)
 	protected.HandleFunc("/email-subscription", authHandler.EmailSubscribe).Methods(http.MethodPatch)
 	protected.HandleFunc("/profile", authHandler.UpdateMyProfile).Methods(http.MethodPatch)
+	protected.Handle("/profile", sensitive(deletionH.DeleteMyAccount)).Methods(http.MethodDelete)
+	protected.Handle("/profile/email", sensitive(emailChangeH.RequestEmailChange)).Methods(http.MethodPost)
+	protected.Handle("/profile/sessions", sensitive(authHandler.ListMySessions)).Methods(http.MethodGet)
+	protected.Handle("/profile/sessions/{id:[0-9]+}", sensitive(authHandler.RevokeMySession)).Methods(http.MethodDelete)
+	protected.HandleFunc("/profile/oauth", oauthH.ListMyIdentities).Methods(http.MethodGet)
+	protected.Handle("/profile/oauth/{provider:[a-z]+}", sensitive(oauthH.StartLink)).Methods(http.MethodPost)
+	protected.Handle("/profile/oauth/{provider:[a-z]+}", sensitive(oauthH.Unlink)).Methods(http.MethodDelete)
+	protected.HandleFunc("/profile/avatar", avatarH.UploadAvatar).Methods(http.MethodPost)
+	protected.HandleFunc("/profile/avatar", avatarH.DeleteAvatar).Methods(http.MethodDelete)
+	protected.Handle("/profile/phone/verification", sensitive(phoneH.RequestPhoneVerification)).Methods(http.MethodPost)
+	protected.Handle("/profile/phone/verification/confirm", sensitive(phoneH.ConfirmPhoneVerification)).Methods(http.MethodPost)
+	protected.Handle("/profile/export", sensitive(exportH.ExportMyData)).Methods(http.MethodGet)
+	protected.HandleFunc("/profile/download-quota", downloadQuotaH.MyQuota).Methods(http.MethodGet)
 
 	// скачивание файла
 	protected.HandleFunc("/files/{id:[0-9]+}", documentHandler.DownloadDocument).Methods(http.MethodGet)
+	protected.HandleFunc("/files/{id:[0-9]+}/view", documentHandler.ViewDocument).Methods(http.MethodGet)
+
+	// бейдж «Что нового»
+	protected.HandleFunc("/changelog/unread", changelogH.Unread).Methods(http.MethodGet)
+	protected.HandleFunc("/changelog/seen", changelogH.MarkSeen).Methods(http.MethodPost)
+
+	// уведомления в личном кабинете
+	protected.HandleFunc("/notifications", notificationH.List).Methods(http.MethodGet)
+	protected.HandleFunc("/notifications/unread-count", notificationH.UnreadCount).Methods(http.MethodGet)
+	protected.HandleFunc("/notifications/stream", notificationH.Stream).Methods(http.MethodGet) // SSE
+	protected.HandleFunc("/notifications/read-all", notificationH.MarkAllRead).Methods(http.MethodPost)
+	protected.HandleFunc("/notifications/{id:[0-9]+}/read", notificationH.MarkRead).Methods(http.MethodPost)
+	protected.HandleFunc("/profile/follows/sections", notificationH.ListFollows).Methods(http.MethodGet)
+	protected.HandleFunc("/sections/{id:[0-9]+}/follow", notificationH.Follow).Methods(http.MethodPost)
+	protected.HandleFunc("/sections/{id:[0-9]+}/follow", notificationH.Unfollow).Methods(http.MethodDelete)
 
 	// смена пароля
-	protected.HandleFunc("/password/change", passwordH.Change).Methods(http.MethodPost)
+	protected.Handle("/password/change", sensitive(passwordH.Change)).Methods(http.MethodPost)
+
+	// ---------- АВТОР ----------
+	// свои статьи и документы (роли author и admin); публикует только админ
+	author := protected.PathPrefix("/author").Name(groupAuthor).Subrouter()
+	author.Use(middleware.RequirePermission(models.PermContentManage))
+
+	author.HandleFunc("/articles", articleH.MyArticles).Methods(http.MethodGet)
+	author.HandleFunc("/articles", articleH.CreateMyArticle).Methods(http.MethodPost)
+	author.HandleFunc("/articles/{id:[0-9]+}", articleH.UpdateMyArticle).Methods(http.MethodPatch)
+	author.HandleFunc("/articles/{id:[0-9]+}", articleH.DeleteMyArticle).Methods(http.MethodDelete)
+	author.HandleFunc("/files", documentHandler.MyDocuments).Methods(http.MethodGet)
+	author.HandleFunc("/files/upload", documentHandler.UploadMyDocument).Methods(http.MethodPost)
+	author.HandleFunc("/files/{id:[0-9]+}", documentHandler.UpdateMyDocument).Methods(http.MethodPatch)
+	author.HandleFunc("/files/{id:[0-9]+}", documentHandler.DeleteMyDocument).Methods(http.MethodDelete)
+	author.HandleFunc("/reviews/{type:article|document}/{id:[0-9]+}/submit", reviewH.Submit).Methods(http.MethodPost)
+	author.HandleFunc("/reviews/{type:article|document}/{id:[0-9]+}/history", reviewH.MyHistory).Methods(http.MethodGet)
 
 	// ---------- АДМИН ----------
-	admin := protected.PathPrefix("/admin").Subrouter()
+	admin := protected.PathPrefix("/admin").Name(groupAdmin).Subrouter()
 	admin.Use(middleware.OnlyRole("admin"))
 
 	admin.HandleFunc("/stats", authHandler.GetSystemStats).Methods(http.MethodGet)
+	admin.HandleFunc("/stats/downloads", downloadStatsH.Stats).Methods(http.MethodGet)
+	admin.HandleFunc("/stats/downloads/top", downloadStatsH.Top).Methods(http.MethodGet)
+	admin.HandleFunc("/stats/registrations", dashboardStatsH.Registrations).Methods(http.MethodGet)
+	admin.HandleFunc("/stats/subscriptions", dashboardStatsH.Subscriptions).Methods(http.MethodGet)
+	admin.HandleFunc("/stats/uploads", dashboardStatsH.Uploads).Methods(http.MethodGet)
+	admin.HandleFunc("/stats/admins", dashboardStatsH.TopAdmins).Methods(http.MethodGet)
+	admin.HandleFunc("/payments", paymentHandler.AdminPayments).Methods(http.MethodGet)
+	admin.HandleFunc("/payments/sandbox/webhook", webhookHandler.SandboxWebhook).Methods(http.MethodPost) // только PAYMENT_SANDBOX=true
+	admin.HandleFunc("/digest/send", digestH.Send).Methods(http.MethodPost)
+	admin.HandleFunc("/alerts/test", alertsH.Test).Methods(http.MethodPost)
+
+	// корзина: удалённые документы, новости и статьи
+	admin.HandleFunc("/trash", trashH.List).Methods(http.MethodGet)
+	admin.HandleFunc("/trash/{type:document|news|article}/{id:[0-9]+}/restore", trashH.Restore).Methods(http.MethodPost)
+	admin.HandleFunc("/trash/{type:document|news|article}/{id:[0-9]+}", trashH.Delete).Methods(http.MethodDelete)
+
+	// согласование материалов авторов
+	admin.HandleFunc("/reviews", reviewH.Queue).Methods(http.MethodGet)
+	admin.HandleFunc("/reviews/{type:article|document}/{id:[0-9]+}/approve", reviewH.Approve).Methods(http.MethodPost)
+	admin.HandleFunc("/reviews/{type:article|document}/{id:[0-9]+}/reject", reviewH.Reject).Methods(http.MethodPost)
+	admin.HandleFunc("/reviews/{type:article|document}/{id:[0-9]+}/history", reviewH.History).Methods(http.MethodGet)
+
+	admin.HandleFunc("/system/authz-report", systemH.AuthzReport).Methods(http.MethodGet)
+	admin.HandleFunc("/system/reindex", systemH.StartReindex).Methods(http.MethodPost)
+	admin.HandleFunc("/system/reindex", systemH.ReindexStatus).Methods(http.MethodGet)
+	admin.HandleFunc("/system/selfcheck", systemH.SelfCheck).Methods(http.MethodGet)
+
+	// шаблоны писем
+	admin.HandleFunc("/email-templates", emailTemplatesH.List).Methods(http.MethodGet)
+	admin.HandleFunc("/email-templates/{name}", emailTemplatesH.Get).Methods(http.MethodGet)
+	admin.HandleFunc("/email-templates/{name}", emailTemplatesH.Save).Methods(http.MethodPut)
+	admin.HandleFunc("/email-templates/{name}", emailTemplatesH.Reset).Methods(http.MethodDelete)
+	admin.HandleFunc("/email-templates/{name}/preview", emailTemplatesH.Preview).Methods(http.MethodPost)
 
 	// файлы (админ)
 	admin.HandleFunc("/files", documentHandler.GetAllDocuments).Methods(http.MethodGet)
 	admin.HandleFunc("/files/upload", documentHandler.UploadDocument).Methods(http.MethodPost)
+	admin.HandleFunc("/files/rescan", documentHandler.RescanDocuments).Methods(http.MethodPost)
+	admin.HandleFunc("/files/duplicates", documentHandler.DuplicateReport).Methods(http.MethodGet)
+	admin.HandleFunc("/files/{id:[0-9]+}/rescan", documentHandler.RescanDocument).Methods(http.MethodPost)
+	admin.HandleFunc("/files/bulk-move", documentHandler.BulkMoveDocuments).Methods(http.MethodPost)
 	admin.HandleFunc("/files/{id:[0-9]+}", documentHandler.DeleteDocument).Methods(http.MethodDelete)
+	admin.HandleFunc("/files/{id:[0-9]+}", documentHandler.UpdateDocument).Methods(http.MethodPatch)
+	admin.HandleFunc("/files/{id:[0-9]+}/accessibility", documentHandler.UpdateAccessibility).Methods(http.MethodPatch)
+	admin.HandleFunc("/files/{id:[0-9]+}/normative", documentHandler.UpdateNormative).Methods(http.MethodPatch)
+	admin.HandleFunc("/files/{id:[0-9]+}/relations", documentHandler.ListRelations).Methods(http.MethodGet)
+	admin.HandleFunc("/files/{id:[0-9]+}/relations", documentHandler.AddRelation).Methods(http.MethodPost)
+	admin.HandleFunc("/files/{id:[0-9]+}/relations/{relationId:[0-9]+}", documentHandler.DeleteRelation).Methods(http.MethodDelete)
+	admin.HandleFunc("/files/{id:[0-9]+}/grants", documentHandler.ListGrants).Methods(http.MethodGet)
+	admin.HandleFunc("/files/{id:[0-9]+}/grants", documentHandler.GrantAccess).Methods(http.MethodPost)
+	admin.HandleFunc("/files/{id:[0-9]+}/grants/{userId:[0-9]+}", documentHandler.RevokeAccess).Methods(http.MethodDelete)
 
 	// пользователи
 	admin.HandleFunc("/dashboard", authHandler.AdminOnly).Methods(http.MethodGet)
 	admin.HandleFunc("/users", authHandler.GetUsers).Methods(http.MethodGet)
+	admin.HandleFunc("/users/bulk", authHandler.BulkUsers).Methods(http.MethodPost)
 	admin.HandleFunc("/users/{id}", authHandler.GetUserByID).Methods(http.MethodGet)
 	admin.HandleFunc("/users/{id}", authHandler.UpdateUser).Methods(http.MethodPatch)
 	admin.HandleFunc("/users/{id}/subscription", authHandler.SetSubscription).Methods(http.MethodPatch)
 	admin.HandleFunc("/users/{id}", authHandler.DeleteUser).Methods(http.MethodDelete)
+	admin.HandleFunc("/users/{id:[0-9]+}/export", exportH.ExportUserData).Methods(http.MethodPost)
+	admin.HandleFunc("/users/{id:[0-9]+}/sessions", authHandler.AdminListUserSessions).Methods(http.MethodGet)
+	admin.HandleFunc("/users/{id:[0-9]+}/sessions/{sid:[0-9]+}", authHandler.AdminRevokeUserSession).Methods(http.MethodDelete)
+	admin.HandleFunc("/users/{id:[0-9]+}/download-quota", downloadQuotaH.AdminQuota).Methods(http.MethodGet)
+	admin.HandleFunc("/users/{id:[0-9]+}/download-quota", downloadQuotaH.SetOverride).Methods(http.MethodPut)
+	admin.HandleFunc("/users/{id:[0-9]+}/download-quota", downloadQuotaH.ClearOverride).Methods(http.MethodDelete)
+	admin.HandleFunc("/users/{id:[0-9]+}/activity", userActivityH.List).Methods(http.MethodGet)
+	admin.HandleFunc("/users/{id:[0-9]+}/impersonate", impersonationH.Impersonate).Methods(http.MethodPost)
+	admin.HandleFunc("/impersonations/{id:[0-9]+}/stop", impersonationH.AdminStopImpersonation).Methods(http.MethodPost)
 
 	// новости (админ)
+	admin.HandleFunc("/news", newsHandler.AdminListNews).Methods(http.MethodGet)
 	admin.HandleFunc("/news", newsHandler.CreateNews).Methods(http.MethodPost)
 	admin.HandleFunc("/news/{id:[0-9]+}", newsHandler.UpdateNews).Methods(http.MethodPatch)
+	admin.HandleFunc("/news/{id:[0-9]+}/publish", newsHandler.SetPublishNews).Methods(http.MethodPatch)
 	admin.HandleFunc("/news/{id:[0-9]+}", newsHandler.DeleteNews).Methods(http.MethodDelete)
 	admin.HandleFunc("/news/upload", newsHandler.UploadNewsImage).Methods(http.MethodPost)
 
+	// changelog (админ)
+	admin.HandleFunc("/changelog", changelogH.AdminList).Methods(http.MethodGet)
+	admin.HandleFunc("/changelog", changelogH.Create).Methods(http.MethodPost)
+	admin.HandleFunc("/changelog/{id:[0-9]+}", changelogH.Update).Methods(http.MethodPatch)
+	admin.HandleFunc("/changelog/{id:[0-9]+}", changelogH.Delete).Methods(http.MethodDelete)
+
+	// баннеры сайта (админ)
+	admin.HandleFunc("/announcements", announcementH.AdminList).Methods(http.MethodGet)
+	admin.HandleFunc("/announcements", announcementH.Create).Methods(http.MethodPost)
+	admin.HandleFunc("/announcements/{id:[0-9]+}", announcementH.Update).Methods(http.MethodPut)
+	admin.HandleFunc("/announcements/{id:[0-9]+}", announcementH.Delete).Methods(http.MethodDelete)
+
+	// флаги фич (админ)
+	admin.HandleFunc("/flags", featureFlagH.AdminList).Methods(http.MethodGet)
+	admin.HandleFunc("/flags", featureFlagH.Create).Methods(http.MethodPost)
+	admin.HandleFunc("/flags/{key}", featureFlagH.Update).Methods(http.MethodPut)
+	admin.HandleFunc("/flags/{key}", featureFlagH.Delete).Methods(http.MethodDelete)
+
 	// рассылка
 	admin.HandleFunc("/notify", authHandler.NotifySubscribers).Methods(http.MethodPost)
+	admin.HandleFunc("/notify/batch/flush", notifyH.FlushBatch).Methods(http.MethodPost)
+	admin.HandleFunc("/notify/campaigns", notifyH.ListCampaigns).Methods(http.MethodGet)
+	admin.HandleFunc("/notify/campaigns", notifyH.CreateCampaign).Methods(http.MethodPost)
+	admin.HandleFunc("/notify/campaigns/{id:[0-9]+}", notifyH.GetCampaign).Methods(http.MethodGet)
+	admin.HandleFunc("/notify/campaigns/{id:[0-9]+}", notifyH.UpdateCampaign).Methods(http.MethodPut)
+	admin.HandleFunc("/notify/campaigns/{id:[0-9]+}/stats", notifyH.CampaignStats).Methods(http.MethodGet)
+	admin.HandleFunc("/notify/campaigns/{id:[0-9]+}/cancel", notifyH.CancelCampaign).Methods(http.MethodPost)
+	admin.HandleFunc("/notifications/broadcast", notificationH.Broadcast).Methods(http.MethodPost)
+
+	// очередь писем (outbox)
+	admin.HandleFunc("/emails", emailOutboxH.List).Methods(http.MethodGet)
+	admin.HandleFunc("/emails/failed", emailOutboxH.ListFailed).Methods(http.MethodGet)
+	admin.HandleFunc("/emails/{id:[0-9]+}", emailOutboxH.Get).Methods(http.MethodGet)
+	admin.HandleFunc("/emails/{id:[0-9]+}/requeue", emailOutboxH.Requeue).Methods(http.MethodPost)
 
 	// статьи (админ)
 	admin.HandleFunc("/articles/preview", articleH.Preview).Methods(http.MethodPost)
+	admin.HandleFunc("/html-profile", htmlProfileH.Profile).Methods(http.MethodGet)
+	admin.HandleFunc("/html-profile/preview", htmlProfileH.Preview).Methods(http.MethodPost)
+	admin.HandleFunc("/articles", articleH.AdminList).Methods(http.MethodGet)
+	admin.HandleFunc("/articles/media", articleMediaH.List).Methods(http.MethodGet)
+	admin.HandleFunc("/articles/media", articleMediaH.Upload).Methods(http.MethodPost)
+	admin.HandleFunc("/articles/media/{id:[0-9]+}", articleMediaH.Delete).Methods(http.MethodDelete)
 	admin.HandleFunc("/articles", articleH.Create).Methods(http.MethodPost)
 	admin.HandleFunc("/articles/{id:[0-9]+}", articleH.Update).Methods(http.MethodPatch)
 	admin.HandleFunc("/articles/{id:[0-9]+}", articleH.Delete).Methods(http.MethodDelete)
 	admin.HandleFunc("/articles/{id:[0-9]+}/publish", articleH.SetPublish).Methods(http.MethodPatch)
+	admin.HandleFunc("/articles/{id:[0-9]+}/draft", articleH.GetDraft).Methods(http.MethodGet)
+	admin.HandleFunc("/articles/{id:[0-9]+}/draft", articleH.SaveDraft).Methods(http.MethodPatch)
+	admin.HandleFunc("/articles/{id:[0-9]+}/revisions", articleH.ListRevisions).Methods(http.MethodGet)
+	admin.HandleFunc("/articles/{id:[0-9]+}/revisions/{rev:[0-9]+}", articleH.GetRevision).Methods(http.MethodGet)
+	admin.HandleFunc("/articles/{id:[0-9]+}/revisions/{rev:[0-9]+}/diff", articleH.DiffRevision).Methods(http.MethodGet)
+	admin.HandleFunc("/articles/{id:[0-9]+}/revisions/{a:[0-9]+}/diff/{b:[0-9]+}", articleH.CompareRevisions).Methods(http.MethodGet)
+	admin.HandleFunc("/articles/{id:[0-9]+}/revisions/{rev:[0-9]+}/restore", articleH.RestoreRevision).Methods(http.MethodPost)
 
 	// таксономия (админ)
 	admin.HandleFunc("/tabs", taxonomyH.CreateTab).Methods(http.MethodPost)
+	admin.HandleFunc("/tabs/reorder", taxonomyH.ReorderTabs).Methods(http.MethodPatch)
 	admin.HandleFunc("/tabs/{id:[0-9]+}", taxonomyH.UpdateTab).Methods(http.MethodPatch)
 	admin.HandleFunc("/tabs/{id:[0-9]+}", taxonomyH.DeleteTab).Methods(http.MethodDelete)
 	admin.HandleFunc("/sections", taxonomyH.CreateSection).Methods(http.MethodPost)
+	admin.HandleFunc("/sections/reorder", taxonomyH.ReorderSections).Methods(http.MethodPatch)
 	admin.HandleFunc("/sections/{id:[0-9]+}", taxonomyH.UpdateSection).Methods(http.MethodPatch)
 	admin.HandleFunc("/sections/{id:[0-9]+}", taxonomyH.DeleteSection).Methods(http.MethodDelete)
+	admin.HandleFunc("/taxonomy/search", taxonomyH.Search).Methods(http.MethodGet)
 
 	// --- ЛОГИ ---
 	admin.HandleFunc("/logs/days", logsAdminH.ListDays).Methods(http.MethodGet)
@@ -137,4 +370,8 @@ func InitRoutes(
 	admin.HandleFunc("/logs/stats", logsAdminH.Stats).Methods(http.MethodGet)
 	admin.HandleFunc("/logs/download", logsAdminH.DownloadLog).Methods(http.MethodGet)
 	admin.HandleFunc("/logs/summary", logsAdminH.StatsSummary).Methods(http.MethodGet)
+	admin.HandleFunc("/logs/retention", logsAdminH.GetRetention).Methods(http.MethodGet)
+	admin.HandleFunc("/logs/retention", logsAdminH.UpdateRetention).Methods(http.MethodPut)
+	admin.HandleFunc("/logs/cleanup", logsAdminH.Cleanup).Methods(http.MethodPost)
+	admin.HandleFunc("/logs/stream", logsAdminH.Stream).Methods(http.MethodGet) // SSE
 }
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils"
+	"edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrDeletionPasswordInvalid = errors.New("неверный пароль")
+	ErrDeletionCancelInvalid   = errors.New("ссылка отмены удаления недействительна или устарела")
+)
+
+const (
+	defaultAccountDeletionGrace = 30 * 24 * time.Hour
+	accountDeletionTick         = time.Hour
+)
+
+// AccountDeletionService — удаление аккаунта самим пользователем: после подтверждения паролем
+// аккаунт отписывается от рассылки, все токены отзываются, а удаление выполняется по истечении
+// срока на отмену. Ссылка отмены (с подписью) уходит письмом.
+type AccountDeletionService struct {
+	users  *repository.UserRepository
+	outbox *repository.OutboxRepository
+	tokens repository.TokenStore
+	auth   *AuthService // сброс кэша пользователей
+	locks  JobLocker
+
+	grace     time.Duration
+	cancelURL string
+	cfg       *config.Holder
+}
+
+func NewAccountDeletionService(
+	users *repository.UserRepository,
+	outbox *repository.OutboxRepository,
+	tokens repository.TokenStore,
+	auth *AuthService,
+	locks JobLocker,
+	live *config.Holder,
+) *AccountDeletionService {
+	cfg := live.Get()
+	grace, err := time.ParseDuration(cfg.AccountDeletionGrace)
+	if err != nil || grace <= 0 {
+		grace = defaultAccountDeletionGrace
+	}
+	return &AccountDeletionService{
+		users:     users,
+		outbox:    outbox,
+		tokens:    tokens,
+		auth:      auth,
+		locks:     locks,
+		grace:     grace,
+		cancelURL: strings.TrimSpace(cfg.AccountDeletionCancelURL),
+		cfg:       live,
+	}
+}
+
+// Request — назначает удаление аккаунта userID после проверки пароля и отзывает его токены.
+// Возвращает дату удаления; если удаление уже назначено — прежнюю дату (письмо повторно не уходит).
+func (s *AccountDeletionService) Request(ctx context.Context, userID int, password string) (time.Time, error) {
+	log := logger.WithCtx(ctx).With(zap.Int("user_id", userID))
+
+	u, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !utils.CheckPasswordHash(password, u.PasswordHash) {
+		log.Warn("Удаление аккаунта: неверный пароль")
+		return time.Time{}, ErrDeletionPasswordInvalid
+	}
+
+	now := time.Now()
+	if at, err := s.users.DeletionScheduledAt(ctx, userID); err != nil {
+		return time.Time{}, err
+	} else if at != nil {
+		// Повтор (например, после сбоя отзыва токенов) — только отзываем токены ещё раз
+		log.Info("Удаление аккаунта уже назначено", zap.Time("delete_at", *at))
+		return *at, s.tokens.RevokeUserTokens(ctx, userID, now)
+	}
+
+	deleteAt := now.Add(s.grace).Truncate(time.Second)
+	if _, err := s.users.ScheduleDeletion(ctx, userID, deleteAt, s.outbox, func(u *models.User) ([]models.OutboxIntent, error) {
+		if u.Email == "" {
+			return nil, nil
+		}
+		html := helpers.BuildAccountDeletionHTML(u.Locale, u.FullName, deleteAt, s.CancelURL(userID, deleteAt))
+		email, err := EmailIntent(EmailJob{
+			To:      []string{u.Email},
+			Subject: helpers.EmailSubject(u.Locale, helpers.MailAccountDeletion),
+			Body:    html,
+			IsHTML:  true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []models.OutboxIntent{email}, nil
+	}); err != nil {
+		return time.Time{}, err
+	}
+	s.auth.InvalidateUser(userID)
+
+	// Удаление уже назначено и не откатывается; при сбое отзыва повторный запрос отзовёт токены
+	if err := s.tokens.RevokeUserTokens(ctx, userID, now); err != nil {
+		log.Error("Удаление аккаунта: не удалось отозвать токены", zap.Error(err))
+		return deleteAt, err
+	}
+
+	log.Info("Удаление аккаунта назначено", zap.Time("delete_at", deleteAt))
+	return deleteAt, nil
+}
+
+// CancelURL — подписанная ссылка отмены: <ACCOUNT_DELETION_CANCEL_URL>?token=<user>.<at>.<sig>.
+// Привязана к дате удаления: после отмены и повторного запроса старая ссылка не работает.
+func (s *AccountDeletionService) CancelURL(userID int, deleteAt time.Time) string {
+	uid, at := strconv.Itoa(userID), strconv.FormatInt(deleteAt.Unix(), 10)
+	token := uid + "." + at + "." + base64.RawURLEncoding.EncodeToString(s.cancelMAC(uid, at))
+	return fmt.Sprintf("%s?token=%s", s.cancelURL, url.QueryEscape(token))
+}
+
+// secret — ключ подписи ссылок: JWT_SECRET текущей конфигурации (меняется по SIGHUP).
+func (s *AccountDeletionService) secret() []byte {
+	return []byte(s.cfg.Get().JWTSecret)
+}
+
+func (s *AccountDeletionService) cancelMAC(uid, at string) []byte {
+	mac := hmac.New(sha256.New, s.secret())
+	mac.Write([]byte("account-deletion-cancel:" + uid + ":" + at))
+	return mac.Sum(nil)
+}
+
+// Cancel — отменяет удаление по токену из письма; возвращает ID пользователя.
+func (s *AccountDeletionService) Cancel(ctx context.Context, token string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 || len(s.secret()) == 0 {
+		return 0, ErrDeletionCancelInvalid
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, s.cancelMAC(parts[0], parts[1])) {
+		return 0, ErrDeletionCancelInvalid
+	}
+	userID, err1 := strconv.Atoi(parts[0])
+	at, err2 := strconv.ParseInt(parts[1], 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, ErrDeletionCancelInvalid
+	}
+
+	ok, err := s.users.CancelDeletion(ctx, userID, at)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrDeletionCancelInvalid
+	}
+	s.auth.InvalidateUser(userID)
+
+	logger.WithCtx(ctx).Info("Удаление аккаунта отменено", zap.Int("user_id", userID))
+	return userID, nil
+}
+
+// PurgeDue — удаляет аккаунты с истёкшим сроком на отмену.
+func (s *AccountDeletionService) PurgeDue(ctx context.Context) (int, error) {
+	ids, err := s.users.DeleteDueUsers(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		s.auth.InvalidateUser(id)
+	}
+	if len(ids) > 0 {
+		logger.Log.Info("Аккаунты удалены по запросу пользователей", zap.Ints("user_ids", ids))
+	}
+	return len(ids), nil
+}
+
+// Start — периодическое удаление аккаунтов (на одном инстансе); возвращает функцию остановки.
+func (s *AccountDeletionService) Start() func() {
+	ticker := time.NewTicker(accountDeletionTick)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ticker.C:
+				err := RunExclusive(context.Background(), s.locks, JobAccountDeletion, func(ctx context.Context) error {
+					_, err := s.PurgeDue(ctx)
+					return err
+				})
+				if err != nil {
+					logger.Log.Error("Ошибка удаления аккаунтов по запросу", zap.Error(err))
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return StopAndWait(JobAccountDeletion, done, stopped)
+}
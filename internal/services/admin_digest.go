@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+var ErrDigestNoRecipients = errors.New("не настроены получатели сводки (ADMIN_DIGEST_EMAILS)")
+
+const (
+	DigestTriggerSchedule = "schedule"
+	DigestTriggerManual   = "manual"
+
+	digestPeriod         = 7 * 24 * time.Hour
+	digestTopDownloads   = 10
+	digestTopErrors      = 10
+	digestExpiringWithin = 7 * 24 * time.Hour
+)
+
+// DigestLogSource — источник статистики из файлов логов (реализует handlers.AdminLogsHandler).
+type DigestLogSource interface {
+	DigestLogStats(ctx context.Context, from, to time.Time, topN int) (models.DigestLogStats, error)
+}
+
+// AdminDigestService собирает еженедельную сводку и рассылает её администраторам.
+type AdminDigestService struct {
+	repo       *repository.AdminDigestRepository
+	downloads  *DownloadStatsService
+	logs       DigestLogSource
+	recipients []string
+	weekday    time.Weekday
+	hour       int
+	locks      JobLocker
+}
+
+func NewAdminDigestService(repo *repository.AdminDigestRepository, downloads *DownloadStatsService, logs DigestLogSource, locks JobLocker, cfg *config.Config) *AdminDigestService {
+	s := &AdminDigestService{
+		repo:      repo,
+		downloads: downloads,
+		logs:      logs,
+		locks:     locks,
+		weekday:   time.Monday,
+		hour:      9,
+	}
+	for _, e := range strings.Split(cfg.AdminDigestEmails, ",") {
+		if e = strings.ToLower(strings.TrimSpace(e)); e != "" {
+			s.recipients = append(s.recipients, e)
+		}
+	}
+	if wd, ok := parseWeekday(cfg.AdminDigestWeekday); ok {
+		s.weekday = wd
+	}
+	if h, err := strconv.Atoi(strings.TrimSpace(cfg.AdminDigestHour)); err == nil && h >= 0 && h <= 23 {
+		s.hour = h
+	}
+	return s
+}
+
+func parseWeekday(v string) (time.Weekday, bool) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if v == name || v == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// Build — сводка за 7 дней, заканчивающихся в to.
+func (s *AdminDigestService) Build(ctx context.Context, to time.Time) (*models.AdminDigest, error) {
+	log := logger.WithCtx(ctx)
+
+	d := &models.AdminDigest{From: to.Add(-digestPeriod), To: to}
+	if err := s.repo.FillCounts(ctx, d, digestExpiringWithin); err != nil {
+		return nil, err
+	}
+
+	// логи — вспомогательный источник: если прочитать не удалось, сводка всё равно уходит
+	ls, err := s.logs.DigestLogStats(ctx, d.From, d.To, digestTopErrors)
+	if err != nil {
+		log.Warn("Не удалось собрать статистику логов для сводки", zap.Error(err))
+	}
+	d.ErrorsTotal = ls.ErrorsTotal
+	d.TopErrors = ls.TopErrors
+
+	top, err := s.downloads.Top(ctx, models.DownloadStatsFilter{From: &d.From, To: &d.To}, digestTopDownloads)
+	if err != nil {
+		return nil, err
+	}
+	d.TopDownloads = top
+
+	return d, nil
+}
+
+// SendNow — собирает сводку за последние 7 дней и отправляет её (ручной запуск).
+func (s *AdminDigestService) SendNow(ctx context.Context) (*models.AdminDigest, []string, error) {
+	if len(s.recipients) == 0 {
+		return nil, nil, ErrDigestNoRecipients
+	}
+	d, err := s.Build(ctx, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	s.send(d)
+	if err := s.repo.Save(ctx, 0, DigestTriggerManual, d, s.recipients); err != nil {
+		logger.WithCtx(ctx).Warn("Сводка отправлена, но не сохранена в истории", zap.Error(err))
+	}
+	return d, s.recipients, nil
+}
+
+// RunScheduled — плановая отправка: в заданный день недели, начиная с заданного часа, один раз за неделю.
+func (s *AdminDigestService) RunScheduled(ctx context.Context, now time.Time) error {
+	log := logger.WithCtx(ctx)
+
+	if len(s.recipients) == 0 || now.Weekday() != s.weekday || now.Hour() < s.hour {
+		return nil
+	}
+
+	to := time.Date(now.Year(), now.Month(), now.Day(), s.hour, 0, 0, 0, now.Location())
+	year, week := to.ISOWeek()
+	weekKey := fmt.Sprintf("%d-W%02d", year, week)
+
+	id, reserved, err := s.repo.ReserveWeek(ctx, weekKey, to.Add(-digestPeriod), to)
+	if err != nil || !reserved {
+		return err
+	}
+
+	d, err := s.Build(ctx, to)
+	if err != nil {
+		log.Error("Не удалось собрать еженедельную сводку", zap.Error(err), zap.String("week", weekKey))
+		return err
+	}
+	s.send(d)
+	if err := s.repo.Save(ctx, id, DigestTriggerSchedule, d, s.recipients); err != nil {
+		log.Warn("Сводка отправлена, но не сохранена в истории", zap.Error(err))
+	}
+
+	log.Info("Еженедельная сводка отправлена", zap.String("week", weekKey), zap.Int("recipients", len(s.recipients)))
+	return nil
+}
+
+func (s *AdminDigestService) send(d *models.AdminDigest) {
+	EmailQueue <- EmailJob{
+		To: s.recipients,
+		Subject: helpers.EmailPhrase(helpers.DefaultLocale, helpers.TextAdminDigestSubject,
+			helpers.FormatEmailDay(helpers.DefaultLocale, d.From, false), helpers.FormatEmailDay(helpers.DefaultLocale, d.To, true)),
+		Body:   helpers.BuildAdminDigestHTML(helpers.DefaultLocale, d),
+		IsHTML: true,
+	}
+}
+
+// Start — проверяет раз в час, не пора ли отправить плановую сводку; возвращает функцию остановки.
+func (s *AdminDigestService) Start() func() {
+	ticker := time.NewTicker(time.Hour)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		logger.Log.Info("AdminDigest запущен",
+			zap.String("weekday", s.weekday.String()),
+			zap.Int("hour", s.hour),
+			zap.Int("recipients", len(s.recipients)),
+		)
+		run := func() {
+			_ = RunExclusive(context.Background(), s.locks, JobAdminDigest, func(ctx context.Context) error {
+				return s.RunScheduled(ctx, time.Now())
+			})
+		}
+		run()
+		for {
+			select {
+			case <-ticker.C:
+				run()
+			case <-done:
+				ticker.Stop()
+				logger.Log.Info("AdminDigest остановлен")
+				return
+			}
+		}
+	}()
+
+	return StopAndWait(JobAdminDigest, done, stopped)
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNotifyNewsURL     = errors.New("для шаблона news укажите url")
+	ErrNotifyBadTemplate = errors.New("template должен быть simple или news")
+	ErrNotifyBadDate     = errors.New("даты регистрации — в формате YYYY-MM-DD или RFC3339")
+)
+
+// notifyPlaceholderRe — подстановки вида {{full_name}} (пробелы внутри скобок допускаются).
+var notifyPlaceholderRe = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// NotifySubscribers — ручная рассылка подписчикам из аудитории a: каждому своё письмо
+// с подставленными значениями. dryRun — только посчитать адресатов. Возвращает их число.
+func (s *AuthService) NotifySubscribers(ctx context.Context, msg models.NotifyMessage, a models.NotifyAudience, dryRun bool) (int, error) {
+	if err := prepareNotify(&msg, &a); err != nil {
+		return 0, err
+	}
+	return s.sendNotify(ctx, msg, a, dryRun, nil)
+}
+
+// prepareNotify — проверяет шаблон письма и разбирает даты регистрации аудитории.
+func prepareNotify(msg *models.NotifyMessage, a *models.NotifyAudience) error {
+	switch msg.Template {
+	case "":
+		msg.Template = models.NotifyTemplateSimple
+	case models.NotifyTemplateSimple:
+	case models.NotifyTemplateNews:
+		if msg.URL == "" {
+			return ErrNotifyNewsURL
+		}
+	default:
+		return ErrNotifyBadTemplate
+	}
+
+	var err error
+	if a.CreatedFrom, err = parseAudienceDate(a.RegisteredFrom, false); err != nil {
+		return ErrNotifyBadDate
+	}
+	if a.CreatedTo, err = parseAudienceDate(a.RegisteredTo, true); err != nil {
+		return ErrNotifyBadDate
+	}
+	return nil
+}
+
+// parseAudienceDate — YYYY-MM-DD или RFC3339; для конца периода (endOfDay) — начало следующего дня.
+func parseAudienceDate(v string, endOfDay bool) (*time.Time, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// sendNotify — письма аудитории a (msg и a уже проверены prepareNotify); campaignID
+// привязывает письма к рассылке по расписанию.
+func (s *AuthService) sendNotify(ctx context.Context, msg models.NotifyMessage, a models.NotifyAudience, dryRun bool, campaignID *int64) (int, error) {
+	log := logger.WithCtx(ctx)
+
+	recipients, err := s.repo.NotifyRecipients(ctx, a)
+	if err != nil {
+		return 0, err
+	}
+	if dryRun || len(recipients) == 0 {
+		log.Info("Ручная рассылка: адресаты посчитаны", zap.Int("count", len(recipients)), zap.Bool("dry_run", dryRun))
+		return len(recipients), nil
+	}
+
+	for _, rc := range recipients {
+		body := fillPlaceholders(msg.Message, rc, true)
+		title := fillPlaceholders(msg.Subject, rc, false)
+		var page string
+		if msg.Template == models.NotifyTemplateNews {
+			page = helpers.BuildNewsHTML(rc.Locale, title, body, msg.URL)
+		} else {
+			page = helpers.BuildSimpleHTML(rc.Locale, title, body)
+		}
+		EmailQueue <- EmailJob{
+			To:         []string{rc.Email},
+			Subject:    title,
+			Body:       page,
+			IsHTML:     true,
+			CampaignID: campaignID,
+		}
+	}
+	log.Info("Ручная рассылка поставлена в очередь",
+		zap.Int("count", len(recipients)), zap.String("template", msg.Template), zap.Int64p("campaign_id", campaignID))
+	return len(recipients), nil
+}
+
+// fillPlaceholders — подставляет {{full_name}}, {{username}} и {{email}}; в HTML значения
+// экранируются. Пустое ФИО заменяется логином. Неизвестные подстановки остаются как есть.
+func fillPlaceholders(text string, rc models.NotifyRecipient, escape bool) string {
+	name := rc.FullName
+	if name == "" {
+		name = rc.Username
+	}
+	values := map[string]string{"full_name": name, "username": rc.Username, "email": rc.Email}
+	return notifyPlaceholderRe.ReplaceAllStringFunc(text, func(m string) string {
+		v, ok := values[notifyPlaceholderRe.FindStringSubmatch(m)[1]]
+		if !ok {
+			return m
+		}
+		if escape {
+			return html.EscapeString(v)
+		}
+		return v
+	})
+}
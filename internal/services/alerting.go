@@ -0,0 +1,330 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	ErrAlertsDisabled    = errors.New("каналы оповещений не настроены")
+	ErrAlertChannelUnset = errors.New("канал оповещений не настроен")
+)
+
+const (
+	alertQueueSize   = 100
+	alertSendTimeout = 10 * time.Second
+	alertMaxText     = 3500 // Telegram режет сообщения длиннее 4096 символов
+)
+
+// alertTelegramAPI — базовый адрес Bot API.
+var alertTelegramAPI = "https://api.telegram.org"
+
+// AlertChannel — куда отправляется оповещение.
+type AlertChannel interface {
+	Name() string
+	Send(ctx context.Context, text string) error
+}
+
+// alertState — последнее оповещение по ключу ошибки и сколько повторов с тех пор не отправлено.
+type alertState struct {
+	sentAt     time.Time
+	suppressed int
+}
+
+// AlertService — оповещения в Telegram/Slack об ERROR/PANIC/FATAL в логах (logger.AddHook).
+// Одинаковые ошибки (уровень, место, сообщение) за ALERT_DEDUP_WINDOW оповещают один раз,
+// всего — не больше ALERT_MAX_PER_MINUTE в минуту; пропущенное посчитается в следующем оповещении.
+// ERROR отправляется в фоне, PANIC и FATAL — сразу: после них процесс может завершиться.
+type AlertService struct {
+	channels  []AlertChannel
+	minLevel  zapcore.Level
+	window    time.Duration
+	perMinute int
+	source    string // сервис@хост — логи у каждого инстанса свои
+	queue     chan string
+
+	mu          sync.Mutex
+	seen        map[string]*alertState
+	minuteStart time.Time
+	minuteSent  int
+	dropped     int // не отправлено из-за лимита или переполненной очереди
+}
+
+func NewAlertService(cfg *config.Config) *AlertService {
+	client := &http.Client{Timeout: alertSendTimeout}
+	s := &AlertService{
+		minLevel:  zapcore.ErrorLevel,
+		window:    10 * time.Minute,
+		perMinute: 10,
+		source:    "edutalks",
+		queue:     make(chan string, alertQueueSize),
+		seen:      map[string]*alertState{},
+	}
+	if cfg.AlertTelegramBotToken != "" && cfg.AlertTelegramChatID != "" {
+		s.channels = append(s.channels, &telegramAlertChannel{token: cfg.AlertTelegramBotToken, chatID: cfg.AlertTelegramChatID, client: client})
+	}
+	if cfg.AlertSlackWebhookURL != "" {
+		s.channels = append(s.channels, &slackAlertChannel{url: cfg.AlertSlackWebhookURL, client: client})
+	}
+	switch strings.ToLower(strings.TrimSpace(cfg.AlertMinLevel)) {
+	case "dpanic":
+		s.minLevel = zapcore.DPanicLevel
+	case "panic":
+		s.minLevel = zapcore.PanicLevel
+	case "fatal":
+		s.minLevel = zapcore.FatalLevel
+	}
+	if d, err := time.ParseDuration(strings.TrimSpace(cfg.AlertDedupWindow)); err == nil && d >= 0 {
+		s.window = d
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(cfg.AlertMaxPerMinute)); err == nil && n >= 1 {
+		s.perMinute = n
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		s.source += "@" + host
+	}
+	return s
+}
+
+// Enabled — настроен ли хотя бы один канал.
+func (s *AlertService) Enabled() bool {
+	return len(s.channels) > 0
+}
+
+// MinLevel — с какого уровня записи лога отправляются оповещения.
+func (s *AlertService) MinLevel() zapcore.Level {
+	return s.minLevel
+}
+
+// Hook — logger.Hook: решает, оповещать ли о записи, и ставит оповещение в очередь.
+func (s *AlertService) Hook(ent zapcore.Entry, fields []zapcore.Field) {
+	if !s.Enabled() || ent.Level < s.minLevel {
+		return
+	}
+	key := ent.Level.String() + "|" + ent.Caller.TrimmedPath() + "|" + ent.Message
+	now := time.Now()
+
+	s.mu.Lock()
+	if st, ok := s.seen[key]; ok && now.Sub(st.sentAt) < s.window {
+		st.suppressed++
+		s.mu.Unlock()
+		return
+	}
+	if now.Sub(s.minuteStart) >= time.Minute {
+		s.minuteStart, s.minuteSent = now, 0
+	}
+	if s.minuteSent >= s.perMinute {
+		s.dropped++
+		s.mu.Unlock()
+		return
+	}
+	s.minuteSent++
+	var repeats int
+	if st, ok := s.seen[key]; ok {
+		repeats = st.suppressed
+	}
+	s.seen[key] = &alertState{sentAt: now}
+	s.pruneLocked(now)
+	dropped := s.dropped
+	s.dropped = 0
+	s.mu.Unlock()
+
+	text := s.format(ent, fields, repeats, dropped)
+
+	if ent.Level >= zapcore.DPanicLevel {
+		ctx, cancel := context.WithTimeout(context.Background(), alertSendTimeout)
+		s.sendAll(ctx, text)
+		cancel()
+		return
+	}
+	select {
+	case s.queue <- text:
+	default:
+		s.mu.Lock()
+		s.dropped++
+		s.mu.Unlock()
+	}
+}
+
+// pruneLocked — забывает ключи, окно которых истекло и повторов не было.
+func (s *AlertService) pruneLocked(now time.Time) {
+	if len(s.seen) < 1000 {
+		return
+	}
+	for k, st := range s.seen {
+		if st.suppressed == 0 && now.Sub(st.sentAt) >= s.window {
+			delete(s.seen, k)
+		}
+	}
+}
+
+// format — текст оповещения: уровень, инстанс, сообщение, ключевые поля и место в коде.
+func (s *AlertService) format(ent zapcore.Entry, fields []zapcore.Field, repeats, dropped int) string {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s\n%s\n", ent.Level.CapitalString(), s.source, ent.Message)
+	for _, k := range []string{"error", "request_id", "user_id", "method", "path", "job"} {
+		if v, ok := enc.Fields[k]; ok {
+			fmt.Fprintf(&b, "%s: %v\n", k, v)
+		}
+	}
+	if ent.Caller.Defined {
+		fmt.Fprintf(&b, "caller: %s\n", ent.Caller.TrimmedPath())
+	}
+	b.WriteString(ent.Time.Format(time.RFC3339))
+	if repeats > 0 {
+		fmt.Fprintf(&b, "\nповторилась ещё %d раз с прошлого оповещения", repeats)
+	}
+	if dropped > 0 {
+		fmt.Fprintf(&b, "\nпропущено оповещений из-за лимита: %d", dropped)
+	}
+
+	text := b.String()
+	if r := []rune(text); len(r) > alertMaxText {
+		text = string(r[:alertMaxText]) + "…"
+	}
+	return text
+}
+
+// sendAll — отправляет во все каналы; ошибки отправки пишутся в лог на уровне Warn,
+// чтобы не порождать новых оповещений.
+func (s *AlertService) sendAll(ctx context.Context, text string) {
+	for _, ch := range s.channels {
+		if err := ch.Send(ctx, text); err != nil {
+			logger.Log.Warn("Не удалось отправить оповещение об ошибке",
+				zap.String("channel", ch.Name()), zap.Error(err))
+		}
+	}
+}
+
+// SendTest — тестовое сообщение в канал channel (пусто — во все) без дедупликации и лимита.
+func (s *AlertService) SendTest(ctx context.Context, channel string) ([]models.AlertTestResult, error) {
+	if !s.Enabled() {
+		return nil, ErrAlertsDisabled
+	}
+	text := fmt.Sprintf("[TEST] %s\nТестовое оповещение: канал настроен правильно.\n%s", s.source, time.Now().Format(time.RFC3339))
+
+	var results []models.AlertTestResult
+	for _, ch := range s.channels {
+		if channel != "" && ch.Name() != channel {
+			continue
+		}
+		res := models.AlertTestResult{Channel: ch.Name(), OK: true}
+		if err := ch.Send(ctx, text); err != nil {
+			res.OK, res.Error = false, err.Error()
+		}
+		results = append(results, res)
+	}
+	if len(results) == 0 {
+		return nil, ErrAlertChannelUnset
+	}
+	logger.WithCtx(ctx).Info("Тестовое оповещение отправлено", zap.Any("results", results))
+	return results, nil
+}
+
+// Start — фоновая отправка оповещений уровня ERROR; при остановке досылает очередь.
+// Возвращает функцию остановки.
+func (s *AlertService) Start() func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case text := <-s.queue:
+				ctx, cancel := context.WithTimeout(context.Background(), alertSendTimeout)
+				s.sendAll(ctx, text)
+				cancel()
+			case <-done:
+				for {
+					select {
+					case text := <-s.queue:
+						ctx, cancel := context.WithTimeout(context.Background(), alertSendTimeout)
+						s.sendAll(ctx, text)
+						cancel()
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return StopAndWait("alerts", done, stopped)
+}
+
+// telegramAlertChannel — сообщение от бота в чат (sendMessage Bot API).
+type telegramAlertChannel struct {
+	token  string
+	chatID string
+	client *http.Client
+}
+
+func (c *telegramAlertChannel) Name() string { return models.AlertChannelTelegram }
+
+func (c *telegramAlertChannel) Send(ctx context.Context, text string) error {
+	body, _ := json.Marshal(map[string]any{
+		"chat_id":                  c.chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	})
+	return postAlert(ctx, c.client, alertTelegramAPI+"/bot"+c.token+"/sendMessage", body)
+}
+
+// slackAlertChannel — сообщение в incoming webhook Slack.
+type slackAlertChannel struct {
+	url    string
+	client *http.Client
+}
+
+func (c *slackAlertChannel) Name() string { return models.AlertChannelSlack }
+
+func (c *slackAlertChannel) Send(ctx context.Context, text string) error {
+	body, _ := json.Marshal(map[string]string{"text": text})
+	return postAlert(ctx, c.client, c.url, body)
+}
+
+// postAlert — POST JSON; ошибка не содержит URL, в котором у Telegram лежит токен бота.
+func postAlert(ctx context.Context, client *http.Client, target string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("некорректный адрес канала")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return uerr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("канал ответил %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var ErrAnnouncementNotFound = errors.New("баннер не найден")
+var ErrAnnouncementWindow = errors.New("ends_at должен быть позже starts_at")
+
+type AnnouncementService struct {
+	repo *repository.AnnouncementRepository
+}
+
+func NewAnnouncementService(repo *repository.AnnouncementRepository) *AnnouncementService {
+	return &AnnouncementService{repo: repo}
+}
+
+// normalize — обрезка пробелов, значения по умолчанию и проверка окна показа.
+func (s *AnnouncementService) normalize(req models.AnnouncementRequest) (*models.Announcement, error) {
+	a := &models.Announcement{
+		Message:     strings.TrimSpace(req.Message),
+		Severity:    req.Severity,
+		Dismissible: req.Dismissible == nil || *req.Dismissible,
+		IsEnabled:   req.Enabled == nil || *req.Enabled,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+	}
+	if a.Severity == "" {
+		a.Severity = models.AnnouncementInfo
+	}
+	if req.LinkURL != nil {
+		if v := strings.TrimSpace(*req.LinkURL); v != "" {
+			a.LinkURL = &v
+		}
+	}
+	if req.LinkText != nil {
+		if v := strings.TrimSpace(*req.LinkText); v != "" {
+			a.LinkText = &v
+		}
+	}
+	if a.StartsAt != nil && a.EndsAt != nil && !a.EndsAt.After(*a.StartsAt) {
+		return nil, ErrAnnouncementWindow
+	}
+	return a, nil
+}
+
+func (s *AnnouncementService) Create(ctx context.Context, req models.AnnouncementRequest, adminID int) (*models.Announcement, error) {
+	a, err := s.normalize(req)
+	if err != nil {
+		return nil, err
+	}
+	if adminID != 0 {
+		a.CreatedBy = &adminID
+	}
+	out, err := s.repo.Create(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithCtx(ctx).Info("Баннер создан", zap.Int("id", out.ID), zap.String("severity", out.Severity))
+	return out, nil
+}
+
+func (s *AnnouncementService) Update(ctx context.Context, id int, req models.AnnouncementRequest) (*models.Announcement, error) {
+	a, err := s.normalize(req)
+	if err != nil {
+		return nil, err
+	}
+	a.ID = id
+	out, err := s.repo.Update(ctx, a)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAnnouncementNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	logger.WithCtx(ctx).Info("Баннер обновлён", zap.Int("id", id), zap.Bool("enabled", out.IsEnabled))
+	return out, nil
+}
+
+func (s *AnnouncementService) Delete(ctx context.Context, id int) error {
+	ok, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAnnouncementNotFound
+	}
+	logger.WithCtx(ctx).Info("Баннер удалён", zap.Int("id", id))
+	return nil
+}
+
+// List — все баннеры, включая выключенные и завершённые (для админки).
+func (s *AnnouncementService) List(ctx context.Context, limit, offset int) ([]models.Announcement, int, error) {
+	return s.repo.List(ctx, limit, offset)
+}
+
+// Active — баннеры, которые сейчас нужно показать на сайте.
+func (s *AnnouncementService) Active(ctx context.Context) ([]models.Announcement, error) {
+	return s.repo.Active(ctx, time.Now().UTC())
+}
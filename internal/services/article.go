@@ -5,36 +5,49 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 	"unicode/utf8"
 
+	"edutalks/internal/events"
 	"edutalks/internal/logger"
 	"edutalks/internal/models"
 	"edutalks/internal/repository"
 
-	"github.com/microcosm-cc/bluemonday"
 	"go.uber.org/zap"
 )
 
 type ArticleService interface {
 	Create(ctx context.Context, authorID *int64, req models.CreateArticleRequest) (*models.Article, error)
 	PreviewHTML(rawHTML string) string
-	GetAll(ctx context.Context, limit, offset int, tag string, onlyPublished bool) ([]*models.Article, error)
+	GetAll(ctx context.Context, limit, offset int, tag, status string) ([]*models.Article, error)
 	GetByID(ctx context.Context, id int64) (*models.Article, error)
 	Update(ctx context.Context, id int64, req models.CreateArticleRequest) (*models.Article, error)
 	Delete(ctx context.Context, id int64) error
 	SetPublish(ctx context.Context, id int64, publish bool) (*models.Article, error)
+	PublishScheduled(ctx context.Context) (int, error)
+
+	ListOwn(ctx context.Context, authorID int64, limit, offset int, status string) ([]*models.Article, error)
+	CreateOwn(ctx context.Context, authorID int64, req models.CreateArticleRequest) (*models.Article, error)
+	UpdateOwn(ctx context.Context, authorID, id int64, req models.CreateArticleRequest) (*models.Article, error)
+	DeleteOwn(ctx context.Context, authorID, id int64) error
+
+	ListRevisions(ctx context.Context, articleID int64) ([]models.ArticleRevision, error)
+	GetRevision(ctx context.Context, articleID, revisionID int64) (*models.ArticleRevision, error)
+	DiffRevision(ctx context.Context, articleID, revisionID int64) (*models.ArticleRevisionDiff, error)
+	CompareRevisions(ctx context.Context, articleID, fromID, toID int64) (*models.ArticleRevisionCompare, error)
+	RestoreRevision(ctx context.Context, articleID, revisionID int64) (*models.Article, error)
+	SaveDraft(ctx context.Context, articleID int64, req models.ArticleDraftRequest) (*models.ArticleDraft, error)
+	GetDraft(ctx context.Context, articleID int64) (*models.ArticleDraft, error)
 }
 
 type articleService struct {
-	repo   repository.ArticleRepo
-	policy *bluemonday.Policy
+	repo      repository.ArticleRepo
+	revisions *repository.ArticleRevisionRepository
+	policy    *HTMLSanitizer
 }
 
-func NewArticleService(repo repository.ArticleRepo) ArticleService {
-	p := bluemonday.UGCPolicy()
-	p.AllowElements("img")
-	p.AllowAttrs("src", "alt").OnElements("img")
-	return &articleService{repo: repo, policy: p}
+func NewArticleService(repo repository.ArticleRepo, revisions *repository.ArticleRevisionRepository, policy *HTMLSanitizer) ArticleService {
+	return &articleService{repo: repo, revisions: revisions, policy: policy}
 }
 
 func (s *articleService) PreviewHTML(rawHTML string) string {
@@ -75,6 +88,7 @@ func (s *articleService) Create(ctx context.Context, authorID *int64, req models
 	}
 
 	safe := s.policy.Sanitize(req.BodyHTML)
+	publish, publishAt := resolvePublishAt(req)
 
 	a := &models.Article{
 		AuthorID:    authorID,
@@ -82,10 +96,17 @@ func (s *articleService) Create(ctx context.Context, authorID *int64, req models
 		Summary:     strPtr(req.Summary),
 		BodyHTML:    safe,
 		Tags:        normalizeTags(req.Tags),
-		IsPublished: req.Publish,
+		IsPublished: publish,
+		PublishAt:   publishAt,
+
+		CoverImageURL: strPtr(strings.TrimSpace(req.CoverImageURL)),
 	}
 
-	created, err := s.repo.Create(ctx, a)
+	var intents repository.ArticleIntents
+	if a.IsPublished {
+		intents = publishIntents
+	}
+	created, err := s.repo.Create(ctx, a, intents)
 	if err != nil {
 		log.Error("Ошибка создания статьи (repo)", zap.Error(err))
 		return nil, err
@@ -94,26 +115,28 @@ func (s *articleService) Create(ctx context.Context, authorID *int64, req models
 	log.Info("Статья создана",
 		zap.Int64("id", created.ID),
 		zap.Bool("published", created.IsPublished),
+		zap.Any("publish_at", created.PublishAt),
 		zap.Int("tags_count", len(created.Tags)),
 	)
 	return created, nil
 }
 
-func (s *articleService) GetAll(ctx context.Context, limit, offset int, tag string, onlyPublished bool) ([]*models.Article, error) {
+func (s *articleService) GetAll(ctx context.Context, limit, offset int, tag, status string) ([]*models.Article, error) {
 	log := logger.WithCtx(ctx)
 	log.Debug("Получение списка статей",
 		zap.Int("limit", limit),
 		zap.Int("offset", offset),
 		zap.String("tag", tag),
-		zap.Bool("only_published", onlyPublished),
+		zap.String("status", status),
 	)
 
-	list, err := s.repo.GetAll(ctx, limit, offset, tag, onlyPublished)
+	list, err := s.repo.GetAll(ctx, limit, offset, tag, status)
 	if err != nil {
 		log.Error("Ошибка получения списка статей (repo)", zap.Error(err))
 		return nil, err
 	}
 
+	markArticlesFreshness(list)
 	log.Debug("Список статей получен", zap.Int("count", len(list)))
 	return list, nil
 }
@@ -128,6 +151,7 @@ func (s *articleService) GetByID(ctx context.Context, id int64) (*models.Article
 		return nil, err
 	}
 
+	a.IsUpdatedRecently = updatedRecently(a.ContentUpdatedAt)
 	log.Debug("Статья получена", zap.Int64("id", id))
 	return a, nil
 }
@@ -142,18 +166,30 @@ func (s *articleService) Update(ctx context.Context, id int64, req models.Create
 		return nil, err
 	}
 
+	before := *a
 	a.Title = strings.TrimSpace(req.Title)
 	a.Summary = strPtr(req.Summary)
 	a.BodyHTML = s.policy.Sanitize(req.BodyHTML)
 	a.Tags = normalizeTags(req.Tags)
-	a.IsPublished = req.Publish
+	a.CoverImageURL = strPtr(strings.TrimSpace(req.CoverImageURL))
+	a.IsPublished, a.PublishAt = resolvePublishAt(req)
+	contentUpdated := touchContent(&before, a)
 
-	if err := s.repo.Update(ctx, a); err != nil {
+	var intents repository.ArticleIntents
+	if a.IsPublished && !before.IsPublished {
+		intents = publishIntents
+	}
+	if err := s.repo.Update(ctx, a, intents); err != nil {
 		log.Error("Ошибка обновления статьи (repo)", zap.Int64("id", id), zap.Error(err))
 		return nil, err
 	}
 
-	log.Info("Статья обновлена", zap.Int64("id", id), zap.Bool("published", a.IsPublished))
+	log.Info("Статья обновлена", zap.Int64("id", id), zap.Bool("published", a.IsPublished),
+		zap.Any("publish_at", a.PublishAt), zap.Bool("content_updated", contentUpdated))
+	if contentUpdated {
+		emitArticleEvent(ctx, events.ArticleUpdated, a)
+	}
+	a.IsUpdatedRecently = updatedRecently(a.ContentUpdatedAt)
 	return a, nil
 }
 
@@ -184,7 +220,16 @@ func (s *articleService) SetPublish(ctx context.Context, id int64, publish bool)
 		return nil, fmt.Errorf("не найдено")
 	}
 
-	if err := s.repo.UpdatePublish(ctx, id, publish); err != nil {
+	wasPublished := false
+	if before, err := s.repo.GetByID(ctx, id); err == nil {
+		wasPublished = before.IsPublished
+	}
+
+	var intents repository.ArticleIntents
+	if publish && !wasPublished {
+		intents = publishIntents
+	}
+	if err := s.repo.UpdatePublish(ctx, id, publish, intents); err != nil {
 		log.Error("Ошибка обновления статуса публикации (repo)", zap.Int64("id", id), zap.Bool("publish", publish), zap.Error(err))
 		return nil, fmt.Errorf("ошибка обновления статуса публикации: %w", err)
 	}
@@ -199,6 +244,45 @@ func (s *articleService) SetPublish(ctx context.Context, id int64, publish bool)
 	return a, nil
 }
 
+// PublishScheduled — публикует статьи с наступившим publish_at; каждая публикация даёт article.published.
+func (s *articleService) PublishScheduled(ctx context.Context) (int, error) {
+	list, err := s.repo.PublishDue(ctx, publishIntents)
+	if err != nil {
+		return 0, err
+	}
+	for _, a := range list {
+		logger.WithCtx(ctx).Info("Статья опубликована по расписанию", zap.Int64("id", a.ID), zap.String("title", a.Title))
+	}
+	return len(list), nil
+}
+
+// resolvePublishAt — publish=true публикует сразу; publishAt в будущем — по расписанию,
+// наступившее или прошедшее время — тоже сразу.
+func resolvePublishAt(req models.CreateArticleRequest) (bool, *time.Time) {
+	if req.Publish || req.PublishAt == nil {
+		return req.Publish, nil
+	}
+	if !req.PublishAt.After(time.Now()) {
+		return true, nil
+	}
+	at := req.PublishAt.UTC()
+	return false, &at
+}
+
+// touchContent — если у опубликованной статьи изменились заголовок, анонс, текст или теги,
+// выставляет ContentUpdatedAt и возвращает true. Правки до публикации обновлением не считаются.
+func touchContent(before, a *models.Article) bool {
+	if !before.IsPublished || !a.IsPublished {
+		return false
+	}
+	if len(changedFields(revisionOf(before), revisionOf(a))) == 0 {
+		return false
+	}
+	now := time.Now().UTC()
+	a.ContentUpdatedAt = &now
+	return true
+}
+
 func strPtr(s string) *string {
 	if strings.TrimSpace(s) == "" {
 		return nil
@@ -222,3 +306,38 @@ func normalizeTags(in []string) []string {
 	}
 	return out
 }
+
+// publishIntents — побочные эффекты публикации статьи: событие article.published и рассылка
+// подписчикам. Пишутся в outbox в транзакции публикации, поэтому не теряются при падении процесса.
+func publishIntents(a *models.Article) ([]models.OutboxIntent, error) {
+	var authorID *int
+	if a.AuthorID != nil {
+		id := int(*a.AuthorID)
+		authorID = &id
+	}
+	event, err := EventIntent(events.ArticlePublished, authorID, map[string]any{
+		"article_id": a.ID,
+		"title":      a.Title,
+	})
+	if err != nil {
+		return nil, err
+	}
+	notify, err := NotifyIntent(models.NotifyArticlePublished, a.ID, a.Title)
+	if err != nil {
+		return nil, err
+	}
+	return []models.OutboxIntent{event, notify}, nil
+}
+
+// emitArticleEvent — article.updated (на него подписана групповая рассылка «Обновлено»).
+func emitArticleEvent(ctx context.Context, eventType string, a *models.Article) {
+	var authorID *int
+	if a.AuthorID != nil {
+		id := int(*a.AuthorID)
+		authorID = &id
+	}
+	events.Publish(ctx, eventType, authorID, map[string]any{
+		"article_id": a.ID,
+		"title":      a.Title,
+	})
+}
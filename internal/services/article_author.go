@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// Ошибки кабинета автора (роль author): автор работает только со своими материалами
+// и не публикует их — это делает администратор.
+var (
+	ErrNotContentOwner   = errors.New("материал принадлежит другому автору")
+	ErrContentPublished  = errors.New("опубликованный материал может снять только администратор")
+	ErrPublishNotAllowed = errors.New("публиковать материалы может только администратор")
+	ErrContentLocked     = errors.New("опубликованный материал или материал на проверке не редактируется: его сначала снимает с публикации или отклоняет администратор")
+)
+
+// contentLocked — материал нельзя править из кабинета автора: опубликованный ушёл бы в эфир
+// без проверки, а на проверке администратор одобрил бы не ту версию.
+func contentLocked(published bool, reviewState string) bool {
+	return published || reviewState == models.ReviewStateReview
+}
+
+// ListOwn — статьи автора, включая черновики и запланированные.
+func (s *articleService) ListOwn(ctx context.Context, authorID int64, limit, offset int, status string) ([]*models.Article, error) {
+	list, err := s.repo.GetByAuthor(ctx, authorID, limit, offset, status)
+	if err != nil {
+		logger.WithCtx(ctx).Error("Ошибка получения статей автора (repo)", zap.Int64("author_id", authorID), zap.Error(err))
+		return nil, err
+	}
+	markArticlesFreshness(list)
+	return list, nil
+}
+
+// CreateOwn — статья автора; всегда черновик, публикует её администратор.
+func (s *articleService) CreateOwn(ctx context.Context, authorID int64, req models.CreateArticleRequest) (*models.Article, error) {
+	if changesPublication(&models.Article{}, req) {
+		return nil, ErrPublishNotAllowed
+	}
+	return s.Create(ctx, &authorID, req)
+}
+
+// UpdateOwn — правка своей статьи (черновик или отклонённая); статус публикации и расписание не меняются.
+func (s *articleService) UpdateOwn(ctx context.Context, authorID, id int64, req models.CreateArticleRequest) (*models.Article, error) {
+	a, err := s.ownArticle(ctx, authorID, id)
+	if err != nil {
+		return nil, err
+	}
+	if contentLocked(a.IsPublished, a.ReviewState) {
+		return nil, ErrContentLocked
+	}
+	if changesPublication(a, req) {
+		return nil, ErrPublishNotAllowed
+	}
+	req.Publish, req.PublishAt = a.IsPublished, a.PublishAt
+	return s.Update(ctx, id, req)
+}
+
+// DeleteOwn — своя статья в корзину; опубликованную снимает только администратор.
+func (s *articleService) DeleteOwn(ctx context.Context, authorID, id int64) error {
+	a, err := s.ownArticle(ctx, authorID, id)
+	if err != nil {
+		return err
+	}
+	if a.IsPublished {
+		return ErrContentPublished
+	}
+	return s.Delete(ctx, id)
+}
+
+// ownArticle — статья автора; pgx.ErrNoRows — статьи нет, ErrNotContentOwner — она чужая.
+func (s *articleService) ownArticle(ctx context.Context, authorID, id int64) (*models.Article, error) {
+	a, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.AuthorID == nil || *a.AuthorID != authorID {
+		logger.WithCtx(ctx).Warn("Попытка изменить чужую статью", zap.Int64("id", id), zap.Int64("author_id", authorID))
+		return nil, ErrNotContentOwner
+	}
+	return a, nil
+}
+
+// changesPublication — меняет ли запрос статус публикации статьи a или её расписание
+// (повторно присланное текущее значение изменением не считается).
+func changesPublication(a *models.Article, req models.CreateArticleRequest) bool {
+	if req.Publish && !a.IsPublished {
+		return true
+	}
+	if req.IsPublished != nil && *req.IsPublished != a.IsPublished {
+		return true
+	}
+	return req.PublishAt != nil && (a.PublishAt == nil || !req.PublishAt.Equal(*a.PublishAt))
+}
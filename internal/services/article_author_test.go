@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"edutalks/internal/models"
+	"edutalks/internal/repository/repofake"
+)
+
+func TestArticleUpdateOwnLocked(t *testing.T) {
+	ctx := context.Background()
+	repo := repofake.NewArticles()
+	s := NewArticleService(repo, nil, nil)
+	author := int64(7)
+
+	for name, a := range map[string]models.Article{
+		"published": {Title: "Статья", AuthorID: &author, IsPublished: true, ReviewState: models.ReviewStatePublished},
+		"review":    {Title: "Статья", AuthorID: &author, ReviewState: models.ReviewStateReview},
+	} {
+		stored, err := repo.Create(ctx, &a, nil)
+		if err != nil {
+			t.Fatalf("%s: Create: %v", name, err)
+		}
+		req := models.CreateArticleRequest{Title: "Правка", Publish: a.IsPublished}
+		if _, err := s.UpdateOwn(ctx, author, stored.ID, req); !errors.Is(err, ErrContentLocked) {
+			t.Errorf("%s: err = %v, want ErrContentLocked", name, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+
+	"edutalks/internal/models"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// diffHTML — сравнение тел статей по узлам верхнего уровня (абзацы, заголовки, списки…):
+// список узлов с op и готовая разметка, где удалённое обёрнуто в <del>, вставленное — в <ins>.
+func diffHTML(from, to string) ([]models.HTMLDiffNode, string) {
+	tags := map[string]string{}
+	a, b := htmlNodes(from, tags), htmlNodes(to, tags)
+
+	nodes := []models.HTMLDiffNode{}
+	var markup strings.Builder
+	for _, l := range diffLines(a, b) {
+		nodes = append(nodes, models.HTMLDiffNode{Op: l.Op, Tag: tags[l.Text], HTML: l.Text})
+		switch l.Op {
+		case "-":
+			markup.WriteString(`<del class="diff-removed">` + l.Text + `</del>`)
+		case "+":
+			markup.WriteString(`<ins class="diff-inserted">` + l.Text + `</ins>`)
+		default:
+			markup.WriteString(l.Text)
+		}
+	}
+	return nodes, markup.String()
+}
+
+// htmlNodes — узлы верхнего уровня фрагмента в нормализованном виде (html.Render);
+// tags пополняется соответствием «разметка → имя элемента». Пробельный текст и комментарии пропускаются.
+func htmlNodes(body string, tags map[string]string) []string {
+	ctx := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	parsed, err := html.ParseFragment(strings.NewReader(body), ctx)
+	if err != nil {
+		// невалидный фрагмент сравниваем построчно, как текст
+		lines := htmlLines(body)
+		for _, l := range lines {
+			tags[l] = "#text"
+		}
+		return lines
+	}
+
+	var out []string
+	for _, n := range parsed {
+		var tag string
+		switch n.Type {
+		case html.ElementNode:
+			tag = n.Data
+		case html.TextNode:
+			if strings.TrimSpace(n.Data) == "" {
+				continue
+			}
+			tag = "#text"
+		default:
+			continue
+		}
+		var buf bytes.Buffer
+		if err := html.Render(&buf, n); err != nil {
+			continue
+		}
+		s := strings.TrimSpace(buf.String())
+		tags[s] = tag
+		out = append(out, s)
+	}
+	return out
+}
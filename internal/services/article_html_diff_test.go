@@ -0,0 +1,68 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffHTML(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		from, to string
+		want     []string // op tag html
+		markup   string
+	}{
+		{
+			name: "unchanged, whitespace between nodes ignored",
+			from: "<p>Один</p>\n\n<p>Два</p>",
+			to:   "<p>Один</p><p>Два</p>",
+			want: []string{"= p <p>Один</p>", "= p <p>Два</p>"},
+		},
+		{
+			name:   "inserted paragraph",
+			from:   "<p>Один</p><p>Три</p>",
+			to:     "<p>Один</p><p>Два</p><p>Три</p>",
+			want:   []string{"= p <p>Один</p>", "+ p <p>Два</p>", "= p <p>Три</p>"},
+			markup: `<p>Один</p><ins class="diff-inserted"><p>Два</p></ins><p>Три</p>`,
+		},
+		{
+			name:   "removed heading",
+			from:   "<h2>Введение</h2><p>Текст</p>",
+			to:     "<p>Текст</p>",
+			want:   []string{"- h2 <h2>Введение</h2>", "= p <p>Текст</p>"},
+			markup: `<del class="diff-removed"><h2>Введение</h2></del><p>Текст</p>`,
+		},
+		{
+			name: "changed node is removed and inserted",
+			from: "<p>Старый текст</p>",
+			to:   "<p>Новый текст</p>",
+			want: []string{"- p <p>Старый текст</p>", "+ p <p>Новый текст</p>"},
+		},
+		{
+			name: "attributes normalized",
+			from: `<a href='/x'>ссылка</a>`,
+			to:   `<a href="/x">ссылка</a>`,
+			want: []string{`= a <a href="/x">ссылка</a>`},
+		},
+		{
+			name: "top-level text",
+			from: "Просто текст",
+			to:   "Просто текст<ul><li>пункт</li></ul>",
+			want: []string{"= #text Просто текст", "+ ul <ul><li>пункт</li></ul>"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			nodes, markup := diffHTML(tc.from, tc.to)
+			var got []string
+			for _, n := range nodes {
+				got = append(got, n.Op+" "+n.Tag+" "+n.HTML)
+			}
+			if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tc.want) {
+				t.Errorf("nodes = %q\nwant %q", got, tc.want)
+			}
+			if tc.markup != "" && markup != tc.markup {
+				t.Errorf("markup = %q\nwant %q", markup, tc.markup)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var ErrMediaNotFound = errors.New("изображение не найдено")
+var ErrMediaInUse = errors.New("изображение используется в статьях")
+
+type ArticleMediaService struct {
+	repo *repository.ArticleMediaRepository
+}
+
+func NewArticleMediaService(repo *repository.ArticleMediaRepository) *ArticleMediaService {
+	return &ArticleMediaService{repo: repo}
+}
+
+// Add — запись о загруженном файле медиатеки; сам файл уже сохранён и проверен.
+func (s *ArticleMediaService) Add(ctx context.Context, m *models.ArticleMedia) (*models.ArticleMedia, error) {
+	out, err := s.repo.Create(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithCtx(ctx).Info("Изображение добавлено в медиатеку", zap.Int64("id", out.ID), zap.String("url", out.URL))
+	return out, nil
+}
+
+func (s *ArticleMediaService) List(ctx context.Context, unusedOnly bool, limit, offset int) ([]models.ArticleMedia, int, error) {
+	return s.repo.List(ctx, unusedOnly, limit, offset)
+}
+
+// Delete — удаляет запись медиатеки и возвращает её (файл удаляет вызывающий). Используемое
+// изображение без force не удаляется: ErrMediaInUse вместе с записью, где видно, в каких статьях.
+func (s *ArticleMediaService) Delete(ctx context.Context, id int64, force bool) (*models.ArticleMedia, error) {
+	m, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(m.UsedIn) > 0 && !force {
+		return m, ErrMediaInUse
+	}
+
+	ok, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrMediaNotFound
+	}
+	logger.WithCtx(ctx).Info("Изображение удалено из медиатеки",
+		zap.Int64("id", id), zap.String("url", m.URL), zap.Int("used_in", len(m.UsedIn)), zap.Bool("force", force))
+	return m, nil
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"edutalks/internal/events"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/reqctx"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var ErrArticleNotFound = errors.New("статья не найдена")
+var ErrRevisionNotFound = errors.New("ревизия не найдена")
+var ErrDraftNotFound = errors.New("черновик не найден")
+var ErrDraftTitleTooLong = errors.New("длина заголовка должна быть не более 255 символов")
+
+// maxDiffLines — выше этого построчный diff не считаем (O(n*m)), отдаём замену целиком.
+const maxDiffLines = 3000
+
+func (s *articleService) ListRevisions(ctx context.Context, articleID int64) ([]models.ArticleRevision, error) {
+	if err := s.ensureArticle(ctx, articleID); err != nil {
+		return nil, err
+	}
+	return s.revisions.List(ctx, articleID)
+}
+
+func (s *articleService) GetRevision(ctx context.Context, articleID, revisionID int64) (*models.ArticleRevision, error) {
+	rev, err := s.revisions.Get(ctx, articleID, revisionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRevisionNotFound
+	}
+	return rev, err
+}
+
+// DiffRevision — что изменилось с момента ревизии: список полей и построчный diff тела.
+func (s *articleService) DiffRevision(ctx context.Context, articleID, revisionID int64) (*models.ArticleRevisionDiff, error) {
+	rev, err := s.GetRevision(ctx, articleID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := s.repo.GetByID(ctx, articleID)
+	if err != nil {
+		return nil, ErrArticleNotFound
+	}
+
+	d := &models.ArticleRevisionDiff{
+		ArticleID:     articleID,
+		RevisionID:    revisionID,
+		ChangedFields: changedFields(rev, revisionOf(cur)),
+	}
+	d.Body = diffLines(htmlLines(rev.BodyHTML), htmlLines(cur.BodyHTML))
+	return d, nil
+}
+
+// CompareRevisions — что изменилось между двумя ревизиями: список полей и вставленные/удалённые узлы тела.
+func (s *articleService) CompareRevisions(ctx context.Context, articleID, fromID, toID int64) (*models.ArticleRevisionCompare, error) {
+	from, err := s.GetRevision(ctx, articleID, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.GetRevision(ctx, articleID, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, markup := diffHTML(from.BodyHTML, to.BodyHTML)
+	return &models.ArticleRevisionCompare{
+		ArticleID:      articleID,
+		FromRevisionID: fromID,
+		ToRevisionID:   toID,
+		ChangedFields:  changedFields(from, to),
+		Nodes:          nodes,
+		HTML:           markup,
+	}, nil
+}
+
+// RestoreRevision — возвращает содержимое ревизии; текущая версия при этом сама становится ревизией.
+// Статус публикации и расписание не меняются.
+func (s *articleService) RestoreRevision(ctx context.Context, articleID, revisionID int64) (*models.Article, error) {
+	log := logger.WithCtx(ctx)
+
+	rev, err := s.GetRevision(ctx, articleID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.repo.GetByID(ctx, articleID)
+	if err != nil {
+		return nil, ErrArticleNotFound
+	}
+
+	before := *a
+	a.Title = rev.Title
+	a.Summary = rev.Summary
+	a.BodyHTML = rev.BodyHTML
+	a.Tags = rev.Tags
+	contentUpdated := touchContent(&before, a)
+	if err := s.repo.Update(ctx, a, nil); err != nil {
+		log.Error("Ошибка восстановления ревизии статьи", zap.Int64("id", articleID), zap.Int64("revision_id", revisionID), zap.Error(err))
+		return nil, err
+	}
+	if contentUpdated {
+		emitArticleEvent(ctx, events.ArticleUpdated, a)
+	}
+
+	log.Info("Статья восстановлена из ревизии", zap.Int64("id", articleID), zap.Int64("revision_id", revisionID))
+	return s.repo.GetByID(ctx, articleID)
+}
+
+// SaveDraft — автосохранение правок без изменения опубликованной версии.
+func (s *articleService) SaveDraft(ctx context.Context, articleID int64, req models.ArticleDraftRequest) (*models.ArticleDraft, error) {
+	if err := s.ensureArticle(ctx, articleID); err != nil {
+		return nil, err
+	}
+	title := strings.TrimSpace(req.Title)
+	if utf8.RuneCountInString(title) > 255 {
+		return nil, ErrDraftTitleTooLong
+	}
+
+	d := &models.ArticleDraft{
+		ArticleID: articleID,
+		Title:     title,
+		Summary:   strPtr(req.Summary),
+		BodyHTML:  s.policy.Sanitize(req.BodyHTML),
+		Tags:      normalizeTags(req.Tags),
+	}
+	if uid, ok := reqctx.GetUserID(ctx); ok && uid != 0 {
+		id := int64(uid)
+		d.EditorID = &id
+	}
+	return s.revisions.SaveDraft(ctx, d)
+}
+
+func (s *articleService) GetDraft(ctx context.Context, articleID int64) (*models.ArticleDraft, error) {
+	d, err := s.revisions.GetDraft(ctx, articleID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDraftNotFound
+	}
+	return d, err
+}
+
+func (s *articleService) ensureArticle(ctx context.Context, id int64) error {
+	ok, err := s.repo.Exists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrArticleNotFound
+	}
+	return nil
+}
+
+// changedFields — какие поля отличаются у двух версий (в JSON-именах).
+func changedFields(a, b *models.ArticleRevision) []string {
+	out := []string{}
+	if a.Title != b.Title {
+		out = append(out, "title")
+	}
+	if derefStr(a.Summary) != derefStr(b.Summary) {
+		out = append(out, "summary")
+	}
+	if a.BodyHTML != b.BodyHTML {
+		out = append(out, "bodyHtml")
+	}
+	if !slices.Equal(a.Tags, b.Tags) {
+		out = append(out, "tags")
+	}
+	return out
+}
+
+// revisionOf — текущая версия статьи в виде ревизии (для сравнения).
+func revisionOf(a *models.Article) *models.ArticleRevision {
+	return &models.ArticleRevision{
+		ArticleID: a.ID,
+		Title:     a.Title,
+		Summary:   a.Summary,
+		BodyHTML:  a.BodyHTML,
+		Tags:      a.Tags,
+	}
+}
+
+func derefStr(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
+// htmlLines — HTML по строкам; соседние теги разносим, чтобы однострочный HTML сравнивался по блокам.
+func htmlLines(h string) []string {
+	h = strings.ReplaceAll(h, "\r\n", "\n")
+	h = strings.ReplaceAll(h, "><", ">\n<")
+	lines := strings.Split(h, "\n")
+	out := lines[:0]
+	for _, l := range lines {
+		if l = strings.TrimSpace(l); l != "" {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// diffLines — построчный diff по наибольшей общей подпоследовательности.
+func diffLines(a, b []string) []models.DiffLine {
+	out := []models.DiffLine{}
+	if len(a) > maxDiffLines || len(b) > maxDiffLines {
+		for _, l := range a {
+			out = append(out, models.DiffLine{Op: "-", Text: l})
+		}
+		for _, l := range b {
+			out = append(out, models.DiffLine{Op: "+", Text: l})
+		}
+		return out
+	}
+
+	// lcs[i][j] — длина НОП для a[i:] и b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, models.DiffLine{Op: "=", Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, models.DiffLine{Op: "-", Text: a[i]})
+			i++
+		default:
+			out = append(out, models.DiffLine{Op: "+", Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, models.DiffLine{Op: "-", Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, models.DiffLine{Op: "+", Text: b[j]})
+	}
+	return out
+}
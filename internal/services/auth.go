@@ -3,35 +3,68 @@ package services
 import (
 	"context"
 	"errors"
-	"fmt"
 	"strings"
 	"time"
 
+	"edutalks/internal/config"
+	"edutalks/internal/events"
 	"edutalks/internal/logger"
+	"edutalks/internal/metrics"
 	"edutalks/internal/models"
 	"edutalks/internal/repository"
 	"edutalks/internal/utils"
 	"edutalks/internal/utils/helpers"
 
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
+// ErrUnsupportedLocale — язык писем не поддерживается (см. helpers.Locale*).
+var ErrUnsupportedLocale = errors.New("неподдерживаемый язык")
+
+// ErrAccountDeletionPending — вход в аккаунт, удаление которого назначено (отмена — по ссылке из письма).
+var ErrAccountDeletionPending = errors.New("аккаунт ожидает удаления: отменить удаление можно по ссылке из письма")
+
+var (
+	ErrUsernameTaken     = errors.New("имя пользователя уже занято")
+	ErrLoginUserNotFound = errors.New("пользователь не найден")
+	ErrLoginPassword     = errors.New("неверный пароль")
+)
+
 type AuthService struct {
-	repo repository.UserRepo
+	repo     repository.UserRepo
+	outbox   *repository.OutboxRepository
+	tokens   repository.TokenStore
+	sessions *repository.SessionRepository
+	cache    *userCache // nil — кэш выключен
+	alerts   *loginAlerter
 }
 
-func NewAuthService(repo repository.UserRepo) *AuthService {
-	return &AuthService{repo: repo}
+func NewAuthService(
+	repo repository.UserRepo,
+	outbox *repository.OutboxRepository,
+	tokens repository.TokenStore,
+	sessions *repository.SessionRepository,
+	cfg *config.Config,
+) *AuthService {
+	return &AuthService{
+		repo:     repo,
+		outbox:   outbox,
+		tokens:   tokens,
+		sessions: sessions,
+		cache:    newUserCache(cfg),
+		alerts:   newLoginAlerter(sessions, cfg),
+	}
 }
 
 func (s *AuthService) RegisterUser(ctx context.Context, input *models.User, plainPassword string) error {
 	//log := logger.WithCtx(ctx)
 
 	if exists, _ := s.repo.IsUsernameTaken(ctx, input.Username); exists {
-		return errors.New("имя пользователя уже занято")
+		return ErrUsernameTaken
 	}
 	if exists, _ := s.repo.IsEmailTaken(ctx, input.Email); exists {
-		return errors.New("адрес электронной почты уже зарегистрирован")
+		return ErrEmailTaken
 	}
 
 	hashed, err := utils.HashPassword(plainPassword)
@@ -41,34 +74,76 @@ func (s *AuthService) RegisterUser(ctx context.Context, input *models.User, plai
 
 	input.PasswordHash = hashed
 	input.Role = "user"
+	input.Locale = helpers.NormalizeLocale(input.Locale)
 
-	return s.repo.CreateUser(ctx, input)
+	if err := s.repo.CreateUser(ctx, input); err != nil {
+		return err
+	}
+
+	events.Publish(ctx, events.UserRegistered, &input.ID, map[string]any{
+		"username": input.Username,
+	})
+	return nil
 }
 
-func (s *AuthService) Logout(ctx context.Context, token string, exp time.Time) error {
-	return s.repo.AddAccessTokenToBlacklist(ctx, token, exp)
+// Logout — токен в блоклист; если токен привязан к сессии (sessionID > 0), она закрывается.
+func (s *AuthService) Logout(ctx context.Context, userID int, sessionID int64, token string, exp time.Time) error {
+	if err := s.tokens.AddAccessTokenToBlacklist(ctx, token, exp); err != nil {
+		return err
+	}
+	if sessionID == 0 {
+		return nil
+	}
+	if _, err := s.sessions.Revoke(ctx, userID, sessionID); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	return nil
 }
 
 func (s *AuthService) GetUsersPaginated(ctx context.Context, limit, offset int) ([]*models.User, int, error) {
 	return s.repo.GetAllUsersPaginated(ctx, limit, offset)
 }
 
+// GetUserByID — пользователь по ID; повторные запросы в пределах USER_CACHE_TTL обслуживает кэш.
 func (s *AuthService) GetUserByID(ctx context.Context, id int) (*models.User, error) {
 	log := logger.WithCtx(ctx)
+
+	if user, ok := s.cache.get(id); ok {
+		log.Debug("Пользователь получен из кэша", zap.Int("user_id", id))
+		return user, nil
+	}
 	log.Info("Получение пользователя по ID", zap.Int("user_id", id))
 
 	user, err := s.repo.GetUserByID(ctx, id)
 	if err != nil {
 		log.Warn("Пользователь не найден по ID", zap.Int("user_id", id), zap.Error(err))
+		return user, err
 	}
-	return user, err
+	s.cache.put(user)
+	return user, nil
+}
+
+// InvalidateUser — сбрасывает пользователя из кэша (после изменений в обход AuthService).
+func (s *AuthService) InvalidateUser(id int) {
+	s.cache.invalidate(id)
 }
 
 func (s *AuthService) UpdateUser(ctx context.Context, id int, input *models.UpdateUserRequest) error {
 	log := logger.WithCtx(ctx)
 	log.Info("Обновление пользователя", zap.Int("user_id", id))
 
-	if err := s.repo.UpdateUserFields(ctx, id, input); err != nil {
+	if input.Locale != nil {
+		locale := strings.ToLower(strings.TrimSpace(*input.Locale))
+		if !helpers.SupportedLocale(locale) {
+			log.Warn("Неподдерживаемый язык писем", zap.String("locale", *input.Locale))
+			return ErrUnsupportedLocale
+		}
+		input.Locale = &locale
+	}
+
+	err := s.repo.UpdateUserFields(ctx, id, input)
+	s.cache.invalidate(id)
+	if err != nil {
 		log.Error("Ошибка при обновлении пользователя", zap.Error(err), zap.Int("user_id", id))
 		return err
 	}
@@ -88,11 +163,17 @@ func (s *AuthService) SetSubscription(ctx context.Context, userID int, status bo
 		prevExpiresAt = uBefore.SubscriptionExpiresAt
 	}
 
-	if err := s.repo.UpdateSubscriptionStatus(ctx, userID, status); err != nil {
+	err := s.repo.UpdateSubscriptionStatus(ctx, userID, status)
+	s.cache.invalidate(userID)
+	if err != nil {
 		log.Error("Ошибка изменения статуса подписки", zap.Error(err))
 		return err
 	}
 
+	if status {
+		events.Publish(ctx, events.SubscriptionGrant, &userID, subscriptionGrantedPayload("manual", 0, nil))
+	}
+
 	// При отключении подписки отправим письмо (не блокируя запрос)
 	if !status {
 		u, err := s.repo.GetUserByID(ctx, userID)
@@ -101,10 +182,10 @@ func (s *AuthService) SetSubscription(ctx context.Context, userID int, status bo
 			return nil
 		}
 		if u != nil && u.Email != "" {
-			html := helpers.BuildSubscriptionRevokedHTML(u.FullName, time.Now().UTC(), prevExpiresAt)
+			html := helpers.BuildSubscriptionRevokedHTML(u.Locale, u.FullName, time.Now().UTC(), prevExpiresAt)
 			EmailQueue <- EmailJob{
 				To:      []string{u.Email},
-				Subject: "Подписка отключена",
+				Subject: helpers.EmailSubject(u.Locale, helpers.MailSubscriptionRevoked),
 				Body:    html,
 				IsHTML:  true,
 			}
@@ -114,14 +195,50 @@ func (s *AuthService) SetSubscription(ctx context.Context, userID int, status bo
 	return nil
 }
 
-func (s *AuthService) GetSubscribedEmails(ctx context.Context) ([]string, error) {
-	return s.repo.GetSubscribedEmails(ctx)
+// StopLoginAlerts — дожидается писем о входе, которые ещё собираются; вызывать до StopEmailWorkers.
+func (s *AuthService) StopLoginAlerts() {
+	s.alerts.stop()
 }
 
 func (s *AuthService) UpdateEmailSubscription(ctx context.Context, userID int, subscribe bool) error {
+	defer s.cache.invalidate(userID)
 	return s.repo.UpdateEmailSubscription(ctx, userID, subscribe)
 }
 
+// UpdateEmailFrequency — сразу или сводкой (models.EmailFrequency*).
+func (s *AuthService) UpdateEmailFrequency(ctx context.Context, userID int, frequency string) error {
+	defer s.cache.invalidate(userID)
+	return s.repo.UpdateEmailFrequency(ctx, userID, frequency)
+}
+
+// UpdateLoginAlerts — присылать ли письмо о входе с нового устройства.
+func (s *AuthService) UpdateLoginAlerts(ctx context.Context, userID int, enabled bool) error {
+	defer s.cache.invalidate(userID)
+	return s.repo.UpdateLoginAlerts(ctx, userID, enabled)
+}
+
+// UnsubscribeByEmail — отписка от рассылок по ссылке из письма; уже удалённый адрес — не ошибка.
+func (s *AuthService) UnsubscribeByEmail(ctx context.Context, email string) error {
+	user, err := s.repo.GetUserByEmail(ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		logger.WithCtx(ctx).Info("Отписка: пользователь с таким email не найден — пропускаем")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.EmailSubscription {
+		return nil
+	}
+	err = s.repo.UpdateEmailSubscription(ctx, user.ID, false)
+	s.cache.invalidate(user.ID)
+	if err != nil {
+		return err
+	}
+	logger.WithCtx(ctx).Info("Отписка от рассылки по ссылке из письма", zap.Int("user_id", user.ID))
+	return nil
+}
+
 func (s *AuthService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	log := logger.WithCtx(ctx)
 	log.Info("Получение пользователя по email", zap.String("email", strings.ToLower(strings.TrimSpace(email))))
@@ -138,6 +255,7 @@ func (s *AuthService) DeleteUserByID(ctx context.Context, id int) error {
 	log.Info("Удаление пользователя", zap.Int("user_id", id))
 
 	err := s.repo.DeleteUserByID(ctx, id)
+	s.cache.invalidate(id)
 	if err != nil {
 		log.Error("Ошибка удаления пользователя", zap.Int("user_id", id), zap.Error(err))
 	}
@@ -148,6 +266,7 @@ func (s *AuthService) SetSubscriptionTrue(userID int) error {
 	// Нет контекста извне — логгер без контекста.
 	logger.Log.Info("Принудительное включение подписки", zap.Int("user_id", userID))
 	ctx := context.Background()
+	defer s.cache.invalidate(userID)
 	return s.repo.UpdateSubscriptionStatus(ctx, userID, true)
 }
 
@@ -155,61 +274,90 @@ func (s *AuthService) SetSubscriptionWithExpiry(ctx context.Context, userID int,
 	log := logger.WithCtx(ctx)
 	log.Info("Выдача подписки с истечением", zap.Int("user_id", userID), zap.Duration("duration", duration))
 
-	if err := s.repo.SetSubscriptionWithExpiry(ctx, userID, duration); err != nil {
+	if err := s.grantSubscription(ctx, userID, duration, false); err != nil {
 		log.Error("Ошибка выдачи подписки с истечением", zap.Error(err))
 		return err
 	}
 
-	u, err := s.repo.GetUserByID(ctx, userID)
-	if err != nil {
-		log.Error("Не удалось получить пользователя после выдачи подписки", zap.Error(err), zap.Int("user_id", userID))
-		return nil // подписка уже установлена — письмо необязательно
-	}
-
-	if u != nil && u.Email != "" && u.SubscriptionExpiresAt != nil {
-		plan := humanizeDuration(duration)
-		html := helpers.BuildSubscriptionGrantedHTML(u.FullName, plan, u.SubscriptionExpiresAt.Format("02.01.2006 15:04"))
-
-		EmailQueue <- EmailJob{
-			To:      []string{u.Email},
-			Subject: "Подписка активирована",
-			Body:    html,
-			IsHTML:  true,
-		}
-	}
-
 	log.Info("Подписка с истечением успешно установлена", zap.Int("user_id", userID))
 	return nil
 }
 
+// GrantPaidSubscription — подписка по оплате: событие вебхука eventID помечается обработанным
+// в той же транзакции, что и выдача подписки. false — событие уже обработано другой доставкой,
+// подписка не менялась.
+func (s *AuthService) GrantPaidSubscription(ctx context.Context, events *repository.PaymentWebhookRepository, eventID int64, userID int, duration time.Duration) (bool, error) {
+	defer s.cache.invalidate(userID)
+	return events.ProcessOnce(ctx, eventID, s.outbox, func(ctx context.Context, tx pgx.Tx) ([]models.OutboxIntent, error) {
+		u, err := s.repo.GrantSubscriptionTx(ctx, tx, userID, duration, false)
+		if err != nil {
+			return nil, err
+		}
+		return grantIntents("grant", helpers.MailSubscriptionGranted, duration, u)
+	})
+}
+
 func (s *AuthService) ExtendSubscription(ctx context.Context, userID int, duration time.Duration) error {
 	log := logger.WithCtx(ctx)
 	log.Info("Продление подписки", zap.Int("user_id", userID), zap.Duration("duration", duration))
 
-	if err := s.repo.ExtendSubscription(ctx, userID, duration); err != nil {
+	if err := s.grantSubscription(ctx, userID, duration, true); err != nil {
 		log.Error("Ошибка продления подписки", zap.Error(err))
 		return err
 	}
 
-	u, err := s.repo.GetUserByID(ctx, userID)
+	log.Info("Подписка продлена", zap.Int("user_id", userID))
+	return nil
+}
+
+// grantSubscription — меняет подписку и в той же транзакции ставит письмо и событие subscription.granted
+// в side_effect_outbox; их доставит OutboxRelay только после коммита.
+func (s *AuthService) grantSubscription(ctx context.Context, userID int, duration time.Duration, extend bool) error {
+	kind, mail := "grant", helpers.MailSubscriptionGranted
+	if extend {
+		kind, mail = "extend", helpers.MailSubscriptionExtended
+	}
+
+	defer s.cache.invalidate(userID)
+	_, err := s.repo.GrantSubscriptionWithOutbox(ctx, userID, duration, extend, s.outbox,
+		func(u *models.User) ([]models.OutboxIntent, error) {
+			return grantIntents(kind, mail, duration, u)
+		})
+	return err
+}
+
+// grantIntents — событие subscription.granted и письмо о выдаче/продлении подписки.
+func grantIntents(kind, mail string, duration time.Duration, u *models.User) ([]models.OutboxIntent, error) {
+	userID := u.ID
+	ev, err := EventIntent(events.SubscriptionGrant, &userID, subscriptionGrantedPayload(kind, duration, u))
 	if err != nil {
-		log.Error("Не удалось получить пользователя после продления", zap.Error(err), zap.Int("user_id", userID))
-		return nil
+		return nil, err
 	}
+	intents := []models.OutboxIntent{ev}
 
-	if u != nil && u.Email != "" && u.SubscriptionExpiresAt != nil {
-		plan := humanizeDuration(duration)
-		html := helpers.BuildSubscriptionGrantedHTML(u.FullName, plan, u.SubscriptionExpiresAt.Format("02.01.2006 15:04"))
-		EmailQueue <- EmailJob{
-			To:      []string{u.Email},
-			Subject: "Подписка продлена",
-			Body:    html,
-			IsHTML:  true,
+	if u.Email != "" && u.SubscriptionExpiresAt != nil {
+		html := helpers.BuildSubscriptionGrantedHTML(u.Locale, u.FullName,
+			helpers.FormatPlanDuration(u.Locale, duration), *u.SubscriptionExpiresAt)
+		job := EmailJob{To: []string{u.Email}, Subject: helpers.EmailSubject(u.Locale, mail), Body: html, IsHTML: true}
+		email, err := EmailIntent(job)
+		if err != nil {
+			return nil, err
 		}
+		intents = append(intents, email)
 	}
+	return intents, nil
+}
 
-	log.Info("Подписка продлена", zap.Int("user_id", userID))
-	return nil
+// subscriptionGrantedPayload — payload события subscription.granted; kind: grant | extend | manual.
+func subscriptionGrantedPayload(kind string, duration time.Duration, u *models.User) map[string]any {
+	payload := map[string]any{"kind": kind}
+	if duration > 0 {
+		payload["duration_sec"] = int64(duration.Seconds())
+	}
+	if u != nil && u.SubscriptionExpiresAt != nil {
+		payload["expires_at"] = u.SubscriptionExpiresAt.UTC()
+	}
+	return payload
 }
 
 func (s *AuthService) findUserByIdentifier(ctx context.Context, identifier string) (*models.User, error) {
@@ -227,42 +375,71 @@ func (s *AuthService) findUserByIdentifier(ctx context.Context, identifier strin
 	return s.repo.GetByUsername(ctx, id)
 }
 
+// Исходы входа по логину/паролю.
+var loginAttempts = metrics.NewCounterVec("auth_logins_total", "Попыток входа по исходу (success/failure)", "result")
+
 func (s *AuthService) LoginUserByIdentifier(
 	ctx context.Context,
 	identifier, password, jwtSecret string,
 	accessTTL time.Duration,
+	client models.SessionClient,
 ) (string, *models.User, error) {
 	log := logger.WithCtx(ctx)
 	log.Info("Попытка входа (только access)")
 
 	user, err := s.findUserByIdentifier(ctx, identifier)
 	if err != nil {
-		return "", nil, errors.New("пользователь не найден")
+		loginAttempts.WithLabelValues("failure").Inc()
+		return "", nil, ErrLoginUserNotFound
 	}
 
 	if !utils.CheckPasswordHash(password, user.PasswordHash) {
-		return "", nil, errors.New("неверный пароль")
+		loginAttempts.WithLabelValues("failure").Inc()
+		return "", nil, ErrLoginPassword
 	}
 
-	accessToken, err := utils.GenerateToken(jwtSecret, user.ID, user.Role, accessTTL, "access")
+	accessToken, err := s.StartSession(ctx, user, jwtSecret, accessTTL, client)
 	if err != nil {
-		log.Error("Ошибка генерации access-токена", zap.Error(err))
+		if errors.Is(err, ErrAccountDeletionPending) {
+			loginAttempts.WithLabelValues("failure").Inc()
+		}
 		return "", nil, err
 	}
 
+	loginAttempts.WithLabelValues("success").Inc()
 	log.Info("Вход выполнен", zap.Int("user_id", user.ID))
 	return accessToken, user, nil
 }
-func humanizeDuration(d time.Duration) string {
-	days := int(d.Hours() / 24)
-	switch {
-	case days%365 == 0 && days >= 365:
-		return fmt.Sprintf("%d год(а)", days/365)
-	case days%30 == 0 && days >= 30:
-		return fmt.Sprintf("%d мес.", days/30)
-	default:
-		return fmt.Sprintf("%d дней", days)
+
+// StartSession — вход уже опознанного пользователя (пароль, соцсеть): новая сессия и access-токен к ней.
+// Вход с нового устройства — письмо пользователю (если не отключено в настройках).
+func (s *AuthService) StartSession(
+	ctx context.Context,
+	user *models.User,
+	jwtSecret string,
+	accessTTL time.Duration,
+	client models.SessionClient,
+) (string, error) {
+	log := logger.WithCtx(ctx)
+
+	if at, err := s.repo.DeletionScheduledAt(ctx, user.ID); err == nil && at != nil {
+		log.Info("Вход отклонён: аккаунт ожидает удаления", zap.Int("user_id", user.ID))
+		return "", ErrAccountDeletionPending
+	}
+
+	session, err := s.sessions.Create(ctx, user.ID, client, time.Now().Add(accessTTL))
+	if err != nil {
+		log.Error("Ошибка создания сессии", zap.Error(err), zap.Int("user_id", user.ID))
+		return "", err
+	}
+
+	accessToken, err := utils.GenerateSessionToken(jwtSecret, user.ID, user.Role, session.ID, accessTTL)
+	if err != nil {
+		log.Error("Ошибка генерации access-токена", zap.Error(err))
+		return "", err
 	}
+	s.alerts.noteLogin(ctx, user, client)
+	return accessToken, nil
 }
 
 func normalizePhoneDigits(s string) string {
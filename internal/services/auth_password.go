@@ -24,7 +24,7 @@ type PasswordService struct {
 }
 
 type EmailSender interface {
-	SendPasswordReset(ctx context.Context, to, resetLink string) error
+	SendPasswordReset(ctx context.Context, to, locale, resetLink string, validFor time.Duration) error
 }
 
 func NewPasswordService(repo repository.PasswordResetRepo, emailSender EmailSender, appURL string) *PasswordService {
@@ -42,7 +42,7 @@ func (s *PasswordService) RequestReset(ctx context.Context, email string) error
 	email = strings.TrimSpace(strings.ToLower(email))
 	logger.Log.Info("Запрос на сброс пароля", zap.String("email", email))
 
-	userID, err := s.repo.FindUserIDByEmail(ctx, email)
+	userID, locale, err := s.repo.FindUserIDByEmail(ctx, email)
 	if err != nil {
 		// Не раскрываем наличие почты пользователю, но логируем для нас:
 		logger.Log.Warn("Не удалось найти пользователя по email при запросе сброса",
@@ -75,7 +75,7 @@ func (s *PasswordService) RequestReset(ctx context.Context, email string) error
 	}
 
 	resetLink := fmt.Sprintf("%s/reset?token=%s", s.appURL, token)
-	if err := s.emailSender.SendPasswordReset(ctx, email, resetLink); err != nil {
+	if err := s.emailSender.SendPasswordReset(ctx, email, locale, resetLink, s.tokenTTL); err != nil {
 		logger.Log.Error("Ошибка отправки письма для сброса пароля",
 			zap.Int64("user_id", userID),
 			zap.String("email", email),
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"edutalks/internal/models"
+	"edutalks/internal/repository/repofake"
+	"edutalks/internal/utils"
+)
+
+// recordingSender — EmailSender, который запоминает последнюю ссылку сброса.
+type recordingSender struct {
+	to, link string
+	calls    int
+}
+
+func (s *recordingSender) SendPasswordReset(ctx context.Context, to, locale, resetLink string, validFor time.Duration) error {
+	s.to, s.link = to, resetLink
+	s.calls++
+	return nil
+}
+
+func resetToken(t *testing.T, link string) string {
+	t.Helper()
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("ссылка %q: %v", link, err)
+	}
+	return u.Query().Get("token")
+}
+
+func TestPasswordResetFlow(t *testing.T) {
+	users := repofake.NewUsers()
+	u := users.Add(models.User{Username: "ivan", Email: "Ivan@Example.com", PasswordHash: "old"})
+	sender := &recordingSender{}
+	svc := NewPasswordService(repofake.NewPasswordResets(users), sender, "https://edutalks.ru")
+	ctx := context.Background()
+
+	if err := svc.RequestReset(ctx, " ivan@example.com "); err != nil {
+		t.Fatalf("RequestReset: %v", err)
+	}
+	if sender.calls != 1 || sender.to != "ivan@example.com" {
+		t.Fatalf("письмо: calls=%d to=%q", sender.calls, sender.to)
+	}
+	token := resetToken(t, sender.link)
+	if token == "" {
+		t.Fatalf("в ссылке нет токена: %q", sender.link)
+	}
+
+	if err := svc.ResetPassword(ctx, token, "short"); err == nil {
+		t.Error("короткий пароль принят")
+	}
+	if err := svc.ResetPassword(ctx, token, "newpass123"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	stored, _ := users.GetUserByID(ctx, u.ID)
+	if !utils.CheckPasswordHash("newpass123", stored.PasswordHash) {
+		t.Error("новый пароль не сохранён")
+	}
+	// токен одноразовый
+	if err := svc.ResetPassword(ctx, token, "another123"); err == nil {
+		t.Error("токен принят повторно")
+	}
+}
+
+func TestPasswordResetUnknownEmail(t *testing.T) {
+	sender := &recordingSender{}
+	svc := NewPasswordService(repofake.NewPasswordResets(repofake.NewUsers()), sender, "https://edutalks.ru")
+
+	// ответ тот же, что и для существующего адреса, но письмо не уходит
+	if err := svc.RequestReset(context.Background(), "nobody@example.com"); err != nil {
+		t.Fatalf("RequestReset: %v", err)
+	}
+	if sender.calls != 0 {
+		t.Errorf("письмо отправлено на неизвестный адрес")
+	}
+}
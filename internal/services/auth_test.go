@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository/repofake"
+	"edutalks/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// newTestAuth — AuthService поверх in-memory фейков; кэш пользователей включён.
+func newTestAuth(t *testing.T) (*AuthService, *repofake.Users) {
+	t.Helper()
+	users := repofake.NewUsers()
+	cfg := &config.Config{UserCacheTTL: "1m", UserCacheSize: "16"}
+	return NewAuthService(users, nil, repofake.NewTokens(), nil, cfg), users
+}
+
+func TestRegisterUser(t *testing.T) {
+	auth, users := newTestAuth(t)
+	ctx := context.Background()
+
+	u := &models.User{Username: "ivan", Email: "ivan@example.com", FullName: "Иван", Locale: "EN"}
+	if err := auth.RegisterUser(ctx, u, "secret123"); err != nil {
+		t.Fatalf("RegisterUser: %v", err)
+	}
+	stored, err := users.GetUserByID(ctx, u.ID)
+	if err != nil {
+		t.Fatalf("пользователь не сохранён: %v", err)
+	}
+	if stored.Role != "user" || stored.Locale != "en" || !stored.LoginAlerts {
+		t.Errorf("role=%q locale=%q login_alerts=%v", stored.Role, stored.Locale, stored.LoginAlerts)
+	}
+	if stored.PasswordHash == "secret123" || !utils.CheckPasswordHash("secret123", stored.PasswordHash) {
+		t.Error("пароль сохранён не хэшем")
+	}
+
+	dupName := &models.User{Username: "ivan", Email: "other@example.com"}
+	if err := auth.RegisterUser(ctx, dupName, "secret123"); !errors.Is(err, ErrUsernameTaken) {
+		t.Errorf("повтор логина: %v, ожидалось ErrUsernameTaken", err)
+	}
+	dupEmail := &models.User{Username: "petr", Email: "ivan@example.com"}
+	if err := auth.RegisterUser(ctx, dupEmail, "secret123"); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("повтор e-mail: %v, ожидалось ErrEmailTaken", err)
+	}
+}
+
+func TestUpdateLoginAlertsInvalidatesCache(t *testing.T) {
+	auth, users := newTestAuth(t)
+	ctx := context.Background()
+
+	u := users.Add(models.User{Username: "anna", Email: "anna@example.com", LoginAlerts: true})
+	if got, _ := auth.GetUserByID(ctx, u.ID); !got.LoginAlerts {
+		t.Fatal("login_alerts должен быть включён")
+	}
+	if err := auth.UpdateLoginAlerts(ctx, u.ID, false); err != nil {
+		t.Fatalf("UpdateLoginAlerts: %v", err)
+	}
+	// без сброса кэша вернулось бы прежнее значение
+	if got, _ := auth.GetUserByID(ctx, u.ID); got.LoginAlerts {
+		t.Error("после отключения из кэша пришло login_alerts=true")
+	}
+}
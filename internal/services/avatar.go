@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// SetAvatar — новый аватар пользователя (префикс файлов в uploads, nil — удалить);
+// возвращает прежний префикс — его файлы удаляет вызывающий.
+func (s *AuthService) SetAvatar(ctx context.Context, userID int, key *string) (*string, error) {
+	defer s.cache.invalidate(userID)
+
+	prev, err := s.repo.SetAvatar(ctx, userID, key)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithCtx(ctx).Info("Аватар пользователя обновлён", zap.Int("user_id", userID), zap.Bool("removed", key == nil))
+	return prev, nil
+}
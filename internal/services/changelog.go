@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/microcosm-cc/bluemonday"
+	"go.uber.org/zap"
+)
+
+var ErrChangelogNotFound = errors.New("запись changelog не найдена")
+var ErrChangelogInvalid = errors.New("некорректная запись changelog")
+
+type ChangelogService struct {
+	repo   *repository.ChangelogRepository
+	policy *bluemonday.Policy
+}
+
+func NewChangelogService(repo *repository.ChangelogRepository) *ChangelogService {
+	return &ChangelogService{repo: repo, policy: bluemonday.UGCPolicy()}
+}
+
+// normalize — обрезка пробелов, проверка kind и очистка HTML.
+func (s *ChangelogService) normalize(req models.ChangelogEntryRequest) (*models.ChangelogEntry, error) {
+	e := &models.ChangelogEntry{
+		Title:       strings.TrimSpace(req.Title),
+		BodyHTML:    s.policy.Sanitize(strings.TrimSpace(req.BodyHTML)),
+		Kind:        strings.ToLower(strings.TrimSpace(req.Kind)),
+		IsPublished: req.Publish,
+	}
+	if e.Title == "" {
+		return nil, ErrChangelogInvalid
+	}
+	switch e.Kind {
+	case "":
+		e.Kind = models.ChangelogKindFeature
+	case models.ChangelogKindFeature, models.ChangelogKindImprovement, models.ChangelogKindFix:
+	default:
+		return nil, ErrChangelogInvalid
+	}
+	if req.Version != nil {
+		if v := strings.TrimSpace(*req.Version); v != "" {
+			e.Version = &v
+		}
+	}
+	return e, nil
+}
+
+func (s *ChangelogService) Create(ctx context.Context, req models.ChangelogEntryRequest) (*models.ChangelogEntry, error) {
+	e, err := s.normalize(req)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.repo.Create(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithCtx(ctx).Info("Запись changelog создана", zap.Int("id", out.ID), zap.Bool("published", out.IsPublished))
+	return out, nil
+}
+
+func (s *ChangelogService) Update(ctx context.Context, id int, req models.ChangelogEntryRequest) (*models.ChangelogEntry, error) {
+	e, err := s.normalize(req)
+	if err != nil {
+		return nil, err
+	}
+	e.ID = id
+	out, err := s.repo.Update(ctx, e)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrChangelogNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	logger.WithCtx(ctx).Info("Запись changelog обновлена", zap.Int("id", id), zap.Bool("published", out.IsPublished))
+	return out, nil
+}
+
+func (s *ChangelogService) Delete(ctx context.Context, id int) error {
+	ok, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrChangelogNotFound
+	}
+	logger.WithCtx(ctx).Info("Запись changelog удалена", zap.Int("id", id))
+	return nil
+}
+
+// ListPublished — публичная лента обновлений.
+func (s *ChangelogService) ListPublished(ctx context.Context, limit, offset int) ([]models.ChangelogEntry, int, error) {
+	return s.repo.List(ctx, true, limit, offset)
+}
+
+// ListAll — все записи, включая черновики (для админки).
+func (s *ChangelogService) ListAll(ctx context.Context, limit, offset int) ([]models.ChangelogEntry, int, error) {
+	return s.repo.List(ctx, false, limit, offset)
+}
+
+// Unread — количество непросмотренных обновлений для бейджа «Что нового».
+func (s *ChangelogService) Unread(ctx context.Context, userID int) (*models.ChangelogUnread, error) {
+	return s.repo.Unread(ctx, userID)
+}
+
+// MarkSeen — сбрасывает бейдж: всё опубликованное до текущего момента считается просмотренным.
+func (s *ChangelogService) MarkSeen(ctx context.Context, userID int) error {
+	return s.repo.MarkSeen(ctx, userID, time.Now().UTC())
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrReviewBadType    = errors.New("type должен быть article или document")
+	ErrReviewBadState   = errors.New("state должен быть draft|review|rejected|published")
+	ErrReviewNotFound   = errors.New("материал не найден")
+	ErrReviewTransition = errors.New("в текущем состоянии материала это действие недоступно")
+	ErrReviewComment    = errors.New("укажите причину отклонения")
+)
+
+var reviewStates = map[string]bool{
+	models.ReviewStateDraft:     true,
+	models.ReviewStateReview:    true,
+	models.ReviewStateRejected:  true,
+	models.ReviewStatePublished: true,
+}
+
+// ContentReviewService — согласование статей и документов: автор отправляет материал
+// на проверку, админ одобряет (материал публикуется) или отклоняет с комментарием.
+type ContentReviewService struct {
+	repo     *repository.ContentReviewRepository
+	articles ArticleService
+	docs     *DocumentService
+}
+
+func NewContentReviewService(repo *repository.ContentReviewRepository, articles ArticleService, docs *DocumentService) *ContentReviewService {
+	return &ContentReviewService{repo: repo, articles: articles, docs: docs}
+}
+
+// Submit — автор отправляет свой черновик или отклонённый материал на проверку. Снятый с
+// публикации материал тоже черновик: Target показывает его как draft.
+func (s *ContentReviewService) Submit(ctx context.Context, userID int, typ string, id int64, comment string) (*models.ReviewEvent, error) {
+	state, err := s.ownTarget(ctx, userID, typ, id)
+	if err != nil {
+		return nil, err
+	}
+	if state != models.ReviewStateDraft && state != models.ReviewStateRejected {
+		return nil, ErrReviewTransition
+	}
+	// в БД у снятого с публикации материала review_state = published — переход разрешён и из него
+	return s.transition(ctx, typ, id, userID, state, models.ReviewStateReview, comment,
+		models.ReviewStateDraft, models.ReviewStateRejected, models.ReviewStatePublished)
+}
+
+// Approve — админ одобряет материал на проверке: статья публикуется, документ становится публичным.
+func (s *ContentReviewService) Approve(ctx context.Context, adminID int, typ string, id int64, comment string) (*models.ReviewEvent, error) {
+	if err := s.inReview(ctx, typ, id); err != nil {
+		return nil, err
+	}
+
+	var err error
+	switch typ {
+	case models.ReviewArticle:
+		_, err = s.articles.SetPublish(ctx, id, true)
+	case models.ReviewDocument:
+		public := true
+		_, err = s.docs.UpdateDocument(ctx, int(id), models.UpdateDocumentRequest{IsPublic: &public}, nil)
+	}
+	if err != nil {
+		logger.WithCtx(ctx).Error("Согласование: не удалось опубликовать материал",
+			zap.String("type", typ), zap.Int64("id", id), zap.Error(err))
+		return nil, err
+	}
+	return s.transition(ctx, typ, id, adminID, models.ReviewStateReview, models.ReviewStatePublished, comment,
+		models.ReviewStateReview)
+}
+
+// Reject — админ возвращает материал автору; комментарий обязателен.
+func (s *ContentReviewService) Reject(ctx context.Context, adminID int, typ string, id int64, comment string) (*models.ReviewEvent, error) {
+	if strings.TrimSpace(comment) == "" {
+		return nil, ErrReviewComment
+	}
+	if err := s.inReview(ctx, typ, id); err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, typ, id, adminID, models.ReviewStateReview, models.ReviewStateRejected, comment,
+		models.ReviewStateReview)
+}
+
+// Queue — материалы в состоянии state (по умолчанию — ждущие проверки); typ пустой — оба типа.
+func (s *ContentReviewService) Queue(ctx context.Context, typ, state string, limit, offset int) ([]models.ReviewItem, int, error) {
+	if typ != "" && !validReviewType(typ) {
+		return nil, 0, ErrReviewBadType
+	}
+	if state == "" {
+		state = models.ReviewStateReview
+	}
+	if !reviewStates[state] {
+		return nil, 0, ErrReviewBadState
+	}
+	return s.repo.Queue(ctx, typ, state, limit, offset)
+}
+
+// History — переходы состояния материала (для админа).
+func (s *ContentReviewService) History(ctx context.Context, typ string, id int64) ([]models.ReviewEvent, error) {
+	if _, _, err := s.target(ctx, typ, id); err != nil {
+		return nil, err
+	}
+	return s.repo.History(ctx, typ, id)
+}
+
+// OwnHistory — переходы состояния своего материала с комментариями проверяющих (для автора).
+func (s *ContentReviewService) OwnHistory(ctx context.Context, userID int, typ string, id int64) ([]models.ReviewEvent, error) {
+	if _, err := s.ownTarget(ctx, userID, typ, id); err != nil {
+		return nil, err
+	}
+	return s.repo.History(ctx, typ, id)
+}
+
+func validReviewType(typ string) bool {
+	return typ == models.ReviewArticle || typ == models.ReviewDocument
+}
+
+// target — владелец и состояние материала.
+func (s *ContentReviewService) target(ctx context.Context, typ string, id int64) (*int, string, error) {
+	if !validReviewType(typ) {
+		return nil, "", ErrReviewBadType
+	}
+	owner, state, err := s.repo.Target(ctx, typ, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, "", ErrReviewNotFound
+	}
+	return owner, state, err
+}
+
+// ownTarget — состояние материала пользователя; чужой — ErrNotContentOwner.
+func (s *ContentReviewService) ownTarget(ctx context.Context, userID int, typ string, id int64) (string, error) {
+	owner, state, err := s.target(ctx, typ, id)
+	if err != nil {
+		return "", err
+	}
+	if owner == nil || *owner != userID {
+		return "", ErrNotContentOwner
+	}
+	return state, nil
+}
+
+func (s *ContentReviewService) inReview(ctx context.Context, typ string, id int64) error {
+	_, state, err := s.target(ctx, typ, id)
+	if err != nil {
+		return err
+	}
+	if state != models.ReviewStateReview {
+		return ErrReviewTransition
+	}
+	return nil
+}
+
+func (s *ContentReviewService) transition(ctx context.Context, typ string, id int64, actorID int, from, to, comment string, rawFrom ...string) (*models.ReviewEvent, error) {
+	ev := &models.ReviewEvent{FromState: from, ToState: to, Comment: strings.TrimSpace(comment)}
+	if actorID > 0 {
+		ev.ActorID = &actorID
+	}
+	err := s.repo.Transition(ctx, typ, id, rawFrom, ev)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrReviewTransition
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithCtx(ctx).Info("Согласование: состояние материала изменено",
+		zap.String("type", typ), zap.Int64("id", id), zap.String("from", from), zap.String("to", to), zap.Int("actor_id", actorID))
+	return ev, nil
+}
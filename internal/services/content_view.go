@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/metrics"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/reqctx"
+	"edutalks/internal/utils/clientip"
+
+	"go.uber.org/zap"
+)
+
+// MaxTrendingWindow — самый длинный период для подборки популярного.
+const MaxTrendingWindow = 90 * 24 * time.Hour
+
+var contentViews = metrics.NewCounter("content_views_total", "Засчитанных просмотров новостей и статей")
+
+// botMarkers — подстроки User-Agent роботов и утилит; такие запросы просмотрами не считаются.
+var botMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "preview", "facebookexternalhit", "lighthouse",
+	"headless", "curl", "wget", "python", "go-http-client", "java/", "okhttp", "httpclient",
+}
+
+// ContentViewService — просмотры новостей и статей: один просмотр на зрителя в сутки,
+// роботы не считаются.
+type ContentViewService struct {
+	repo   *repository.ContentViewRepository
+	window time.Duration
+}
+
+func NewContentViewService(repo *repository.ContentViewRepository, cfg *config.Config) *ContentViewService {
+	window, err := time.ParseDuration(cfg.TrendingWindow)
+	if err != nil || window <= 0 || window > MaxTrendingWindow {
+		window = 7 * 24 * time.Hour
+	}
+	return &ContentViewService{
+		repo:   repo,
+		window: window,
+	}
+}
+
+// Record — засчитывает просмотр материала из запроса r. Ошибка записи не должна ломать
+// отдачу материала, поэтому только логируется. true — просмотр засчитан.
+func (s *ContentViewService) Record(r *http.Request, kind string, id int64) bool {
+	ua := r.UserAgent()
+	if isBot(ua) {
+		return false
+	}
+
+	// зритель — пользователь, а без входа — хеш IP и User-Agent (сами они не хранятся)
+	viewer := ""
+	if uid, ok := reqctx.GetUserID(r.Context()); ok && uid > 0 {
+		viewer = "u:" + strconv.Itoa(uid)
+	} else {
+		sum := sha256.Sum256([]byte(clientip.FromRequest(r) + "|" + ua))
+		viewer = "a:" + hex.EncodeToString(sum[:16])
+	}
+
+	counted, err := s.repo.Record(r.Context(), kind, id, viewer)
+	if err != nil {
+		logger.WithCtx(r.Context()).Warn("Не удалось записать просмотр",
+			zap.String("kind", kind), zap.Int64("id", id), zap.Error(err))
+		return false
+	}
+	if counted {
+		contentViews.Inc()
+	}
+	return counted
+}
+
+// Trending — самые просматриваемые опубликованные материалы за window (0 — период по умолчанию);
+// kind — models.ContentKind* или пусто (все).
+func (s *ContentViewService) Trending(ctx context.Context, kind string, window time.Duration, limit int) ([]models.TrendingItem, time.Duration, error) {
+	if window <= 0 {
+		window = s.window
+	}
+	if window > MaxTrendingWindow {
+		window = MaxTrendingWindow
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+	items, err := s.repo.Trending(ctx, kind, time.Now().Add(-window), limit)
+	return items, window, err
+}
+
+func isBot(ua string) bool {
+	ua = strings.ToLower(strings.TrimSpace(ua))
+	if ua == "" {
+		return true
+	}
+	for _, m := range botMarkers {
+		if strings.Contains(ua, m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+)
+
+// maxStatsPeriodDays — самый длинный период графиков дашборда.
+const maxStatsPeriodDays = 366
+
+var ErrStatsPeriod = errors.New("некорректный период: from позже to или длиннее года")
+
+// DashboardStatsService — графики админ-дашборда: регистрации, подписки, загрузки, активность админов.
+type DashboardStatsService struct {
+	repo *repository.DashboardStatsRepository
+}
+
+func NewDashboardStatsService(repo *repository.DashboardStatsRepository) *DashboardStatsService {
+	return &DashboardStatsService{repo: repo}
+}
+
+func (s *DashboardStatsService) Registrations(ctx context.Context, from, to *time.Time) ([]models.DayCount, models.StatsPeriod, error) {
+	p, err := statsPeriod(from, to, 30)
+	if err != nil {
+		return nil, p, err
+	}
+	out, err := s.repo.Registrations(ctx, p)
+	return out, p, err
+}
+
+func (s *DashboardStatsService) SubscriptionChanges(ctx context.Context, from, to *time.Time) ([]models.SubscriptionDayPoint, models.StatsPeriod, error) {
+	p, err := statsPeriod(from, to, 30)
+	if err != nil {
+		return nil, p, err
+	}
+	out, err := s.repo.SubscriptionChanges(ctx, p)
+	return out, p, err
+}
+
+// UploadsWeekly — по умолчанию последние 12 недель.
+func (s *DashboardStatsService) UploadsWeekly(ctx context.Context, from, to *time.Time) ([]models.WeekCount, models.StatsPeriod, error) {
+	p, err := statsPeriod(from, to, 12*7)
+	if err != nil {
+		return nil, p, err
+	}
+	out, err := s.repo.UploadsWeekly(ctx, p)
+	return out, p, err
+}
+
+func (s *DashboardStatsService) TopAdmins(ctx context.Context, from, to *time.Time, limit int) ([]models.AdminActivity, models.StatsPeriod, error) {
+	p, err := statsPeriod(from, to, 30)
+	if err != nil {
+		return nil, p, err
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+	out, err := s.repo.TopAdmins(ctx, p, limit)
+	return out, p, err
+}
+
+// statsPeriod — даты периода (включительно); без from — defaultDays дней до to, без to — сегодня.
+func statsPeriod(from, to *time.Time, defaultDays int) (models.StatsPeriod, error) {
+	day := func(t time.Time) time.Time {
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	p := models.StatsPeriod{To: day(time.Now())}
+	if to != nil {
+		p.To = day(*to)
+	}
+	p.From = p.To.AddDate(0, 0, -(defaultDays - 1))
+	if from != nil {
+		p.From = day(*from)
+	}
+	if p.From.After(p.To) || p.To.Sub(p.From) > maxStatsPeriodDays*24*time.Hour {
+		return p, ErrStatsPeriod
+	}
+	return p, nil
+}
@@ -2,34 +2,54 @@ package services
 
 import (
 	"context"
+	"errors"
+	"net/url"
+	"strings"
 
+	"edutalks/internal/events"
 	"edutalks/internal/logger"
 	"edutalks/internal/models"
 	"edutalks/internal/repository"
 
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
+var ErrDocumentNotFound = errors.New("документ не найден")
+var ErrInvalidAccessibilityURL = errors.New("ссылка должна быть http(s)-адресом или путём от корня сайта")
+
 type DocumentService struct {
-	repo repository.DocumentRepo
+	repo      repository.DocumentRepo
+	relations *repository.DocumentRelationRepository
+	grants    *repository.DocumentGrantRepository
 }
 
-func NewDocumentService(repo repository.DocumentRepo) *DocumentService {
-	return &DocumentService{repo: repo}
+func NewDocumentService(repo repository.DocumentRepo, relations *repository.DocumentRelationRepository, grants *repository.DocumentGrantRepository) *DocumentService {
+	return &DocumentService{repo: repo, relations: relations, grants: grants}
 }
 
 type DocumentServiceInterface interface {
 	Upload(ctx context.Context, doc *models.Document) (int, error)
-	GetPublicDocumentsPaginated(ctx context.Context, limit, offset int, category string) ([]*models.Document, int, error)
+	GetPublicDocumentsPaginated(ctx context.Context, limit, offset int, category string, a11y models.DocumentAccessibilityFilter, normative models.DocumentNormativeFilter) ([]*models.Document, int, error)
 	GetDocumentByID(ctx context.Context, id int) (*models.Document, error)
 	Delete(ctx context.Context, id int) error
 	GetAllDocuments(ctx context.Context, limit int) ([]*models.Document, error)
 	Search(ctx context.Context, query string) ([]models.Document, error)
 	GetPublicDocumentsByFilterPaginated(ctx context.Context, limit, offset int, sectionID *int, category string) ([]*models.Document, int, error)
-	GetPublicDocuments(ctx context.Context, sectionID *int, category string) ([]*models.Document, error)
+	GetPublicDocuments(ctx context.Context, sectionID *int, category string, a11y models.DocumentAccessibilityFilter, normative models.DocumentNormativeFilter) ([]*models.Document, error)
+	UpdateAccessibility(ctx context.Context, id int, req models.UpdateDocumentAccessibilityRequest) (*models.Document, error)
+	UpdateNormative(ctx context.Context, id int, req models.UpdateDocumentNormativeRequest) (*models.Document, error)
+	UpdateDocument(ctx context.Context, id int, req models.UpdateDocumentRequest, file *models.DocumentFile) (*models.Document, error)
+	AddRelation(ctx context.Context, id int, req models.CreateDocumentRelationRequest) (*models.DocumentRelation, error)
+	DeleteRelation(ctx context.Context, id int, relationID int64) error
+	Relations(ctx context.Context, id int) ([]models.DocumentLink, error)
 }
 
 func (s *DocumentService) Upload(ctx context.Context, doc *models.Document) (int, error) {
+	if err := normalizeNormative(doc); err != nil {
+		return 0, err
+	}
+
 	logger.Log.Info("Сервис: загрузка документа",
 		zap.Int("user_id", doc.UserID),
 		zap.String("title", doc.Title),
@@ -53,19 +73,23 @@ func (s *DocumentService) Upload(ctx context.Context, doc *models.Document) (int
 	return id, nil
 }
 
-func (s *DocumentService) GetPublicDocumentsPaginated(ctx context.Context, limit, offset int, category string) ([]*models.Document, int, error) {
+func (s *DocumentService) GetPublicDocumentsPaginated(ctx context.Context, limit, offset int, category string, a11y models.DocumentAccessibilityFilter, normative models.DocumentNormativeFilter) ([]*models.Document, int, error) {
 	logger.Log.Info("Сервис: получение публичных документов (пагинация)",
 		zap.Int("limit", limit),
 		zap.Int("offset", offset),
 		zap.String("category", category),
+		zap.Any("a11y", a11y),
+		zap.Any("normative", normative),
 	)
 
-	docs, total, err := s.repo.GetPublicDocumentsPaginated(ctx, limit, offset, category)
+	docs, total, err := s.repo.GetPublicDocumentsPaginated(ctx, limit, offset, category, a11y, normative)
 	if err != nil {
 		logger.Log.Error("Сервис: ошибка получения публичных документов", zap.Error(err))
 		return nil, 0, err
 	}
 
+	markDocumentsFreshness(docs)
+	s.attachRelations(ctx, docs, true)
 	logger.Log.Info("Сервис: публичные документы получены",
 		zap.Int("count", len(docs)),
 		zap.Int("total", total),
@@ -85,6 +109,8 @@ func (s *DocumentService) GetDocumentByID(ctx context.Context, id int) (*models.
 		return nil, err
 	}
 
+	doc.IsUpdatedRecently = updatedRecently(doc.ContentUpdatedAt)
+	s.attachRelations(ctx, []*models.Document{doc}, false)
 	logger.Log.Info("Сервис: документ получен", zap.Int("doc_id", id))
 	return doc, nil
 }
@@ -113,6 +139,8 @@ func (s *DocumentService) GetAllDocuments(ctx context.Context, limit int) ([]*mo
 		return nil, err
 	}
 
+	markDocumentsFreshness(docs)
+	s.attachRelations(ctx, docs, false)
 	logger.Log.Info("Сервис: документы получены", zap.Int("count", len(docs)))
 	return docs, nil
 }
@@ -126,6 +154,9 @@ func (s *DocumentService) Search(ctx context.Context, query string) ([]models.Do
 		return nil, err
 	}
 
+	for i := range res {
+		res[i].IsUpdatedRecently = updatedRecently(res[i].ContentUpdatedAt)
+	}
 	logger.Log.Info("Сервис: поиск завершён", zap.Int("count", len(res)))
 	return res, nil
 }
@@ -149,6 +180,8 @@ func (s *DocumentService) GetPublicDocumentsByFilterPaginated(
 		return nil, 0, err
 	}
 
+	markDocumentsFreshness(docs)
+	s.attachRelations(ctx, docs, true)
 	logger.Log.Info("Сервис: документы по фильтру получены",
 		zap.Int("count", len(docs)),
 		zap.Int("total", total),
@@ -160,18 +193,129 @@ func (s *DocumentService) GetPublicDocuments(
 	ctx context.Context,
 	sectionID *int,
 	category string,
+	a11y models.DocumentAccessibilityFilter,
+	normative models.DocumentNormativeFilter,
 ) ([]*models.Document, error) {
 	logger.Log.Info("Сервис: публичные документы (без пагинации)",
 		zap.Any("section_id", sectionID),
 		zap.String("category", category),
+		zap.Any("a11y", a11y),
+		zap.Any("normative", normative),
 	)
 
-	docs, err := s.repo.GetPublicDocuments(ctx, sectionID, category)
+	docs, err := s.repo.GetPublicDocuments(ctx, sectionID, category, a11y, normative)
 	if err != nil {
 		logger.Log.Error("Сервис: ошибка получения публичных документов", zap.Error(err))
 		return nil, err
 	}
 
+	markDocumentsFreshness(docs)
+	s.attachRelations(ctx, docs, true)
 	logger.Log.Info("Сервис: публичные документы получены", zap.Int("count", len(docs)))
 	return docs, nil
 }
+
+// UpdateAccessibility — поля доступности документа (текстовый слой, крупный шрифт, аудиоверсия).
+func (s *DocumentService) UpdateAccessibility(ctx context.Context, id int, req models.UpdateDocumentAccessibilityRequest) (*models.Document, error) {
+	for _, u := range []*string{req.LargePrintURL, req.AudioURL} {
+		if u == nil {
+			continue
+		}
+		*u = strings.TrimSpace(*u)
+		if *u != "" && !ValidAccessibilityURL(*u) {
+			return nil, ErrInvalidAccessibilityURL
+		}
+	}
+
+	before, err := s.repo.GetDocumentByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	contentUpdated := accessibilityAdded(before, req)
+
+	doc, err := s.repo.UpdateAccessibility(ctx, id, req, contentUpdated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDocumentNotFound
+	}
+	if err != nil {
+		logger.Log.Error("Сервис: ошибка обновления доступности документа", zap.Int("doc_id", id), zap.Error(err))
+		return nil, err
+	}
+	doc.IsUpdatedRecently = updatedRecently(doc.ContentUpdatedAt)
+
+	if contentUpdated && doc.IsPublic {
+		events.Publish(ctx, events.DocumentUpdated, nil, map[string]any{
+			"document_id": doc.ID,
+			"title":       doc.Title,
+			"section_id":  doc.SectionID,
+		})
+	}
+
+	logger.Log.Info("Сервис: доступность документа обновлена",
+		zap.Int("doc_id", id),
+		zap.Bool("has_text_layer", doc.HasTextLayer),
+		zap.Bool("large_print", doc.LargePrintURL != nil),
+		zap.Bool("audio", doc.AudioURL != nil),
+	)
+	return doc, nil
+}
+
+// UpdateDocument — частичная правка метаданных; file != nil — замена файла (новая версия документа:
+// публичный документ попадёт в блок «Обновлено» рассылки). Старый файл с диска удаляет вызывающий.
+func (s *DocumentService) UpdateDocument(ctx context.Context, id int, req models.UpdateDocumentRequest, file *models.DocumentFile) (*models.Document, error) {
+	for _, p := range []*string{req.Title, req.Category} {
+		if p != nil {
+			*p = strings.TrimSpace(*p)
+		}
+	}
+
+	doc, err := s.repo.UpdateDocument(ctx, id, req, file)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDocumentNotFound
+	}
+	if err != nil {
+		logger.Log.Error("Сервис: ошибка обновления документа", zap.Int("doc_id", id), zap.Error(err))
+		return nil, err
+	}
+	doc.IsUpdatedRecently = updatedRecently(doc.ContentUpdatedAt)
+
+	if file != nil && doc.IsPublic {
+		events.Publish(ctx, events.DocumentUpdated, nil, map[string]any{
+			"document_id": doc.ID,
+			"title":       doc.Title,
+			"section_id":  doc.SectionID,
+		})
+	}
+
+	logger.Log.Info("Сервис: документ обновлён",
+		zap.Int("doc_id", id),
+		zap.String("title", doc.Title),
+		zap.Bool("is_public", doc.IsPublic),
+		zap.Bool("file_replaced", file != nil),
+	)
+	return doc, nil
+}
+
+// accessibilityAdded — появилась ли у документа новая версия: включён текстовый слой
+// или задана новая ссылка на крупный шрифт/аудио. Удаление версий обновлением не считается.
+func accessibilityAdded(before *models.Document, req models.UpdateDocumentAccessibilityRequest) bool {
+	if req.HasTextLayer != nil && *req.HasTextLayer && !before.HasTextLayer {
+		return true
+	}
+	changed := func(next, prev *string) bool {
+		return next != nil && *next != "" && (prev == nil || *prev != *next)
+	}
+	return changed(req.LargePrintURL, before.LargePrintURL) || changed(req.AudioURL, before.AudioURL)
+}
+
+// ValidAccessibilityURL — абсолютный http(s)-адрес или путь от корня сайта.
+func ValidAccessibilityURL(raw string) bool {
+	if strings.HasPrefix(raw, "/") && !strings.HasPrefix(raw, "//") {
+		return true
+	}
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
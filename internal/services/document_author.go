@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ListOwn — документы, загруженные пользователем, и их общее число.
+func (s *DocumentService) ListOwn(ctx context.Context, userID, limit, offset int) ([]*models.Document, int, error) {
+	docs, total, err := s.repo.GetDocumentsByUser(ctx, userID, limit, offset)
+	if err != nil {
+		logger.Log.Error("Сервис: ошибка получения документов автора", zap.Int("user_id", userID), zap.Error(err))
+		return nil, 0, err
+	}
+	markDocumentsFreshness(docs)
+	return docs, total, nil
+}
+
+// OwnDocument — документ, загруженный пользователем; чужой — ErrNotContentOwner.
+func (s *DocumentService) OwnDocument(ctx context.Context, userID, id int) (*models.Document, error) {
+	doc, err := s.repo.GetDocumentByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if doc.UserID != userID {
+		logger.Log.Warn("Сервис: попытка изменить чужой документ", zap.Int("doc_id", id), zap.Int("user_id", userID))
+		return nil, ErrNotContentOwner
+	}
+	return doc, nil
+}
+
+// UpdateOwn — правка своего документа (черновик или отклонённый); открыть его (is_public,
+// allow_free_download) может только админ.
+func (s *DocumentService) UpdateOwn(ctx context.Context, userID, id int, req models.UpdateDocumentRequest, file *models.DocumentFile) (*models.Document, error) {
+	if req.IsPublic != nil || req.AllowFreeDownload != nil {
+		return nil, ErrPublishNotAllowed
+	}
+	doc, err := s.OwnDocument(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if contentLocked(doc.IsPublic, doc.ReviewState) {
+		return nil, ErrContentLocked
+	}
+	return s.UpdateDocument(ctx, id, req, file)
+}
+
+// DeleteOwn — свой документ в корзину; публичный убирает только админ.
+func (s *DocumentService) DeleteOwn(ctx context.Context, userID, id int) error {
+	doc, err := s.OwnDocument(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if doc.IsPublic {
+		return ErrContentPublished
+	}
+	return s.Delete(ctx, id)
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+const documentHashBatch = 100
+
+// DocumentDedupService — дубликаты файлов документов по SHA-256: поиск при загрузке,
+// отчёт о лишних копиях и подсчёт хэшей у документов, загруженных раньше.
+type DocumentDedupService struct {
+	repo  *repository.DocumentRepository
+	locks JobLocker
+}
+
+func NewDocumentDedupService(repo *repository.DocumentRepository, locks JobLocker) *DocumentDedupService {
+	return &DocumentDedupService{repo: repo, locks: locks}
+}
+
+// FindDuplicates — документы с файлом того же содержимого; пусто — дубликатов нет.
+func (s *DocumentDedupService) FindDuplicates(ctx context.Context, sha string) ([]models.DocumentDuplicate, error) {
+	if sha == "" {
+		return nil, nil
+	}
+	return s.repo.FindByHash(ctx, sha)
+}
+
+// Report — страница групп дубликатов, их число и место под лишние копии всего.
+func (s *DocumentDedupService) Report(ctx context.Context, limit, offset int) ([]models.DocumentDuplicateGroup, int, int64, error) {
+	return s.repo.DuplicateGroups(ctx, limit, offset)
+}
+
+// RemoveFileIfUnused — удаляет файл, если на него не ссылается ни один документ
+// (при DUPLICATE_UPLOAD_MODE=link один файл бывает у нескольких документов).
+func (s *DocumentDedupService) RemoveFileIfUnused(ctx context.Context, path string) error {
+	used, err := s.repo.FileInUse(ctx, path)
+	if err != nil || used {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Backfill — считает SHA-256 файлов документов, у которых его ещё нет. Файл, который
+// не удалось прочитать, пропускается и попадёт в следующий запуск.
+func (s *DocumentDedupService) Backfill(ctx context.Context) (int, error) {
+	log := logger.WithCtx(ctx)
+	done, after := 0, 0
+	for {
+		batch, err := s.repo.WithoutHash(ctx, after, documentHashBatch)
+		if err != nil {
+			return done, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		for _, t := range batch {
+			after = t.ID
+			if err := ctx.Err(); err != nil {
+				return done, err
+			}
+			sha, size, err := hashFile(t.Filepath)
+			if err != nil {
+				log.Warn("Не удалось посчитать SHA-256 файла документа", zap.Int("doc_id", t.ID), zap.Error(err))
+				continue
+			}
+			if err := s.repo.SetHash(ctx, t.ID, sha, size); err != nil {
+				return done, err
+			}
+			done++
+		}
+	}
+	if done > 0 {
+		log.Info("SHA-256 файлов документов посчитаны", zap.Int("documents", done))
+	}
+	return done, nil
+}
+
+func hashFile(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// Start — однократный подсчёт хэшей после запуска (на одном инстансе); возвращает функцию
+// остановки, которая прерывает подсчёт.
+func (s *DocumentDedupService) Start() func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		defer cancel()
+		go func() {
+			select {
+			case <-done:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		err := RunExclusive(ctx, s.locks, JobDocumentHashes, func(ctx context.Context) error {
+			_, err := s.Backfill(ctx)
+			return err
+		})
+		if err != nil && ctx.Err() == nil {
+			logger.Log.Error("Ошибка подсчёта SHA-256 файлов документов", zap.Error(err))
+		}
+	}()
+
+	return StopAndWait(JobDocumentHashes, done, stopped)
+}
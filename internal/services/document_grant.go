@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrDocumentGrantUser     = errors.New("пользователь не найден")
+	ErrDocumentGrantExpired  = errors.New("срок доступа уже прошёл")
+	ErrDocumentGrantNotFound = errors.New("у пользователя нет персонального доступа к документу")
+)
+
+// GrantAccess — выдаёт пользователю персональный доступ к документу (или меняет срок уже выданного).
+func (s *DocumentService) GrantAccess(ctx context.Context, id, adminID int, req models.GrantDocumentRequest) (*models.DocumentGrant, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrDocumentGrantExpired
+	}
+	if _, err := s.repo.GetDocumentByID(ctx, id); errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDocumentNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	g := &models.DocumentGrant{
+		DocumentID: id,
+		UserID:     req.UserID,
+		Note:       strings.TrimSpace(req.Note),
+		ExpiresAt:  req.ExpiresAt,
+	}
+	if adminID > 0 {
+		g.GrantedBy = &adminID
+	}
+	if err := s.grants.Upsert(ctx, g); errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDocumentGrantUser
+	} else if err != nil {
+		return nil, err
+	}
+
+	logger.WithCtx(ctx).Info("Сервис: выдан доступ к документу",
+		zap.Int("doc_id", id),
+		zap.Int("user_id", req.UserID),
+		zap.Int("admin_id", adminID),
+		zap.Any("expires_at", req.ExpiresAt),
+	)
+	return g, nil
+}
+
+// RevokeAccess — отзывает персональный доступ пользователя к документу.
+func (s *DocumentService) RevokeAccess(ctx context.Context, id, userID int) error {
+	err := s.grants.Delete(ctx, id, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrDocumentGrantNotFound
+	}
+	if err == nil {
+		logger.WithCtx(ctx).Info("Сервис: доступ к документу отозван", zap.Int("doc_id", id), zap.Int("user_id", userID))
+	}
+	return err
+}
+
+// Grants — персональные доступы к документу, включая истёкшие (для админки).
+func (s *DocumentService) Grants(ctx context.Context, id int) ([]models.DocumentGrant, error) {
+	if _, err := s.repo.GetDocumentByID(ctx, id); errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDocumentNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return s.grants.List(ctx, id)
+}
+
+// HasGrant — есть ли у пользователя действующий персональный доступ к документу.
+func (s *DocumentService) HasGrant(ctx context.Context, id, userID int) (bool, error) {
+	if s.grants == nil {
+		return false, nil
+	}
+	return s.grants.Has(ctx, id, userID)
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// documentMoveMax — предел документов, перечисленных в одном переносе.
+const documentMoveMax = 1000
+
+var (
+	ErrMoveNoTargets       = errors.New("укажите document_ids или from_section_id")
+	ErrMoveTooMany         = errors.New("слишком много документов для одного переноса")
+	ErrMoveSectionNotFound = errors.New("раздел не найден")
+)
+
+// MoveDocuments — перенос документов в раздел (или из раздела) одной транзакцией.
+// Отчёт содержит результат по каждому документу: перечисленному в запросе или найденному в from_section_id.
+func (s *DocumentService) MoveDocuments(ctx context.Context, req *models.DocumentBulkMoveRequest) (*models.DocumentBulkMoveReport, error) {
+	log := logger.WithCtx(ctx)
+
+	if len(req.DocumentIDs) > documentMoveMax {
+		return nil, ErrMoveTooMany
+	}
+	seen := make(map[int]bool, len(req.DocumentIDs))
+	ids := make([]int, 0, len(req.DocumentIDs))
+	for _, id := range req.DocumentIDs {
+		if id > 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 && req.FromSectionID == nil {
+		return nil, ErrMoveNoTargets
+	}
+
+	log.Info("Перенос документов", zap.Int("count", len(ids)), zap.Any("from_section_id", req.FromSectionID), zap.Any("section_id", req.SectionID))
+	moved, err := s.repo.MoveDocuments(ctx, ids, req.FromSectionID, req.SectionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMoveSectionNotFound
+		}
+		log.Error("Ошибка переноса документов", zap.Error(err))
+		return nil, err
+	}
+
+	report := &models.DocumentBulkMoveReport{SectionID: req.SectionID}
+	for _, id := range ids {
+		res := models.DocumentMoveResult{DocumentID: id, Status: models.DocumentMoveNotFound}
+		if prev, ok := moved[id]; ok {
+			res.Status, res.FromSectionID = models.DocumentMoveOK, prev
+			delete(moved, id)
+		}
+		report.Results = append(report.Results, res)
+	}
+	// остальные — документы из from_section_id, не перечисленные явно
+	rest := make([]int, 0, len(moved))
+	for id := range moved {
+		rest = append(rest, id)
+	}
+	slices.Sort(rest)
+	for _, id := range rest {
+		report.Results = append(report.Results, models.DocumentMoveResult{DocumentID: id, Status: models.DocumentMoveOK, FromSectionID: moved[id]})
+	}
+
+	for _, res := range report.Results {
+		if res.Status == models.DocumentMoveOK {
+			report.Moved++
+		} else {
+			report.NotFound++
+		}
+	}
+	report.Total = len(report.Results)
+
+	log.Info("Перенос документов завершён", zap.Int("moved", report.Moved), zap.Int("not_found", report.NotFound))
+	return report, nil
+}
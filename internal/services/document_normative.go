@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// NormativeDateLayout — формат дат реквизитов в API (query, форма загрузки, JSON правки).
+const NormativeDateLayout = "2006-01-02"
+
+const (
+	maxDocNumberLen        = 100
+	maxIssuingAuthorityLen = 255
+)
+
+var (
+	ErrInvalidNormativeDate  = errors.New("дата должна быть в формате ГГГГ-ММ-ДД")
+	ErrInvalidNormativeField = errors.New("номер документа — до 100 символов, орган — до 255 символов")
+	ErrNormativeDatesOrder   = errors.New("дата вступления в силу не может быть раньше даты принятия")
+	ErrAdoptedInFuture       = errors.New("дата принятия не может быть в будущем")
+)
+
+// ParseNormativeDate — дата реквизита; пустая строка — nil.
+func ParseNormativeDate(raw string) (*time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(NormativeDateLayout, raw)
+	if err != nil {
+		return nil, ErrInvalidNormativeDate
+	}
+	return &t, nil
+}
+
+// normalizeNormative — обрезает пробелы (пустые строки → nil) и проверяет реквизиты документа.
+func normalizeNormative(doc *models.Document) error {
+	trim := func(p *string, max int) (*string, error) {
+		if p == nil {
+			return nil, nil
+		}
+		v := strings.Join(strings.Fields(*p), " ")
+		if v == "" {
+			return nil, nil
+		}
+		if utf8.RuneCountInString(v) > max {
+			return nil, ErrInvalidNormativeField
+		}
+		return &v, nil
+	}
+
+	var err error
+	if doc.DocNumber, err = trim(doc.DocNumber, maxDocNumberLen); err != nil {
+		return err
+	}
+	if doc.IssuingAuthority, err = trim(doc.IssuingAuthority, maxIssuingAuthorityLen); err != nil {
+		return err
+	}
+	if doc.AdoptedAt != nil && doc.AdoptedAt.After(time.Now()) {
+		return ErrAdoptedInFuture
+	}
+	if doc.AdoptedAt != nil && doc.EffectiveAt != nil && doc.EffectiveAt.Before(*doc.AdoptedAt) {
+		return ErrNormativeDatesOrder
+	}
+	return nil
+}
+
+// IsNormativeValidationError — ошибка во входных реквизитах (для ответа 400).
+func IsNormativeValidationError(err error) bool {
+	return errors.Is(err, ErrInvalidNormativeDate) || errors.Is(err, ErrInvalidNormativeField) ||
+		errors.Is(err, ErrNormativeDatesOrder) || errors.Is(err, ErrAdoptedInFuture)
+}
+
+// UpdateNormative — реквизиты нормативного документа; проверяются вместе с текущими значениями,
+// чтобы нельзя было, например, поставить дату вступления раньше уже сохранённой даты принятия.
+func (s *DocumentService) UpdateNormative(ctx context.Context, id int, req models.UpdateDocumentNormativeRequest) (*models.Document, error) {
+	before, err := s.repo.GetDocumentByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	merged := *before
+	if req.DocNumber != nil {
+		merged.DocNumber = req.DocNumber
+	}
+	if req.IssuingAuthority != nil {
+		merged.IssuingAuthority = req.IssuingAuthority
+	}
+	if req.AdoptedAt != nil {
+		if merged.AdoptedAt, err = ParseNormativeDate(*req.AdoptedAt); err != nil {
+			return nil, err
+		}
+	}
+	if req.EffectiveAt != nil {
+		if merged.EffectiveAt, err = ParseNormativeDate(*req.EffectiveAt); err != nil {
+			return nil, err
+		}
+	}
+	if err := normalizeNormative(&merged); err != nil {
+		return nil, err
+	}
+
+	// в репозиторий — нормализованные значения ("" — очистить)
+	str := func(sent *string, v *string) *string {
+		if sent == nil {
+			return nil
+		}
+		out := ""
+		if v != nil {
+			out = *v
+		}
+		return &out
+	}
+	date := func(sent *string, v *time.Time) *string {
+		if sent == nil {
+			return nil
+		}
+		out := ""
+		if v != nil {
+			out = v.Format(NormativeDateLayout)
+		}
+		return &out
+	}
+	req = models.UpdateDocumentNormativeRequest{
+		DocNumber:        str(req.DocNumber, merged.DocNumber),
+		IssuingAuthority: str(req.IssuingAuthority, merged.IssuingAuthority),
+		AdoptedAt:        date(req.AdoptedAt, merged.AdoptedAt),
+		EffectiveAt:      date(req.EffectiveAt, merged.EffectiveAt),
+	}
+
+	doc, err := s.repo.UpdateNormative(ctx, id, req)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDocumentNotFound
+	}
+	if err != nil {
+		logger.Log.Error("Сервис: ошибка обновления реквизитов документа", zap.Int("doc_id", id), zap.Error(err))
+		return nil, err
+	}
+	doc.IsUpdatedRecently = updatedRecently(doc.ContentUpdatedAt)
+
+	logger.Log.Info("Сервис: реквизиты документа обновлены",
+		zap.Int("doc_id", id),
+		zap.Any("doc_number", doc.DocNumber),
+		zap.Any("issuing_authority", doc.IssuingAuthority),
+	)
+	return doc, nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidDocumentRelation  = errors.New("тип связи: supersedes, amended_by или related; документ не может ссылаться на себя")
+	ErrDocumentRelationExists   = errors.New("такая связь уже есть")
+	ErrDocumentRelationNotFound = errors.New("связь не найдена")
+)
+
+// AddRelation — связывает документ id с другим документом (id <relation> req.RelatedID).
+func (s *DocumentService) AddRelation(ctx context.Context, id int, req models.CreateDocumentRelationRequest) (*models.DocumentRelation, error) {
+	req.Relation = strings.ToLower(strings.TrimSpace(req.Relation))
+	switch req.Relation {
+	case models.DocumentRelationSupersedes, models.DocumentRelationAmendedBy, models.DocumentRelationRelated:
+	default:
+		return nil, ErrInvalidDocumentRelation
+	}
+	if req.RelatedID <= 0 || req.RelatedID == id {
+		return nil, ErrInvalidDocumentRelation
+	}
+	for _, docID := range []int{id, req.RelatedID} {
+		if _, err := s.repo.GetDocumentByID(ctx, docID); errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDocumentNotFound
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	rel := &models.DocumentRelation{
+		DocumentID: id,
+		RelatedID:  req.RelatedID,
+		Relation:   req.Relation,
+		Note:       strings.TrimSpace(req.Note),
+	}
+	if err := s.relations.Create(ctx, rel); errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDocumentRelationExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("Сервис: связь документов добавлена",
+		zap.Int("doc_id", id),
+		zap.Int("related_id", req.RelatedID),
+		zap.String("relation", req.Relation),
+	)
+	return rel, nil
+}
+
+// DeleteRelation — удаляет связь документа (с любой из сторон).
+func (s *DocumentService) DeleteRelation(ctx context.Context, id int, relationID int64) error {
+	err := s.relations.Delete(ctx, id, relationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrDocumentRelationNotFound
+	}
+	if err == nil {
+		logger.Log.Info("Сервис: связь документов удалена", zap.Int("doc_id", id), zap.Int64("relation_id", relationID))
+	}
+	return err
+}
+
+// Relations — все связи документа, включая закрытые документы (для админки).
+func (s *DocumentService) Relations(ctx context.Context, id int) ([]models.DocumentLink, error) {
+	if _, err := s.repo.GetDocumentByID(ctx, id); errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDocumentNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	links, err := s.relations.LinksFor(ctx, []int{id}, false)
+	if err != nil {
+		return nil, err
+	}
+	if links[id] == nil {
+		return []models.DocumentLink{}, nil
+	}
+	return links[id], nil
+}
+
+// attachRelations — заполняет Relations и SupersededBy; ошибка не ломает выдачу документов.
+// SupersededBy указывает только на публичную редакцию — закрытая учителю не поможет.
+func (s *DocumentService) attachRelations(ctx context.Context, docs []*models.Document, publicOnly bool) {
+	if s.relations == nil || len(docs) == 0 {
+		return
+	}
+	ids := make([]int, 0, len(docs))
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+	links, err := s.relations.LinksFor(ctx, ids, publicOnly)
+	if err != nil {
+		logger.Log.Warn("Сервис: не удалось загрузить связи документов", zap.Error(err))
+		return
+	}
+	for _, d := range docs {
+		d.Relations = links[d.ID]
+		for i, l := range d.Relations {
+			if l.Relation == models.DocumentRelationSupersededBy && l.IsPublic {
+				d.SupersededBy = &d.Relations[i]
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// DefaultDownloadQuotas — дневные лимиты скачиваний, если DOWNLOAD_QUOTAS не задан.
+const DefaultDownloadQuotas = "free:5,subscriber:20,monthly:20,halfyear:30,yearly:50"
+
+var ErrDownloadQuotaExceeded = errors.New("дневной лимит скачиваний исчерпан")
+
+// quotaZone — сутки лимита считаются по Москве (переходов на летнее время нет).
+var quotaZone = time.FixedZone("MSK", 3*60*60)
+
+// DownloadQuotaService — дневные лимиты скачиваний по тарифам с индивидуальными исключениями.
+type DownloadQuotaService struct {
+	repo   *repository.DownloadQuotaRepository
+	limits map[string]int
+}
+
+// NewDownloadQuotaService — spec: "тариф:лимит" через запятую; некорректные записи пропускаются.
+func NewDownloadQuotaService(repo *repository.DownloadQuotaRepository, spec string) *DownloadQuotaService {
+	if strings.TrimSpace(spec) == "" {
+		spec = DefaultDownloadQuotas
+	}
+	s := &DownloadQuotaService{repo: repo, limits: map[string]int{}}
+	for _, item := range strings.Split(spec, ",") {
+		tier, raw, ok := strings.Cut(strings.TrimSpace(item), ":")
+		tier = strings.ToLower(strings.TrimSpace(tier))
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if !ok || tier == "" || err != nil || n < 0 {
+			continue
+		}
+		s.limits[tier] = n
+	}
+	return s
+}
+
+// Status — лимит пользователя на сегодня.
+func (s *DownloadQuotaService) Status(ctx context.Context, u *models.User) (*models.DownloadQuota, error) {
+	day, resets := quotaDay(time.Now())
+	if u.Role == "admin" {
+		return &models.DownloadQuota{Tier: models.DownloadTierAdmin, ResetsAt: resets}, nil
+	}
+	st, err := s.repo.State(ctx, u.ID, day)
+	if err != nil {
+		return nil, err
+	}
+	return s.quota(u, st, resets), nil
+}
+
+// Consume — учитывает скачивание документа. Докачка (resume) документа, уже скачанного сегодня,
+// лимит не тратит. При исчерпанном лимите — ErrDownloadQuotaExceeded вместе с состоянием лимита.
+func (s *DownloadQuotaService) Consume(ctx context.Context, u *models.User, documentID int, resume bool) (*models.DownloadQuota, error) {
+	day, resets := quotaDay(time.Now())
+	if u.Role == "admin" {
+		return &models.DownloadQuota{Tier: models.DownloadTierAdmin, ResetsAt: resets}, nil
+	}
+	st, err := s.repo.State(ctx, u.ID, day)
+	if err != nil {
+		return nil, err
+	}
+	q := s.quota(u, st, resets)
+
+	if resume {
+		if again, err := s.repo.DownloadedSince(ctx, u.ID, documentID, day); err == nil && again {
+			return q, nil
+		}
+	}
+	if q.Limit != nil && *q.Limit == 0 {
+		return q, ErrDownloadQuotaExceeded
+	}
+
+	used, ok, err := s.repo.Consume(ctx, u.ID, day, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		logger.WithCtx(ctx).Info("Лимит скачиваний исчерпан",
+			zap.Int("user_id", u.ID), zap.String("tier", q.Tier), zap.Intp("limit", q.Limit))
+		q.Used = *q.Limit
+		q.Remaining = intPtr(0)
+		return q, ErrDownloadQuotaExceeded
+	}
+	q.Used = used
+	if q.Limit != nil {
+		q.Remaining = intPtr(max(*q.Limit-used, 0))
+	}
+	return q, nil
+}
+
+// SetOverride — индивидуальный дневной лимит вместо тарифного (nil — без ограничения).
+func (s *DownloadQuotaService) SetOverride(ctx context.Context, userID int, limit *int, adminID int) error {
+	return s.repo.SetOverride(ctx, userID, limit, adminID)
+}
+
+// ClearOverride — вернуть тарифный лимит; false — индивидуального лимита не было.
+func (s *DownloadQuotaService) ClearOverride(ctx context.Context, userID int) (bool, error) {
+	return s.repo.DeleteOverride(ctx, userID)
+}
+
+func (s *DownloadQuotaService) quota(u *models.User, st *models.DownloadQuotaState, resets time.Time) *models.DownloadQuota {
+	q := &models.DownloadQuota{Tier: models.DownloadTierFree, Used: st.Used, ResetsAt: resets}
+	if subscriptionActive(u) {
+		q.Tier = models.DownloadTierSubscriber
+		if st.Plan != "" {
+			q.Tier = st.Plan
+		}
+	}
+
+	switch {
+	case st.HasOverride:
+		q.Override, q.Limit = true, st.OverrideLimit
+	default:
+		q.Limit = s.tierLimit(q.Tier)
+	}
+	if q.Limit != nil {
+		q.Remaining = intPtr(max(*q.Limit-q.Used, 0))
+	}
+	return q
+}
+
+// tierLimit — лимит тарифа; план без своей записи берёт лимит subscriber, нет и его — без ограничения.
+func (s *DownloadQuotaService) tierLimit(tier string) *int {
+	if n, ok := s.limits[tier]; ok {
+		return &n
+	}
+	if tier != models.DownloadTierFree {
+		if n, ok := s.limits[models.DownloadTierSubscriber]; ok {
+			return &n
+		}
+	}
+	return nil
+}
+
+// quotaDay — начало текущих суток лимита и момент сброса счётчика.
+func quotaDay(now time.Time) (day, resets time.Time) {
+	y, m, d := now.In(quotaZone).Date()
+	day = time.Date(y, m, d, 0, 0, 0, 0, quotaZone)
+	return day, day.AddDate(0, 0, 1)
+}
+
+func subscriptionActive(u *models.User) bool {
+	return u.HasSubscription && u.SubscriptionExpiresAt != nil && u.SubscriptionExpiresAt.After(time.Now())
+}
+
+func intPtr(n int) *int { return &n }
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/metrics"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+var documentDownloads = metrics.NewCounter("document_downloads_total", "Скачиваний документов")
+
+type DownloadStatsService struct {
+	repo *repository.DownloadRepository
+}
+
+func NewDownloadStatsService(repo *repository.DownloadRepository) *DownloadStatsService {
+	return &DownloadStatsService{repo: repo}
+}
+
+// Record — фиксирует скачивание. Ошибка записи не должна ломать отдачу файла, поэтому только логируется.
+func (s *DownloadStatsService) Record(ctx context.Context, documentID, userID int) {
+	documentDownloads.Inc()
+	if err := s.repo.Record(ctx, documentID, userID); err != nil {
+		logger.WithCtx(ctx).Warn("Не удалось записать скачивание документа",
+			zap.Int("doc_id", documentID), zap.Int("user_id", userID), zap.Error(err))
+	}
+}
+
+// Stats — скачивания по документам (страница) и временной ряд по дням за тот же период.
+func (s *DownloadStatsService) Stats(ctx context.Context, f models.DownloadStatsFilter, limit, offset int) ([]models.DocumentDownloadStat, int, []models.DownloadsDayPoint, error) {
+	items, total, err := s.repo.PerDocument(ctx, f, limit, offset)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	series, err := s.repo.DailySeries(ctx, f)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return items, total, series, nil
+}
+
+// Top — самые популярные документы за период.
+func (s *DownloadStatsService) Top(ctx context.Context, f models.DownloadStatsFilter, limit int) ([]models.DocumentDownloadStat, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+	return s.repo.Top(ctx, f, limit)
+}
@@ -1,12 +1,19 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"edutalks/internal/config"
 	"edutalks/internal/logger"
 	"edutalks/internal/utils/helpers"
+	"encoding/base64"
 	"fmt"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -20,9 +27,17 @@ type EmailService struct {
 	from string
 	host string
 	port string
+
+	// devDir — EMAIL_MODE=file: письма сохраняются в каталог как .eml, SMTP не используется
+	devDir string
+
+	// отписка в один клик: ссылка в List-Unsubscribe; ключ подписи токена — из cfg на каждое письмо
+	unsubscribeURL string
+	cfg            *config.Holder
 }
 
-func NewEmailService(cfg *config.Config) *EmailService {
+func NewEmailService(live *config.Holder) *EmailService {
+	cfg := live.Get()
 	// Применяем настройку задержки между адресатами из .env
 	if d, err := time.ParseDuration(cfg.EmailPerRecipientDelay); err == nil && d >= 0 {
 		emailPerRecipientDelay = d
@@ -34,6 +49,17 @@ func NewEmailService(cfg *config.Config) *EmailService {
 		from: cfg.SMTPUser,
 		host: cfg.SMTPHost,
 		port: cfg.SMTPPort,
+		cfg:  live,
+	}
+	s.unsubscribeURL = strings.TrimSpace(cfg.UnsubscribeURL)
+	if len(s.unsubscribeSecret()) == 0 {
+		logger.Log.Warn("Сервис: не задан UNSUBSCRIBE_SECRET/JWT_SECRET — ссылка отписки в письма не добавляется")
+	}
+	if cfg.EmailMode == "file" {
+		s.devDir = cfg.EmailDevDir
+		logger.Log.Warn("Сервис: EMAIL_MODE=file — письма не отправляются, а сохраняются на диск",
+			zap.String("dir", s.devDir),
+		)
 	}
 	logger.Log.Info("Сервис: инициализация EmailService",
 		zap.String("smtp_host", s.host),
@@ -48,6 +74,30 @@ func (s *EmailService) smtpAddr() string {
 	return fmt.Sprintf("%s:%s", s.host, s.port)
 }
 
+// deliver — отправка готового письма одному адресату: через SMTP или (dev) в файл.
+func (s *EmailService) deliver(addr, recipient string, msg []byte) error {
+	if s.devDir == "" {
+		return smtp.SendMail(addr, s.auth, s.from, []string{recipient}, msg)
+	}
+
+	if err := os.MkdirAll(s.devDir, 0o755); err != nil {
+		return err
+	}
+	safe := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@._-", r) {
+			return r
+		}
+		return '_'
+	}, recipient)
+	name := time.Now().Format("20060102-150405.000000000") + "_" + safe + ".eml"
+	path := filepath.Join(s.devDir, name)
+	if err := os.WriteFile(path, msg, 0o644); err != nil {
+		return err
+	}
+	logger.Log.Debug("Сервис: письмо сохранено в файл (EMAIL_MODE=file)", zap.String("path", path))
+	return nil
+}
+
 // Send — текстовое письмо; отправляем по одному получателю с небольшой паузой
 func (s *EmailService) Send(to []string, subject, body string) error {
 	addr := s.smtpAddr()
@@ -62,14 +112,14 @@ func (s *EmailService) Send(to []string, subject, body string) error {
 			"From: Edutalks <" + s.from + ">\r\n" +
 				"To: " + recipient + "\r\n" +
 				"Subject: " + subject + "\r\n" +
-				"List-Unsubscribe: <mailto:unsubscribe@edutalks.ru?subject=unsubscribe>, <https://edutalks.ru/unsubscribe>\r\n" +
+				"List-Unsubscribe: " + s.listUnsubscribe(recipient) + "\r\n" +
 				"List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n" +
 				"Precedence: bulk\r\n" +
 				"Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n" +
 				body,
 		)
 
-		if err := smtp.SendMail(addr, s.auth, s.from, []string{recipient}, msg); err != nil {
+		if err := s.deliver(addr, recipient, msg); err != nil {
 			logger.Log.Error("Сервис: ошибка отправки письма (plain)",
 				zap.String("to", recipient),
 				zap.String("subject", subject),
@@ -84,7 +134,7 @@ func (s *EmailService) Send(to []string, subject, body string) error {
 		)
 
 		// Пауза между адресатами, чтобы сгладить спайки
-		if i < len(to)-1 && emailPerRecipientDelay > 0 {
+		if i < len(to)-1 && emailPerRecipientDelay > 0 && s.devDir == "" {
 			time.Sleep(emailPerRecipientDelay)
 		}
 	}
@@ -95,6 +145,12 @@ func (s *EmailService) Send(to []string, subject, body string) error {
 func (s *EmailService) SendHTML(to []string, subject, htmlBody string) error {
 	addr := s.smtpAddr()
 
+	contentType, body, err := alternativeBody(htmlBody)
+	if err != nil {
+		logger.Log.Error("Сервис: ошибка сборки письма (html)", zap.String("subject", subject), zap.Error(err))
+		return err
+	}
+
 	for i, recipient := range to {
 		logger.Log.Info("Сервис: отправка письма (html)",
 			zap.String("to", recipient),
@@ -106,14 +162,14 @@ func (s *EmailService) SendHTML(to []string, subject, htmlBody string) error {
 				"To: " + recipient + "\r\n" +
 				"Subject: " + subject + "\r\n" +
 				"MIME-Version: 1.0\r\n" +
-				"List-Unsubscribe: <mailto:unsubscribe@edutalks.ru?subject=unsubscribe>, <https://edutalks.ru/unsubscribe>\r\n" +
+				"List-Unsubscribe: " + s.listUnsubscribe(recipient) + "\r\n" +
 				"List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n" +
 				"Precedence: bulk\r\n" +
-				"Content-Type: text/html; charset=\"utf-8\"\r\n\r\n" +
-				htmlBody,
+				"Content-Type: " + contentType + "\r\n\r\n" +
+				body,
 		)
 
-		if err := smtp.SendMail(addr, s.auth, s.from, []string{recipient}, msg); err != nil {
+		if err := s.deliver(addr, recipient, msg); err != nil {
 			logger.Log.Error("Сервис: ошибка отправки письма (html)",
 				zap.String("to", recipient),
 				zap.String("subject", subject),
@@ -128,16 +184,123 @@ func (s *EmailService) SendHTML(to []string, subject, htmlBody string) error {
 		)
 
 		// Пауза между адресатами, чтобы сгладить спайки
-		if i < len(to)-1 && emailPerRecipientDelay > 0 {
+		if i < len(to)-1 && emailPerRecipientDelay > 0 && s.devDir == "" {
 			time.Sleep(emailPerRecipientDelay)
 		}
 	}
 	return nil
 }
 
-func (s *EmailService) SendPasswordReset(ctx context.Context, to, resetLink string) error {
-	subject := "Восстановление пароля"
-	htmlBody := helpers.BuildPasswordResetHTML(resetLink)
+// EmailAttachment — вложение письма (квитанция и т.п.).
+type EmailAttachment struct {
+	Filename    string // только ASCII
+	ContentType string
+	Data        []byte
+}
+
+// SendWithAttachment — личное HTML-письмо с вложением одному адресату (без заголовков рассылки).
+func (s *EmailService) SendWithAttachment(to, subject, htmlBody string, att EmailAttachment) error {
+	contentType, body, err := alternativeBody(htmlBody)
+	if err != nil {
+		logger.Log.Error("Сервис: ошибка сборки письма (вложение)", zap.String("subject", subject), zap.Error(err))
+		return err
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	pw, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	if err == nil {
+		_, err = pw.Write([]byte(body))
+	}
+	if err == nil {
+		pw, err = mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {att.ContentType + "; name=\"" + att.Filename + "\""},
+			"Content-Disposition":       {"attachment; filename=\"" + att.Filename + "\""},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+	}
+	if err == nil {
+		_, err = pw.Write(base64Lines(att.Data))
+	}
+	if err == nil {
+		err = mw.Close()
+	}
+	if err != nil {
+		logger.Log.Error("Сервис: ошибка сборки письма (вложение)", zap.String("subject", subject), zap.Error(err))
+		return err
+	}
+
+	logger.Log.Info("Сервис: отправка письма с вложением",
+		zap.String("to", to),
+		zap.String("subject", subject),
+		zap.String("attachment", att.Filename),
+		zap.Int("size", len(att.Data)),
+	)
+
+	msg := []byte(
+		"From: Edutalks <" + s.from + ">\r\n" +
+			"To: " + to + "\r\n" +
+			"Subject: " + subject + "\r\n" +
+			"MIME-Version: 1.0\r\n" +
+			"Content-Type: multipart/mixed; boundary=\"" + mw.Boundary() + "\"\r\n\r\n" +
+			buf.String(),
+	)
+	if err := s.deliver(s.smtpAddr(), to, msg); err != nil {
+		logger.Log.Error("Сервис: ошибка отправки письма с вложением",
+			zap.String("to", to),
+			zap.String("subject", subject),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	logger.Log.Info("Сервис: письмо с вложением отправлено", zap.String("to", to), zap.String("subject", subject))
+	return nil
+}
+
+// base64Lines — base64 со строками по 76 символов (RFC 2045).
+func base64Lines(data []byte) []byte {
+	enc := base64.StdEncoding.EncodeToString(data)
+	var b bytes.Buffer
+	for len(enc) > 76 {
+		b.WriteString(enc[:76] + "\r\n")
+		enc = enc[76:]
+	}
+	b.WriteString(enc + "\r\n")
+	return b.Bytes()
+}
+
+// alternativeBody — multipart/alternative: текстовая версия для клиентов без HTML и сама HTML-версия.
+func alternativeBody(htmlBody string) (string, string, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	parts := []struct{ contentType, body string }{
+		{"text/plain; charset=\"utf-8\"", helpers.HTMLToText(htmlBody)},
+		{"text/html; charset=\"utf-8\"", htmlBody},
+	}
+	for _, p := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"8bit"},
+		})
+		if err != nil {
+			return "", "", err
+		}
+		if _, err := pw.Write([]byte(p.body)); err != nil {
+			return "", "", err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return "", "", err
+	}
+	return "multipart/alternative; boundary=\"" + mw.Boundary() + "\"", buf.String(), nil
+}
+
+// SendPasswordReset — письмо со ссылкой сброса пароля на языке locale; validFor — срок действия ссылки.
+func (s *EmailService) SendPasswordReset(ctx context.Context, to, locale, resetLink string, validFor time.Duration) error {
+	subject := helpers.EmailSubject(locale, helpers.MailPasswordReset)
+	htmlBody := helpers.BuildPasswordResetHTML(locale, resetLink, helpers.FormatTTL(locale, validFor))
 
 	logger.Log.Info("Сервис: формирование письма для восстановления пароля",
 		zap.String("to", to),
@@ -155,9 +318,9 @@ func (s *EmailService) SendPasswordReset(ctx context.Context, to, resetLink stri
 	return nil
 }
 
-func (s *EmailService) SendSubscriptionGranted(ctx context.Context, to, name, planLabel string, expiresAt time.Time) error {
-	subject := "Подписка активирована"
-	body := helpers.BuildSubscriptionGrantedHTML(name, planLabel, expiresAt.Format("02.01.2006 15:04"))
+func (s *EmailService) SendSubscriptionGranted(ctx context.Context, to, locale, name, planLabel string, expiresAt time.Time) error {
+	subject := helpers.EmailSubject(locale, helpers.MailSubscriptionGranted)
+	body := helpers.BuildSubscriptionGrantedHTML(locale, name, planLabel, expiresAt)
 
 	logger.Log.Info("Сервис: формирование письма об активации подписки",
 		zap.String("to", to),
@@ -180,9 +343,9 @@ func (s *EmailService) SendSubscriptionGranted(ctx context.Context, to, name, pl
 	return nil
 }
 
-func (s *EmailService) SendSubscriptionRevoked(ctx context.Context, to, name string, revokedAt time.Time, prevExpiresAt *time.Time) error {
-	subject := "Подписка отключена"
-	body := helpers.BuildSubscriptionRevokedHTML(name, revokedAt, prevExpiresAt)
+func (s *EmailService) SendSubscriptionRevoked(ctx context.Context, to, locale, name string, revokedAt time.Time, prevExpiresAt *time.Time) error {
+	subject := helpers.EmailSubject(locale, helpers.MailSubscriptionRevoked)
+	body := helpers.BuildSubscriptionRevokedHTML(locale, name, revokedAt, prevExpiresAt)
 
 	logger.Log.Info("Сервис: формирование письма об отключении подписки",
 		zap.String("to", to),
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils"
+	"edutalks/internal/utils/helpers"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrEmailInvalid            = errors.New("некорректный адрес почты")
+	ErrEmailUnchanged          = errors.New("новый адрес совпадает с текущим")
+	ErrEmailTaken              = errors.New("адрес электронной почты уже зарегистрирован")
+	ErrEmailChangePassword     = errors.New("неверный пароль")
+	ErrEmailChangeTokenInvalid = errors.New("ссылка подтверждения недействительна или устарела")
+)
+
+const emailChangeTTL = 24 * time.Hour
+
+// EmailChangeService — смена email с подтверждением: ссылка уходит на новый адрес, а текущий
+// остаётся в силе (и подтверждённым), пока по ней не перейдут.
+type EmailChangeService struct {
+	repo  *repository.EmailChangeRepository
+	users *repository.UserRepository
+	auth  *AuthService // сброс кэша пользователей
+
+	confirmURL string
+}
+
+func NewEmailChangeService(repo *repository.EmailChangeRepository, users *repository.UserRepository, auth *AuthService, cfg *config.Config) *EmailChangeService {
+	return &EmailChangeService{
+		repo:       repo,
+		users:      users,
+		auth:       auth,
+		confirmURL: strings.TrimSpace(cfg.EmailChangeConfirmURL),
+	}
+}
+
+// NormalizeEmail — адрес в нижнем регистре без пробелов; ErrEmailInvalid — не похоже на адрес.
+func NormalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", ErrEmailInvalid
+	}
+	return email, nil
+}
+
+// Request — запрос на смену email пользователя userID (с подтверждением паролем): письмо со ссылкой
+// уходит на новый адрес. Повторный запрос заменяет предыдущий.
+func (s *EmailChangeService) Request(ctx context.Context, userID int, newEmail, password string) (*models.EmailChangeRequest, error) {
+	log := logger.WithCtx(ctx).With(zap.Int("user_id", userID))
+
+	newEmail, err := NormalizeEmail(newEmail)
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.CheckPasswordHash(password, u.PasswordHash) {
+		log.Warn("Смена email: неверный пароль")
+		return nil, ErrEmailChangePassword
+	}
+	if strings.EqualFold(u.Email, newEmail) {
+		return nil, ErrEmailUnchanged
+	}
+	taken, err := s.users.IsEmailTaken(ctx, newEmail)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, ErrEmailTaken
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	req, err := s.repo.Create(ctx, userID, newEmail, emailChangeTokenHash(token), time.Now().Add(emailChangeTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	EmailQueue <- EmailJob{
+		To:      []string{newEmail},
+		Subject: helpers.EmailSubject(u.Locale, helpers.MailEmailChange),
+		Body: helpers.BuildEmailChangeHTML(u.Locale, u.FullName, newEmail,
+			appendTokenParam(s.confirmURL, token), helpers.FormatTTL(u.Locale, emailChangeTTL)),
+		IsHTML: true,
+	}
+	log.Info("Смена email запрошена, письмо на новый адрес поставлено в очередь", zap.Int64("request_id", req.ID))
+	return req, nil
+}
+
+// Pending — действующий запрос на смену email (nil — нет).
+func (s *EmailChangeService) Pending(ctx context.Context, userID int) (*models.EmailChangeRequest, error) {
+	req, err := s.repo.Pending(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return req, err
+}
+
+// Confirm — применяет новый адрес по токену из письма.
+func (s *EmailChangeService) Confirm(ctx context.Context, token string) error {
+	req, err := s.repo.Confirm(ctx, emailChangeTokenHash(strings.TrimSpace(token)))
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return ErrEmailChangeTokenInvalid
+	case errors.As(err, &pgErr) && pgErr.Code == "23505":
+		return ErrEmailTaken
+	case err != nil:
+		return err
+	}
+	s.auth.InvalidateUser(req.UserID)
+
+	logger.WithCtx(ctx).Info("Email пользователя изменён", zap.Int("user_id", req.UserID))
+	return nil
+}
+
+func emailChangeTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var ErrEmailNotFound = errors.New("письмо не найдено")
+var ErrEmailNotRequeueable = errors.New("письмо не найдено или не в статусе failed")
+
+type EmailOutboxService struct {
+	repo *repository.EmailOutboxRepository
+}
+
+func NewEmailOutboxService(repo *repository.EmailOutboxRepository) *EmailOutboxService {
+	return &EmailOutboxService{repo: repo}
+}
+
+// ListFailed — письма, исчерпавшие попытки отправки.
+func (s *EmailOutboxService) ListFailed(ctx context.Context, limit, offset int) ([]models.EmailOutbox, int, error) {
+	return s.repo.ListByStatus(ctx, models.EmailStatusFailed, limit, offset)
+}
+
+// List — история писем с фильтрами.
+func (s *EmailOutboxService) List(ctx context.Context, f models.EmailOutboxFilter, limit, offset int) ([]models.EmailOutbox, int, error) {
+	return s.repo.List(ctx, f, limit, offset)
+}
+
+// Get — письмо по ID; ErrEmailNotFound, если такого нет.
+func (s *EmailOutboxService) Get(ctx context.Context, id int64) (*models.EmailOutbox, error) {
+	m, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrEmailNotFound
+	}
+	return m, err
+}
+
+// Requeue — повторная постановка failed-письма в очередь.
+func (s *EmailOutboxService) Requeue(ctx context.Context, id int64) error {
+	log := logger.WithCtx(ctx)
+
+	ok, err := s.repo.Requeue(ctx, id)
+	if err != nil {
+		log.Error("Ошибка повторной постановки письма в очередь", zap.Int64("id", id), zap.Error(err))
+		return err
+	}
+	if !ok {
+		return ErrEmailNotRequeueable
+	}
+	log.Info("Письмо возвращено в очередь", zap.Int64("id", id))
+	return nil
+}
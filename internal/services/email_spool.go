@@ -0,0 +1,96 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+
+	"edutalks/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// Файл отложенных писем: задания, которые не удалось ни сохранить в email_outbox, ни отправить
+// (БД и SMTP недоступны), а также те, что остались в EmailQueue, когда время остановки вышло.
+// При следующем запуске ReplayEmailSpool снова ставит их в очередь.
+var (
+	emailSpoolFile = "email_spool.jsonl"
+	emailSpoolMu   sync.Mutex
+)
+
+// spoolEmailJob — дописывает задание в файл отложенных писем (одна JSON-строка на задание).
+func spoolEmailJob(job EmailJob) error {
+	line, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	emailSpoolMu.Lock()
+	defer emailSpoolMu.Unlock()
+
+	f, err := os.OpenFile(emailSpoolFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		logger.Log.Error("Не удалось сохранить письмо на диск — письмо потеряно",
+			zap.String("file", emailSpoolFile), zap.String("subject", job.Subject), zap.Int("recipients", len(job.To)), zap.Error(err))
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		logger.Log.Error("Не удалось сохранить письмо на диск — письмо потеряно",
+			zap.String("file", emailSpoolFile), zap.String("subject", job.Subject), zap.Int("recipients", len(job.To)), zap.Error(err))
+		return err
+	}
+	logger.Log.Warn("Письмо отложено на диск до следующего запуска",
+		zap.String("file", emailSpoolFile), zap.String("subject", job.Subject), zap.Int("recipients", len(job.To)))
+	return nil
+}
+
+// ReplayEmailSpool — ставит в EmailQueue письма, отложенные на диск; вызывать после
+// StartEmailWorker. Возвращает число заданий.
+func ReplayEmailSpool() int {
+	emailSpoolMu.Lock()
+	replay := emailSpoolFile + ".replay"
+	// файл уже мог остаться от прерванного повтора — тогда дочитываем его
+	if _, err := os.Stat(replay); errors.Is(err, fs.ErrNotExist) {
+		if err := os.Rename(emailSpoolFile, replay); err != nil {
+			emailSpoolMu.Unlock()
+			if !errors.Is(err, fs.ErrNotExist) {
+				logger.Log.Error("Не удалось прочитать отложенные письма", zap.String("file", emailSpoolFile), zap.Error(err))
+			}
+			return 0
+		}
+	}
+	emailSpoolMu.Unlock()
+
+	f, err := os.Open(replay)
+	if err != nil {
+		logger.Log.Error("Не удалось прочитать отложенные письма", zap.String("file", replay), zap.Error(err))
+		return 0
+	}
+	defer f.Close()
+
+	count := 0
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var job EmailJob
+		if err := json.Unmarshal(sc.Bytes(), &job); err != nil || len(job.To) == 0 {
+			logger.Log.Error("Отложенное письмо повреждено и пропущено", zap.String("file", replay), zap.Error(err))
+			continue
+		}
+		EmailQueue <- job
+		count++
+	}
+	if err := sc.Err(); err != nil {
+		logger.Log.Error("Не удалось дочитать отложенные письма — файл оставлен", zap.String("file", replay), zap.Error(err))
+		return count
+	}
+	_ = os.Remove(replay)
+	if count > 0 {
+		logger.Log.Info("Отложенные письма поставлены в очередь", zap.Int("count", count))
+	}
+	return count
+}
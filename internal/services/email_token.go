@@ -4,10 +4,13 @@ import (
 	"context"
 	"edutalks/internal/config"
 	"edutalks/internal/logger"
+	"edutalks/internal/metrics"
 	"edutalks/internal/models"
 	"edutalks/internal/repository"
+	helpers "edutalks/internal/utils/helpers"
 	"errors"
 	"math/rand"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -39,6 +42,9 @@ func ConfigureEmailWorkerFromEnv(cfg *config.Config) {
 	if v, err := strconv.Atoi(cfg.EmailBatchSize); err == nil && v > 0 {
 		emailBatchSize = v
 	}
+	if f := strings.TrimSpace(cfg.EmailSpoolFile); f != "" {
+		emailSpoolFile = f
+	}
 	logger.Log.Info("Email-воркер: применены настройки из .env",
 		zap.Duration("send_interval", emailSendInterval),
 		zap.Int("max_retries", emailMaxRetries),
@@ -50,12 +56,80 @@ func ConfigureEmailWorkerFromEnv(cfg *config.Config) {
 type EmailTokenService struct {
 	repo     *repository.EmailTokenRepository
 	userRepo *repository.UserRepository
+
+	maxOutstanding int
+	ttl            time.Duration
+	resendCooldown time.Duration
+	webURL         string
+	appURL         string
 }
 
-func NewEmailTokenService(repo *repository.EmailTokenRepository, userRepo *repository.UserRepository) *EmailTokenService {
-	return &EmailTokenService{repo: repo, userRepo: userRepo}
+func NewEmailTokenService(repo *repository.EmailTokenRepository, userRepo *repository.UserRepository, cfg *config.Config) *EmailTokenService {
+	s := &EmailTokenService{
+		repo:           repo,
+		userRepo:       userRepo,
+		maxOutstanding: 3,
+		ttl:            24 * time.Hour,
+		resendCooldown: 5 * time.Minute,
+		webURL:         strings.TrimSpace(cfg.EmailVerifyWebURL),
+		appURL:         strings.TrimSpace(cfg.EmailVerifyAppURL),
+	}
+	if v, err := strconv.Atoi(cfg.EmailTokenMaxOutstanding); err == nil && v > 0 {
+		s.maxOutstanding = v
+	}
+	if d, err := time.ParseDuration(cfg.EmailVerifyTokenTTL); err == nil && d > 0 {
+		s.ttl = d
+	}
+	if d, err := time.ParseDuration(cfg.EmailVerifyResendCooldown); err == nil && d >= 0 {
+		s.resendCooldown = d
+	}
+	if s.webURL == "" {
+		s.webURL = strings.TrimRight(cfg.SiteURL, "/") + "/verify-email"
+	}
+	return s
 }
 
+// VerificationLinks — ссылка подтверждения для браузера и (если настроен) deep link в приложение.
+func (s *EmailTokenService) VerificationLinks(token string) (web, app string) {
+	web = appendTokenParam(s.webURL, token)
+	if s.appURL != "" {
+		app = appendTokenParam(s.appURL, token)
+	}
+	return web, app
+}
+
+func appendTokenParam(base, token string) string {
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	return base + sep + "token=" + url.QueryEscape(token)
+}
+
+// TTL — срок действия токена подтверждения.
+func (s *EmailTokenService) TTL() time.Duration { return s.ttl }
+
+// TTLLabel — срок действия для текста письма на языке locale («24 ч», «30 min»).
+func (s *EmailTokenService) TTLLabel(locale string) string {
+	return helpers.FormatTTL(locale, s.ttl)
+}
+
+// ResendCooldown — минимальная пауза между письмами подтверждения одному пользователю.
+func (s *EmailTokenService) ResendCooldown() time.Duration { return s.resendCooldown }
+
+// Воронка подтверждения email: выдано → подтверждено / истекло.
+var (
+	emailTokensIssued    = metrics.NewCounter("email_verification_tokens_issued_total", "Выдано токенов подтверждения email")
+	emailTokensConfirmed = metrics.NewCounter("email_verification_tokens_confirmed_total", "Подтверждено токенов email")
+	emailTokensExpired   = metrics.NewCounter("email_verification_tokens_expired_total", "Истёкших токенов email (попытки подтверждения и очистка)")
+)
+
+// Отправка писем: успешные и неудачные попытки (retry — будет повтор, failed — попытки исчерпаны).
+var (
+	emailsSent        = metrics.NewCounter("email_sent_total", "Писем принято SMTP")
+	emailSendFailures = metrics.NewCounterVec("email_send_failures_total", "Ошибок отправки писем", "outcome")
+)
+
 var (
 	ErrTokenInvalid = errors.New("неверный токен")
 	ErrTokenExpired = errors.New("токен истёк")
@@ -63,7 +137,7 @@ var (
 
 func (s *EmailTokenService) GenerateToken(ctx context.Context, userID int) (*models.EmailVerificationToken, error) {
 	token := uuid.New().String()
-	expires := time.Now().Add(24 * time.Hour)
+	expires := time.Now().Add(s.ttl)
 
 	t := &models.EmailVerificationToken{
 		UserID:    userID,
@@ -71,9 +145,10 @@ func (s *EmailTokenService) GenerateToken(ctx context.Context, userID int) (*mod
 		ExpiresAt: expires,
 		CreatedAt: time.Now(),
 	}
-	if err := s.repo.SaveToken(ctx, t); err != nil {
+	if err := s.repo.SaveToken(ctx, t, s.maxOutstanding); err != nil {
 		return nil, err
 	}
+	emailTokensIssued.Inc()
 	return t, nil
 }
 
@@ -83,6 +158,7 @@ func (s *EmailTokenService) ConfirmToken(ctx context.Context, token string) erro
 		return ErrTokenInvalid
 	}
 	if t.ExpiresAt.Before(time.Now()) {
+		emailTokensExpired.Inc()
 		return ErrTokenExpired
 	}
 	if t.Confirmed {
@@ -94,6 +170,22 @@ func (s *EmailTokenService) ConfirmToken(ctx context.Context, token string) erro
 	if err := s.userRepo.SetEmailVerified(ctx, t.UserID, true); err != nil {
 		return err
 	}
+	emailTokensConfirmed.Inc()
+	_ = s.repo.DeleteOutstandingByUserID(ctx, t.UserID)
+	return nil
+}
+
+// Cleanup — удаляет истёкшие и давно подтверждённые токены.
+func (s *EmailTokenService) Cleanup(ctx context.Context) error {
+	expired, confirmed, err := s.repo.Cleanup(ctx, 7*24*time.Hour)
+	if err != nil {
+		return err
+	}
+	emailTokensExpired.Add(float64(expired))
+	logger.Log.Debug("Очистка токенов подтверждения email",
+		zap.Int64("expired_removed", expired),
+		zap.Int64("confirmed_removed", confirmed),
+	)
 	return nil
 }
 
@@ -106,73 +198,177 @@ type EmailJob struct {
 	Subject string
 	Body    string
 	IsHTML  bool
+	// CampaignID — рассылка администратора, к которой относится письмо (для статистики доставки)
+	CampaignID *int64
 }
 
 var (
 	EmailQueue = make(chan EmailJob, 100)
 	closeOnce  sync.Once
+
+	emailStop     = make(chan struct{})
+	emailIntakeWG sync.WaitGroup
+	emailSendWG   sync.WaitGroup
 )
 
-// StartEmailWorker — воркер с глобальным троттлингом, ретраями и автонарезкой по batch size.
-func StartEmailWorker(id int, emailService *EmailService) {
+// Сколько письмо «занято» воркером: если процесс упал посреди отправки,
+// запись снова станет доступной по истечении этого времени.
+const emailClaimLease = 10 * time.Minute
+
+// StartEmailWorker — воркер поверх таблицы email_outbox.
+// Задания из EmailQueue нарезаются по batch size и сохраняются в БД, а отправка идёт
+// из outbox с глобальным троттлингом, ретраями и backoff — так письма переживают рестарт.
+func StartEmailWorker(id int, emailService *EmailService, outbox *repository.EmailOutboxRepository) {
+	emailIntakeWG.Add(1)
 	go func(workerID int) {
+		defer emailIntakeWG.Done()
+		for job := range EmailQueue {
+			persistEmailJob(workerID, emailService, outbox, job)
+		}
+		logger.Log.Info("Email-воркер: приём заданий остановлен", zap.Int("worker_id", workerID))
+	}(id)
+
+	emailSendWG.Add(1)
+	go func(workerID int) {
+		defer emailSendWG.Done()
 		logger.Log.Info("Сервис: email-воркер запущен", zap.Int("worker_id", workerID))
 
-		ticker := time.NewTicker(emailSendInterval)
+		interval := emailSendInterval
+		if interval <= 0 {
+			interval = time.Second
+		}
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
-		for job := range EmailQueue {
-			<-ticker.C // квота перед обработкой задания
-
-			batches := ChunkEmails(job.To, emailBatchSize)
-			for bi, batch := range batches {
-				var err error
-				for attempt := 0; attempt <= emailMaxRetries; attempt++ {
-					if job.IsHTML {
-						err = emailService.SendHTML(batch, job.Subject, job.Body)
-					} else {
-						err = emailService.Send(batch, job.Subject, job.Body)
-					}
-					if err == nil {
-						logger.Log.Info("Письмо отправлено (SMTP accepted)",
-							zap.Int("worker_id", workerID),
-							zap.Int("batch_index", bi),
-							zap.Int("batch_size", len(batch)),
-							zap.String("subject", job.Subject),
-						)
-						break
-					}
-					if !isTempSMTPError(err) || attempt == emailMaxRetries {
-						logger.Log.Error("Не удалось отправить письмо",
-							zap.Int("worker_id", workerID),
-							zap.Int("batch_index", bi),
-							zap.Int("batch_size", len(batch)),
-							zap.String("subject", job.Subject),
-							zap.Int("attempt", attempt),
-							zap.Error(err),
-						)
-						break
-					}
-					// backoff + джиттер
-					sleep := emailBaseBackoff * time.Duration(1<<attempt)
-					jitter := time.Duration(rand.Int63n(int64(emailBaseBackoff / 2)))
-					time.Sleep(sleep + jitter)
-				}
-
-				// Пауза между батчами (кроме последнего), чтобы сгладить поток
-				if bi < len(batches)-1 && emailSendInterval > 0 {
-					time.Sleep(emailSendInterval)
-				}
+		for {
+			select {
+			case <-emailStop:
+				logger.Log.Info("Email-воркер остановлен", zap.Int("worker_id", workerID))
+				return
+			case <-ticker.C: // квота перед обработкой задания
 			}
+			processNextEmail(workerID, emailService, outbox)
 		}
-		logger.Log.Info("Email-воркер остановлен", zap.Int("worker_id", workerID))
 	}(id)
 }
 
-// StopEmailWorkers — корректно закрывает очередь (воркеры завершатся сами).
+// persistEmailJob — кладёт задание в outbox батчами; если БД недоступна, отправляем сразу,
+// чтобы не потерять письмо, а если и SMTP недоступен — откладываем на диск.
+func persistEmailJob(workerID int, emailService *EmailService, outbox *repository.EmailOutboxRepository, job EmailJob) {
+	ctx := context.Background()
+	for bi, batch := range ChunkEmails(job.To, emailBatchSize) {
+		if _, err := outbox.EnqueueCampaign(ctx, job.CampaignID, batch, job.Subject, job.Body, job.IsHTML); err == nil {
+			continue
+		}
+		logger.Log.Warn("Email-воркер: не удалось сохранить письмо в outbox, отправляем напрямую",
+			zap.Int("worker_id", workerID),
+			zap.Int("batch_index", bi),
+			zap.String("subject", job.Subject),
+		)
+		var err error
+		if job.IsHTML {
+			err = emailService.SendHTML(batch, job.Subject, job.Body)
+		} else {
+			err = emailService.Send(batch, job.Subject, job.Body)
+		}
+		if err != nil {
+			emailSendFailures.WithLabelValues("failed").Inc()
+			logger.Log.Error("Не удалось отправить письмо",
+				zap.Int("worker_id", workerID),
+				zap.Int("batch_size", len(batch)),
+				zap.String("subject", job.Subject),
+				zap.Error(err),
+			)
+			_ = spoolEmailJob(EmailJob{To: batch, Subject: job.Subject, Body: job.Body, IsHTML: job.IsHTML, CampaignID: job.CampaignID})
+			continue
+		}
+		emailsSent.Inc()
+	}
+}
+
+// processNextEmail — отправляет одно письмо из outbox и фиксирует результат.
+func processNextEmail(workerID int, emailService *EmailService, outbox *repository.EmailOutboxRepository) {
+	ctx := context.Background()
+
+	m, err := outbox.ClaimNext(ctx, emailClaimLease)
+	if err != nil || m == nil {
+		return
+	}
+
+	if m.IsHTML {
+		err = emailService.SendHTML(m.Recipients, m.Subject, m.Body)
+	} else {
+		err = emailService.Send(m.Recipients, m.Subject, m.Body)
+	}
+	if err == nil {
+		emailsSent.Inc()
+		_ = outbox.MarkSent(ctx, m.ID)
+		logger.Log.Info("Письмо отправлено (SMTP accepted)",
+			zap.Int("worker_id", workerID),
+			zap.Int64("outbox_id", m.ID),
+			zap.Int("batch_size", len(m.Recipients)),
+			zap.String("subject", m.Subject),
+		)
+		return
+	}
+
+	// attempts уже увеличен в ClaimNext: первая попытка — 1
+	if !isTempSMTPError(err) || m.Attempts > emailMaxRetries {
+		emailSendFailures.WithLabelValues("failed").Inc()
+		_ = outbox.MarkFailed(ctx, m.ID, err.Error())
+		logger.Log.Error("Не удалось отправить письмо",
+			zap.Int("worker_id", workerID),
+			zap.Int64("outbox_id", m.ID),
+			zap.Int("batch_size", len(m.Recipients)),
+			zap.String("subject", m.Subject),
+			zap.Int("attempt", m.Attempts),
+			zap.Error(err),
+		)
+		return
+	}
+
+	// backoff + джиттер
+	emailSendFailures.WithLabelValues("retry").Inc()
+	next := time.Now().Add(emailBackoff(m.Attempts - 1))
+	_ = outbox.MarkRetry(ctx, m.ID, err.Error(), next)
+	logger.Log.Warn("Временная ошибка SMTP, письмо будет повторено",
+		zap.Int("worker_id", workerID),
+		zap.Int64("outbox_id", m.ID),
+		zap.Int("attempt", m.Attempts),
+		zap.Time("next_attempt_at", next),
+		zap.Error(err),
+	)
+}
+
+func emailBackoff(attempt int) time.Duration {
+	sleep := emailBaseBackoff * time.Duration(1<<attempt)
+	if half := int64(emailBaseBackoff / 2); half > 0 {
+		sleep += time.Duration(rand.Int63n(half))
+	}
+	return sleep
+}
+
+// StopEmailWorkers — закрывает очередь, дожидается сохранения оставшихся заданий в outbox
+// и останавливает отправку, дав доотправить текущие письма (недоотправленное останется в БД
+// до следующего запуска). Каждый этап ждёт не дольше SHUTDOWN_TIMEOUT; задания, которые
+// не успели попасть в outbox, откладываются на диск.
 func StopEmailWorkers() {
 	closeOnce.Do(func() {
 		close(EmailQueue)
+		if !waitStopped("email-intake", waitGroupDone(&emailIntakeWG)) {
+			spooled := 0
+			for job := range EmailQueue {
+				if spoolEmailJob(job) == nil {
+					spooled++
+				}
+			}
+			logger.Log.Warn("Email-очередь: не сохранённые в outbox задания отложены на диск", zap.Int("count", spooled))
+		}
+		close(emailStop)
+		if !waitStopped("email-sender", waitGroupDone(&emailSendWG)) {
+			logger.Log.Warn("Письма, взятые в отправку, будут повторены после истечения аренды",
+				zap.Duration("lease", emailClaimLease))
+		}
 		logger.Log.Info("Email-очередь закрыта")
 	})
 }
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// ErrUnsubscribeTokenInvalid — токен отписки не разобран или подпись не совпала.
+var ErrUnsubscribeTokenInvalid = errors.New("неверная ссылка отписки")
+
+// UnsubscribeToken — подписанный токен отписки адреса: base64url(email).base64url(HMAC-SHA256).
+// Срока действия нет: ссылка из старого письма тоже должна работать.
+func (s *EmailService) UnsubscribeToken(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(email)) + "." + enc.EncodeToString(s.unsubscribeMAC(email))
+}
+
+// ParseUnsubscribeToken — проверяет подпись и возвращает адрес, который нужно отписать.
+func (s *EmailService) ParseUnsubscribeToken(token string) (string, error) {
+	if len(s.unsubscribeSecret()) == 0 {
+		return "", ErrUnsubscribeTokenInvalid
+	}
+	payload, sig, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok {
+		return "", ErrUnsubscribeTokenInvalid
+	}
+	enc := base64.RawURLEncoding
+	rawEmail, err := enc.DecodeString(payload)
+	if err != nil {
+		return "", ErrUnsubscribeTokenInvalid
+	}
+	rawSig, err := enc.DecodeString(sig)
+	if err != nil {
+		return "", ErrUnsubscribeTokenInvalid
+	}
+	email := string(rawEmail)
+	if email == "" || !hmac.Equal(rawSig, s.unsubscribeMAC(email)) {
+		return "", ErrUnsubscribeTokenInvalid
+	}
+	return email, nil
+}
+
+// unsubscribeSecret — ключ подписи из текущей конфигурации: UNSUBSCRIBE_SECRET, если не задан —
+// выведенный из JWT_SECRET через HKDF. Сам JWT_SECRET не используется: токен отписки не должен
+// проверяться тем же ключом, что и access-токены.
+func (s *EmailService) unsubscribeSecret() []byte {
+	cfg := s.cfg.Get()
+	if cfg.UnsubscribeSecret != "" {
+		return []byte(cfg.UnsubscribeSecret)
+	}
+	if cfg.JWTSecret == "" {
+		return nil
+	}
+	key := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(cfg.JWTSecret), nil, []byte("edutalks/unsubscribe/v1")), key); err != nil {
+		return nil
+	}
+	return key
+}
+
+func (s *EmailService) unsubscribeMAC(email string) []byte {
+	mac := hmac.New(sha256.New, s.unsubscribeSecret())
+	mac.Write([]byte("unsubscribe:" + email))
+	return mac.Sum(nil)
+}
+
+// listUnsubscribe — значение заголовка List-Unsubscribe: mailto и (если есть ключ) персональная ссылка.
+func (s *EmailService) listUnsubscribe(recipient string) string {
+	h := "<mailto:unsubscribe@edutalks.ru?subject=unsubscribe>"
+	if s.unsubscribeURL == "" || len(s.unsubscribeSecret()) == 0 {
+		return h
+	}
+	return h + ", <" + s.unsubscribeURL + "?token=" + url.QueryEscape(s.UnsubscribeToken(recipient)) + ">"
+}
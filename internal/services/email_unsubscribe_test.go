@@ -0,0 +1,36 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"edutalks/internal/config"
+)
+
+func TestUnsubscribeToken(t *testing.T) {
+	live := config.NewHolder(&config.Config{JWTSecret: "jwt-secret"})
+	s := &EmailService{cfg: live}
+
+	token := s.UnsubscribeToken(" Ivan@Example.com ")
+	email, err := s.ParseUnsubscribeToken(token)
+	if err != nil || email != "ivan@example.com" {
+		t.Fatalf("ParseUnsubscribeToken = %q, %v", email, err)
+	}
+
+	// подпись самим JWT_SECRET не принимается: ключ отписки выведен из него, а не совпадает
+	mac := hmac.New(sha256.New, []byte("jwt-secret"))
+	mac.Write([]byte("unsubscribe:ivan@example.com"))
+	enc := base64.RawURLEncoding
+	forged := enc.EncodeToString([]byte("ivan@example.com")) + "." + enc.EncodeToString(mac.Sum(nil))
+	if _, err := s.ParseUnsubscribeToken(forged); err == nil {
+		t.Error("токен, подписанный JWT_SECRET, принят")
+	}
+
+	// отдельный UNSUBSCRIBE_SECRET заменяет выведенный ключ
+	live2 := config.NewHolder(&config.Config{JWTSecret: "jwt-secret", UnsubscribeSecret: "other"})
+	if _, err := (&EmailService{cfg: live2}).ParseUnsubscribeToken(token); err == nil {
+		t.Error("токен принят с другим ключом")
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// featureFlagsTTL — как долго GET /api/flags отвечает из памяти; правки через админку сбрасывают кэш сразу.
+const featureFlagsTTL = 15 * time.Second
+
+var featureFlagKeyRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+var (
+	ErrFeatureFlagNotFound = errors.New("флаг не найден")
+	ErrFeatureFlagExists   = errors.New("флаг с таким ключом уже есть")
+	ErrFeatureFlagKey      = errors.New("ключ флага: латиница в нижнем регистре, цифры, _ . - (до 64 символов)")
+	ErrFeatureFlagRollout  = errors.New("процент выкатывания: от 0 до 100")
+)
+
+type FeatureFlagService struct {
+	repo *repository.FeatureFlagRepository
+
+	mu       sync.Mutex
+	cached   []models.FeatureFlag
+	cachedAt time.Time
+}
+
+func NewFeatureFlagService(repo *repository.FeatureFlagRepository) *FeatureFlagService {
+	return &FeatureFlagService{repo: repo}
+}
+
+// Evaluate — значения всех флагов для пользователя (0 — аноним). Если база недоступна,
+// отдаёт последний загруженный набор.
+func (s *FeatureFlagService) Evaluate(ctx context.Context, userID int) (map[string]bool, error) {
+	flags, err := s.flags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]bool, len(flags))
+	for _, f := range flags {
+		out[f.Key] = flagOn(f, userID)
+	}
+	return out, nil
+}
+
+// flagOn — включён ли флаг для пользователя: при частичном выкатывании пользователь попадает
+// в одну и ту же корзину 0..99 для флага, а разные флаги выкатываются на разных пользователей.
+func flagOn(f models.FeatureFlag, userID int) bool {
+	switch {
+	case !f.Enabled || f.RolloutPercent <= 0:
+		return false
+	case f.RolloutPercent >= 100:
+		return true
+	case userID == 0:
+		return false
+	}
+	h := fnv.New32a()
+	h.Write([]byte(f.Key + ":" + strconv.Itoa(userID)))
+	return int(h.Sum32()%100) < f.RolloutPercent
+}
+
+func (s *FeatureFlagService) flags(ctx context.Context) ([]models.FeatureFlag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && time.Since(s.cachedAt) < featureFlagsTTL {
+		return s.cached, nil
+	}
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		if s.cached != nil {
+			logger.WithCtx(ctx).Warn("Флаги фич: отдаём устаревший набор", zap.Error(err))
+			return s.cached, nil
+		}
+		return nil, err
+	}
+	s.cached, s.cachedAt = list, time.Now()
+	return list, nil
+}
+
+func (s *FeatureFlagService) invalidate() {
+	s.mu.Lock()
+	s.cachedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+func (s *FeatureFlagService) normalize(key string, req models.FeatureFlagRequest) (*models.FeatureFlag, error) {
+	f := &models.FeatureFlag{
+		Key:            strings.ToLower(strings.TrimSpace(key)),
+		Description:    strings.TrimSpace(req.Description),
+		Enabled:        req.Enabled,
+		RolloutPercent: 100,
+	}
+	if !featureFlagKeyRe.MatchString(f.Key) {
+		return nil, ErrFeatureFlagKey
+	}
+	if req.RolloutPercent != nil {
+		f.RolloutPercent = *req.RolloutPercent
+	}
+	// тег validate проверяет только HTTP-запрос; сервис не полагается на него
+	if f.RolloutPercent < 0 || f.RolloutPercent > 100 {
+		return nil, ErrFeatureFlagRollout
+	}
+	return f, nil
+}
+
+// List — все флаги с настройками (для админки), без кэша.
+func (s *FeatureFlagService) List(ctx context.Context) ([]models.FeatureFlag, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *FeatureFlagService) Create(ctx context.Context, req models.FeatureFlagRequest) (*models.FeatureFlag, error) {
+	f, err := s.normalize(req.Key, req)
+	if err != nil {
+		return nil, err
+	}
+	out, created, err := s.repo.Create(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrFeatureFlagExists
+	}
+	s.invalidate()
+	logger.WithCtx(ctx).Info("Флаг фичи создан", zap.String("key", out.Key),
+		zap.Bool("enabled", out.Enabled), zap.Int("rollout_percent", out.RolloutPercent))
+	return out, nil
+}
+
+func (s *FeatureFlagService) Update(ctx context.Context, key string, req models.FeatureFlagRequest) (*models.FeatureFlag, error) {
+	f, err := s.normalize(key, req)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.repo.Update(ctx, f)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrFeatureFlagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	logger.WithCtx(ctx).Info("Флаг фичи изменён", zap.String("key", out.Key),
+		zap.Bool("enabled", out.Enabled), zap.Int("rollout_percent", out.RolloutPercent))
+	return out, nil
+}
+
+func (s *FeatureFlagService) Delete(ctx context.Context, key string) error {
+	ok, err := s.repo.Delete(ctx, strings.ToLower(strings.TrimSpace(key)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrFeatureFlagNotFound
+	}
+	s.invalidate()
+	logger.WithCtx(ctx).Info("Флаг фичи удалён", zap.String("key", key))
+	return nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"edutalks/internal/models"
+)
+
+func TestFlagOn(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		enabled bool
+		percent int
+		userID  int
+		want    bool
+	}{
+		{"disabled", false, 100, 1, false},
+		{"zero percent", true, 0, 1, false},
+		{"full rollout", true, 100, 1, true},
+		{"full rollout anonymous", true, 100, 0, true},
+		{"partial rollout anonymous", true, 50, 0, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := models.FeatureFlag{Key: "new-editor", Enabled: tc.enabled, RolloutPercent: tc.percent}
+			if got := flagOn(f, tc.userID); got != tc.want {
+				t.Errorf("flagOn = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFlagOnBuckets(t *testing.T) {
+	const users = 10000
+	for _, percent := range []int{1, 10, 50, 90} {
+		t.Run(fmt.Sprint(percent), func(t *testing.T) {
+			f := models.FeatureFlag{Key: "new-editor", Enabled: true, RolloutPercent: percent}
+			wider := f
+			wider.RolloutPercent = percent + 5
+			on := 0
+			for id := 1; id <= users; id++ {
+				got := flagOn(f, id)
+				if got != flagOn(f, id) {
+					t.Fatalf("user %d: результат меняется между вызовами", id)
+				}
+				// увеличение процента не выключает тех, кому флаг уже включён
+				if got && !flagOn(wider, id) {
+					t.Fatalf("user %d: выпал при %d%%", id, wider.RolloutPercent)
+				}
+				if got {
+					on++
+				}
+			}
+			if share := on * 100 / users; share < percent-2 || share > percent+2 {
+				t.Errorf("включено у %d%% пользователей, want ~%d%%", share, percent)
+			}
+		})
+	}
+
+	// разные флаги выкатываются на разных пользователей
+	a := models.FeatureFlag{Key: "new-editor", Enabled: true, RolloutPercent: 50}
+	b := models.FeatureFlag{Key: "dark-mode", Enabled: true, RolloutPercent: 50}
+	same := 0
+	for id := 1; id <= users; id++ {
+		if flagOn(a, id) == flagOn(b, id) {
+			same++
+		}
+	}
+	if same == users {
+		t.Error("флаги включены у одних и тех же пользователей")
+	}
+}
+
+func TestFeatureFlagNormalize(t *testing.T) {
+	s := &FeatureFlagService{}
+	pct := func(v int) *int { return &v }
+	for _, tc := range []struct {
+		name    string
+		key     string
+		percent *int
+		want    int
+		err     error
+	}{
+		{"default rollout", " New-Editor ", nil, 100, nil},
+		{"bounds", "a", pct(0), 0, nil},
+		{"full", "a", pct(100), 100, nil},
+		{"negative", "a", pct(-1), 0, ErrFeatureFlagRollout},
+		{"over 100", "a", pct(101), 0, ErrFeatureFlagRollout},
+		{"bad key", "Ключ", nil, 0, ErrFeatureFlagKey},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := s.normalize(tc.key, models.FeatureFlagRequest{RolloutPercent: tc.percent})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if err == nil && f.RolloutPercent != tc.want {
+				t.Errorf("RolloutPercent = %d, want %d", f.RolloutPercent, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils/clamav"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrScanUnavailable — clamd настроен, но не ответил: файл не принимаем непроверенным.
+	ErrScanUnavailable = errors.New("проверка файла на вирусы недоступна, попробуйте позже")
+	ErrRescanRunning   = errors.New("повторная проверка файлов уже выполняется")
+)
+
+// ScanResult — итог проверки одного файла.
+type ScanResult struct {
+	Status    string // models.DocumentScan*
+	Signature string // имя угрозы для infected
+}
+
+func (r ScanResult) Infected() bool { return r.Status == models.DocumentScanInfected }
+
+// FileScanService — антивирусная проверка загружаемых файлов через clamd (CLAMAV_ADDRESS).
+// Без адреса проверка выключена: файлы принимаются со статусом skipped.
+type FileScanService struct {
+	client  *clamav.Client
+	docs    *repository.DocumentRepository
+	running atomic.Bool
+}
+
+func NewFileScanService(docs *repository.DocumentRepository, cfg *config.Config) *FileScanService {
+	timeout, _ := time.ParseDuration(cfg.ClamAVTimeout)
+	return &FileScanService{client: clamav.New(cfg.ClamAVAddress, timeout), docs: docs}
+}
+
+func (s *FileScanService) Enabled() bool { return s.client != nil }
+
+// ScanFile — проверяет файл на диске; ErrScanUnavailable — clamd не смог проверить.
+func (s *FileScanService) ScanFile(ctx context.Context, path string) (ScanResult, error) {
+	if s.client == nil {
+		return ScanResult{Status: models.DocumentScanSkipped}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return ScanResult{}, err
+	}
+	defer f.Close()
+
+	start := time.Now()
+	sig, err := s.client.Scan(ctx, f)
+	if err != nil {
+		logger.WithCtx(ctx).Error("Антивирус: ошибка проверки", zap.String("path", path), zap.Error(err))
+		return ScanResult{Status: models.DocumentScanError}, ErrScanUnavailable
+	}
+	if sig != "" {
+		logger.WithCtx(ctx).Warn("Антивирус: обнаружена угроза", zap.String("path", path), zap.String("signature", sig))
+		return ScanResult{Status: models.DocumentScanInfected, Signature: sig}, nil
+	}
+	logger.WithCtx(ctx).Debug("Антивирус: файл чист", zap.String("path", path), zap.Duration("took", time.Since(start)))
+	return ScanResult{Status: models.DocumentScanClean}, nil
+}
+
+// RescanDocument — повторная проверка файла одного документа; результат сохраняется.
+func (s *FileScanService) RescanDocument(ctx context.Context, id int) (ScanResult, error) {
+	if s.client == nil {
+		return ScanResult{}, ErrScanUnavailable
+	}
+	doc, err := s.docs.GetDocumentByID(ctx, id)
+	if err != nil {
+		return ScanResult{}, err
+	}
+	res, err := s.ScanFile(ctx, doc.Filepath)
+	if err != nil {
+		_ = s.docs.SetScanResult(ctx, id, models.DocumentScanError, nil)
+		return res, err
+	}
+	var sig *string
+	if res.Infected() {
+		sig = &res.Signature
+	}
+	return res, s.docs.SetScanResult(ctx, id, res.Status, sig)
+}
+
+// StartRescan — повторная проверка уже загруженных файлов в фоне (например, после обновления баз);
+// итог — в логе. Заражённые помечаются infected и перестают скачиваться. Одновременно — один запуск.
+func (s *FileScanService) StartRescan(ctx context.Context, onlyUnchecked bool) error {
+	if s.client == nil {
+		return ErrScanUnavailable
+	}
+	if !s.running.CompareAndSwap(false, true) {
+		return ErrRescanRunning
+	}
+	go func() {
+		defer s.running.Store(false)
+		s.rescan(context.WithoutCancel(ctx), onlyUnchecked)
+	}()
+	return nil
+}
+
+func (s *FileScanService) rescan(ctx context.Context, onlyUnchecked bool) {
+	log := logger.WithCtx(ctx)
+	targets, err := s.docs.ListScanTargets(ctx, onlyUnchecked)
+	if err != nil {
+		log.Error("Антивирус: не удалось получить список файлов", zap.Error(err))
+		return
+	}
+
+	var clean, errs int
+	infected := []int{}
+	for _, t := range targets {
+		res, err := s.ScanFile(ctx, t.Filepath)
+		if err != nil {
+			errs++
+			if !errors.Is(err, ErrScanUnavailable) {
+				log.Warn("Антивирус: файл документа недоступен", zap.Int("doc_id", t.ID), zap.Error(err))
+			}
+			_ = s.docs.SetScanResult(ctx, t.ID, models.DocumentScanError, nil)
+			continue
+		}
+
+		var sig *string
+		if res.Infected() {
+			sig = &res.Signature
+			infected = append(infected, t.ID)
+		} else {
+			clean++
+		}
+		_ = s.docs.SetScanResult(ctx, t.ID, res.Status, sig)
+	}
+
+	log.Info("Антивирус: повторная проверка завершена",
+		zap.Int("total", len(targets)), zap.Int("clean", clean),
+		zap.Ints("infected_doc_ids", infected), zap.Int("errors", errs))
+}
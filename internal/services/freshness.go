@@ -0,0 +1,38 @@
+package services
+
+import (
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// contentFreshWindow — сколько после содержательной правки материал помечается is_updated_recently.
+var contentFreshWindow = 7 * 24 * time.Hour
+
+// ConfigureFreshness — вызови один раз при старте (после LoadConfig).
+func ConfigureFreshness(cfg *config.Config) {
+	if d, err := time.ParseDuration(cfg.ContentFreshWindow); err == nil && d > 0 {
+		contentFreshWindow = d
+	}
+	logger.Log.Info("Свежесть контента: окно «обновлено»", zap.Duration("window", contentFreshWindow))
+}
+
+func updatedRecently(contentUpdatedAt *time.Time) bool {
+	return contentUpdatedAt != nil && time.Since(*contentUpdatedAt) < contentFreshWindow
+}
+
+func markArticlesFreshness(list []*models.Article) {
+	for _, a := range list {
+		a.IsUpdatedRecently = updatedRecently(a.ContentUpdatedAt)
+	}
+}
+
+func markDocumentsFreshness(list []*models.Document) {
+	for _, d := range list {
+		d.IsUpdatedRecently = updatedRecently(d.ContentUpdatedAt)
+	}
+}
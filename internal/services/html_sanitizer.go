@@ -0,0 +1,159 @@
+package services
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"edutalks/internal/config"
+	"edutalks/internal/models"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// DefaultHTMLIframeHosts — откуда можно встраивать плееры, если HTML_IFRAME_HOSTS не задан.
+const DefaultHTMLIframeHosts = "www.youtube.com,youtube.com,www.youtube-nocookie.com,rutube.ru"
+
+// unsafeHTMLElements — не разрешаются через конфиг ни при каких настройках;
+// iframe настраивается только списком хостов.
+var unsafeHTMLElements = []string{
+	"script", "style", "iframe", "frame", "frameset", "object", "embed", "applet",
+	"form", "input", "button", "textarea", "select", "link", "meta", "base", "svg", "math",
+}
+
+// mediaURLRe — poster и src у source bluemonday как ссылки не проверяет: только http(s) и пути сайта.
+var mediaURLRe = regexp.MustCompile(`^(https?://[^\s"]+|/[^/\s"][^\s"]*)$`)
+
+// emptyIframeRe — iframe, у которого очистка убрала src (чужой хост): такой удаляется целиком.
+var emptyIframeRe = regexp.MustCompile(`<iframe(\s[^>]*)?>\s*</iframe>`)
+
+// HTMLSanitizer — очистка HTML статей по профилю из конфига: базовый UGC-профиль,
+// дополнительные элементы и атрибуты, iframe с разрешённых хостов и video.
+type HTMLSanitizer struct {
+	profile models.HTMLProfile
+	policy  *bluemonday.Policy
+}
+
+// NewHTMLSanitizer — профиль из HTML_EXTRA_ELEMENTS, HTML_EXTRA_ATTRS ("элемент:атр1|атр2" через
+// запятую), HTML_IFRAME_HOSTS ("none" — без iframe) и HTML_ALLOW_VIDEO; небезопасное пропускается.
+func NewHTMLSanitizer(cfg *config.Config) *HTMLSanitizer {
+	p := models.HTMLProfile{
+		ExtraElements: []string{},
+		ExtraAttrs:    map[string][]string{},
+		IframeHosts:   []string{},
+		AllowVideo:    strings.EqualFold(strings.TrimSpace(cfg.HTMLAllowVideo), "true"),
+	}
+	for _, el := range splitList(cfg.HTMLExtraElements) {
+		if safeHTMLElement(el) && !slices.Contains(p.ExtraElements, el) {
+			p.ExtraElements = append(p.ExtraElements, el)
+		}
+	}
+	for _, item := range strings.Split(cfg.HTMLExtraAttrs, ",") {
+		el, raw, ok := strings.Cut(strings.TrimSpace(item), ":")
+		el = strings.ToLower(strings.TrimSpace(el))
+		if !ok || !safeHTMLElement(el) {
+			continue
+		}
+		for _, attr := range strings.Split(raw, "|") {
+			attr = strings.ToLower(strings.TrimSpace(attr))
+			if safeHTMLAttr(attr) && !slices.Contains(p.ExtraAttrs[el], attr) {
+				p.ExtraAttrs[el] = append(p.ExtraAttrs[el], attr)
+			}
+		}
+	}
+	hosts := cfg.HTMLIframeHosts
+	if strings.TrimSpace(hosts) == "" {
+		hosts = DefaultHTMLIframeHosts
+	}
+	if !strings.EqualFold(strings.TrimSpace(hosts), "none") {
+		for _, h := range splitList(hosts) {
+			if !slices.Contains(p.IframeHosts, h) {
+				p.IframeHosts = append(p.IframeHosts, h)
+			}
+		}
+	}
+	return &HTMLSanitizer{profile: p, policy: buildHTMLPolicy(p)}
+}
+
+func buildHTMLPolicy(p models.HTMLProfile) *bluemonday.Policy {
+	pol := bluemonday.UGCPolicy()
+	pol.AllowElements("img")
+	pol.AllowAttrs("src", "alt").OnElements("img")
+
+	if len(p.ExtraElements) > 0 {
+		pol.AllowElements(p.ExtraElements...)
+	}
+	for el, attrs := range p.ExtraAttrs {
+		pol.AllowAttrs(attrs...).OnElements(el)
+	}
+	if p.AllowVideo {
+		pol.AllowAttrs("src", "width", "height", "preload", "controls", "muted", "loop", "playsinline").OnElements("video")
+		pol.AllowAttrs("poster").Matching(mediaURLRe).OnElements("video")
+		pol.AllowAttrs("src").Matching(mediaURLRe).OnElements("source")
+		pol.AllowAttrs("type").OnElements("source")
+	}
+	if len(p.IframeHosts) > 0 {
+		quoted := make([]string, len(p.IframeHosts))
+		for i, h := range p.IframeHosts {
+			quoted[i] = regexp.QuoteMeta(h)
+		}
+		src := regexp.MustCompile(`^https://(` + strings.Join(quoted, "|") + `)(/|$)`)
+		pol.AllowAttrs("src").Matching(src).OnElements("iframe")
+		pol.AllowAttrs("width", "height", "title", "frameborder", "allowfullscreen", "allow").OnElements("iframe")
+	}
+	return pol
+}
+
+// Sanitize — очищенный HTML.
+func (s *HTMLSanitizer) Sanitize(raw string) string {
+	out := s.policy.Sanitize(raw)
+	if len(s.profile.IframeHosts) > 0 {
+		out = emptyIframeRe.ReplaceAllStringFunc(out, func(tag string) string {
+			if strings.Contains(tag, ` src="`) {
+				return tag
+			}
+			return ""
+		})
+	}
+	return out
+}
+
+// Profile — текущий профиль (для админки).
+func (s *HTMLSanitizer) Profile() models.HTMLProfile {
+	return s.profile
+}
+
+func safeHTMLElement(el string) bool {
+	if el == "" || slices.Contains(unsafeHTMLElements, el) {
+		return false
+	}
+	for _, r := range el {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// safeHTMLAttr — обработчики событий, style и srcdoc не разрешаются.
+func safeHTMLAttr(attr string) bool {
+	if attr == "" || strings.HasPrefix(attr, "on") || attr == "style" || attr == "srcdoc" || attr == "formaction" {
+		return false
+	}
+	for _, r := range attr {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func splitList(s string) []string {
+	out := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
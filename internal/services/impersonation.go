@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// maxImpersonationTTL — токен поддержки не живёт дольше часа, даже если в конфиге больше.
+const maxImpersonationTTL = time.Hour
+
+var (
+	ErrImpersonateSelf  = errors.New("нельзя войти под своим аккаунтом")
+	ErrImpersonateAdmin = errors.New("нельзя войти под администратором")
+	// ErrImpersonationNotFound — входа нет, он уже завершён или истёк.
+	ErrImpersonationNotFound = errors.New("вход поддержки не найден")
+)
+
+// ImpersonationService — вход поддержки под пользователем, чтобы воспроизвести его проблему.
+// Каждая выдача токена пишется в журнал impersonations; токен привязан к отдельной сессии
+// пользователя и перестаёт действовать, как только она отозвана (Stop, выход со всех устройств).
+type ImpersonationService struct {
+	repo   *repository.ImpersonationRepository
+	users  *repository.UserRepository
+	tokens repository.TokenStore
+	cfg    *config.Holder
+	ttl    time.Duration
+}
+
+func NewImpersonationService(repo *repository.ImpersonationRepository, users *repository.UserRepository, tokens repository.TokenStore, live *config.Holder) *ImpersonationService {
+	cfg := live.Get()
+	ttl, err := time.ParseDuration(cfg.ImpersonationTTL)
+	if err != nil || ttl <= 0 || ttl > maxImpersonationTTL {
+		ttl = 15 * time.Minute
+	}
+	return &ImpersonationService{repo: repo, users: users, tokens: tokens, cfg: live, ttl: ttl}
+}
+
+// Start — короткоживущий access-токен пользователя userID для администратора adminID.
+// Под другим администратором войти нельзя: токен дал бы его права.
+func (s *ImpersonationService) Start(ctx context.Context, adminID, userID int, reason string, client models.SessionClient) (*models.ImpersonateResponse, error) {
+	log := logger.WithCtx(ctx)
+
+	if adminID == userID {
+		return nil, ErrImpersonateSelf
+	}
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == "admin" {
+		return nil, ErrImpersonateAdmin
+	}
+
+	imp := models.Impersonation{
+		AdminID:   adminID,
+		UserID:    userID,
+		Reason:    strings.TrimSpace(reason),
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if err := s.repo.Create(ctx, &imp); err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateImpersonationToken(s.cfg.Get().JWTSecret, user.ID, user.Role, adminID, imp.ID, imp.SessionID, s.ttl)
+	if err != nil {
+		log.Error("Ошибка генерации токена поддержки", zap.Error(err))
+		return nil, err
+	}
+
+	log.Warn("Администратор вошёл под пользователем",
+		zap.Int("admin_id", adminID),
+		zap.Int("target_user_id", userID),
+		zap.Int64("impersonation_id", imp.ID),
+		zap.String("reason", imp.Reason),
+	)
+	return &models.ImpersonateResponse{AccessToken: token, ExpiresAt: imp.ExpiresAt, Impersonation: imp}, nil
+}
+
+// Stop — досрочно завершает вход id: сессия отзывается, токен поддержки больше не принимается.
+func (s *ImpersonationService) Stop(ctx context.Context, id int64) error {
+	imp, err := s.repo.End(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrImpersonationNotFound
+	}
+	if err != nil {
+		return err
+	}
+	// у записей до учёта сессий токен без sid — он доживёт до expires_at (не дольше часа)
+	if imp.SessionID != 0 {
+		if err := s.tokens.RevokeSession(ctx, imp.SessionID, imp.ExpiresAt); err != nil {
+			return err
+		}
+	}
+
+	logger.WithCtx(ctx).Warn("Вход поддержки под пользователем завершён",
+		zap.Int("admin_id", imp.AdminID),
+		zap.Int("target_user_id", imp.UserID),
+		zap.Int64("impersonation_id", imp.ID),
+	)
+	return nil
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils/helpers"
+	"edutalks/internal/utils/pdf"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvoicePaymentNotFound = errors.New("платёж не найден")
+	ErrInvoiceNotPaid         = errors.New("квитанция выдаётся только по успешному платежу")
+	ErrInvoiceUnavailable     = errors.New("формирование квитанций не настроено (INVOICE_FONT)")
+)
+
+// InvoiceSeller — реквизиты продавца в квитанции.
+type InvoiceSeller struct {
+	Name    string
+	INN     string
+	OGRN    string
+	Address string
+}
+
+// InvoiceService выдаёт квитанции (PDF) по успешным платежам: номер присваивается
+// один раз, файл собирается при каждом запросе из данных платежа.
+type InvoiceService struct {
+	invoices *repository.InvoiceRepository
+	payments *repository.PaymentRepository
+	users    *repository.UserRepository
+	email    *EmailService
+
+	seller   InvoiceSeller
+	prefix   string
+	fontPath string
+
+	fontOnce sync.Once
+	font     *pdf.Font
+	fontErr  error
+}
+
+func NewInvoiceService(invoices *repository.InvoiceRepository, payments *repository.PaymentRepository, users *repository.UserRepository, email *EmailService, cfg *config.Config) *InvoiceService {
+	return &InvoiceService{
+		invoices: invoices,
+		payments: payments,
+		users:    users,
+		email:    email,
+		seller: InvoiceSeller{
+			Name:    strings.TrimSpace(cfg.InvoiceSellerName),
+			INN:     strings.TrimSpace(cfg.InvoiceSellerINN),
+			OGRN:    strings.TrimSpace(cfg.InvoiceSellerOGRN),
+			Address: strings.TrimSpace(cfg.InvoiceSellerAddress),
+		},
+		prefix:   strings.TrimSpace(cfg.InvoiceNumberPrefix),
+		fontPath: cfg.InvoiceFont,
+	}
+}
+
+// ForUser — квитанция по платежу пользователя (выдаётся при первом запросе) и её PDF.
+func (s *InvoiceService) ForUser(ctx context.Context, userID int, paymentID int64) (*models.Invoice, []byte, error) {
+	log := logger.WithCtx(ctx)
+
+	p, err := s.payments.GetByID(ctx, paymentID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && p.UserID != userID) {
+		return nil, nil, ErrInvoicePaymentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.Status != models.PaymentStatusSucceeded {
+		return nil, nil, ErrInvoiceNotPaid
+	}
+
+	user, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error("Ошибка получения пользователя для квитанции", zap.Int("user_id", userID), zap.Error(err))
+		return nil, nil, err
+	}
+	inv, err := s.invoices.Issue(ctx, p.ID, p.UserID, s.prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := s.Render(inv, p, user)
+	if err != nil {
+		log.Error("Ошибка формирования PDF квитанции", zap.String("number", inv.Number), zap.Error(err))
+		return nil, nil, err
+	}
+	return inv, data, nil
+}
+
+// IssueAndSend — выдаёт квитанцию по успешному платежу и отправляет её на почту
+// пользователя (один раз: повторные уведомления письмо не дублируют).
+func (s *InvoiceService) IssueAndSend(ctx context.Context, provider, providerPaymentID string) error {
+	log := logger.WithCtx(ctx)
+
+	p, err := s.payments.GetByProviderID(ctx, provider, providerPaymentID)
+	if err != nil {
+		return err
+	}
+	if p.Status != models.PaymentStatusSucceeded {
+		return ErrInvoiceNotPaid
+	}
+	inv, err := s.invoices.Issue(ctx, p.ID, p.UserID, s.prefix)
+	if err != nil {
+		return err
+	}
+	if inv.EmailedAt != nil {
+		log.Info("Квитанция уже отправлена", zap.String("number", inv.Number))
+		return nil
+	}
+
+	user, err := s.users.GetUserByID(ctx, p.UserID)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(user.Email) == "" {
+		log.Warn("Квитанция не отправлена: у пользователя нет email", zap.Int("user_id", user.ID), zap.String("number", inv.Number))
+		return nil
+	}
+
+	data, err := s.Render(inv, p, user)
+	if err != nil {
+		log.Error("Ошибка формирования PDF квитанции", zap.String("number", inv.Number), zap.Error(err))
+		return err
+	}
+	subject := fmt.Sprintf("%s № %s", helpers.EmailSubject(user.Locale, helpers.MailInvoice), inv.Number)
+	body := helpers.BuildInvoiceHTML(user.Locale, displayName(user), inv.Number, helpers.FormatAmount(user.Locale, p.Amount, p.Currency))
+	if err := s.email.SendWithAttachment(user.Email, subject, body, EmailAttachment{
+		Filename:    InvoiceFilename(inv),
+		ContentType: "application/pdf",
+		Data:        data,
+	}); err != nil {
+		return err
+	}
+
+	if err := s.invoices.MarkEmailed(ctx, inv.ID); err != nil {
+		return err
+	}
+	log.Info("Квитанция отправлена на почту", zap.Int("user_id", user.ID), zap.String("number", inv.Number))
+	return nil
+}
+
+// InvoiceFilename — имя PDF-файла квитанции (ASCII — для вложений и Content-Disposition).
+func InvoiceFilename(inv *models.Invoice) string {
+	return "invoice-" + inv.Number + ".pdf"
+}
+
+func displayName(u *models.User) string {
+	if name := strings.TrimSpace(u.FullName); name != "" {
+		return name
+	}
+	return u.Username
+}
+
+func (s *InvoiceService) loadFont() (*pdf.Font, error) {
+	s.fontOnce.Do(func() {
+		s.font, s.fontErr = pdf.LoadFont(s.fontPath)
+		if s.fontErr != nil {
+			logger.Log.Error("Не удалось загрузить шрифт квитанций", zap.String("path", s.fontPath), zap.Error(s.fontErr))
+		}
+	})
+	if s.fontErr != nil {
+		return nil, ErrInvoiceUnavailable
+	}
+	return s.font, nil
+}
+
+// Render — PDF квитанции (на русском: документ для бухгалтерии).
+func (s *InvoiceService) Render(inv *models.Invoice, p *models.Payment, user *models.User) ([]byte, error) {
+	font, err := s.loadFont()
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		left  = 56.0
+		right = pdf.PageWidth - 56
+	)
+	doc := pdf.New(font)
+	doc.Title = "Квитанция № " + inv.Number
+	doc.Author = s.seller.Name
+	doc.AddPage()
+
+	paidAt := p.UpdatedAt
+	if p.PaidAt != nil {
+		paidAt = *p.PaidAt
+	}
+	amount := helpers.FormatAmount(helpers.LocaleRU, p.Amount, p.Currency)
+
+	y := 80.0
+	doc.Text(left, y, 20, "Квитанция об оплате № "+inv.Number)
+	y += 22
+	doc.Text(left, y, 11, "от "+inv.IssuedAt.Local().Format("02.01.2006"))
+	y += 14
+	doc.Line(left, y, right, y, 1)
+
+	// реквизиты сторон
+	y += 26
+	block := func(title string, lines ...string) {
+		doc.Text(left, y, 12, title)
+		y += 16
+		for _, l := range lines {
+			if strings.TrimSpace(l) == "" {
+				continue
+			}
+			for _, w := range doc.Wrap(l, 10, right-left) {
+				doc.Text(left, y, 10, w)
+				y += 14
+			}
+		}
+		y += 10
+	}
+	block("Продавец",
+		s.seller.Name,
+		prefixed("ИНН ", s.seller.INN),
+		prefixed("ОГРН ", s.seller.OGRN),
+		prefixed("Адрес: ", s.seller.Address),
+	)
+	block("Покупатель",
+		displayName(user),
+		prefixed("Email: ", user.Email),
+		prefixed("Телефон: ", user.Phone),
+	)
+
+	// таблица услуг
+	cols := []float64{left, left + 28, right - 150, right - 90, right}
+	row := func(size int, cells ...string) {
+		doc.Text(cols[0]+4, y, size, cells[0])
+		lines := doc.Wrap(cells[1], size, cols[2]-cols[1]-8)
+		for i, l := range lines {
+			doc.Text(cols[1]+4, y+float64(i)*14, size, l)
+		}
+		doc.TextRight(cols[3]-4, y, size, cells[2])
+		doc.TextRight(cols[4]-4, y, size, cells[3])
+		y += float64(len(lines))*14 + 10
+		doc.Line(left, y-13, right, y-13, 0.5)
+	}
+	y += 4
+	doc.Line(left, y-14, right, y-14, 0.5)
+	row(10, "№", "Наименование", "Кол-во", "Сумма")
+	description := strings.TrimSpace(p.Description)
+	if description == "" {
+		description = "Подписка Edutalks (" + p.Plan + ")"
+	}
+	row(10, "1", description, "1", amount)
+
+	y += 8
+	doc.TextRight(right, y, 12, "Итого: "+amount)
+
+	y += 30
+	payment := "Оплата получена " + paidAt.Local().Format("02.01.2006 15:04")
+	if p.ProviderPaymentID != nil {
+		payment += " через ЮKassa, идентификатор платежа " + *p.ProviderPaymentID
+	}
+	for _, l := range doc.Wrap(payment+".", 10, right-left) {
+		doc.Text(left, y, 10, l)
+		y += 14
+	}
+	doc.Text(left, y, 10, "Квитанция сформирована автоматически и действительна без подписи и печати.")
+
+	return doc.Bytes()
+}
+
+func prefixed(prefix, v string) string {
+	if strings.TrimSpace(v) == "" {
+		return ""
+	}
+	return prefix + v
+}
+
+// IssueAndSendAsync — IssueAndSend в фоне, чтобы не задерживать ответ на уведомление ЮKassa.
+func (s *InvoiceService) IssueAndSendAsync(ctx context.Context, provider, providerPaymentID string) {
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Log.Error("Паника при отправке квитанции", zap.Any("panic", r))
+			}
+		}()
+		ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		defer cancel()
+		if err := s.IssueAndSend(ctx, provider, providerPaymentID); err != nil {
+			logger.WithCtx(ctx).Error("Не удалось выдать/отправить квитанцию",
+				zap.String("provider_payment_id", providerPaymentID), zap.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"edutalks/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// Имена фоновых задач для распределённой блокировки.
+const (
+	JobSubscriptions     = "subscriptions"       // истечение подписок и напоминания
+	JobAdminDigest       = "admin_digest"        // еженедельная сводка
+	JobEmailTokenCleanup = "email_token_cleanup" // очистка токенов подтверждения email
+	JobArticlePublisher  = "article_publisher"   // отложенная публикация статей
+	JobAccountDeletion   = "account_deletion"    // удаление аккаунтов после срока на отмену
+	JobNewsPublisher     = "news_publisher"      // отложенная публикация новостей
+	JobUserDigest        = "user_digest"         // ежедневные и еженедельные сводки пользователям
+	JobNotifyCampaigns   = "notify_campaigns"    // рассылки администратора по расписанию
+	JobTrashPurge        = "trash_purge"         // окончательное удаление из корзины
+	JobDocumentHashes    = "document_hashes"     // SHA-256 файлов документов, загруженных до его появления
+)
+
+// JobLocker — запуск задачи только на одном инстансе (repository.JobLockRepository).
+type JobLocker interface {
+	TryRun(ctx context.Context, name string, fn func(ctx context.Context) error) (bool, error)
+}
+
+// RunExclusive — выполняет fn под блокировкой задачи name; без locker — просто выполняет.
+// Если задачу сейчас выполняет другой инстанс, этот запуск пропускается.
+func RunExclusive(ctx context.Context, locks JobLocker, name string, fn func(ctx context.Context) error) error {
+	if locks == nil {
+		return fn(ctx)
+	}
+	ran, err := locks.TryRun(ctx, name, fn)
+	if !ran && err == nil {
+		logger.Log.Debug("Фоновая задача выполняется другим инстансом — пропуск", zap.String("job", name))
+	}
+	return err
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"go.uber.org/zap"
+)
+
+var ErrLogRetentionInvalid = errors.New("compress_after_days должен быть меньше retention_days")
+
+// logRetentionFile — настройки, изменённые через админку. Логи у каждого инстанса свои,
+// поэтому и настройки лежат рядом с ними, а не в БД.
+const logRetentionFile = "retention.json"
+
+// reLogFileDay — день в имени файла лога: app.YYYY-MM-DD.log[.gz] или app-YYYY-MM-DDT...log[.gz].
+var reLogFileDay = regexp.MustCompile(`^app[.-](\d{4}-\d{2}-\d{2})(T.*)?\.log(\.gz)?$`)
+
+// LogRetentionService — хранение файлов логов: старые сжимает в .gz, просроченные удаляет.
+type LogRetentionService struct {
+	dir string
+
+	mu       sync.Mutex
+	settings models.LogRetentionSettings
+
+	cleanMu sync.Mutex // ручной запуск и плановый не идут одновременно
+}
+
+// NewLogRetentionService — настройки из LOG_RETENTION_DAYS и LOG_COMPRESS_AFTER_DAYS;
+// сохранённые через админку (retention.json в папке логов) важнее.
+func NewLogRetentionService(dir string, cfg *config.Config) *LogRetentionService {
+	s := &LogRetentionService{
+		dir:      dir,
+		settings: models.LogRetentionSettings{RetentionDays: 14, CompressAfterDays: 2},
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(cfg.LogRetentionDays)); err == nil && n >= 1 {
+		s.settings.RetentionDays = n
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(cfg.LogCompressAfterDays)); err == nil && n >= 0 {
+		s.settings.CompressAfterDays = n
+	}
+
+	if b, err := os.ReadFile(filepath.Join(dir, logRetentionFile)); err == nil {
+		var saved models.LogRetentionSettings
+		if err := json.Unmarshal(b, &saved); err != nil || validLogRetention(saved) != nil {
+			logger.Log.Warn("Некорректный файл настроек хранения логов, используем конфиг",
+				zap.String("file", logRetentionFile), zap.Error(err))
+		} else {
+			s.settings = saved
+		}
+	}
+	return s
+}
+
+func validLogRetention(st models.LogRetentionSettings) error {
+	if st.RetentionDays < 1 || st.RetentionDays > 365 || st.CompressAfterDays < 0 {
+		return ErrLogRetentionInvalid
+	}
+	if st.CompressAfterDays > 0 && st.CompressAfterDays >= st.RetentionDays {
+		return ErrLogRetentionInvalid
+	}
+	return nil
+}
+
+// Dir — папка с логами.
+func (s *LogRetentionService) Dir() string {
+	return s.dir
+}
+
+// Settings — действующие настройки.
+func (s *LogRetentionService) Settings() models.LogRetentionSettings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.settings
+}
+
+// RetentionDays — сколько дней хранить логи.
+func (s *LogRetentionService) RetentionDays() int {
+	return s.Settings().RetentionDays
+}
+
+// Update — меняет настройки и сохраняет их в retention.json; применяются со следующей очистки.
+func (s *LogRetentionService) Update(ctx context.Context, st models.LogRetentionSettings) error {
+	if err := validLogRetention(st); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, logRetentionFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	prev := s.settings
+	s.settings = st
+
+	logger.WithCtx(ctx).Info("Настройки хранения логов изменены",
+		zap.Int("retention_days_old", prev.RetentionDays),
+		zap.Int("retention_days", st.RetentionDays),
+		zap.Int("compress_after_days", st.CompressAfterDays),
+	)
+	return nil
+}
+
+// Cleanup — удаляет файлы старше retention_days и сжимает файлы старше compress_after_days.
+// Сегодняшние файлы и текущий app.log не трогает: в них ещё пишет логгер.
+// Ошибка по одному файлу не останавливает очистку остальных — они попадут в лог.
+func (s *LogRetentionService) Cleanup(ctx context.Context) (models.LogCleanupReport, error) {
+	s.cleanMu.Lock()
+	defer s.cleanMu.Unlock()
+
+	log := logger.WithCtx(ctx)
+	st := s.Settings()
+	report := models.LogCleanupReport{Compressed: []string{}, Deleted: []string{}}
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return report, nil
+		}
+		return report, err
+	}
+
+	now := time.Now().Local()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		m := reLogFileDay.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		day, err := time.ParseInLocation("2006-01-02", m[1], time.Local)
+		if err != nil {
+			continue
+		}
+		age := int(math.Round(today.Sub(day).Hours() / 24)) // сутки с переходом на летнее время — не ровно 24ч
+		if age <= 0 {
+			continue
+		}
+		path := filepath.Join(s.dir, name)
+
+		switch {
+		case age >= st.RetentionDays:
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				log.Warn("Не удалось удалить старый файл лога", zap.String("file", name), zap.Error(err))
+				continue
+			}
+			report.Deleted = append(report.Deleted, name)
+			report.FreedBytes += info.Size()
+
+		case st.CompressAfterDays > 0 && age >= st.CompressAfterDays && !strings.HasSuffix(name, ".gz"):
+			freed, err := gzipLogFile(path)
+			if err != nil {
+				log.Warn("Не удалось сжать файл лога", zap.String("file", name), zap.Error(err))
+				continue
+			}
+			report.Compressed = append(report.Compressed, name)
+			report.FreedBytes += freed
+		}
+	}
+
+	if len(report.Compressed) > 0 || len(report.Deleted) > 0 {
+		log.Info("Очистка логов выполнена",
+			zap.Int("compressed", len(report.Compressed)),
+			zap.Int("deleted", len(report.Deleted)),
+			zap.Int64("freed_bytes", report.FreedBytes),
+		)
+	}
+	return report, nil
+}
+
+// gzipLogFile — сжимает path в path.gz через временный файл и удаляет исходный; возвращает,
+// сколько байт освободилось. Если .gz уже есть (прошлый запуск не успел удалить исходный),
+// исходный просто удаляется.
+func gzipLogFile(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	dst := path + ".gz"
+	if _, err := os.Stat(dst); err == nil {
+		return info.Size(), os.Remove(path)
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	zw := gzip.NewWriter(out)
+	zw.Name = filepath.Base(path)
+	zw.ModTime = info.ModTime()
+	_, err = io.Copy(zw, src)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+
+	_ = os.Chtimes(tmp, info.ModTime(), info.ModTime())
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+	gz, err := os.Stat(dst)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Remove(path); err != nil {
+		return 0, err
+	}
+	return info.Size() - gz.Size(), nil
+}
+
+// Start — периодическая очистка логов; interval <= 0 — только по запросу из админки.
+// Возвращает функцию остановки.
+func (s *LogRetentionService) Start(interval time.Duration) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		logger.Log.Info("Очистка логов по расписанию запущена", zap.Duration("interval", interval))
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.Cleanup(context.Background()); err != nil {
+					logger.Log.Error("Ошибка очистки логов", zap.Error(err))
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return StopAndWait("log_retention", done, stopped)
+}
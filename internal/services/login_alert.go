@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/metrics"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+// Адрес API ipwho.is по умолчанию; GEOIP_URL может заменить его, но только на https.
+const ipwhoisURL = "https://ipwho.is/"
+
+// Входы с новых устройств по исходу: sent — письмо отправлено, first — первое устройство
+// пользователя, off — уведомления отключены или нет e-mail.
+var loginAlerts = metrics.NewCounterVec("login_alerts_total", "Входы с новых устройств по исходу уведомления", "result")
+
+// GeoLocator — примерное место по IP («Москва, Россия»); пусто — определить не удалось.
+type GeoLocator interface {
+	Locate(ctx context.Context, ip, locale string) (string, error)
+}
+
+// NewGeoLocator — из GEOIP_PROVIDER: ipwhois (ipwho.is); nil — место не определяется.
+// IP пользователя уходит стороннему сервису, поэтому только по https и с коротким таймаутом.
+func NewGeoLocator(cfg *config.Config) GeoLocator {
+	switch cfg.GeoIPProvider {
+	case "ipwhois":
+		base := ipwhoisURL
+		if cfg.GeoIPURL != "" {
+			if !strings.HasPrefix(cfg.GeoIPURL, "https://") {
+				return nil
+			}
+			base = strings.TrimRight(cfg.GeoIPURL, "/") + "/"
+		}
+		timeout := 2 * time.Second
+		if d, err := time.ParseDuration(strings.TrimSpace(cfg.GeoIPTimeout)); err == nil && d > 0 {
+			timeout = d
+		}
+		return &ipwhoisLocator{base: base, client: &http.Client{Timeout: timeout}}
+	}
+	return nil
+}
+
+// ipwhoisLocator — ipwho.is, ответ в JSON с городом и страной на языке письма.
+type ipwhoisLocator struct {
+	base   string
+	client *http.Client
+}
+
+func (l *ipwhoisLocator) Locate(ctx context.Context, ip, locale string) (string, error) {
+	target := l.base + url.PathEscape(ip) + "?fields=success,message,country,city&lang=" + helpers.NormalizeLocale(locale)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("ipwho.is ответил %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+		Country string `json:"country"`
+		City    string `json:"city"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 16<<10)).Decode(&body); err != nil {
+		return "", fmt.Errorf("ответ ipwho.is не разобран: %w", err)
+	}
+	if !body.Success {
+		return "", fmt.Errorf("ipwho.is: %s", body.Message)
+	}
+	parts := make([]string, 0, 2)
+	for _, p := range []string{body.City, body.Country} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// loginAlerter — запоминает устройства, с которых входят, и пишет о входе с нового.
+// О самом первом устройстве не пишем — это регистрация или первый вход после выката.
+//
+// Письмо собирается в фоне (место по IP ищется уже после ответа на вход); такие горутины
+// учитываются в wg, и stop дожидается их, прежде чем закроется EmailQueue.
+type loginAlerter struct {
+	sessions *repository.SessionRepository
+	geo      GeoLocator // nil — без места
+	resetURL string
+
+	mu      sync.Mutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+func newLoginAlerter(sessions *repository.SessionRepository, cfg *config.Config) *loginAlerter {
+	return &loginAlerter{sessions: sessions, geo: NewGeoLocator(cfg), resetURL: cfg.PasswordResetPageURL()}
+}
+
+// noteLogin — вызывается после создания сессии; ошибки только логируются, вход они не прерывают.
+func (a *loginAlerter) noteLogin(ctx context.Context, user *models.User, client models.SessionClient) {
+	log := logger.WithCtx(ctx).With(zap.Int("user_id", user.ID))
+
+	isNew, known, err := a.sessions.RememberDevice(ctx, user.ID, client)
+	if err != nil {
+		log.Warn("Не удалось запомнить устройство входа", zap.Error(err))
+		return
+	}
+	if !isNew {
+		return
+	}
+	switch {
+	case known == 0:
+		loginAlerts.WithLabelValues("first").Inc()
+		return
+	case !user.LoginAlerts || user.Email == "":
+		loginAlerts.WithLabelValues("off").Inc()
+		return
+	}
+
+	a.mu.Lock()
+	if a.stopped {
+		a.mu.Unlock()
+		log.Warn("Письмо о входе не отправлено: сервис останавливается")
+		return
+	}
+	a.wg.Add(1)
+	a.mu.Unlock()
+
+	at := time.Now()
+	go func(ctx context.Context) {
+		defer a.wg.Done()
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Error("Паника при отправке письма о входе", zap.Any("panic", rec))
+			}
+		}()
+		location := a.locate(ctx, client.IP, user.Locale)
+		EmailQueue <- EmailJob{
+			To:      []string{user.Email},
+			Subject: helpers.EmailSubject(user.Locale, helpers.MailNewLogin),
+			Body: helpers.BuildNewLoginHTML(user.Locale, loginAlertName(user), at, client.IP, location,
+				describeDevice(client.UserAgent), a.resetURL),
+			IsHTML: true,
+		}
+		loginAlerts.WithLabelValues("sent").Inc()
+		log.Info("Письмо о входе с нового устройства поставлено в очередь", zap.String("ip", client.IP))
+	}(context.WithoutCancel(ctx))
+}
+
+// stop — новые письма больше не собираются; ждёт начатые, не дольше SHUTDOWN_TIMEOUT.
+// Вызывать до StopEmailWorkers.
+func (a *loginAlerter) stop() {
+	a.mu.Lock()
+	a.stopped = true
+	a.mu.Unlock()
+	waitStopped("login-alerts", waitGroupDone(&a.wg))
+}
+
+// locate — место по IP; для локальных и частных адресов не ищем.
+func (a *loginAlerter) locate(ctx context.Context, ip, locale string) string {
+	addr := net.ParseIP(ip)
+	if a.geo == nil || addr == nil || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() {
+		return ""
+	}
+	location, err := a.geo.Locate(ctx, ip, locale)
+	if err != nil {
+		logger.WithCtx(ctx).Warn("Не удалось определить место по IP", zap.String("ip", ip), zap.Error(err))
+		return ""
+	}
+	return location
+}
+
+func loginAlertName(u *models.User) string {
+	if name := strings.TrimSpace(u.FullName); name != "" {
+		return name
+	}
+	return u.Username
+}
+
+// Браузеры и системы для строки «устройство»; порядок важен: Edge и Opera содержат «Chrome»,
+// Chrome — «Safari», Android — «Linux».
+var (
+	uaBrowsers = []struct{ marker, name string }{
+		{"YaBrowser/", "Yandex Browser"},
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	}
+	uaSystems = []struct{ marker, name string }{
+		{"Windows", "Windows"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Mac OS X", "macOS"},
+		{"Android", "Android"},
+		{"Linux", "Linux"},
+	}
+)
+
+// describeDevice — «Chrome, Windows» по User-Agent; неизвестный — сам User-Agent, укороченный.
+func describeDevice(ua string) string {
+	var parts []string
+	for _, b := range uaBrowsers {
+		if strings.Contains(ua, b.marker) {
+			parts = append(parts, b.name)
+			break
+		}
+	}
+	for _, s := range uaSystems {
+		if strings.Contains(ua, s.marker) {
+			parts = append(parts, s.name)
+			break
+		}
+	}
+	if len(parts) > 0 {
+		return strings.Join(parts, ", ")
+	}
+	if r := []rune(strings.TrimSpace(ua)); len(r) > 80 {
+		return string(r[:80]) + "…"
+	}
+	return strings.TrimSpace(ua)
+}
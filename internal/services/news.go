@@ -2,15 +2,35 @@ package services
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"edutalks/internal/config"
 	"edutalks/internal/logger"
 	"edutalks/internal/models"
 	"edutalks/internal/repository"
 
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
+// maxNewsTags — сколько тегов можно указать у новости (как у статей).
+const maxNewsTags = 5
+
+var (
+	ErrNewsTags      = errors.New("у новости не больше 5 тегов, каждый — до 50 символов")
+	ErrNewsNotFound  = errors.New("новость не найдена")
+	ErrNewsPublished = errors.New("новость уже опубликована")
+)
+
+// newsPublishIntents — письмо подписчикам о новости ставится через outbox вместе с публикацией.
+func newsPublishIntents(n *models.News) ([]models.OutboxIntent, error) {
+	intent, err := NotifyIntent(models.NotifyNewsPublished, int64(n.ID), n.Title)
+	return []models.OutboxIntent{intent}, err
+}
+
 type NewsService struct {
 	repo         *repository.NewsRepository
 	userRepo     *repository.UserRepository
@@ -33,9 +53,25 @@ func NewNewsService(
 }
 
 func (s *NewsService) Create(ctx context.Context, news *models.News) (int, error) {
-	logger.Log.Info("Сервис: создание новости", zap.String("title", news.Title))
+	logger.Log.Info("Сервис: создание новости", zap.String("title", news.Title), zap.Bool("publish", news.IsPublished))
+
+	tags, err := newsTags(news.Tags)
+	if err != nil {
+		return 0, err
+	}
+	news.Tags = tags
+	news.Category = strings.TrimSpace(news.Category)
+	resolveNewsPublishAt(news)
 
-	id, err := s.repo.Create(ctx, news)
+	// рассылка подписчикам — через outbox: не теряется при падении процесса после коммита;
+	// у черновика рассылки нет до публикации (SetPublish или по расписанию)
+	var intents func(id int) ([]models.OutboxIntent, error)
+	if news.IsPublished {
+		intents = func(id int) ([]models.OutboxIntent, error) {
+			return newsPublishIntents(&models.News{ID: id, Title: news.Title})
+		}
+	}
+	id, err := s.repo.Create(ctx, news, intents)
 	if err != nil {
 		logger.Log.Error("Сервис: ошибка создания новости", zap.Error(err))
 		return 0, err
@@ -45,13 +81,17 @@ func (s *NewsService) Create(ctx context.Context, news *models.News) (int, error
 	return id, nil
 }
 
-func (s *NewsService) ListPaginated(ctx context.Context, limit, offset int) ([]*models.News, int, error) {
+func (s *NewsService) ListPaginated(ctx context.Context, limit, offset int, f models.NewsFilter) ([]*models.News, int, error) {
+	f.Tag = strings.ToLower(strings.TrimSpace(f.Tag))
+	f.Category = strings.TrimSpace(f.Category)
 	logger.Log.Debug("Сервис: список новостей (пагинация)",
 		zap.Int("limit", limit),
 		zap.Int("offset", offset),
+		zap.String("tag", f.Tag),
+		zap.String("category", f.Category),
 	)
 
-	items, total, err := s.repo.ListPaginated(ctx, limit, offset)
+	items, total, err := s.repo.ListPaginated(ctx, limit, offset, f)
 	if err != nil {
 		logger.Log.Error("Сервис: ошибка получения списка новостей", zap.Error(err))
 		return nil, 0, err
@@ -80,10 +120,22 @@ func (s *NewsService) GetByID(ctx context.Context, id int) (*models.News, error)
 	return n, nil
 }
 
-func (s *NewsService) Update(ctx context.Context, id int, title, content, imageURL, color, sticker string) error {
+func (s *NewsService) Update(ctx context.Context, id int, u models.NewsUpdate) error {
 	logger.Log.Info("Сервис: обновление новости", zap.Int("news_id", id))
 
-	if err := s.repo.Update(ctx, id, title, content, imageURL, color, sticker); err != nil {
+	if u.Tags != nil {
+		tags, err := newsTags(u.Tags)
+		if err != nil {
+			return err
+		}
+		u.Tags = tags
+	}
+	if u.Category != nil {
+		c := strings.TrimSpace(*u.Category)
+		u.Category = &c
+	}
+
+	if err := s.repo.Update(ctx, id, u); err != nil {
 		logger.Log.Error("Сервис: ошибка обновления новости",
 			zap.Int("news_id", id),
 			zap.Error(err),
@@ -95,6 +147,60 @@ func (s *NewsService) Update(ctx context.Context, id int, title, content, imageU
 	return nil
 }
 
+// SetPublish — публикует черновик или снимает новость с публикации. Подписчики получают
+// письмо только при первой публикации.
+func (s *NewsService) SetPublish(ctx context.Context, id int, publish bool) (*models.News, error) {
+	logger.Log.Info("Сервис: изменение публикации новости", zap.Int("news_id", id), zap.Bool("publish", publish))
+
+	n, err := s.repo.SetPublished(ctx, id, publish, newsPublishIntents)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNewsNotFound
+	}
+	if err != nil {
+		logger.Log.Error("Сервис: ошибка изменения публикации новости",
+			zap.Int("news_id", id),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	logger.Log.Info("Сервис: публикация новости изменена", zap.Int("news_id", id), zap.Bool("published", n.IsPublished))
+	return n, nil
+}
+
+// Schedule — публикация черновика в момент at; наступившее время публикует сразу.
+func (s *NewsService) Schedule(ctx context.Context, id int, at time.Time) (*models.News, error) {
+	if !at.After(time.Now()) {
+		return s.SetPublish(ctx, id, true)
+	}
+	logger.Log.Info("Сервис: планирование публикации новости", zap.Int("news_id", id), zap.Time("publish_at", at))
+
+	n, err := s.repo.Schedule(ctx, id, at.UTC())
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, gerr := s.repo.GetByID(ctx, id); gerr != nil {
+			return nil, ErrNewsNotFound
+		}
+		return nil, ErrNewsPublished
+	}
+	if err != nil {
+		logger.Log.Error("Сервис: ошибка планирования публикации новости", zap.Int("news_id", id), zap.Error(err))
+		return nil, err
+	}
+	return n, nil
+}
+
+// PublishScheduled — публикует новости с наступившим publish_at; рассылка — через outbox.
+func (s *NewsService) PublishScheduled(ctx context.Context) (int, error) {
+	list, err := s.repo.PublishDue(ctx, newsPublishIntents)
+	if err != nil {
+		return 0, err
+	}
+	for _, n := range list {
+		logger.Log.Info("Сервис: новость опубликована по расписанию", zap.Int("news_id", n.ID), zap.String("title", n.Title))
+	}
+	return len(list), nil
+}
+
 func (s *NewsService) Delete(ctx context.Context, id int) error {
 	logger.Log.Info("Сервис: удаление новости", zap.Int("news_id", id))
 
@@ -122,3 +228,31 @@ func (s *NewsService) Search(ctx context.Context, query string) ([]models.News,
 	logger.Log.Debug("Сервис: поиск новостей завершён", zap.Int("count", len(items)))
 	return items, nil
 }
+
+// newsTags — теги в нижнем регистре без повторов; не больше maxNewsTags, каждый до 50 символов.
+func newsTags(in []string) ([]string, error) {
+	tags := normalizeTags(in)
+	if len(tags) > maxNewsTags {
+		return nil, ErrNewsTags
+	}
+	for _, t := range tags {
+		if utf8.RuneCountInString(t) > 50 {
+			return nil, ErrNewsTags
+		}
+	}
+	return tags, nil
+}
+
+// resolveNewsPublishAt — опубликованной новости расписание не нужно; наступившее время
+// публикации — публикация сразу.
+func resolveNewsPublishAt(n *models.News) {
+	if n.PublishAt == nil {
+		return
+	}
+	if n.IsPublished || !n.PublishAt.After(time.Now()) {
+		n.IsPublished, n.PublishAt = true, nil
+		return
+	}
+	at := n.PublishAt.UTC()
+	n.PublishAt = &at
+}
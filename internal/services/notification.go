@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+var ErrNotificationNotFound = errors.New("уведомление не найдено")
+var ErrFollowSectionNotFound = errors.New("раздел не найден")
+
+// maxNotifyTitles — сколько названий документов пакета перечисляем в тексте уведомления.
+const maxNotifyTitles = 5
+
+// NotificationService — уведомления в личном кабинете и подписки на разделы.
+// Новые уведомления приходят в открытые SSE-потоки через NotificationHub.
+type NotificationService struct {
+	repo     *repository.NotificationRepository
+	follows  *repository.SectionFollowRepository
+	taxRepo  *repository.TaxonomyRepo
+	hub      *NotificationHub
+	renewURL string
+}
+
+func NewNotificationService(
+	repo *repository.NotificationRepository,
+	follows *repository.SectionFollowRepository,
+	taxRepo *repository.TaxonomyRepo,
+	hub *NotificationHub,
+	cfg *config.Config,
+) *NotificationService {
+	renewURL := strings.TrimSpace(cfg.SubscriptionRenewURL)
+	if renewURL == "" {
+		renewURL = strings.TrimRight(cfg.FrontendURL, "/")
+	}
+	return &NotificationService{repo: repo, follows: follows, taxRepo: taxRepo, hub: hub, renewURL: renewURL}
+}
+
+// DocumentsAdded — уведомление подписчикам раздела о новых документах; пакет — одно уведомление.
+func (s *NotificationService) DocumentsAdded(ctx context.Context, sectionID int, docIDs []int, titles []string) error {
+	if len(docIDs) == 0 {
+		return nil
+	}
+	tab, sec, err := s.taxRepo.GetSectionPlacement(ctx, sectionID)
+	if err != nil {
+		return err
+	}
+
+	link := "/" + sec.Path
+	n := &models.Notification{
+		Type:  models.NotificationDocumentAdded,
+		Title: fmt.Sprintf("Новый документ в разделе «%s»", sec.Title),
+		Body:  titles[0],
+		Link:  &link,
+		Data: map[string]any{
+			"section_id":   sectionID,
+			"tab_slug":     tab.Slug,
+			"document_ids": docIDs,
+		},
+	}
+	if len(docIDs) > 1 {
+		n.Title = fmt.Sprintf("Новые документы (%d) в разделе «%s»", len(docIDs), sec.Title)
+		n.Body = notifyTitles(titles)
+	}
+
+	count, err := s.repo.CreateForSectionFollowers(ctx, sectionID, n)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		logger.WithCtx(ctx).Info("Уведомления о новых документах созданы",
+			zap.Int("section_id", sectionID), zap.Int("documents", len(docIDs)), zap.Int("recipients", count))
+	}
+	return nil
+}
+
+// SubscriptionExpiring — уведомление о скором окончании подписки.
+func (s *NotificationService) SubscriptionExpiring(ctx context.Context, userID int, expiresAt time.Time) error {
+	link := s.renewURL
+	n := &models.Notification{
+		Type:  models.NotificationSubscriptionExpiring,
+		Title: "Подписка скоро закончится",
+		Body:  fmt.Sprintf("Подписка действует до %s. Продлите её, чтобы не потерять доступ к материалам.", expiresAt.In(quotaZone).Format("02.01.2006")),
+		Data:  map[string]any{"expires_at": expiresAt.UTC()},
+	}
+	if link != "" {
+		n.Link = &link
+	}
+	_, err := s.repo.CreateForUser(ctx, userID, n)
+	return err
+}
+
+// Broadcast — объявление администрации выбранной аудитории; возвращает число получателей.
+func (s *NotificationService) Broadcast(ctx context.Context, req models.BroadcastRequest, adminID int) (int, error) {
+	audience := req.Audience
+	if audience == "" {
+		audience = models.BroadcastAll
+	}
+	n := &models.Notification{
+		Type:  models.NotificationBroadcast,
+		Title: strings.TrimSpace(req.Title),
+		Body:  strings.TrimSpace(req.Body),
+		Data:  map[string]any{"audience": audience},
+	}
+	if req.Link != nil {
+		if v := strings.TrimSpace(*req.Link); v != "" {
+			n.Link = &v
+		}
+	}
+
+	count, err := s.repo.CreateBroadcast(ctx, audience, n)
+	if err != nil {
+		return 0, err
+	}
+	logger.WithCtx(ctx).Info("Объявление разослано",
+		zap.String("audience", audience), zap.Int("recipients", count), zap.Int("admin_id", adminID))
+	return count, nil
+}
+
+func (s *NotificationService) List(ctx context.Context, userID int, unreadOnly bool, limit, offset int) ([]models.Notification, int, int, error) {
+	return s.repo.List(ctx, userID, unreadOnly, limit, offset)
+}
+
+func (s *NotificationService) UnreadCount(ctx context.Context, userID int) (int, error) {
+	return s.repo.UnreadCount(ctx, userID)
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID int, id int64) error {
+	ok, err := s.repo.MarkRead(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID int) (int, error) {
+	return s.repo.MarkAllRead(ctx, userID)
+}
+
+// Subscribe — сигналы о новых уведомлениях пользователя для SSE-потока; cancel обязателен.
+func (s *NotificationService) Subscribe(userID int) (<-chan struct{}, func()) {
+	return s.hub.Subscribe(userID)
+}
+
+// LastID — с какого места начинать поток без Last-Event-ID: только уведомления после подключения.
+func (s *NotificationService) LastID(ctx context.Context, userID int) (int64, error) {
+	return s.repo.LastID(ctx, userID)
+}
+
+func (s *NotificationService) ListAfter(ctx context.Context, userID int, afterID int64, limit int) ([]models.Notification, error) {
+	return s.repo.ListAfter(ctx, userID, afterID, limit)
+}
+
+func (s *NotificationService) Follow(ctx context.Context, userID, sectionID int) error {
+	found, err := s.follows.Follow(ctx, userID, sectionID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrFollowSectionNotFound
+	}
+	return nil
+}
+
+// Unfollow — отписка от раздела; отписка от неотслеживаемого раздела не ошибка.
+func (s *NotificationService) Unfollow(ctx context.Context, userID, sectionID int) error {
+	_, err := s.follows.Unfollow(ctx, userID, sectionID)
+	return err
+}
+
+func (s *NotificationService) Follows(ctx context.Context, userID int) ([]models.SectionFollow, error) {
+	return s.follows.List(ctx, userID)
+}
+
+// notifyTitles — названия документов через запятую; лишние — «и ещё N».
+func notifyTitles(titles []string) string {
+	shown := titles
+	if len(shown) > maxNotifyTitles {
+		shown = shown[:maxNotifyTitles]
+	}
+	out := strings.Join(shown, ", ")
+	if rest := len(titles) - len(shown); rest > 0 {
+		out += fmt.Sprintf(" и ещё %d", rest)
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// notificationListenRetry — пауза перед повторным LISTEN после разрыва соединения.
+const notificationListenRetry = 5 * time.Second
+
+// NotificationHub — раздаёт открытым SSE-потокам этого инстанса сигналы «у пользователя новые
+// уведомления». Сигналы приходят из Postgres (LISTEN/NOTIFY), поэтому уведомление, созданное
+// на любом инстансе, доходит до потоков на всех. Сам сигнал без данных: поток дочитывает
+// новые записи из БД.
+type NotificationHub struct {
+	repo *repository.NotificationRepository
+
+	mu   sync.Mutex
+	subs map[int]map[chan struct{}]struct{}
+}
+
+func NewNotificationHub(repo *repository.NotificationRepository) *NotificationHub {
+	return &NotificationHub{repo: repo, subs: map[int]map[chan struct{}]struct{}{}}
+}
+
+// Subscribe — канал сигналов пользователя; несколько сигналов подряд сливаются в один.
+func (h *NotificationHub) Subscribe(userID int) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	if h.subs[userID] == nil {
+		h.subs[userID] = map[chan struct{}]struct{}{}
+	}
+	h.subs[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subs[userID], ch)
+		if len(h.subs[userID]) == 0 {
+			delete(h.subs, userID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// signal — будит потоки пользователя; userID 0 — все потоки.
+func (h *NotificationHub) signal(userID int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	wake := func(set map[chan struct{}]struct{}) {
+		for ch := range set {
+			select {
+			case ch <- struct{}{}:
+			default: // сигнал уже ждёт чтения
+			}
+		}
+	}
+	if userID == 0 {
+		for _, set := range h.subs {
+			wake(set)
+		}
+		return
+	}
+	wake(h.subs[userID])
+}
+
+func (h *NotificationHub) dispatch(payload string) {
+	if payload == repository.NotificationBroadcastPayload {
+		h.signal(0)
+		return
+	}
+	id, err := strconv.Atoi(payload)
+	if err != nil || id <= 0 {
+		logger.Log.Warn("Некорректный сигнал уведомлений", zap.String("payload", payload))
+		return
+	}
+	h.signal(id)
+}
+
+// Start — слушает канал уведомлений до остановки, переподключаясь после разрывов;
+// возвращает функцию остановки.
+func (h *NotificationHub) Start() func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		logger.Log.Info("NotificationHub запущен")
+		for {
+			err := h.repo.Listen(ctx, h.dispatch)
+			if ctx.Err() != nil {
+				logger.Log.Info("NotificationHub остановлен")
+				return
+			}
+			logger.Log.Warn("NotificationHub: соединение LISTEN потеряно", zap.Error(err))
+			select {
+			case <-time.After(notificationListenRetry):
+				// пока соединения не было, сигналы могли потеряться — потоки дочитают из БД
+				h.signal(0)
+			case <-ctx.Done():
+				logger.Log.Info("NotificationHub остановлен")
+				return
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-stopped
+	}
+}
@@ -4,9 +4,11 @@ package services
 import (
 	"context"
 	"edutalks/internal/logger"
+	"edutalks/internal/models"
 	"edutalks/internal/repository"
 	helpers "edutalks/internal/utils/helpers"
 	"fmt"
+	"html"
 	"net/url"
 	"strings"
 	"sync"
@@ -18,25 +20,58 @@ import (
 type Notifier struct {
 	subsRepo *repository.SubscriptionRepository
 	taxRepo  *repository.TaxonomyRepo
+	follows  *repository.SectionFollowRepository // документы раздела — только отслеживающим его
 	baseURL  string
 	fromName string
 
 	// — батч-уведомления —
-	mu     sync.Mutex
-	buffer []string
-	once   sync.Once
+	// буфер в памяти свой у каждого инстанса, поэтому и рассылает его каждый инстанс сам,
+	// без общей блокировки задач
+	mu       sync.Mutex
+	buffer   map[string]*batchItem // ключ — «doc:<id>» / «docs:<id>» / «article:<id>»: повторное сохранение не даёт дубля
+	order    []string              // порядок добавления
+	once     sync.Once
+	interval time.Duration // период групповой рассылки
+	done     chan struct{}
+	stopped  chan struct{} // закрывается при выходе батч-воркера; nil — воркер не запускался
+	stopOnce sync.Once
 }
 
+// batchItem — материал в буфере групповой рассылки: новый документ либо обновлённые документ/статья.
+type batchItem struct {
+	ID      int
+	Title   string
+	Titles  []string              // пакетная загрузка: названия всех документов пакета, ID — первого
+	Article bool                  // статья; иначе документ
+	Updated bool                  // «обновлено» — в письме отдельным блоком
+	Tab     *models.TaxonomyCrumb // nil — документ без раздела
+	Section *models.TaxonomyCrumb
+}
+
+// maxBatchItems — сколько материалов перечисляем в каждом блоке письма; остальные — ссылкой «и ещё N».
+const maxBatchItems = 20
+
+// defaultBatchInterval — период групповой рассылки, если NOTIFY_BATCH_INTERVAL не задан или некорректен.
+const defaultBatchInterval = 10 * time.Minute
+
 func NewNotifier(
 	subsRepo *repository.SubscriptionRepository,
 	taxRepo *repository.TaxonomyRepo,
-	baseURL, fromName string,
+	follows *repository.SectionFollowRepository,
+	baseURL, fromName, batchInterval string,
 ) *Notifier {
+	interval, err := time.ParseDuration(batchInterval)
+	if err != nil || interval <= 0 {
+		interval = defaultBatchInterval
+	}
 	return &Notifier{
 		subsRepo: subsRepo,
 		taxRepo:  taxRepo,
+		follows:  follows,
 		baseURL:  strings.TrimRight(baseURL, "/"),
 		fromName: fromName,
+		interval: interval,
+		done:     make(chan struct{}),
 	}
 }
 
@@ -55,7 +90,8 @@ func chunkStrings(all []string, n int) [][]string {
 	return out
 }
 
-func (n *Notifier) sendToAll(ctx context.Context, subject, htmlBody string) {
+// sendToAll — письмо всем подписчикам; build собирает тему и HTML на языке получателей.
+func (n *Notifier) sendToAll(ctx context.Context, build func(locale string) (subject, htmlBody string)) {
 	// не завязываемся на HTTP-контекст
 	ctx = context.WithoutCancel(ctx)
 
@@ -64,6 +100,28 @@ func (n *Notifier) sendToAll(ctx context.Context, subject, htmlBody string) {
 		logger.Log.Error("Не удалось получить список подписчиков", zap.Error(err))
 		return
 	}
+	for locale, group := range n.byLocale(ctx, emails) {
+		subject, htmlBody := build(locale)
+		n.sendTo(group, subject, htmlBody)
+	}
+}
+
+// byLocale — адреса по языку писем (users.locale); язык узнать не удалось — язык по умолчанию.
+func (n *Notifier) byLocale(ctx context.Context, emails []string) map[string][]string {
+	locales, err := n.subsRepo.EmailLocales(ctx, emails)
+	if err != nil {
+		logger.Log.Warn("Не удалось получить языки подписчиков — письма уйдут на языке по умолчанию", zap.Error(err))
+	}
+	out := map[string][]string{}
+	for _, e := range emails {
+		l := helpers.NormalizeLocale(locales[e])
+		out[l] = append(out[l], e)
+	}
+	return out
+}
+
+// sendTo — ставит письмо в очередь батчами по 50 адресов.
+func (n *Notifier) sendTo(emails []string, subject, htmlBody string) {
 	if len(emails) == 0 {
 		logger.Log.Debug("Список подписчиков пуст — рассылка пропущена")
 		return
@@ -115,115 +173,411 @@ func (n *Notifier) NotifyNewDocument(ctx context.Context, title string, tabsID *
 		zap.String("link", link),
 	)
 
-	subject := "Новый документ на Edutalks"
-	body := fmt.Sprintf(`
+	n.sendToAll(ctx, func(locale string) (string, string) {
+		body := fmt.Sprintf(`
       <p style="font-size:16px;color:#222;margin:0 0 16px 0;"><strong>%s</strong></p>
-      <p><a href="%s" style="display:inline-block;padding:12px 24px;background:#2d74da;color:#fff;text-decoration:none;border-radius:6px;font-weight:600;">Открыть документ</a></p>
-      <p style="font-size:12px;color:#999;margin-top:16px;">Если кнопка не работает — скопируйте ссылку: %s</p>
-    `, title, link, link)
-	html := helpers.BuildSimpleHTML("Добавлен новый документ", body)
-
-	n.sendToAll(ctx, subject, html)
+      <p><a href="%s" style="display:inline-block;padding:12px 24px;background:#2d74da;color:#fff;text-decoration:none;border-radius:6px;font-weight:600;">%s</a></p>
+      <p style="font-size:12px;color:#999;margin-top:16px;">%s</p>
+    `, title, link, helpers.EmailPhrase(locale, helpers.TextOpenDocument), helpers.EmailPhrase(locale, helpers.TextButtonFallback, link))
+		return helpers.EmailSubject(locale, helpers.MailNewDocument),
+			helpers.BuildSimpleHTML(locale, helpers.EmailPhrase(locale, helpers.TextNewDocumentTitle), body)
+	})
 }
 
-// Новость опубликована
-func (n *Notifier) NotifyNewsPublished(ctx context.Context, newsID int, title string) {
-	link := fmt.Sprintf("%s/recomm/%d", n.baseURL, newsID)
-
-	logger.Log.Info("Уведомление: опубликована новость",
-		zap.Int("news_id", newsID),
-		zap.String("title", title),
-		zap.String("link", link),
-	)
-
-	subject := "Новая новость на Edutalks"
-	html := helpers.BuildNewsHTML(title, "", link) // сюда можно передать краткий контент
+// PublishedMessage — тема и HTML письма о публикации новости или статьи на языке locale
+// (рассылку ставит OutboxRelay по намерению kind=notify).
+func (n *Notifier) PublishedMessage(locale string, m models.OutboxNotify) (subject, body string, err error) {
+	switch m.Type {
+	case models.NotifyNewsPublished:
+		link := fmt.Sprintf("%s/recomm/%d", n.baseURL, m.ID)
+		return helpers.EmailSubject(locale, helpers.MailNewsPublished), helpers.BuildNewsHTML(locale, m.Title, "", link), nil // сюда можно передать краткий контент
+	case models.NotifyArticlePublished:
+		link := fmt.Sprintf("%s/zavuch/%d", n.baseURL, m.ID)
+		content := fmt.Sprintf(`
+      <p style="font-size:16px;color:#222;margin:0 0 16px 0;"><strong>%s</strong></p>
+      <p><a href="%s" style="display:inline-block;padding:12px 24px;background:#2d74da;color:#fff;text-decoration:none;border-radius:6px;font-weight:600;">%s</a></p>
+      <p style="font-size:12px;color:#999;margin-top:16px;">%s</p>
+    `, m.Title, link, helpers.EmailPhrase(locale, helpers.TextReadArticle), helpers.EmailPhrase(locale, helpers.TextButtonFallback, link))
+		return helpers.EmailSubject(locale, helpers.MailArticlePublished),
+			helpers.BuildSimpleHTML(locale, helpers.EmailPhrase(locale, helpers.TextArticleTitle), content), nil
+	}
+	return "", "", fmt.Errorf("неизвестный тип рассылки: %s", m.Type)
+}
 
-	n.sendToAll(context.WithoutCancel(ctx), subject, html)
+// Recipients — адреса всех подписчиков рассылки по языку писем.
+func (n *Notifier) Recipients(ctx context.Context) (map[string][]string, error) {
+	emails, err := n.subsRepo.GetAllSubscribedEmails(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.byLocale(ctx, emails), nil
 }
 
-// Статья опубликована
-func (n *Notifier) NotifyArticlePublished(ctx context.Context, articleID int, title string) {
-	link := fmt.Sprintf("%s/zavuch/%d", n.baseURL, articleID)
+// AddDocumentForBatch — добавляем документ во временный буфер для групповой рассылки.
+// Документ учитывается один раз: повторное добавление (пересохранение, смена раздела)
+// только обновляет его заголовок и раздел.
+func (n *Notifier) AddDocumentForBatch(ctx context.Context, docID int, title string, sectionID *int) {
+	n.addToBatch(n.documentItem(ctx, docID, title, sectionID, false))
+}
 
-	logger.Log.Info("Уведомление: опубликована статья",
-		zap.Int("article_id", articleID),
-		zap.String("title", title),
-		zap.String("link", link),
-	)
+// AddDocumentsForBatch — пакет документов, загруженных одним запросом: в письме это одна
+// строка «Новые документы (N)» в разделе пакета, а не N отдельных строк.
+func (n *Notifier) AddDocumentsForBatch(ctx context.Context, docIDs []int, titles []string, sectionID *int) {
+	switch len(docIDs) {
+	case 0:
+		return
+	case 1:
+		n.AddDocumentForBatch(ctx, docIDs[0], titles[0], sectionID)
+		return
+	}
+	item := n.documentItem(ctx, docIDs[0], titles[0], sectionID, false)
+	item.Titles = titles
+	n.addToBatch(item)
+}
 
-	body := fmt.Sprintf(`
-      <p style="font-size:16px;color:#222;margin:0 0 16px 0;"><strong>%s</strong></p>
-      <p><a href="%s" style="display:inline-block;padding:12px 24px;background:#2d74da;color:#fff;text-decoration:none;border-radius:6px;font-weight:600;">Читать статью</a></p>
-      <p style="font-size:12px;color:#999;margin-top:16px;">Если кнопка не работает — скопируйте ссылку: %s</p>
-    `, title, link, link)
-	html := helpers.BuildSimpleHTML("Новая статья", body)
+// AddDocumentUpdateForBatch — в ближайшей рассылке документ попадёт в блок «Обновлено»
+// (если он не добавлен в этом же окне как новый).
+func (n *Notifier) AddDocumentUpdateForBatch(ctx context.Context, docID int, title string, sectionID *int) {
+	n.addToBatch(n.documentItem(ctx, docID, title, sectionID, true))
+}
 
-	n.sendToAll(context.WithoutCancel(ctx), "Новая статья на Edutalks", html)
+// AddArticleUpdateForBatch — правка опубликованной статьи, блок «Обновлено».
+func (n *Notifier) AddArticleUpdateForBatch(ctx context.Context, articleID int, title string) {
+	n.addToBatch(&batchItem{ID: articleID, Title: title, Article: true, Updated: true})
 }
 
-// AddDocumentForBatch — добавляем документ в временный буфер для групповой рассылки
-func (n *Notifier) AddDocumentForBatch(ctx context.Context, title string, tabsID *int) {
-	base := strings.TrimRight(n.baseURL, "/")
-	link := base + "/documents"
-	if tabsID != nil {
-		if slug, err := n.taxRepo.GetTabSlugByID(ctx, *tabsID); err == nil && slug != "" {
-			link = base + "/" + url.PathEscape(slug)
-		} else if err != nil {
-			logger.Log.Warn("Не удалось получить slug вкладки (batch)", zap.Error(err), zap.Intp("tab_id", tabsID))
+func (n *Notifier) documentItem(ctx context.Context, docID int, title string, sectionID *int, updated bool) *batchItem {
+	item := &batchItem{ID: docID, Title: title, Updated: updated}
+	if sectionID != nil {
+		if tab, sec, err := n.taxRepo.GetSectionPlacement(ctx, *sectionID); err == nil {
+			item.Tab, item.Section = tab, sec
+		} else {
+			logger.Log.Warn("Не удалось получить раздел документа (batch)", zap.Error(err), zap.Intp("section_id", sectionID))
 		}
 	}
+	return item
+}
 
-	item := fmt.Sprintf(`<li><a href="%s">%s</a></li>`, link, title)
+func (n *Notifier) addToBatch(item *batchItem) {
+	key := fmt.Sprintf("doc:%d", item.ID)
+	switch {
+	case item.Article:
+		key = fmt.Sprintf("article:%d", item.ID)
+	case len(item.Titles) > 0:
+		key = fmt.Sprintf("docs:%d", item.ID)
+	}
 
 	n.mu.Lock()
-	n.buffer = append(n.buffer, item)
-	size := len(n.buffer)
+	if n.buffer == nil {
+		n.buffer = map[string]*batchItem{}
+	}
+	prev, dup := n.buffer[key]
+	if !dup {
+		n.order = append(n.order, key)
+	} else if !prev.Updated {
+		item.Updated = false // новый в этом окне — остаётся в списке новых
+	}
+	n.buffer[key] = item
+	size := len(n.order)
 	n.mu.Unlock()
 
-	logger.Log.Info("Документ добавлен в батч-буфер",
-		zap.String("title", title),
-		zap.String("link", link),
+	logger.Log.Info("Материал добавлен в батч-буфер",
+		zap.String("key", key),
+		zap.String("title", item.Title),
+		zap.Bool("updated", item.Updated),
+		zap.Bool("duplicate", dup),
 		zap.Int("buffer_size", size),
 	)
 
 	// запускаем воркер только один раз
 	n.once.Do(func() {
 		logger.Log.Info("Старт батч-воркера уведомлений документов")
+		n.stopped = make(chan struct{})
 		go n.startBatchWorker()
 	})
 }
 
+// buildBatchBody — новые документы и отдельно «Обновлено», сгруппированные по вкладкам и разделам.
+func (n *Notifier) buildBatchBody(locale string, items []*batchItem) string {
+	var fresh, updated []*batchItem
+	for _, it := range items {
+		if it.Updated {
+			updated = append(updated, it)
+		} else {
+			fresh = append(fresh, it)
+		}
+	}
+
+	var b strings.Builder
+	if len(fresh) > 0 {
+		fmt.Fprintf(&b, "<p>%s</p>", helpers.EmailPhrase(locale, helpers.TextBatchIntro))
+		n.writeBatchGroups(&b, locale, fresh)
+	}
+	if len(updated) > 0 {
+		fmt.Fprintf(&b, `<h3 style="color:#2d74da;margin:24px 0 8px 0;">%s</h3>`, helpers.EmailPhrase(locale, helpers.TextBatchUpdated))
+		n.writeBatchGroups(&b, locale, updated)
+	}
+	return b.String()
+}
+
+func (n *Notifier) writeBatchGroups(b *strings.Builder, locale string, items []*batchItem) {
+	base := strings.TrimRight(n.baseURL, "/")
+
+	shown := items
+	if len(shown) > maxBatchItems {
+		shown = shown[:maxBatchItems]
+	}
+
+	type group struct {
+		title, link string
+		items       []*batchItem
+	}
+	var groups []*group
+	byKey := map[string]*group{}
+	for _, it := range shown {
+		key, title, link := "", helpers.EmailPhrase(locale, helpers.TextBatchNoSection), base+"/documents"
+		switch {
+		case it.Article:
+			key, title, link = "articles", helpers.EmailPhrase(locale, helpers.TextBatchArticles), base+"/zavuch"
+		case it.Section != nil:
+			key = it.Section.Path
+			title = it.Tab.Title + " → " + it.Section.Title
+			link = base + "/" + url.PathEscape(it.Tab.Slug) + "/" + url.PathEscape(it.Section.Slug)
+		}
+		g, ok := byKey[key]
+		if !ok {
+			g = &group{title: title, link: link}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.items = append(g.items, it)
+	}
+
+	for _, g := range groups {
+		fmt.Fprintf(b, `<p style="margin:16px 0 4px 0;"><a href="%s" style="color:#2d74da;font-weight:600;">%s</a></p><ul style="margin:0;">`,
+			g.link, html.EscapeString(g.title))
+		for _, it := range g.items {
+			link := g.link
+			if it.Article {
+				link = fmt.Sprintf("%s/zavuch/%d", base, it.ID)
+			}
+			if len(it.Titles) > 0 {
+				fmt.Fprintf(b, `<li><a href="%s">%s</a>: %s</li>`, link,
+					helpers.EmailPhrase(locale, helpers.TextBatchNewDocuments, len(it.Titles)), batchTitles(it.Titles))
+				continue
+			}
+			fmt.Fprintf(b, `<li><a href="%s">%s</a></li>`, link, html.EscapeString(it.Title))
+		}
+		b.WriteString("</ul>")
+	}
+	if rest := len(items) - len(shown); rest > 0 {
+		fmt.Fprintf(b, `<p style="margin-top:16px;"><a href="%s/documents" style="color:#2d74da;">и ещё %d — смотреть все материалы</a></p>`, base, rest)
+	}
+}
+
+// maxPackTitles — сколько названий пакета перечисляем в строке письма.
+const maxPackTitles = 5
+
+func batchTitles(titles []string) string {
+	shown := titles
+	if len(shown) > maxPackTitles {
+		shown = shown[:maxPackTitles]
+	}
+	escaped := make([]string, len(shown))
+	for i, t := range shown {
+		escaped[i] = html.EscapeString(t)
+	}
+	out := strings.Join(escaped, ", ")
+	if rest := len(titles) - len(shown); rest > 0 {
+		out += fmt.Sprintf(" и ещё %d", rest)
+	}
+	return out
+}
+
 func (n *Notifier) startBatchWorker() {
-	ticker := time.NewTicker(10 * time.Minute) // период можно вынести в конфиг
+	defer close(n.stopped)
+	ticker := time.NewTicker(n.interval)
 	defer ticker.Stop()
 
-	logger.Log.Info("Батч-воркер запущен", zap.String("period", "10m"))
+	logger.Log.Info("Батч-воркер запущен", zap.Duration("period", n.interval))
 
-	for range ticker.C {
-		n.mu.Lock()
-		if len(n.buffer) == 0 {
-			n.mu.Unlock()
-			logger.Log.Debug("Батч-тик: буфер пуст — рассылка пропущена")
-			continue
+	for {
+		select {
+		case <-ticker.C:
+			_, _ = n.flush(context.Background()) // при ошибке батч остаётся в буфере до следующего тика
+		case <-n.done:
+			logger.Log.Info("Батч-воркер остановлен")
+			return
 		}
+	}
+}
 
-		items := make([]string, len(n.buffer))
-		copy(items, n.buffer)
-		n.buffer = nil
+// Flush — отправляет накопленный батч сейчас, не дожидаясь тика; возвращает число материалов в письме.
+// При ошибке материалы возвращаются в буфер и уйдут со следующей рассылкой.
+func (n *Notifier) Flush(ctx context.Context) (int, error) {
+	return n.flush(ctx)
+}
+
+// Stop — останавливает батч-воркер, дожидается текущей рассылки и отправляет то, что успело
+// накопиться. Вызывать до StopEmailWorkers: письмо ставится в EmailQueue.
+func (n *Notifier) Stop() {
+	n.stopOnce.Do(func() {
+		close(n.done)
+		n.once.Do(func() {}) // воркер больше не запустится; n.stopped виден после once
+		if n.stopped != nil {
+			waitStopped("batch-notify", n.stopped)
+		}
+		count, err := n.Flush(context.Background())
+		switch {
+		case err != nil:
+			logger.Log.Error("Батч уведомлений при остановке не отправлен — материалы потеряны",
+				zap.Int("items_count", count), zap.Error(err))
+		case count > 0:
+			logger.Log.Info("Батч уведомлений отправлен при остановке", zap.Int("items_count", count))
+		}
+	})
+}
+
+// flush — отправляет накопленный батч (документы раздела — только отслеживающим его);
+// возвращает число материалов. Если получателей узнать не удалось, батч возвращается в буфер.
+func (n *Notifier) flush(ctx context.Context) (int, error) {
+	n.mu.Lock()
+	if len(n.order) == 0 {
 		n.mu.Unlock()
+		logger.Log.Debug("Батч-тик: буфер пуст — рассылка пропущена")
+		return 0, nil
+	}
 
-		body := "<p>За последние 10 минут добавлены документы:</p><ul>"
-		body += strings.Join(items, "")
-		body += "</ul>"
+	keys := n.order
+	items := make([]*batchItem, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, n.buffer[key])
+	}
+	n.buffer = nil
+	n.order = nil
+	n.mu.Unlock()
 
-		logger.Log.Info("Флаш батча документов",
-			zap.Int("items_count", len(items)),
-		)
+	logger.Log.Info("Флаш батча документов",
+		zap.Int("items_count", len(items)),
+	)
+
+	ctx = context.WithoutCancel(ctx)
+	groups, err := n.batchRecipients(ctx, items)
+	if err != nil {
+		n.requeue(keys, items)
+		logger.Log.Error("Не удалось получить получателей рассылки — материалы возвращены в буфер",
+			zap.Int("items_count", len(items)), zap.Error(err))
+		return len(items), err
+	}
+	for _, g := range groups {
+		title, subject := helpers.TextBatchNewTitle, helpers.MailBatchNew
+		if !hasNewItems(g.items) {
+			title, subject = helpers.TextBatchUpdatedTitle, helpers.MailBatchUpdated
+		}
+		for locale, emails := range n.byLocale(ctx, g.emails) {
+			n.sendTo(emails, helpers.EmailSubject(locale, subject),
+				helpers.BuildSimpleHTML(locale, helpers.EmailPhrase(locale, title), n.buildBatchBody(locale, g.items)))
+		}
+	}
+
+	logger.Log.Debug("Буфер батча очищен после отправки", zap.Int("letters", len(groups)))
+	return len(items), nil
+}
+
+// requeue — возвращает неотправленный батч в начало буфера. Материал, добавленный заново
+// за время рассылки, остаётся в новой версии (и новым, если был новым в батче).
+func (n *Notifier) requeue(keys []string, items []*batchItem) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
 
-		html := helpers.BuildSimpleHTML("Новые документы на сайте", body)
-		n.sendToAll(context.Background(), "Новые документы на Edutalks", html)
+	buffer := make(map[string]*batchItem, len(keys)+len(n.order))
+	order := make([]string, 0, len(keys)+len(n.order))
+	for i, key := range keys {
+		buffer[key] = items[i]
+		order = append(order, key)
+	}
+	for _, key := range n.order {
+		item := n.buffer[key]
+		if prev, dup := buffer[key]; dup {
+			if !prev.Updated {
+				item.Updated = false
+			}
+		} else {
+			order = append(order, key)
+		}
+		buffer[key] = item
+	}
+	n.buffer, n.order = buffer, order
+}
 
-		logger.Log.Debug("Буфер батча очищен после отправки")
+// batchGroup — получатели, которым приходит письмо с одним и тем же набором материалов.
+type batchGroup struct {
+	items  []*batchItem
+	emails []string
+}
+
+// batchRecipients — кому что отправить: статьи и документы без раздела — всем подписчикам
+// рассылки, документы раздела — только отслеживающим этот раздел (или его родителя).
+// Адресаты с одинаковым набором материалов получают одно письмо.
+func (n *Notifier) batchRecipients(ctx context.Context, items []*batchItem) ([]*batchGroup, error) {
+	all, err := n.subsRepo.GetAllSubscribedEmails(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	followers := map[int][]string{} // раздел → адреса отслеживающих
+	perEmail := map[string][]int{}  // адрес → индексы материалов в items, по порядку
+	var order []string
+	add := func(email string, i int) {
+		if _, ok := perEmail[email]; !ok {
+			order = append(order, email)
+		}
+		perEmail[email] = append(perEmail[email], i)
+	}
+
+	for i, it := range items {
+		if it.Article || it.Section == nil {
+			for _, e := range all {
+				add(e, i)
+			}
+			continue
+		}
+		emails, ok := followers[it.Section.ID]
+		if !ok {
+			if emails, err = n.follows.FollowerEmails(ctx, it.Section.ID); err != nil {
+				return nil, err
+			}
+			followers[it.Section.ID] = emails
+		}
+		for _, e := range emails {
+			add(e, i)
+		}
+	}
+
+	var groups []*batchGroup
+	bySet := map[string]*batchGroup{}
+	for _, email := range order {
+		idx := perEmail[email]
+		key := fmt.Sprint(idx)
+		g, ok := bySet[key]
+		if !ok {
+			g = &batchGroup{}
+			for _, i := range idx {
+				g.items = append(g.items, items[i])
+			}
+			bySet[key] = g
+			groups = append(groups, g)
+		}
+		g.emails = append(g.emails, email)
+	}
+	return groups, nil
+}
+
+func hasNewItems(items []*batchItem) bool {
+	for _, it := range items {
+		if !it.Updated {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,30 @@
+package services
+
+import "testing"
+
+func TestNotifierRequeue(t *testing.T) {
+	n := &Notifier{}
+	batch := []*batchItem{{ID: 1, Title: "a"}, {ID: 2, Title: "b"}}
+
+	// пока рассылка шла, документ 2 пересохранили, а документ 3 добавили
+	n.buffer = map[string]*batchItem{
+		"doc:2": {ID: 2, Title: "b2", Updated: true},
+		"doc:3": {ID: 3, Title: "c"},
+	}
+	n.order = []string{"doc:2", "doc:3"}
+
+	n.requeue([]string{"doc:1", "doc:2"}, batch)
+
+	want := []string{"doc:1", "doc:2", "doc:3"}
+	if len(n.order) != len(want) {
+		t.Fatalf("order = %v, want %v", n.order, want)
+	}
+	for i, key := range want {
+		if n.order[i] != key {
+			t.Fatalf("order = %v, want %v", n.order, want)
+		}
+	}
+	if b := n.buffer["doc:2"]; b.Title != "b2" || b.Updated {
+		t.Errorf("doc:2 = %+v, want свежий заголовок и по-прежнему новый", b)
+	}
+}
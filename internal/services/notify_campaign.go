@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// campaignTick — как часто проверяем наступившие рассылки.
+const campaignTick = time.Minute
+
+var (
+	ErrCampaignNotFound    = errors.New("рассылка не найдена")
+	ErrCampaignNotEditable = errors.New("рассылка уже отправлена или отменена")
+)
+
+// NotifyCampaignService — рассылки администратора по расписанию, разовые и повторяющиеся.
+// Письма уходят так же, как при ручной рассылке (AuthService.NotifySubscribers), с привязкой
+// к рассылке в email_outbox — по ней считается статистика доставки.
+type NotifyCampaignService struct {
+	repo  *repository.NotifyCampaignRepository
+	users *AuthService
+	locks JobLocker
+}
+
+func NewNotifyCampaignService(repo *repository.NotifyCampaignRepository, users *AuthService, locks JobLocker) *NotifyCampaignService {
+	return &NotifyCampaignService{repo: repo, users: users, locks: locks}
+}
+
+// campaignFromRequest — рассылка из запроса с проверкой шаблона и дат аудитории.
+func campaignFromRequest(req models.NotifyCampaignRequest) (*models.NotifyCampaign, error) {
+	c := &models.NotifyCampaign{
+		Subject:  req.Subject,
+		Message:  req.Message,
+		Template: req.Template,
+		URL:      req.URL,
+		SendAt:   req.SendAt.UTC(),
+		Repeat:   req.Repeat,
+	}
+	if req.Audience != nil {
+		c.Audience = *req.Audience
+	}
+	if c.Repeat == "" {
+		c.Repeat = models.CampaignRepeatNone
+	}
+	msg := campaignMessage(c)
+	if err := prepareNotify(&msg, &c.Audience); err != nil {
+		return nil, err
+	}
+	c.Template = msg.Template
+	return c, nil
+}
+
+func campaignMessage(c *models.NotifyCampaign) models.NotifyMessage {
+	return models.NotifyMessage{Subject: c.Subject, Message: c.Message, Template: c.Template, URL: c.URL}
+}
+
+func (s *NotifyCampaignService) Create(ctx context.Context, adminID int, req models.NotifyCampaignRequest) (*models.NotifyCampaign, error) {
+	c, err := campaignFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if adminID > 0 {
+		c.CreatedBy = &adminID
+	}
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	logger.WithCtx(ctx).Info("Рассылка запланирована",
+		zap.Int64("campaign_id", c.ID), zap.Time("send_at", c.SendAt), zap.String("repeat", c.Repeat), zap.Int("admin_id", adminID))
+	return c, nil
+}
+
+func (s *NotifyCampaignService) List(ctx context.Context, status string, limit, offset int) ([]*models.NotifyCampaign, int, error) {
+	return s.repo.List(ctx, status, limit, offset)
+}
+
+func (s *NotifyCampaignService) Get(ctx context.Context, id int64) (*models.NotifyCampaign, error) {
+	c, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCampaignNotFound
+	}
+	return c, err
+}
+
+// Update — правка ожидающей рассылки; отправленную или отменённую менять нельзя.
+func (s *NotifyCampaignService) Update(ctx context.Context, id int64, req models.NotifyCampaignRequest) (*models.NotifyCampaign, error) {
+	c, err := campaignFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	c.ID = id
+	if err := s.repo.Update(ctx, c); errors.Is(err, pgx.ErrNoRows) {
+		if _, gerr := s.Get(ctx, id); gerr != nil {
+			return nil, gerr
+		}
+		return nil, ErrCampaignNotEditable
+	} else if err != nil {
+		return nil, err
+	}
+	logger.WithCtx(ctx).Info("Рассылка изменена", zap.Int64("campaign_id", id), zap.Time("send_at", c.SendAt))
+	return s.Get(ctx, id)
+}
+
+func (s *NotifyCampaignService) Cancel(ctx context.Context, id int64) (*models.NotifyCampaign, error) {
+	ok, err := s.repo.Cancel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrCampaignNotEditable
+	}
+	logger.WithCtx(ctx).Info("Рассылка отменена", zap.Int64("campaign_id", id))
+	return c, nil
+}
+
+// nextCampaignRun — следующий запуск повторяющейся рассылки после now (пропущенные запуски
+// не догоняем); nil — рассылка разовая. Месяц и неделя считаются по московскому календарю.
+func nextCampaignRun(c *models.NotifyCampaign, now time.Time) *time.Time {
+	step := func(t time.Time) time.Time {
+		switch c.Repeat {
+		case models.CampaignRepeatDaily:
+			return t.AddDate(0, 0, 1)
+		case models.CampaignRepeatWeekly:
+			return t.AddDate(0, 0, 7)
+		default:
+			return t.AddDate(0, 1, 0)
+		}
+	}
+	switch c.Repeat {
+	case models.CampaignRepeatDaily, models.CampaignRepeatWeekly, models.CampaignRepeatMonthly:
+	default:
+		return nil
+	}
+	at := step(c.SendAt.In(quotaZone))
+	for !at.After(now) {
+		at = step(at)
+	}
+	at = at.UTC()
+	return &at
+}
+
+// RunDue — отправляет рассылки с наступившим временем; возвращает число поставленных писем.
+func (s *NotifyCampaignService) RunDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.repo.ClaimDue(ctx, now, func(c *models.NotifyCampaign) *time.Time {
+		return nextCampaignRun(c, now)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, c := range due {
+		msg, audience := campaignMessage(c), c.Audience
+		if err := prepareNotify(&msg, &audience); err != nil {
+			logger.Log.Error("Рассылка пропущена: некорректные параметры", zap.Int64("campaign_id", c.ID), zap.Error(err))
+			continue
+		}
+		id := c.ID
+		n, err := s.users.sendNotify(ctx, msg, audience, false, &id)
+		if err != nil {
+			logger.Log.Error("Ошибка отправки рассылки", zap.Int64("campaign_id", c.ID), zap.Error(err))
+			continue
+		}
+		logger.Log.Info("Рассылка отправлена по расписанию", zap.Int64("campaign_id", c.ID), zap.Int("recipients", n))
+		total += n
+	}
+	return total, nil
+}
+
+// Start — раз в минуту отправляет наступившие рассылки; возвращает функцию остановки.
+// Вызывать остановку до StopEmailWorkers: письма ставятся в EmailQueue.
+func (s *NotifyCampaignService) Start() func() {
+	ticker := time.NewTicker(campaignTick)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		logger.Log.Info("Рассылки по расписанию: планировщик запущен", zap.Duration("interval", campaignTick))
+		run := func() {
+			_ = RunExclusive(context.Background(), s.locks, JobNotifyCampaigns, func(ctx context.Context) error {
+				_, err := s.RunDue(ctx, time.Now())
+				return err
+			})
+		}
+		run()
+		for {
+			select {
+			case <-ticker.C:
+				run()
+			case <-done:
+				ticker.Stop()
+				logger.Log.Info("Рассылки по расписанию: планировщик остановлен")
+				return
+			}
+		}
+	}()
+
+	return StopAndWait(JobNotifyCampaigns, done, stopped)
+}
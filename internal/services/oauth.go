@@ -0,0 +1,481 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/events"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/endpoints"
+)
+
+var (
+	ErrOAuthUnknownProvider = errors.New("провайдер входа не поддерживается или не настроен")
+	ErrOAuthState           = errors.New("ссылка входа устарела, начните заново")
+	ErrOAuthEmailUnverified = errors.New("провайдер не подтвердил адрес почты")
+	ErrOAuthEmailTaken      = errors.New("адрес уже зарегистрирован: войдите по паролю и привяжите аккаунт в профиле")
+	ErrOAuthIdentityTaken   = errors.New("этот аккаунт уже привязан к другому пользователю")
+	ErrOAuthNotLinked       = errors.New("аккаунт провайдера не привязан")
+)
+
+const oauthStateTTL = 10 * time.Minute
+
+// oauthProfile — пользователь провайдера.
+type oauthProfile struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type oauthProvider struct {
+	conf    *oauth2.Config
+	profile func(ctx context.Context, c *http.Client, accessToken string) (*oauthProfile, error)
+}
+
+// OAuthResult — итог возврата от провайдера: LinkedTo != 0 — аккаунт привязан к профилю,
+// иначе User — пользователь для входа (Created — только что зарегистрирован).
+type OAuthResult struct {
+	Provider string
+	User     *models.User
+	Created  bool
+	LinkedTo int
+}
+
+// OAuthService — вход через Google/Яндекс (OAuth2 authorization code) и привязка внешних
+// аккаунтов к профилю. Провайдер без client id/secret в конфиге выключен.
+type OAuthService struct {
+	repo  *repository.OAuthRepository
+	users *repository.UserRepository
+
+	providers map[string]oauthProvider
+	cfg       *config.Holder
+	http      *http.Client
+}
+
+func NewOAuthService(repo *repository.OAuthRepository, users *repository.UserRepository, live *config.Holder) *OAuthService {
+	cfg := live.Get()
+	redirectBase := strings.TrimRight(strings.TrimSpace(cfg.OAuthRedirectBaseURL), "/")
+	conf := func(provider, id, secret string, endpoint oauth2.Endpoint, scopes ...string) *oauth2.Config {
+		return &oauth2.Config{
+			ClientID:     id,
+			ClientSecret: secret,
+			Endpoint:     endpoint,
+			RedirectURL:  redirectBase + "/" + provider + "/callback",
+			Scopes:       scopes,
+		}
+	}
+
+	providers := make(map[string]oauthProvider)
+	if cfg.OAuthGoogleClientID != "" && cfg.OAuthGoogleClientSecret != "" {
+		providers[models.OAuthProviderGoogle] = oauthProvider{
+			conf: conf(models.OAuthProviderGoogle, cfg.OAuthGoogleClientID, cfg.OAuthGoogleClientSecret,
+				endpoints.Google, "openid", "email", "profile"),
+			profile: googleProfile,
+		}
+	}
+	if cfg.OAuthYandexClientID != "" && cfg.OAuthYandexClientSecret != "" {
+		providers[models.OAuthProviderYandex] = oauthProvider{
+			conf: conf(models.OAuthProviderYandex, cfg.OAuthYandexClientID, cfg.OAuthYandexClientSecret,
+				endpoints.Yandex, "login:email", "login:info"),
+			profile: yandexProfile,
+		}
+	}
+	return &OAuthService{
+		repo:      repo,
+		users:     users,
+		providers: providers,
+		cfg:       live,
+		http:      &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Providers — включённые провайдеры.
+func (s *OAuthService) Providers() []string {
+	list := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// oauthStateAudience — назначение подписанной cookie входа, чтобы её нельзя было выдать за другой токен.
+const oauthStateAudience = "edutalks/oauth-state"
+
+// oauthState — cookie браузера, начавшего вход: провайдер, режим (UserID != 0 — привязка
+// к профилю), значение параметра state и PKCE-верификатор. Подписана JWT (HS256).
+type oauthState struct {
+	Provider string `json:"p"`
+	UserID   int    `json:"u,omitempty"`
+	State    string `json:"s"`
+	Verifier string `json:"v"`
+	jwt.RegisteredClaims
+}
+
+// Begin — ссылка на страницу входа провайдера и значение cookie, которая привязывает возврат
+// к этому браузеру. userID != 0 — привязка аккаунта к профилю, 0 — вход.
+func (s *OAuthService) Begin(provider string, userID int) (authURL, cookie string, err error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return "", "", ErrOAuthUnknownProvider
+	}
+	key := s.stateKey()
+	if key == nil {
+		return "", "", errors.New("oauth: JWT_SECRET не задан")
+	}
+
+	st := oauthState{
+		Provider: provider,
+		UserID:   userID,
+		State:    oauth2.GenerateVerifier(),
+		Verifier: oauth2.GenerateVerifier(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{oauthStateAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(oauthStateTTL)),
+		},
+	}
+	cookie, err = jwt.NewWithClaims(jwt.SigningMethodHS256, st).SignedString(key)
+	if err != nil {
+		return "", "", err
+	}
+	return p.conf.AuthCodeURL(st.State, oauth2.S256ChallengeOption(st.Verifier)), cookie, nil
+}
+
+// stateKey — ключ подписи cookie входа, выведенный из JWT_SECRET текущей конфигурации
+// (меняется по SIGHUP); nil — секрет не задан.
+func (s *OAuthService) stateKey() []byte {
+	secret := s.cfg.Get().JWTSecret
+	if secret == "" {
+		return nil
+	}
+	key := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(secret), nil, []byte("edutalks/oauth-state/v1")), key); err != nil {
+		return nil
+	}
+	return key
+}
+
+// parseState — cookie входа цела, не истекла, выдана для provider и совпадает с параметром state.
+func (s *OAuthService) parseState(provider, state, cookie string) (*oauthState, error) {
+	key := s.stateKey()
+	if key == nil || state == "" || cookie == "" {
+		return nil, ErrOAuthState
+	}
+	var st oauthState
+	_, err := jwt.ParseWithClaims(cookie, &st, func(*jwt.Token) (any, error) { return key, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(oauthStateAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || st.Provider != provider || subtle.ConstantTimeCompare([]byte(st.State), []byte(state)) != 1 {
+		return nil, ErrOAuthState
+	}
+	return &st, nil
+}
+
+// Complete — обработка возврата от провайдера: обмен code на токен (с PKCE-верификатором из cookie),
+// профиль провайдера, затем привязка к профилю (вход начат из профиля) или поиск/регистрация
+// пользователя для входа.
+func (s *OAuthService) Complete(ctx context.Context, provider, code, state, cookie string) (*OAuthResult, error) {
+	log := logger.WithCtx(ctx).With(zap.String("provider", provider))
+
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, ErrOAuthUnknownProvider
+	}
+	st, err := s.parseState(provider, state, cookie)
+	if err != nil {
+		return nil, err
+	}
+	token, err := p.conf.Exchange(context.WithValue(ctx, oauth2.HTTPClient, s.http), code, oauth2.VerifierOption(st.Verifier))
+	if err != nil {
+		log.Error("OAuth: ошибка обмена кода на токен", zap.Error(err))
+		return nil, err
+	}
+	prof, err := p.profile(ctx, s.http, token.AccessToken)
+	if err != nil {
+		log.Error("OAuth: ошибка получения профиля", zap.Error(err))
+		return nil, err
+	}
+	prof.Email = strings.ToLower(strings.TrimSpace(prof.Email))
+
+	if st.UserID != 0 {
+		if err := s.link(ctx, st.UserID, provider, prof); err != nil {
+			return nil, err
+		}
+		return &OAuthResult{Provider: provider, LinkedTo: st.UserID}, nil
+	}
+	return s.login(ctx, provider, prof)
+}
+
+func (s *OAuthService) link(ctx context.Context, userID int, provider string, prof *oauthProfile) error {
+	owner, err := s.repo.FindUserID(ctx, provider, prof.Subject)
+	switch {
+	case err == nil && owner == userID:
+		return nil
+	case err == nil:
+		return ErrOAuthIdentityTaken
+	case !errors.Is(err, pgx.ErrNoRows):
+		return err
+	}
+
+	err = s.repo.Link(ctx, &models.OAuthIdentity{UserID: userID, Provider: provider, Subject: prof.Subject, Email: prof.Email})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		// к профилю уже привязан другой аккаунт этого провайдера
+		return ErrOAuthIdentityTaken
+	}
+	if err != nil {
+		return err
+	}
+	logger.WithCtx(ctx).Info("OAuth: аккаунт привязан", zap.Int("user_id", userID), zap.String("provider", provider))
+	return nil
+}
+
+// login — пользователь по привязке; иначе по подтверждённому у провайдера email: существующий
+// пользователь с подтверждённым адресом получает привязку, а если адрес свободен — регистрируется
+// новый.
+func (s *OAuthService) login(ctx context.Context, provider string, prof *oauthProfile) (*OAuthResult, error) {
+	log := logger.WithCtx(ctx).With(zap.String("provider", provider))
+
+	userID, err := s.repo.FindUserID(ctx, provider, prof.Subject)
+	if err == nil {
+		u, err := s.users.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return &OAuthResult{Provider: provider, User: u}, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	if prof.Email == "" || !prof.EmailVerified {
+		return nil, ErrOAuthEmailUnverified
+	}
+	identity := &models.OAuthIdentity{Provider: provider, Subject: prof.Subject, Email: prof.Email}
+
+	existing, err := s.users.GetUserByEmail(ctx, prof.Email)
+	if err == nil {
+		if !existing.EmailVerified {
+			return nil, ErrOAuthEmailTaken
+		}
+		identity.UserID = existing.ID
+		if err := s.repo.Link(ctx, identity); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return nil, ErrOAuthIdentityTaken
+			}
+			return nil, err
+		}
+		log.Info("OAuth: аккаунт привязан по подтверждённому email", zap.Int("user_id", existing.ID))
+		return &OAuthResult{Provider: provider, User: existing}, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	u, err := s.register(ctx, prof, identity)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("OAuth: пользователь зарегистрирован", zap.Int("user_id", u.ID))
+	return &OAuthResult{Provider: provider, User: u, Created: true}, nil
+}
+
+// register — новый пользователь со случайным паролем (задать свой — через восстановление пароля).
+func (s *OAuthService) register(ctx context.Context, prof *oauthProfile, identity *models.OAuthIdentity) (*models.User, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	hash, err := utils.HashPassword(hex.EncodeToString(raw))
+	if err != nil {
+		return nil, err
+	}
+	username, err := s.freeUsername(ctx, prof.Email)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(prof.Name)
+	if name == "" {
+		name = username
+	}
+
+	u := &models.User{
+		Username:     username,
+		FullName:     name,
+		Email:        prof.Email,
+		PasswordHash: hash,
+		Role:         "user",
+	}
+	if err := s.repo.CreateUserWithIdentity(ctx, u, identity); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrOAuthEmailTaken
+		}
+		return nil, err
+	}
+
+	events.Publish(ctx, events.UserRegistered, &u.ID, map[string]any{
+		"username": u.Username,
+		"provider": identity.Provider,
+	})
+	return u, nil
+}
+
+// freeUsername — имя пользователя из локальной части email (a-z, 0-9, _ и .), при занятости —
+// с числовым суффиксом.
+func (s *OAuthService) freeUsername(ctx context.Context, email string) (string, error) {
+	local, _, _ := strings.Cut(email, "@")
+	var b strings.Builder
+	for _, r := range strings.ToLower(local) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '.' {
+			b.WriteRune(r)
+		}
+	}
+	base := b.String()
+	if len(base) > 40 {
+		base = base[:40]
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		taken, err := s.users.IsUsernameTaken(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		raw := make([]byte, 3)
+		if _, err := rand.Read(raw); err != nil {
+			return "", err
+		}
+		candidate = base + "_" + hex.EncodeToString(raw)
+	}
+	return "", errors.New("не удалось подобрать имя пользователя")
+}
+
+// Identities — привязанные к пользователю внешние аккаунты.
+func (s *OAuthService) Identities(ctx context.Context, userID int) ([]models.OAuthIdentity, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// Unlink — отвязывает аккаунт провайдера от пользователя.
+func (s *OAuthService) Unlink(ctx context.Context, userID int, provider string) error {
+	ok, err := s.repo.Unlink(ctx, userID, provider)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrOAuthNotLinked
+	}
+	logger.WithCtx(ctx).Info("OAuth: аккаунт отвязан", zap.Int("user_id", userID), zap.String("provider", provider))
+	return nil
+}
+
+func googleProfile(ctx context.Context, c *http.Client, accessToken string) (*oauthProfile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://openidconnect.googleapis.com/v1/userinfo", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	var res struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := doOAuthJSON(c, req, &res); err != nil {
+		return nil, err
+	}
+	if res.Sub == "" {
+		return nil, errors.New("oauth: google не вернул id пользователя")
+	}
+	return &oauthProfile{Subject: res.Sub, Email: res.Email, EmailVerified: res.EmailVerified, Name: res.Name}, nil
+}
+
+// yandexMailDomains — почта, которую обслуживает сам Яндекс. Адрес в default_email может быть
+// и чужим (Gmail, почта для домена), владение им Яндекс не подтверждает.
+var yandexMailDomains = map[string]bool{
+	"yandex.ru": true, "yandex.com": true, "yandex.by": true, "yandex.kz": true,
+	"yandex.ua": true, "yandex.com.tr": true, "ya.ru": true, "narod.ru": true,
+}
+
+// yandexProfile — подтверждённым считается только адрес на домене Яндекса: по нему вход
+// привязывается к существующему пользователю, остальные адреса — только явная привязка из профиля.
+func yandexProfile(ctx context.Context, c *http.Client, accessToken string) (*oauthProfile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://login.yandex.ru/info?format=json", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "OAuth "+accessToken)
+
+	var res struct {
+		ID           string `json:"id"`
+		DefaultEmail string `json:"default_email"`
+		RealName     string `json:"real_name"`
+		DisplayName  string `json:"display_name"`
+	}
+	if err := doOAuthJSON(c, req, &res); err != nil {
+		return nil, err
+	}
+	if res.ID == "" {
+		return nil, errors.New("oauth: яндекс не вернул id пользователя")
+	}
+	name := res.RealName
+	if name == "" {
+		name = res.DisplayName
+	}
+	return &oauthProfile{Subject: res.ID, Email: res.DefaultEmail, EmailVerified: yandexHosted(res.DefaultEmail), Name: name}, nil
+}
+
+func yandexHosted(email string) bool {
+	_, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	return ok && yandexMailDomains[domain]
+}
+
+func doOAuthJSON(c *http.Client, req *http.Request, out any) error {
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("oauth: %s ответил %d", req.URL.Host, resp.StatusCode)
+	}
+	return json.Unmarshal(body, out)
+}
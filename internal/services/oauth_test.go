@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"edutalks/internal/config"
+	"edutalks/internal/models"
+)
+
+func TestOAuthState(t *testing.T) {
+	live := config.NewHolder(&config.Config{
+		JWTSecret:               "secret",
+		OAuthGoogleClientID:     "id",
+		OAuthGoogleClientSecret: "client-secret",
+		OAuthRedirectBaseURL:    "https://api.example.com/api/auth/oauth/",
+	})
+	s := NewOAuthService(nil, nil, live)
+
+	authURL, cookie, err := s.Begin(models.OAuthProviderGoogle, 7)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("authURL: %v", err)
+	}
+	q := u.Query()
+	if q.Get("redirect_uri") != "https://api.example.com/api/auth/oauth/google/callback" ||
+		q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		t.Errorf("authURL = %s", authURL)
+	}
+
+	st, err := s.parseState(models.OAuthProviderGoogle, q.Get("state"), cookie)
+	if err != nil || st.UserID != 7 || st.Verifier == "" {
+		t.Fatalf("parseState = %+v, %v", st, err)
+	}
+	// state из другого браузера, чужой провайдер или другой ключ подписи не подходят
+	if _, err := s.parseState(models.OAuthProviderGoogle, "other", cookie); !errors.Is(err, ErrOAuthState) {
+		t.Errorf("чужой state: %v", err)
+	}
+	if _, err := s.parseState(models.OAuthProviderYandex, q.Get("state"), cookie); !errors.Is(err, ErrOAuthState) {
+		t.Errorf("чужой провайдер: %v", err)
+	}
+	rotated := NewOAuthService(nil, nil, config.NewHolder(&config.Config{JWTSecret: "rotated"}))
+	if _, err := rotated.parseState(models.OAuthProviderGoogle, q.Get("state"), cookie); !errors.Is(err, ErrOAuthState) {
+		t.Errorf("другой ключ: %v", err)
+	}
+}
+
+func TestYandexHosted(t *testing.T) {
+	for email, want := range map[string]bool{
+		"ivan@yandex.ru":     true,
+		" Ivan@YA.RU ":       true,
+		"ivan@yandex.com.tr": true,
+		"ivan@gmail.com":     false,
+		"ivan@school.ru":     false, // почта для домена — владение не подтверждено
+		"ivan@notyandex.ru":  false,
+		"":                   false,
+	} {
+		if got := yandexHosted(email); got != want {
+			t.Errorf("yandexHosted(%q) = %v, want %v", email, got, want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"edutalks/internal/events"
+	"edutalks/internal/logger"
+	"edutalks/internal/metrics"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	outboxRelayInterval    = 2 * time.Second
+	outboxRelayBatch       = 50
+	outboxRelayLease       = time.Minute
+	outboxRelayMaxAttempts = 10
+)
+
+var (
+	outboxDispatched = metrics.NewCounter("side_effect_outbox_dispatched_total", "Доставлено побочных эффектов из outbox")
+	outboxFailed     = metrics.NewCounter("side_effect_outbox_failed_total", "Побочных эффектов, исчерпавших попытки")
+)
+
+// EmailIntent — намерение отправить письмо (для записи в outbox внутри транзакции).
+func EmailIntent(job EmailJob) (models.OutboxIntent, error) {
+	b, err := json.Marshal(models.OutboxEmail{To: job.To, Subject: job.Subject, Body: job.Body, IsHTML: job.IsHTML})
+	return models.OutboxIntent{Kind: models.OutboxKindEmail, Payload: b}, err
+}
+
+// EventIntent — намерение опубликовать доменное событие.
+func EventIntent(eventType string, userID *int, payload map[string]any) (models.OutboxIntent, error) {
+	b, err := json.Marshal(models.OutboxEvent{Type: eventType, UserID: userID, Payload: payload})
+	return models.OutboxIntent{Kind: models.OutboxKindEvent, Payload: b}, err
+}
+
+// NotifyIntent — намерение разослать подписчикам письмо о публикации (notifyType — models.Notify*).
+func NotifyIntent(notifyType string, id int64, title string) (models.OutboxIntent, error) {
+	b, err := json.Marshal(models.OutboxNotify{Type: notifyType, ID: id, Title: title})
+	return models.OutboxIntent{Kind: models.OutboxKindNotify, Payload: b}, err
+}
+
+// OutboxRelay — доставляет намерения из side_effect_outbox после коммита транзакции (at-least-once):
+// письма и рассылки подписчикам перекладываются в email_outbox, события публикуются в шину.
+type OutboxRelay struct {
+	repo     *repository.OutboxRepository
+	emails   *repository.EmailOutboxRepository
+	notifier *Notifier
+}
+
+func NewOutboxRelay(repo *repository.OutboxRepository, emails *repository.EmailOutboxRepository, notifier *Notifier) *OutboxRelay {
+	return &OutboxRelay{repo: repo, emails: emails, notifier: notifier}
+}
+
+// RunOnce — обрабатывает одну пачку; возвращает количество взятых записей.
+func (r *OutboxRelay) RunOnce(ctx context.Context) (int, error) {
+	batch, err := r.repo.ClaimBatch(ctx, outboxRelayBatch, outboxRelayLease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, rec := range batch {
+		if err := r.dispatch(ctx, rec); err != nil {
+			if rec.Attempts >= outboxRelayMaxAttempts {
+				outboxFailed.Inc()
+				logger.Log.Error("Outbox: побочный эффект не доставлен, попытки исчерпаны",
+					zap.Int64("id", rec.ID), zap.String("kind", rec.Kind), zap.Error(err))
+				_ = r.repo.MarkFailed(ctx, rec.ID, err.Error())
+				continue
+			}
+			logger.Log.Warn("Outbox: ошибка доставки, повторим позже",
+				zap.Int64("id", rec.ID), zap.String("kind", rec.Kind), zap.Int("attempt", rec.Attempts), zap.Error(err))
+			_ = r.repo.MarkRetry(ctx, rec.ID, err.Error(), time.Now().Add(emailBackoff(rec.Attempts)))
+			continue
+		}
+		outboxDispatched.Inc()
+		_ = r.repo.MarkDone(ctx, rec.ID)
+	}
+	return len(batch), nil
+}
+
+func (r *OutboxRelay) dispatch(ctx context.Context, rec models.OutboxRecord) error {
+	switch rec.Kind {
+	case models.OutboxKindEmail:
+		var m models.OutboxEmail
+		if err := json.Unmarshal(rec.Payload, &m); err != nil {
+			return err
+		}
+		for _, batch := range ChunkEmails(m.To, emailBatchSize) {
+			if _, err := r.emails.Enqueue(ctx, batch, m.Subject, m.Body, m.IsHTML); err != nil {
+				return err
+			}
+		}
+		return nil
+	case models.OutboxKindEvent:
+		var e models.OutboxEvent
+		if err := json.Unmarshal(rec.Payload, &e); err != nil {
+			return err
+		}
+		events.Publish(ctx, e.Type, e.UserID, e.Payload)
+		return nil
+	case models.OutboxKindNotify:
+		var n models.OutboxNotify
+		if err := json.Unmarshal(rec.Payload, &n); err != nil {
+			return err
+		}
+		byLocale, err := r.notifier.Recipients(ctx)
+		if err != nil {
+			return err
+		}
+		total := 0
+		for locale, recipients := range byLocale {
+			subject, body, err := r.notifier.PublishedMessage(locale, n)
+			if err != nil {
+				return err
+			}
+			for _, batch := range ChunkEmails(recipients, emailBatchSize) {
+				if _, err := r.emails.Enqueue(ctx, batch, subject, body, true); err != nil {
+					return err
+				}
+			}
+			total += len(recipients)
+		}
+		logger.Log.Info("Outbox: рассылка о публикации поставлена в очередь",
+			zap.String("type", n.Type), zap.Int64("id", n.ID), zap.Int("recipients", total))
+		return nil
+	default:
+		return fmt.Errorf("неизвестный тип побочного эффекта: %s", rec.Kind)
+	}
+}
+
+// Start — опрашивает outbox по интервалу (пачки подряд, пока есть работа); возвращает функцию остановки.
+func (r *OutboxRelay) Start() func() {
+	ticker := time.NewTicker(outboxRelayInterval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		logger.Log.Info("OutboxRelay запущен")
+		for {
+			select {
+			case <-ticker.C:
+				for {
+					n, err := r.RunOnce(context.Background())
+					if err != nil {
+						logger.Log.Error("OutboxRelay: ошибка обработки", zap.Error(err))
+					}
+					if err != nil || n < outboxRelayBatch {
+						break
+					}
+				}
+			case <-done:
+				ticker.Stop()
+				logger.Log.Info("OutboxRelay остановлен")
+				return
+			}
+		}
+	}()
+
+	return StopAndWait("outbox_relay", done, stopped)
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/metrics"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils"
+	"edutalks/internal/utils/helpers"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrSMSDisabled          = errors.New("отправка SMS не настроена")
+	ErrPhoneMissing         = errors.New("в профиле не указан телефон")
+	ErrPhoneInvalid         = errors.New("некорректный номер телефона")
+	ErrPhoneAlreadyVerified = errors.New("телефон уже подтверждён")
+	ErrPhoneTaken           = errors.New("этот номер уже подтверждён другим пользователем")
+	ErrPhoneCodeCooldown    = errors.New("новый код можно запросить позже")
+	ErrPhoneCodeInvalid     = errors.New("неверный или устаревший код")
+	ErrPhoneCodeAttempts    = errors.New("исчерпаны попытки ввода кода, запросите новый")
+	ErrPhoneSendFailed      = errors.New("не удалось отправить SMS, попробуйте позже")
+)
+
+const (
+	phoneCodeDigits      = 6
+	phoneCodeMaxAttempts = 5
+)
+
+// Отправленные SMS с кодами по назначению (verify/reset) и исходу (sent/failed).
+var smsCodesSent = metrics.NewCounterVec("sms_codes_total", "SMS с кодами по назначению и исходу", "purpose", "result")
+
+// PhoneVerificationService — коды из SMS: подтверждение телефона в профиле и сброс пароля по номеру.
+// Код из 6 цифр действует SMS_CODE_TTL, новый — не чаще SMS_RESEND_COOLDOWN, после
+// phoneCodeMaxAttempts неверных вводов код больше не принимается.
+type PhoneVerificationService struct {
+	codes  *repository.PhoneCodeRepository
+	users  *repository.UserRepository
+	auth   *AuthService // сброс кэша пользователей
+	sender SMSSender    // nil — SMS выключены
+
+	ttl           time.Duration
+	cooldown      time.Duration
+	resetVerified bool // код сброса — только на подтверждённый номер
+}
+
+func NewPhoneVerificationService(codes *repository.PhoneCodeRepository, users *repository.UserRepository, auth *AuthService, sender SMSSender, cfg *config.Config) *PhoneVerificationService {
+	s := &PhoneVerificationService{
+		codes:         codes,
+		users:         users,
+		auth:          auth,
+		sender:        sender,
+		ttl:           10 * time.Minute,
+		cooldown:      time.Minute,
+		resetVerified: cfg.PhoneResetVerifiedOnly(),
+	}
+	if d, err := time.ParseDuration(strings.TrimSpace(cfg.SMSCodeTTL)); err == nil && d > 0 {
+		s.ttl = d
+	}
+	if d, err := time.ParseDuration(strings.TrimSpace(cfg.SMSResendCooldown)); err == nil && d >= 0 {
+		s.cooldown = d
+	}
+	return s
+}
+
+// Enabled — настроен ли SMS-провайдер.
+func (s *PhoneVerificationService) Enabled() bool {
+	return s.sender != nil
+}
+
+// RequestVerification — код подтверждения на телефон из профиля. При ErrPhoneCodeCooldown
+// возвращает и состояние предыдущего кода — когда можно запросить новый.
+func (s *PhoneVerificationService) RequestVerification(ctx context.Context, userID int) (*models.PhoneCodeStatus, error) {
+	if !s.Enabled() {
+		return nil, ErrSMSDisabled
+	}
+	u, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(u.Phone) == "" {
+		return nil, ErrPhoneMissing
+	}
+	if u.PhoneVerified {
+		return nil, ErrPhoneAlreadyVerified
+	}
+	return s.sendCode(ctx, u, models.PhoneCodeVerify)
+}
+
+// ConfirmVerification — проверяет код и отмечает телефон подтверждённым. Если номер в профиле
+// сменили после отправки кода, код не подходит.
+func (s *PhoneVerificationService) ConfirmVerification(ctx context.Context, userID int, code string) error {
+	hash, err := s.checkCode(ctx, userID, models.PhoneCodeVerify, code)
+	if err != nil {
+		return err
+	}
+	if err := s.codes.ConfirmPhone(ctx, userID, hash, phoneCodeMaxAttempts); err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrPhoneCodeInvalid
+		case errors.As(err, &pgErr) && pgErr.Code == "23505":
+			return ErrPhoneTaken
+		}
+		return err
+	}
+	s.auth.InvalidateUser(userID)
+
+	logger.WithCtx(ctx).Info("Телефон подтверждён", zap.Int("user_id", userID))
+	return nil
+}
+
+// RequestReset — код сброса пароля по номеру телефона. Ответ не зависит от того, есть ли такой
+// номер, подтверждён ли он и не слишком ли часто запрашивают коды: ошибка — только ErrSMSDisabled
+// и ErrPhoneInvalid.
+func (s *PhoneVerificationService) RequestReset(ctx context.Context, phone string) error {
+	log := logger.WithCtx(ctx)
+
+	if !s.Enabled() {
+		return ErrSMSDisabled
+	}
+	digits, ok := smsPhone(phone)
+	if !ok {
+		return ErrPhoneInvalid
+	}
+	u, err := s.users.GetUserByPhone(ctx, digits)
+	if err != nil {
+		log.Info("Сброс пароля по телефону: номер не найден")
+		return nil
+	}
+	if s.resetVerified && !u.PhoneVerified {
+		log.Info("Сброс пароля по телефону: номер не подтверждён", zap.Int("user_id", u.ID))
+		return nil
+	}
+	if _, err := s.sendCode(ctx, u, models.PhoneCodeReset); err != nil {
+		log.Warn("Сброс пароля по телефону: код не отправлен", zap.Int("user_id", u.ID), zap.Error(err))
+	}
+	return nil
+}
+
+// ResetPassword — новый пароль по коду из SMS, пришедшему на phone (пароль уже проверен
+// правилом password, как при регистрации). Все токены и сессии пользователя отзываются.
+func (s *PhoneVerificationService) ResetPassword(ctx context.Context, phone, code, newPassword string) error {
+	digits, ok := smsPhone(phone)
+	if !ok {
+		return ErrPhoneCodeInvalid
+	}
+	u, err := s.users.GetUserByPhone(ctx, digits)
+	if err != nil {
+		return ErrPhoneCodeInvalid
+	}
+	hash, err := s.checkCode(ctx, u.ID, models.PhoneCodeReset, code)
+	if err != nil {
+		return err
+	}
+	pwHash, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.codes.ResetPassword(ctx, u.ID, hash, pwHash, phoneCodeMaxAttempts); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPhoneCodeInvalid
+		}
+		return err
+	}
+	s.auth.InvalidateUser(u.ID)
+	// сессии и refresh-токены отозваны в БД; access-токены — в хранилище токенов (может быть Redis)
+	if err := s.auth.tokens.RevokeUserTokens(ctx, u.ID, time.Now()); err != nil {
+		return err
+	}
+
+	logger.WithCtx(ctx).Info("Пароль сброшен по коду из SMS", zap.Int("user_id", u.ID))
+	return nil
+}
+
+// sendCode — новый код назначения purpose на телефон пользователя u.
+func (s *PhoneVerificationService) sendCode(ctx context.Context, u *models.User, purpose string) (*models.PhoneCodeStatus, error) {
+	log := logger.WithCtx(ctx).With(zap.Int("user_id", u.ID), zap.String("purpose", purpose))
+
+	phone, ok := smsPhone(u.Phone)
+	if !ok {
+		return nil, ErrPhoneInvalid
+	}
+	if prev, err := s.codes.Get(ctx, u.ID, purpose); err == nil {
+		if resendAt := prev.CreatedAt.Add(s.cooldown); time.Now().Before(resendAt) {
+			return &models.PhoneCodeStatus{ExpiresAt: prev.ExpiresAt, ResendAt: resendAt}, ErrPhoneCodeCooldown
+		}
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	code, err := newPhoneCode()
+	if err != nil {
+		return nil, err
+	}
+	c := &models.PhoneCode{
+		UserID:    u.ID,
+		Purpose:   purpose,
+		Phone:     u.Phone,
+		CodeHash:  phoneCodeHash(u.ID, purpose, code),
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if err := s.codes.Save(ctx, c); err != nil {
+		return nil, err
+	}
+
+	key := helpers.TextSMSPhoneCode
+	if purpose == models.PhoneCodeReset {
+		key = helpers.TextSMSResetCode
+	}
+	text := helpers.EmailPhrase(u.Locale, key, code, helpers.FormatTTL(u.Locale, s.ttl))
+	if err := s.sender.Send(ctx, phone, text); err != nil {
+		smsCodesSent.WithLabelValues(purpose, "failed").Inc()
+		log.Error("Не удалось отправить SMS с кодом", zap.String("provider", s.sender.Name()), zap.Error(err))
+		return nil, ErrPhoneSendFailed
+	}
+	smsCodesSent.WithLabelValues(purpose, "sent").Inc()
+
+	log.Info("SMS с кодом отправлено", zap.String("provider", s.sender.Name()))
+	return &models.PhoneCodeStatus{ExpiresAt: c.ExpiresAt, ResendAt: c.CreatedAt.Add(s.cooldown)}, nil
+}
+
+// checkCode — сверяет code с действующим кодом; неверный ввод засчитывается попыткой.
+// Возвращает хэш кода для того, чтобы погасить его в транзакции.
+func (s *PhoneVerificationService) checkCode(ctx context.Context, userID int, purpose, code string) (string, error) {
+	c, err := s.codes.Get(ctx, userID, purpose)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrPhoneCodeInvalid
+	}
+	if err != nil {
+		return "", err
+	}
+	if time.Now().After(c.ExpiresAt) {
+		return "", ErrPhoneCodeInvalid
+	}
+	if c.Attempts >= phoneCodeMaxAttempts {
+		return "", ErrPhoneCodeAttempts
+	}
+
+	hash := phoneCodeHash(userID, purpose, strings.TrimSpace(code))
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(c.CodeHash)) != 1 {
+		if err := s.codes.AddAttempt(ctx, userID, purpose); err != nil {
+			return "", err
+		}
+		logger.WithCtx(ctx).Info("Неверный код из SMS", zap.Int("user_id", userID),
+			zap.String("purpose", purpose), zap.Int("attempt", c.Attempts+1))
+		if c.Attempts+1 >= phoneCodeMaxAttempts {
+			return "", ErrPhoneCodeAttempts
+		}
+		return "", ErrPhoneCodeInvalid
+	}
+	return hash, nil
+}
+
+// newPhoneCode — случайный код из phoneCodeDigits цифр.
+func newPhoneCode() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < phoneCodeDigits; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", phoneCodeDigits, n.Int64()), nil
+}
+
+// phoneCodeHash — хэш кода; пользователь и назначение в нём — чтобы код нельзя было перенести.
+func phoneCodeHash(userID int, purpose, code string) string {
+	sum := sha256.Sum256([]byte(purpose + ":" + strconv.Itoa(userID) + ":" + code))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// smsPhone — номер для провайдера: только цифры с кодом страны; российские 10-значные
+// и начинающиеся с 8 номера приводятся к 7XXXXXXXXXX.
+func smsPhone(phone string) (string, bool) {
+	d := normalizePhoneDigits(phone)
+	switch {
+	case len(d) == 10:
+		return "7" + d, true
+	case len(d) == 11 && d[0] == '8':
+		return "7" + d[1:], true
+	case len(d) >= 11 && len(d) <= 15:
+		return d, true
+	}
+	return "", false
+}
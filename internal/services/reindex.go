@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+var ErrReindexRunning = errors.New("переиндексация уже выполняется")
+
+// reindexPlan — что пересобирать: таблицы поиска, дерева разделов и топов скачиваний.
+var reindexPlan = []struct{ group, table string }{
+	{"search", "documents"},
+	{"search", "news"},
+	{"search", "articles"},
+	{"taxonomy", "tabs"},
+	{"taxonomy", "sections"},
+	{"downloads", "document_downloads"},
+}
+
+// ReindexService — фоновая пересборка индексов после массового импорта или правки
+// схемы/индексов без перезапуска сервиса. Одновременно выполняется один запуск.
+type ReindexService struct {
+	repo *repository.MaintenanceRepository
+
+	mu  sync.Mutex
+	job *models.ReindexJob
+}
+
+func NewReindexService(repo *repository.MaintenanceRepository) *ReindexService {
+	return &ReindexService{repo: repo}
+}
+
+// Start — запускает переиндексацию в фоне; ErrReindexRunning и текущее состояние, если она уже идёт.
+func (s *ReindexService) Start(ctx context.Context, userID int) (*models.ReindexJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.job != nil && s.job.Status == models.ReindexRunning {
+		return s.snapshot(), ErrReindexRunning
+	}
+
+	now := time.Now()
+	job := &models.ReindexJob{
+		ID:        fmt.Sprintf("reindex-%d", now.UnixNano()),
+		Status:    models.ReindexRunning,
+		StartedBy: userID,
+		Total:     len(reindexPlan),
+		StartedAt: now,
+	}
+	for _, p := range reindexPlan {
+		job.Steps = append(job.Steps, models.ReindexStep{Group: p.group, Table: p.table, Status: "pending"})
+	}
+	s.job = job
+
+	logger.WithCtx(ctx).Info("Переиндексация запущена", zap.String("job_id", job.ID), zap.Int("user_id", userID))
+	go s.run(context.WithoutCancel(ctx))
+	return s.snapshot(), nil
+}
+
+// Status — состояние последнего запуска (nil, если запусков не было).
+func (s *ReindexService) Status() *models.ReindexJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshot()
+}
+
+func (s *ReindexService) snapshot() *models.ReindexJob {
+	if s.job == nil {
+		return nil
+	}
+	cp := *s.job
+	cp.Steps = append([]models.ReindexStep(nil), s.job.Steps...)
+	return &cp
+}
+
+func (s *ReindexService) run(ctx context.Context) {
+	log := logger.WithCtx(ctx)
+	failed := 0
+
+	for i := range reindexPlan {
+		s.mu.Lock()
+		s.job.Steps[i].Status = "running"
+		table := s.job.Steps[i].Table
+		s.mu.Unlock()
+
+		start := time.Now()
+		err := s.repo.ReindexTable(ctx, table)
+
+		s.mu.Lock()
+		step := &s.job.Steps[i]
+		step.DurationMs = time.Since(start).Milliseconds()
+		if err != nil {
+			// остальные таблицы не зависят от этой — продолжаем
+			failed++
+			step.Status = "failed"
+			step.Error = err.Error()
+		} else {
+			step.Status = "done"
+		}
+		s.job.Done++
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	s.job.FinishedAt = &now
+	s.job.Status = models.ReindexDone
+	if failed > 0 {
+		s.job.Status = models.ReindexFailed
+	}
+	id, took := s.job.ID, now.Sub(s.job.StartedAt)
+	s.mu.Unlock()
+
+	if failed > 0 {
+		log.Error("Переиндексация завершена с ошибками", zap.String("job_id", id), zap.Int("failed", failed), zap.Duration("took", took))
+		return
+	}
+	log.Info("Переиндексация завершена", zap.String("job_id", id), zap.Duration("took", took))
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/db"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+var ErrSelfCheckFailed = errors.New("самопроверка при старте не пройдена")
+
+const (
+	selfCheckUploadDir   = "uploaded" // как в DocumentHandler.UploadDocument
+	selfCheckSMTPTimeout = 5 * time.Second
+	selfCheckJWTMinLen   = 32
+)
+
+// jwtPlaceholders — значения JWT_SECRET из примеров, с которыми нельзя выходить в прод.
+var jwtPlaceholders = map[string]bool{"secret": true, "changeme": true, "change_me": true, "jwt_secret": true, "your_secret": true}
+
+// SelfCheckService — проверка окружения при старте: БД и миграции, каталог загрузок,
+// SMTP, обязательные переменные и ключ JWT. Последний отчёт отдаётся в админке.
+type SelfCheckService struct {
+	maint    *repository.MaintenanceRepository
+	migrator *db.Migrator
+	cfg      *config.Config
+
+	mu   sync.RWMutex
+	last *models.SelfCheckReport
+}
+
+func NewSelfCheckService(maint *repository.MaintenanceRepository, migrator *db.Migrator, cfg *config.Config) *SelfCheckService {
+	return &SelfCheckService{maint: maint, migrator: migrator, cfg: cfg}
+}
+
+// Last — отчёт последней проверки (nil — ещё не запускалась).
+func (s *SelfCheckService) Last() *models.SelfCheckReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.last
+}
+
+// Run — выполняет все проверки, логирует каждую и запоминает отчёт.
+func (s *SelfCheckService) Run(ctx context.Context) *models.SelfCheckReport {
+	report := &models.SelfCheckReport{Status: models.SelfCheckOK, StartedAt: time.Now()}
+
+	checks := []struct {
+		name string
+		fn   func(ctx context.Context) models.SelfCheckItem
+	}{
+		{"db", s.checkDB},
+		{"migrations", s.checkMigrations},
+		{"uploads", s.checkUploads},
+		{"smtp", s.checkSMTP},
+		{"env", s.checkEnv},
+		{"jwt", s.checkJWT},
+	}
+	for _, c := range checks {
+		start := time.Now()
+		item := c.fn(ctx)
+		item.Name = c.name
+		item.DurationMs = time.Since(start).Milliseconds()
+		report.Checks = append(report.Checks, item)
+		report.Status = worseStatus(report.Status, item.Status)
+
+		fields := []zap.Field{zap.String("check", item.Name), zap.String("status", item.Status), zap.String("message", item.Message)}
+		if len(item.Details) > 0 {
+			fields = append(fields, zap.Any("details", item.Details))
+		}
+		switch item.Status {
+		case models.SelfCheckFail:
+			logger.Log.Error("Самопроверка: ошибка", fields...)
+		case models.SelfCheckWarn:
+			logger.Log.Warn("Самопроверка: предупреждение", fields...)
+		default:
+			logger.Log.Info("Самопроверка: ок", fields...)
+		}
+	}
+	report.DurationMs = time.Since(report.StartedAt).Milliseconds()
+
+	logger.Log.Info("Самопроверка завершена",
+		zap.String("status", report.Status),
+		zap.Int64("duration_ms", report.DurationMs),
+	)
+
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+	return report
+}
+
+func worseStatus(a, b string) string {
+	rank := map[string]int{models.SelfCheckOK: 0, models.SelfCheckWarn: 1, models.SelfCheckFail: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+func (s *SelfCheckService) checkDB(ctx context.Context) models.SelfCheckItem {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := s.maint.Ping(ctx); err != nil {
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Message: "БД недоступна: " + err.Error()}
+	}
+	return models.SelfCheckItem{Status: models.SelfCheckOK, Details: map[string]any{"dsn": s.cfg.GetDSNSafe()}}
+}
+
+// checkMigrations — версия схемы в БД против миграций, встроенных в сборку.
+func (s *SelfCheckService) checkMigrations(ctx context.Context) models.SelfCheckItem {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	list, err := s.migrator.Status(ctx)
+	if err != nil {
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Message: "не удалось прочитать версию миграций: " + err.Error()}
+	}
+	version, err := s.migrator.Version(ctx)
+	if err != nil {
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Message: "не удалось прочитать версию миграций: " + err.Error()}
+	}
+	latest := s.migrator.Latest()
+
+	var pending []int64
+	for _, st := range list {
+		if st.AppliedAt == nil {
+			pending = append(pending, st.Version)
+		}
+	}
+	details := map[string]any{"db_version": version, "latest": latest, "pending": len(pending)}
+	if len(pending) > 0 {
+		details["pending_versions"] = pending
+	}
+
+	switch {
+	case version == 0:
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Details: details, Message: "миграции не применены"}
+	case len(pending) > 0:
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Details: details,
+			Message: fmt.Sprintf("схема БД отстаёт: не применено миграций — %d", len(pending))}
+	case version > latest:
+		return models.SelfCheckItem{Status: models.SelfCheckWarn, Details: details,
+			Message: "в БД миграции новее, чем в сборке (откат версии?)"}
+	}
+	return models.SelfCheckItem{Status: models.SelfCheckOK, Details: details}
+}
+
+// checkUploads — каталог загрузок существует (или создаётся) и доступен на запись.
+func (s *SelfCheckService) checkUploads(context.Context) models.SelfCheckItem {
+	details := map[string]any{"dir": selfCheckUploadDir}
+	if err := os.MkdirAll(selfCheckUploadDir, os.ModePerm); err != nil {
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Details: details, Message: "не удалось создать каталог загрузок: " + err.Error()}
+	}
+	f, err := os.CreateTemp(selfCheckUploadDir, ".selfcheck-*")
+	if err != nil {
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Details: details, Message: "каталог загрузок недоступен на запись: " + err.Error()}
+	}
+	name := f.Name()
+	_ = f.Close()
+	_ = os.Remove(name)
+	if abs, err := filepath.Abs(selfCheckUploadDir); err == nil {
+		details["dir"] = abs
+	}
+	return models.SelfCheckItem{Status: models.SelfCheckOK, Details: details}
+}
+
+// checkSMTP — TCP-соединение с SMTP-сервером (без авторизации и отправки).
+func (s *SelfCheckService) checkSMTP(ctx context.Context) models.SelfCheckItem {
+	if s.cfg.EmailMode == "file" {
+		return models.SelfCheckItem{Status: models.SelfCheckWarn, Details: map[string]any{"dir": s.cfg.EmailDevDir},
+			Message: "EMAIL_MODE=file: письма сохраняются на диск и не отправляются"}
+	}
+	if strings.TrimSpace(s.cfg.SMTPHost) == "" {
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Message: "SMTP_HOST не задан: письма не будут отправляться"}
+	}
+
+	addr := net.JoinHostPort(s.cfg.SMTPHost, s.cfg.SMTPPort)
+	details := map[string]any{"addr": addr}
+	d := net.Dialer{Timeout: selfCheckSMTPTimeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Details: details, Message: "SMTP недоступен: " + err.Error()}
+	}
+	_ = conn.Close()
+	if s.cfg.SMTPUser == "" || s.cfg.SMTPPassword == "" {
+		return models.SelfCheckItem{Status: models.SelfCheckWarn, Details: details, Message: "SMTP доступен, но SMTP_USER/SMTP_PASSWORD не заданы"}
+	}
+	return models.SelfCheckItem{Status: models.SelfCheckOK, Details: details}
+}
+
+// checkEnv — обязательные переменные окружения и предупреждения Config.Validate.
+func (s *SelfCheckService) checkEnv(context.Context) models.SelfCheckItem {
+	required := map[string]string{
+		"DB_HOST":    s.cfg.DbHost,
+		"DB_USER":    s.cfg.DbUser,
+		"DB_NAME":    s.cfg.DbName,
+		"JWT_SECRET": s.cfg.JWTSecret,
+		"SITEURL":    s.cfg.SiteURL,
+	}
+	var missing []string
+	for name, v := range required {
+		if strings.TrimSpace(v) == "" {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+
+	warnings, err := s.cfg.Validate()
+	details := map[string]any{}
+	if len(warnings) > 0 {
+		details["warnings"] = warnings
+	}
+	if len(missing) > 0 {
+		details["missing"] = missing
+	}
+
+	switch {
+	case err != nil:
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Details: details, Message: err.Error()}
+	case len(missing) > 0:
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Details: details,
+			Message: "не заданы обязательные переменные: " + strings.Join(missing, ", ")}
+	case len(warnings) > 0:
+		return models.SelfCheckItem{Status: models.SelfCheckWarn, Details: details, Message: "конфигурация неполная"}
+	}
+	return models.SelfCheckItem{Status: models.SelfCheckOK}
+}
+
+// checkJWT — ключ подписи задан, не шаблонный и достаточно длинный; токен подписывается и проверяется.
+func (s *SelfCheckService) checkJWT(context.Context) models.SelfCheckItem {
+	secret := s.cfg.JWTSecret
+	if strings.TrimSpace(secret) == "" {
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Message: "JWT_SECRET пуст: токены можно подделать"}
+	}
+
+	ttl, err := time.ParseDuration(s.cfg.AccessTokenTTL)
+	if err != nil || ttl <= 0 {
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Message: "ACCESS_TOKEN_EXPIRY некорректен: " + s.cfg.AccessTokenTTL}
+	}
+	details := map[string]any{"secret_len": len(secret), "access_ttl": ttl.String()}
+
+	token, err := utils.GenerateToken(secret, 0, "selfcheck", time.Minute, "access")
+	if err == nil {
+		_, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return []byte(secret), nil },
+			jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	}
+	if err != nil {
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Details: details, Message: "токен не проходит проверку: " + err.Error()}
+	}
+
+	switch {
+	case jwtPlaceholders[strings.ToLower(secret)]:
+		return models.SelfCheckItem{Status: models.SelfCheckFail, Details: details, Message: "JWT_SECRET — значение из примера"}
+	case len(secret) < selfCheckJWTMinLen:
+		return models.SelfCheckItem{Status: models.SelfCheckWarn, Details: details,
+			Message: fmt.Sprintf("JWT_SECRET короче %d символов", selfCheckJWTMinLen)}
+	}
+	return models.SelfCheckItem{Status: models.SelfCheckOK, Details: details}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ErrSessionNotFound — сессии нет, она чужая, уже отозвана или истекла.
+var ErrSessionNotFound = errors.New("сессия не найдена")
+
+// ListSessions — действующие сессии пользователя; currentID (0 — неизвестна) помечается как текущая.
+func (s *AuthService) ListSessions(ctx context.Context, userID int, currentID int64) ([]models.UserSession, error) {
+	list, err := s.sessions.ListActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Current = currentID != 0 && list[i].ID == currentID
+	}
+	return list, nil
+}
+
+// RevokeSession — завершает сессию sessionID пользователя userID: её токены перестают приниматься.
+func (s *AuthService) RevokeSession(ctx context.Context, userID int, sessionID int64) error {
+	session, err := s.sessions.Revoke(ctx, userID, sessionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.tokens.RevokeSession(ctx, session.ID, session.ExpiresAt); err != nil {
+		return err
+	}
+
+	logger.WithCtx(ctx).Info("Сессия завершена", zap.Int("user_id", userID), zap.Int64("session_id", sessionID))
+	return nil
+}
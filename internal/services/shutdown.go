@@ -0,0 +1,58 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// shutdownTimeout — сколько при остановке ждём текущий запуск фоновой задачи и отправку писем.
+var shutdownTimeout = 30 * time.Second
+
+// ConfigureShutdown — применяет SHUTDOWN_TIMEOUT.
+func ConfigureShutdown(cfg *config.Config) {
+	if d, err := time.ParseDuration(cfg.ShutdownTimeout); err == nil && d > 0 {
+		shutdownTimeout = d
+	}
+}
+
+// StopAndWait — функция остановки фонового цикла: закрывает done и ждёт, пока цикл выйдет
+// (stopped закрывается при выходе горутины), чтобы текущий запуск успел доделать работу —
+// в том числе поставить письма в EmailQueue до её закрытия.
+func StopAndWait(name string, done, stopped chan struct{}) func() {
+	return func() {
+		close(done)
+		waitStopped(name, stopped)
+	}
+}
+
+// waitStopped — ждёт stopped не дольше shutdownTimeout; false — не дождались.
+func waitStopped(name string, stopped <-chan struct{}) bool {
+	select {
+	case <-stopped:
+		return true
+	case <-time.After(shutdownTimeout):
+		logger.Log.Warn("Остановка: задача не завершилась за отведённое время",
+			zap.String("job", name), zap.Duration("timeout", shutdownTimeout))
+		return false
+	}
+}
+
+// waitGroupDone — канал, который закрывается, когда wg дождался всех.
+func waitGroupDone(wg *sync.WaitGroup) <-chan struct{} {
+	ch := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// ShutdownTimeout — действующий SHUTDOWN_TIMEOUT.
+func ShutdownTimeout() time.Duration {
+	return shutdownTimeout
+}
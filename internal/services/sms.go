@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+const smsSendTimeout = 15 * time.Second
+
+// Базовые адреса API провайдеров.
+var (
+	smscAPI  = "https://smsc.ru/sys/send.php"
+	smsruAPI = "https://sms.ru/sms/send"
+)
+
+// SMSSender — отправка SMS; phone — только цифры, с кодом страны (79991234567).
+type SMSSender interface {
+	Name() string
+	Send(ctx context.Context, phone, text string) error
+}
+
+// NewSMSSender — провайдер из SMS_PROVIDER: smsc (SMSC.ru), smsru (SMS.ru) или log — текст только
+// пишется в лог (dev/staging). nil — SMS выключены или провайдер не настроен.
+func NewSMSSender(cfg *config.Config) SMSSender {
+	client := &http.Client{Timeout: smsSendTimeout}
+	switch strings.ToLower(strings.TrimSpace(cfg.SMSProvider)) {
+	case "smsc":
+		if cfg.SMSCLogin == "" || cfg.SMSCPassword == "" {
+			return nil
+		}
+		return &smscSender{login: cfg.SMSCLogin, password: cfg.SMSCPassword, sender: cfg.SMSSenderName, client: client}
+	case "smsru":
+		if cfg.SMSRuAPIID == "" {
+			return nil
+		}
+		return &smsruSender{apiID: cfg.SMSRuAPIID, sender: cfg.SMSSenderName, client: client}
+	case "log":
+		return logSMSSender{}
+	}
+	return nil
+}
+
+// smscSender — SMSC.ru, send.php с ответом в JSON (fmt=3).
+type smscSender struct {
+	login, password, sender string
+	client                  *http.Client
+}
+
+func (s *smscSender) Name() string { return "smsc" }
+
+func (s *smscSender) Send(ctx context.Context, phone, text string) error {
+	form := url.Values{
+		"login":   {s.login},
+		"psw":     {s.password},
+		"phones":  {phone},
+		"mes":     {text},
+		"charset": {"utf-8"},
+		"fmt":     {"3"},
+	}
+	if s.sender != "" {
+		form.Set("sender", s.sender)
+	}
+	var resp struct {
+		ID        int64  `json:"id"`
+		Error     string `json:"error"`
+		ErrorCode int    `json:"error_code"`
+	}
+	if err := postSMS(ctx, s.client, smscAPI, form, &resp); err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("smsc: %s (код %d)", resp.Error, resp.ErrorCode)
+	}
+	return nil
+}
+
+// smsruSender — SMS.ru, sms/send с ответом в JSON.
+type smsruSender struct {
+	apiID, sender string
+	client        *http.Client
+}
+
+func (s *smsruSender) Name() string { return "smsru" }
+
+func (s *smsruSender) Send(ctx context.Context, phone, text string) error {
+	form := url.Values{
+		"api_id": {s.apiID},
+		"to":     {phone},
+		"msg":    {text},
+		"json":   {"1"},
+	}
+	if s.sender != "" {
+		form.Set("from", s.sender)
+	}
+	type status struct {
+		Status     string `json:"status"`
+		StatusCode int    `json:"status_code"`
+		StatusText string `json:"status_text"`
+	}
+	var resp struct {
+		status
+		SMS map[string]status `json:"sms"`
+	}
+	if err := postSMS(ctx, s.client, smsruAPI, form, &resp); err != nil {
+		return err
+	}
+	if resp.Status != "OK" {
+		return fmt.Errorf("sms.ru: %s (код %d)", resp.StatusText, resp.StatusCode)
+	}
+	if st, ok := resp.SMS[phone]; ok && st.Status != "OK" {
+		return fmt.Errorf("sms.ru: %s (код %d)", st.StatusText, st.StatusCode)
+	}
+	return nil
+}
+
+// logSMSSender — вместо отправки пишет SMS в лог.
+type logSMSSender struct{}
+
+func (logSMSSender) Name() string { return "log" }
+
+func (logSMSSender) Send(ctx context.Context, phone, text string) error {
+	logger.WithCtx(ctx).Info("SMS (SMS_PROVIDER=log)", zap.String("phone", phone), zap.String("text", text))
+	return nil
+}
+
+// postSMS — POST формы и разбор JSON-ответа; ошибка не содержит URL и пароль из формы.
+func postSMS(ctx context.Context, client *http.Client, target string, form url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(form.Encode()))
+	if err != nil {
+		return errors.New("некорректный адрес SMS-провайдера")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return uerr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("SMS-провайдер ответил %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(out); err != nil {
+		return fmt.Errorf("ответ SMS-провайдера не разобран: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/events"
+	"edutalks/internal/logger"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+// expiredReminderLookback — о подписках, истёкших раньше, письмо уже не шлём
+// (например, после долгого простоя или при первом запуске планировщика).
+const expiredReminderLookback = 7 * 24 * time.Hour
+
+// SubscriptionScheduler периодически отключает истёкшие подписки и рассылает напоминания:
+// «подписка скоро закончится» и «подписка закончилась». Каждое напоминание отправляется
+// один раз на пользователя и дату окончания — это гарантирует таблица subscription_reminders.
+type SubscriptionScheduler struct {
+	users        *repository.UserRepository
+	reminders    *repository.SubscriptionReminderRepository
+	interval     time.Duration
+	remindBefore time.Duration
+	renewURL     string
+	locks        JobLocker
+}
+
+func NewSubscriptionScheduler(users *repository.UserRepository, reminders *repository.SubscriptionReminderRepository, locks JobLocker, cfg *config.Config) *SubscriptionScheduler {
+	s := &SubscriptionScheduler{
+		users:        users,
+		reminders:    reminders,
+		locks:        locks,
+		interval:     time.Hour,
+		remindBefore: 72 * time.Hour,
+		renewURL:     strings.TrimSpace(cfg.SubscriptionRenewURL),
+	}
+	if d, err := time.ParseDuration(cfg.SubscriptionCheckInterval); err == nil && d > 0 {
+		s.interval = d
+	}
+	if d, err := time.ParseDuration(cfg.SubscriptionRemindBefore); err == nil && d > 0 {
+		s.remindBefore = d
+	}
+	if s.renewURL == "" {
+		s.renewURL = strings.TrimRight(cfg.FrontendURL, "/")
+	}
+	return s
+}
+
+// RunOnce — один проход: истекает подписки и ставит напоминания в очередь писем.
+func (s *SubscriptionScheduler) RunOnce(ctx context.Context) error {
+	log := logger.WithCtx(ctx)
+
+	if err := s.users.ExpireSubscriptions(ctx); err != nil {
+		log.Error("Ошибка в ExpireSubscriptions", zap.Error(err))
+		return err
+	}
+
+	expiring, err := s.reminders.ClaimExpiringSoon(ctx, s.remindBefore)
+	if err != nil {
+		log.Error("Не удалось выбрать подписки для напоминания", zap.Error(err))
+		return err
+	}
+	for _, it := range expiring {
+		EmailQueue <- EmailJob{
+			To:      []string{it.Email},
+			Subject: helpers.EmailSubject(it.Locale, helpers.MailSubscriptionExpiring),
+			Body:    helpers.BuildSubscriptionExpiringHTML(it.Locale, it.FullName, it.ExpiresAt, s.renewURL),
+			IsHTML:  true,
+		}
+		events.Publish(ctx, events.SubscriptionExpiring, &it.UserID, map[string]any{"expires_at": it.ExpiresAt})
+	}
+
+	expired, err := s.reminders.ClaimExpired(ctx, expiredReminderLookback)
+	if err != nil {
+		log.Error("Не удалось выбрать истёкшие подписки для уведомления", zap.Error(err))
+		return err
+	}
+	for _, it := range expired {
+		EmailQueue <- EmailJob{
+			To:      []string{it.Email},
+			Subject: helpers.EmailSubject(it.Locale, helpers.MailSubscriptionExpired),
+			Body:    helpers.BuildSubscriptionExpiredHTML(it.Locale, it.FullName, it.ExpiresAt, s.renewURL),
+			IsHTML:  true,
+		}
+	}
+
+	log.Debug("Проверка подписок выполнена",
+		zap.Int("expiring_reminders", len(expiring)),
+		zap.Int("expired_reminders", len(expired)),
+	)
+	return nil
+}
+
+// Start запускает проверку сразу и далее по интервалу; возвращает функцию остановки.
+func (s *SubscriptionScheduler) Start() func() {
+	ticker := time.NewTicker(s.interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		logger.Log.Info("SubscriptionScheduler запущен",
+			zap.Duration("interval", s.interval),
+			zap.Duration("remind_before", s.remindBefore),
+		)
+		run := func() { _ = RunExclusive(context.Background(), s.locks, JobSubscriptions, s.RunOnce) }
+		run()
+		for {
+			select {
+			case <-ticker.C:
+				run()
+			case <-done:
+				ticker.Stop()
+				logger.Log.Info("SubscriptionScheduler остановлен")
+				return
+			}
+		}
+	}()
+
+	return StopAndWait(JobSubscriptions, done, stopped)
+}
@@ -6,10 +6,12 @@ import (
 	"edutalks/internal/logger"
 	"edutalks/internal/models"
 	"edutalks/internal/repository"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
@@ -38,6 +40,8 @@ func (s *TaxonomyService) CreateTab(ctx context.Context, t *models.Tab) (int, er
 		t.Slug = unique
 	}
 
+	t.SEOMeta = normalizeSEO(t.SEOMeta)
+
 	logger.Log.Info("Создание вкладки", zap.String("title", t.Title), zap.String("slug", t.Slug))
 	id, err := s.repo.CreateTab(ctx, t)
 	if err != nil {
@@ -49,6 +53,7 @@ func (s *TaxonomyService) CreateTab(ctx context.Context, t *models.Tab) (int, er
 
 // UpdateTab — обновляет вкладку (slug оставляем как прислал фронт).
 func (s *TaxonomyService) UpdateTab(ctx context.Context, t *models.Tab) error {
+	t.SEOMeta = normalizeSEO(t.SEOMeta)
 	logger.Log.Info("Обновление вкладки", zap.Int("id", t.ID))
 	if err := s.repo.UpdateTab(ctx, t); err != nil {
 		logger.Log.Error("Ошибка обновления вкладки", zap.Int("id", t.ID), zap.Error(err))
@@ -85,6 +90,11 @@ func (s *TaxonomyService) CreateSection(ctx context.Context, sec *models.Section
 		sec.Slug = unique
 	}
 
+	if err := s.checkSectionParent(ctx, sec.TabID, 0, sec.ParentSectionID); err != nil {
+		return 0, err
+	}
+	sec.SEOMeta = normalizeSEO(sec.SEOMeta)
+
 	logger.Log.Info("Создание раздела", zap.String("title", sec.Title), zap.String("slug", sec.Slug), zap.Int("tab_id", sec.TabID))
 	id, err := s.repo.CreateSection(ctx, sec)
 	if err != nil {
@@ -96,6 +106,17 @@ func (s *TaxonomyService) CreateSection(ctx context.Context, sec *models.Section
 
 // UpdateSection — обновляет раздел (slug не трогаем).
 func (s *TaxonomyService) UpdateSection(ctx context.Context, sec *models.Section) error {
+	if sec.ParentSectionID != nil {
+		// раздел не переезжает между вкладками: родителя ищем в его текущей вкладке
+		tabID, err := s.repo.GetTabIDBySectionID(ctx, sec.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkSectionParent(ctx, tabID, sec.ID, sec.ParentSectionID); err != nil {
+			return err
+		}
+	}
+	sec.SEOMeta = normalizeSEO(sec.SEOMeta)
 	logger.Log.Info("Обновление раздела", zap.Int("id", sec.ID), zap.Int("tab_id", sec.TabID))
 	if err := s.repo.UpdateSection(ctx, sec); err != nil {
 		logger.Log.Error("Ошибка обновления раздела", zap.Int("id", sec.ID), zap.Error(err))
@@ -104,13 +125,162 @@ func (s *TaxonomyService) UpdateSection(ctx context.Context, sec *models.Section
 	return nil
 }
 
-// DeleteSection — удаляет раздел.
-func (s *TaxonomyService) DeleteSection(ctx context.Context, id int) error {
-	logger.Log.Info("Удаление раздела", zap.Int("id", id))
-	if err := s.repo.DeleteSection(ctx, id); err != nil {
-		logger.Log.Error("Ошибка удаления раздела", zap.Int("id", id), zap.Error(err))
+// ErrSectionParent — недопустимый родительский раздел.
+var ErrSectionParent = errors.New("недопустимый родительский раздел")
+
+// checkSectionParent — родитель из той же вкладки, без циклов и не глубже models.MaxSectionDepth
+// (с учётом поддерева перемещаемого раздела id; id == 0 — новый раздел).
+func (s *TaxonomyService) checkSectionParent(ctx context.Context, tabID, id int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+	parents, err := s.repo.SectionParents(ctx, tabID)
+	if err != nil {
+		logger.Log.Error("Ошибка чтения иерархии разделов", zap.Int("tab_id", tabID), zap.Error(err))
 		return err
 	}
+	if _, ok := parents[*parentID]; !ok {
+		return fmt.Errorf("%w: раздел %d не найден в этой вкладке", ErrSectionParent, *parentID)
+	}
+
+	// глубина родителя: 1 — верхний уровень
+	depth := 0
+	for cur := parentID; cur != nil; cur = parents[*cur] {
+		if *cur == id {
+			return fmt.Errorf("%w: раздел нельзя вложить в самого себя или в свой подраздел", ErrSectionParent)
+		}
+		depth++
+		if depth > len(parents) {
+			return fmt.Errorf("%w: цикл в иерархии разделов", ErrSectionParent)
+		}
+	}
+
+	if depth+subtreeHeight(parents, id) > models.MaxSectionDepth {
+		return fmt.Errorf("%w: допускается не больше %d уровней вложенности", ErrSectionParent, models.MaxSectionDepth)
+	}
+	return nil
+}
+
+// subtreeHeight — число уровней в поддереве раздела id, включая его самого (1 — нет подразделов).
+func subtreeHeight(parents map[int]*int, id int) int {
+	if id == 0 {
+		return 1
+	}
+	children := map[int][]int{}
+	for c, p := range parents {
+		if p != nil {
+			children[*p] = append(children[*p], c)
+		}
+	}
+	var height func(id, depth int) int
+	height = func(id, depth int) int {
+		h := 1
+		if depth > len(parents) {
+			return h
+		}
+		for _, c := range children[id] {
+			h = max(h, 1+height(c, depth+1))
+		}
+		return h
+	}
+	return height(id, 1)
+}
+
+var (
+	ErrSectionDeleteMode = errors.New("mode должен быть block|detach|reassign; для reassign нужен move_to")
+	ErrSectionMoveTarget = errors.New("раздел для переноса документов не найден или вложен в удаляемый")
+	ErrSectionNotEmpty   = errors.New("в разделе есть документы или подразделы")
+)
+
+// DeleteSection — удаляет раздел с подразделами. mode решает судьбу документов поддерева:
+// block (по умолчанию) — отказ, если раздел не пуст; detach — документы остаются без раздела;
+// reassign — переносятся в раздел moveTo. При ErrSectionNotEmpty возвращает и результат с числом
+// документов и подразделов, мешающих удалению.
+func (s *TaxonomyService) DeleteSection(ctx context.Context, id int, mode string, moveTo *int) (*models.SectionDeleteResult, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		mode = models.SectionDeleteBlock
+	}
+	switch mode {
+	case models.SectionDeleteBlock, models.SectionDeleteDetach:
+		if moveTo != nil {
+			return nil, ErrSectionDeleteMode
+		}
+	case models.SectionDeleteReassign:
+		if moveTo == nil {
+			return nil, ErrSectionDeleteMode
+		}
+	default:
+		return nil, ErrSectionDeleteMode
+	}
+
+	logger.Log.Info("Удаление раздела", zap.Int("id", id), zap.String("mode", mode), zap.Intp("move_to", moveTo))
+	res, err := s.repo.DeleteSection(ctx, id, mode, moveTo)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrSectionNotEmpty):
+			return res, ErrSectionNotEmpty
+		case errors.Is(err, repository.ErrSectionMoveTarget):
+			return nil, ErrSectionMoveTarget
+		case !errors.Is(err, pgx.ErrNoRows):
+			logger.Log.Error("Ошибка удаления раздела", zap.Int("id", id), zap.Error(err))
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+// ErrTaxonomyReorder — некорректный список для сортировки (пустой, дубли, чужие ID).
+var ErrTaxonomyReorder = errors.New("некорректный порядок")
+
+// ReorderTabs — порядок вкладок из drag-and-drop; возвращает итоговый порядок ID.
+func (s *TaxonomyService) ReorderTabs(ctx context.Context, ids []int) ([]int, error) {
+	if err := checkReorderIDs(ids); err != nil {
+		return nil, err
+	}
+	logger.Log.Info("Сортировка вкладок", zap.Ints("ids", ids))
+	order, err := s.repo.ReorderTabs(ctx, ids)
+	if err != nil {
+		if errors.Is(err, repository.ErrReorderUnknownID) {
+			return nil, fmt.Errorf("%w: в списке есть несуществующие вкладки", ErrTaxonomyReorder)
+		}
+		logger.Log.Error("Ошибка сортировки вкладок", zap.Error(err))
+		return nil, err
+	}
+	return order, nil
+}
+
+// ReorderSections — порядок разделов внутри вкладки; возвращает итоговый порядок ID.
+func (s *TaxonomyService) ReorderSections(ctx context.Context, tabID int, ids []int) ([]int, error) {
+	if err := checkReorderIDs(ids); err != nil {
+		return nil, err
+	}
+	logger.Log.Info("Сортировка разделов", zap.Int("tab_id", tabID), zap.Ints("ids", ids))
+	order, err := s.repo.ReorderSections(ctx, tabID, ids)
+	if err != nil {
+		if errors.Is(err, repository.ErrReorderUnknownID) {
+			return nil, fmt.Errorf("%w: в списке есть разделы не из этой вкладки", ErrTaxonomyReorder)
+		}
+		logger.Log.Error("Ошибка сортировки разделов", zap.Int("tab_id", tabID), zap.Error(err))
+		return nil, err
+	}
+	return order, nil
+}
+
+func checkReorderIDs(ids []int) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: пустой список", ErrTaxonomyReorder)
+	}
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return fmt.Errorf("%w: неверный ID %d", ErrTaxonomyReorder, id)
+		}
+		if seen[id] {
+			return fmt.Errorf("%w: ID %d повторяется", ErrTaxonomyReorder, id)
+		}
+		seen[id] = true
+	}
 	return nil
 }
 
@@ -139,6 +309,68 @@ func (s *TaxonomyService) PublicTreeFiltered(ctx context.Context, tabID *int, ta
 	return items, nil
 }
 
+var ErrTaxonomyPathNotFound = errors.New("страница каталога не найдена")
+
+// Resolve — цепочка «вкладка → раздел» по пути вида /tab-slug/section-slug.
+func (s *TaxonomyService) Resolve(ctx context.Context, path string) (*models.TaxonomyResolveResult, error) {
+	var segs []string
+	for _, p := range strings.Split(path, "/") {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p != "" {
+			segs = append(segs, p)
+		}
+	}
+	if len(segs) == 0 || len(segs) > 2 {
+		return nil, ErrTaxonomyPathNotFound
+	}
+
+	sectionSlug := ""
+	if len(segs) == 2 {
+		sectionSlug = segs[1]
+	}
+
+	tab, sec, err := s.repo.ResolvePath(ctx, segs[0], sectionSlug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTaxonomyPathNotFound
+		}
+		logger.Log.Error("Ошибка разрешения пути таксономии", zap.String("path", path), zap.Error(err))
+		return nil, err
+	}
+	if sectionSlug != "" && sec == nil {
+		return nil, ErrTaxonomyPathNotFound
+	}
+
+	tab.Path = "/" + tab.Slug
+	res := &models.TaxonomyResolveResult{Path: tab.Path, Crumbs: []models.TaxonomyCrumb{*tab}}
+	if sec != nil {
+		sec.Path = tab.Path + "/" + sec.Slug
+		res.Path = sec.Path
+		res.Crumbs = append(res.Crumbs, *sec)
+	}
+	return res, nil
+}
+
+// Search — автокомплит для выбора вкладки/раздела в админке.
+func (s *TaxonomyService) Search(ctx context.Context, q string, limit int) ([]models.TaxonomySearchItem, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return []models.TaxonomySearchItem{}, nil
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	// экранируем спецсимволы LIKE: «%» и «_» в запросе не должны матчить всё подряд
+	q = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(q)
+
+	items, err := s.repo.Search(ctx, q, limit)
+	if err != nil {
+		logger.Log.Error("Ошибка поиска по таксономии", zap.String("q", q), zap.Error(err))
+		return nil, err
+	}
+	return items, nil
+}
+
 // ----------------- helpers -----------------
 
 var nonWord = regexp.MustCompile(`[^\p{L}\p{N}]+`) // всё, что не буквы/цифры, -> дефисы
@@ -164,6 +396,14 @@ func slugify(s string) string {
 	return s
 }
 
+// normalizeSEO — обрезаем пробелы; пустые поля фронт заменит значениями по умолчанию (title/description).
+func normalizeSEO(m models.SEOMeta) models.SEOMeta {
+	m.MetaTitle = strings.TrimSpace(m.MetaTitle)
+	m.MetaDescription = strings.TrimSpace(m.MetaDescription)
+	m.OGImageURL = strings.TrimSpace(m.OGImageURL)
+	return m
+}
+
 func normalizeSlug(s string) string {
 	return slugify(strings.ToLower(strings.TrimSpace(s)))
 }
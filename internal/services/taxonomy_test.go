@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"edutalks/internal/repository"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+// sectionTree — pgxmock с одним запросом SectionParents для вкладки 1.
+func sectionTree(t *testing.T, parents map[int]*int) *TaxonomyService {
+	t.Helper()
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("pgxmock: %v", err)
+	}
+	t.Cleanup(mock.Close)
+	rows := mock.NewRows([]string{"id", "parent_section_id"})
+	for id, p := range parents {
+		rows.AddRow(id, p)
+	}
+	mock.ExpectQuery("SELECT id, parent_section_id FROM sections").WithArgs(1).WillReturnRows(rows)
+	return NewTaxonomyService(repository.NewTaxonomyRepo(mock))
+}
+
+func TestCheckSectionParent(t *testing.T) {
+	p := func(v int) *int { return &v }
+	// 1 → 2 → 3, 4 отдельно; 6 ↔ 7 — цикл, уже попавший в базу
+	tree := map[int]*int{1: nil, 2: p(1), 3: p(2), 4: nil, 6: p(7), 7: p(6)}
+	// цепочка из models.MaxSectionDepth уровней: 10 → 11 → 12 → 13 → 14
+	deep := map[int]*int{10: nil, 11: p(10), 12: p(11), 13: p(12), 14: p(13), 20: nil, 21: p(20)}
+
+	for _, tc := range []struct {
+		name    string
+		parents map[int]*int
+		id      int
+		parent  int
+		wantErr bool
+	}{
+		{"new section under leaf", tree, 0, 3, false},
+		{"move subtree", tree, 4, 3, false},
+		{"into itself", tree, 2, 2, true},
+		{"into own descendant", tree, 1, 3, true},
+		{"unknown parent", tree, 0, 99, true},
+		{"existing cycle", tree, 0, 6, true},
+		{"new section too deep", deep, 0, 14, true},
+		{"new section at max depth", deep, 0, 13, false},
+		{"subtree too deep after move", deep, 20, 13, true},
+		{"subtree fits after move", deep, 20, 12, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := sectionTree(t, tc.parents)
+			err := s.checkSectionParent(context.Background(), 1, tc.id, &tc.parent)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrSectionParent) {
+				t.Errorf("err = %v, want ErrSectionParent", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+var ErrTrashItemNotFound = errors.New("в корзине нет такого материала")
+
+const (
+	defaultTrashRetentionDays = 30
+	trashPurgeTick            = time.Hour
+)
+
+// TrashService — корзина удалённых документов, новостей и статей: просмотр, восстановление
+// и окончательное удаление. Через TRASH_RETENTION_DAYS материалы удаляются сами,
+// у документов — вместе с файлом на диске.
+type TrashService struct {
+	repo  *repository.TrashRepository
+	locks JobLocker
+	days  int
+}
+
+func NewTrashService(repo *repository.TrashRepository, locks JobLocker, cfg *config.Config) *TrashService {
+	days := defaultTrashRetentionDays
+	if n, err := strconv.Atoi(strings.TrimSpace(cfg.TrashRetentionDays)); err == nil && n >= 1 {
+		days = n
+	}
+	return &TrashService{repo: repo, locks: locks, days: days}
+}
+
+func (s *TrashService) retention() time.Duration {
+	return time.Duration(s.days) * 24 * time.Hour
+}
+
+// List — страница корзины; у каждого материала — когда он удалится окончательно.
+func (s *TrashService) List(ctx context.Context, typ string, limit, offset int) ([]models.TrashItem, int, error) {
+	items, total, err := s.repo.List(ctx, typ, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.Add(s.retention())
+	}
+	return items, total, nil
+}
+
+// Restore — возвращает материал из корзины на место.
+func (s *TrashService) Restore(ctx context.Context, typ string, id int64) error {
+	ok, err := s.repo.Restore(ctx, typ, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTrashItemNotFound
+	}
+	logger.WithCtx(ctx).Info("Материал восстановлен из корзины", zap.String("type", typ), zap.Int64("id", id))
+	return nil
+}
+
+// Delete — окончательно удаляет материал из корзины, документ — вместе с файлом.
+func (s *TrashService) Delete(ctx context.Context, typ string, id int64) error {
+	file, ok, err := s.repo.Delete(ctx, typ, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTrashItemNotFound
+	}
+	removeTrashFiles(ctx, []string{file})
+	logger.WithCtx(ctx).Info("Материал удалён из корзины окончательно", zap.String("type", typ), zap.Int64("id", id))
+	return nil
+}
+
+// Purge — окончательно удаляет материалы, пролежавшие в корзине дольше срока хранения.
+func (s *TrashService) Purge(ctx context.Context) (int, error) {
+	n, files, err := s.repo.Purge(ctx, time.Now().Add(-s.retention()))
+	if err != nil {
+		return 0, err
+	}
+	removeTrashFiles(ctx, files)
+	if n > 0 {
+		logger.WithCtx(ctx).Info("Корзина очищена от просроченных материалов",
+			zap.Int("deleted", n), zap.Int("files", len(files)), zap.Int("retention_days", s.days))
+	}
+	return n, nil
+}
+
+// removeTrashFiles — удаляет файлы документов; запись в БД уже удалена, поэтому ошибка
+// только пишется в лог — файл без записи никому не выдаётся.
+func removeTrashFiles(ctx context.Context, files []string) {
+	for _, f := range files {
+		if f == "" {
+			continue
+		}
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			logger.WithCtx(ctx).Warn("Не удалось удалить файл документа из корзины", zap.String("filepath", f), zap.Error(err))
+		}
+	}
+}
+
+// Start — периодическая очистка корзины (на одном инстансе); возвращает функцию остановки.
+func (s *TrashService) Start() func() {
+	ticker := time.NewTicker(trashPurgeTick)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ticker.C:
+				err := RunExclusive(context.Background(), s.locks, JobTrashPurge, func(ctx context.Context) error {
+					_, err := s.Purge(ctx)
+					return err
+				})
+				if err != nil {
+					logger.Log.Error("Ошибка очистки корзины", zap.Error(err))
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return StopAndWait(JobTrashPurge, done, stopped)
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultUploadDocumentTypes — допустимые типы документов и лимиты в МБ (UPLOAD_DOCUMENT_TYPES).
+const DefaultUploadDocumentTypes = "pdf:100,doc:50,docx:50,xls:50,xlsx:50,ppt:100,pptx:100,odt:50,ods:50,odp:100,rtf:20,txt:10,zip:200,jpg:20,jpeg:20,png:20"
+
+var (
+	ErrUploadType     = errors.New("недопустимый тип файла")
+	ErrUploadContent  = errors.New("содержимое файла не соответствует расширению")
+	ErrUploadTooLarge = errors.New("файл слишком большой")
+)
+
+// UploadError — отказ политики загрузки с подробностями для ответа клиенту.
+type UploadError struct {
+	Err      error // ErrUpload*
+	Ext      string
+	MaxBytes int64    // для ErrUploadTooLarge
+	Allowed  []string // для ErrUploadType
+}
+
+func (e *UploadError) Error() string {
+	switch {
+	case errors.Is(e.Err, ErrUploadTooLarge):
+		return fmt.Sprintf("%s: для .%s не больше %d МБ", e.Err, e.Ext, e.MaxBytes>>20)
+	case errors.Is(e.Err, ErrUploadType):
+		return fmt.Sprintf("%s: допустимы %s", e.Err, strings.Join(e.Allowed, ", "))
+	}
+	return fmt.Sprintf("%s (.%s)", e.Err, e.Ext)
+}
+
+func (e *UploadError) Unwrap() error { return e.Err }
+
+// UploadPolicy — какие файлы можно загружать как документы: расширение из списка, размер
+// в пределах лимита типа и сигнатура содержимого, совпадающая с расширением.
+type UploadPolicy struct {
+	limits map[string]int64 // расширение без точки → байт
+}
+
+// NewUploadPolicy — из строки "pdf:100,docx:50" (лимиты в МБ); неразобранные элементы пропускаются,
+// пустая строка — DefaultUploadDocumentTypes.
+func NewUploadPolicy(spec string) *UploadPolicy {
+	if strings.TrimSpace(spec) == "" {
+		spec = DefaultUploadDocumentTypes
+	}
+	p := &UploadPolicy{limits: map[string]int64{}}
+	for _, item := range strings.Split(spec, ",") {
+		ext, mb, ok := strings.Cut(strings.TrimSpace(item), ":")
+		ext = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+		n, err := strconv.ParseInt(strings.TrimSpace(mb), 10, 64)
+		if !ok || ext == "" || err != nil || n <= 0 {
+			continue
+		}
+		p.limits[ext] = n << 20
+	}
+	return p
+}
+
+// Allowed — допустимые расширения по алфавиту.
+func (p *UploadPolicy) Allowed() []string {
+	list := make([]string, 0, len(p.limits))
+	for ext := range p.limits {
+		list = append(list, ext)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// MaxBytes — наибольший лимит среди типов (ограничение тела запроса).
+func (p *UploadPolicy) MaxBytes() int64 {
+	var max int64
+	for _, n := range p.limits {
+		if n > max {
+			max = n
+		}
+	}
+	return max
+}
+
+// Check — проверяет имя, размер и содержимое файла; nil или *UploadError.
+func (p *UploadPolicy) Check(filename string, size int64, f io.ReaderAt) error {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+	limit, ok := p.limits[ext]
+	if !ok {
+		return &UploadError{Err: ErrUploadType, Ext: ext, Allowed: p.Allowed()}
+	}
+	if size > limit {
+		return &UploadError{Err: ErrUploadTooLarge, Ext: ext, MaxBytes: limit}
+	}
+
+	head := make([]byte, 512)
+	n, err := f.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if !matchesSignature(ext, head[:n], f, size) {
+		return &UploadError{Err: ErrUploadContent, Ext: ext}
+	}
+	return nil
+}
+
+var (
+	sigPDF = []byte("%PDF-")
+	sigZip = []byte("PK\x03\x04")
+	sigOLE = []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1") // doc/xls/ppt
+	sigRTF = []byte(`{\rtf`)
+	sigJPG = []byte("\xFF\xD8\xFF")
+	sigPNG = []byte("\x89PNG\r\n\x1a\n")
+)
+
+// ooxmlDirs — обязательный каталог внутри архива Office Open XML / OpenDocument.
+var ooxmlDirs = map[string]string{
+	"docx": "word/", "xlsx": "xl/", "pptx": "ppt/",
+	"odt": "mimetype", "ods": "mimetype", "odp": "mimetype",
+}
+
+// matchesSignature — совпадает ли содержимое с расширением. Для неизвестных проверке типов — true.
+func matchesSignature(ext string, head []byte, f io.ReaderAt, size int64) bool {
+	switch ext {
+	case "pdf":
+		return bytes.HasPrefix(head, sigPDF)
+	case "doc", "xls", "ppt":
+		return bytes.HasPrefix(head, sigOLE)
+	case "rtf":
+		return bytes.HasPrefix(head, sigRTF)
+	case "jpg", "jpeg":
+		return bytes.HasPrefix(head, sigJPG)
+	case "png":
+		return bytes.HasPrefix(head, sigPNG)
+	case "txt":
+		// кодировка может быть любой (в том числе cp1251) — только отсекаем двоичные файлы
+		return !bytes.Contains(head, []byte{0})
+	case "zip":
+		return bytes.HasPrefix(head, sigZip)
+	}
+	dir, ok := ooxmlDirs[ext]
+	if !ok {
+		return true
+	}
+	if !bytes.HasPrefix(head, sigZip) {
+		return false
+	}
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		return false
+	}
+	for _, zf := range zr.File {
+		if strings.HasPrefix(zf.Name, dir) {
+			return true
+		}
+	}
+	return false
+}
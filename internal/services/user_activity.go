@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+var ErrActivityType = errors.New("type должен быть login|download|payment|subscription|email_verified|email_changed|impersonation")
+
+type UserActivityService struct {
+	repo *repository.UserActivityRepository
+}
+
+func NewUserActivityService(repo *repository.UserActivityRepository) *UserActivityService {
+	return &UserActivityService{repo: repo}
+}
+
+// List — лента действий пользователя для поддержки: новые события сверху, typ пустой — все типы.
+func (s *UserActivityService) List(ctx context.Context, userID int, typ string, limit, offset int) ([]models.UserActivity, int, error) {
+	if typ != "" && !slices.Contains(models.ActivityTypes, typ) {
+		return nil, 0, ErrActivityType
+	}
+
+	items, total, err := s.repo.List(ctx, userID, typ, limit, offset)
+	if err != nil {
+		logger.WithCtx(ctx).Error("Ошибка получения ленты действий пользователя", zap.Int("user_id", userID), zap.Error(err))
+		return nil, 0, err
+	}
+	return items, total, nil
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils/helpers"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// userBulkMax — предел пользователей в одной массовой операции.
+const userBulkMax = 1000
+
+var (
+	ErrBulkBadAction = errors.New("action должен быть grant|extend|revoke|set_role|delete")
+	ErrBulkNoTargets = errors.New("укажите user_ids или непустой filter")
+	ErrBulkTooMany   = errors.New("слишком много пользователей для одной операции")
+	ErrBulkBadRole   = errors.New("role должна быть user, author или admin")
+)
+
+var bulkRoles = map[string]bool{"user": true, "author": true, "admin": true}
+
+// BulkUsers — массовая операция над пользователями (по списку ID или фильтру) в одной транзакции.
+// actorID — администратор: удалить себя или сменить себе роль нельзя. duration — для grant/extend.
+func (s *AuthService) BulkUsers(ctx context.Context, actorID int, req *models.UserBulkRequest, duration time.Duration) (*models.UserBulkReport, error) {
+	log := logger.WithCtx(ctx)
+
+	action := strings.ToLower(strings.TrimSpace(req.Action))
+	role := strings.ToLower(strings.TrimSpace(req.Role))
+	switch action {
+	case models.UserBulkGrant, models.UserBulkExtend, models.UserBulkRevoke, models.UserBulkDelete:
+	case models.UserBulkSetRole:
+		if !bulkRoles[role] {
+			return nil, ErrBulkBadRole
+		}
+	default:
+		return nil, ErrBulkBadAction
+	}
+
+	ids, err := s.bulkTargets(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Массовая операция над пользователями",
+		zap.String("action", action), zap.Int("count", len(ids)), zap.Int("actor_id", actorID), zap.Bool("all_or_nothing", req.AllOrNothing))
+
+	report := &models.UserBulkReport{Action: action, Total: len(ids)}
+	var apply []int
+	for _, id := range ids {
+		if id == actorID && (action == models.UserBulkDelete || action == models.UserBulkSetRole) {
+			report.Results = append(report.Results, models.UserBulkResult{UserID: id, Status: models.UserBulkSkipped,
+				Error: "нельзя применить к своей учётной записи"})
+			continue
+		}
+		apply = append(apply, id)
+	}
+
+	failed, err := s.repo.BulkApply(ctx, apply, req.AllOrNothing, s.outbox, s.bulkOp(action, role, duration))
+	for _, id := range apply {
+		s.cache.invalidate(id)
+	}
+	if err != nil {
+		log.Error("Ошибка массовой операции над пользователями", zap.String("action", action), zap.Error(err))
+		return nil, err
+	}
+
+	report.RolledBack = req.AllOrNothing && len(failed) > 0
+	for _, id := range apply {
+		res := models.UserBulkResult{UserID: id, Status: models.UserBulkOK}
+		if ferr, ok := failed[id]; ok {
+			res.Status, res.Error = models.UserBulkFailed, bulkErrorText(ferr)
+		} else if report.RolledBack {
+			res.Status = models.UserBulkRolledBack
+		}
+		report.Results = append(report.Results, res)
+	}
+	if action == models.UserBulkSetRole || action == models.UserBulkDelete {
+		s.revokeBulkTokens(ctx, report)
+	}
+	for _, res := range report.Results {
+		switch res.Status {
+		case models.UserBulkOK:
+			report.Succeeded++
+		case models.UserBulkFailed:
+			report.Failed++
+		default:
+			report.Skipped++
+		}
+	}
+
+	log.Info("Массовая операция завершена",
+		zap.String("action", action),
+		zap.Int("succeeded", report.Succeeded),
+		zap.Int("failed", report.Failed),
+		zap.Int("skipped", report.Skipped),
+		zap.Bool("rolled_back", report.RolledBack),
+	)
+	return report, nil
+}
+
+// revokeBulkTokens — после коммита смены роли или удаления отзывает токены затронутых пользователей:
+// роль зашита в access-токен, и без отзыва старая роль (или удалённый аккаунт) действует до его истечения.
+// Изменение уже применено, поэтому сбой отзыва не откатывает результат, а попадает в его error.
+func (s *AuthService) revokeBulkTokens(ctx context.Context, report *models.UserBulkReport) {
+	now := time.Now()
+	for i := range report.Results {
+		res := &report.Results[i]
+		if res.Status != models.UserBulkOK {
+			continue
+		}
+		if err := s.tokens.RevokeUserTokens(ctx, res.UserID, now); err != nil {
+			logger.WithCtx(ctx).Error("Ошибка отзыва токенов после массовой операции",
+				zap.Int("user_id", res.UserID), zap.Error(err))
+			res.Error = "изменение применено, но токены не отозваны"
+		}
+	}
+}
+
+// bulkTargets — ID из запроса (без дублей) или по фильтру; не больше userBulkMax.
+func (s *AuthService) bulkTargets(ctx context.Context, req *models.UserBulkRequest) ([]int, error) {
+	if len(req.UserIDs) > 0 {
+		if len(req.UserIDs) > userBulkMax {
+			return nil, ErrBulkTooMany
+		}
+		seen := make(map[int]bool, len(req.UserIDs))
+		ids := make([]int, 0, len(req.UserIDs))
+		for _, id := range req.UserIDs {
+			if id > 0 && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return nil, ErrBulkNoTargets
+		}
+		return ids, nil
+	}
+
+	// Пустой фильтр выбрал бы всех пользователей — такое не принимаем.
+	f := req.Filter
+	if f == nil || (strings.TrimSpace(f.Q) == "" && (f.Role == nil || strings.TrimSpace(*f.Role) == "") && f.HasSubscription == nil) {
+		return nil, ErrBulkNoTargets
+	}
+	ids, err := s.repo.FilterUserIDs(ctx, f.Q, f.Role, f.HasSubscription, userBulkMax+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > userBulkMax {
+		return nil, ErrBulkTooMany
+	}
+	return ids, nil
+}
+
+// bulkOp — действие над одним пользователем; письма и события идут через outbox той же транзакции.
+func (s *AuthService) bulkOp(action, role string, duration time.Duration) repository.UserBulkOp {
+	return func(ctx context.Context, tx pgx.Tx, userID int) ([]models.OutboxIntent, error) {
+		switch action {
+		case models.UserBulkGrant, models.UserBulkExtend:
+			extend := action == models.UserBulkExtend
+			u, err := s.repo.GrantSubscriptionTx(ctx, tx, userID, duration, extend)
+			if err != nil {
+				return nil, err
+			}
+			if extend {
+				return grantIntents("extend", helpers.MailSubscriptionExtended, duration, u)
+			}
+			return grantIntents("grant", helpers.MailSubscriptionGranted, duration, u)
+		case models.UserBulkRevoke:
+			u, prev, err := s.repo.RevokeSubscriptionTx(ctx, tx, userID)
+			if err != nil || u.Email == "" {
+				return nil, err
+			}
+			html := helpers.BuildSubscriptionRevokedHTML(u.Locale, u.FullName, time.Now().UTC(), prev)
+			email, err := EmailIntent(EmailJob{
+				To:      []string{u.Email},
+				Subject: helpers.EmailSubject(u.Locale, helpers.MailSubscriptionRevoked),
+				Body:    html,
+				IsHTML:  true,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return []models.OutboxIntent{email}, nil
+		case models.UserBulkSetRole:
+			return nil, s.repo.SetRoleTx(ctx, tx, userID, role)
+		case models.UserBulkDelete:
+			return nil, s.repo.DeleteUserTx(ctx, tx, userID)
+		}
+		return nil, ErrBulkBadAction
+	}
+}
+
+func bulkErrorText(err error) string {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "пользователь не найден"
+	}
+	return err.Error()
+}
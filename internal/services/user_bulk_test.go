@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"edutalks/internal/config"
+	"edutalks/internal/models"
+	"edutalks/internal/repository/repofake"
+)
+
+func TestRevokeBulkTokens(t *testing.T) {
+	tokens := repofake.NewTokens()
+	auth := NewAuthService(repofake.NewUsers(), nil, tokens, nil, &config.Config{})
+	ctx := context.Background()
+
+	report := &models.UserBulkReport{Action: models.UserBulkSetRole, Results: []models.UserBulkResult{
+		{UserID: 1, Status: models.UserBulkOK},
+		{UserID: 2, Status: models.UserBulkFailed},
+		{UserID: 3, Status: models.UserBulkSkipped},
+	}}
+	auth.revokeBulkTokens(ctx, report)
+
+	// токены отзываются только там, где изменение применено
+	for id, want := range map[int]bool{1: true, 2: false, 3: false} {
+		at, _ := tokens.TokensRevokedAt(ctx, id)
+		if !at.IsZero() != want {
+			t.Errorf("user %d: revoked_at = %v, want revoked=%v", id, at, want)
+		}
+	}
+}
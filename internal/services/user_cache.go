@@ -0,0 +1,114 @@
+package services
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/metrics"
+	"edutalks/internal/models"
+)
+
+// Обращения к кэшу пользователей по исходу (hit/miss).
+var userCacheRequests = metrics.NewCounterVec("user_cache_requests_total", "Обращений к кэшу пользователей по исходу (hit/miss)", "result")
+
+const (
+	defaultUserCacheTTL  = 30 * time.Second
+	defaultUserCacheSize = 1000
+)
+
+// userCache — LRU пользователей по ID с TTL для горячих эндпоинтов (скачивание, профиль).
+// Изменения через AuthService сбрасывают запись сразу; правки в обход сервиса
+// (истечение подписок планировщиком, подтверждение email) видны не позже чем через TTL.
+type userCache struct {
+	ttl  time.Duration
+	size int
+
+	mu    sync.Mutex
+	ll    *list.List // спереди — недавно использованные
+	items map[int]*list.Element
+}
+
+type userCacheEntry struct {
+	id      int
+	user    models.User
+	expires time.Time
+}
+
+// newUserCache — кэш из USER_CACHE_TTL / USER_CACHE_SIZE; TTL "0" выключает кэш (nil).
+func newUserCache(cfg *config.Config) *userCache {
+	ttl, err := time.ParseDuration(cfg.UserCacheTTL)
+	if err != nil || ttl < 0 {
+		ttl = defaultUserCacheTTL
+	}
+	if ttl == 0 {
+		return nil
+	}
+	size, err := strconv.Atoi(strings.TrimSpace(cfg.UserCacheSize))
+	if err != nil || size <= 0 {
+		size = defaultUserCacheSize
+	}
+	return &userCache{ttl: ttl, size: size, ll: list.New(), items: map[int]*list.Element{}}
+}
+
+// get — копия пользователя из кэша (вызывающий может её менять).
+func (c *userCache) get(id int) (*models.User, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[id]
+	if !ok {
+		userCacheRequests.WithLabelValues("miss").Inc()
+		return nil, false
+	}
+	e := el.Value.(*userCacheEntry)
+	if time.Now().After(e.expires) {
+		c.ll.Remove(el)
+		delete(c.items, id)
+		userCacheRequests.WithLabelValues("miss").Inc()
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	userCacheRequests.WithLabelValues("hit").Inc()
+	u := e.user
+	return &u, true
+}
+
+func (c *userCache) put(u *models.User) {
+	if c == nil || u == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &userCacheEntry{id: u.ID, user: *u, expires: time.Now().Add(c.ttl)}
+	if el, ok := c.items[u.ID]; ok {
+		el.Value = entry
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[u.ID] = c.ll.PushFront(entry)
+	for c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*userCacheEntry).id)
+	}
+}
+
+func (c *userCache) invalidate(id int) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[id]; ok {
+		c.ll.Remove(el)
+		delete(c.items, id)
+	}
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils/helpers"
+
+	"go.uber.org/zap"
+)
+
+const (
+	userDigestItems = 10  // сколько материалов каждого вида перечисляем в письме
+	userDigestBatch = 200 // сколько получателей выбираем за один запрос
+)
+
+// UserDigestService — сводки новых материалов для пользователей, выбравших письма раз в день
+// или раз в неделю вместо немедленной рассылки. Период сводки заканчивается в заданный час
+// по Москве (еженедельной — в заданный день недели).
+type UserDigestService struct {
+	repo    *repository.UserDigestRepository
+	locks   JobLocker
+	baseURL string
+	weekday time.Weekday
+	hour    int
+}
+
+func NewUserDigestService(repo *repository.UserDigestRepository, locks JobLocker, cfg *config.Config) *UserDigestService {
+	s := &UserDigestService{
+		repo:    repo,
+		locks:   locks,
+		baseURL: strings.TrimRight(cfg.SiteURLNews, "/"),
+		weekday: time.Monday,
+		hour:    8,
+	}
+	if wd, ok := parseWeekday(cfg.UserDigestWeekday); ok {
+		s.weekday = wd
+	}
+	if h, err := strconv.Atoi(strings.TrimSpace(cfg.UserDigestHour)); err == nil && h >= 0 && h <= 23 {
+		s.hour = h
+	}
+	return s
+}
+
+// periodEnd — конец последнего наступившего периода сводки и его длина.
+func (s *UserDigestService) periodEnd(frequency string, now time.Time) (time.Time, time.Duration) {
+	now = now.In(quotaZone)
+	end := time.Date(now.Year(), now.Month(), now.Day(), s.hour, 0, 0, 0, quotaZone)
+	if end.After(now) {
+		end = end.AddDate(0, 0, -1)
+	}
+	if frequency == models.EmailFrequencyDaily {
+		return end, 24 * time.Hour
+	}
+	back := (int(end.Weekday()) - int(s.weekday) + 7) % 7
+	return end.AddDate(0, 0, -back), 7 * 24 * time.Hour
+}
+
+// RunOnce — отправляет сводки всем, чей период наступил к now; возвращает число отправленных писем.
+func (s *UserDigestService) RunOnce(ctx context.Context, now time.Time) (int, error) {
+	sent := 0
+	for _, freq := range []string{models.EmailFrequencyDaily, models.EmailFrequencyWeekly} {
+		end, period := s.periodEnd(freq, now)
+		for {
+			users, err := s.repo.DueUsers(ctx, freq, end, userDigestBatch)
+			if err != nil {
+				return sent, err
+			}
+			for _, u := range users {
+				ok, err := s.sendOne(ctx, u, end, period)
+				if err != nil {
+					return sent, err
+				}
+				if ok {
+					sent++
+				}
+			}
+			if len(users) < userDigestBatch {
+				break
+			}
+		}
+	}
+	return sent, nil
+}
+
+// sendOne — закрепляет период за пользователем и ставит письмо в очередь; пустую сводку не шлём.
+func (s *UserDigestService) sendOne(ctx context.Context, u models.DigestRecipient, end time.Time, period time.Duration) (bool, error) {
+	claimed, err := s.repo.Claim(ctx, u.UserID, end)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	d := &models.UserDigest{From: end.Add(-period), To: end}
+	if u.SentAt != nil && u.SentAt.Before(end) {
+		d.From = *u.SentAt
+	}
+	if err := s.repo.Content(ctx, u.UserID, d, userDigestItems); err != nil {
+		// период уже закреплён: материалы этой сводки пользователь не получит, но очередь не встанет
+		logger.WithCtx(ctx).Error("Не удалось собрать сводку пользователя", zap.Int("user_id", u.UserID), zap.Error(err))
+		return false, nil
+	}
+	if d.Empty() {
+		return false, nil
+	}
+
+	EmailQueue <- EmailJob{
+		To: []string{u.Email},
+		Subject: helpers.EmailPhrase(u.Locale, helpers.TextDigestSubject,
+			helpers.FormatEmailDay(u.Locale, d.From.In(quotaZone), false), helpers.FormatEmailDay(u.Locale, d.To.In(quotaZone), true)),
+		Body:   helpers.BuildSimpleHTML(u.Locale, helpers.EmailPhrase(u.Locale, helpers.TextDigestTitle), s.render(u, d)),
+		IsHTML: true,
+	}
+	return true, nil
+}
+
+func (s *UserDigestService) render(u models.DigestRecipient, d *models.UserDigest) string {
+	var b strings.Builder
+	if u.FullName != "" {
+		fmt.Fprintf(&b, "<p>%s</p>", helpers.EmailPhrase(u.Locale, helpers.TextDigestHello, html.EscapeString(u.FullName)))
+	}
+	fmt.Fprintf(&b, "<p>%s</p>", helpers.EmailPhrase(u.Locale, helpers.TextDigestIntro))
+
+	s.writeSection(&b, u.Locale, helpers.TextDigestNews, d.News, d.NewsTotal, s.baseURL+"/recomm", func(it models.DigestItem) string {
+		return fmt.Sprintf("%s/recomm/%d", s.baseURL, it.ID)
+	})
+	s.writeSection(&b, u.Locale, helpers.TextDigestArticles, d.Articles, d.ArticlesTotal, s.baseURL+"/zavuch", func(it models.DigestItem) string {
+		return fmt.Sprintf("%s/zavuch/%d", s.baseURL, it.ID)
+	})
+	s.writeSection(&b, u.Locale, helpers.TextDigestDocuments, d.Documents, d.DocumentsTotal, s.baseURL+"/documents", func(it models.DigestItem) string {
+		if it.TabSlug == "" || it.SectionSlug == "" {
+			return s.baseURL + "/documents"
+		}
+		return s.baseURL + "/" + url.PathEscape(it.TabSlug) + "/" + url.PathEscape(it.SectionSlug)
+	})
+
+	fmt.Fprintf(&b, `<p style="font-size:12px;color:#999;margin-top:24px;">%s</p>`, helpers.EmailPhrase(u.Locale, helpers.TextDigestFrequency))
+	return b.String()
+}
+
+// writeSection — раздел сводки; title — ключ фразы (helpers.TextDigest*).
+func (s *UserDigestService) writeSection(b *strings.Builder, locale, title string, items []models.DigestItem, total int, more string, link func(models.DigestItem) string) {
+	if total == 0 {
+		return
+	}
+	fmt.Fprintf(b, `<h3 style="color:#2d74da;margin:24px 0 8px 0;">%s (%d)</h3><ul style="margin:0;">`, helpers.EmailPhrase(locale, title), total)
+	for _, it := range items {
+		label := html.EscapeString(it.Title)
+		if it.SectionTitle != "" {
+			label += fmt.Sprintf(` <span style="color:#999;">— %s → %s</span>`, html.EscapeString(it.TabTitle), html.EscapeString(it.SectionTitle))
+		}
+		fmt.Fprintf(b, `<li><a href="%s">%s</a></li>`, link(it), label)
+	}
+	b.WriteString("</ul>")
+	if rest := total - len(items); rest > 0 {
+		fmt.Fprintf(b, `<p style="margin-top:8px;"><a href="%s" style="color:#2d74da;">%s</a></p>`, more, helpers.EmailPhrase(locale, helpers.TextDigestMore, rest))
+	}
+}
+
+// Start — раз в час отправляет наступившие сводки; возвращает функцию остановки.
+// Вызывать остановку до StopEmailWorkers: письма ставятся в EmailQueue.
+func (s *UserDigestService) Start() func() {
+	ticker := time.NewTicker(time.Hour)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		logger.Log.Info("UserDigest запущен",
+			zap.String("weekday", s.weekday.String()),
+			zap.Int("hour", s.hour),
+		)
+		run := func() {
+			_ = RunExclusive(context.Background(), s.locks, JobUserDigest, func(ctx context.Context) error {
+				sent, err := s.RunOnce(ctx, time.Now())
+				if err != nil {
+					logger.Log.Error("Ошибка отправки сводок пользователям", zap.Error(err))
+				}
+				if sent > 0 {
+					logger.Log.Info("Сводки пользователям поставлены в очередь", zap.Int("count", sent))
+				}
+				return err
+			})
+		}
+		run()
+		for {
+			select {
+			case <-ticker.C:
+				run()
+			case <-done:
+				ticker.Stop()
+				logger.Log.Info("UserDigest остановлен")
+				return
+			}
+		}
+	}()
+
+	return StopAndWait(JobUserDigest, done, stopped)
+}
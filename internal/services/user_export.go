@@ -0,0 +1,346 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
+	"edutalks/internal/utils/helpers"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrExportLinkInvalid = errors.New("ссылка на выгрузку недействительна или устарела")
+	ErrExportNotReady    = errors.New("выгрузка ещё не готова")
+)
+
+const (
+	defaultExportLinkTTL = 72 * time.Hour
+	// exportReuseWindow — повторный запрос в этом окне возвращает уже начатую/готовую выгрузку.
+	exportReuseWindow = 10 * time.Minute
+	exportCleanupTick = time.Hour
+)
+
+// UserExportService — выгрузка всех данных пользователя (профиль, рассылки, платежи, квитанции,
+// скачивания) в ZIP. Собирается в фоне; ссылка с подписью и сроком действия уходит письмом
+// тому, кто запросил выгрузку.
+type UserExportService struct {
+	repo      *repository.UserExportRepository
+	users     repository.UserRepo
+	payments  *repository.PaymentRepository
+	invoices  *repository.InvoiceRepository
+	downloads *repository.DownloadRepository
+
+	dir     string
+	linkTTL time.Duration
+	baseURL string // пример: https://edutalks.ru/api/exports
+	cfg     *config.Holder
+}
+
+func NewUserExportService(
+	repo *repository.UserExportRepository,
+	users repository.UserRepo,
+	payments *repository.PaymentRepository,
+	invoices *repository.InvoiceRepository,
+	downloads *repository.DownloadRepository,
+	live *config.Holder,
+) *UserExportService {
+	cfg := live.Get()
+	ttl, err := time.ParseDuration(cfg.ExportLinkTTL)
+	if err != nil || ttl <= 0 {
+		ttl = defaultExportLinkTTL
+	}
+	return &UserExportService{
+		repo:      repo,
+		users:     users,
+		payments:  payments,
+		invoices:  invoices,
+		downloads: downloads,
+		dir:       cfg.ExportDir,
+		linkTTL:   ttl,
+		baseURL:   strings.TrimRight(cfg.ExportURL, "/"),
+		cfg:       live,
+	}
+}
+
+// Request — запускает выгрузку данных userID; requestedBy — кто запросил (ему уйдёт ссылка).
+// Если такая выгрузка уже собирается или готова (запрошена недавно), возвращается она.
+func (s *UserExportService) Request(ctx context.Context, userID, requestedBy int) (*models.UserExport, error) {
+	log := logger.WithCtx(ctx)
+
+	if _, err := s.users.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if e, err := s.repo.Active(ctx, userID, &requestedBy, time.Now().Add(-exportReuseWindow)); err == nil {
+		log.Info("Выгрузка данных уже запрошена — возвращаем текущую", zap.Int64("export_id", e.ID), zap.String("status", e.Status))
+		return e, nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	e, err := s.repo.Create(ctx, userID, &requestedBy)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Выгрузка данных пользователя запрошена",
+		zap.Int64("export_id", e.ID), zap.Int("user_id", userID), zap.Int("requested_by", requestedBy))
+
+	go s.build(context.WithoutCancel(ctx), e.ID, userID, requestedBy)
+	return e, nil
+}
+
+func (s *UserExportService) build(ctx context.Context, id int64, userID, requestedBy int) {
+	log := logger.WithCtx(ctx).With(zap.Int64("export_id", id), zap.Int("user_id", userID))
+	start := time.Now()
+
+	path, size, err := s.writeArchive(ctx, id, userID)
+	if err != nil {
+		log.Error("Ошибка сборки выгрузки данных", zap.Error(err))
+		_ = s.repo.MarkFailed(ctx, id, err.Error())
+		return
+	}
+
+	expiresAt := time.Now().Add(s.linkTTL)
+	if err := s.repo.MarkReady(ctx, id, path, size, expiresAt); err != nil {
+		_ = os.Remove(path)
+		return
+	}
+	log.Info("Выгрузка данных собрана", zap.Int64("size_bytes", size), zap.Duration("took", time.Since(start)))
+
+	s.notify(ctx, id, userID, requestedBy, expiresAt)
+}
+
+// Bundle — все данные пользователя.
+func (s *UserExportService) Bundle(ctx context.Context, userID int) (*models.UserExportBundle, error) {
+	u, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	b := &models.UserExportBundle{
+		GeneratedAt: time.Now().UTC(),
+		Profile:     *u,
+		EmailSubscription: models.UserEmailSubscription{
+			Email:             u.Email,
+			EmailSubscription: u.EmailSubscription,
+			EmailVerified:     u.EmailVerified,
+			Locale:            u.Locale,
+			EmailFrequency:    u.EmailFrequency,
+		},
+	}
+	if b.Payments, err = s.payments.ListByUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if b.Invoices, err = s.invoices.ListByUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if b.Downloads, err = s.downloads.ListByUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// writeArchive — ZIP с разделами выгрузки; файл пишется во временный и переименовывается.
+func (s *UserExportService) writeArchive(ctx context.Context, id int64, userID int) (string, int64, error) {
+	b, err := s.Bundle(ctx, userID)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return "", 0, err
+	}
+
+	path := filepath.Join(s.dir, fmt.Sprintf("export-%d-user-%d.zip", id, userID))
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp) // после переименования — no-op
+
+	zw := zip.NewWriter(f)
+	sections := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", b.Profile},
+		{"email_subscription.json", b.EmailSubscription},
+		{"payments.json", b.Payments},
+		{"invoices.json", b.Invoices},
+		{"downloads.json", b.Downloads},
+		{"export.json", b},
+	}
+	for _, sec := range sections {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: sec.name, Method: zip.Deflate, Modified: b.GeneratedAt})
+		if err != nil {
+			_ = f.Close()
+			return "", 0, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(sec.data); err != nil {
+			_ = f.Close()
+			return "", 0, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		_ = f.Close()
+		return "", 0, err
+	}
+	info, err := f.Stat()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", 0, err
+	}
+	return path, info.Size(), nil
+}
+
+// notify — письмо со ссылкой тому, кто запросил выгрузку.
+func (s *UserExportService) notify(ctx context.Context, id int64, userID, requestedBy int, expiresAt time.Time) {
+	log := logger.WithCtx(ctx).With(zap.Int64("export_id", id))
+
+	to, err := s.users.GetUserByID(ctx, requestedBy)
+	if err != nil || to.Email == "" {
+		log.Warn("Не удалось определить адрес для письма о выгрузке", zap.Int("requested_by", requestedBy), zap.Error(err))
+		return
+	}
+
+	link := s.DownloadURL(id, expiresAt)
+	intro := helpers.EmailPhrase(to.Locale, helpers.TextExportReady)
+	if userID != requestedBy {
+		intro = helpers.EmailPhrase(to.Locale, helpers.TextExportReadyUser, userID)
+	}
+	body := fmt.Sprintf(`
+      <p style="font-size:16px;color:#222;margin:0 0 16px 0;">%s</p>
+      <p><a href="%s" style="display:inline-block;padding:12px 24px;background:#2d74da;color:#fff;text-decoration:none;border-radius:6px;font-weight:600;">%s</a></p>
+      <p style="font-size:13px;color:#666;">%s</p>
+    `, html.EscapeString(intro), link, helpers.EmailPhrase(to.Locale, helpers.TextExportButton),
+		helpers.EmailPhrase(to.Locale, helpers.TextExportValid, helpers.FormatEmailDate(to.Locale, expiresAt.UTC())))
+
+	EmailQueue <- EmailJob{
+		To:      []string{to.Email},
+		Subject: helpers.EmailSubject(to.Locale, helpers.MailDataExport),
+		Body:    helpers.BuildSimpleHTML(to.Locale, helpers.EmailPhrase(to.Locale, helpers.TextExportTitle), body),
+		IsHTML:  true,
+	}
+	log.Info("Письмо со ссылкой на выгрузку поставлено в очередь", zap.Int("requested_by", requestedBy))
+}
+
+// DownloadURL — подписанная ссылка на архив: <ExportURL>/<id>/download?token=<exp>.<sig>.
+func (s *UserExportService) DownloadURL(id int64, expiresAt time.Time) string {
+	return fmt.Sprintf("%s/%d/download?token=%s", s.baseURL, id, url.QueryEscape(s.downloadToken(id, expiresAt)))
+}
+
+func (s *UserExportService) downloadToken(id int64, expiresAt time.Time) string {
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	return exp + "." + base64.RawURLEncoding.EncodeToString(s.exportMAC(id, exp))
+}
+
+// secret — ключ подписи ссылок: JWT_SECRET текущей конфигурации (меняется по SIGHUP).
+func (s *UserExportService) secret() []byte {
+	return []byte(s.cfg.Get().JWTSecret)
+}
+
+func (s *UserExportService) exportMAC(id int64, exp string) []byte {
+	mac := hmac.New(sha256.New, s.secret())
+	mac.Write([]byte("export:" + strconv.FormatInt(id, 10) + ":" + exp))
+	return mac.Sum(nil)
+}
+
+// Open — проверяет подпись ссылки и возвращает готовую выгрузку (путь к файлу — в FilePath).
+func (s *UserExportService) Open(ctx context.Context, id int64, token string) (*models.UserExport, error) {
+	exp, sig, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok || len(s.secret()) == 0 {
+		return nil, ErrExportLinkInvalid
+	}
+	rawSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(rawSig, s.exportMAC(id, exp)) {
+		return nil, ErrExportLinkInvalid
+	}
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() >= expUnix {
+		return nil, ErrExportLinkInvalid
+	}
+
+	e, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrExportLinkInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch e.Status {
+	case models.UserExportReady:
+		return e, nil
+	case models.UserExportPending:
+		return nil, ErrExportNotReady
+	}
+	return nil, ErrExportLinkInvalid
+}
+
+// Cleanup — удаляет файлы истёкших выгрузок.
+func (s *UserExportService) Cleanup(ctx context.Context) error {
+	list, err := s.repo.ExpireDue(ctx)
+	if err != nil {
+		return err
+	}
+	for _, e := range list {
+		if e.FilePath == "" {
+			continue
+		}
+		if err := os.Remove(e.FilePath); err != nil && !os.IsNotExist(err) {
+			logger.Log.Warn("Не удалось удалить файл истёкшей выгрузки", zap.Int64("export_id", e.ID), zap.Error(err))
+		}
+	}
+	if len(list) > 0 {
+		logger.Log.Info("Истёкшие выгрузки данных удалены", zap.Int("count", len(list)))
+	}
+	return nil
+}
+
+// Start — периодическая очистка истёкших выгрузок; возвращает функцию остановки.
+func (s *UserExportService) Start() func() {
+	ticker := time.NewTicker(exportCleanupTick)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Cleanup(context.Background()); err != nil {
+					logger.Log.Error("Ошибка очистки выгрузок данных", zap.Error(err))
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return StopAndWait("export_cleanup", done, stopped)
+}
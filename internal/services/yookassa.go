@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"edutalks/internal/logger"
+	"edutalks/internal/models"
+	"edutalks/internal/repository"
 	"go.uber.org/zap"
 
 	"github.com/google/uuid"
@@ -19,15 +21,28 @@ type YooKassaService struct {
 	SecretKey  string
 	ReturnURL  string
 	HTTPClient *http.Client
+	// Sandbox — платёж не уходит в ЮKassa: создаётся запись sandbox-*, а уведомление
+	// об оплате эмулирует админ (POST /api/admin/payments/sandbox/webhook).
+	Sandbox bool
+
+	payments *repository.PaymentRepository
 }
 
-func NewYooKassaService(shopID, secretKey, returnURL string) *YooKassaService {
+// SandboxPaymentPrefix — префикс id платежей, созданных в режиме песочницы.
+const SandboxPaymentPrefix = "sandbox-"
+
+func NewYooKassaService(shopID, secretKey, returnURL string, sandbox bool, payments *repository.PaymentRepository) *YooKassaService {
 	client := &http.Client{Timeout: 15 * time.Second}
+	if sandbox {
+		logger.Log.Warn("YooKassa: включён режим песочницы (PAYMENT_SANDBOX) — реальные платежи не создаются")
+	}
 	return &YooKassaService{
 		ShopID:     shopID,
 		SecretKey:  secretKey,
 		ReturnURL:  returnURL,
 		HTTPClient: client,
+		Sandbox:    sandbox,
+		payments:   payments,
 	}
 }
 
@@ -68,6 +83,7 @@ type ykError struct {
 
 // CreatePayment — создаёт платёж и возвращает URL для подтверждения.
 // value — рубли (например 1250.00), plan — один из: monthly | halfyear | yearly.
+// Каждая попытка (в том числе неудачная) сохраняется в истории платежей.
 func (s *YooKassaService) CreatePayment(ctx context.Context, value float64, description string, userID int, plan string) (string, error) {
 	if value <= 0 {
 		return "", fmt.Errorf("amount must be positive")
@@ -78,6 +94,43 @@ func (s *YooKassaService) CreatePayment(ctx context.Context, value float64, desc
 		return "", fmt.Errorf("invalid plan")
 	}
 
+	res, err := s.createPayment(ctx, value, description, userID, plan)
+
+	rec := &models.Payment{
+		UserID:      userID,
+		Provider:    "yookassa",
+		Plan:        plan,
+		Amount:      value,
+		Currency:    "RUB",
+		Description: description,
+		Status:      models.PaymentStatusPending,
+	}
+	if err != nil {
+		msg := err.Error()
+		rec.Status = models.PaymentStatusFailed
+		rec.Error = &msg
+	} else {
+		rec.ProviderPaymentID = &res.ID
+		if res.Status != "" {
+			rec.Status = res.Status
+		}
+	}
+	if s.payments != nil {
+		// история не должна ломать оплату — ошибку только логируем (в репозитории)
+		_, _ = s.payments.Create(context.WithoutCancel(ctx), rec)
+	}
+
+	if err != nil {
+		return "", err
+	}
+	return res.Confirmation.ConfirmationURL, nil
+}
+
+func (s *YooKassaService) createPayment(ctx context.Context, value float64, description string, userID int, plan string) (*CreatePaymentResponse, error) {
+	if s.Sandbox {
+		return s.createSandboxPayment(userID, plan), nil
+	}
+
 	reqBody := CreatePaymentRequest{
 		Amount: Amount{
 			// ЮKassa требует 2 знака после запятой
@@ -98,12 +151,12 @@ func (s *YooKassaService) CreatePayment(ctx context.Context, value float64, desc
 
 	data, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.yookassa.ru/v3/payments", bytes.NewBuffer(data))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
@@ -119,7 +172,7 @@ func (s *YooKassaService) CreatePayment(ctx context.Context, value float64, desc
 
 	resp, err := s.HTTPClient.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -127,13 +180,13 @@ func (s *YooKassaService) CreatePayment(ctx context.Context, value float64, desc
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 		var res CreatePaymentResponse
 		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
-			return "", err
+			return nil, err
 		}
 		logger.Log.Info("YooKassa: платёж создан",
 			zap.String("payment_id", res.ID),
 			zap.String("status", res.Status),
 		)
-		return res.Confirmation.ConfirmationURL, nil
+		return &res, nil
 	}
 
 	// Ошибка: попробуем разобрать тело от ЮKassa
@@ -146,11 +199,33 @@ func (s *YooKassaService) CreatePayment(ctx context.Context, value float64, desc
 			zap.String("desc", ek.Description),
 			zap.String("param", ek.Parameter),
 		)
-		return "", fmt.Errorf("yookassa error: %s (%s)", ek.Description, ek.Code)
+		return nil, fmt.Errorf("yookassa error: %s (%s)", ek.Description, ek.Code)
 	}
 
 	logger.Log.Warn("YooKassa: неизвестная ошибка создания платежа",
 		zap.Int("http_status", resp.StatusCode),
 	)
-	return "", fmt.Errorf("yookassa http status: %d", resp.StatusCode)
+	return nil, fmt.Errorf("yookassa http status: %d", resp.StatusCode)
+}
+
+// createSandboxPayment — ответ «как от ЮKassa» без обращения к API; подтверждение сразу ведёт на ReturnURL.
+func (s *YooKassaService) createSandboxPayment(userID int, plan string) *CreatePaymentResponse {
+	res := &CreatePaymentResponse{
+		ID:     SandboxPaymentPrefix + uuid.NewString(),
+		Status: models.PaymentStatusPending,
+	}
+	res.Confirmation.Type = "redirect"
+	res.Confirmation.ConfirmationURL = s.ReturnURL
+
+	logger.Log.Info("YooKassa: sandbox-платёж создан",
+		zap.String("payment_id", res.ID),
+		zap.Int("user_id", userID),
+		zap.String("plan", plan),
+	)
+	return res
+}
+
+// ListPayments — история платежей (пользователя — через f.UserID, либо все для админки).
+func (s *YooKassaService) ListPayments(ctx context.Context, f models.PaymentFilter, limit, offset int) ([]models.Payment, int, error) {
+	return s.payments.List(ctx, f, limit, offset)
 }
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"edutalks/internal/config"
+	"edutalks/internal/logger"
+	"edutalks/internal/utils/clientip"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Официальные адреса, с которых ЮKassa присылает HTTP-уведомления.
+var yooKassaDefaultWebhookNets = []string{
+	"185.71.76.0/27",
+	"185.71.77.0/27",
+	"77.75.153.0/25",
+	"77.75.156.11/32",
+	"77.75.156.35/32",
+	"77.75.154.128/25",
+	"2a02:5180::/32",
+}
+
+var (
+	ErrWebhookIPNotAllowed   = errors.New("webhook: ip not allowed")
+	ErrWebhookBadCredentials = errors.New("webhook: bad credentials")
+	ErrWebhookBadSignature   = errors.New("webhook: bad signature")
+)
+
+// YooKassaWebhookGuard — проверка входящих уведомлений: IP-allowlist, basic-auth и HMAC тела.
+// ЮKassa сама не подписывает уведомления, поэтому basic-auth (в URL уведомления) и HMAC
+// (если перед нами стоит подписывающий прокси) опциональны и включаются заданием секретов.
+type YooKassaWebhookGuard struct {
+	nets     []*net.IPNet
+	anyIP    bool
+	user     string
+	password string
+	secret   []byte
+}
+
+func NewYooKassaWebhookGuard(cfg *config.Config) *YooKassaWebhookGuard {
+	g := &YooKassaWebhookGuard{
+		user:     cfg.YooKassaWebhookUser,
+		password: cfg.YooKassaWebhookPassword,
+		secret:   []byte(cfg.YooKassaWebhookSecret),
+	}
+
+	list := yooKassaDefaultWebhookNets
+	if raw := strings.TrimSpace(cfg.YooKassaWebhookIPs); raw != "" {
+		list = strings.Split(raw, ",")
+	}
+	for _, item := range list {
+		item = strings.TrimSpace(item)
+		switch {
+		case item == "":
+			continue
+		case item == "*":
+			g.anyIP = true
+			continue
+		case !strings.Contains(item, "/"):
+			if strings.Contains(item, ":") {
+				item += "/128"
+			} else {
+				item += "/32"
+			}
+		}
+		_, n, err := net.ParseCIDR(item)
+		if err != nil {
+			logger.Log.Warn("YooKassa webhook: некорректная запись в allowlist — пропущена", zap.String("value", item), zap.Error(err))
+			continue
+		}
+		g.nets = append(g.nets, n)
+	}
+
+	logger.Log.Info("Сервис: инициализация проверки вебхуков ЮKassa",
+		zap.Int("allow_nets", len(g.nets)),
+		zap.Bool("any_ip", g.anyIP),
+		zap.Bool("basic_auth", g.user != ""),
+		zap.Bool("hmac", len(g.secret) > 0),
+	)
+	return g
+}
+
+// ClientIP — IP отправителя с учётом доверенных прокси (TRUSTED_PROXIES).
+func (g *YooKassaWebhookGuard) ClientIP(r *http.Request) string {
+	return clientip.FromRequest(r)
+}
+
+// Verify — проверяет запрос и уже прочитанное тело; возвращает одну из ErrWebhook* при отказе.
+func (g *YooKassaWebhookGuard) Verify(r *http.Request, body []byte) error {
+	if !g.anyIP {
+		ip := net.ParseIP(g.ClientIP(r))
+		allowed := false
+		for _, n := range g.nets {
+			if ip != nil && n.Contains(ip) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return ErrWebhookIPNotAllowed
+		}
+	}
+
+	if g.user != "" {
+		u, p, ok := r.BasicAuth()
+		if !ok ||
+			subtle.ConstantTimeCompare([]byte(u), []byte(g.user)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(p), []byte(g.password)) != 1 {
+			return ErrWebhookBadCredentials
+		}
+	}
+
+	if len(g.secret) > 0 {
+		got, err := hex.DecodeString(strings.TrimSpace(r.Header.Get("X-Webhook-Signature")))
+		if err != nil || len(got) == 0 {
+			return ErrWebhookBadSignature
+		}
+		mac := hmac.New(sha256.New, g.secret)
+		mac.Write(body)
+		if !hmac.Equal(got, mac.Sum(nil)) {
+			return ErrWebhookBadSignature
+		}
+	}
+	return nil
+}
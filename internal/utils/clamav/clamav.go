@@ -0,0 +1,140 @@
+// Package clamav — клиент clamd (протокол INSTREAM): файл передаётся потоком по сокету,
+// clamd отвечает «stream: OK» или «stream: <сигнатура> FOUND».
+package clamav
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const chunkSize = 64 << 10
+
+// ErrSizeLimit — файл больше StreamMaxLength в настройках clamd.
+var ErrSizeLimit = errors.New("clamav: файл превышает лимит clamd (StreamMaxLength)")
+
+type Client struct {
+	network string
+	addr    string
+	timeout time.Duration
+}
+
+// New — клиент по адресу "unix:/run/clamav/clamd.ctl", "/run/clamav/clamd.ctl", "tcp://host:3310"
+// или "host:3310". Пустой адрес — nil (проверка выключена).
+func New(address string, timeout time.Duration) *Client {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	c := &Client{network: "tcp", addr: address, timeout: timeout}
+	switch {
+	case strings.HasPrefix(address, "unix:"):
+		c.network, c.addr = "unix", strings.TrimPrefix(address, "unix:")
+	case strings.HasPrefix(address, "/"):
+		c.network = "unix"
+	case strings.HasPrefix(address, "tcp://"):
+		c.addr = strings.TrimPrefix(address, "tcp://")
+	}
+	return c
+}
+
+func (c *Client) dial(ctx context.Context) (net.Conn, error) {
+	d := net.Dialer{Timeout: 5 * time.Second}
+	conn, err := d.DialContext(ctx, c.network, c.addr)
+	if err != nil {
+		return nil, fmt.Errorf("clamav: подключение к %s: %w", c.addr, err)
+	}
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+	return conn, nil
+}
+
+// Ping — clamd отвечает PONG.
+func (c *Client) Ping(ctx context.Context) error {
+	reply, err := c.command(ctx, "zPING\x00", nil)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("clamav: неожиданный ответ на PING: %q", reply)
+	}
+	return nil
+}
+
+// Scan — проверяет поток; сигнатура угрозы или "" для чистого файла.
+func (c *Client) Scan(ctx context.Context, r io.Reader) (string, error) {
+	reply, err := c.command(ctx, "zINSTREAM\x00", r)
+	if err != nil {
+		return "", err
+	}
+	// ответ: "stream: OK" | "stream: Eicar-Signature FOUND" | "... ERROR"
+	reply = strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case reply == "OK":
+		return "", nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return strings.TrimSuffix(reply, " FOUND"), nil
+	case strings.Contains(reply, "size limit exceeded"):
+		return "", ErrSizeLimit
+	}
+	return "", fmt.Errorf("clamav: ошибка проверки: %s", reply)
+}
+
+func (c *Client) command(ctx context.Context, cmd string, body io.Reader) (string, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if _, err := io.WriteString(conn, cmd); err != nil {
+		return "", fmt.Errorf("clamav: запись команды: %w", err)
+	}
+	if body != nil {
+		if err := writeChunks(conn, body); err != nil {
+			return "", err
+		}
+	}
+
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil && !(errors.Is(err, io.EOF) && len(reply) > 0) {
+		return "", fmt.Errorf("clamav: чтение ответа: %w", err)
+	}
+	return string(bytes.TrimSpace(bytes.TrimRight(reply, "\x00"))), nil
+}
+
+// writeChunks — поток кусками <длина uint32 BE><данные>, в конце — кусок нулевой длины.
+// Ошибка записи не фатальна: при превышении лимита clamd закрывает приём, но ответ присылает.
+func writeChunks(w io.Writer, r io.Reader) error {
+	buf := make([]byte, 4+chunkSize)
+	for {
+		n, err := io.ReadFull(r, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, werr := w.Write(buf[:4+n]); werr != nil {
+				return nil
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("clamav: чтение файла: %w", err)
+		}
+	}
+	w.Write([]byte{0, 0, 0, 0})
+	return nil
+}
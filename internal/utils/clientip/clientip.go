@@ -0,0 +1,108 @@
+// Package clientip — IP клиента запроса; общий для ограничения частоты, вебхуков ЮKassa,
+// сессий, логов и Sentry, чтобы правило доверия прокси было одно (TRUSTED_PROXIES).
+package clientip
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Proxies — доверенные прокси: только от них принимаются X-Forwarded-For / X-Real-IP.
+type Proxies []netip.Prefix
+
+// Private — loopback и частные сети: nginx на том же хосте или в docker-сети.
+var Private = Proxies{
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("::1/128"),
+	netip.MustParsePrefix("fc00::/7"),
+}
+
+// ParseProxies — CSV из IP/CIDR; некорректные записи возвращаются в bad и пропускаются.
+func ParseProxies(csv string) (p Proxies, bad []string) {
+	for _, item := range strings.Split(csv, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(item); err == nil {
+			p = append(p, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(item); err == nil {
+			p = append(p, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		bad = append(bad, item)
+	}
+	return p, bad
+}
+
+func (p Proxies) trusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range p {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve — IP клиента. Заголовки учитываются, только если соединение пришло от доверенного
+// прокси: тогда берётся самый правый адрес X-Forwarded-For, который сам не доверенный прокси
+// (левые записи дописывает клиент, им верить нельзя), а без X-Forwarded-For — X-Real-IP.
+func (p Proxies) Resolve(r *http.Request) string {
+	remote := remoteHost(r)
+	addr, err := netip.ParseAddr(remote)
+	if err != nil || !p.trusted(addr) {
+		return remote
+	}
+
+	xff := strings.TrimSpace(strings.Join(r.Header.Values("X-Forwarded-For"), ","))
+	if xff == "" {
+		if real, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+			return real.Unmap().String()
+		}
+		return remote
+	}
+	hops := strings.Split(xff, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break // дальше цепочка не проверяема — остаёмся на последнем доверенном узле
+		}
+		if !p.trusted(hop) {
+			return hop.Unmap().String()
+		}
+		addr = hop
+	}
+	return addr.Unmap().String()
+}
+
+type ctxKey struct{}
+
+// WithIP — IP клиента, определённый middleware.ClientIP, в контексте запроса.
+func WithIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, ip)
+}
+
+// FromRequest — IP клиента, определённый middleware.ClientIP; без него — адрес соединения.
+func FromRequest(r *http.Request) string {
+	if ip, ok := r.Context().Value(ctxKey{}).(string); ok {
+		return ip
+	}
+	return remoteHost(r)
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,57 @@
+package clientip
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	proxies, bad := ParseProxies("192.0.2.1, 10.0.0.0/8, nonsense")
+	if len(bad) != 1 || bad[0] != "nonsense" {
+		t.Fatalf("bad = %v", bad)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		remote    string
+		realIP    string
+		forwarded string
+		want      string
+	}{
+		{"remote addr", "192.0.2.1:1234", "", "", "192.0.2.1"},
+		{"headers ignored from untrusted peer", "198.51.100.9:1234", "203.0.113.7", "198.51.100.2", "198.51.100.9"},
+		{"x-real-ip", "192.0.2.1:1234", " 203.0.113.7 ", "", "203.0.113.7"},
+		{"right-most x-forwarded-for", "192.0.2.1:1234", "", "198.51.100.2", "198.51.100.2"},
+		// клиент сам подставил левую запись — её не берём
+		{"spoofed left entry", "192.0.2.1:1234", "", "1.2.3.4, 198.51.100.2", "198.51.100.2"},
+		{"skip trusted hops", "192.0.2.1:1234", "", "198.51.100.2, 10.1.2.3", "198.51.100.2"},
+		{"garbage stops the walk", "192.0.2.1:1234", "", "198.51.100.2, junk, 10.1.2.3", "10.1.2.3"},
+		{"x-forwarded-for wins over x-real-ip", "192.0.2.1:1234", "203.0.113.7", "198.51.100.2", "198.51.100.2"},
+		{"all hops trusted", "192.0.2.1:1234", "", "10.0.0.5", "10.0.0.5"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tc.remote
+			if tc.realIP != "" {
+				r.Header.Set("X-Real-IP", tc.realIP)
+			}
+			if tc.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tc.forwarded)
+			}
+			if got := proxies.Resolve(r); got != tc.want {
+				t.Errorf("Resolve = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil) // RemoteAddr 192.0.2.1:1234
+	if got := FromRequest(r); got != "192.0.2.1" {
+		t.Errorf("без middleware: %q", got)
+	}
+	r = r.WithContext(WithIP(r.Context(), "203.0.113.7"))
+	if got := FromRequest(r); got != "203.0.113.7" {
+		t.Errorf("из контекста: %q", got)
+	}
+}
@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// JSONConditional — ответ 200 как у JSON, но с ETag (хэш тела); на GET/HEAD с совпавшим
+// If-None-Match — 304 без тела. Last-Modified не отдаётся: для списков его пришлось бы брать
+// из max(updated_at), а удаление записи или её выпадение из выборки это время не меняет —
+// клиент с If-Modified-Since получил бы 304 на устаревший список.
+func JSONConditional(w http.ResponseWriter, r *http.Request, data interface{}) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(Response{Data: data}); err != nil {
+		Error(w, http.StatusInternalServerError, "Ошибка формирования ответа")
+		return
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+	h := w.Header()
+	h.Set("ETag", etag)
+	// кэшировать можно, но перед использованием — перепроверить
+	h.Set("Cache-Control", "public, no-cache")
+
+	if notModified(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	h.Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(buf.Bytes())
+	}
+}
+
+func notModified(r *http.Request, etag string) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	inm := r.Header.Get("If-None-Match")
+	return inm != "" && etagMatch(inm, etag)
+}
+
+// etagMatch — слабое сравнение со списком из If-None-Match.
+func etagMatch(header, etag string) bool {
+	want := strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
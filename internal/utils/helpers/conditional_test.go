@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJSONConditional(t *testing.T) {
+	list := []string{"a", "b"}
+
+	rec := httptest.NewRecorder()
+	JSONConditional(rec, httptest.NewRequest(http.MethodGet, "/api/articles", nil), list)
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" {
+		t.Fatalf("code = %d, etag = %q", rec.Code, etag)
+	}
+	if lm := rec.Header().Get("Last-Modified"); lm != "" {
+		t.Errorf("Last-Modified = %q on a list response", lm)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		header string
+		value  string
+		data   []string
+		want   int
+	}{
+		{"same etag", "If-None-Match", etag, list, http.StatusNotModified},
+		{"etag in list", "If-None-Match", `"other", ` + etag, list, http.StatusNotModified},
+		// запись удалили — тело и ETag другие
+		{"item deleted", "If-None-Match", etag, list[:1], http.StatusOK},
+		// If-Modified-Since без Last-Modified не даёт 304 на устаревший список
+		{"if-modified-since ignored", "If-Modified-Since", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), list[:1], http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/articles", nil)
+			r.Header.Set(tc.header, tc.value)
+			rec := httptest.NewRecorder()
+			JSONConditional(rec, r, tc.data)
+			if rec.Code != tc.want {
+				t.Errorf("code = %d, want %d", rec.Code, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,514 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Языки транзакционных писем (users.locale). Пустой или неизвестный — русский.
+const (
+	LocaleRU      = "ru"
+	LocaleEN      = "en"
+	DefaultLocale = LocaleRU
+)
+
+// Виды писем — ключи для EmailSubject.
+const (
+	MailVerification         = "verification"
+	MailPasswordReset        = "password_reset"
+	MailSubscriptionGranted  = "subscription_granted"
+	MailSubscriptionExtended = "subscription_extended"
+	MailSubscriptionRevoked  = "subscription_revoked"
+	MailSubscriptionExpiring = "subscription_expiring"
+	MailSubscriptionExpired  = "subscription_expired"
+	MailInvoice              = "invoice"
+	MailAccountDeletion      = "account_deletion"
+	MailEmailChange          = "email_change"
+	MailNewDocument          = "new_document"
+	MailNewsPublished        = "news_published"
+	MailArticlePublished     = "article_published"
+	MailBatchNew             = "batch_new"
+	MailBatchUpdated         = "batch_updated"
+	MailDataExport           = "data_export"
+	MailNewLogin             = "new_login"
+)
+
+// Фразы писем и SMS, которые собирают сервисы (рассылки, сводки, выгрузка, коды) — ключи для EmailPhrase.
+const (
+	TextButtonFallback     = "button_fallback" // %s — ссылка
+	TextNewDocumentTitle   = "new_document_title"
+	TextOpenDocument       = "open_document"
+	TextArticleTitle       = "article_title"
+	TextReadArticle        = "read_article"
+	TextBatchNewTitle      = "batch_new_title"
+	TextBatchUpdatedTitle  = "batch_updated_title"
+	TextBatchIntro         = "batch_intro"
+	TextBatchUpdated       = "batch_updated"
+	TextBatchNoSection     = "batch_no_section"
+	TextBatchArticles      = "batch_articles"
+	TextBatchNewDocuments  = "batch_new_documents" // %d — число документов
+	TextDigestSubject      = "digest_subject"      // %s — начало и конец периода
+	TextDigestTitle        = "digest_title"
+	TextDigestHello        = "digest_hello" // %s — имя
+	TextDigestIntro        = "digest_intro"
+	TextDigestNews         = "digest_news"
+	TextDigestArticles     = "digest_articles"
+	TextDigestDocuments    = "digest_documents"
+	TextDigestMore         = "digest_more" // %d — сколько не поместилось
+	TextDigestFrequency    = "digest_frequency"
+	TextAdminDigestSubject = "admin_digest_subject" // %s — начало и конец периода
+	TextExportTitle        = "export_title"
+	TextExportReady        = "export_ready"
+	TextExportReadyUser    = "export_ready_user" // %d — ID пользователя
+	TextExportButton       = "export_button"
+	TextExportValid        = "export_valid"   // %s — срок действия ссылки
+	TextSMSPhoneCode       = "sms_phone_code" // SMS; %s — код, %s — срок действия
+	TextSMSResetCode       = "sms_reset_code" // SMS; %s — код, %s — срок действия
+)
+
+// emailTexts — тексты писем одного языка.
+type emailTexts struct {
+	dateLayout, dayLayout, shortDayLayout string
+	subjects                              map[string]string
+	phrases                               map[string]string
+
+	hours, minutes      string // «%d ч»
+	years, months, days string // срок подписки
+
+	autoFooter string
+
+	verifyTitle, verifyHello, verifyText, verifyButton, verifyApp, verifyAppLink, verifyValid, verifyIgnore string
+
+	resetTitle, resetText, resetHint, resetButton, resetValid, resetIgnore string
+
+	grantedTitle, grantedText, grantedUntil, grantedThanks string
+
+	revokedTitle, revokedText, revokedPrev, revokedSupport string
+
+	expiringTitle, expiringText, expiringHint, expiringButton string
+
+	expiredTitle, expiredText, expiredHint, expiredButton string
+
+	invoiceTitle, invoiceText, invoiceHint string
+
+	deletionTitle, deletionText, deletionHint, deletionButton, deletionIgnore string
+
+	changeTitle, changeText, changeHint, changeButton, changeValid, changeIgnore string
+
+	loginTitle, loginText, loginTime, loginIP, loginLocation, loginDevice, loginHint, loginButton, loginFooter string
+
+	newsButton, subscribedFooter string
+
+	digestHeading, digestDownloads, digestNoDownloads, digestErrors, digestNoErrors, digestDocument string
+	digestNewUsers, digestPayments, digestRevenue, digestFailed, digestExpiring, digestLogErrors    string
+}
+
+var emailLocales = map[string]*emailTexts{
+	LocaleRU: {
+		dateLayout:     "02.01.2006 15:04",
+		dayLayout:      "02.01.2006",
+		shortDayLayout: "02.01",
+		subjects: map[string]string{
+			MailVerification:         "Подтверждение регистрации",
+			MailPasswordReset:        "Восстановление пароля",
+			MailSubscriptionGranted:  "Подписка активирована",
+			MailSubscriptionExtended: "Подписка продлена",
+			MailSubscriptionRevoked:  "Подписка отключена",
+			MailSubscriptionExpiring: "Подписка скоро закончится",
+			MailSubscriptionExpired:  "Подписка закончилась",
+			MailInvoice:              "Квитанция об оплате",
+			MailAccountDeletion:      "Удаление учётной записи",
+			MailEmailChange:          "Подтверждение нового адреса",
+			MailNewDocument:          "Новый документ на Edutalks",
+			MailNewsPublished:        "Новая новость на Edutalks",
+			MailArticlePublished:     "Новая статья на Edutalks",
+			MailBatchNew:             "Новые документы на Edutalks",
+			MailBatchUpdated:         "Обновления на Edutalks",
+			MailDataExport:           "Выгрузка данных Edutalks",
+			MailNewLogin:             "Новый вход в аккаунт Edutalks",
+		},
+		phrases: map[string]string{
+			TextButtonFallback:     "Если кнопка не работает — скопируйте ссылку: %s",
+			TextNewDocumentTitle:   "Добавлен новый документ",
+			TextOpenDocument:       "Открыть документ",
+			TextArticleTitle:       "Новая статья",
+			TextReadArticle:        "Читать статью",
+			TextBatchNewTitle:      "Новые документы на сайте",
+			TextBatchUpdatedTitle:  "Обновлённые материалы",
+			TextBatchIntro:         "За последние 10 минут добавлены документы:",
+			TextBatchUpdated:       "Обновлено",
+			TextBatchNoSection:     "Без раздела",
+			TextBatchArticles:      "Статьи",
+			TextBatchNewDocuments:  "Новые документы (%d)",
+			TextDigestSubject:      "Edutalks: новое за %s — %s",
+			TextDigestTitle:        "Новые материалы",
+			TextDigestHello:        "%s, здравствуйте!",
+			TextDigestIntro:        "Что появилось на сайте с прошлого письма:",
+			TextDigestNews:         "Новости",
+			TextDigestArticles:     "Статьи",
+			TextDigestDocuments:    "Документы",
+			TextDigestMore:         "и ещё %d",
+			TextDigestFrequency:    "Частоту писем можно изменить в профиле.",
+			TextAdminDigestSubject: "Edutalks: сводка за %s — %s",
+			TextExportTitle:        "Выгрузка данных",
+			TextExportReady:        "Выгрузка ваших данных на Edutalks готова.",
+			TextExportReadyUser:    "Выгрузка данных пользователя #%d готова.",
+			TextExportButton:       "Скачать архив",
+			TextExportValid:        "Ссылка действует до %s (UTC). Никому её не пересылайте: по ней скачивается архив без входа в аккаунт.",
+			TextSMSPhoneCode:       "Edutalks: код подтверждения телефона %s. Действует %s.",
+			TextSMSResetCode:       "Edutalks: код для сброса пароля %s. Действует %s. Никому его не сообщайте.",
+		},
+		hours:   "%d ч",
+		minutes: "%d мин",
+		years:   "%d год(а)",
+		months:  "%d мес.",
+		days:    "%d дней",
+
+		autoFooter: "Письмо отправлено автоматически. Не отвечайте на него.",
+
+		verifyTitle:   "Подтверждение почты",
+		verifyHello:   "Здравствуйте, %s!",
+		verifyText:    "Для подтверждения вашей электронной почты нажмите кнопку ниже:",
+		verifyButton:  "Подтвердить почту",
+		verifyApp:     "Пользуетесь приложением?",
+		verifyAppLink: "Открыть в приложении",
+		verifyValid:   "Ссылка действительна %s.",
+		verifyIgnore:  "Если вы не регистрировались на сайте, просто проигнорируйте это письмо.",
+
+		resetTitle:  "Восстановление пароля",
+		resetText:   "Вы запросили восстановление пароля для своей учетной записи.",
+		resetHint:   "Чтобы установить новый пароль, перейдите по ссылке ниже:",
+		resetButton: "Сбросить пароль",
+		resetValid:  "Ссылка действительна %s.",
+		resetIgnore: "Если вы не запрашивали восстановление пароля, просто проигнорируйте это письмо.",
+
+		grantedTitle:  "Подписка активирована 🎉",
+		grantedText:   "%s, ваша подписка <b>%s</b> активирована/продлена.",
+		grantedUntil:  "Дата окончания: <b>%s</b>",
+		grantedThanks: "Спасибо, что пользуетесь Edutalks.",
+
+		revokedTitle:   "Подписка отключена",
+		revokedText:    "%s, ваша подписка была отключена: <b>%s</b>.",
+		revokedPrev:    "Ранее дата окончания была: <b>%s</b>",
+		revokedSupport: "Если вы не ожидали это письмо, свяжитесь с поддержкой.",
+
+		expiringTitle:  "Подписка скоро закончится",
+		expiringText:   "%s, ваша подписка действует до <b>%s</b>.",
+		expiringHint:   "Продлите её заранее, чтобы не потерять доступ к материалам.",
+		expiringButton: "Продлить подписку",
+
+		expiredTitle:  "Подписка закончилась",
+		expiredText:   "%s, срок вашей подписки истёк <b>%s</b>.",
+		expiredHint:   "Чтобы снова получить доступ к материалам, оформите подписку.",
+		expiredButton: "Оформить подписку",
+
+		invoiceTitle: "Спасибо за оплату",
+		invoiceText:  "%s, квитанция <b>№ %s</b> на сумму <b>%s</b> — во вложении к письму.",
+		invoiceHint:  "Её можно скачать и в личном кабинете, в истории платежей.",
+
+		deletionTitle:  "Учётная запись будет удалена",
+		deletionText:   "%s, мы получили запрос на удаление вашей учётной записи. Она и все её данные будут удалены <b>%s</b>.",
+		deletionHint:   "До этого момента удаление можно отменить — вход в аккаунт до отмены недоступен.",
+		deletionButton: "Отменить удаление",
+		deletionIgnore: "Если вы не запрашивали удаление, отмените его и смените пароль.",
+
+		changeTitle:  "Смена адреса почты",
+		changeText:   "%s, вы указали <b>%s</b> как новый адрес для учётной записи Edutalks.",
+		changeHint:   "Чтобы подтвердить адрес, нажмите кнопку ниже. До подтверждения письма приходят на прежний адрес.",
+		changeButton: "Подтвердить адрес",
+		changeValid:  "Ссылка действительна %s.",
+		changeIgnore: "Если вы не меняли адрес, просто проигнорируйте это письмо.",
+
+		loginTitle:    "Новый вход в аккаунт",
+		loginText:     "%s, в вашу учётную запись Edutalks вошли с устройства, с которого раньше не входили.",
+		loginTime:     "Время (МСК)",
+		loginIP:       "IP-адрес",
+		loginLocation: "Местоположение (примерно)",
+		loginDevice:   "Устройство",
+		loginHint:     "Если это были вы, ничего делать не нужно. Если нет — сразу смените пароль: после сброса завершите чужие сессии в профиле.",
+		loginButton:   "Сбросить пароль",
+		loginFooter:   "Уведомления о входе с новых устройств можно отключить в настройках профиля.",
+
+		newsButton: "Читать новость",
+		subscribedFooter: `Вы получили это письмо, потому что подписаны на уведомления Edutalks.<br>
+                  <i>Если вы не хотите получать такие письма — отпишитесь в настройках профиля.</i>`,
+
+		digestHeading:     "Сводка за неделю",
+		digestDownloads:   "Топ скачиваний",
+		digestNoDownloads: "Скачиваний за период не было.",
+		digestErrors:      "Ошибки в логах",
+		digestNoErrors:    "Ошибок в логах нет.",
+		digestDocument:    "Документ #%d",
+		digestNewUsers:    "Новые пользователи",
+		digestPayments:    "Успешные платежи",
+		digestRevenue:     "Выручка",
+		digestFailed:      "Отменённые / неуспешные платежи",
+		digestExpiring:    "Подписки, истекающие в ближайшие 7 дней",
+		digestLogErrors:   "Ошибок в логах",
+	},
+	LocaleEN: {
+		dateLayout:     "Jan 2, 2006 15:04",
+		dayLayout:      "Jan 2, 2006",
+		shortDayLayout: "Jan 2",
+		subjects: map[string]string{
+			MailVerification:         "Confirm your registration",
+			MailPasswordReset:        "Password reset",
+			MailSubscriptionGranted:  "Subscription activated",
+			MailSubscriptionExtended: "Subscription extended",
+			MailSubscriptionRevoked:  "Subscription cancelled",
+			MailSubscriptionExpiring: "Your subscription is about to expire",
+			MailSubscriptionExpired:  "Your subscription has expired",
+			MailInvoice:              "Payment receipt",
+			MailAccountDeletion:      "Account deletion",
+			MailEmailChange:          "Confirm your new email address",
+			MailNewDocument:          "New document on Edutalks",
+			MailNewsPublished:        "New post on Edutalks",
+			MailArticlePublished:     "New article on Edutalks",
+			MailBatchNew:             "New documents on Edutalks",
+			MailBatchUpdated:         "Updates on Edutalks",
+			MailDataExport:           "Your Edutalks data export",
+			MailNewLogin:             "New sign-in to your Edutalks account",
+		},
+		phrases: map[string]string{
+			TextButtonFallback:     "If the button does not work, copy the link: %s",
+			TextNewDocumentTitle:   "A new document has been added",
+			TextOpenDocument:       "Open document",
+			TextArticleTitle:       "New article",
+			TextReadArticle:        "Read the article",
+			TextBatchNewTitle:      "New documents on the site",
+			TextBatchUpdatedTitle:  "Updated materials",
+			TextBatchIntro:         "Documents added in the last 10 minutes:",
+			TextBatchUpdated:       "Updated",
+			TextBatchNoSection:     "No section",
+			TextBatchArticles:      "Articles",
+			TextBatchNewDocuments:  "New documents (%d)",
+			TextDigestSubject:      "Edutalks: what's new for %s — %s",
+			TextDigestTitle:        "New materials",
+			TextDigestHello:        "Hello, %s!",
+			TextDigestIntro:        "Here is what has appeared on the site since our last email:",
+			TextDigestNews:         "News",
+			TextDigestArticles:     "Articles",
+			TextDigestDocuments:    "Documents",
+			TextDigestMore:         "and %d more",
+			TextDigestFrequency:    "You can change how often we email you in your profile.",
+			TextAdminDigestSubject: "Edutalks: summary for %s — %s",
+			TextExportTitle:        "Data export",
+			TextExportReady:        "Your Edutalks data export is ready.",
+			TextExportReadyUser:    "The data export for user #%d is ready.",
+			TextExportButton:       "Download archive",
+			TextExportValid:        "The link is valid until %s (UTC). Do not share it: anyone with the link can download the archive without signing in.",
+			TextSMSPhoneCode:       "Edutalks: your phone confirmation code is %s. Valid for %s.",
+			TextSMSResetCode:       "Edutalks: your password reset code is %s. Valid for %s. Do not share it.",
+		},
+		hours:   "%d h",
+		minutes: "%d min",
+		years:   "%d year(s)",
+		months:  "%d month(s)",
+		days:    "%d days",
+
+		autoFooter: "This is an automated message. Please do not reply.",
+
+		verifyTitle:   "Email confirmation",
+		verifyHello:   "Hello, %s!",
+		verifyText:    "To confirm your email address, click the button below:",
+		verifyButton:  "Confirm email",
+		verifyApp:     "Using the app?",
+		verifyAppLink: "Open in the app",
+		verifyValid:   "The link is valid for %s.",
+		verifyIgnore:  "If you did not sign up on the site, just ignore this email.",
+
+		resetTitle:  "Password reset",
+		resetText:   "You requested a password reset for your account.",
+		resetHint:   "To set a new password, follow the link below:",
+		resetButton: "Reset password",
+		resetValid:  "The link is valid for %s.",
+		resetIgnore: "If you did not request a password reset, just ignore this email.",
+
+		grantedTitle:  "Subscription activated 🎉",
+		grantedText:   "%s, your <b>%s</b> subscription has been activated/extended.",
+		grantedUntil:  "Expires on: <b>%s</b>",
+		grantedThanks: "Thank you for using Edutalks.",
+
+		revokedTitle:   "Subscription cancelled",
+		revokedText:    "%s, your subscription was cancelled on <b>%s</b>.",
+		revokedPrev:    "It was previously valid until: <b>%s</b>",
+		revokedSupport: "If you did not expect this email, please contact support.",
+
+		expiringTitle:  "Your subscription is about to expire",
+		expiringText:   "%s, your subscription is valid until <b>%s</b>.",
+		expiringHint:   "Renew it in advance so you don't lose access to the materials.",
+		expiringButton: "Renew subscription",
+
+		expiredTitle:  "Your subscription has expired",
+		expiredText:   "%s, your subscription expired on <b>%s</b>.",
+		expiredHint:   "Subscribe again to regain access to the materials.",
+		expiredButton: "Subscribe",
+
+		invoiceTitle: "Thank you for your payment",
+		invoiceText:  "%s, receipt <b>No. %s</b> for <b>%s</b> is attached to this email.",
+		invoiceHint:  "You can also download it from your payment history in your account.",
+
+		deletionTitle:  "Your account will be deleted",
+		deletionText:   "%s, we received a request to delete your account. It will be deleted along with all its data on <b>%s</b>.",
+		deletionHint:   "Until then you can cancel the deletion; signing in is unavailable until you do.",
+		deletionButton: "Cancel deletion",
+		deletionIgnore: "If you did not request this, cancel the deletion and change your password.",
+
+		changeTitle:  "Email address change",
+		changeText:   "%s, you entered <b>%s</b> as the new email address for your Edutalks account.",
+		changeHint:   "Click the button below to confirm it. Until then, emails are sent to your current address.",
+		changeButton: "Confirm address",
+		changeValid:  "The link is valid for %s.",
+		changeIgnore: "If you did not change your address, just ignore this email.",
+
+		loginTitle:    "New sign-in to your account",
+		loginText:     "%s, your Edutalks account was signed in to from a device that has not been used before.",
+		loginTime:     "Time (Moscow)",
+		loginIP:       "IP address",
+		loginLocation: "Location (approximate)",
+		loginDevice:   "Device",
+		loginHint:     "If this was you, no action is needed. If not, reset your password right away and then end unknown sessions in your profile.",
+		loginButton:   "Reset password",
+		loginFooter:   "You can turn off new sign-in alerts in your profile settings.",
+
+		newsButton: "Read more",
+		subscribedFooter: `You received this email because you are subscribed to Edutalks notifications.<br>
+                  <i>If you no longer want these emails, unsubscribe in your profile settings.</i>`,
+
+		digestHeading:     "Weekly summary",
+		digestDownloads:   "Top downloads",
+		digestNoDownloads: "There were no downloads in this period.",
+		digestErrors:      "Log errors",
+		digestNoErrors:    "No errors in the logs.",
+		digestDocument:    "Document #%d",
+		digestNewUsers:    "New users",
+		digestPayments:    "Successful payments",
+		digestRevenue:     "Revenue",
+		digestFailed:      "Cancelled / failed payments",
+		digestExpiring:    "Subscriptions expiring in the next 7 days",
+		digestLogErrors:   "Errors in the logs",
+	},
+}
+
+// NormalizeLocale — «en-US», « EN » → «en»; неподдерживаемые языки → DefaultLocale.
+func NormalizeLocale(locale string) string {
+	if l := primaryLanguage(locale); SupportedLocale(l) {
+		return l
+	}
+	return DefaultLocale
+}
+
+// SupportedLocale — есть ли тексты писем для языка (точное совпадение, для валидации профиля).
+func SupportedLocale(locale string) bool {
+	_, ok := emailLocales[locale]
+	return ok
+}
+
+// LocaleFromAcceptLanguage — первый поддерживаемый язык из заголовка Accept-Language (веса не учитываются).
+func LocaleFromAcceptLanguage(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		tag, _, _ := strings.Cut(part, ";")
+		if l := primaryLanguage(tag); SupportedLocale(l) {
+			return l
+		}
+	}
+	return DefaultLocale
+}
+
+func primaryLanguage(tag string) string {
+	l := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(l, "-_"); i > 0 {
+		l = l[:i]
+	}
+	return l
+}
+
+func emailText(locale string) *emailTexts {
+	return emailLocales[NormalizeLocale(locale)]
+}
+
+// EmailSubject — тема письма вида mail (Mail*) на языке пользователя.
+func EmailSubject(locale, mail string) string {
+	return emailText(locale).subjects[mail]
+}
+
+// EmailPhrase — фраза письма (Text*) на языке пользователя с подстановкой args; нет перевода —
+// русский текст, нет и его — сам ключ.
+func EmailPhrase(locale, key string, args ...any) string {
+	text, ok := emailText(locale).phrases[key]
+	if !ok {
+		if text, ok = emailLocales[DefaultLocale].phrases[key]; !ok {
+			text = key
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// FormatEmailDay — день без времени («02.01.2006» / «Jan 2, 2006»); withYear=false — «02.01» / «Jan 2».
+func FormatEmailDay(locale string, t time.Time, withYear bool) string {
+	if withYear {
+		return t.Format(emailText(locale).dayLayout)
+	}
+	return t.Format(emailText(locale).shortDayLayout)
+}
+
+// FormatEmailDate — дата для текста письма в формате языка.
+func FormatEmailDate(locale string, t time.Time) string {
+	return t.Format(emailText(locale).dateLayout)
+}
+
+// FormatTTL — срок действия ссылки («24 ч», «30 min»).
+func FormatTTL(locale string, d time.Duration) string {
+	t := emailText(locale)
+	if d >= time.Hour {
+		return fmt.Sprintf(t.hours, int(d.Hours()))
+	}
+	return fmt.Sprintf(t.minutes, int(d.Minutes()))
+}
+
+// FormatPlanDuration — срок подписки для письма: годы, месяцы (по 30 дней) или дни.
+func FormatPlanDuration(locale string, d time.Duration) string {
+	t := emailText(locale)
+	days := int(d.Hours() / 24)
+	switch {
+	case days%365 == 0 && days >= 365:
+		return fmt.Sprintf(t.years, days/365)
+	case days%30 == 0 && days >= 30:
+		return fmt.Sprintf(t.months, days/30)
+	default:
+		return fmt.Sprintf(t.days, days)
+	}
+}
+
+// FormatAmount — сумма с валютой: «1 250,00 ₽» / «1,250.00 RUB».
+func FormatAmount(locale string, amount float64, currency string) string {
+	s := fmt.Sprintf("%.2f", amount)
+	intPart, frac, _ := strings.Cut(s, ".")
+	neg := strings.HasPrefix(intPart, "-")
+	intPart = strings.TrimPrefix(intPart, "-")
+
+	thousands, point := ",", "."
+	if NormalizeLocale(locale) == LocaleRU {
+		thousands, point = "\u00a0", "," // неразрывный пробел: сумма не переносится
+	}
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(thousands)
+		}
+		b.WriteRune(r)
+	}
+	out := b.String() + point + frac
+	if neg {
+		out = "-" + out
+	}
+
+	if currency == "" || currency == "RUB" && NormalizeLocale(locale) == LocaleRU {
+		return out + "\u00a0₽"
+	}
+	return out + " " + currency
+}
@@ -0,0 +1,283 @@
+package helpers
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"edutalks/internal/logger"
+	"edutalks/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// Шаблоны писем (html/template). Встроенные лежат в email_templates/, их можно
+// переопределить файлом <EMAIL_TEMPLATES_DIR>/<имя>.html — вручную или через админку.
+// Каждый шаблон задаёт блок "content", общая обёртка — шаблон "layout".
+
+//go:embed email_templates/*.html
+var embeddedEmailTemplates embed.FS
+
+const TemplateLayout = "layout"
+
+var (
+	ErrUnknownEmailTemplate   = errors.New("неизвестный шаблон письма")
+	ErrEmailTemplatesReadOnly = errors.New("каталог шаблонов писем не задан (EMAIL_TEMPLATES_DIR)")
+)
+
+// emailTemplateSamples — шаблоны и данные для предпросмотра (и проверки при сохранении).
+var emailTemplateSamples = map[string]func(locale string) map[string]any{
+	TemplateLayout: func(locale string) map[string]any {
+		return simpleData(locale, "Заголовок письма", "<p>Текст письма.</p>")
+	},
+	"news": func(locale string) map[string]any {
+		return newsData(locale, EmailSubject(locale, MailNewsPublished), "", "https://edutalks.ru/news/1")
+	},
+	"simple": func(locale string) map[string]any {
+		return simpleData(locale, EmailPhrase(locale, TextNewDocumentTitle), "<p>Приказ №1 «Об утверждении положения»</p>")
+	},
+	MailVerification: func(locale string) map[string]any {
+		return verificationData(locale, "Иван Петров", "https://edutalks.ru/verify-email?token=sample",
+			"edutalks://verify?token=sample", FormatTTL(locale, 24*time.Hour))
+	},
+	MailPasswordReset: func(locale string) map[string]any {
+		return passwordResetData(locale, "https://edutalks.ru/reset-password?token=sample", FormatTTL(locale, 30*time.Minute))
+	},
+	MailSubscriptionGranted: func(locale string) map[string]any {
+		return subscriptionGrantedData(locale, "Иван Петров", FormatPlanDuration(locale, 30*24*time.Hour), time.Now().AddDate(0, 1, 0))
+	},
+	MailSubscriptionRevoked: func(locale string) map[string]any {
+		prev := time.Now().AddDate(0, 0, 10)
+		return subscriptionRevokedData(locale, "Иван Петров", time.Now(), &prev)
+	},
+	MailSubscriptionExpiring: func(locale string) map[string]any {
+		return subscriptionReminderData(locale, true, "Иван Петров", time.Now().AddDate(0, 0, 3), "https://edutalks.ru/subscription")
+	},
+	MailSubscriptionExpired: func(locale string) map[string]any {
+		return subscriptionReminderData(locale, false, "Иван Петров", time.Now(), "https://edutalks.ru/subscription")
+	},
+	MailInvoice: func(locale string) map[string]any {
+		return invoiceData(locale, "Иван Петров", "ET2026-000001", FormatAmount(locale, 1250, "RUB"))
+	},
+	MailAccountDeletion: func(locale string) map[string]any {
+		return accountDeletionData(locale, "Иван Петров", time.Now().AddDate(0, 0, 30), "https://edutalks.ru/api/account/deletion/cancel?token=sample")
+	},
+	MailEmailChange: func(locale string) map[string]any {
+		return emailChangeData(locale, "Иван Петров", "new@example.com", "https://edutalks.ru/api/profile/email/confirm?token=sample",
+			FormatTTL(locale, 24*time.Hour))
+	},
+	MailNewLogin: func(locale string) map[string]any {
+		return newLoginData(locale, "Иван Петров", time.Now(), "203.0.113.7", "Москва, Россия",
+			"Chrome 128, Windows", "https://edutalks.ru/forgot-password")
+	},
+	"admin_digest": func(locale string) map[string]any {
+		now := time.Now()
+		return adminDigestData(locale, &models.AdminDigest{
+			From: now.AddDate(0, 0, -7), To: now,
+			NewUsers: 12, PaymentsSucceeded: 5, Revenue: 6250,
+			TopDownloads: []models.DocumentDownloadStat{{DocumentID: 1, Title: "Приказ №1", Downloads: 42}},
+			ErrorsTotal:  3,
+			TopErrors:    []models.DigestLogError{{Message: "Ошибка отправки письма", Count: 3}},
+		})
+	},
+}
+
+var (
+	emailTemplatesMu    sync.RWMutex
+	emailTemplatesDir   string
+	emailTemplatesCache = map[string]*template.Template{}
+)
+
+// ConfigureEmailTemplates — каталог переопределений шаблонов (пусто — только встроенные).
+func ConfigureEmailTemplates(dir string) {
+	emailTemplatesMu.Lock()
+	emailTemplatesDir = dir
+	emailTemplatesCache = map[string]*template.Template{}
+	emailTemplatesMu.Unlock()
+	logger.Log.Info("Шаблоны писем: каталог переопределений", zap.String("dir", dir))
+}
+
+// EmailTemplates — список шаблонов и признак переопределения.
+func EmailTemplates() []models.EmailTemplateInfo {
+	names := make([]string, 0, len(emailTemplateSamples))
+	for name := range emailTemplateSamples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]models.EmailTemplateInfo, 0, len(names))
+	for _, name := range names {
+		_, overridden, _ := EmailTemplateSource(name)
+		out = append(out, models.EmailTemplateInfo{Name: name, Overridden: overridden})
+	}
+	return out
+}
+
+// EmailTemplateSource — текущий исходник шаблона и взят ли он из каталога переопределений.
+func EmailTemplateSource(name string) (string, bool, error) {
+	if _, ok := emailTemplateSamples[name]; !ok {
+		return "", false, ErrUnknownEmailTemplate
+	}
+	emailTemplatesMu.RLock()
+	dir := emailTemplatesDir
+	emailTemplatesMu.RUnlock()
+
+	if dir != "" {
+		if b, err := os.ReadFile(filepath.Join(dir, name+".html")); err == nil {
+			return string(b), true, nil
+		}
+	}
+	b, err := embeddedEmailTemplates.ReadFile("email_templates/" + name + ".html")
+	return string(b), false, err
+}
+
+// SaveEmailTemplate — проверяет шаблон на примере данных и сохраняет переопределение.
+func SaveEmailTemplate(name, source string) error {
+	if _, _, err := PreviewEmailTemplate(name, source, DefaultLocale); err != nil {
+		return err
+	}
+	emailTemplatesMu.Lock()
+	defer emailTemplatesMu.Unlock()
+	if emailTemplatesDir == "" {
+		return ErrEmailTemplatesReadOnly
+	}
+	if err := os.MkdirAll(emailTemplatesDir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(emailTemplatesDir, name+".html"), []byte(source), 0o644); err != nil {
+		return err
+	}
+	emailTemplatesCache = map[string]*template.Template{}
+	return nil
+}
+
+// ResetEmailTemplate — удаляет переопределение, дальше используется встроенный шаблон.
+func ResetEmailTemplate(name string) error {
+	if _, ok := emailTemplateSamples[name]; !ok {
+		return ErrUnknownEmailTemplate
+	}
+	emailTemplatesMu.Lock()
+	defer emailTemplatesMu.Unlock()
+	if emailTemplatesDir == "" {
+		return nil
+	}
+	err := os.Remove(filepath.Join(emailTemplatesDir, name+".html"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	emailTemplatesCache = map[string]*template.Template{}
+	return nil
+}
+
+// PreviewEmailTemplate — HTML и текстовая версия письма на примере данных.
+// source — черновик шаблона (пусто — текущий).
+func PreviewEmailTemplate(name, source, locale string) (string, string, error) {
+	sample, ok := emailTemplateSamples[name]
+	if !ok {
+		return "", "", ErrUnknownEmailTemplate
+	}
+	layout, content := source, ""
+	var err error
+	if name == TemplateLayout {
+		if layout == "" {
+			layout, _, err = EmailTemplateSource(TemplateLayout)
+		}
+		if err == nil {
+			content, _, err = EmailTemplateSource("simple")
+		}
+	} else {
+		content = source
+		if content == "" {
+			content, _, err = EmailTemplateSource(name)
+		}
+		if err == nil {
+			layout, _, err = EmailTemplateSource(TemplateLayout)
+		}
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	t, err := parseEmailTemplate(layout, content)
+	if err != nil {
+		return "", "", err
+	}
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, TemplateLayout, sample(locale)); err != nil {
+		return "", "", err
+	}
+	return buf.String(), HTMLToText(buf.String()), nil
+}
+
+func parseEmailTemplate(layout, content string) (*template.Template, error) {
+	t, err := template.New(TemplateLayout).Parse(layout)
+	if err != nil {
+		return nil, fmt.Errorf("layout: %w", err)
+	}
+	if _, err := t.New("content").Parse(content); err != nil {
+		return nil, fmt.Errorf("content: %w", err)
+	}
+	return t, nil
+}
+
+// loadEmailTemplate — layout + шаблон name (из кэша); embedded — без переопределений.
+func loadEmailTemplate(name string, embedded bool) (*template.Template, error) {
+	key := name
+	if embedded {
+		key = "embedded:" + name
+	}
+	emailTemplatesMu.RLock()
+	t, ok := emailTemplatesCache[key]
+	emailTemplatesMu.RUnlock()
+	if ok {
+		return t, nil
+	}
+
+	source := func(n string) (string, error) {
+		if embedded {
+			b, err := embeddedEmailTemplates.ReadFile("email_templates/" + n + ".html")
+			return string(b), err
+		}
+		s, _, err := EmailTemplateSource(n)
+		return s, err
+	}
+	layout, err := source(TemplateLayout)
+	if err != nil {
+		return nil, err
+	}
+	content, err := source(name)
+	if err != nil {
+		return nil, err
+	}
+	if t, err = parseEmailTemplate(layout, content); err != nil {
+		return nil, err
+	}
+
+	emailTemplatesMu.Lock()
+	emailTemplatesCache[key] = t
+	emailTemplatesMu.Unlock()
+	return t, nil
+}
+
+// renderEmail — письмо по шаблону; сломанное переопределение не должно останавливать
+// рассылку — логируем и отдаём встроенный шаблон.
+func renderEmail(name string, data map[string]any) string {
+	for _, embedded := range []bool{false, true} {
+		t, err := loadEmailTemplate(name, embedded)
+		if err == nil {
+			var buf bytes.Buffer
+			if err = t.ExecuteTemplate(&buf, TemplateLayout, data); err == nil {
+				return buf.String()
+			}
+		}
+		logger.Log.Error("Шаблоны писем: ошибка шаблона", zap.String("template", name), zap.Bool("embedded", embedded), zap.Error(err))
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package helpers
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// HTMLToText — текстовая часть письма (text/plain) из HTML: абзацы и строки таблиц
+// с новой строки, пункты списков с «- », у ссылок — адрес в скобках.
+func HTMLToText(s string) string {
+	var (
+		b     strings.Builder
+		href  string
+		label strings.Builder
+		skip  int
+		space bool // пробел перед следующим текстом (между словами и тегами)
+	)
+	z := html.NewTokenizer(strings.NewReader(s))
+	write := func(text string) {
+		if href != "" {
+			label.WriteString(text)
+			return
+		}
+		b.WriteString(text)
+	}
+
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			lines := strings.Split(b.String(), "\n")
+			for i, l := range lines {
+				lines[i] = strings.TrimSpace(l)
+			}
+			return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			raw := string(z.Text())
+			text := strings.Join(strings.Fields(raw), " ")
+			if text == "" {
+				space = space || raw != ""
+				continue
+			}
+			if space || unicode.IsSpace(rune(raw[0])) {
+				switch out := b.String(); {
+				case href != "" && label.Len() == 0:
+					// пробел перед ссылкой — в тексте, а не в её подписи
+					if out != "" && !strings.HasSuffix(out, " ") && !strings.HasSuffix(out, "\n") {
+						b.WriteString(" ")
+					}
+				case href != "" || (out != "" && !strings.HasSuffix(out, " ") && !strings.HasSuffix(out, "\n")):
+					text = " " + text
+				}
+			}
+			space = unicode.IsSpace(rune(raw[len(raw)-1]))
+			write(text)
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.Style, atom.Script, atom.Head, atom.Title:
+				skip++
+			case atom.Br:
+				b.WriteString("\n")
+			case atom.Li:
+				b.WriteString("\n- ")
+			case atom.P, atom.Div, atom.Tr, atom.H1, atom.H2, atom.H3, atom.H4, atom.Table, atom.Ul, atom.Ol, atom.Hr:
+				b.WriteString("\n")
+			case atom.Td, atom.Th:
+				b.WriteString(" ")
+			case atom.A:
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = z.TagAttr()
+					if string(key) == "href" {
+						href = string(val)
+					}
+				}
+				label.Reset()
+			}
+
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.Style, atom.Script, atom.Head, atom.Title:
+				if skip > 0 {
+					skip--
+				}
+			case atom.P, atom.Div, atom.Tr, atom.H1, atom.H2, atom.H3, atom.H4, atom.Table, atom.Ul, atom.Ol:
+				b.WriteString("\n")
+			case atom.A:
+				text := strings.TrimSpace(label.String())
+				switch {
+				case href == "" || strings.HasPrefix(href, "#"):
+					b.WriteString(text)
+				case text == "" || text == href:
+					b.WriteString(href)
+				default:
+					b.WriteString(text + " (" + href + ")")
+				}
+				href = ""
+			}
+		}
+	}
+}
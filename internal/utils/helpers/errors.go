@@ -0,0 +1,102 @@
+package helpers
+
+import "net/http"
+
+// ErrorCode — машиночитаемый код ошибки API (поле error.code). Коды стабильны: фронт и мобильные
+// клиенты ветвятся по ним, а не по тексту сообщения. Новые коды — только добавлять.
+type ErrorCode string
+
+// Общие коды; по умолчанию код выводится из HTTP-статуса (см. CodeForStatus).
+const (
+	CodeBadRequest         ErrorCode = "BAD_REQUEST"
+	CodeInvalidJSON        ErrorCode = "INVALID_JSON"
+	CodeInvalidID          ErrorCode = "INVALID_ID"
+	CodeValidationFailed   ErrorCode = "VALIDATION_FAILED"
+	CodeUnauthorized       ErrorCode = "UNAUTHORIZED"
+	CodeForbidden          ErrorCode = "FORBIDDEN"
+	CodeNotFound           ErrorCode = "NOT_FOUND"
+	CodeMethodNotAllowed   ErrorCode = "METHOD_NOT_ALLOWED"
+	CodeConflict           ErrorCode = "CONFLICT"
+	CodeGone               ErrorCode = "GONE"
+	CodePayloadTooLarge    ErrorCode = "PAYLOAD_TOO_LARGE"
+	CodeRateLimited        ErrorCode = "RATE_LIMITED"
+	CodeInternal           ErrorCode = "INTERNAL_ERROR"
+	CodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
+)
+
+// Авторизация и аккаунт.
+const (
+	CodeAuthInvalidCredentials ErrorCode = "AUTH_INVALID_CREDENTIALS"
+	CodeAuthTokenMissing       ErrorCode = "AUTH_TOKEN_MISSING"
+	CodeAuthTokenInvalid       ErrorCode = "AUTH_TOKEN_INVALID"
+	CodeAuthDeletionPending    ErrorCode = "AUTH_ACCOUNT_DELETION_PENDING"
+	CodeAuthUsernameTaken      ErrorCode = "AUTH_USERNAME_TAKEN"
+	CodeAuthEmailTaken         ErrorCode = "AUTH_EMAIL_TAKEN"
+	CodeAuthPasswordInvalid    ErrorCode = "AUTH_PASSWORD_INVALID" // неверный текущий пароль
+	CodeAuthLinkInvalid        ErrorCode = "AUTH_LINK_INVALID"     // ссылка из письма неверна или устарела
+	CodeAuthImpersonated       ErrorCode = "AUTH_IMPERSONATED"     // действие недоступно с токеном поддержки
+
+	CodeUserNotFound         ErrorCode = "USER_NOT_FOUND"
+	CodeLocaleUnsupported    ErrorCode = "LOCALE_UNSUPPORTED"
+	CodeEmailUnchanged       ErrorCode = "EMAIL_UNCHANGED"
+	CodeEmailChangeRequired  ErrorCode = "EMAIL_CHANGE_CONFIRMATION_REQUIRED"
+	CodeSessionNotFound      ErrorCode = "SESSION_NOT_FOUND"
+	CodeOAuthProvider        ErrorCode = "OAUTH_PROVIDER_UNKNOWN"
+	CodeOAuthNotLinked       ErrorCode = "OAUTH_NOT_LINKED"
+	CodePhoneMissing         ErrorCode = "PHONE_MISSING"
+	CodePhoneAlreadyVerified ErrorCode = "PHONE_ALREADY_VERIFIED"
+	CodePhoneTaken           ErrorCode = "PHONE_TAKEN"                  // номер подтверждён другим пользователем
+	CodePhoneCodeInvalid     ErrorCode = "PHONE_CODE_INVALID"           // код из SMS неверен или устарел
+	CodePhoneCodeAttempts    ErrorCode = "PHONE_CODE_ATTEMPTS_EXCEEDED" // код больше не принимается, нужен новый
+)
+
+// Контент и платежи.
+const (
+	CodeDocNotFound             ErrorCode = "DOC_NOT_FOUND"
+	CodeDocNotPublic            ErrorCode = "DOC_NOT_PUBLIC"
+	CodeDocSubscriptionRequired ErrorCode = "DOC_SUBSCRIPTION_REQUIRED"
+	CodeNewsNotFound            ErrorCode = "NEWS_NOT_FOUND"
+	CodeSectionNotEmpty         ErrorCode = "SECTION_NOT_EMPTY" // в разделе есть документы или подразделы
+	CodeMediaInUse              ErrorCode = "MEDIA_IN_USE"      // изображение используется в статьях
+	CodeFileInfected            ErrorCode = "FILE_INFECTED"     // антивирус нашёл угрозу в файле
+	CodeFileTypeNotAllowed      ErrorCode = "FILE_TYPE_NOT_ALLOWED"
+	CodeFileContentMismatch     ErrorCode = "FILE_CONTENT_MISMATCH" // сигнатура не совпадает с расширением
+	CodeFileTooLarge            ErrorCode = "FILE_TOO_LARGE"
+	CodeDownloadQuotaExceeded   ErrorCode = "DOWNLOAD_QUOTA_EXCEEDED" // дневной лимит скачиваний исчерпан
+	CodeContentNotOwner         ErrorCode = "CONTENT_NOT_OWNER"       // статья или документ другого автора
+	CodeContentPublishDenied    ErrorCode = "CONTENT_PUBLISH_DENIED"  // публикует только администратор
+	CodePaymentNotFound         ErrorCode = "PAYMENT_NOT_FOUND"
+	CodePaymentInvalidPlan      ErrorCode = "PAYMENT_INVALID_PLAN"
+)
+
+// CodeForStatus — код по умолчанию для HTTP-статуса.
+func CodeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusGone:
+		return CodeGone
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	}
+	if status >= 500 {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
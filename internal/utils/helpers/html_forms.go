@@ -2,98 +2,79 @@ package helpers
 
 import (
 	"fmt"
+	"html"
+	"html/template"
 	"time"
+
+	"edutalks/internal/models"
 )
 
-func BuildNewsHTML(title, content, url string) string {
-	return fmt.Sprintf(`
-<html>
-  <body style="font-family:Arial,sans-serif;background:#f7f7f7;padding:0;margin:0;">
-    <table width="100%%" bgcolor="#f7f7f7" cellpadding="0" cellspacing="0" style="padding:30px 0;">
-      <tr>
-        <td align="center">
-          <table width="600" bgcolor="#fff" cellpadding="24" cellspacing="0" style="border-radius:10px;box-shadow:0 2px 8px #eee;">
-            <tr>
-              <td>
-                <h2 style="color:#2d74da;margin-top:0;">%s</h2>
-                <p style="font-size:16px;color:#333;">%s</p>
-                <p>
-                  <a href="%s" style="display:inline-block;padding:12px 24px;background:#2d74da;color:#fff;text-decoration:none;border-radius:5px;font-weight:bold;margin-top:16px;">
-                    Читать новость
-                  </a>
-                </p>
-                <hr style="border:none;border-top:1px solid #eee;margin:32px 0 12px 0;">
-                <p style="font-size:12px;color:#999;margin:0;">
-                  Вы получили это письмо, потому что подписаны на уведомления Edutalks.<br>
-                  <i>Если вы не хотите получать такие письма — отпишитесь в настройках профиля.</i>
-                </p>
-              </td>
-            </tr>
-          </table>
-        </td>
-      </tr>
-    </table>
-  </body>
-</html>
-`, title, content, url)
+// Письма собираются из шаблонов (см. email_templates.go): здесь — только данные для них.
+// Строки с разметкой из emailTexts («<b>%s</b>») передаются как template.HTML,
+// пользовательские значения в них экранируются.
+
+func markup(format string, args ...any) template.HTML {
+	for i, a := range args {
+		if s, ok := a.(string); ok {
+			args[i] = html.EscapeString(s)
+		}
+	}
+	return template.HTML(fmt.Sprintf(format, args...))
 }
 
-func BuildSimpleHTML(title, body string) string {
-	return fmt.Sprintf(`
-<html>
-  <body style="font-family:Arial,sans-serif; background:#f9f9f9;">
-    <table width="100%%" cellpadding="0" cellspacing="0" bgcolor="#f9f9f9">
-      <tr>
-        <td align="center" style="padding:32px 0;">
-          <table width="500" bgcolor="#fff" cellpadding="24" cellspacing="0" style="border-radius:8px; box-shadow:0 1px 6px #eee;">
-            <tr>
-              <td>
-                <h2 style="color:#2d74da; margin-top:0;">%s</h2>
-                <div style="font-size:16px; color:#222;">%s</div>
-                <hr style="margin:32px 0 16px 0; border:0; border-top:1px solid #eee;">
-                <div style="font-size:12px; color:#999;">Письмо сгенерировано автоматически. Не отвечайте на него.</div>
-              </td>
-            </tr>
-          </table>
-        </td>
-      </tr>
-    </table>
-  </body>
-</html>
-`, title, body)
+func newsData(locale, title, content, url string) map[string]any {
+	t := emailText(locale)
+	return map[string]any{
+		"Lang": NormalizeLocale(locale), "Width": 600, "Footer": template.HTML(t.subscribedFooter),
+		"Title":   title,
+		"Content": template.HTML(content),
+		"URL":     url,
+		"Button":  t.newsButton,
+	}
 }
 
-func BuildVerificationHTML(name, link string) string {
-	return fmt.Sprintf(`
-<html>
-  <body style="font-family:Arial,sans-serif; background:#f9f9f9;">
-    <table width="100%%" cellpadding="0" cellspacing="0" bgcolor="#f9f9f9">
-      <tr>
-        <td align="center" style="padding:32px 0;">
-          <table width="500" bgcolor="#fff" cellpadding="24" cellspacing="0" style="border-radius:8px; box-shadow:0 1px 6px #eee;">
-            <tr>
-              <td>
-                <h2 style="color:#2d74da; margin-top:0;">Подтверждение почты</h2>
-                <div style="font-size:16px; color:#222;">Здравствуйте, %s!</div>
-                <p style="margin:24px 0;">
-                  Для подтверждения вашей электронной почты нажмите кнопку ниже:
-                </p>
-                <p>
-                  <a href="%s" style="display:inline-block;padding:12px 24px;background:#2d74da;color:#fff;text-decoration:none;border-radius:5px;font-weight:bold;">
-                    Подтвердить почту
-                  </a>
-                </p>
-                <hr style="margin:32px 0 16px 0; border:0; border-top:1px solid #eee;">
-                <div style="font-size:12px; color:#999;">Если вы не регистрировались на сайте, просто проигнорируйте это письмо.</div>
-              </td>
-            </tr>
-          </table>
-        </td>
-      </tr>
-    </table>
-  </body>
-</html>
-`, name, link)
+// BuildNewsHTML — письмо о новости; content — HTML (может быть пустым).
+func BuildNewsHTML(locale, title, content, url string) string {
+	return renderEmail("news", newsData(locale, title, content, url))
+}
+
+func simpleData(locale, title, body string) map[string]any {
+	return map[string]any{
+		"Lang": NormalizeLocale(locale), "Width": 500, "Footer": emailText(locale).autoFooter,
+		"Title": title,
+		"Body":  template.HTML(body),
+	}
+}
+
+// BuildSimpleHTML — письмо с заголовком и готовым HTML-телом (рассылки, уведомления о материалах).
+func BuildSimpleHTML(locale, title, body string) string {
+	return renderEmail("simple", simpleData(locale, title, body))
+}
+
+func verificationData(locale, name, link, appLink, validFor string) map[string]any {
+	t := emailText(locale)
+	data := map[string]any{
+		"Lang": NormalizeLocale(locale), "Width": 500, "Footer": t.verifyIgnore,
+		"Title":        t.verifyTitle,
+		"Hello":        fmt.Sprintf(t.verifyHello, name),
+		"Text":         t.verifyText,
+		"Link":         link,
+		"Button":       t.verifyButton,
+		"AppText":      t.verifyApp,
+		"AppLink":      template.URL(appLink), // deep link со своей схемой — html/template иначе заменит его на #ZgotmplZ
+		"AppLinkLabel": t.verifyAppLink,
+		"ValidFor":     "",
+	}
+	if validFor != "" {
+		data["ValidFor"] = fmt.Sprintf(t.verifyValid, validFor)
+	}
+	return data
+}
+
+// BuildVerificationHTML — письмо подтверждения почты на языке locale. appLink (deep link в мобильное
+// приложение) и validFor (срок действия ссылки, например «24 ч») необязательны.
+func BuildVerificationHTML(locale, name, link, appLink, validFor string) string {
+	return renderEmail(MailVerification, verificationData(locale, name, link, appLink, validFor))
 }
 
 func BuildVerifySuccessHTML() string {
@@ -126,36 +107,22 @@ func BuildVerifySuccessHTML() string {
 `
 }
 
-func BuildPasswordResetHTML(resetLink string) string {
-	return fmt.Sprintf(`
-<html>
-  <body style="font-family:Arial,sans-serif; background:#f9f9f9;">
-    <table width="100%%" cellpadding="0" cellspacing="0" bgcolor="#f9f9f9">
-      <tr>
-        <td align="center" style="padding:32px 0;">
-          <table width="500" bgcolor="#fff" cellpadding="24" cellspacing="0" style="border-radius:8px; box-shadow:0 1px 6px #eee;">
-            <tr>
-              <td>
-                <h2 style="color:#2d74da; margin-top:0;">Восстановление пароля</h2>
-                <p style="font-size:16px; color:#222;">Вы запросили восстановление пароля для своей учетной записи.</p>
-                <p>Чтобы установить новый пароль, перейдите по ссылке ниже:</p>
-                <p>
-                  <a href="%s" style="display:inline-block;padding:12px 24px;background:#2d74da;color:#fff;text-decoration:none;border-radius:5px;font-weight:bold;">
-                    Сбросить пароль
-                  </a>
-                </p>
-                <p style="font-size:14px; color:#666;">Ссылка действительна 30 минут.</p>
-                <hr style="margin:32px 0 16px 0; border:0; border-top:1px solid #eee;">
-                <div style="font-size:12px; color:#999;">Если вы не запрашивали восстановление пароля, просто проигнорируйте это письмо.</div>
-              </td>
-            </tr>
-          </table>
-        </td>
-      </tr>
-    </table>
-  </body>
-</html>
-`, resetLink)
+func passwordResetData(locale, resetLink, validFor string) map[string]any {
+	t := emailText(locale)
+	return map[string]any{
+		"Lang": NormalizeLocale(locale), "Width": 500, "Footer": t.resetIgnore,
+		"Title":    t.resetTitle,
+		"Text":     t.resetText,
+		"Hint":     t.resetHint,
+		"Link":     resetLink,
+		"Button":   t.resetButton,
+		"ValidFor": fmt.Sprintf(t.resetValid, validFor),
+	}
+}
+
+// BuildPasswordResetHTML — письмо со ссылкой сброса пароля; validFor — срок действия («30 мин»).
+func BuildPasswordResetHTML(locale, resetLink, validFor string) string {
+	return renderEmail(MailPasswordReset, passwordResetData(locale, resetLink, validFor))
 }
 
 // Ошибка подтверждения email
@@ -186,64 +153,195 @@ func BuildVerifyErrorHTML(errorMsg string) string {
 `, errorMsg)
 }
 
+func subscriptionGrantedData(locale, name, planLabel string, expiresAt time.Time) map[string]any {
+	t := emailText(locale)
+	return map[string]any{
+		"Lang": NormalizeLocale(locale), "Width": 520, "Footer": t.autoFooter,
+		"Title":  t.grantedTitle,
+		"Text":   markup(t.grantedText, name, planLabel),
+		"Until":  markup(t.grantedUntil, expiresAt.Format(t.dateLayout)),
+		"Thanks": t.grantedThanks,
+	}
+}
+
 // BuildSubscriptionGrantedHTML — письмо о выдаче/продлении подписки
-func BuildSubscriptionGrantedHTML(name, planLabel, expiresAt string) string {
-	return fmt.Sprintf(`
-<html>
-  <body style="font-family:Arial,sans-serif; background:#f9f9f9;">
-    <table width="100%%" cellpadding="0" cellspacing="0" bgcolor="#f9f9f9">
-      <tr>
-        <td align="center" style="padding:32px 0;">
-          <table width="520" bgcolor="#fff" cellpadding="24" cellspacing="0" style="border-radius:10px; box-shadow:0 1px 8px #eee;">
-            <tr>
-              <td>
-                <h2 style="color:#2d74da; margin-top:0;">Подписка активирована 🎉</h2>
-                <p style="font-size:16px; color:#222;">%s, ваша подписка <b>%s</b> активирована/продлена.</p>
-                <p style="font-size:16px; color:#222;">Дата окончания: <b>%s</b></p>
-                <p style="font-size:14px; color:#666;">Спасибо, что пользуетесь Edutalks.</p>
-                <hr style="margin:24px 0; border:0; border-top:1px solid #eee;">
-                <div style="font-size:12px; color:#999;">Письмо отправлено автоматически. Не отвечайте на него.</div>
-              </td>
-            </tr>
-          </table>
-        </td>
-      </tr>
-    </table>
-  </body>
-</html>
-`, name, planLabel, expiresAt)
+func BuildSubscriptionGrantedHTML(locale, name, planLabel string, expiresAt time.Time) string {
+	return renderEmail(MailSubscriptionGranted, subscriptionGrantedData(locale, name, planLabel, expiresAt))
 }
 
-// BuildSubscriptionRevokedHTML — письмо об отключении подписки
-func BuildSubscriptionRevokedHTML(name string, revokedAt time.Time, prevExpiresAt *time.Time) string {
-	prev := ""
+func subscriptionRevokedData(locale, name string, revokedAt time.Time, prevExpiresAt *time.Time) map[string]any {
+	t := emailText(locale)
+	data := map[string]any{
+		"Lang": NormalizeLocale(locale), "Width": 520, "Footer": t.autoFooter,
+		"Title":   t.revokedTitle,
+		"Text":    markup(t.revokedText, name, revokedAt.Format(t.dateLayout)),
+		"Prev":    template.HTML(""),
+		"Support": t.revokedSupport,
+	}
 	if prevExpiresAt != nil {
-		prev = fmt.Sprintf(`<p style="font-size:14px; color:#666;">Ранее дата окончания была: <b>%s</b></p>`,
-			prevExpiresAt.Format("02.01.2006 15:04"))
+		data["Prev"] = markup(t.revokedPrev, prevExpiresAt.Format(t.dateLayout))
 	}
+	return data
+}
 
-	return fmt.Sprintf(`
-<html>
-  <body style="font-family:Arial,sans-serif; background:#f9f9f9;">
-    <table width="100%%" cellpadding="0" cellspacing="0" bgcolor="#f9f9f9">
-      <tr>
-        <td align="center" style="padding:32px 0;">
-          <table width="520" bgcolor="#fff" cellpadding="24" cellspacing="0" style="border-radius:10px; box-shadow:0 1px 8px #eee;">
-            <tr>
-              <td>
-                <h2 style="color:#d63636; margin-top:0;">Подписка отключена</h2>
-                <p style="font-size:16px; color:#222;">%s, ваша подписка была отключена: <b>%s</b>.</p>
-                %s
-                <p style="font-size:14px; color:#666;">Если вы не ожидали это письмо, свяжитесь с поддержкой.</p>
-                <hr style="margin:24px 0; border:0; border-top:1px solid #eee;">
-                <div style="font-size:12px; color:#999;">Письмо отправлено автоматически. Не отвечайте на него.</div>
-              </td>
-            </tr>
-          </table>
-        </td>
-      </tr>
-    </table>
-  </body>
-</html>
-`, name, revokedAt.Format("02.01.2006 15:04"), prev)
+// BuildSubscriptionRevokedHTML — письмо об отключении подписки
+func BuildSubscriptionRevokedHTML(locale, name string, revokedAt time.Time, prevExpiresAt *time.Time) string {
+	return renderEmail(MailSubscriptionRevoked, subscriptionRevokedData(locale, name, revokedAt, prevExpiresAt))
+}
+
+// subscriptionReminderData — «скоро закончится» (expiring) или «закончилась».
+func subscriptionReminderData(locale string, expiring bool, name string, at time.Time, renewLink string) map[string]any {
+	t := emailText(locale)
+	title, text, hint, button := t.expiredTitle, t.expiredText, t.expiredHint, t.expiredButton
+	if expiring {
+		title, text, hint, button = t.expiringTitle, t.expiringText, t.expiringHint, t.expiringButton
+	}
+	return map[string]any{
+		"Lang": NormalizeLocale(locale), "Width": 520, "Footer": t.autoFooter,
+		"Title":  title,
+		"Text":   markup(text, name, at.Format(t.dateLayout)),
+		"Hint":   hint,
+		"Link":   renewLink,
+		"Button": button,
+	}
+}
+
+// BuildSubscriptionExpiringHTML — напоминание о скором окончании подписки
+func BuildSubscriptionExpiringHTML(locale, name string, expiresAt time.Time, renewLink string) string {
+	return renderEmail(MailSubscriptionExpiring, subscriptionReminderData(locale, true, name, expiresAt, renewLink))
+}
+
+// BuildSubscriptionExpiredHTML — письмо об окончании подписки
+func BuildSubscriptionExpiredHTML(locale, name string, expiredAt time.Time, renewLink string) string {
+	return renderEmail(MailSubscriptionExpired, subscriptionReminderData(locale, false, name, expiredAt, renewLink))
+}
+
+func invoiceData(locale, name, number, amount string) map[string]any {
+	t := emailText(locale)
+	return map[string]any{
+		"Lang": NormalizeLocale(locale), "Width": 520, "Footer": t.autoFooter,
+		"Title": t.invoiceTitle,
+		"Text":  markup(t.invoiceText, name, number, amount),
+		"Hint":  t.invoiceHint,
+	}
+}
+
+// BuildInvoiceHTML — письмо с квитанцией об оплате; amount — уже отформатированная сумма
+func BuildInvoiceHTML(locale, name, number, amount string) string {
+	return renderEmail(MailInvoice, invoiceData(locale, name, number, amount))
+}
+
+func accountDeletionData(locale, name string, deleteAt time.Time, cancelLink string) map[string]any {
+	t := emailText(locale)
+	return map[string]any{
+		"Lang": NormalizeLocale(locale), "Width": 520, "Footer": t.autoFooter,
+		"Title":  t.deletionTitle,
+		"Text":   markup(t.deletionText, name, deleteAt.Format(t.dateLayout)),
+		"Hint":   t.deletionHint,
+		"Link":   cancelLink,
+		"Button": t.deletionButton,
+		"Ignore": t.deletionIgnore,
+	}
+}
+
+// BuildAccountDeletionHTML — подтверждение запроса на удаление аккаунта со ссылкой отмены
+func BuildAccountDeletionHTML(locale, name string, deleteAt time.Time, cancelLink string) string {
+	return renderEmail(MailAccountDeletion, accountDeletionData(locale, name, deleteAt, cancelLink))
+}
+
+func emailChangeData(locale, name, newEmail, link, validFor string) map[string]any {
+	t := emailText(locale)
+	return map[string]any{
+		"Lang": NormalizeLocale(locale), "Width": 500, "Footer": t.changeIgnore,
+		"Title":    t.changeTitle,
+		"Text":     markup(t.changeText, name, newEmail),
+		"Hint":     t.changeHint,
+		"Link":     link,
+		"Button":   t.changeButton,
+		"ValidFor": fmt.Sprintf(t.changeValid, validFor),
+	}
+}
+
+// BuildEmailChangeHTML — письмо на новый адрес со ссылкой подтверждения смены email
+func BuildEmailChangeHTML(locale, name, newEmail, link, validFor string) string {
+	return renderEmail(MailEmailChange, emailChangeData(locale, name, newEmail, link, validFor))
+}
+
+type digestRow struct{ Label, Value string }
+
+// loginZone — время входа в письме — московское, как в сводках.
+var loginZone = time.FixedZone("MSK", 3*60*60)
+
+func newLoginData(locale, name string, at time.Time, ip, location, device, resetURL string) map[string]any {
+	t := emailText(locale)
+	rows := []digestRow{{t.loginTime, at.In(loginZone).Format(t.dateLayout)}, {t.loginIP, ip}}
+	if location != "" {
+		rows = append(rows, digestRow{t.loginLocation, location})
+	}
+	if device != "" {
+		rows = append(rows, digestRow{t.loginDevice, device})
+	}
+	return map[string]any{
+		"Lang": NormalizeLocale(locale), "Width": 500, "Footer": t.loginFooter,
+		"Title":  t.loginTitle,
+		"Text":   markup(t.loginText, name),
+		"Rows":   rows,
+		"Hint":   t.loginHint,
+		"Link":   resetURL,
+		"Button": t.loginButton,
+	}
+}
+
+// BuildNewLoginHTML — письмо о входе с нового устройства: время, IP, примерное место и ссылка на сброс пароля.
+func BuildNewLoginHTML(locale, name string, at time.Time, ip, location, device, resetURL string) string {
+	return renderEmail(MailNewLogin, newLoginData(locale, name, at, ip, location, device, resetURL))
+}
+
+type digestItem struct {
+	Title   string
+	Message string
+	Count   int
+}
+
+func adminDigestData(locale string, d *models.AdminDigest) map[string]any {
+	t := emailText(locale)
+	rows := []digestRow{
+		{t.digestNewUsers, fmt.Sprint(d.NewUsers)},
+		{t.digestPayments, fmt.Sprint(d.PaymentsSucceeded)},
+		{t.digestRevenue, FormatAmount(locale, d.Revenue, "RUB")},
+		{t.digestFailed, fmt.Sprint(d.PaymentsFailed)},
+		{t.digestExpiring, fmt.Sprint(d.ExpiringSubscriptions)},
+		{t.digestLogErrors, fmt.Sprint(d.ErrorsTotal)},
+	}
+
+	downloads := make([]digestItem, 0, len(d.TopDownloads))
+	for _, it := range d.TopDownloads {
+		title := it.Title
+		if title == "" {
+			title = fmt.Sprintf(t.digestDocument, it.DocumentID)
+		}
+		downloads = append(downloads, digestItem{Title: title, Count: it.Downloads})
+	}
+	errs := make([]digestItem, 0, len(d.TopErrors))
+	for _, e := range d.TopErrors {
+		errs = append(errs, digestItem{Message: e.Message, Count: e.Count})
+	}
+
+	return map[string]any{
+		"Lang": NormalizeLocale(locale), "Width": 600, "Footer": t.autoFooter,
+		"Heading":        t.digestHeading,
+		"Period":         d.From.Format(t.dateLayout) + " — " + d.To.Format(t.dateLayout),
+		"Rows":           rows,
+		"DownloadsTitle": t.digestDownloads,
+		"Downloads":      downloads,
+		"NoDownloads":    t.digestNoDownloads,
+		"ErrorsTitle":    t.digestErrors,
+		"Errors":         errs,
+		"NoErrors":       t.digestNoErrors,
+	}
+}
+
+// BuildAdminDigestHTML — еженедельная сводка для администраторов
+func BuildAdminDigestHTML(locale string, d *models.AdminDigest) string {
+	return renderEmail("admin_digest", adminDigestData(locale, d))
 }
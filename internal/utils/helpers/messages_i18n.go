@@ -0,0 +1,434 @@
+package helpers
+
+import (
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Сообщения ошибок API пишутся в коде по-русски; на другой язык их переводит FailWithDetails
+// по языку ответа (LocaleWriter, ставит middleware.Locale). Цепочка: перевод сообщения из
+// каталога → общий текст кода ошибки на этом языке → исходное сообщение. Сообщения, уже
+// написанные латиницей (старые эндпоинты), не трогаем.
+
+// LocaleWriter — writer, знающий язык ответа (Accept-Language или язык пользователя).
+type LocaleWriter interface {
+	Locale() string
+}
+
+// ResponseLocale — язык ответа: ищет LocaleWriter среди обёрток writer-а; нет — DefaultLocale.
+func ResponseLocale(w http.ResponseWriter) string {
+	for {
+		if lw, ok := w.(LocaleWriter); ok {
+			return NormalizeLocale(lw.Locale())
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return DefaultLocale
+		}
+		w = u.Unwrap()
+	}
+}
+
+// apiMessages — переводы сообщений по языкам; ключ — русский текст без учёта регистра первой буквы.
+var apiMessages = map[string]map[string]string{
+	LocaleEN: {
+		// общее
+		"Ошибка сервера":                           "Internal server error",
+		"Внутренняя ошибка сервера":                "Internal server error",
+		"Нет доступа":                              "Access denied",
+		"Доступ запрещён":                          "Access denied",
+		"Невалидный JSON":                          "Invalid JSON",
+		"Ошибка валидации":                         "Validation failed",
+		"Пустой запрос":                            "Empty request",
+		"Ошибка разбора формы":                     "Failed to parse the form",
+		"Некорректный id":                          "Invalid id",
+		"Невалидный id":                            "Invalid id",
+		"Невалидный ID":                            "Invalid ID",
+		"Неверный ID":                              "Invalid ID",
+		"Некорректный параметр from":               "Invalid from parameter",
+		"Некорректный параметр to":                 "Invalid to parameter",
+		"Некорректный параметр window":             "Invalid window parameter",
+		"Некорректный параметр document_id":        "Invalid document_id parameter",
+		"Неверный формат duration":                 "Invalid duration format",
+		"Слишком много запросов, попробуйте позже": "Too many requests, please try again later",
+		"Не удалось определить роль":               "Could not determine the role",
+		"Запись не найдена":                        "Record not found",
+		"Ошибка удаления":                          "Delete failed",
+		"Ошибка при удалении":                      "Delete failed",
+		"Ошибка обновления":                        "Update failed",
+		"Ошибка при обновлении":                    "Update failed",
+		"Ошибка массовой операции":                 "Bulk operation failed",
+		"Не удалось получить данные":               "Failed to load data",
+		"Не удалось получить статистику":           "Failed to load statistics",
+		"Не удалось сохранить порядок":             "Failed to save the order",
+		"Не удалось сохранить отметку":             "Failed to save the mark",
+		"Не удалось запустить проверку":            "Failed to start the check",
+
+		// авторизация и аккаунт
+		"Отсутствует access token":                                                    "Access token is missing",
+		"Отсутствует токен":                                                           "Token is missing",
+		"Токен отсутствует":                                                           "Token is missing",
+		"Неверный или просроченный токен":                                             "Invalid or expired token",
+		"Невалидный токен":                                                            "Invalid token",
+		"Недопустимый payload":                                                        "Invalid token payload",
+		"Ошибка генерации токена":                                                     "Failed to issue a token",
+		"Требуются login/username и password":                                         "login/username and password are required",
+		"Неверный пароль":                                                             "Wrong password",
+		"неверный логин или пароль":                                                   "Invalid login or password",
+		"Ошибка при выходе":                                                           "Logout failed",
+		"Пользователь не найден":                                                      "User not found",
+		"Не удалось получить пользователя":                                            "Failed to load the user",
+		"Ошибка получения пользователей":                                              "Failed to load users",
+		"Некорректный id пользователя":                                                "Invalid user id",
+		"Некорректный ID пользователя":                                                "Invalid user ID",
+		"Неверный ID пользователя":                                                    "Invalid user ID",
+		"Ошибка при удалении пользователя":                                            "Failed to delete the user",
+		"Ошибка обновления профиля":                                                   "Failed to update the profile",
+		"Неподдерживаемый язык: допустимы ru, en":                                     "Unsupported language: ru and en are allowed",
+		"Неверный формат запроса или пустой email":                                    "Malformed request or empty email",
+		"Ошибка при отправке письма":                                                  "Failed to send the email",
+		"Укажите текущий пароль":                                                      "Enter your current password",
+		"Укажите новый адрес и текущий пароль":                                        "Enter the new address and your current password",
+		"Email меняется через POST /api/profile/email с подтверждением нового адреса": "Email is changed via POST /api/profile/email with confirmation of the new address",
+		"отправка SMS не настроена":                                                   "SMS sending is not configured",
+		"в профиле не указан телефон":                                                 "No phone number in the profile",
+		"некорректный номер телефона":                                                 "Invalid phone number",
+		"телефон уже подтверждён":                                                     "The phone number is already confirmed",
+		"новый код можно запросить позже":                                             "A new code can be requested later",
+		"неверный или устаревший код":                                                 "The code is invalid or has expired",
+		"исчерпаны попытки ввода кода, запросите новый":                               "Too many wrong codes, request a new one",
+		"не удалось отправить SMS, попробуйте позже":                                  "Failed to send the SMS, please try again later",
+		"пароль должен быть не короче 8 символов":                                     "The password must be at least 8 characters long",
+		"Укажите код из SMS":                                                          "Enter the code from the SMS",
+		"Укажите номер телефона":                                                      "Enter the phone number",
+		"Укажите номер, код из SMS и новый пароль":                                    "Enter the phone number, the code from the SMS and a new password",
+		"Не удалось отправить код":                                                    "Failed to send the code",
+		"Не удалось подтвердить телефон":                                              "Failed to confirm the phone number",
+		"Не удалось сбросить пароль":                                                  "Failed to reset the password",
+		"Не удалось запросить смену email":                                            "Failed to request the email change",
+		"Не удалось сменить email":                                                    "Failed to change the email",
+		"Не удалось удалить аккаунт":                                                  "Failed to delete the account",
+		"Не удалось отменить удаление":                                                "Failed to cancel the deletion",
+		"Неверный ID сессии":                                                          "Invalid session ID",
+		"Не удалось получить сессии":                                                  "Failed to load sessions",
+		"Не удалось завершить сессию":                                                 "Failed to end the session",
+		"Не удалось получить привязанные аккаунты":                                    "Failed to load linked accounts",
+		"Не удалось отвязать аккаунт":                                                 "Failed to unlink the account",
+		"Не удалось начать вход":                                                      "Failed to start sign-in",
+		"Неверная ссылка отписки":                                                     "Invalid unsubscribe link",
+		"Не удалось отписаться от рассылки":                                           "Failed to unsubscribe",
+		"Укажите subscribe, frequency или login_alerts":                               "Specify subscribe, frequency or login_alerts",
+		"Не удалось обновить статус подписки":                                         "Failed to update the subscription status",
+		"Ошибка выдачи подписки":                                                      "Failed to grant the subscription",
+		"Ошибка продления подписки":                                                   "Failed to extend the subscription",
+		"Ошибка отключения подписки":                                                  "Failed to revoke the subscription",
+		"Некорректный ID выгрузки":                                                    "Invalid export ID",
+		"Файл выгрузки недоступен":                                                    "The export file is not available",
+		"Не удалось открыть выгрузку":                                                 "Failed to open the export",
+		"Не удалось запустить выгрузку":                                               "Failed to start the export",
+		"адрес электронной почты уже зарегистрирован":                                 "This email address is already registered",
+		"имя пользователя уже занято":                                                 "This username is already taken",
+		"аккаунт ожидает удаления: отменить удаление можно по ссылке из письма":       "The account is pending deletion: you can cancel it using the link from the email",
+		"адрес уже зарегистрирован: войдите по паролю и привяжите аккаунт в профиле": "This address is already registered: sign in with your password and link the account in your profile",
+		"аккаунт провайдера не привязан":                                             "The provider account is not linked",
+		"этот аккаунт уже привязан к другому пользователю":                           "This account is already linked to another user",
+		"провайдер входа не поддерживается или не настроен":                          "This sign-in provider is not supported or not configured",
+		"провайдер не подтвердил адрес почты":                                        "The provider did not confirm the email address",
+		"ссылка входа устарела, начните заново":                                      "The sign-in link has expired, please start again",
+		"ссылка подтверждения недействительна или устарела":                          "The confirmation link is invalid or has expired",
+		"ссылка отмены удаления недействительна или устарела":                        "The deletion cancellation link is invalid or has expired",
+		"ссылка на выгрузку недействительна или устарела":                            "The export link is invalid or has expired",
+		"выгрузка ещё не готова":                                                     "The export is not ready yet",
+		"новый адрес совпадает с текущим":                                            "The new address is the same as the current one",
+		"некорректный адрес почты":                                                   "Invalid email address",
+		"неподдерживаемый язык":                                                      "Unsupported language",
+		"сессия не найдена":                                                          "Session not found",
+		"нельзя войти под администратором":                                           "You cannot sign in as an administrator",
+		"нельзя войти под своим аккаунтом":                                           "You cannot sign in as yourself",
+		"неверный токен":                                                             "Invalid token",
+		"токен истёк":                                                                "The token has expired",
+		"role должна быть user, author или admin":                                    "role must be user, author or admin",
+		"слишком много пользователей для одной операции":                             "Too many users for a single operation",
+		"укажите user_ids или непустой filter":                                       "Specify user_ids or a non-empty filter",
+		"даты регистрации — в формате YYYY-MM-DD или RFC3339":                        "Registration dates must be YYYY-MM-DD or RFC3339",
+
+		// документы, файлы, разделы
+		"Документ не найден":                                    "Document not found",
+		"Некорректный id документа":                             "Invalid document id",
+		"Некорректный идентификатор документа":                  "Invalid document id",
+		"Этот документ закрыт":                                  "This document is private",
+		"Нет доступа — купите подписку":                         "Access denied: a subscription is required",
+		"Документ недоступен для просмотра":                     "The document is not available for viewing",
+		"Просмотр в браузере доступен только для PDF":           "Viewing in the browser is only available for PDF",
+		"Ошибка получения документов":                           "Failed to load documents",
+		"Ошибка при получении документов":                       "Failed to load documents",
+		"Файл не найден":                                        "File not found",
+		"Файл слишком большой":                                  "The file is too large",
+		"файл слишком большой":                                  "The file is too large",
+		"файл слишком большой (макс 10 МБ)":                     "The file is too large (max 10 MB)",
+		"Файл заблокирован: обнаружена угроза":                  "The file is blocked: a threat was detected",
+		"Не удалось прочитать файл":                             "Failed to read the file",
+		"Не удалось проверить файл":                             "Failed to check the file",
+		"поле file обязательно":                                 "The file field is required",
+		"ошибка записи файла":                                   "Failed to write the file",
+		"ошибка сохранения файла":                               "Failed to save the file",
+		"не удалось создать директорию":                         "Failed to create the directory",
+		"допустимы только изображения: jpg, png, webp, gif":     "Only images are allowed: jpg, png, webp, gif",
+		"допустимы только изображения: jpg, png, gif":           "Only images are allowed: jpg, png, gif",
+		"изображение слишком большое (до 25 Мп)":                "The image is too large (up to 25 MP)",
+		"ошибка чтения файла":                                   "Failed to read the file",
+		"Ошибка формирования изображения":                       "Failed to render the image",
+		"Некорректный ID":                                       "Invalid ID",
+		"недопустимый тип файла":                                "This file type is not allowed",
+		"содержимое файла не соответствует расширению":          "The file content does not match its extension",
+		"проверка файла на вирусы недоступна, попробуйте позже": "Virus scanning is unavailable, please try again later",
+		"дневной лимит скачиваний исчерпан":                     "The daily download limit has been reached",
+		"документ не найден":                                    "Document not found",
+		"у пользователя нет персонального доступа к документу":  "The user has no personal access to the document",
+		"срок доступа уже прошёл":                               "The access period has already ended",
+		"Раздел не найден":                                      "Section not found",
+		"раздел не найден":                                      "Section not found",
+		"в разделе есть документы или подразделы":               "The section contains documents or subsections",
+		"недопустимый родительский раздел":                      "Invalid parent section",
+		"Не удалось перенести документы":                        "Failed to move the documents",
+		"Некорректный move_to":                                  "Invalid move_to",
+		"слишком много документов для одного переноса":          "Too many documents for a single move",
+		"укажите document_ids или from_section_id":              "Specify document_ids or from_section_id",
+		"Связь не найдена":                                      "Link not found",
+		"связь не найдена":                                      "Link not found",
+		"такая связь уже есть":                                  "This link already exists",
+		"Некорректный id связи":                                 "Invalid link id",
+		"страница каталога не найдена":                          "Catalog page not found",
+
+		// статьи, новости, медиа
+		"Статья не найдена":           "Article not found",
+		"статья не найдена":           "Article not found",
+		"Новость не найдена":          "News item not found",
+		"новость не найдена":          "News item not found",
+		"Не удалось создать новость":  "Failed to create the news item",
+		"Ошибка получения новостей":   "Failed to load news",
+		"Ошибка изменения публикации": "Failed to change publication",
+		"Ошибка формирования ленты":   "Failed to build the feed",
+		"Новость уже опубликована — сначала снимите её с публикации": "The news item is already published: unpublish it first",
+		"новость уже опубликована":                                   "The news item is already published",
+		"Некорректный запрос: нужен publish или publish_at":          "Invalid request: publish or publish_at is required",
+		"status должен быть published|draft|scheduled":               "status must be published|draft|scheduled",
+		"Изображение не найдено":                                     "Image not found",
+		"изображение не найдено":                                     "Image not found",
+		"Изображение используется в статьях":                         "The image is used in articles",
+		"изображение используется в статьях":                         "The image is used in articles",
+		"Не удалось получить медиатеку":                              "Failed to load the media library",
+		"ревизия не найдена":                                         "Revision not found",
+		"черновик не найден":                                         "Draft not found",
+		"контент слишком короткий":                                   "The content is too short",
+		"длина заголовка должна быть от 3 до 255 символов":           "The title must be between 3 and 255 characters",
+		"длина заголовка должна быть не более 255 символов":          "The title must be at most 255 characters",
+		"максимум 5 тегов":                                           "At most 5 tags",
+		"у новости не больше 5 тегов, каждый — до 50 символов":       "A news item can have at most 5 tags, each up to 50 characters",
+		"материал принадлежит другому автору":                        "This material belongs to another author",
+		"опубликованный материал может снять только администратор":   "Only an administrator can remove published material",
+		"публиковать материалы может только администратор":           "Only an administrator can publish materials",
+		"материал не найден":                                         "Material not found",
+		"в текущем состоянии материала это действие недоступно":      "This action is not available in the material's current state",
+		"укажите причину отклонения":                                 "Specify the reason for rejection",
+		"type должен быть article или document":                      "type must be article or document",
+		"state должен быть draft|review|rejected|published":          "state must be draft|review|rejected|published",
+		"в корзине нет такого материала":                             "There is no such item in the trash",
+		"Не удалось получить популярные материалы":                   "Failed to load popular materials",
+
+		// платежи, рассылки, админка
+		"платёж не найден": "Payment not found",
+		"квитанция выдаётся только по успешному платежу":       "A receipt is issued only for a successful payment",
+		"формирование квитанций не настроено (INVOICE_FONT)":   "Receipt generation is not configured (INVOICE_FONT)",
+		"Не удалось поставить письмо в очередь":                "Failed to queue the email",
+		"Не удалось получить список писем":                     "Failed to load the email list",
+		"Не удалось получить письмо":                           "Failed to load the email",
+		"письмо не найдено":                                    "Email not found",
+		"письмо не найдено или не в статусе failed":            "Email not found or not in the failed status",
+		"Не удалось получить список подписчиков":               "Failed to load subscribers",
+		"рассылка не найдена":                                  "Mailing not found",
+		"рассылка уже отправлена или отменена":                 "The mailing has already been sent or cancelled",
+		"template должен быть simple или news":                 "template must be simple or news",
+		"для шаблона news укажите url":                         "Specify url for the news template",
+		"Не удалось собрать сводку":                            "Failed to build the summary",
+		"Не удалось отправить сводку":                          "Failed to send the summary",
+		"не настроены получатели сводки (ADMIN_DIGEST_EMAILS)": "Summary recipients are not configured (ADMIN_DIGEST_EMAILS)",
+		"Не удалось отправить оповещение":                      "Failed to send the alert",
+		"канал оповещений не настроен":                         "The alert channel is not configured",
+		"каналы оповещений не настроены":                       "Alert channels are not configured",
+		"Не удалось получить флаги":                            "Failed to load feature flags",
+		"Флаг не найден":                                       "Flag not found",
+		"флаг не найден":                                       "Flag not found",
+		"флаг с таким ключом уже есть":                         "A flag with this key already exists",
+		"Баннер не найден":                                     "Banner not found",
+		"баннер не найден":                                     "Banner not found",
+		"Не удалось получить баннеры":                          "Failed to load banners",
+		"Не удалось получить список баннеров":                  "Failed to load banners",
+		"ends_at должен быть позже starts_at":                  "ends_at must be later than starts_at",
+		"Не удалось получить список обновлений":                "Failed to load the changelog",
+		"запись changelog не найдена":                          "Changelog entry not found",
+		"Нужен title; kind — feature|improvement|fix":          "title is required; kind is feature|improvement|fix",
+		"Уведомление не найдено":                               "Notification not found",
+		"уведомление не найдено":                               "Notification not found",
+		"Не удалось получить уведомления":                      "Failed to load notifications",
+		"Не удалось получить ленту действий":                   "Failed to load the activity feed",
+		"Не удалось получить топ документов":                   "Failed to load top documents",
+		"Не удалось получить статистику скачиваний":            "Failed to load download statistics",
+		"переиндексация уже выполняется":                       "Reindexing is already running",
+		"повторная проверка файлов уже выполняется":            "File rescanning is already running",
+		"некорректный период: from позже to или длиннее года":  "Invalid period: from is after to or longer than a year",
+		"type: document, news или article":                     "type: document, news or article",
+		"type должен быть news|article":                        "type must be news|article",
+		"has_subscription должен быть true|false":              "has_subscription must be true|false",
+		"channel должен быть telegram или slack":               "channel must be telegram or slack",
+		"status должен быть pending|sent|failed":               "status must be pending|sent|failed",
+		"status: scheduled, sent или cancelled":                "status: scheduled, sent or cancelled",
+	},
+}
+
+// codeMessages — общие тексты кодов ошибок: если точного перевода сообщения нет.
+var codeMessages = map[string]map[ErrorCode]string{
+	LocaleEN: {
+		CodeBadRequest:         "Bad request",
+		CodeInvalidJSON:        "Invalid JSON",
+		CodeInvalidID:          "Invalid ID",
+		CodeValidationFailed:   "Validation failed",
+		CodeUnauthorized:       "Unauthorized",
+		CodeForbidden:          "Access denied",
+		CodeNotFound:           "Not found",
+		CodeMethodNotAllowed:   "Method not allowed",
+		CodeConflict:           "Conflict",
+		CodeGone:               "No longer available",
+		CodePayloadTooLarge:    "Request is too large",
+		CodeRateLimited:        "Too many requests, please try again later",
+		CodeInternal:           "Internal server error",
+		CodeServiceUnavailable: "Service unavailable",
+
+		CodeAuthInvalidCredentials: "Invalid login or password",
+		CodeAuthTokenMissing:       "Access token is missing",
+		CodeAuthTokenInvalid:       "Invalid or expired token",
+		CodeAuthDeletionPending:    "The account is pending deletion",
+		CodeAuthUsernameTaken:      "This username is already taken",
+		CodeAuthEmailTaken:         "This email address is already registered",
+		CodeAuthPasswordInvalid:    "Wrong password",
+		CodeAuthLinkInvalid:        "The link is invalid or has expired",
+
+		CodeUserNotFound:         "User not found",
+		CodeLocaleUnsupported:    "Unsupported language",
+		CodeEmailUnchanged:       "The new address is the same as the current one",
+		CodeEmailChangeRequired:  "Email change requires confirmation of the new address",
+		CodeSessionNotFound:      "Session not found",
+		CodeOAuthProvider:        "Unknown sign-in provider",
+		CodeOAuthNotLinked:       "The provider account is not linked",
+		CodePhoneMissing:         "No phone number in the profile",
+		CodePhoneAlreadyVerified: "The phone number is already confirmed",
+		CodePhoneCodeInvalid:     "The code is invalid or has expired",
+		CodePhoneCodeAttempts:    "Too many wrong codes, request a new one",
+
+		CodeDocNotFound:             "Document not found",
+		CodeDocNotPublic:            "This document is private",
+		CodeDocSubscriptionRequired: "A subscription is required",
+		CodeNewsNotFound:            "News item not found",
+		CodeSectionNotEmpty:         "The section contains documents or subsections",
+		CodeMediaInUse:              "The image is used in articles",
+		CodeFileInfected:            "The file is blocked: a threat was detected",
+		CodeFileTypeNotAllowed:      "This file type is not allowed",
+		CodeFileContentMismatch:     "The file content does not match its extension",
+		CodeFileTooLarge:            "The file is too large",
+		CodeDownloadQuotaExceeded:   "The daily download limit has been reached",
+		CodeContentNotOwner:         "This material belongs to another author",
+		CodeContentPublishDenied:    "Only an administrator can publish materials",
+		CodePaymentNotFound:         "Payment not found",
+		CodePaymentInvalidPlan:      "Invalid plan",
+	},
+}
+
+// fieldMessages — переводы ошибок валидации полей (validate): точные тексты и префиксы с параметрами.
+var (
+	fieldMessages = map[string]map[string]string{
+		LocaleEN: {
+			"обязательное поле":           "required field",
+			"не может быть пустым":        "must not be empty",
+			"некорректный адрес почты":    "invalid email address",
+			"некорректный номер телефона": "invalid phone number",
+			"пароль должен быть не короче 8 символов и содержать буквы и цифры": "the password must be at least 8 characters long and contain letters and digits",
+			"пароль слишком длинный": "the password is too long",
+			"допустимы латинские буквы, цифры, точка, дефис и подчёркивание": "only latin letters, digits, dot, hyphen and underscore are allowed",
+			"некорректная ссылка": "invalid link",
+		},
+	}
+	fieldPrefixes = map[string][][2]string{
+		LocaleEN: {
+			{"значение не меньше ", "value must be at least "},
+			{"значение не больше ", "value must be at most "},
+			{"не меньше ", "at least "},
+			{"не больше ", "at most "},
+			{"допустимые значения: ", "allowed values: "},
+		},
+	}
+	fieldUnits = map[string]map[string]string{
+		LocaleEN: {"символов": "characters", "элементов": "items"},
+	}
+)
+
+// LocalizeError — сообщение ошибки API на языке locale (см. цепочку в начале файла).
+func LocalizeError(locale string, code ErrorCode, msg string) string {
+	locale = NormalizeLocale(locale)
+	if locale == DefaultLocale || !hasCyrillic(msg) {
+		return msg
+	}
+	if tr, ok := lookupMessage(apiMessages[locale], msg); ok {
+		return tr
+	}
+	if tr, ok := codeMessages[locale][code]; ok {
+		return tr
+	}
+	return msg
+}
+
+// localizeFieldMessage — текст ошибки поля из validate на языке locale.
+func localizeFieldMessage(locale, msg string) string {
+	if locale == DefaultLocale {
+		return msg
+	}
+	if tr, ok := fieldMessages[locale][msg]; ok {
+		return tr
+	}
+	for _, p := range fieldPrefixes[locale] {
+		if rest, ok := strings.CutPrefix(msg, p[0]); ok {
+			for ru, tr := range fieldUnits[locale] {
+				rest = strings.Replace(rest, ru, tr, 1)
+			}
+			return p[1] + rest
+		}
+	}
+	return msg
+}
+
+// lookupMessage — перевод без учёта регистра первой буквы («Документ не найден» = «документ не найден»).
+func lookupMessage(catalog map[string]string, msg string) (string, bool) {
+	if tr, ok := catalog[msg]; ok {
+		return tr, true
+	}
+	r, size := utf8.DecodeRuneInString(msg)
+	for _, alt := range []rune{unicode.ToLower(r), unicode.ToUpper(r)} {
+		if alt == r {
+			continue
+		}
+		if tr, ok := catalog[string(alt)+msg[size:]]; ok {
+			return tr, true
+		}
+	}
+	return "", false
+}
+
+func hasCyrillic(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,22 +2,90 @@ package helpers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"edutalks/internal/utils/validate"
 )
 
 type Response struct {
 	Data  interface{} `json:"data,omitempty"`
-	Error string      `json:"error,omitempty"`
+	Error *ErrorBody  `json:"error,omitempty"`
+}
+
+// ErrorBody — ошибка API: {"code": "DOC_NOT_PUBLIC", "message": "...", "details": {...}}.
+// message — для человека (может меняться, на языке ответа — см. LocalizeError), code — для клиента (см. ErrorCode).
+type ErrorBody struct {
+	Code    ErrorCode   `json:"code" example:"BAD_REQUEST"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 func JSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(Response{Data: data, Error: ""})
+	json.NewEncoder(w).Encode(Response{Data: data})
 }
 
+// Error — ошибка с кодом по HTTP-статусу (CodeForStatus).
 func Error(w http.ResponseWriter, status int, errMsg string) {
+	FailWithDetails(w, status, CodeForStatus(status), errMsg, nil)
+}
+
+// ErrorWithData — ошибка с дополнительными данными (например, сколько ждать до повтора) в details.
+func ErrorWithData(w http.ResponseWriter, status int, errMsg string, data interface{}) {
+	FailWithDetails(w, status, CodeForStatus(status), errMsg, data)
+}
+
+// Fail — ошибка с конкретным кодом из каталога.
+func Fail(w http.ResponseWriter, status int, code ErrorCode, errMsg string) {
+	FailWithDetails(w, status, code, errMsg, nil)
+}
+
+func FailWithDetails(w http.ResponseWriter, status int, code ErrorCode, errMsg string, details interface{}) {
+	if status >= http.StatusInternalServerError {
+		reportServerError(w, status, code, errMsg)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(Response{Data: nil, Error: errMsg})
+	msg := LocalizeError(ResponseLocale(w), code, errMsg)
+	json.NewEncoder(w).Encode(Response{Error: &ErrorBody{Code: code, Message: msg, Details: details}})
+}
+
+// ServerErrorReporter — writer, которому сообщают об ответах 5xx (middleware.Recoverer передаёт их в Sentry).
+type ServerErrorReporter interface {
+	ReportServerError(status int, code ErrorCode, message string)
+}
+
+// reportServerError — ищет ServerErrorReporter среди обёрток writer-а (Unwrap, как у http.ResponseController).
+func reportServerError(w http.ResponseWriter, status int, code ErrorCode, errMsg string) {
+	for {
+		if rep, ok := w.(ServerErrorReporter); ok {
+			rep.ReportServerError(status, code, errMsg)
+			return
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return
+		}
+		w = u.Unwrap()
+	}
+}
+
+// ValidationError — 400 VALIDATION_FAILED с ошибками по полям в details.fields.
+// err — результат validate.Struct; прочие ошибки отдаются как BAD_REQUEST.
+func ValidationError(w http.ResponseWriter, err error) {
+	var fields validate.Errors
+	if !errors.As(err, &fields) {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if locale := ResponseLocale(w); locale != DefaultLocale {
+		localized := make(validate.Errors, len(fields))
+		for i, f := range fields {
+			localized[i] = validate.FieldError{Field: f.Field, Message: localizeFieldMessage(locale, f.Message)}
+		}
+		fields = localized
+	}
+	FailWithDetails(w, http.StatusBadRequest, CodeValidationFailed, "Ошибка валидации", map[string]any{"fields": fields})
 }
@@ -0,0 +1,79 @@
+package imaging
+
+import (
+	"crypto/sha256"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+var identiconBackground = color.RGBA{R: 0xf0, G: 0xf0, B: 0xf0, A: 0xff}
+
+// Identicon — узор 5×5, симметричный по вертикали, цвет и клетки — из SHA-256 от seed;
+// один seed — всегда одна картинка.
+func Identicon(seed string, size int) *image.RGBA {
+	sum := sha256.Sum256([]byte(seed))
+	fg := hslToRGB(float64(uint16(sum[0])<<8|uint16(sum[1]))/65536*360, 0.55, 0.5)
+
+	const cells = 5
+	cell := size / (cells + 1) // поле по краям — полклетки
+	offset := (size - cell*cells) / 2
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: identiconBackground}, image.Point{}, draw.Src)
+	for row := 0; row < cells; row++ {
+		for col := 0; col < (cells+1)/2; col++ {
+			bit := row*3 + col
+			if sum[2+bit/8]>>(bit%8)&1 == 0 {
+				continue
+			}
+			fillCell(img, offset+col*cell, offset+row*cell, cell, fg)
+			fillCell(img, offset+(cells-1-col)*cell, offset+row*cell, cell, fg)
+		}
+	}
+	return img
+}
+
+func fillCell(img *image.RGBA, x0, y0, cell int, c color.RGBA) {
+	for y := y0; y < y0+cell; y++ {
+		for x := x0; x < x0+cell; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// hslToRGB — h в градусах, s и l — от 0 до 1.
+func hslToRGB(h, s, l float64) color.RGBA {
+	c := (1 - abs(2*l-1)) * s
+	hp := h / 60
+	x := c * (1 - abs(mod2(hp)-1))
+	var r, g, b float64
+	switch {
+	case hp < 1:
+		r, g = c, x
+	case hp < 2:
+		r, g = x, c
+	case hp < 3:
+		g, b = c, x
+	case hp < 4:
+		g, b = x, c
+	case hp < 5:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	m := l - c/2
+	return color.RGBA{R: uint8((r + m) * 255), G: uint8((g + m) * 255), B: uint8((b + m) * 255), A: 0xff}
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// mod2 — остаток от деления на 2 для неотрицательных v.
+func mod2(v float64) float64 {
+	return v - 2*float64(int(v/2))
+}
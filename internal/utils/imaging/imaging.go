@@ -0,0 +1,102 @@
+// Package imaging — обработка загруженных изображений без внешних зависимостей: декодирование
+// (JPEG, PNG, GIF) с защитой от «бомб», квадратные миниатюры и identicon по умолчанию.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+
+	_ "image/gif" // декодер GIF для image.Decode
+)
+
+// maxPixels — предел размера исходника: маленький файл может распаковаться в гигабайты пикселей.
+const maxPixels = 25_000_000
+
+// jpegQuality — качество сохранённых миниатюр.
+const jpegQuality = 85
+
+var (
+	ErrUnsupported = errors.New("допустимы только изображения: jpg, png, gif")
+	ErrTooLarge    = errors.New("изображение слишком большое (до 25 Мп)")
+)
+
+// Decode — изображение из data; размер проверяется по заголовку до распаковки.
+func Decode(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	return img, nil
+}
+
+// SquareThumbnail — центральный квадрат src, приведённый к size×size: уменьшение — усреднением
+// по площади, увеличение — ближайшим пикселем. Прозрачность кладётся на белый фон.
+func SquareThumbnail(src image.Image, size int) *image.RGBA {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for dy := 0; dy < size; dy++ {
+		sy0, sy1 := span(y0, side, size, dy)
+		for dx := 0; dx < size; dx++ {
+			sx0, sx1 := span(x0, side, size, dx)
+
+			var r, g, bl, n uint64
+			for y := sy0; y < sy1; y++ {
+				for x := sx0; x < sx1; x++ {
+					pr, pg, pb, pa := src.At(x, y).RGBA() // 16 бит, с предумножением на альфу
+					white := uint64(0xffff - pa)
+					r += uint64(pr) + white
+					g += uint64(pg) + white
+					bl += uint64(pb) + white
+					n++
+				}
+			}
+			dst.SetRGBA(dx, dy, color.RGBA{
+				R: uint8((r / n) >> 8), G: uint8((g / n) >> 8), B: uint8((bl / n) >> 8), A: 0xff,
+			})
+		}
+	}
+	return dst
+}
+
+// span — диапазон исходных координат [from, to) для пикселя i из size; не пустой.
+func span(origin, side, size, i int) (int, int) {
+	from := origin + i*side/size
+	to := origin + (i+1)*side/size
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
+
+// EncodeJPEG — миниатюра в JPEG.
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodePNG — изображение в PNG (identicon).
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -1,18 +1,30 @@
 package utils
 
 import (
+	"math"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// IssuedAt — claim iat с точностью до миллисекунд (JWT допускает дробные секунды): отзыв токенов
+// сравнивается с ним точнее секунды, и токен, выданный сразу после отзыва, принимается.
+func IssuedAt(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000
+}
+
+// IssuedAtTime — обратное к IssuedAt; целое iat старых токенов даёт начало секунды.
+func IssuedAtTime(iat float64) time.Time {
+	return time.UnixMilli(int64(math.Round(iat * 1000)))
+}
+
 // GenerateToken создаёт JWT (теперь только access-токен).
 func GenerateToken(secret string, userID int, role string, duration time.Duration, tokenType string) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"role":    role,
 		"exp":     time.Now().Add(duration).Unix(),
-		"iat":     time.Now().Unix(), // issued at — доп. уникальность
+		"iat":     IssuedAt(time.Now()), // issued at — доп. уникальность
 
 	}
 
@@ -23,6 +35,40 @@ func GenerateToken(secret string, userID int, role string, duration time.Duratio
 	return token.SignedString([]byte(secret))
 }
 
+// GenerateSessionToken — access-токен, привязанный к сессии (claim sid): отзыв сессии отзывает и токен.
+func GenerateSessionToken(secret string, userID int, role string, sessionID int64, duration time.Duration) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id":    userID,
+		"role":       role,
+		"sid":        sessionID,
+		"exp":        time.Now().Add(duration).Unix(),
+		"iat":        IssuedAt(time.Now()),
+		"token_type": "access",
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+// GenerateImpersonationToken — access-токен пользователя для администратора поддержки:
+// claim imp — id администратора, imp_id — запись в журнале impersonations, sid — сессия,
+// отзыв которой завершает вход.
+func GenerateImpersonationToken(secret string, userID int, role string, adminID int, impersonationID, sessionID int64, duration time.Duration) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id":    userID,
+		"role":       role,
+		"sid":        sessionID,
+		"imp":        adminID,
+		"imp_id":     impersonationID,
+		"exp":        time.Now().Add(duration).Unix(),
+		"iat":        IssuedAt(time.Now()),
+		"token_type": "access",
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
 // --- ❌ Старый вариант (оставлен для истории) ---
 //
 // func GenerateToken(secret string, userID int, role string, duration time.Duration, tokenType string) (string, error) {
@@ -0,0 +1,202 @@
+// Package pdf — PDF через pdfcpu: квитанции (страницы A4, текст встроенным TrueType-шрифтом
+// с кириллицей, линии) и водяной знак поверх существующих PDF (см. Watermark).
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/color"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/create"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/draw"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// Размер A4 в пунктах.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font — TrueType-шрифт, установленный в pdfcpu.
+type Font struct {
+	name string // PostScript-имя, под которым его знает pdfcpu
+}
+
+var fontMu sync.Mutex
+
+// LoadFont — устанавливает .ttf в каталог шрифтов pdfcpu. Каталог настроек pdfcpu отключён
+// (см. init в watermark.go), поэтому шрифты живут в постоянном каталоге во временной
+// директории: перезапуск сервера переписывает те же файлы, а не копит новые.
+func LoadFont(path string) (*Font, error) {
+	fontMu.Lock()
+	defer fontMu.Unlock()
+
+	if font.UserFontDir == "" {
+		dir := filepath.Join(os.TempDir(), "edutalks-pdf-fonts")
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("pdf: font dir: %w", err)
+		}
+		font.UserFontDir = dir
+	}
+
+	// pdfcpu называет установленный шрифт его PostScript-именем и не возвращает его,
+	// так что шрифт ставится в пустой каталог, а оттуда переносится к остальным
+	scratch, err := os.MkdirTemp(font.UserFontDir, "install-")
+	if err != nil {
+		return nil, fmt.Errorf("pdf: font dir: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+	if err := font.InstallTrueTypeFont(scratch, path); err != nil {
+		return nil, fmt.Errorf("pdf: font %s: %w", path, err)
+	}
+	gobs, _ := filepath.Glob(filepath.Join(scratch, "*.gob"))
+	if len(gobs) != 1 {
+		return nil, fmt.Errorf("pdf: font %s: not installed", path)
+	}
+	file := filepath.Base(gobs[0])
+	if err := os.Rename(gobs[0], filepath.Join(font.UserFontDir, file)); err != nil {
+		return nil, fmt.Errorf("pdf: font %s: %w", path, err)
+	}
+	if err := font.LoadUserFonts(); err != nil {
+		return nil, fmt.Errorf("pdf: font %s: %w", path, err)
+	}
+	return &Font{name: strings.TrimSuffix(file, ".gob")}, nil
+}
+
+// Width — ширина строки в пунктах.
+func (f *Font) Width(s string, size int) float64 {
+	return font.TextWidth(s, f.name, size)
+}
+
+type Document struct {
+	font  *Font
+	ctx   *model.Context // таблица объектов: в неё же pdfcpu собирает глифы для подмножества шрифта
+	err   error
+	pages []*model.Page
+
+	Title  string
+	Author string
+}
+
+func New(font *Font) *Document {
+	conf := model.NewDefaultConfiguration()
+	conf.Cmd = model.CREATE
+	ctx, err := pdfcpu.CreateContextWithXRefTable(conf, &types.Dim{Width: PageWidth, Height: PageHeight})
+	return &Document{font: font, ctx: ctx, err: err}
+}
+
+// AddPage — новая страница; дальнейшие вызовы рисуют на ней.
+func (d *Document) AddPage() {
+	box := types.RectForDim(PageWidth, PageHeight)
+	p := model.NewPage(box, box)
+	d.pages = append(d.pages, &p)
+}
+
+func (d *Document) page() *model.Page {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// TextWidth — ширина строки в пунктах.
+func (d *Document) TextWidth(s string, size int) float64 {
+	return d.font.Width(s, size)
+}
+
+// Text — строка с базовой линией в (x, y); y отсчитывается сверху страницы.
+func (d *Document) Text(x, y float64, size int, s string) {
+	if s == "" || d.err != nil {
+		return
+	}
+	p := d.page()
+	model.WriteMultiLine(d.ctx.XRefTable, p.Buf, p.MediaBox, nil, model.TextDescriptor{
+		Text:     s,
+		FontName: d.font.name,
+		FontKey:  p.Fm.EnsureKey(d.font.name),
+		FontSize: size,
+		Embed:    true,
+		X:        x,
+		Y:        PageHeight - y,
+		HAlign:   types.AlignLeft,
+		Scale:    1,
+		ScaleAbs: true,
+		FillCol:  color.Black,
+	})
+}
+
+// TextRight — строка, выровненная по правому краю right.
+func (d *Document) TextRight(right, y float64, size int, s string) {
+	d.Text(right-d.TextWidth(s, size), y, size, s)
+}
+
+// Wrap — разбивает текст на строки не шире width (по словам).
+func (d *Document) Wrap(s string, size int, width float64) []string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			next := word
+			if line != "" {
+				next = line + " " + word
+			}
+			if line != "" && d.TextWidth(next, size) > width {
+				lines = append(lines, line)
+				next = word
+			}
+			line = next
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Line — отрезок толщиной width.
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	draw.DrawLine(d.page().Buf, x1, PageHeight-y1, x2, PageHeight-y2, width, &color.Black, nil)
+}
+
+// Bytes — готовый PDF.
+func (d *Document) Bytes() ([]byte, error) {
+	if d.err != nil {
+		return nil, fmt.Errorf("pdf: %w", d.err)
+	}
+	d.page()
+	ctx := d.ctx
+	if _, _, err := create.UpdatePageTree(ctx, d.pages, model.FontMap{d.font.name: {}}); err != nil {
+		return nil, fmt.Errorf("pdf: %w", err)
+	}
+	// Producer и даты pdfcpu проставит сам при записи
+	info := types.NewDict()
+	info.InsertString("Creator", "Edutalks")
+	for key, v := range map[string]string{"Title": d.Title, "Author": d.Author} {
+		if v == "" {
+			continue
+		}
+		s, err := types.EscapedUTF16String(v)
+		if err != nil {
+			return nil, fmt.Errorf("pdf: %w", err)
+		}
+		info.Insert(key, types.StringLiteral(*s))
+	}
+	ir, err := ctx.IndRefForNewObject(info)
+	if err != nil {
+		return nil, fmt.Errorf("pdf: %w", err)
+	}
+	ctx.Info = ir
+
+	var out bytes.Buffer
+	if err := api.WriteContext(ctx, &out); err != nil {
+		return nil, fmt.Errorf("pdf: %w", err)
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,62 @@
+package pdf
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+func TestDocument(t *testing.T) {
+	const path = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+	if _, err := os.Stat(path); err != nil {
+		t.Skip("нет шрифта DejaVuSans")
+	}
+	f, err := LoadFont(path)
+	if err != nil {
+		t.Fatalf("LoadFont: %v", err)
+	}
+	if f.Width("Итого", 10) <= f.Width("Итого", 5) {
+		t.Error("ширина не растёт с размером шрифта")
+	}
+
+	doc := New(f)
+	doc.Title = "Квитанция № 1"
+	doc.Text(56, 80, 20, "Квитанция об оплате № E-1")
+	doc.Line(56, 94, PageWidth-56, 94, 1)
+	doc.TextRight(PageWidth-56, 120, 12, "Итого: 1 000,00 ₽")
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+
+	ctx, err := api.ReadAndValidate(bytes.NewReader(out), model.NewDefaultConfiguration())
+	if err != nil {
+		t.Fatalf("result is not a valid PDF: %v", err)
+	}
+	if ctx.PageCount != 1 {
+		t.Errorf("pages = %d, want 1", ctx.PageCount)
+	}
+	if !strings.Contains(ctx.Title, "Квитанция") {
+		t.Errorf("title = %q", ctx.Title)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	const path = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+	if _, err := os.Stat(path); err != nil {
+		t.Skip("нет шрифта DejaVuSans")
+	}
+	f, err := LoadFont(path)
+	if err != nil {
+		t.Fatalf("LoadFont: %v", err)
+	}
+	doc := New(f)
+	lines := doc.Wrap("Подписка Edutalks на двенадцать месяцев", 10, doc.TextWidth("Подписка Edutalks", 10))
+	if len(lines) != 3 || lines[0] != "Подписка Edutalks" {
+		t.Errorf("Wrap = %q", lines)
+	}
+}
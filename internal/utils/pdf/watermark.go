@@ -0,0 +1,183 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+var (
+	// ErrEncrypted — зашифрованный PDF: менять его без ключа нельзя.
+	ErrEncrypted = errors.New("pdf: encrypted documents are not supported")
+	// ErrTooLarge — файл или распакованные потоки больше лимита (zip-бомба).
+	ErrTooLarge = errors.New("pdf: document or decompressed streams exceed the limit")
+	errBadPDF   = errors.New("pdf: unsupported or corrupt document")
+)
+
+// MaxInputSize — наибольший PDF, который переписывается (как лимит загрузки pdf по умолчанию).
+// Объектные потоки и потоки xref pdfcpu распаковывает ещё при чтении, и их ограничивает
+// только размер файла.
+const MaxInputSize = 100 << 20
+
+// Лимиты распаковки: одного потока и всех потоков документа вместе. Deflate сжимает
+// нули примерно в тысячу раз, так что без них килобайты файла становятся гигабайтами.
+var (
+	maxStreamSize   int64 = 64 << 20
+	maxInflatedSize int64 = 256 << 20
+)
+
+// WatermarkText — что нанести на страницы: крупно по диагонали и мелко внизу.
+// Стандартный шрифт Helvetica не содержит кириллицы, поэтому текст транслитерируется.
+type WatermarkText struct {
+	Diagonal string
+	Footer   string
+}
+
+// Оформление знаков в формате описаний pdfcpu.
+const (
+	wmDiagonalDesc = "font:Helvetica, points:48, scale:0.8 rel, diagonal:1, opacity:0.15, fillcolor:#808080"
+	wmFooterDesc   = "font:Helvetica, points:7, scale:1 abs, pos:bl, offset:20 12, rotation:0, opacity:1, fillcolor:#737373"
+)
+
+func init() {
+	// pdfcpu по умолчанию заводит каталог настроек в домашнем каталоге; серверу он не нужен
+	api.DisableConfigDir()
+}
+
+// Watermark — копия PDF с водяным знаком на каждой странице.
+// Документ переписывается pdfcpu целиком: исходные xref и %%EOF в результат не попадают,
+// поэтому знак не снять, обрезав файл по старому концу. pdfcpu распаковывает потоки без
+// ограничений, так что после чтения их размер проверяет checkStreams.
+func Watermark(src []byte, text WatermarkText) ([]byte, error) {
+	if len(src) > MaxInputSize {
+		return nil, ErrTooLarge
+	}
+	// заголовок допускается в первом килобайте (как у Acrobat)
+	if !bytes.Contains(src[:min(len(src), 1024)], []byte("%PDF-")) {
+		return nil, errBadPDF
+	}
+
+	conf := model.NewDefaultConfiguration()
+	conf.Cmd = model.ADDWATERMARKS
+	conf.ValidationMode = model.ValidationRelaxed
+	// потоки страниц при чтении остаются сжатыми — их распакует checkStreams
+	ctx, err := api.ReadContext(bytes.NewReader(src), conf)
+	if errors.Is(err, pdfcpu.ErrWrongPassword) {
+		return nil, ErrEncrypted
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pdf: %w", err)
+	}
+	// с пустым паролем pdfcpu документ расшифровывает, но и такой не меняем
+	if ctx.XRefTable.Encrypt != nil {
+		return nil, ErrEncrypted
+	}
+	if err := checkStreams(ctx); err != nil {
+		return nil, err
+	}
+	if err := api.ValidateContext(ctx); err != nil {
+		return nil, fmt.Errorf("pdf: %w", err)
+	}
+	if err := api.OptimizeContext(ctx); err != nil {
+		return nil, fmt.Errorf("pdf: %w", err)
+	}
+	if ctx.PageCount == 0 {
+		return nil, errBadPDF
+	}
+
+	marks := []struct{ text, desc string }{
+		{asciiText(text.Diagonal), wmDiagonalDesc},
+		{asciiText(text.Footer), wmFooterDesc},
+	}
+	for _, m := range marks {
+		if m.text == "" {
+			continue
+		}
+		wm, err := api.TextWatermark(m.text, m.desc, true, false, types.POINTS)
+		if err != nil {
+			return nil, fmt.Errorf("pdf: %w", err)
+		}
+		if err := api.WatermarkContext(ctx, nil, wm); err != nil {
+			return nil, fmt.Errorf("pdf: %w", err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := api.WriteContext(ctx, &out); err != nil {
+		return nil, fmt.Errorf("pdf: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// checkStreams — распаковывает в пределах лимитов все потоки документа, сжатые Deflate.
+// Потоки с другими фильтрами (DCT, JPX…) pdfcpu для водяного знака не распаковывает.
+func checkStreams(ctx *model.Context) error {
+	var total int64
+	for _, entry := range ctx.XRefTable.Table {
+		if entry == nil || entry.Free {
+			continue
+		}
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok || len(sd.FilterPipeline) == 0 || sd.FilterPipeline[0].Name != filter.Flate {
+			continue
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(sd.Raw))
+		if err != nil {
+			continue // битый поток pdfcpu отвергнет сам
+		}
+		limit := min(maxStreamSize, maxInflatedSize-total)
+		n, err := io.Copy(io.Discard, io.LimitReader(zr, limit+1))
+		if n > limit {
+			return ErrTooLarge
+		}
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			continue
+		}
+		total += n
+	}
+	return nil
+}
+
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z", 'и': "i",
+	'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+	'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// asciiText — транслитерация кириллицы; прочие символы вне ASCII заменяются.
+func asciiText(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case c >= ' ' && c < utf8.RuneSelf:
+			b.WriteRune(c)
+		case c == '—' || c == '–':
+			b.WriteByte('-')
+		case c == '«' || c == '»':
+			b.WriteByte('"')
+		default:
+			lower := []rune(strings.ToLower(string(c)))[0]
+			t, ok := translit[lower]
+			if !ok {
+				b.WriteByte('?')
+				continue
+			}
+			if lower != c && t != "" {
+				t = strings.ToUpper(t[:1]) + t[1:]
+			}
+			b.WriteString(t)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,102 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestWatermark(t *testing.T) {
+	text := WatermarkText{Diagonal: "ivan@example.com / ID 42", Footer: "edutalks.ru: Иван Петров, ID 42. Распространение запрещено."}
+	for _, tc := range []struct {
+		file  string
+		pages int
+	}{
+		{"classic.pdf", 2},
+		{"objstm.pdf", 1},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			src := fixture(t, tc.file)
+			out, err := Watermark(src, text)
+			if err != nil {
+				t.Fatalf("Watermark: %v", err)
+			}
+
+			// файл переписан целиком: исходник не префикс результата, конец файла один
+			if bytes.HasPrefix(out, src[:bytes.LastIndex(src, []byte("%%EOF"))]) {
+				t.Error("output starts with the original document: watermark can be cut off")
+			}
+			if n := bytes.Count(out, []byte("%%EOF")); n != 1 {
+				t.Errorf("%%%%EOF markers = %d, want 1", n)
+			}
+
+			conf := model.NewDefaultConfiguration()
+			ctx, err := api.ReadAndValidate(bytes.NewReader(out), conf)
+			if err != nil {
+				t.Fatalf("result is not a valid PDF: %v", err)
+			}
+			if ctx.PageCount != tc.pages {
+				t.Errorf("pages = %d, want %d", ctx.PageCount, tc.pages)
+			}
+			has, err := api.HasWatermarks(bytes.NewReader(out), conf)
+			if err != nil || !has {
+				t.Errorf("HasWatermarks = %v, %v; want true", has, err)
+			}
+		})
+	}
+}
+
+func TestWatermarkRejects(t *testing.T) {
+	// лимит меньше 4 МиБ нулей из bomb.pdf
+	defer func(stream, total int64) { maxStreamSize, maxInflatedSize = stream, total }(maxStreamSize, maxInflatedSize)
+	maxStreamSize, maxInflatedSize = 1<<20, 2<<20
+
+	for _, tc := range []struct {
+		name string
+		src  []byte
+		want error
+	}{
+		{"zip bomb", fixture(t, "bomb.pdf"), ErrTooLarge},
+		{"encrypted", fixture(t, "encrypted.pdf"), ErrEncrypted},
+		{"too large", append([]byte("%PDF-1.7\n"), make([]byte, MaxInputSize)...), ErrTooLarge},
+		{"not a pdf", []byte("<html>hello</html>"), errBadPDF},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Watermark(tc.src, WatermarkText{Diagonal: "x"})
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			if out != nil {
+				t.Error("output returned together with an error")
+			}
+		})
+	}
+}
+
+func TestAsciiText(t *testing.T) {
+	cases := map[string]string{
+		"Щука и Ёж":         "Shchuka i Ezh",
+		"«Эдутокс» — сайт":  "\"Edutoks\" - sayt",
+		"ID 42, 01.02.2026": "ID 42, 01.02.2026",
+		"日本":                "??",
+	}
+	for in, want := range cases {
+		if got := asciiText(in); got != want {
+			t.Errorf("asciiText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
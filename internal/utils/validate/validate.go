@@ -0,0 +1,299 @@
+// Package validate — проверка DTO запросов по тегам `validate:"..."`.
+//
+// Правила (через запятую):
+//
+//	required       — непустая строка (без учёта пробелов), не-nil указатель, непустой срез, ненулевое число
+//	notblank       — строка, если задана (не-nil указатель), не пустая; для частичных обновлений
+//	min=N / max=N  — для строк длина в символах, для срезов — число элементов, для чисел — значение
+//	oneof=a b c    — одно из значений
+//	email, phone, password, username, url — форматы, см. checkFormat
+//
+// Остальные правила к пустым значениям (пустая строка, nil) не применяются. Вложенные структуры
+// (в том числе встроенные) проверяются рекурсивно; имя поля в ошибке берётся из тега json.
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FieldError — ошибка одного поля.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors — ошибки по полям; возвращается из Struct как error.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, f := range e {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return "ошибка валидации: " + strings.Join(parts, "; ")
+}
+
+// Struct — проверяет структуру (или указатель на неё); nil или Errors.
+func Struct(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs Errors
+	walk(rv, "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func walk(rv reflect.Value, prefix string, errs *Errors) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := rv.Field(i)
+
+		// встроенная структура — её поля на том же уровне
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			walk(fv, prefix, errs)
+			continue
+		}
+
+		name := fieldName(sf)
+		if name == "-" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		if tag := sf.Tag.Get("validate"); tag != "" && tag != "-" {
+			if msg := checkField(fv, tag); msg != "" {
+				*errs = append(*errs, FieldError{Field: name, Message: msg})
+				continue
+			}
+		}
+
+		inner := fv
+		if inner.Kind() == reflect.Pointer && !inner.IsNil() {
+			inner = inner.Elem()
+		}
+		if inner.Kind() == reflect.Struct && inner.Type().PkgPath() != "time" {
+			walk(inner, name, errs)
+		}
+	}
+}
+
+func fieldName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
+
+// checkField — текст ошибки по первому нарушенному правилу ("" — поле в порядке).
+func checkField(fv reflect.Value, tag string) string {
+	rules := strings.Split(tag, ",")
+
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			for _, r := range rules {
+				if r == "required" {
+					return "обязательное поле"
+				}
+			}
+			return ""
+		}
+		fv = fv.Elem()
+	}
+
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			if isEmpty(fv) {
+				return "обязательное поле"
+			}
+			continue
+		case "notblank":
+			if fv.Kind() == reflect.String && strings.TrimSpace(fv.String()) == "" {
+				return "не может быть пустым"
+			}
+			continue
+		}
+		if fv.Kind() == reflect.String && fv.String() == "" {
+			continue
+		}
+
+		var msg string
+		switch name {
+		case "min":
+			msg = checkBound(fv, arg, true)
+		case "max":
+			msg = checkBound(fv, arg, false)
+		case "oneof":
+			msg = checkOneOf(fv, arg)
+		default:
+			msg = checkFormat(fv, name)
+		}
+		if msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+func isEmpty(fv reflect.Value) bool {
+	switch fv.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(fv.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return fv.Len() == 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return fv.Float() == 0
+	}
+	return fv.IsZero()
+}
+
+func checkBound(fv reflect.Value, arg string, isMin bool) string {
+	n, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validate: неверный аргумент правила: %q", arg))
+	}
+
+	var got int64
+	var unit string
+	switch fv.Kind() {
+	case reflect.String:
+		got, unit = int64(utf8.RuneCountInString(fv.String())), "символов"
+	case reflect.Slice, reflect.Map:
+		got, unit = int64(fv.Len()), "элементов"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		got = fv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		got = int64(fv.Uint())
+	case reflect.Float32, reflect.Float64:
+		got = int64(fv.Float())
+	default:
+		return ""
+	}
+
+	switch {
+	case isMin && got < n && unit != "":
+		return fmt.Sprintf("не меньше %d %s", n, unit)
+	case isMin && got < n:
+		return fmt.Sprintf("значение не меньше %d", n)
+	case !isMin && got > n && unit != "":
+		return fmt.Sprintf("не больше %d %s", n, unit)
+	case !isMin && got > n:
+		return fmt.Sprintf("значение не больше %d", n)
+	}
+	return ""
+}
+
+func checkOneOf(fv reflect.Value, arg string) string {
+	allowed := strings.Fields(arg)
+	var got string
+	switch fv.Kind() {
+	case reflect.String:
+		got = fv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		got = strconv.FormatInt(fv.Int(), 10)
+	default:
+		return ""
+	}
+	for _, a := range allowed {
+		if got == a {
+			return ""
+		}
+	}
+	return "допустимые значения: " + strings.Join(allowed, ", ")
+}
+
+var (
+	usernameRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	phoneRe    = regexp.MustCompile(`^\+?[0-9\s()-]+$`)
+)
+
+// checkFormat — форматы строк.
+func checkFormat(fv reflect.Value, name string) string {
+	if fv.Kind() != reflect.String {
+		return ""
+	}
+	s := strings.TrimSpace(fv.String())
+
+	switch name {
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		if err != nil || addr.Address != s {
+			return "некорректный адрес почты"
+		}
+	case "phone":
+		// 10–15 цифр (E.164), допускаются +, пробелы, скобки и дефисы
+		digits := 0
+		for _, r := range s {
+			if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+		if !phoneRe.MatchString(s) || digits < 10 || digits > 15 {
+			return "некорректный номер телефона"
+		}
+	case "password":
+		var letter, digit bool
+		for _, r := range s {
+			switch {
+			case unicode.IsLetter(r):
+				letter = true
+			case unicode.IsDigit(r):
+				digit = true
+			}
+		}
+		if utf8.RuneCountInString(s) < 8 || !letter || !digit {
+			return "пароль должен быть не короче 8 символов и содержать буквы и цифры"
+		}
+		// bcrypt учитывает только первые 72 байта
+		if len(s) > 72 {
+			return "пароль слишком длинный"
+		}
+	case "username":
+		if !usernameRe.MatchString(s) {
+			return "допустимы латинские буквы, цифры, точка, дефис и подчёркивание"
+		}
+	case "url":
+		// абсолютный http(s) или путь от корня сайта (/uploads/...)
+		if strings.HasPrefix(s, "/") && !strings.HasPrefix(s, "//") {
+			return ""
+		}
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "некорректная ссылка"
+		}
+	default:
+		panic("validate: неизвестное правило " + name)
+	}
+	return ""
+}
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS email_outbox (
+    id              BIGSERIAL PRIMARY KEY,
+    recipients      TEXT[]      NOT NULL,
+    subject         TEXT        NOT NULL,
+    body            TEXT        NOT NULL,
+    is_html         BOOLEAN     NOT NULL DEFAULT TRUE,
+    status          TEXT        NOT NULL DEFAULT 'pending', -- pending | sent | failed
+    attempts        INT         NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at         TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_outbox_pending
+    ON email_outbox (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_email_outbox_status_created
+    ON email_outbox (status, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS email_outbox;
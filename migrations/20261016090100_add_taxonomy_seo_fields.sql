@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE tabs
+    ADD COLUMN IF NOT EXISTS meta_title       TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS meta_description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS og_image_url     TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE sections
+    ADD COLUMN IF NOT EXISTS meta_title       TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS meta_description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS og_image_url     TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE sections
+    DROP COLUMN IF EXISTS og_image_url,
+    DROP COLUMN IF EXISTS meta_description,
+    DROP COLUMN IF EXISTS meta_title;
+
+ALTER TABLE tabs
+    DROP COLUMN IF EXISTS og_image_url,
+    DROP COLUMN IF EXISTS meta_description,
+    DROP COLUMN IF EXISTS meta_title;
@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+    id              BIGSERIAL PRIMARY KEY,
+    provider        TEXT        NOT NULL DEFAULT 'yookassa',
+    event           TEXT        NOT NULL DEFAULT '',
+    payment_id      TEXT        NOT NULL DEFAULT '',
+    status          TEXT        NOT NULL DEFAULT '',
+    remote_ip       TEXT        NOT NULL DEFAULT '',
+    raw_payload     TEXT        NOT NULL,            -- тело как пришло (для аудита)
+    rejected_reason TEXT,                            -- не NULL — уведомление отклонено проверкой
+    attempts        INT         NOT NULL DEFAULT 1,  -- сколько раз провайдер присылал это событие
+    processed_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- ключ идемпотентности: одно событие по одному платежу обрабатываем один раз
+CREATE UNIQUE INDEX IF NOT EXISTS ux_payment_webhook_events_key
+    ON payment_webhook_events (provider, event, payment_id)
+    WHERE rejected_reason IS NULL;
+CREATE INDEX IF NOT EXISTS idx_payment_webhook_events_created
+    ON payment_webhook_events (created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS payment_webhook_events;
@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS payments (
+    id                  BIGSERIAL PRIMARY KEY,
+    user_id             BIGINT        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider            TEXT          NOT NULL DEFAULT 'yookassa',
+    provider_payment_id TEXT,                                   -- NULL, если провайдер не создал платёж
+    plan                TEXT          NOT NULL,
+    amount              NUMERIC(12,2) NOT NULL,
+    currency            TEXT          NOT NULL DEFAULT 'RUB',
+    description         TEXT          NOT NULL DEFAULT '',
+    status              TEXT          NOT NULL DEFAULT 'pending', -- pending | succeeded | canceled | failed
+    error               TEXT,
+    paid_at             TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ   NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_payments_provider_payment_id
+    ON payments (provider, provider_payment_id);
+CREATE INDEX IF NOT EXISTS idx_payments_user_created
+    ON payments (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_payments_status_created
+    ON payments (status, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS payments;
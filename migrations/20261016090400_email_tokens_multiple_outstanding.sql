@@ -0,0 +1,22 @@
+-- +goose Up
+-- Разрешаем несколько действующих токенов на пользователя (лимит задаётся в сервисе),
+-- чтобы повторная отправка не «ломала» ссылку из предыдущего письма.
+ALTER TABLE email_verification_tokens
+    DROP CONSTRAINT IF EXISTS uniq_user_token;
+
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user_created
+    ON email_verification_tokens (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_expires
+    ON email_verification_tokens (expires_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_email_verification_tokens_expires;
+DROP INDEX IF EXISTS idx_email_verification_tokens_user_created;
+
+DELETE FROM email_verification_tokens a
+    USING email_verification_tokens b
+WHERE a.user_id = b.user_id
+  AND a.created_at < b.created_at;
+
+ALTER TABLE email_verification_tokens
+    ADD CONSTRAINT uniq_user_token UNIQUE (user_id);
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS subscription_reminders (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind       TEXT        NOT NULL, -- expiring_soon | expired
+    expires_at TIMESTAMPTZ NOT NULL, -- дата окончания, о которой напоминали
+    sent_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, kind, expires_at)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS subscription_reminders;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS admin_digests (
+    id          BIGSERIAL PRIMARY KEY,
+    trigger     TEXT        NOT NULL,          -- schedule | manual
+    period_from TIMESTAMPTZ NOT NULL,
+    period_to   TIMESTAMPTZ NOT NULL,
+    week_key    TEXT,                          -- ISO-неделя для плановой отправки, например 2026-W42
+    recipients  TEXT[]      NOT NULL DEFAULT '{}',
+    payload     JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- плановая сводка уходит не больше одного раза в неделю, даже при нескольких инстансах
+CREATE UNIQUE INDEX IF NOT EXISTS ux_admin_digests_week
+    ON admin_digests (week_key) WHERE trigger = 'schedule';
+
+-- +goose Down
+DROP TABLE IF EXISTS admin_digests;
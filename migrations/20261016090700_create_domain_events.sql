@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS domain_events (
+    id          UUID PRIMARY KEY,
+    type        TEXT        NOT NULL,
+    user_id     BIGINT,
+    request_id  TEXT,
+    payload     JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    occurred_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain_events_type_occurred
+    ON domain_events (type, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_domain_events_user_occurred
+    ON domain_events (user_id, occurred_at DESC) WHERE user_id IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS domain_events;
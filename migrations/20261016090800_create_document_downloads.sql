@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS document_downloads (
+    id            BIGSERIAL PRIMARY KEY,
+    document_id   INTEGER     NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    user_id       INTEGER     REFERENCES users(id) ON DELETE SET NULL,
+    downloaded_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_downloads_doc_at
+    ON document_downloads (document_id, downloaded_at DESC);
+CREATE INDEX IF NOT EXISTS idx_document_downloads_at
+    ON document_downloads (downloaded_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS document_downloads;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Намерения выполнить побочный эффект (письмо, событие), записанные в той же транзакции,
+-- что и изменение данных. Relay-воркер доставляет их после коммита (at-least-once).
+CREATE TABLE IF NOT EXISTS side_effect_outbox (
+    id              BIGSERIAL PRIMARY KEY,
+    kind            TEXT        NOT NULL,                   -- email | event
+    payload         JSONB       NOT NULL,
+    status          TEXT        NOT NULL DEFAULT 'pending', -- pending | done | failed
+    attempts        INT         NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    processed_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_side_effect_outbox_pending
+    ON side_effect_outbox (next_attempt_at, id) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE IF EXISTS side_effect_outbox;
@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS changelog_entries (
+    id           SERIAL PRIMARY KEY,
+    title        TEXT        NOT NULL,
+    body_html    TEXT        NOT NULL DEFAULT '',
+    version      TEXT,                              -- например "2.4.0"; необязательно
+    kind         TEXT        NOT NULL DEFAULT 'feature', -- feature | improvement | fix
+    is_published BOOLEAN     NOT NULL DEFAULT false,
+    published_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_changelog_published
+    ON changelog_entries (published_at DESC) WHERE is_published;
+
+-- когда пользователь последний раз открывал «Что нового»
+CREATE TABLE IF NOT EXISTS changelog_seen (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    seen_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS changelog_seen;
+DROP TABLE IF EXISTS changelog_entries;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE documents
+    ADD COLUMN has_text_layer  BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN large_print_url TEXT,
+    ADD COLUMN audio_url       TEXT;
+
+-- +goose Down
+ALTER TABLE documents
+    DROP COLUMN audio_url,
+    DROP COLUMN large_print_url,
+    DROP COLUMN has_text_layer;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE articles
+    ADD COLUMN publish_at TIMESTAMPTZ;
+
+-- отложенные публикации, которые ещё не вышли
+CREATE INDEX IF NOT EXISTS idx_articles_publish_at_pending
+    ON articles (publish_at) WHERE NOT is_published AND publish_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_articles_publish_at_pending;
+ALTER TABLE articles DROP COLUMN publish_at;
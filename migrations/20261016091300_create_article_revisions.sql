@@ -0,0 +1,30 @@
+-- +goose Up
+-- предыдущие версии статьи: снимок пишется при каждом Update
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id         BIGSERIAL PRIMARY KEY,
+    article_id BIGINT       NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    title      VARCHAR(255) NOT NULL,
+    summary    TEXT,
+    body_html  TEXT         NOT NULL,
+    tags       JSONB        NOT NULL DEFAULT '[]'::jsonb,
+    editor_id  BIGINT       REFERENCES users(id) ON DELETE SET NULL, -- кто заменил эту версию
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_revisions_article
+    ON article_revisions (article_id, id DESC);
+
+-- автосохранение черновика: не трогает опубликованный текст
+CREATE TABLE IF NOT EXISTS article_drafts (
+    article_id BIGINT       PRIMARY KEY REFERENCES articles(id) ON DELETE CASCADE,
+    title      VARCHAR(255) NOT NULL DEFAULT '',
+    summary    TEXT,
+    body_html  TEXT         NOT NULL DEFAULT '',
+    tags       JSONB        NOT NULL DEFAULT '[]'::jsonb,
+    editor_id  BIGINT       REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS article_drafts;
+DROP TABLE IF EXISTS article_revisions;
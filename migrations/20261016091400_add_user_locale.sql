@@ -0,0 +1,7 @@
+-- +goose Up
+-- язык транзакционных писем (подтверждение, сброс пароля, подписка)
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS locale VARCHAR(8) NOT NULL DEFAULT 'ru';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- время последней содержательной правки (текст статьи, новые версии документа);
+-- в отличие от updated_at не меняется при смене раздела, расписания и т.п.
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS content_updated_at TIMESTAMPTZ;
+
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS content_updated_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE documents DROP COLUMN IF EXISTS content_updated_at;
+ALTER TABLE articles DROP COLUMN IF EXISTS content_updated_at;
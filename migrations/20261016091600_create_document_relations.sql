@@ -0,0 +1,19 @@
+-- +goose Up
+-- связи между документами: document_id <relation> related_id
+-- («приказ A заменяет B», «A изменён документом B», «см. также»)
+CREATE TABLE IF NOT EXISTS document_relations (
+    id          BIGSERIAL   PRIMARY KEY,
+    document_id INTEGER     NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    related_id  INTEGER     NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    relation    VARCHAR(16) NOT NULL CHECK (relation IN ('supersedes', 'amended_by', 'related')),
+    note        TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (document_id <> related_id),
+    UNIQUE (document_id, related_id, relation)
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_relations_related
+    ON document_relations (related_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS document_relations;
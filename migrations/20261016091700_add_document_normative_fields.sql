@@ -0,0 +1,26 @@
+-- +goose Up
+-- реквизиты нормативных документов: поиск по номеру, органу и датам вместо свободного описания
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS doc_number        TEXT,
+    ADD COLUMN IF NOT EXISTS issuing_authority TEXT,
+    ADD COLUMN IF NOT EXISTS adopted_at        DATE,
+    ADD COLUMN IF NOT EXISTS effective_at      DATE;
+
+ALTER TABLE documents
+    ADD CONSTRAINT documents_effective_after_adopted
+        CHECK (effective_at IS NULL OR adopted_at IS NULL OR effective_at >= adopted_at);
+
+CREATE INDEX IF NOT EXISTS idx_documents_doc_number ON documents (lower(doc_number)) WHERE doc_number IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_documents_adopted_at ON documents (adopted_at) WHERE adopted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_documents_effective_at ON documents (effective_at) WHERE effective_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_documents_effective_at;
+DROP INDEX IF EXISTS idx_documents_adopted_at;
+DROP INDEX IF EXISTS idx_documents_doc_number;
+ALTER TABLE documents DROP CONSTRAINT IF EXISTS documents_effective_after_adopted;
+ALTER TABLE documents
+    DROP COLUMN IF EXISTS effective_at,
+    DROP COLUMN IF EXISTS adopted_at,
+    DROP COLUMN IF EXISTS issuing_authority,
+    DROP COLUMN IF EXISTS doc_number;
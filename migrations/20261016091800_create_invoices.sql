@@ -0,0 +1,18 @@
+-- +goose Up
+-- квитанции об оплате: сквозная нумерация, одна квитанция на успешный платёж
+CREATE SEQUENCE IF NOT EXISTS invoice_number_seq;
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id         BIGSERIAL PRIMARY KEY,
+    payment_id BIGINT      NOT NULL UNIQUE REFERENCES payments(id) ON DELETE CASCADE,
+    user_id    BIGINT      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    number     TEXT        NOT NULL UNIQUE,
+    issued_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    emailed_at TIMESTAMPTZ             -- NULL — на почту ещё не отправлена
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_user ON invoices (user_id, issued_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS invoices;
+DROP SEQUENCE IF EXISTS invoice_number_seq;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- выгрузки персональных данных пользователя (ZIP на диске, ссылка уходит письмом)
+CREATE TABLE IF NOT EXISTS user_exports (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      INTEGER     NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    requested_by INTEGER     REFERENCES users(id) ON DELETE SET NULL, -- сам пользователь или админ
+    status       TEXT        NOT NULL DEFAULT 'pending',             -- pending | ready | failed | expired
+    file_path    TEXT,
+    size_bytes   BIGINT,
+    error        TEXT,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at  TIMESTAMPTZ,
+    expires_at   TIMESTAMPTZ                                         -- после — файл удаляется, ссылка не работает
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_exports_user ON user_exports (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_user_exports_expires ON user_exports (expires_at) WHERE status = 'ready';
+
+-- +goose Down
+DROP TABLE IF EXISTS user_exports;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- удаление аккаунта по запросу пользователя: до deletion_scheduled_at его можно отменить по ссылке из письма
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled ON users (deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL;
+
+-- отзыв всех токенов пользователя: access-токены, выпущенные не позже revoked_at, не принимаются
+CREATE TABLE IF NOT EXISTS user_token_revocations (
+    user_id    INTEGER     PRIMARY KEY, -- без FK: запись должна пережить удаление пользователя
+    revoked_at TIMESTAMPTZ NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_token_revocations;
+DROP INDEX IF EXISTS idx_users_deletion_scheduled;
+ALTER TABLE users DROP COLUMN IF EXISTS deletion_scheduled_at;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- смена email: новый адрес применяется только после перехода по ссылке из письма на него
+CREATE TABLE IF NOT EXISTS email_change_requests (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      INTEGER     NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    new_email    TEXT        NOT NULL,
+    token_hash   TEXT        NOT NULL, -- хранится только хэш
+    expires_at   TIMESTAMPTZ NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_email_change_requests_token_hash ON email_change_requests (token_hash);
+CREATE INDEX IF NOT EXISTS idx_email_change_requests_user ON email_change_requests (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS email_change_requests;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- сессии: одна на вход; access-токен несёт id сессии (claim sid) и перестаёт приниматься после её отзыва
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INTEGER     NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent TEXT        NOT NULL DEFAULT '',
+    ip         TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user ON user_sessions (user_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_sessions;
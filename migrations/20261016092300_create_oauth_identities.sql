@@ -0,0 +1,15 @@
+-- +goose Up
+-- внешние аккаунты (Google, Яндекс), привязанные к пользователям; subject — id пользователя у провайдера
+CREATE TABLE IF NOT EXISTS oauth_identities (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INTEGER     NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider   TEXT        NOT NULL,
+    subject    TEXT        NOT NULL,
+    email      TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (provider, subject),
+    UNIQUE (user_id, provider)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS oauth_identities;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- результат антивирусной проверки файла: clean | infected | skipped (проверка выключена) | error;
+-- NULL — файл загружен до появления проверки
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS scan_status TEXT,
+    ADD COLUMN IF NOT EXISTS scan_signature TEXT,
+    ADD COLUMN IF NOT EXISTS scanned_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE documents
+    DROP COLUMN IF EXISTS scanned_at,
+    DROP COLUMN IF EXISTS scan_signature,
+    DROP COLUMN IF EXISTS scan_status;
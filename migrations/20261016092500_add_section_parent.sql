@@ -0,0 +1,13 @@
+-- +goose Up
+-- вложенные разделы: родитель — раздел той же вкладки (проверяется в сервисе);
+-- при удалении родителя удаляется и всё поддерево
+ALTER TABLE sections
+    ADD COLUMN IF NOT EXISTS parent_section_id INT REFERENCES sections(id) ON DELETE CASCADE,
+    ADD CONSTRAINT sections_parent_not_self CHECK (parent_section_id IS NULL OR parent_section_id <> id);
+CREATE INDEX IF NOT EXISTS idx_sections_parent ON sections(parent_section_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_sections_parent;
+ALTER TABLE sections
+    DROP CONSTRAINT IF EXISTS sections_parent_not_self,
+    DROP COLUMN IF EXISTS parent_section_id;
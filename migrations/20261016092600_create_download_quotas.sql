@@ -0,0 +1,20 @@
+-- +goose Up
+-- дневной счётчик скачиваний пользователя (сутки — по московскому времени, считает сервис)
+CREATE TABLE IF NOT EXISTS download_quota_usage (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day     DATE    NOT NULL,
+    used    INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day)
+);
+
+-- индивидуальный лимит вместо тарифного; daily_limit NULL — без ограничения
+CREATE TABLE IF NOT EXISTS download_quota_overrides (
+    user_id     INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    daily_limit INTEGER CHECK (daily_limit >= 0),
+    updated_by  INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS download_quota_overrides;
+DROP TABLE IF EXISTS download_quota_usage;
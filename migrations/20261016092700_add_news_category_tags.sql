@@ -0,0 +1,15 @@
+-- +goose Up
+-- рубрика и теги новостей (теги — как у статей: jsonb-массив строк в нижнем регистре)
+ALTER TABLE news
+    ADD COLUMN IF NOT EXISTS category VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tags     JSONB       NOT NULL DEFAULT '[]'::jsonb;
+
+CREATE INDEX IF NOT EXISTS idx_news_tags_gin ON news USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_news_category ON news (category) WHERE category <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_news_category;
+DROP INDEX IF EXISTS idx_news_tags_gin;
+ALTER TABLE news
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS category;
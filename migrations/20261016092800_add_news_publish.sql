@@ -0,0 +1,17 @@
+-- +goose Up
+-- черновики новостей: уже существующие новости считаются опубликованными
+ALTER TABLE news
+    ADD COLUMN IF NOT EXISTS is_published BOOLEAN     NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+
+UPDATE news SET published_at = created_at WHERE is_published AND published_at IS NULL;
+
+ALTER TABLE news ALTER COLUMN is_published SET DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_news_published ON news (published_at DESC) WHERE is_published;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_news_published;
+ALTER TABLE news
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS is_published;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- отложенная публикация новостей (как publish_at у статей)
+ALTER TABLE news ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_news_publish_at ON news (publish_at) WHERE NOT is_published AND publish_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_news_publish_at;
+ALTER TABLE news DROP COLUMN IF EXISTS publish_at;
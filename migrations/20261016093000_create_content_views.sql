@@ -0,0 +1,20 @@
+-- +goose Up
+-- просмотры новостей и статей: один засчитанный просмотр на зрителя в сутки
+CREATE TABLE IF NOT EXISTS content_views (
+    kind       VARCHAR(16) NOT NULL CHECK (kind IN ('news', 'article')),
+    content_id BIGINT      NOT NULL,
+    viewer     VARCHAR(64) NOT NULL, -- u:<user_id> или a:<хеш IP и User-Agent>
+    viewed_on  DATE        NOT NULL DEFAULT CURRENT_DATE,
+    viewed_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (kind, content_id, viewer, viewed_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_content_views_viewed_at ON content_views (viewed_at);
+
+ALTER TABLE news     ADD COLUMN IF NOT EXISTS view_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS view_count BIGINT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE articles DROP COLUMN IF EXISTS view_count;
+ALTER TABLE news     DROP COLUMN IF EXISTS view_count;
+DROP TABLE IF EXISTS content_views;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- журнал выдачи и отключения подписок (для графиков админ-дашборда)
+CREATE TABLE IF NOT EXISTS subscription_changes (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INTEGER     NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind       VARCHAR(16) NOT NULL CHECK (kind IN ('grant', 'extend', 'revoke', 'expire')),
+    actor_id   INTEGER     REFERENCES users(id) ON DELETE SET NULL, -- кто изменил (админ или сам пользователь); NULL — система
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_changes_created ON subscription_changes (created_at);
+CREATE INDEX IF NOT EXISTS idx_subscription_changes_actor ON subscription_changes (actor_id, created_at) WHERE actor_id IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS subscription_changes;
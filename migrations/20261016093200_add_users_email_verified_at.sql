@@ -0,0 +1,6 @@
+-- +goose Up
+-- момент подтверждения email — для ленты действий пользователя в админке; у подтверждённых ранее неизвестен
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
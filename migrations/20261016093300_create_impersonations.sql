@@ -0,0 +1,18 @@
+-- +goose Up
+-- журнал входов поддержки под пользователем: кто, под кем, зачем и откуда
+CREATE TABLE IF NOT EXISTS impersonations (
+    id         BIGSERIAL PRIMARY KEY,
+    admin_id   INTEGER     REFERENCES users(id) ON DELETE SET NULL,
+    user_id    INTEGER     NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason     TEXT        NOT NULL DEFAULT '',
+    ip         TEXT        NOT NULL DEFAULT '',
+    user_agent TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonations_user ON impersonations (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_impersonations_admin ON impersonations (admin_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS impersonations;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- баннеры на сайте (техработы, акции): показываются в окне starts_at..ends_at, если включены
+CREATE TABLE IF NOT EXISTS announcements (
+    id          SERIAL PRIMARY KEY,
+    message     TEXT        NOT NULL,
+    link_url    TEXT,
+    link_text   TEXT,
+    severity    TEXT        NOT NULL DEFAULT 'info', -- info | warning | critical | promo
+    dismissible BOOLEAN     NOT NULL DEFAULT true,
+    is_enabled  BOOLEAN     NOT NULL DEFAULT true,
+    starts_at   TIMESTAMPTZ,                         -- NULL — сразу
+    ends_at     TIMESTAMPTZ,                         -- NULL — бессрочно
+    created_by  INTEGER     REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_announcements_window
+    ON announcements (starts_at, ends_at) WHERE is_enabled;
+
+-- +goose Down
+DROP TABLE IF EXISTS announcements;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- флаги фич для фронта; у каждого окружения своя база, поэтому и свой набор флагов
+CREATE TABLE IF NOT EXISTS feature_flags (
+    key             TEXT PRIMARY KEY,
+    description     TEXT        NOT NULL DEFAULT '',
+    enabled         BOOLEAN     NOT NULL DEFAULT false,
+    rollout_percent SMALLINT    NOT NULL DEFAULT 100 CHECK (rollout_percent BETWEEN 0 AND 100),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS feature_flags;
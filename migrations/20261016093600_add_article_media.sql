@@ -0,0 +1,20 @@
+-- +goose Up
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS cover_image_url TEXT;
+
+-- медиатека статей: изображения, загруженные через админку (файлы — uploads/articles)
+CREATE TABLE IF NOT EXISTS article_media (
+    id            BIGSERIAL PRIMARY KEY,
+    url           TEXT        NOT NULL UNIQUE, -- /uploads/articles/<file>
+    path          TEXT        NOT NULL,        -- относительно каталога uploads
+    original_name TEXT        NOT NULL DEFAULT '',
+    content_type  TEXT        NOT NULL,
+    size_bytes    BIGINT      NOT NULL DEFAULT 0,
+    uploaded_by   INTEGER     REFERENCES users(id) ON DELETE SET NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_media_created ON article_media (created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS article_media;
+ALTER TABLE articles DROP COLUMN IF EXISTS cover_image_url;
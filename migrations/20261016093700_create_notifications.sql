@@ -0,0 +1,31 @@
+-- +goose Up
+-- уведомления в личном кабинете (колокольчик): новые документы в отслеживаемых разделах,
+-- скорое окончание подписки, объявления администрации
+CREATE TABLE IF NOT EXISTS notifications (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INTEGER     NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type       TEXT        NOT NULL, -- document_added | subscription_expiring | broadcast
+    title      TEXT        NOT NULL,
+    body       TEXT        NOT NULL DEFAULT '',
+    link       TEXT,
+    data       JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    read_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+
+-- разделы, на новые документы в которых пользователь подписан
+CREATE TABLE IF NOT EXISTS section_follows (
+    user_id    INTEGER     NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    section_id INTEGER     NOT NULL REFERENCES sections(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, section_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_section_follows_section ON section_follows (section_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS section_follows;
+DROP TABLE IF EXISTS notifications;
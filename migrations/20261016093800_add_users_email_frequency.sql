@@ -0,0 +1,15 @@
+-- +goose Up
+-- как получать письма о новых материалах: сразу (групповая рассылка и письма о публикациях)
+-- или одной сводкой раз в день / раз в неделю
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS email_frequency TEXT NOT NULL DEFAULT 'immediate'
+        CHECK (email_frequency IN ('immediate', 'daily', 'weekly')),
+    ADD COLUMN IF NOT EXISTS digest_sent_at TIMESTAMPTZ; -- конец периода последней отправленной сводки
+
+CREATE INDEX IF NOT EXISTS idx_users_email_digest ON users (email_frequency) WHERE email_frequency <> 'immediate';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_email_digest;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS digest_sent_at,
+    DROP COLUMN IF EXISTS email_frequency;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- рассылки администратора по расписанию: разовые (repeat = 'none') и повторяющиеся
+CREATE TABLE IF NOT EXISTS notify_campaigns (
+    id          BIGSERIAL PRIMARY KEY,
+    subject     TEXT        NOT NULL,
+    message     TEXT        NOT NULL,
+    template    TEXT        NOT NULL DEFAULT 'simple',
+    url         TEXT        NOT NULL DEFAULT '',
+    audience    JSONB       NOT NULL DEFAULT '{}',
+    send_at     TIMESTAMPTZ NOT NULL, -- ближайший запуск
+    repeat      TEXT        NOT NULL DEFAULT 'none'
+        CHECK (repeat IN ('none', 'daily', 'weekly', 'monthly')),
+    status      TEXT        NOT NULL DEFAULT 'scheduled'
+        CHECK (status IN ('scheduled', 'sent', 'cancelled')),
+    runs        INT         NOT NULL DEFAULT 0,
+    last_run_at TIMESTAMPTZ,
+    created_by  INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notify_campaigns_due
+    ON notify_campaigns (send_at) WHERE status = 'scheduled';
+
+-- письма рассылки в outbox: по ним считается статистика доставки
+ALTER TABLE email_outbox
+    ADD COLUMN IF NOT EXISTS campaign_id BIGINT REFERENCES notify_campaigns(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_email_outbox_campaign
+    ON email_outbox (campaign_id, status) WHERE campaign_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_email_outbox_campaign;
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS campaign_id;
+DROP TABLE IF EXISTS notify_campaigns;
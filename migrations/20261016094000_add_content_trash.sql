@@ -0,0 +1,23 @@
+-- +goose Up
+-- корзина: удалённые документы, новости и статьи хранятся до окончательного удаления (TRASH_RETENTION_DAYS)
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE news      ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE articles  ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_documents_deleted_at ON documents (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_news_deleted_at      ON news (deleted_at)      WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_articles_deleted_at  ON articles (deleted_at)  WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_articles_deleted_at;
+DROP INDEX IF EXISTS idx_news_deleted_at;
+DROP INDEX IF EXISTS idx_documents_deleted_at;
+
+-- удалённое в корзину без колонки стало бы снова видно — удаляем окончательно
+DELETE FROM articles  WHERE deleted_at IS NOT NULL;
+DELETE FROM news      WHERE deleted_at IS NOT NULL;
+DELETE FROM documents WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE articles  DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE news      DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE documents DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- SHA-256 и размер файла документа: поиск дубликатов при загрузке и отчёт о занятом ими месте.
+-- У старых документов заполняются фоновой задачей после запуска.
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS sha256     TEXT,
+    ADD COLUMN IF NOT EXISTS size_bytes BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_documents_sha256 ON documents (sha256) WHERE sha256 IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_documents_sha256;
+
+ALTER TABLE documents
+    DROP COLUMN IF EXISTS size_bytes,
+    DROP COLUMN IF EXISTS sha256;
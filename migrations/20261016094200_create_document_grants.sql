@@ -0,0 +1,19 @@
+-- +goose Up
+-- персональный доступ к документу: пользователь скачивает его, даже если документ закрыт
+-- или у пользователя нет подписки; expires_at = NULL — бессрочно
+CREATE TABLE IF NOT EXISTS document_grants (
+    id          BIGSERIAL   PRIMARY KEY,
+    document_id INTEGER     NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    user_id     INTEGER     NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    granted_by  INTEGER     REFERENCES users(id) ON DELETE SET NULL,
+    note        TEXT        NOT NULL DEFAULT '',
+    expires_at  TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (document_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_grants_user
+    ON document_grants (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS document_grants;
@@ -0,0 +1,38 @@
+-- +goose Up
+-- согласование статей и документов: автор отправляет на проверку, админ одобряет (публикует)
+-- или отклоняет с комментарием. review_state = 'published' после одобрения; опубликован ли
+-- материал на самом деле, решают is_published / is_public
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS review_state VARCHAR(16) NOT NULL DEFAULT 'draft';
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS review_state VARCHAR(16) NOT NULL DEFAULT 'draft';
+
+UPDATE articles SET review_state = 'published' WHERE is_published;
+UPDATE documents SET review_state = 'published' WHERE is_public;
+
+CREATE INDEX IF NOT EXISTS idx_articles_review_state
+    ON articles (review_state) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_documents_review_state
+    ON documents (review_state) WHERE deleted_at IS NULL;
+
+-- история переходов: кто, когда и с каким комментарием
+CREATE TABLE IF NOT EXISTS content_review_events (
+    id           BIGSERIAL   PRIMARY KEY,
+    content_type VARCHAR(16) NOT NULL,
+    content_id   BIGINT      NOT NULL,
+    from_state   VARCHAR(16) NOT NULL,
+    to_state     VARCHAR(16) NOT NULL,
+    actor_id     INTEGER     REFERENCES users(id) ON DELETE SET NULL,
+    comment      TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_content_review_events_content
+    ON content_review_events (content_type, content_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS content_review_events;
+DROP INDEX IF EXISTS idx_documents_review_state;
+DROP INDEX IF EXISTS idx_articles_review_state;
+ALTER TABLE documents DROP COLUMN IF EXISTS review_state;
+ALTER TABLE articles DROP COLUMN IF EXISTS review_state;
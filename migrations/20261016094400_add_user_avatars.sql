@@ -0,0 +1,8 @@
+-- +goose Up
+-- аватар пользователя: общий префикс файлов в uploads (avatars/<id>_<время>_<суффикс>),
+-- к нему дописываются размеры: _256.jpg, _128.jpg, _64.jpg. NULL — аватара нет, показываем identicon
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS avatar_key TEXT;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_key;
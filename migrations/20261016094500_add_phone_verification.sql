@@ -0,0 +1,21 @@
+-- +goose Up
+-- подтверждён ли телефон кодом из SMS; смена номера сбрасывает флаг
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS phone_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- действующие коды из SMS: один на пользователя и назначение (verify — подтверждение номера,
+-- reset — сброс пароля); новый код заменяет прежний. Храним только хэш кода
+CREATE TABLE IF NOT EXISTS phone_codes (
+    user_id    INTEGER     NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose    TEXT        NOT NULL CHECK (purpose IN ('verify', 'reset')),
+    phone      TEXT        NOT NULL,
+    code_hash  TEXT        NOT NULL,
+    attempts   INTEGER     NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, purpose)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS phone_codes;
+ALTER TABLE users DROP COLUMN IF EXISTS phone_verified;
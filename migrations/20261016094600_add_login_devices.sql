@@ -0,0 +1,27 @@
+-- +goose Up
+-- письмо о входе с нового устройства; отключается в настройках уведомлений
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS login_alerts BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- устройства, с которых входил пользователь: пара IP + User-Agent (fingerprint — md5 от неё)
+CREATE TABLE IF NOT EXISTS user_devices (
+    id            BIGSERIAL   PRIMARY KEY,
+    user_id       INTEGER     NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    fingerprint   TEXT        NOT NULL,
+    ip            TEXT        NOT NULL DEFAULT '',
+    user_agent    TEXT        NOT NULL DEFAULT '',
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_seen_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, fingerprint)
+);
+
+-- уже известные по сессиям устройства — чтобы после выката не пришли письма о каждом входе
+INSERT INTO user_devices (user_id, fingerprint, ip, user_agent, first_seen_at, last_seen_at)
+SELECT user_id, md5(ip || '|' || user_agent), ip, user_agent, min(created_at), max(created_at)
+FROM user_sessions
+GROUP BY user_id, ip, user_agent
+ON CONFLICT (user_id, fingerprint) DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS user_devices;
+ALTER TABLE users DROP COLUMN IF EXISTS login_alerts;
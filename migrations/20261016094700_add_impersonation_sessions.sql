@@ -0,0 +1,11 @@
+-- +goose Up
+-- токен поддержки привязан к сессии пользователя (claim sid): её отзыв отзывает и токен;
+-- ended_at — вход завершён досрочно (кнопкой «выйти из режима поддержки» или администратором)
+ALTER TABLE impersonations
+    ADD COLUMN IF NOT EXISTS session_id BIGINT REFERENCES user_sessions(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS ended_at   TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE impersonations
+    DROP COLUMN IF EXISTS ended_at,
+    DROP COLUMN IF EXISTS session_id;
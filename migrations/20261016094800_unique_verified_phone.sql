@@ -0,0 +1,18 @@
+-- +goose Up
+-- подтверждённый номер (по последним 10 цифрам) принадлежит одному пользователю: по нему
+-- ищут аккаунт при сбросе пароля. Из уже подтверждённых дублей флаг остаётся у самого раннего
+UPDATE users u
+SET phone_verified = FALSE
+WHERE u.phone_verified
+  AND EXISTS (
+      SELECT 1 FROM users o
+      WHERE o.phone_verified AND o.id < u.id
+        AND right(regexp_replace(o.phone, '\D', '', 'g'), 10) = right(regexp_replace(u.phone, '\D', '', 'g'), 10)
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_verified_phone_last10_uniq
+    ON users (right(regexp_replace(phone, '\D', '', 'g'), 10))
+    WHERE phone_verified;
+
+-- +goose Down
+DROP INDEX IF EXISTS users_verified_phone_last10_uniq;
@@ -0,0 +1,9 @@
+// Package migrations — SQL-миграции схемы (формат goose), встроенные в бинарник.
+package migrations
+
+import "embed"
+
+// FS — все *.sql этого каталога; применяются db.Migrator (goose) при старте и командой `migrate`.
+//
+//go:embed *.sql
+var FS embed.FS